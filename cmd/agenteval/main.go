@@ -0,0 +1,111 @@
+// Command agenteval runs the agent golden conversations and writes a report.
+//
+// By default it replays the fake provider scripts of each turn against an
+// in-memory SQLite database seeded with the suite dataset:
+//
+//	go run ./cmd/agenteval -format markdown
+//
+// With -provider env the turns go to the provider configured by
+// AGENT_LLM_PROVIDER (and AGENT_EMBEDDING_PROVIDER), and with -dsn the dataset
+// is seeded into that Postgres database after running the migrations. Use a
+// dedicated database: each run adds one user per case.
+//
+// The command exits with status 1 when any case fails.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"personal-finance/internal/agenteval"
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/plataform/database"
+	"personal-finance/pkg/log"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+)
+
+func main() {
+	ok, err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error running agent evaluation: %v\n", err)
+		os.Exit(2)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+func run() (bool, error) {
+	suiteDir := flag.String("suite", "internal/agenteval/golden", "suite directory with dataset.json and cases/")
+	dsn := flag.String("dsn", "", "Postgres connection string; empty uses an in-memory SQLite database")
+	providerName := flag.String("provider", "fake", "fake replays the scripts of the suite; env uses AGENT_LLM_PROVIDER")
+	format := flag.String("format", "markdown", "report format: markdown or json")
+	out := flag.String("out", "", "report file; empty writes to stdout")
+	flag.Parse()
+
+	_ = godotenv.Load(".env")
+	log.Initialize(log.WithLevel("error"))
+
+	suite, err := agenteval.LoadSuite(*suiteDir)
+	if err != nil {
+		return false, err
+	}
+
+	db, err := openDatabase(*dsn)
+	if err != nil {
+		return false, err
+	}
+
+	var provider gateway.LLMProvider
+	var embedder domain.EmbeddingProvider = gateway.NewLocalEmbeddingProvider()
+	switch *providerName {
+	case "fake":
+	case "env":
+		provider = gateway.NewLLMProviderFromEnv()
+		embedder = gateway.NewEmbeddingProviderFromEnv()
+	default:
+		return false, fmt.Errorf("unknown provider %q, use fake or env", *providerName)
+	}
+
+	report := agenteval.NewRunner(db, provider, embedder).Run(context.Background(), suite)
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return false, fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		err = report.WriteJSON(w)
+	case "markdown":
+		err = report.WriteMarkdown(w)
+	default:
+		return false, fmt.Errorf("unknown format %q, use markdown or json", *format)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return report.OK(), nil
+}
+
+func openDatabase(dsn string) (*gorm.DB, error) {
+	if dsn == "" {
+		return agenteval.OpenSQLite()
+	}
+	if err := database.RunMigrations(dsn, "file://db/migrations/"); err != nil {
+		return nil, err
+	}
+	return database.OpenGORMConnection(dsn), nil
+}
@@ -7,7 +7,10 @@ import (
 	"os"
 
 	"personal-finance/internal/bootstrap"
+	"personal-finance/internal/bootstrap/accesstoken"
 	"personal-finance/internal/bootstrap/environment"
+	"personal-finance/internal/bootstrap/household"
+	"personal-finance/internal/bootstrap/referral"
 	"personal-finance/internal/bootstrap/registry"
 	balanceApi "personal-finance/internal/domain/balance/api"
 	balanceService "personal-finance/internal/domain/balance/service"
@@ -90,7 +93,7 @@ func setupGin(logger log.Logger, db *gorm.DB) (*gin.Engine, authentication.Authe
 
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true // TODO
-	corsConfig.AllowHeaders = []string{authentication.UserToken, authentication.APIKeyHeader, "Content-Type"}
+	corsConfig.AllowHeaders = []string{authentication.UserToken, authentication.APIKeyHeader, authentication.ReferralCodeHeader, "Content-Type"}
 	r.Use(cors.New(corsConfig))
 
 	// Liveness/readiness probes are unauthenticated, registered before auth.
@@ -98,14 +101,18 @@ func setupGin(logger log.Logger, db *gorm.DB) (*gin.Engine, authentication.Authe
 
 	r.GET("/ping", ping())
 
-	bootstrap.SetupInternalJobs(r, db)
+	authenticator := authentication.NewAuthenticator(environment.IsProduction())
 
-	authenticator := authentication.NewFirebaseAuth()
+	bootstrap.SetupInternalJobs(r, db, authenticator)
 
 	bootstrap.SetupPublicComponents(r, db, authenticator)
 
-	r.Use(authenticator.Authenticate())
-	r.Use(authentication.LazyProvisionUser(registry.NewRegistry(db).GetUserRepository(), authenticator.AuthClient()))
+	reg := registry.NewRegistry(db)
+	reg.SetAuthenticator(authenticator)
+	// Personal access tokens are accepted wherever a Firebase ID token is.
+	r.Use(authentication.AuthenticateWithAccessTokens(accesstoken.NewUseCase(reg), authenticator.Authenticate()))
+	r.Use(authentication.LazyProvisionUser(reg.GetUserRepository(), referral.NewUseCase(reg), authenticator.ClaimsStore()))
+	r.Use(household.Scope(reg))
 
 	return r, authenticator
 }
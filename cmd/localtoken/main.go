@@ -0,0 +1,100 @@
+// Command localtoken mints a token for the local authenticator, used when the
+// API runs with AUTH_PROVIDER=local:
+//
+//	go run ./cmd/localtoken -uid dev-user -plan plus -role admin
+//
+// The claims flags are written to the local claims store (LOCAL_AUTH_CLAIMS_FILE)
+// before minting, so the API and the admin endpoints see the same claims. The
+// token is printed to stdout and goes in the user_token header.
+//
+// The command refuses to run in production.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"personal-finance/internal/bootstrap/environment"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error minting local token: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	uid := flag.String("uid", "", "user id, the token subject")
+	email := flag.String("email", "", "user email")
+	plan := flag.String("plan", "", "free or plus; empty keeps the stored plan")
+	role := flag.String("role", "", "user or admin; empty keeps the stored role")
+	source := flag.String("subscription-source", "", "mp, iap or stripe; only used with -plan")
+	provisioned := flag.Bool("provisioned", false, "mark the user as provisioned")
+	ttl := flag.Duration("ttl", time.Hour, "token lifetime")
+	flag.Parse()
+
+	_ = godotenv.Load(".env")
+	log.Initialize(log.WithLevel("error"))
+
+	if environment.IsProduction() {
+		return errors.New("local tokens are not allowed in production")
+	}
+	if *uid == "" {
+		return errors.New("-uid is required")
+	}
+
+	cfg, err := authentication.LocalAuthConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	auth, err := authentication.NewLocalAuth(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	claims := gateway.NewFirebaseGateway(auth.ClaimsStore())
+
+	if *plan != "" {
+		err := claims.SetUserSubscription(ctx, *uid, authentication.Plan(*plan), "", authentication.SubscriptionSource(*source), 0)
+		if err != nil {
+			return err
+		}
+	}
+	if *role != "" {
+		if err := claims.SetUserRole(ctx, *uid, authentication.Role(*role)); err != nil {
+			return err
+		}
+	}
+	if *provisioned {
+		if err := setProvisioned(ctx, auth.ClaimsStore(), *uid); err != nil {
+			return err
+		}
+	}
+
+	token, err := auth.MintToken(ctx, *uid, *email, *ttl)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+func setProvisioned(ctx context.Context, store authentication.ClaimsStore, userID string) error {
+	claims, err := store.GetCustomClaims(ctx, userID)
+	if err != nil {
+		return err
+	}
+	claims["provisioned"] = true
+	return store.SetCustomClaims(ctx, userID, claims)
+}
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_invoices_open_period_end;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS closed_amount,
+    DROP COLUMN IF EXISTS closed_at;
+
+ALTER TABLE credit_cards
+    DROP COLUMN IF EXISTS auto_debit;
@@ -0,0 +1,10 @@
+ALTER TABLE credit_cards
+    ADD COLUMN IF NOT EXISTS auto_debit BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS closed_amount double precision;
+
+CREATE INDEX IF NOT EXISTS idx_invoices_open_period_end
+    ON invoices (period_end)
+    WHERE closed_at IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_recurrent_movements_pair_id;
+
+ALTER TABLE recurrent_movements
+    DROP COLUMN IF EXISTS pair_id;
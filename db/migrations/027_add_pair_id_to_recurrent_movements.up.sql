@@ -0,0 +1,6 @@
+ALTER TABLE recurrent_movements
+    ADD COLUMN IF NOT EXISTS pair_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_recurrent_movements_pair_id
+    ON recurrent_movements (pair_id)
+    WHERE pair_id IS NOT NULL;
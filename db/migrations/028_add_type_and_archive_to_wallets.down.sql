@@ -0,0 +1,3 @@
+ALTER TABLE wallets
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS type;
@@ -0,0 +1,3 @@
+ALTER TABLE wallets
+    ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'checking',
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
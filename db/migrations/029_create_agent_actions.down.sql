@@ -0,0 +1,5 @@
+ALTER TABLE agent_audit_log
+    DROP COLUMN IF EXISTS action_type,
+    DROP COLUMN IF EXISTS action_id;
+
+DROP TABLE IF EXISTS agent_actions;
@@ -0,0 +1,20 @@
+-- Agent Actions (write operations proposed by the agent, executed only after user confirmation)
+CREATE TABLE agent_actions (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id         TEXT NOT NULL,
+    conversation_id UUID REFERENCES agent_conversations(id) ON DELETE SET NULL,
+    action_type     TEXT NOT NULL,
+    summary         TEXT NOT NULL DEFAULT '',
+    params          JSONB NOT NULL DEFAULT '{}',
+    status          TEXT NOT NULL DEFAULT 'pending',
+    result_id       UUID,
+    error           TEXT,
+    created_at      TIMESTAMP DEFAULT now(),
+    expires_at      TIMESTAMP NOT NULL,
+    resolved_at     TIMESTAMP
+);
+CREATE INDEX idx_agent_actions_user_status ON agent_actions(user_id, status);
+
+ALTER TABLE agent_audit_log
+    ADD COLUMN IF NOT EXISTS action_id UUID,
+    ADD COLUMN IF NOT EXISTS action_type TEXT;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ai_quota_overrides;
+DROP TABLE IF EXISTS ai_usage;
@@ -0,0 +1,24 @@
+-- Monthly AI usage per user and feature, checked against the plan AI quota
+CREATE TABLE ai_usage (
+    user_id       TEXT NOT NULL,
+    year          INT NOT NULL,
+    month         INT NOT NULL,
+    feature       TEXT NOT NULL,
+    requests      BIGINT NOT NULL DEFAULT 0,
+    input_tokens  BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    updated_at    TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (user_id, year, month, feature)
+);
+
+-- Per-user AI quota granted by an admin, replacing the plan quota while active
+CREATE TABLE ai_quota_overrides (
+    user_id            TEXT PRIMARY KEY,
+    tokens_per_month   INT NOT NULL DEFAULT 0,
+    requests_per_month INT NOT NULL DEFAULT 0,
+    reason             TEXT NOT NULL DEFAULT '',
+    granted_by         TEXT NOT NULL DEFAULT '',
+    expires_at         TIMESTAMP,
+    created_at         TIMESTAMP DEFAULT now(),
+    updated_at         TIMESTAMP DEFAULT now()
+);
@@ -0,0 +1,3 @@
+ALTER TABLE agent_conversations
+    DROP COLUMN IF EXISTS summarized_messages,
+    DROP COLUMN IF EXISTS summary;
@@ -0,0 +1,3 @@
+ALTER TABLE agent_conversations
+    ADD COLUMN IF NOT EXISTS summary TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS summarized_messages INTEGER NOT NULL DEFAULT 0;
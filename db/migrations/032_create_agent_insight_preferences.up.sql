@@ -0,0 +1,10 @@
+-- Opt-in to the weekly insights digest and when the last one was sent
+CREATE TABLE agent_insight_preferences (
+    user_id        TEXT PRIMARY KEY,
+    enabled        BOOLEAN NOT NULL DEFAULT false,
+    last_digest_at TIMESTAMP,
+    created_at     TIMESTAMP DEFAULT now(),
+    updated_at     TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_agent_insight_preferences_due ON agent_insight_preferences (last_digest_at) WHERE enabled;
@@ -0,0 +1,3 @@
+ALTER TABLE agent_memories
+    DROP COLUMN IF EXISTS embedding_model,
+    DROP COLUMN IF EXISTS embedding;
@@ -0,0 +1,3 @@
+ALTER TABLE agent_memories
+    ADD COLUMN IF NOT EXISTS embedding JSONB,
+    ADD COLUMN IF NOT EXISTS embedding_model VARCHAR(100) NOT NULL DEFAULT '';
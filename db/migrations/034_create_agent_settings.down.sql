@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_agent_audit_log_user;
+DROP TABLE IF EXISTS agent_settings;
@@ -0,0 +1,9 @@
+-- Per-user agent settings; a missing row means the defaults (memory enabled)
+CREATE TABLE agent_settings (
+    user_id        TEXT PRIMARY KEY,
+    memory_enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at     TIMESTAMP DEFAULT now(),
+    updated_at     TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_agent_audit_log_user ON agent_audit_log (user_id, created_at);
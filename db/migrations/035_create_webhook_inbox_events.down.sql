@@ -0,0 +1 @@
+DROP TABLE IF EXISTS webhook_inbox_events;
@@ -0,0 +1,17 @@
+-- Verified billing webhooks, processed asynchronously with retries
+CREATE TABLE webhook_inbox_events (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider        TEXT NOT NULL,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL DEFAULT '',
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+    last_error      TEXT NOT NULL DEFAULT '',
+    received_at     TIMESTAMP NOT NULL DEFAULT now(),
+    processed_at    TIMESTAMP,
+    updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT uq_webhook_inbox_events_provider_event UNIQUE (provider, event_id)
+);
+CREATE INDEX idx_webhook_inbox_events_due ON webhook_inbox_events (status, next_attempt_at);
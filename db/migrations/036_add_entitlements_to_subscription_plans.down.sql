@@ -0,0 +1,4 @@
+DELETE FROM subscription_plans WHERE id = 'free';
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS entitlements;
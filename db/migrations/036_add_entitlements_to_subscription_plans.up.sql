@@ -0,0 +1,8 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS entitlements JSONB;
+
+-- Holds the entitlements of users without a paid plan. Never sold; while its
+-- entitlements are NULL the PLAN_FREE_* env defaults apply.
+INSERT INTO subscription_plans (id, name, price, currency, is_active)
+VALUES ('free', 'Free', 0, 'BRL', false)
+ON CONFLICT (id) DO NOTHING;
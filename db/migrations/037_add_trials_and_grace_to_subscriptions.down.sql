@@ -0,0 +1,6 @@
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS grace_ends_at,
+    DROP COLUMN IF EXISTS trial_ends_at;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS trial_days;
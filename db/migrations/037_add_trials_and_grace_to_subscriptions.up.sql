@@ -0,0 +1,6 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS trial_days INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS trial_ends_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS grace_ends_at TIMESTAMP WITH TIME ZONE;
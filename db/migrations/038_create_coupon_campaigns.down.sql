@@ -0,0 +1,6 @@
+ALTER TABLE coupon_redemptions
+    DROP COLUMN IF EXISTS code;
+
+DROP TABLE IF EXISTS coupon_campaign_codes;
+
+DROP TABLE IF EXISTS coupon_campaigns;
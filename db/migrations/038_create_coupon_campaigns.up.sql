@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS coupon_campaigns
+(
+    id               VARCHAR                                                                       NOT NULL
+        PRIMARY KEY,
+    name             VARCHAR                                                                       NOT NULL,
+    coupon_id        VARCHAR                                                                       NOT NULL
+        REFERENCES coupons (id),
+    stripe_coupon_id VARCHAR,
+    code_prefix      VARCHAR,
+    created_at       TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL
+);
+
+ALTER TABLE IF EXISTS coupon_campaigns
+    OWNER TO silvioubaldino;
+
+CREATE INDEX IF NOT EXISTS idx_coupon_campaigns_coupon
+    ON coupon_campaigns (coupon_id);
+
+CREATE TABLE IF NOT EXISTS coupon_campaign_codes
+(
+    code                     VARCHAR                                                                       NOT NULL
+        PRIMARY KEY,
+    campaign_id              VARCHAR                                                                       NOT NULL
+        REFERENCES coupon_campaigns (id) ON DELETE CASCADE,
+    status                   VARCHAR                                                                       NOT NULL DEFAULT 'available',
+    redeemed_by              VARCHAR,
+    redemption_id            UUID
+        REFERENCES coupon_redemptions (id) ON DELETE SET NULL,
+    redeemed_at              TIMESTAMP WITH TIME ZONE,
+    stripe_promotion_code_id VARCHAR,
+    created_at               TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL
+);
+
+ALTER TABLE IF EXISTS coupon_campaign_codes
+    OWNER TO silvioubaldino;
+
+CREATE INDEX IF NOT EXISTS idx_coupon_campaign_codes_campaign
+    ON coupon_campaign_codes (campaign_id);
+
+ALTER TABLE coupon_redemptions
+    ADD COLUMN IF NOT EXISTS code VARCHAR;
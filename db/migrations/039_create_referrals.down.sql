@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS referral_rewards;
+
+DROP TABLE IF EXISTS referrals;
+
+DROP TABLE IF EXISTS referral_codes;
@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS referral_codes
+(
+    user_id    VARCHAR                                                                       NOT NULL
+        PRIMARY KEY
+        REFERENCES users (id) ON DELETE CASCADE,
+    code       VARCHAR                                                                       NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL,
+    CONSTRAINT referral_codes_code_key UNIQUE (code)
+);
+
+ALTER TABLE IF EXISTS referral_codes
+    OWNER TO silvioubaldino;
+
+CREATE TABLE IF NOT EXISTS referrals
+(
+    id            UUID                                                                          NOT NULL
+        PRIMARY KEY,
+    referrer_id   VARCHAR                                                                       NOT NULL
+        REFERENCES users (id) ON DELETE CASCADE,
+    referee_id    VARCHAR                                                                       NOT NULL
+        REFERENCES users (id) ON DELETE CASCADE,
+    code          VARCHAR                                                                       NOT NULL,
+    status        VARCHAR                                                                       NOT NULL DEFAULT 'pending',
+    reject_reason VARCHAR,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL,
+    qualified_at  TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT referrals_referee_key UNIQUE (referee_id)
+);
+
+ALTER TABLE IF EXISTS referrals
+    OWNER TO silvioubaldino;
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer
+    ON referrals (referrer_id);
+
+CREATE TABLE IF NOT EXISTS referral_rewards
+(
+    id          UUID                                                                          NOT NULL
+        PRIMARY KEY,
+    referral_id UUID                                                                          NOT NULL
+        REFERENCES referrals (id) ON DELETE CASCADE,
+    user_id     VARCHAR                                                                       NOT NULL
+        REFERENCES users (id) ON DELETE CASCADE,
+    role        VARCHAR                                                                       NOT NULL,
+    months      INTEGER                                                                       NOT NULL,
+    status      VARCHAR                                                                       NOT NULL DEFAULT 'pending',
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL,
+    applied_at  TIMESTAMP WITH TIME ZONE,
+    plus_until  TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT referral_rewards_referral_user_key UNIQUE (referral_id, user_id)
+);
+
+ALTER TABLE IF EXISTS referral_rewards
+    OWNER TO silvioubaldino;
+
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_pending
+    ON referral_rewards (created_at)
+    WHERE status = 'pending';
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS personal_access_tokens
+(
+    id           UUID                                                                          NOT NULL
+        PRIMARY KEY,
+    user_id      VARCHAR                                                                       NOT NULL
+        REFERENCES users (id) ON DELETE CASCADE,
+    name         VARCHAR                                                                       NOT NULL,
+    token_hash   VARCHAR                                                                       NOT NULL,
+    prefix       VARCHAR                                                                       NOT NULL,
+    scopes       VARCHAR                                                                       NOT NULL,
+    expires_at   TIMESTAMP WITH TIME ZONE                                                      NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at   TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL,
+    CONSTRAINT personal_access_tokens_token_hash_key UNIQUE (token_hash)
+);
+
+ALTER TABLE IF EXISTS personal_access_tokens
+    OWNER TO silvioubaldino;
+
+CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_user
+    ON personal_access_tokens (user_id);
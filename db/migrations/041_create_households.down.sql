@@ -0,0 +1,10 @@
+ALTER TABLE movements
+    DROP COLUMN IF EXISTS created_by;
+
+DROP TABLE IF EXISTS household_shares;
+
+DROP TABLE IF EXISTS household_invitations;
+
+DROP TABLE IF EXISTS household_members;
+
+DROP TABLE IF EXISTS households;
@@ -0,0 +1,79 @@
+CREATE TABLE IF NOT EXISTS households
+(
+    id         UUID                                                                          NOT NULL
+        PRIMARY KEY,
+    name       VARCHAR                                                                       NOT NULL,
+    owner_id   VARCHAR                                                                       NOT NULL
+        REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL
+);
+
+ALTER TABLE IF EXISTS households
+    OWNER TO silvioubaldino;
+
+CREATE TABLE IF NOT EXISTS household_members
+(
+    household_id UUID                                                                          NOT NULL
+        REFERENCES households (id) ON DELETE CASCADE,
+    user_id      VARCHAR                                                                       NOT NULL
+        REFERENCES users (id) ON DELETE CASCADE,
+    role         VARCHAR                                                                       NOT NULL,
+    joined_at    TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL,
+    PRIMARY KEY (household_id, user_id)
+);
+
+ALTER TABLE IF EXISTS household_members
+    OWNER TO silvioubaldino;
+
+CREATE INDEX IF NOT EXISTS idx_household_members_user
+    ON household_members (user_id);
+
+CREATE TABLE IF NOT EXISTS household_invitations
+(
+    id           UUID                                                                          NOT NULL
+        PRIMARY KEY,
+    household_id UUID                                                                          NOT NULL
+        REFERENCES households (id) ON DELETE CASCADE,
+    email        VARCHAR                                                                       NOT NULL,
+    role         VARCHAR                                                                       NOT NULL,
+    token_hash   VARCHAR                                                                       NOT NULL,
+    invited_by   VARCHAR                                                                       NOT NULL
+        REFERENCES users (id) ON DELETE CASCADE,
+    expires_at   TIMESTAMP WITH TIME ZONE                                                      NOT NULL,
+    accepted_by  VARCHAR,
+    accepted_at  TIMESTAMP WITH TIME ZONE,
+    revoked_at   TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL,
+    CONSTRAINT household_invitations_token_hash_key UNIQUE (token_hash)
+);
+
+ALTER TABLE IF EXISTS household_invitations
+    OWNER TO silvioubaldino;
+
+CREATE INDEX IF NOT EXISTS idx_household_invitations_household
+    ON household_invitations (household_id);
+
+CREATE TABLE IF NOT EXISTS household_shares
+(
+    household_id  UUID                                                                          NOT NULL
+        REFERENCES households (id) ON DELETE CASCADE,
+    resource_type VARCHAR                                                                       NOT NULL,
+    resource_id   UUID                                                                          NOT NULL,
+    shared_by     VARCHAR                                                                       NOT NULL
+        REFERENCES users (id) ON DELETE CASCADE,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'::text) NOT NULL,
+    PRIMARY KEY (household_id, resource_type, resource_id)
+);
+
+ALTER TABLE IF EXISTS household_shares
+    OWNER TO silvioubaldino;
+
+CREATE INDEX IF NOT EXISTS idx_household_shares_resource
+    ON household_shares (resource_type, resource_id);
+
+ALTER TABLE movements
+    ADD COLUMN IF NOT EXISTS created_by VARCHAR;
+
+UPDATE movements
+SET created_by = user_id
+WHERE created_by IS NULL;
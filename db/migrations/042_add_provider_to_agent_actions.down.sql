@@ -0,0 +1,3 @@
+ALTER TABLE agent_actions
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,4 @@
+-- LLM provider and region that proposed the action, copied to the audit log on confirm (LGPD Art. 37)
+ALTER TABLE agent_actions
+    ADD COLUMN IF NOT EXISTS provider TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS region   TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE coupon_campaign_codes
+    DROP COLUMN IF EXISTS reserved_until,
+    DROP COLUMN IF EXISTS reserved_by;
@@ -0,0 +1,4 @@
+-- Applying a campaign code at checkout holds it for the user until reserved_until
+ALTER TABLE coupon_campaign_codes
+    ADD COLUMN IF NOT EXISTS reserved_by    VARCHAR,
+    ADD COLUMN IF NOT EXISTS reserved_until TIMESTAMP WITH TIME ZONE;
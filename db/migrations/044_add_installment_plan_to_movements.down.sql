@@ -0,0 +1,2 @@
+alter table if exists movements drop column if exists interest_rate;
+alter table if exists movements drop column if exists total_amount;
@@ -0,0 +1,6 @@
+-- Total amount and monthly interest rate of an installment plan, repeated on each installment
+alter table if exists movements
+    add column if not exists total_amount double precision;
+
+alter table if exists movements
+    add column if not exists interest_rate double precision;
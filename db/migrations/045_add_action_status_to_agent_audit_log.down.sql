@@ -0,0 +1,2 @@
+alter table if exists agent_audit_log drop column if exists action_error;
+alter table if exists agent_audit_log drop column if exists action_status;
@@ -0,0 +1,6 @@
+-- Outcome of the audited agent action, so failed executions are also recorded
+alter table if exists agent_audit_log
+    add column if not exists action_status text;
+
+alter table if exists agent_audit_log
+    add column if not exists action_error text;
@@ -0,0 +1,205 @@
+package agenteval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-finance/internal/domain"
+)
+
+// amountTolerance absorbs the rounding of an answer written with cents.
+const amountTolerance = 0.005
+
+// FinancialRepository is the part of the agent financial repository the facts
+// are computed from.
+type FinancialRepository interface {
+	GetFinancialOverview(ctx context.Context, month, year int) (domain.AgentFinancialOverview, error)
+	GetSpendingBreakdown(ctx context.Context, month, year int) (domain.AgentSpendingBreakdown, error)
+	GetRecurringCharges(ctx context.Context) (domain.AgentRecurringCharges, error)
+}
+
+// Check is the outcome of one expectation.
+type Check struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func checkTools(expect Expect, called []string) []Check {
+	checks := make([]Check, 0, len(expect.ToolsCalled)+len(expect.ToolsNotCalled))
+	for _, name := range expect.ToolsCalled {
+		check := Check{Name: "tool called: " + name, Passed: slices.Contains(called, name)}
+		if !check.Passed {
+			check.Detail = fmt.Sprintf("called %v", called)
+		}
+		checks = append(checks, check)
+	}
+	for _, name := range expect.ToolsNotCalled {
+		check := Check{Name: "tool not called: " + name, Passed: !slices.Contains(called, name)}
+		if !check.Passed {
+			check.Detail = fmt.Sprintf("called %v", called)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func checkContains(expect Expect, answer string) []Check {
+	checks := make([]Check, 0, len(expect.Contains))
+	lower := strings.ToLower(answer)
+	for _, snippet := range expect.Contains {
+		checks = append(checks, Check{
+			Name:   fmt.Sprintf("answer contains %q", snippet),
+			Passed: strings.Contains(lower, strings.ToLower(snippet)),
+		})
+	}
+	return checks
+}
+
+func checkFacts(ctx context.Context, repo FinancialRepository, facts []Fact, answer string, now time.Time) []Check {
+	checks := make([]Check, 0, len(facts))
+	for _, fact := range facts {
+		name := "fact " + describeFact(fact)
+		value, err := factValue(ctx, repo, fact, now)
+		if err != nil {
+			checks = append(checks, Check{Name: name, Detail: err.Error()})
+			continue
+		}
+
+		check := Check{Name: fmt.Sprintf("%s = %.2f", name, value), Passed: mentionsAmount(answer, value)}
+		if !check.Passed {
+			check.Detail = fmt.Sprintf("answer does not mention %s", formatBRL(value))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func describeFact(fact Fact) string {
+	name := fact.Source
+	if fact.Category != "" {
+		name += "[" + fact.Category + "]"
+	}
+	if fact.MonthOffset != 0 {
+		name += fmt.Sprintf(" (month %+d)", fact.MonthOffset)
+	}
+	return name
+}
+
+func factValue(ctx context.Context, repo FinancialRepository, fact Fact, now time.Time) (float64, error) {
+	period := monthStart(now, fact.MonthOffset)
+	month, year := int(period.Month()), period.Year()
+
+	switch fact.Source {
+	case FactOverviewIncome, FactOverviewExpenses, FactOverviewNet, FactWalletsBalance:
+		overview, err := repo.GetFinancialOverview(ctx, month, year)
+		if err != nil {
+			return 0, err
+		}
+		switch fact.Source {
+		case FactOverviewIncome:
+			return overview.Income, nil
+		case FactOverviewExpenses:
+			return overview.Expenses, nil
+		case FactOverviewNet:
+			return overview.Net, nil
+		}
+		var total float64
+		for _, w := range overview.Wallets {
+			total += w.Balance
+		}
+		return total, nil
+	case FactSpendingCategory:
+		breakdown, err := repo.GetSpendingBreakdown(ctx, month, year)
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range breakdown.Categories {
+			if strings.EqualFold(c.Name, fact.Category) {
+				return c.Amount, nil
+			}
+		}
+		return 0, fmt.Errorf("category %q has no movements in %d-%02d", fact.Category, year, month)
+	case FactRecurringChargesTotal:
+		charges, err := repo.GetRecurringCharges(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return charges.TotalMonthly, nil
+	default:
+		return 0, fmt.Errorf("unknown fact source %q", fact.Source)
+	}
+}
+
+var numberRegex = regexp.MustCompile(`\d[\d.,]*\d|\d`)
+
+// mentionsAmount reports whether any number of the answer equals value in
+// absolute terms. Each number is read both as Brazilian ("1.234,56") and as
+// plain ("1234.56") notation, since models write either.
+func mentionsAmount(answer string, value float64) bool {
+	target := math.Abs(value)
+	for _, token := range numberRegex.FindAllString(answer, -1) {
+		brazilian := strings.ReplaceAll(strings.ReplaceAll(token, ".", ""), ",", ".")
+		plain := strings.ReplaceAll(token, ",", "")
+		for _, candidate := range []string{brazilian, plain} {
+			n, err := strconv.ParseFloat(candidate, 64)
+			if err == nil && math.Abs(n-target) < amountTolerance {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatBRL writes value as the app shows it, e.g. "R$ 1.234,56".
+func formatBRL(value float64) string {
+	cents := int64(math.Round(math.Abs(value) * 100))
+	integer := strconv.FormatInt(cents/100, 10)
+
+	var sb strings.Builder
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(r)
+	}
+	return fmt.Sprintf("R$ %s,%02d", sb.String(), cents%100)
+}
+
+// checkMemoryGuards verifies what the agent left stored: no CPF or e-mail, at
+// most one risk profile and no more memories than the cap.
+func checkMemoryGuards(memories []domain.AgentMemory) []Check {
+	pii := Check{Name: "no PII stored in memories", Passed: true}
+	var riskProfiles, others int
+	for _, m := range memories {
+		if domain.ContainsPII(m.Content) {
+			pii.Passed = false
+			pii.Detail = fmt.Sprintf("memory %s: %q", m.ID, m.Content)
+		}
+		if m.Type == domain.MemoryTypeRiskProfile {
+			riskProfiles++
+		} else {
+			others++
+		}
+	}
+
+	return []Check{
+		pii,
+		{
+			Name:   fmt.Sprintf("memory cap of %d holds", domain.MaxMemoriesPerUser),
+			Passed: others <= domain.MaxMemoriesPerUser,
+			Detail: fmt.Sprintf("%d memories", others),
+		},
+		{
+			Name:   "at most one risk profile",
+			Passed: riskProfiles <= 1,
+			Detail: fmt.Sprintf("%d risk profiles", riskProfiles),
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package agenteval
+
+import (
+	"testing"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMentionsAmount(t *testing.T) {
+	tests := map[string]struct {
+		answer   string
+		value    float64
+		expected bool
+	}{
+		"should match the brazilian notation":        {answer: "Você gastou R$ 3.161,80 no mês.", value: 3161.80, expected: true},
+		"should match the plain notation":            {answer: "Total: 3161.80", value: 3161.80, expected: true},
+		"should match thousands without cents":       {answer: "Recebeu R$ 8.500 de salário", value: 8500, expected: true},
+		"should compare negative values by absolute": {answer: "Saldo de -R$ 120,50", value: -120.50, expected: true},
+		"should not match a rounded amount":          {answer: "Cerca de R$ 3.160,00", value: 3161.80, expected: false},
+		"should not match digits of a date":          {answer: "Em 03/2026 você gastou pouco", value: 2026.03, expected: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mentionsAmount(tt.answer, tt.value))
+		})
+	}
+}
+
+func TestFormatBRL(t *testing.T) {
+	assert.Equal(t, "R$ 0,05", formatBRL(0.05))
+	assert.Equal(t, "R$ 999,90", formatBRL(-999.9))
+	assert.Equal(t, "R$ 1.234.567,89", formatBRL(1234567.89))
+}
+
+func TestCheckMemoryGuards(t *testing.T) {
+	memories := []domain.AgentMemory{
+		domain.NewAgentMemory("user-1", domain.MemoryTypeRiskProfile, "Conservador", domain.MemorySourceExplicit),
+		domain.NewAgentMemory("user-1", domain.MemoryTypeFact, "Contato: ana@example.com", domain.MemorySourceExplicit),
+	}
+
+	checks := checkMemoryGuards(memories)
+
+	assert.False(t, checks[0].Passed, "e-mail is PII")
+	assert.True(t, checks[1].Passed)
+	assert.True(t, checks[2].Passed)
+}
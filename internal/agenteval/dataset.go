@@ -0,0 +1,175 @@
+package agenteval
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Dataset is the financial data every case starts from. Wallets and categories
+// are referenced by key; movement dates are a day of a month relative to the
+// current one, so the same facts hold whenever the suite runs.
+type Dataset struct {
+	Wallets    []DatasetWallet   `json:"wallets"`
+	Categories []DatasetCategory `json:"categories"`
+	Movements  []DatasetMovement `json:"movements"`
+}
+
+type DatasetWallet struct {
+	Key         string  `json:"key"`
+	Description string  `json:"description"`
+	Type        string  `json:"type,omitempty"`
+	Balance     float64 `json:"balance"`
+}
+
+type DatasetCategory struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	IsIncome    bool   `json:"is_income,omitempty"`
+}
+
+type DatasetMovement struct {
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	MonthOffset int     `json:"month_offset"`
+	Day         int     `json:"day"`
+	Wallet      string  `json:"wallet"`
+	Category    string  `json:"category,omitempty"`
+	TypePayment string  `json:"type_payment,omitempty"`
+	Pending     bool    `json:"pending,omitempty"`
+}
+
+// OpenSQLite opens an in-memory database with the tables the agent reads and
+// writes. A single connection keeps every query on the same in-memory database.
+func OpenSQLite() (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	err = db.AutoMigrate(
+		&repository.WalletDB{},
+		&repository.CategoryDB{},
+		&repository.SubCategoryDB{},
+		&repository.MovementDB{},
+		&repository.RecurrentMovementDB{},
+		&repository.CreditCardDB{},
+		&repository.InvoiceDB{},
+		&repository.EstimateCategoryDB{},
+		&repository.EstimateSubCategoryDB{},
+		&repository.AgentMemoryDB{},
+		&repository.AgentConversationDB{},
+		&repository.AgentMessageDB{},
+		&repository.AgentAuditLogDB{},
+		&repository.AgentActionDB{},
+		&repository.AgentSettingsDB{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate sqlite: %w", err)
+	}
+	return db, nil
+}
+
+// Seed writes the dataset for userID, with dates resolved against now.
+func Seed(ctx context.Context, db *gorm.DB, dataset Dataset, userID string, now time.Time) error {
+	wallets := make(map[string]*uuid.UUID, len(dataset.Wallets))
+	categories := make(map[string]*uuid.UUID, len(dataset.Categories))
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, w := range dataset.Wallets {
+			id := uuid.New()
+			walletType := w.Type
+			if walletType == "" {
+				walletType = string(domain.WalletTypeChecking)
+			}
+			err := tx.Create(&repository.WalletDB{
+				ID:             &id,
+				Description:    w.Description,
+				Type:           walletType,
+				Balance:        w.Balance,
+				UserID:         userID,
+				InitialBalance: w.Balance,
+				InitialDate:    now,
+				DateCreate:     now,
+				DateUpdate:     now,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("failed to seed wallet %s: %w", w.Key, err)
+			}
+			wallets[w.Key] = &id
+		}
+
+		for _, c := range dataset.Categories {
+			id := uuid.New()
+			err := tx.Create(&repository.CategoryDB{
+				ID:          &id,
+				Description: c.Description,
+				UserID:      userID,
+				IsIncome:    c.IsIncome,
+				DateCreate:  now,
+				DateUpdate:  now,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("failed to seed category %s: %w", c.Key, err)
+			}
+			categories[c.Key] = &id
+		}
+
+		for i, m := range dataset.Movements {
+			walletID, ok := wallets[m.Wallet]
+			if !ok {
+				return fmt.Errorf("movement %d references unknown wallet %q", i, m.Wallet)
+			}
+			categoryID, ok := categories[m.Category]
+			if m.Category != "" && !ok {
+				return fmt.Errorf("movement %d references unknown category %q", i, m.Category)
+			}
+			if m.Day < 1 || m.Day > 28 {
+				return fmt.Errorf("movement %d has day %d; use 1-28 so it exists in every month", i, m.Day)
+			}
+
+			typePayment := m.TypePayment
+			if typePayment == "" {
+				typePayment = string(domain.TypePaymentPix)
+			}
+			id := uuid.New()
+			date := monthStart(now, m.MonthOffset).AddDate(0, 0, m.Day-1).Add(12 * time.Hour)
+			err := tx.Create(&repository.MovementDB{
+				ID:          &id,
+				Description: m.Description,
+				Amount:      m.Amount,
+				Date:        &date,
+				UserID:      userID,
+				IsPaid:      !m.Pending,
+				WalletID:    walletID,
+				TypePayment: typePayment,
+				CategoryID:  categoryID,
+				DateCreate:  now,
+				DateUpdate:  now,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("failed to seed movement %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+}
+
+// monthStart returns the first day of the month offset months away from now, in UTC.
+func monthStart(now time.Time, offset int) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+}
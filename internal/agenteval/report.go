@@ -0,0 +1,142 @@
+package agenteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Report struct {
+	Provider  string       `json:"provider"`
+	Model     string       `json:"model"`
+	StartedAt time.Time    `json:"started_at"`
+	Duration  string       `json:"duration"`
+	Passed    int          `json:"passed"`
+	Failed    int          `json:"failed"`
+	Cases     []CaseResult `json:"cases"`
+}
+
+type CaseResult struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Passed      bool         `json:"passed"`
+	Error       string       `json:"error,omitempty"`
+	Turns       []TurnResult `json:"turns"`
+	// Guards are checked on the stored memories after the last turn.
+	Guards []Check `json:"guards"`
+}
+
+type TurnResult struct {
+	Message      string   `json:"message"`
+	Answer       string   `json:"answer"`
+	ToolsCalled  []string `json:"tools_called"`
+	InputTokens  int      `json:"input_tokens"`
+	OutputTokens int      `json:"output_tokens"`
+	Error        string   `json:"error,omitempty"`
+	Checks       []Check  `json:"checks"`
+}
+
+// OK reports whether every case passed.
+func (r Report) OK() bool {
+	return r.Failed == 0
+}
+
+func (r *Report) add(result CaseResult) {
+	result.Passed = result.Error == "" && allPassed(result.Guards)
+	for _, turn := range result.Turns {
+		if turn.Error != "" || !allPassed(turn.Checks) {
+			result.Passed = false
+		}
+	}
+
+	if result.Passed {
+		r.Passed++
+	} else {
+		r.Failed++
+	}
+	r.Cases = append(r.Cases, result)
+}
+
+func allPassed(checks []Check) bool {
+	for _, c := range checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes a summary table followed by the failed checks of each
+// case, which is what a reviewer of a prompt change needs to look at.
+func (r Report) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("# Agent evaluation\n\n")
+	fmt.Fprintf(&sb, "- Provider: %s (%s)\n", r.Provider, r.Model)
+	fmt.Fprintf(&sb, "- Started at: %s (%s)\n", r.StartedAt.Format(time.RFC3339), r.Duration)
+	fmt.Fprintf(&sb, "- Result: %d passed, %d failed\n\n", r.Passed, r.Failed)
+
+	sb.WriteString("| Case | Result | Tools called | Tokens (in/out) |\n")
+	sb.WriteString("|---|---|---|---|\n")
+	for _, c := range r.Cases {
+		var tools []string
+		var in, out int
+		for _, turn := range c.Turns {
+			tools = append(tools, turn.ToolsCalled...)
+			in += turn.InputTokens
+			out += turn.OutputTokens
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %d/%d |\n", c.Name, result(c.Passed), strings.Join(tools, ", "), in, out)
+	}
+
+	for _, c := range r.Cases {
+		if c.Passed {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n## %s\n\n", c.Name)
+		if c.Description != "" {
+			fmt.Fprintf(&sb, "%s\n\n", c.Description)
+		}
+		if c.Error != "" {
+			fmt.Fprintf(&sb, "Error: %s\n\n", c.Error)
+		}
+		for i, turn := range c.Turns {
+			fmt.Fprintf(&sb, "**Turn %d:** %s\n\n", i+1, turn.Message)
+			fmt.Fprintf(&sb, "> %s\n\n", strings.ReplaceAll(strings.TrimSpace(turn.Answer), "\n", "\n> "))
+			if turn.Error != "" {
+				fmt.Fprintf(&sb, "- FAIL error: %s\n", turn.Error)
+			}
+			writeChecks(&sb, turn.Checks)
+			sb.WriteString("\n")
+		}
+		writeChecks(&sb, c.Guards)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeChecks(sb *strings.Builder, checks []Check) {
+	for _, check := range checks {
+		fmt.Fprintf(sb, "- %s %s", result(check.Passed), check.Name)
+		if !check.Passed && check.Detail != "" {
+			fmt.Fprintf(sb, ": %s", check.Detail)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+func result(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
@@ -0,0 +1,152 @@
+package agenteval
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/internal/usecase"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Runner plays a suite through the same use case, gateway and repositories the
+// API uses. Each case runs as a new user with its own copy of the dataset, so
+// cases do not see each other's memories and conversations.
+type Runner struct {
+	db       *gorm.DB
+	provider gateway.LLMProvider
+	embedder domain.EmbeddingProvider
+}
+
+// NewRunner creates a runner. A nil provider replays the fake script of each
+// turn; otherwise every turn goes to the given provider.
+func NewRunner(db *gorm.DB, provider gateway.LLMProvider, embedder domain.EmbeddingProvider) *Runner {
+	return &Runner{db: db, provider: provider, embedder: embedder}
+}
+
+// recordingAuditRepository keeps the last audit record of the turn, which is
+// where the use case reports the tools called and the tokens used.
+type recordingAuditRepository struct {
+	usecase.AgentAuditRepository
+	last domain.AgentAuditRecord
+}
+
+func (r *recordingAuditRepository) Log(ctx context.Context, record domain.AgentAuditRecord) error {
+	r.last = record
+	return r.AgentAuditRepository.Log(ctx, record)
+}
+
+func (r *Runner) Run(ctx context.Context, suite Suite) Report {
+	started := time.Now()
+	report := Report{Provider: gateway.ProviderFake, Model: "scripted", StartedAt: started}
+	if r.provider != nil {
+		report.Provider = r.provider.Name()
+		report.Model = r.provider.ModelName()
+	}
+
+	runID := uuid.NewString()[:8]
+	for i, c := range suite.Cases {
+		userID := fmt.Sprintf("agenteval-%s-%02d", runID, i+1)
+		report.add(r.runCase(ctx, suite, c, userID))
+	}
+
+	report.Duration = time.Since(started).Round(time.Millisecond).String()
+	return report
+}
+
+func (r *Runner) runCase(ctx context.Context, suite Suite, c Case, userID string) CaseResult {
+	result := CaseResult{Name: c.Name, Description: c.Description}
+	now := time.Now()
+
+	// Repositories read the user from either context key, depending on their age.
+	ctx = context.WithValue(ctx, authentication.UserID, userID)
+	ctx = authentication.ContextWithAuth(ctx, authentication.AuthContext{UserID: userID})
+
+	memoryRepo := repository.NewAgentMemoryRepository(r.db).WithEmbeddings(r.embedder)
+	financialRepo := repository.NewAgentFinancialRepository(r.db)
+	auditRepo := &recordingAuditRepository{AgentAuditRepository: repository.NewAgentAuditRepository(r.db)}
+
+	if err := Seed(ctx, r.db, suite.Dataset, userID, now); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if err := seedMemories(ctx, memoryRepo, userID, c.SeedMemories); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var conversationID *uuid.UUID
+	for _, turn := range c.Turns {
+		provider := r.provider
+		if provider == nil {
+			provider = gateway.NewFakeProvider(filepath.Join(suite.Dir, turn.FakeScript))
+		}
+		agentUseCase := usecase.NewAgentUseCase(
+			memoryRepo,
+			repository.NewAgentConversationRepository(r.db),
+			auditRepo,
+			repository.NewAgentActionRepository(r.db),
+			gateway.NewADKAgentGateway(memoryRepo, repository.NewAgentSettingsRepository(r.db), financialRepo, provider),
+			nil,
+			usecase.AgentContextWindow{},
+		)
+
+		auditRepo.last = domain.AgentAuditRecord{}
+		output, err := agentUseCase.Chat(ctx, usecase.AgentChatInput{Message: turn.Message, ConversationID: conversationID})
+
+		turnResult := TurnResult{
+			Message:      turn.Message,
+			Answer:       output.Response,
+			ToolsCalled:  auditRepo.last.ToolsCalled,
+			InputTokens:  auditRepo.last.InputTokens,
+			OutputTokens: auditRepo.last.OutputTokens,
+		}
+		if err != nil {
+			turnResult.Error = err.Error()
+			result.Turns = append(result.Turns, turnResult)
+			break
+		}
+		conversationID = &output.ConversationID
+
+		turnResult.Checks = append(turnResult.Checks, checkTools(turn.Expect, turnResult.ToolsCalled)...)
+		turnResult.Checks = append(turnResult.Checks, checkContains(turn.Expect, output.Response)...)
+		turnResult.Checks = append(turnResult.Checks, checkFacts(ctx, financialRepo, turn.Expect.Facts, output.Response, now)...)
+		result.Turns = append(result.Turns, turnResult)
+	}
+
+	memories, err := memoryRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Guards = checkMemoryGuards(memories)
+	return result
+}
+
+func seedMemories(ctx context.Context, repo *repository.AgentMemoryRepository, userID string, seeds []SeedMemory) error {
+	for _, seed := range seeds {
+		memType := domain.AgentMemoryType(seed.MemoryType)
+		if !memType.IsValid() {
+			return fmt.Errorf("invalid seed memory type %q", seed.MemoryType)
+		}
+
+		count := max(seed.Count, 1)
+		for i := 1; i <= count; i++ {
+			content := seed.Content
+			if count > 1 {
+				content = fmt.Sprintf("%s (%d)", seed.Content, i)
+			}
+			if _, err := repo.Save(ctx, domain.NewAgentMemory(userID, memType, content, domain.MemorySourceExplicit)); err != nil {
+				return fmt.Errorf("failed to seed memories: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package agenteval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"personal-finance/internal/infrastructure/gateway"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_GoldenSuite(t *testing.T) {
+	suite, err := LoadSuite("golden")
+	require.NoError(t, err)
+	db, err := OpenSQLite()
+	require.NoError(t, err)
+
+	report := NewRunner(db, nil, gateway.NewLocalEmbeddingProvider()).Run(context.Background(), suite)
+
+	var md bytes.Buffer
+	require.NoError(t, report.WriteMarkdown(&md))
+	require.True(t, report.OK(), md.String())
+	assert.Equal(t, len(suite.Cases), report.Passed)
+	assert.Equal(t, gateway.ProviderFake, report.Provider)
+
+	var decoded Report
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJSON(&out))
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, report.Cases[0].Turns[0].ToolsCalled, decoded.Cases[0].Turns[0].ToolsCalled)
+}
+
+func TestRunner_ReportsFailures(t *testing.T) {
+	suite, err := LoadSuite("golden")
+	require.NoError(t, err)
+	db, err := OpenSQLite()
+	require.NoError(t, err)
+
+	overview := suite.Cases[0]
+	overview.Turns = overview.Turns[:1]
+	overview.Turns[0].Expect = Expect{
+		ToolsCalled:    []string{"get_spending_breakdown"},
+		ToolsNotCalled: []string{"get_financial_overview"},
+		Facts:          []Fact{{Source: FactOverviewNet}},
+	}
+	suite.Cases = []Case{overview}
+
+	report := NewRunner(db, nil, gateway.NewLocalEmbeddingProvider()).Run(context.Background(), suite)
+
+	require.False(t, report.OK())
+	require.Len(t, report.Cases, 1)
+	checks := report.Cases[0].Turns[0].Checks
+	require.Len(t, checks, 3)
+	for _, check := range checks {
+		assert.False(t, check.Passed, check.Name)
+	}
+	assert.Equal(t, "answer does not mention R$ 5.338,20", checks[2].Detail)
+
+	var md bytes.Buffer
+	require.NoError(t, report.WriteMarkdown(&md))
+	assert.Contains(t, md.String(), "## monthly_overview")
+	assert.Contains(t, md.String(), "- FAIL tool called: get_spending_breakdown")
+}
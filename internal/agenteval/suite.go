@@ -0,0 +1,121 @@
+// Package agenteval runs golden conversations against the agent with a seeded
+// dataset and checks the answers, so a change in the system prompt or in a tool
+// can be compared before it ships.
+package agenteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	datasetFile = "dataset.json"
+	casesDir    = "cases"
+)
+
+// Fact sources, each backed by an AgentFinancialRepository query.
+const (
+	FactOverviewIncome        = "overview.income"
+	FactOverviewExpenses      = "overview.expenses"
+	FactOverviewNet           = "overview.net"
+	FactWalletsBalance        = "wallets.balance"
+	FactSpendingCategory      = "spending.category"
+	FactRecurringChargesTotal = "recurring_charges.total_monthly"
+)
+
+// Suite is a dataset plus the cases that run against it. On disk it is a
+// directory with dataset.json and one JSON file per case under cases/.
+type Suite struct {
+	Dir     string
+	Dataset Dataset
+	Cases   []Case
+}
+
+// Case is one conversation. Turns share the conversation, so later turns see the
+// history of the earlier ones.
+type Case struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// SeedMemories are saved before the first turn, bypassing the agent rules,
+	// e.g. to start the conversation at the memory cap.
+	SeedMemories []SeedMemory `json:"seed_memories,omitempty"`
+	Turns        []Turn       `json:"turns"`
+}
+
+type SeedMemory struct {
+	MemoryType string `json:"memory_type"`
+	Content    string `json:"content"`
+	// Count saves the memory that many times, numbering the content.
+	Count int `json:"count,omitempty"`
+}
+
+type Turn struct {
+	Message string `json:"message"`
+	// FakeScript is the fake provider script of the turn, relative to the suite
+	// directory. It is ignored when the suite runs against a real provider.
+	FakeScript string `json:"fake_script,omitempty"`
+	Expect     Expect `json:"expect"`
+}
+
+type Expect struct {
+	ToolsCalled    []string `json:"tools_called,omitempty"`
+	ToolsNotCalled []string `json:"tools_not_called,omitempty"`
+	// Contains are case-insensitive snippets the answer must have.
+	Contains []string `json:"contains,omitempty"`
+	// Facts are amounts the answer must mention, as computed from the dataset.
+	Facts []Fact `json:"facts,omitempty"`
+}
+
+// Fact points to an amount of the financial repository. MonthOffset is relative
+// to the current month, like the dataset dates, so the suite does not age.
+type Fact struct {
+	Source      string `json:"source"`
+	MonthOffset int    `json:"month_offset,omitempty"`
+	Category    string `json:"category,omitempty"`
+}
+
+// LoadSuite reads a suite directory. Cases run in file name order.
+func LoadSuite(dir string) (Suite, error) {
+	suite := Suite{Dir: dir}
+	if err := readJSON(filepath.Join(dir, datasetFile), &suite.Dataset); err != nil {
+		return Suite{}, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, casesDir, "*.json"))
+	if err != nil {
+		return Suite{}, fmt.Errorf("failed to list cases: %w", err)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		var c Case
+		if err := readJSON(path, &c); err != nil {
+			return Suite{}, err
+		}
+		if c.Name == "" {
+			c.Name = filepath.Base(path)
+		}
+		if len(c.Turns) == 0 {
+			return Suite{}, fmt.Errorf("case %s has no turns", c.Name)
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	if len(suite.Cases) == 0 {
+		return Suite{}, fmt.Errorf("no cases found in %s", filepath.Join(dir, casesDir))
+	}
+	return suite, nil
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package accesstoken
+
+import (
+	"personal-finance/internal/bootstrap/registry"
+	"personal-finance/internal/infrastructure/api"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Setup(r *gin.Engine, registry *registry.Registry) {
+	api.NewPersonalAccessTokenHandlers(r, NewUseCase(registry))
+}
+
+// NewUseCase builds the personal access tokens. Used by the /me/tokens routes
+// and the authentication middleware.
+func NewUseCase(registry *registry.Registry) *usecase.PersonalAccessTokens {
+	firebaseGateway := gateway.NewFirebaseGateway(registry.GetAuthenticator().ClaimsStore())
+	return usecase.NewPersonalAccessTokens(registry.GetPersonalAccessTokenRepository(), firebaseGateway)
+}
@@ -1,7 +1,9 @@
 package admin
 
 import (
+	"personal-finance/internal/bootstrap/coupon"
 	"personal-finance/internal/bootstrap/registry"
+	"personal-finance/internal/bootstrap/subscription"
 	"personal-finance/internal/infrastructure/api"
 	"personal-finance/internal/infrastructure/gateway"
 	"personal-finance/internal/usecase"
@@ -10,10 +12,7 @@ import (
 )
 
 func Setup(r *gin.Engine, registry *registry.Registry) {
-	authenticator := registry.GetAuthenticator()
-	authClient := authenticator.AuthClient()
-
-	firebaseGateway := gateway.NewFirebaseGateway(authClient)
+	firebaseGateway := gateway.NewFirebaseGateway(registry.GetAuthenticator().ClaimsStore())
 	adminUseCase := usecase.NewAdmin(firebaseGateway)
 	subscriptionUseCase := usecase.NewSubscription(
 		nil,
@@ -25,4 +24,12 @@ func Setup(r *gin.Engine, registry *registry.Registry) {
 	)
 
 	api.NewAdminHandlers(r, adminUseCase, subscriptionUseCase, subscriptionUseCase)
+	api.NewAIQuotaAdminHandlers(r, registry.GetAIQuota())
+	api.NewPlanEntitlementsAdminHandlers(r, registry.GetEntitlements())
+	api.NewSubscriptionAnalyticsAdminHandlers(r, usecase.NewSubscriptionAnalytics(
+		registry.GetSubscriptionRepository(),
+		registry.GetSubscriptionPlanRepository(),
+		registry.GetCouponRedemptionRepository(),
+	))
+	api.NewWebhookInboxAdminHandlers(r, subscription.NewWebhookInbox(registry, coupon.NewUseCase(registry)))
 }
@@ -1,49 +1,130 @@
 package agent
 
 import (
+	"os"
+
 	"personal-finance/internal/bootstrap/registry"
+	"personal-finance/internal/domain"
 	"personal-finance/internal/infrastructure/api"
 	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/infrastructure/push"
 	"personal-finance/internal/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
 func Setup(r *gin.Engine, reg *registry.Registry) {
-	// Repositories
-	memoryRepo := reg.GetAgentMemoryRepository()
+	// Repositories; memories are embedded with the provider from AGENT_EMBEDDING_PROVIDER
+	memoryRepo := reg.GetAgentMemoryRepository().WithEmbeddings(gateway.NewEmbeddingProviderFromEnv())
 	convRepo := reg.GetAgentConversationRepository()
 	auditRepo := reg.GetAgentAuditRepository()
+	actionRepo := reg.GetAgentActionRepository()
+	settingsRepo := reg.GetAgentSettingsRepository()
 	financialRepo := reg.GetAgentFinancialRepository()
 
-	// Gateway: ADK + Vertex AI
-	agentGateway := gateway.NewADKAgentGateway(memoryRepo, financialRepo)
+	// Gateway: ADK + LLM provider from AGENT_LLM_PROVIDER
+	agentGateway := gateway.NewADKAgentGateway(memoryRepo, settingsRepo, financialRepo, gateway.NewLLMProviderFromEnv())
 
 	// Use case
 	agentUseCase := usecase.NewAgentUseCase(
 		memoryRepo,
 		convRepo,
 		auditRepo,
+		actionRepo,
 		agentGateway,
+		reg.GetAIQuota(),
+		usecase.AgentContextWindow{},
+	)
+
+	// Confirmed actions run through the same services as the app
+	movementRepo := reg.GetMovementRepository()
+	recurrentRepo := reg.GetRecurrentMovementRepository()
+	walletRepo := reg.GetWalletRepository()
+	invoiceRepo := reg.GetInvoiceRepository()
+	creditCardRepo := reg.GetCreditCardRepository()
+	txManager := reg.GetTransactionManager()
+	limitsValidator := reg.GetPlanLimitsValidator()
+
+	invoiceService := usecase.NewInvoice(
+		invoiceRepo,
+		creditCardRepo,
+		walletRepo,
+		movementRepo,
+		txManager,
+	)
+	movementService := usecase.NewMovement(
+		movementRepo,
+		recurrentRepo,
+		walletRepo,
+		reg.GetSubCategoryRepository(),
+		invoiceRepo,
+		&invoiceService,
+		creditCardRepo,
+		txManager,
+		limitsValidator,
+	)
+	transferService := usecase.NewTransfer(
+		movementRepo,
+		recurrentRepo,
+		walletRepo,
+		txManager,
+		limitsValidator,
+	)
+
+	agentActions := usecase.NewAgentActions(
+		actionRepo,
+		auditRepo,
+		&movementService,
+		&transferService,
+		usecase.NewEstimate(reg.GetEstimateRepository()),
+		limitsValidator,
 	)
 
 	// API handlers (authenticated routes)
-	api.NewAgentHandlers(r, agentUseCase)
+	// Data controls stay available when the plan has no agent chat.
+	api.NewAgentHandlers(r, agentUseCase, api.RequireFeature(reg.GetEntitlements(), domain.FeatureAgentChat))
+	api.NewAgentActionHandlers(r, agentActions)
+	api.NewAgentInsightsHandlers(r, newAgentInsights(reg, agentGateway))
+	api.NewAgentDataControlsHandlers(r, reg.GetAgentDataControls())
 }
 
 func SetupJobs(jobsGroup *gin.RouterGroup, reg *registry.Registry) {
 	memoryRepo := reg.GetAgentMemoryRepository()
 	convRepo := reg.GetAgentConversationRepository()
 	auditRepo := reg.GetAgentAuditRepository()
+	actionRepo := reg.GetAgentActionRepository()
 	financialRepo := reg.GetAgentFinancialRepository()
-	agentGateway := gateway.NewADKAgentGateway(memoryRepo, financialRepo)
+	agentGateway := gateway.NewADKAgentGateway(memoryRepo, reg.GetAgentSettingsRepository(), financialRepo, gateway.NewLLMProviderFromEnv())
 
 	agentUseCase := usecase.NewAgentUseCase(
 		memoryRepo,
 		convRepo,
 		auditRepo,
+		actionRepo,
 		agentGateway,
+		reg.GetAIQuota(),
+		usecase.AgentContextWindow{},
 	)
 
 	api.NewAgentJobHandlers(jobsGroup, agentUseCase)
+	api.NewAgentInsightsJobHandlers(jobsGroup, newAgentInsights(reg, agentGateway))
+}
+
+// newAgentInsights only lets the agent phrase the weekly digest when
+// AGENT_INSIGHTS_PHRASING is "true"; otherwise it is written from the signals.
+func newAgentInsights(reg *registry.Registry, agentGateway usecase.InsightPhraser) *usecase.AgentInsights {
+	var phraser usecase.InsightPhraser
+	if os.Getenv("AGENT_INSIGHTS_PHRASING") == "true" {
+		phraser = agentGateway
+	}
+
+	return usecase.NewAgentInsights(
+		reg.GetAgentInsightPreferenceRepository(),
+		reg.GetAgentFinancialRepository(),
+		reg.GetAgentMemoryRepository(),
+		reg.GetDeviceRepository(),
+		push.NewExpoClient(),
+		phraser,
+		reg.GetAIQuota(),
+	)
 }
@@ -17,14 +17,16 @@ func NewUseCase(registry *registry.Registry) *usecase.Coupon {
 		registry.GetCouponRedemptionRepository(),
 		registry.GetSubscriptionPlanRepository(),
 		registry.GetDB(),
-	)
+	).WithCampaigns(registry.GetCouponCampaignRepository())
 }
 
-// Setup registers the admin CRUD and authenticated preview endpoints.
+// Setup registers the admin CRUD, the campaign endpoints and the authenticated
+// preview endpoint.
 func Setup(r *gin.Engine, registry *registry.Registry) {
 	couponUseCase := NewUseCase(registry)
 	authenticator := registry.GetAuthenticator()
 
 	api.NewCouponAdminHandlers(r, couponUseCase)
+	api.NewCouponCampaignAdminHandlers(r, couponUseCase)
 	api.NewCouponPublicHandlers(r, couponUseCase, authenticator.Authenticate())
 }
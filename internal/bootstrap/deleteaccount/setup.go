@@ -33,6 +33,16 @@ func Setup(r *gin.Engine, reg *registry.Registry) {
 		creditCardRepo,
 		invoiceRepo,
 		estimateRepo,
+		[]usecase.DeleteAccountAgentRepository{
+			reg.GetAgentMemoryRepository(),
+			reg.GetAgentConversationRepository(),
+			reg.GetAgentAuditRepository(),
+			reg.GetAgentActionRepository(),
+			reg.GetAgentSettingsRepository(),
+			reg.GetAgentInsightPreferenceRepository(),
+			reg.GetAIUsageRepository(),
+			reg.GetAIQuotaOverrideRepository(),
+		},
 	)
 
 	api.NewDeleteAccountHandlers(r, &deleteAccountUseCase)
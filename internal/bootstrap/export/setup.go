@@ -31,6 +31,7 @@ func Setup(r *gin.Engine, reg *registry.Registry) {
 		creditCardRepo,
 		invoiceRepo,
 		estimateRepo,
+		reg.GetAgentDataControls(),
 	)
 
 	api.NewExportHandlers(r, &exportUseCase)
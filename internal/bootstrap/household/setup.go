@@ -0,0 +1,23 @@
+package household
+
+import (
+	"personal-finance/internal/bootstrap/registry"
+	"personal-finance/internal/infrastructure/api"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Setup(r *gin.Engine, registry *registry.Registry) {
+	api.NewHouseholdHandlers(r, NewUseCase(registry))
+}
+
+// Scope loads the households of the authenticated user for the repositories
+// to reach the resources shared with them. Registered after authentication.
+func Scope(registry *registry.Registry) gin.HandlerFunc {
+	return api.HouseholdScope(NewUseCase(registry))
+}
+
+func NewUseCase(registry *registry.Registry) *usecase.Households {
+	return usecase.NewHouseholds(registry.GetHouseholdRepository())
+}
@@ -25,3 +25,19 @@ func Setup(r *gin.Engine, registry *registry.Registry) {
 
 	api.NewInvoiceV2Handlers(r, &invoiceService)
 }
+
+func SetupJobs(jobsGroup *gin.RouterGroup, registry *registry.Registry) {
+	invoiceRepo := registry.GetInvoiceRepository()
+
+	invoiceService := usecase.NewInvoice(
+		invoiceRepo,
+		registry.GetCreditCardRepository(),
+		registry.GetWalletRepository(),
+		registry.GetMovementRepository(),
+		registry.GetTransactionManager(),
+	)
+
+	jobsService := usecase.NewInvoiceJobs(invoiceRepo, invoiceService)
+
+	api.NewInvoiceJobHandlers(jobsGroup, &jobsService)
+}
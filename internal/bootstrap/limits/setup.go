@@ -14,7 +14,7 @@ func Setup(r *gin.Engine, registry *registry.Registry) {
 	movementRepo := registry.GetMovementRepository()
 	recurrentRepo := registry.GetRecurrentMovementRepository()
 
-	limitsUseCase := usecase.NewLimits(walletRepo, creditCardRepo, movementRepo, recurrentRepo)
+	limitsUseCase := usecase.NewLimits(walletRepo, creditCardRepo, movementRepo, recurrentRepo, registry.GetAIQuota(), registry.GetEntitlements())
 
 	api.NewLimitsHandlers(r, limitsUseCase)
 }
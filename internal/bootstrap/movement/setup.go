@@ -39,4 +39,5 @@ func Setup(r *gin.Engine, registry *registry.Registry) {
 	)
 
 	api.NewMovementV2Handlers(r, &movementService)
+	api.NewRecurringChargeHandlers(r, usecase.NewRecurringCharges(movementRepo))
 }
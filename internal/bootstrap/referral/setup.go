@@ -0,0 +1,25 @@
+package referral
+
+import (
+	"personal-finance/internal/bootstrap/registry"
+	"personal-finance/internal/infrastructure/api"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Setup(r *gin.Engine, registry *registry.Registry) {
+	api.NewReferralHandlers(r, NewUseCase(registry))
+}
+
+func SetupJobs(jobsGroup *gin.RouterGroup, registry *registry.Registry) {
+	api.NewReferralJobHandlers(jobsGroup, NewUseCase(registry))
+}
+
+// NewUseCase builds the referral program. Used by the /me routes, the rewards
+// job, the user provisioning and the subscription webhooks.
+func NewUseCase(registry *registry.Registry) *usecase.Referrals {
+	firebaseGateway := gateway.NewFirebaseGateway(registry.GetAuthenticator().ClaimsStore())
+	return usecase.NewReferrals(registry.GetReferralRepository(), registry.GetDeviceRepository(), firebaseGateway)
+}
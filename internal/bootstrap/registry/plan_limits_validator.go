@@ -4,8 +4,8 @@ import (
 	"context"
 	"time"
 
+	"personal-finance/internal/domain"
 	"personal-finance/internal/infrastructure/repository"
-	"personal-finance/internal/plataform/authentication"
 	"personal-finance/internal/usecase"
 	"personal-finance/pkg/metrics"
 )
@@ -15,6 +15,7 @@ type PlanLimitsValidator struct {
 	creditCardRepo *repository.CreditCardRepository
 	movementRepo   *repository.MovementRepository
 	recurrentRepo  *repository.RecurrentMovementRepository
+	entitlements   usecase.EntitlementResolver
 }
 
 func NewPlanLimitsValidator(
@@ -22,32 +23,29 @@ func NewPlanLimitsValidator(
 	creditCardRepo *repository.CreditCardRepository,
 	movementRepo *repository.MovementRepository,
 	recurrentRepo *repository.RecurrentMovementRepository,
+	entitlements usecase.EntitlementResolver,
 ) *PlanLimitsValidator {
 	return &PlanLimitsValidator{
 		walletRepo:     walletRepo,
 		creditCardRepo: creditCardRepo,
 		movementRepo:   movementRepo,
 		recurrentRepo:  recurrentRepo,
+		entitlements:   entitlements,
 	}
 }
 
 func (v *PlanLimitsValidator) ValidateWalletCreation(ctx context.Context) error {
-	auth, ok := authentication.AuthFromContext(ctx)
-	if !ok {
-		return usecase.ErrUnauthorized
-	}
-
-	if !auth.IsFree() {
-		return nil
+	limit, limited, err := v.limit(ctx, domain.LimitWallets)
+	if err != nil || !limited {
+		return err
 	}
 
-	limits := authentication.GetFreePlanLimits()
 	count, err := v.walletRepo.CountByUserID(ctx)
 	if err != nil {
 		return err
 	}
 
-	if count >= int64(limits.Wallets) {
+	if count >= int64(limit) {
 		metrics.IncBusiness(ctx, "biz_plan_limit_hits_total", 1, metrics.String("limit_type", "wallet"))
 		return usecase.ErrWalletLimitReached
 	}
@@ -56,22 +54,17 @@ func (v *PlanLimitsValidator) ValidateWalletCreation(ctx context.Context) error
 }
 
 func (v *PlanLimitsValidator) ValidateCreditCardCreation(ctx context.Context) error {
-	auth, ok := authentication.AuthFromContext(ctx)
-	if !ok {
-		return usecase.ErrUnauthorized
-	}
-
-	if !auth.IsFree() {
-		return nil
+	limit, limited, err := v.limit(ctx, domain.LimitCreditCards)
+	if err != nil || !limited {
+		return err
 	}
 
-	limits := authentication.GetFreePlanLimits()
 	count, err := v.creditCardRepo.CountByUserID(ctx)
 	if err != nil {
 		return err
 	}
 
-	if count >= int64(limits.CreditCards) {
+	if count >= int64(limit) {
 		metrics.IncBusiness(ctx, "biz_plan_limit_hits_total", 1, metrics.String("limit_type", "credit_card"))
 		return usecase.ErrCreditCardLimitReached
 	}
@@ -80,23 +73,18 @@ func (v *PlanLimitsValidator) ValidateCreditCardCreation(ctx context.Context) er
 }
 
 func (v *PlanLimitsValidator) ValidateMovementCreation(ctx context.Context) error {
-	auth, ok := authentication.AuthFromContext(ctx)
-	if !ok {
-		return usecase.ErrUnauthorized
-	}
-
-	if !auth.IsFree() {
-		return nil
+	limit, limited, err := v.limit(ctx, domain.LimitMovementsPerMonth)
+	if err != nil || !limited {
+		return err
 	}
 
-	limits := authentication.GetFreePlanLimits()
 	now := time.Now()
 	count, err := v.movementRepo.CountByUserIDAndMonth(ctx, now.Year(), now.Month())
 	if err != nil {
 		return err
 	}
 
-	if count >= int64(limits.MovementsPerMonth) {
+	if count >= int64(limit) {
 		metrics.IncBusiness(ctx, "biz_plan_limit_hits_total", 1, metrics.String("limit_type", "movement"))
 		return usecase.ErrMovementLimitReached
 	}
@@ -105,26 +93,36 @@ func (v *PlanLimitsValidator) ValidateMovementCreation(ctx context.Context) erro
 }
 
 func (v *PlanLimitsValidator) ValidateRecurrenceCreation(ctx context.Context) error {
-	auth, ok := authentication.AuthFromContext(ctx)
-	if !ok {
-		return usecase.ErrUnauthorized
-	}
-
-	if !auth.IsFree() {
-		return nil
+	limit, limited, err := v.limit(ctx, domain.LimitRecurrencesPerMonth)
+	if err != nil || !limited {
+		return err
 	}
 
-	limits := authentication.GetFreePlanLimits()
 	now := time.Now()
 	count, err := v.recurrentRepo.CountActiveByUserIDAndMonth(ctx, now.Year(), now.Month())
 	if err != nil {
 		return err
 	}
 
-	if count >= int64(limits.RecurrencesPerMonth) {
+	if count >= int64(limit) {
 		metrics.IncBusiness(ctx, "biz_plan_limit_hits_total", 1, metrics.String("limit_type", "recurrence"))
 		return usecase.ErrRecurrenceLimitReached
 	}
 
 	return nil
 }
+
+// limit returns the limit of the current user plan and false when it is
+// unlimited.
+func (v *PlanLimitsValidator) limit(ctx context.Context, name domain.LimitName) (int, bool, error) {
+	if v.entitlements == nil {
+		return 0, false, domain.WrapInternalError(domain.New("entitlement resolver not configured"), "error resolving plan limits")
+	}
+
+	ent, err := v.entitlements.Resolve(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	limit, limited := ent.Limit(name)
+	return limit, limited, nil
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
 	"personal-finance/internal/plataform/authentication"
 	"personal-finance/internal/usecase"
 
@@ -48,6 +50,26 @@ func (m *MockRecurrentRepo) CountActiveByUserIDAndMonth(ctx context.Context, yea
 	return args.Get(0).(int64), args.Error(1)
 }
 
+// stubPlanRepo and stubSubscriptionRepo hold no plans or subscriptions, so the
+// entitlements fall back to the defaults of the plan tier.
+type stubPlanRepo struct {
+	usecase.PlanEntitlementsRepository
+}
+
+func (stubPlanRepo) FindByID(_ context.Context, _ string) (domain.SubscriptionPlan, error) {
+	return domain.SubscriptionPlan{}, repository.ErrSubscriptionPlanNotFound
+}
+
+type stubSubscriptionRepo struct{}
+
+func (stubSubscriptionRepo) FindByUserID(_ context.Context, _ string) ([]domain.Subscription, error) {
+	return nil, nil
+}
+
+func newTestEntitlements() usecase.EntitlementResolver {
+	return usecase.NewEntitlements(stubPlanRepo{}, stubSubscriptionRepo{})
+}
+
 func TestPlanLimitsValidator_ValidateWalletCreation(t *testing.T) {
 	tests := map[string]struct {
 		plan          authentication.Plan
@@ -87,13 +109,14 @@ func TestPlanLimitsValidator_ValidateWalletCreation(t *testing.T) {
 				creditCardRepo: nil,
 				movementRepo:   nil,
 				recurrentRepo:  nil,
+				entitlements:   newTestEntitlements(),
 			}
 
 			authCtx := authentication.NewAuthContext("user-123", "", tc.plan, authentication.RoleUser, "", authentication.SubscriptionSourceNone, false)
 			ctx := authentication.ContextWithAuth(context.Background(), authCtx)
 
 			if tc.plan == authentication.PlanFree {
-				validator = &PlanLimitsValidator{}
+				validator = &PlanLimitsValidator{entitlements: newTestEntitlements()}
 				mockWalletRepo.On("CountByUserID", mock.Anything).Return(tc.walletCount, nil)
 			}
 
@@ -137,7 +160,7 @@ func TestPlanLimitsValidator_ValidateCreditCardCreation(t *testing.T) {
 			authCtx := authentication.NewAuthContext("user-123", "", tc.plan, authentication.RoleUser, "", authentication.SubscriptionSourceNone, false)
 			ctx := authentication.ContextWithAuth(context.Background(), authCtx)
 
-			validator := &PlanLimitsValidator{}
+			validator := &PlanLimitsValidator{entitlements: newTestEntitlements()}
 
 			if tc.plan == authentication.PlanPlus {
 				err := validator.ValidateCreditCardCreation(ctx)
@@ -148,7 +171,7 @@ func TestPlanLimitsValidator_ValidateCreditCardCreation(t *testing.T) {
 }
 
 func TestPlanLimitsValidator_Unauthorized(t *testing.T) {
-	validator := &PlanLimitsValidator{}
+	validator := &PlanLimitsValidator{entitlements: newTestEntitlements()}
 	ctx := context.Background()
 
 	err := validator.ValidateWalletCreation(ctx)
@@ -163,3 +186,11 @@ func TestPlanLimitsValidator_Unauthorized(t *testing.T) {
 	err = validator.ValidateRecurrenceCreation(ctx)
 	assert.Equal(t, usecase.ErrUnauthorized, err)
 }
+
+func TestPlanLimitsValidator_WithoutEntitlements(t *testing.T) {
+	validator := &PlanLimitsValidator{}
+	ctx := context.WithValue(context.Background(), authentication.UserID, "user-123")
+
+	err := validator.ValidateWalletCreation(ctx)
+	assert.ErrorIs(t, err, domain.ErrInternalError)
+}
@@ -4,6 +4,7 @@ import (
 	"personal-finance/internal/infrastructure/repository"
 	"personal-finance/internal/infrastructure/repository/transaction"
 	"personal-finance/internal/plataform/authentication"
+	"personal-finance/internal/usecase"
 
 	"gorm.io/gorm"
 )
@@ -24,14 +25,27 @@ type Registry struct {
 	estimateRepository              *repository.EstimateRepository
 	deviceRepository                *repository.DeviceRepository
 	planLimitsValidator             *PlanLimitsValidator
+	aiUsageRepository               *repository.AIUsageRepository
+	aiQuotaOverrideRepository       *repository.AIQuotaOverrideRepository
+	aiQuota                         *usecase.AIQuota
+	entitlements                    *usecase.Entitlements
+	agentDataControls               *usecase.AgentDataControls
 	agentMemoryRepository           *repository.AgentMemoryRepository
 	agentConversationRepository     *repository.AgentConversationRepository
 	agentAuditRepository            *repository.AgentAuditRepository
+	agentActionRepository           *repository.AgentActionRepository
 	agentFinancialRepository        *repository.AgentFinancialRepository
+	insightPreferenceRepository     *repository.AgentInsightPreferenceRepository
+	agentSettingsRepository         *repository.AgentSettingsRepository
+	webhookInboxRepository          *repository.WebhookInboxRepository
 	subscriptionPlanRepository      *repository.SubscriptionPlanRepository
 	subscriptionRepository          *repository.SubscriptionRepository
 	couponRepository                *repository.CouponRepository
 	couponRedemptionRepository      *repository.CouponRedemptionRepository
+	couponCampaignRepository        *repository.CouponCampaignRepository
+	referralRepository              *repository.ReferralRepository
+	personalAccessTokenRepository   *repository.PersonalAccessTokenRepository
+	householdRepository             *repository.HouseholdRepository
 }
 
 func NewRegistry(db *gorm.DB) *Registry {
@@ -143,11 +157,59 @@ func (r *Registry) GetPlanLimitsValidator() *PlanLimitsValidator {
 			r.GetCreditCardRepository(),
 			r.GetMovementRepository(),
 			r.GetRecurrentMovementRepository(),
+			r.GetEntitlements(),
 		)
 	}
 	return r.planLimitsValidator
 }
 
+func (r *Registry) GetAIUsageRepository() *repository.AIUsageRepository {
+	if r.aiUsageRepository == nil {
+		r.aiUsageRepository = repository.NewAIUsageRepository(r.db)
+	}
+	return r.aiUsageRepository
+}
+
+func (r *Registry) GetAIQuotaOverrideRepository() *repository.AIQuotaOverrideRepository {
+	if r.aiQuotaOverrideRepository == nil {
+		r.aiQuotaOverrideRepository = repository.NewAIQuotaOverrideRepository(r.db)
+	}
+	return r.aiQuotaOverrideRepository
+}
+
+// GetAIQuota is shared by every AI feature so they draw from the same monthly quota.
+func (r *Registry) GetAIQuota() *usecase.AIQuota {
+	if r.aiQuota == nil {
+		r.aiQuota = usecase.NewAIQuota(r.GetAIUsageRepository(), r.GetAIQuotaOverrideRepository(), r.GetEntitlements())
+	}
+	return r.aiQuota
+}
+
+// GetEntitlements is shared by the plan limits, the AI quota and the feature
+// gates so they resolve the plan the same way.
+func (r *Registry) GetEntitlements() *usecase.Entitlements {
+	if r.entitlements == nil {
+		r.entitlements = usecase.NewEntitlements(r.GetSubscriptionPlanRepository(), r.GetSubscriptionRepository())
+	}
+	return r.entitlements
+}
+
+// GetAgentDataControls is shared by the agent routes, the data export and the
+// account deletion.
+func (r *Registry) GetAgentDataControls() *usecase.AgentDataControls {
+	if r.agentDataControls == nil {
+		r.agentDataControls = usecase.NewAgentDataControls(
+			r.GetAgentMemoryRepository(),
+			r.GetAgentConversationRepository(),
+			r.GetAgentAuditRepository(),
+			r.GetAgentActionRepository(),
+			r.GetAgentInsightPreferenceRepository(),
+			r.GetAgentSettingsRepository(),
+		)
+	}
+	return r.agentDataControls
+}
+
 func (r *Registry) GetAgentMemoryRepository() *repository.AgentMemoryRepository {
 	if r.agentMemoryRepository == nil {
 		r.agentMemoryRepository = repository.NewAgentMemoryRepository(r.db)
@@ -169,6 +231,13 @@ func (r *Registry) GetAgentAuditRepository() *repository.AgentAuditRepository {
 	return r.agentAuditRepository
 }
 
+func (r *Registry) GetAgentActionRepository() *repository.AgentActionRepository {
+	if r.agentActionRepository == nil {
+		r.agentActionRepository = repository.NewAgentActionRepository(r.db)
+	}
+	return r.agentActionRepository
+}
+
 func (r *Registry) GetAgentFinancialRepository() *repository.AgentFinancialRepository {
 	if r.agentFinancialRepository == nil {
 		r.agentFinancialRepository = repository.NewAgentFinancialRepository(r.db)
@@ -176,6 +245,27 @@ func (r *Registry) GetAgentFinancialRepository() *repository.AgentFinancialRepos
 	return r.agentFinancialRepository
 }
 
+func (r *Registry) GetAgentInsightPreferenceRepository() *repository.AgentInsightPreferenceRepository {
+	if r.insightPreferenceRepository == nil {
+		r.insightPreferenceRepository = repository.NewAgentInsightPreferenceRepository(r.db)
+	}
+	return r.insightPreferenceRepository
+}
+
+func (r *Registry) GetAgentSettingsRepository() *repository.AgentSettingsRepository {
+	if r.agentSettingsRepository == nil {
+		r.agentSettingsRepository = repository.NewAgentSettingsRepository(r.db)
+	}
+	return r.agentSettingsRepository
+}
+
+func (r *Registry) GetWebhookInboxRepository() *repository.WebhookInboxRepository {
+	if r.webhookInboxRepository == nil {
+		r.webhookInboxRepository = repository.NewWebhookInboxRepository(r.db)
+	}
+	return r.webhookInboxRepository
+}
+
 func (r *Registry) GetSubscriptionPlanRepository() *repository.SubscriptionPlanRepository {
 	if r.subscriptionPlanRepository == nil {
 		r.subscriptionPlanRepository = repository.NewSubscriptionPlanRepository(r.db)
@@ -204,3 +294,30 @@ func (r *Registry) GetCouponRedemptionRepository() *repository.CouponRedemptionR
 	return r.couponRedemptionRepository
 }
 
+func (r *Registry) GetCouponCampaignRepository() *repository.CouponCampaignRepository {
+	if r.couponCampaignRepository == nil {
+		r.couponCampaignRepository = repository.NewCouponCampaignRepository(r.db)
+	}
+	return r.couponCampaignRepository
+}
+
+func (r *Registry) GetReferralRepository() *repository.ReferralRepository {
+	if r.referralRepository == nil {
+		r.referralRepository = repository.NewReferralRepository(r.db)
+	}
+	return r.referralRepository
+}
+
+func (r *Registry) GetPersonalAccessTokenRepository() *repository.PersonalAccessTokenRepository {
+	if r.personalAccessTokenRepository == nil {
+		r.personalAccessTokenRepository = repository.NewPersonalAccessTokenRepository(r.db)
+	}
+	return r.personalAccessTokenRepository
+}
+
+func (r *Registry) GetHouseholdRepository() *repository.HouseholdRepository {
+	if r.householdRepository == nil {
+		r.householdRepository = repository.NewHouseholdRepository(r.db)
+	}
+	return r.householdRepository
+}
@@ -1,6 +1,7 @@
 package bootstrap
 
 import (
+	"personal-finance/internal/bootstrap/accesstoken"
 	"personal-finance/internal/bootstrap/admin"
 	"personal-finance/internal/bootstrap/agent"
 	"personal-finance/internal/bootstrap/balance"
@@ -11,10 +12,12 @@ import (
 	"personal-finance/internal/bootstrap/device"
 	"personal-finance/internal/bootstrap/estimate"
 	"personal-finance/internal/bootstrap/export"
+	"personal-finance/internal/bootstrap/household"
 	"personal-finance/internal/bootstrap/invoice"
 	"personal-finance/internal/bootstrap/limits"
 	"personal-finance/internal/bootstrap/movement"
 	"personal-finance/internal/bootstrap/pushnotifications"
+	"personal-finance/internal/bootstrap/referral"
 	"personal-finance/internal/bootstrap/registry"
 	"personal-finance/internal/bootstrap/statement"
 	"personal-finance/internal/bootstrap/subcategory"
@@ -30,14 +33,18 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupInternalJobs(r *gin.Engine, db *gorm.DB) {
+func SetupInternalJobs(r *gin.Engine, db *gorm.DB, auth authentication.Authenticator) {
 	reg := registry.NewRegistry(db)
+	reg.SetAuthenticator(auth)
 
 	jobsGroup := r.Group("/jobs")
 	jobsGroup.Use(authentication.InternalAPIKeyAuth())
 
 	pushnotifications.SetupJobs(jobsGroup, reg)
 	agent.SetupJobs(jobsGroup, reg)
+	invoice.SetupJobs(jobsGroup, reg)
+	subscription.SetupJobs(jobsGroup, reg, coupon.NewUseCase(reg))
+	referral.SetupJobs(jobsGroup, reg)
 }
 
 func SetupPublicComponents(r *gin.Engine, db *gorm.DB, auth authentication.Authenticator) {
@@ -71,4 +78,7 @@ func SetupCleanArchComponents(r *gin.Engine, db *gorm.DB, auth authentication.Au
 	balance.Setup(r, reg)
 	coupon.Setup(r, reg)
 	telemetry.Setup(r, reg)
+	referral.Setup(r, reg)
+	household.Setup(r, reg)
+	accesstoken.Setup(r, reg)
 }
@@ -2,6 +2,7 @@ package statement
 
 import (
 	"personal-finance/internal/bootstrap/registry"
+	"personal-finance/internal/domain"
 	"personal-finance/internal/infrastructure/api"
 	"personal-finance/internal/infrastructure/gateway"
 	"personal-finance/internal/usecase"
@@ -25,7 +26,8 @@ func Setup(r *gin.Engine, reg *registry.Registry) {
 		categoryRepo,
 		limitsValidator,
 		pdfDecryptor,
+		reg.GetAIQuota(),
 	)
 
-	api.NewStatementHandlers(r, statementUseCase)
+	api.NewStatementHandlers(r, statementUseCase, api.RequireFeature(reg.GetEntitlements(), domain.FeatureStatementImport))
 }
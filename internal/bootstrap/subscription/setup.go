@@ -1,9 +1,12 @@
 package subscription
 
 import (
+	"personal-finance/internal/bootstrap/referral"
 	"personal-finance/internal/bootstrap/registry"
 	"personal-finance/internal/infrastructure/api"
 	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/infrastructure/push"
+	"personal-finance/internal/plataform/authentication"
 	"personal-finance/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -11,16 +14,38 @@ import (
 
 func Setup(r *gin.Engine, registry *registry.Registry, couponUseCase usecase.CouponCheckoutUseCase) {
 	authenticator := registry.GetAuthenticator()
-	authClient := authenticator.AuthClient()
 
-	firebaseGateway := gateway.NewFirebaseGateway(authClient)
+	// Webhooks are verified and stored; the inbox job applies them.
+	subscriptionUseCase := newSubscriptionUseCase(registry, couponUseCase).
+		WithWebhookInbox(NewWebhookInbox(registry, couponUseCase))
+
+	api.NewSubscriptionHandlers(r, subscriptionUseCase, authenticator.Authenticate())
+	api.RegisterSubscriptionReturnRoute(r)
+}
+
+func SetupJobs(jobsGroup *gin.RouterGroup, registry *registry.Registry, couponUseCase usecase.CouponCheckoutUseCase) {
+	api.NewWebhookInboxJobHandlers(jobsGroup, NewWebhookInbox(registry, couponUseCase))
+
+	firebaseGateway := gateway.NewFirebaseGateway(registry.GetAuthenticator().ClaimsStore())
+	reconciliation := usecase.NewEntitlementReconciliation(registry.GetSubscriptionRepository(), firebaseGateway, authentication.GetPastDueGrace())
+	api.NewEntitlementReconciliationJobHandlers(jobsGroup, reconciliation)
+}
+
+// NewWebhookInbox builds the inbox of the billing webhooks. Used by the webhook
+// routes, the processing job and the admin dead-letter routes.
+func NewWebhookInbox(registry *registry.Registry, couponUseCase usecase.CouponCheckoutUseCase) *usecase.WebhookInbox {
+	return usecase.NewWebhookInbox(registry.GetWebhookInboxRepository(), newSubscriptionUseCase(registry, couponUseCase))
+}
+
+func newSubscriptionUseCase(registry *registry.Registry, couponUseCase usecase.CouponCheckoutUseCase) *usecase.Subscription {
+	firebaseGateway := gateway.NewFirebaseGateway(registry.GetAuthenticator().ClaimsStore())
 	mpGateway := gateway.NewMercadoPagoGateway()
 	stripeGateway := gateway.NewStripeGateway()
 	planRepo := registry.GetSubscriptionPlanRepository()
 	subRepo := registry.GetSubscriptionRepository()
+	notifications := usecase.NewBillingNotifications(registry.GetDeviceRepository(), push.NewExpoClient())
 
-	subscriptionUseCase := usecase.NewSubscription(mpGateway, stripeGateway, firebaseGateway, planRepo, subRepo, couponUseCase)
-
-	api.NewSubscriptionHandlers(r, subscriptionUseCase, authenticator.Authenticate())
-	api.RegisterSubscriptionReturnRoute(r)
+	return usecase.NewSubscription(mpGateway, stripeGateway, firebaseGateway, planRepo, subRepo, couponUseCase).
+		WithBillingNotifier(notifications).
+		WithReferrals(referral.NewUseCase(registry))
 }
@@ -10,13 +10,17 @@ import (
 
 func Setup(r *gin.Engine, registry *registry.Registry) {
 	movementRepo := registry.GetMovementRepository()
+	recurrentRepo := registry.GetRecurrentMovementRepository()
 	walletRepo := registry.GetWalletRepository()
 	txManager := registry.GetTransactionManager()
+	limitsValidator := registry.GetPlanLimitsValidator()
 
 	transferService := usecase.NewTransfer(
 		movementRepo,
+		recurrentRepo,
 		walletRepo,
 		txManager,
+		limitsValidator,
 	)
 
 	api.NewTransferHandlers(r, &transferService)
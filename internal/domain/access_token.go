@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessToken lets a user call the API from scripts. Only the hash of
+// the token is stored; the token itself is shown once, when created.
+type PersonalAccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     string     `json:"-"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	Prefix     string     `json:"prefix"` // start of the token, to tell tokens apart
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the token still authenticates at now.
+func (t PersonalAccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+var (
+	ErrAccessTokenNotFound     = errors.New("access token not found")
+	ErrAccessTokenInvalid      = errors.New("access token is invalid, expired or revoked")
+	ErrAccessTokenLimitReached = errors.New("active access token limit reached")
+)
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -104,6 +105,10 @@ type AgentConversation struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	ExpiresAt time.Time      `json:"expires_at"`
+	// Summary condenses the first SummarizedMessages messages, which are no
+	// longer sent to the model.
+	Summary            string `json:"summary,omitempty"`
+	SummarizedMessages int    `json:"summarized_messages,omitempty"`
 }
 
 func NewAgentConversation(userID string) AgentConversation {
@@ -117,6 +122,14 @@ func NewAgentConversation(userID string) AgentConversation {
 	}
 }
 
+// ActiveMessages returns the messages not yet folded into the summary.
+func (c AgentConversation) ActiveMessages() []AgentMessage {
+	if c.SummarizedMessages >= len(c.Messages) {
+		return nil
+	}
+	return c.Messages[c.SummarizedMessages:]
+}
+
 // --- Agent Message ---
 
 type AgentMessage struct {
@@ -146,6 +159,10 @@ type AgentAuditRecord struct {
 	ToolsCalled    []string   `json:"tools_called,omitempty"`
 	InputTokens    int        `json:"input_tokens"`
 	OutputTokens   int        `json:"output_tokens"`
+	ActionID       *uuid.UUID `json:"action_id,omitempty"`
+	ActionType     string     `json:"action_type,omitempty"`
+	ActionStatus   string     `json:"action_status,omitempty"`
+	ActionError    string     `json:"action_error,omitempty"`
 	Provider       string     `json:"provider"`
 	Region         string     `json:"region"`
 	CreatedAt      time.Time  `json:"created_at"`
@@ -158,11 +175,54 @@ type AgentGatewayResponse struct {
 	ToolsCalled []string `json:"tools_called,omitempty"`
 	InputTokens  int     `json:"input_tokens"`
 	OutputTokens int     `json:"output_tokens"`
+	// ProposedActions are write actions the agent wants to run; they wait for user confirmation.
+	ProposedActions []AgentAction `json:"proposed_actions,omitempty"`
+	// Provider and Region identify where the model ran, for the audit record.
+	Provider string `json:"provider"`
+	Region   string `json:"region"`
 }
 
+// --- Agent Stream Events ---
+
+type AgentStreamEventType string
+
+const (
+	AgentStreamEventDelta     AgentStreamEventType = "delta"
+	AgentStreamEventToolStart AgentStreamEventType = "tool_start"
+	AgentStreamEventToolEnd   AgentStreamEventType = "tool_end"
+	AgentStreamEventDone      AgentStreamEventType = "done"
+	AgentStreamEventError     AgentStreamEventType = "error"
+)
+
+// AgentStreamEvent is a single step of a streamed agent response. Only the fields
+// relevant to the event type are filled.
+type AgentStreamEvent struct {
+	Type           AgentStreamEventType `json:"type"`
+	Text           string               `json:"text,omitempty"`
+	Tool           string               `json:"tool,omitempty"`
+	ConversationID *uuid.UUID           `json:"conversation_id,omitempty"`
+	InputTokens    int                  `json:"input_tokens,omitempty"`
+	OutputTokens   int                  `json:"output_tokens,omitempty"`
+	PendingActions []AgentAction        `json:"pending_actions,omitempty"`
+}
+
+// AgentStreamEmitter receives stream events in the order they are produced.
+type AgentStreamEmitter func(event AgentStreamEvent)
+
 // --- Interfaces (Ports) ---
 
 const (
 	MaxMemoriesPerUser    = 50
 	MaxMemoriesPerPrompt  = 15
 )
+
+var (
+	cpfRegex   = regexp.MustCompile(`\d{3}\.?\d{3}\.?\d{3}-?\d{2}`)
+	emailRegex = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
+)
+
+// ContainsPII reports whether the content has a CPF or an e-mail address, which
+// must never be stored as an agent memory.
+func ContainsPII(content string) bool {
+	return cpfRegex.MatchString(content) || emailRegex.MatchString(content)
+}
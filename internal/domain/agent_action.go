@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AgentActionTTL is how long a proposal waits for the user before it can no longer be confirmed.
+const AgentActionTTL = 24 * time.Hour
+
+// --- Agent Action Types ---
+
+type AgentActionType string
+
+const (
+	AgentActionCreateMovement AgentActionType = "create_movement"
+	AgentActionPayMovement    AgentActionType = "pay_movement"
+	AgentActionCreateEstimate AgentActionType = "create_estimate"
+	AgentActionCreateTransfer AgentActionType = "create_transfer"
+)
+
+func (t AgentActionType) IsValid() bool {
+	switch t {
+	case AgentActionCreateMovement, AgentActionPayMovement,
+		AgentActionCreateEstimate, AgentActionCreateTransfer:
+		return true
+	}
+	return false
+}
+
+type AgentActionStatus string
+
+const (
+	AgentActionStatusPending   AgentActionStatus = "pending"
+	AgentActionStatusExecuting AgentActionStatus = "executing"
+	AgentActionStatusExecuted  AgentActionStatus = "executed"
+	AgentActionStatusRejected  AgentActionStatus = "rejected"
+	AgentActionStatusFailed    AgentActionStatus = "failed"
+	AgentActionStatusExpired   AgentActionStatus = "expired"
+)
+
+// --- Agent Action ---
+
+// AgentAction is a write operation proposed by the agent. It only runs after the
+// user confirms it; Params holds the JSON arguments of the matching *Params type.
+type AgentAction struct {
+	ID             uuid.UUID         `json:"id"`
+	UserID         string            `json:"user_id"`
+	ConversationID *uuid.UUID        `json:"conversation_id,omitempty"`
+	Type           AgentActionType   `json:"action_type"`
+	Summary        string            `json:"summary"`
+	Params         json.RawMessage   `json:"params"`
+	Status         AgentActionStatus `json:"status"`
+	ResultID       *uuid.UUID        `json:"result_id,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	ExpiresAt      time.Time         `json:"expires_at"`
+	ResolvedAt     *time.Time        `json:"resolved_at,omitempty"`
+	// Provider and Region of the LLM that proposed the action, for the audit log
+	// written when it is confirmed.
+	Provider string `json:"-"`
+	Region   string `json:"-"`
+}
+
+// NewAgentAction builds a pending proposal, validating params against the action type.
+func NewAgentAction(userID string, actionType AgentActionType, summary string, params any) (AgentAction, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return AgentAction{}, WrapInvalidInput(err, "invalid action params")
+	}
+
+	now := time.Now()
+	action := AgentAction{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      actionType,
+		Summary:   summary,
+		Params:    raw,
+		Status:    AgentActionStatusPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(AgentActionTTL),
+	}
+
+	if err := action.Validate(); err != nil {
+		return AgentAction{}, err
+	}
+	return action, nil
+}
+
+func (a AgentAction) IsPending() bool {
+	return a.Status == AgentActionStatusPending
+}
+
+func (a AgentAction) IsExpired(now time.Time) bool {
+	return now.After(a.ExpiresAt)
+}
+
+// Resolve records the final status of the action and the outcome of its execution.
+func (a *AgentAction) Resolve(status AgentActionStatus, resultID *uuid.UUID, err error) {
+	now := time.Now()
+	a.Status = status
+	a.ResultID = resultID
+	a.ResolvedAt = &now
+	if err != nil {
+		a.Error = err.Error()
+	}
+}
+
+// Validate decodes Params for the action type and checks the required fields.
+func (a AgentAction) Validate() error {
+	var v interface{ validate() error }
+	switch a.Type {
+	case AgentActionCreateMovement:
+		v = &AgentCreateMovementParams{}
+	case AgentActionPayMovement:
+		v = &AgentPayMovementParams{}
+	case AgentActionCreateEstimate:
+		v = &AgentCreateEstimateParams{}
+	case AgentActionCreateTransfer:
+		v = &AgentCreateTransferParams{}
+	default:
+		return fmt.Errorf("%w: unknown action type %q", ErrAgentInvalidAction, a.Type)
+	}
+
+	if err := json.Unmarshal(a.Params, v); err != nil {
+		return fmt.Errorf("%w: %s", ErrAgentInvalidAction, err.Error())
+	}
+	if err := v.validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrAgentInvalidAction, err.Error())
+	}
+	return nil
+}
+
+// DecodeParams unmarshals Params into the *Params type of the action.
+func (a AgentAction) DecodeParams(target any) error {
+	if err := json.Unmarshal(a.Params, target); err != nil {
+		return fmt.Errorf("%w: %s", ErrAgentInvalidAction, err.Error())
+	}
+	return nil
+}
+
+// --- Action Params ---
+// IDs and dates are strings so the model can fill them straight from the read tools.
+
+type AgentCreateMovementParams struct {
+	Description   string  `json:"description"`
+	Amount        float64 `json:"amount"`
+	Date          string  `json:"date"`
+	WalletID      string  `json:"wallet_id"`
+	CategoryID    string  `json:"category_id,omitempty"`
+	SubCategoryID string  `json:"sub_category_id,omitempty"`
+	IsPaid        bool    `json:"is_paid"`
+}
+
+type AgentPayMovementParams struct {
+	MovementID string `json:"movement_id"`
+	Date       string `json:"date,omitempty"`
+}
+
+type AgentCreateEstimateParams struct {
+	CategoryID string  `json:"category_id"`
+	Month      int     `json:"month"`
+	Year       int     `json:"year"`
+	Amount     float64 `json:"amount"`
+}
+
+type AgentCreateTransferParams struct {
+	OriginWalletID      string  `json:"origin_wallet_id"`
+	DestinationWalletID string  `json:"destination_wallet_id"`
+	Amount              float64 `json:"amount"`
+	Date                string  `json:"date"`
+	Description         string  `json:"description,omitempty"`
+	IsPaid              bool    `json:"is_paid"`
+}
+
+func (p AgentCreateMovementParams) validate() error {
+	if p.Description == "" {
+		return errors.New("description is required")
+	}
+	if p.Amount == 0 {
+		return errors.New("amount must not be zero")
+	}
+	if _, err := ParseAgentDate(p.Date); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(p.WalletID); err != nil {
+		return errors.New("wallet_id must be a valid UUID")
+	}
+	return validateOptionalUUIDs(map[string]string{"category_id": p.CategoryID, "sub_category_id": p.SubCategoryID})
+}
+
+func (p AgentPayMovementParams) validate() error {
+	if _, err := uuid.Parse(p.MovementID); err != nil {
+		return errors.New("movement_id must be a valid UUID")
+	}
+	if p.Date != "" {
+		if _, err := ParseAgentDate(p.Date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p AgentCreateEstimateParams) validate() error {
+	if _, err := uuid.Parse(p.CategoryID); err != nil {
+		return errors.New("category_id must be a valid UUID")
+	}
+	if p.Month < 1 || p.Month > 12 {
+		return errors.New("month must be between 1 and 12")
+	}
+	if p.Year < 2000 {
+		return errors.New("year is invalid")
+	}
+	return nil
+}
+
+func (p AgentCreateTransferParams) validate() error {
+	if p.Amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+	if _, err := ParseAgentDate(p.Date); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(p.OriginWalletID); err != nil {
+		return errors.New("origin_wallet_id must be a valid UUID")
+	}
+	if _, err := uuid.Parse(p.DestinationWalletID); err != nil {
+		return errors.New("destination_wallet_id must be a valid UUID")
+	}
+	return nil
+}
+
+// ParseAgentDate parses the YYYY-MM-DD dates used in action params.
+func ParseAgentDate(value string) (time.Time, error) {
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date must be in YYYY-MM-DD format: %q", value)
+	}
+	return date, nil
+}
+
+// ParseOptionalAgentUUID returns nil for an empty value.
+func ParseOptionalAgentUUID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func validateOptionalUUIDs(fields map[string]string) error {
+	for name, value := range fields {
+		if _, err := ParseOptionalAgentUUID(value); err != nil {
+			return fmt.Errorf("%s must be a valid UUID", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAgentAction(t *testing.T) {
+	walletID := uuid.NewString()
+
+	tests := map[string]struct {
+		actionType AgentActionType
+		params     any
+		wantErr    bool
+	}{
+		"valid movement": {
+			actionType: AgentActionCreateMovement,
+			params:     AgentCreateMovementParams{Description: "Almoço", Amount: -45, Date: "2025-03-10", WalletID: walletID},
+		},
+		"movement with invalid date": {
+			actionType: AgentActionCreateMovement,
+			params:     AgentCreateMovementParams{Description: "Almoço", Amount: -45, Date: "10/03/2025", WalletID: walletID},
+			wantErr:    true,
+		},
+		"movement with invalid category": {
+			actionType: AgentActionCreateMovement,
+			params:     AgentCreateMovementParams{Description: "Almoço", Amount: -45, Date: "2025-03-10", WalletID: walletID, CategoryID: "alimentação"},
+			wantErr:    true,
+		},
+		"estimate with invalid month": {
+			actionType: AgentActionCreateEstimate,
+			params:     AgentCreateEstimateParams{CategoryID: uuid.NewString(), Month: 13, Year: 2025, Amount: -1200},
+			wantErr:    true,
+		},
+		"transfer with negative amount": {
+			actionType: AgentActionCreateTransfer,
+			params:     AgentCreateTransferParams{OriginWalletID: walletID, DestinationWalletID: uuid.NewString(), Amount: -10, Date: "2025-03-10"},
+			wantErr:    true,
+		},
+		"unknown type": {
+			actionType: AgentActionType("delete_wallet"),
+			params:     struct{}{},
+			wantErr:    true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			action, err := NewAgentAction("user-1", tt.actionType, "Confirma?", tt.params)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrAgentInvalidAction)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, action.IsPending())
+			assert.Equal(t, action.CreatedAt.Add(AgentActionTTL), action.ExpiresAt)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AgentSettings are the choices of a user about what the agent may keep.
+type AgentSettings struct {
+	UserID string `json:"user_id"`
+	// MemoryEnabled lets the agent save and update memories during a chat. The
+	// memories already saved are still used; the user deletes them separately.
+	MemoryEnabled bool      `json:"memory_enabled"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// DefaultAgentSettings are the settings of a user who never changed them.
+func DefaultAgentSettings(userID string) AgentSettings {
+	return AgentSettings{UserID: userID, MemoryEnabled: true}
+}
+
+// AgentToolUsage is how many times a tool ran in a conversation.
+type AgentToolUsage struct {
+	Name  string `json:"name"`
+	Calls int    `json:"calls"`
+}
+
+// AgentAuditConversation sums up the audit records of one conversation: which
+// tools read or changed data, which actions were confirmed, how many tokens
+// were processed and by which provider and region.
+type AgentAuditConversation struct {
+	ConversationID   *uuid.UUID       `json:"conversation_id,omitempty"`
+	Title            string           `json:"title,omitempty"`
+	Interactions     int              `json:"interactions"`
+	Tools            []AgentToolUsage `json:"tools"`
+	ConfirmedActions []string         `json:"confirmed_actions,omitempty"`
+	FailedActions    []string         `json:"failed_actions,omitempty"`
+	InputTokens      int              `json:"input_tokens"`
+	OutputTokens     int              `json:"output_tokens"`
+	Processors       []string         `json:"processors"`
+	FirstAt          time.Time        `json:"first_at"`
+	LastAt           time.Time        `json:"last_at"`
+}
+
+// SummarizeAgentAudit groups the audit records by conversation, most recent
+// first. Records of confirmed actions count as actions, not as interactions;
+// those whose execution failed are listed apart from the executed ones.
+// titles maps the conversations that still exist to their titles.
+func SummarizeAgentAudit(records []AgentAuditRecord, titles map[uuid.UUID]string) []AgentAuditConversation {
+	type group struct {
+		summary    AgentAuditConversation
+		tools      map[string]int
+		processors map[string]bool
+	}
+
+	groups := make(map[uuid.UUID]*group)
+	order := make([]uuid.UUID, 0)
+	for _, record := range records {
+		key := uuid.Nil
+		if record.ConversationID != nil {
+			key = *record.ConversationID
+		}
+
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				summary: AgentAuditConversation{
+					ConversationID: record.ConversationID,
+					Title:          titles[key],
+					FirstAt:        record.CreatedAt,
+					LastAt:         record.CreatedAt,
+				},
+				tools:      make(map[string]int),
+				processors: make(map[string]bool),
+			}
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		s := &g.summary
+		if record.CreatedAt.Before(s.FirstAt) {
+			s.FirstAt = record.CreatedAt
+		}
+		if record.CreatedAt.After(s.LastAt) {
+			s.LastAt = record.CreatedAt
+		}
+		s.InputTokens += record.InputTokens
+		s.OutputTokens += record.OutputTokens
+		if record.Provider != "" {
+			g.processors[fmt.Sprintf("%s (%s)", record.Provider, record.Region)] = true
+		}
+
+		if record.ActionType != "" {
+			if record.ActionStatus == string(AgentActionStatusFailed) {
+				s.FailedActions = append(s.FailedActions, record.ActionType)
+			} else {
+				s.ConfirmedActions = append(s.ConfirmedActions, record.ActionType)
+			}
+			continue
+		}
+		s.Interactions++
+		for _, name := range record.ToolsCalled {
+			g.tools[name]++
+		}
+	}
+
+	summaries := make([]AgentAuditConversation, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		s := g.summary
+
+		s.Tools = make([]AgentToolUsage, 0, len(g.tools))
+		for name, calls := range g.tools {
+			s.Tools = append(s.Tools, AgentToolUsage{Name: name, Calls: calls})
+		}
+		sort.Slice(s.Tools, func(i, j int) bool {
+			if s.Tools[i].Calls != s.Tools[j].Calls {
+				return s.Tools[i].Calls > s.Tools[j].Calls
+			}
+			return s.Tools[i].Name < s.Tools[j].Name
+		})
+
+		s.Processors = make([]string, 0, len(g.processors))
+		for p := range g.processors {
+			s.Processors = append(s.Processors, p)
+		}
+		sort.Strings(s.Processors)
+
+		summaries = append(summaries, s)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].LastAt.After(summaries[j].LastAt)
+	})
+	return summaries
+}
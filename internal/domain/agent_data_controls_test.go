@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeAgentAudit(t *testing.T) {
+	base := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	older, newer := uuid.New(), uuid.New()
+
+	records := []AgentAuditRecord{
+		{ConversationID: &older, ToolsCalled: []string{"get_financial_overview", "save_memory"}, InputTokens: 100, OutputTokens: 10, Provider: "vertex_ai", Region: "southamerica-east1", CreatedAt: base},
+		{ConversationID: &newer, ToolsCalled: []string{"get_movements"}, InputTokens: 50, OutputTokens: 5, Provider: "openai_compatible", Region: "local", CreatedAt: base.Add(time.Hour)},
+		{ConversationID: &older, ToolsCalled: []string{"get_financial_overview"}, InputTokens: 200, OutputTokens: 20, Provider: "vertex_ai", Region: "southamerica-east1", CreatedAt: base.Add(2 * time.Hour)},
+		{ConversationID: &older, ToolsCalled: []string{"create_movement"}, ActionType: "create_movement", ActionStatus: "executed", CreatedAt: base.Add(3 * time.Hour)},
+		{ConversationID: &older, ToolsCalled: []string{"create_transfer"}, ActionType: "create_transfer", ActionStatus: "failed", ActionError: "limit reached", CreatedAt: base.Add(2 * time.Hour)},
+	}
+
+	summaries := SummarizeAgentAudit(records, map[uuid.UUID]string{older: "Gastos de março"})
+
+	require.Len(t, summaries, 2)
+	first := summaries[0]
+	assert.Equal(t, older, *first.ConversationID, "most recent activity first")
+	assert.Equal(t, "Gastos de março", first.Title)
+	assert.Equal(t, 2, first.Interactions)
+	assert.Equal(t, []AgentToolUsage{{Name: "get_financial_overview", Calls: 2}, {Name: "save_memory", Calls: 1}}, first.Tools)
+	assert.Equal(t, []string{"create_movement"}, first.ConfirmedActions)
+	assert.Equal(t, []string{"create_transfer"}, first.FailedActions, "failed executions are audited apart")
+	assert.Equal(t, 300, first.InputTokens)
+	assert.Equal(t, 30, first.OutputTokens)
+	assert.Equal(t, []string{"vertex_ai (southamerica-east1)"}, first.Processors)
+	assert.Equal(t, base, first.FirstAt)
+	assert.Equal(t, base.Add(3*time.Hour), first.LastAt)
+
+	assert.Empty(t, summaries[1].Title, "deleted conversations keep their audit without a title")
+	assert.Equal(t, 1, summaries[1].Interactions)
+}
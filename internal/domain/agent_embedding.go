@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"math"
+)
+
+// MemorySearchLimit is the number of memories returned by a similarity search.
+const MemorySearchLimit = 10
+
+// EmbeddingProvider turns texts into vectors for semantic memory search. Name
+// identifies the model, so vectors from another model are recomputed instead of
+// being compared.
+type EmbeddingProvider interface {
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// ScoredMemory is a memory with its similarity to the current query, in [-1, 1].
+// Similarity is zero when no query or embedding was available.
+type ScoredMemory struct {
+	Memory     AgentMemory
+	Similarity float64
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or zero
+// when they have different sizes or one of them is empty.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
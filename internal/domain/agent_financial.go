@@ -2,6 +2,7 @@ package domain
 
 // AgentWalletItem is a minimal wallet representation for the agent.
 type AgentWalletItem struct {
+	ID      string  `json:"id"`
 	Name    string  `json:"name"`
 	Balance float64 `json:"balance"`
 }
@@ -17,6 +18,7 @@ type AgentFinancialOverview struct {
 
 // AgentCategoryItem is a minimal category breakdown item for the agent.
 type AgentCategoryItem struct {
+	ID       string  `json:"id,omitempty"`
 	Name     string  `json:"name"`
 	Amount   float64 `json:"amount"`
 	Pct      float64 `json:"pct"`
@@ -48,9 +50,11 @@ type AgentCreditCardsSummary struct {
 
 // AgentMovementItem is a minimal movement representation for the agent.
 type AgentMovementItem struct {
+	ID          string  `json:"id"`
 	Date        string  `json:"date"`
 	Description string  `json:"description"`
 	Amount      float64 `json:"amount"`
+	IsPaid      bool    `json:"is_paid"`
 	Category    string  `json:"category"`
 	Wallet      string  `json:"wallet"`
 }
@@ -68,6 +72,7 @@ type AgentRecurringItem struct {
 	Amount      float64 `json:"amount"`
 	Category    string  `json:"category"`
 	Day         int     `json:"day"`
+	StartDate   string  `json:"start_date"`
 }
 
 // AgentRecurringSummary is the response for get_recurring_expenses tool.
@@ -76,8 +81,31 @@ type AgentRecurringSummary struct {
 	Items        []AgentRecurringItem `json:"items"`
 }
 
+// AgentRecurringChargeItem is a repeated expense not registered as recurring,
+// as detected from the movement history.
+type AgentRecurringChargeItem struct {
+	Description       string  `json:"description"`
+	Period            string  `json:"period"`
+	Amount            float64 `json:"amount"`
+	MonthlyAmount     float64 `json:"monthly_amount"`
+	Occurrences       int     `json:"occurrences"`
+	LastDate          string  `json:"last_date"`
+	NextExpectedDate  string  `json:"next_expected_date"`
+	TypePayment       string  `json:"type_payment"`
+	PossiblyForgotten bool    `json:"possibly_forgotten"`
+	PreviousAmount    float64 `json:"previous_amount,omitempty"`
+	IncreasePct       float64 `json:"increase_pct,omitempty"`
+}
+
+// AgentRecurringCharges is the response for find_recurring_charges tool.
+type AgentRecurringCharges struct {
+	TotalMonthly float64                    `json:"total_monthly"`
+	Items        []AgentRecurringChargeItem `json:"items"`
+}
+
 // AgentBudgetItem is a minimal budget vs actual item for the agent.
 type AgentBudgetItem struct {
+	CategoryID  string  `json:"category_id"`
 	Name        string  `json:"name"`
 	Estimated   float64 `json:"estimated"`
 	Actual      float64 `json:"actual"`
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// InsightKind identifies the signal behind a weekly insight.
+type InsightKind string
+
+const (
+	InsightSpendingSpike   InsightKind = "spending_spike"
+	InsightNewRecurring    InsightKind = "new_recurring"
+	InsightBudgetPace      InsightKind = "budget_pace"
+	InsightUpcomingInvoice InsightKind = "upcoming_invoice"
+)
+
+// Insight is a deterministic signal computed from the user finances. Subject is
+// the category, card or recurring charge it refers to.
+type Insight struct {
+	Kind    InsightKind `json:"kind"`
+	Subject string      `json:"subject"`
+	Message string      `json:"message"`
+	Amount  float64     `json:"amount"`
+}
+
+// InsightPreference is the opt-in of a user to the weekly insights digest.
+type InsightPreference struct {
+	UserID       string     `json:"user_id"`
+	Enabled      bool       `json:"enabled"`
+	LastDigestAt *time.Time `json:"last_digest_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+)
+
+// AIFeature identifies which feature consumed AI usage.
+type AIFeature string
+
+const (
+	AIFeatureAgent             AIFeature = "agent"
+	AIFeatureAgentInsights     AIFeature = "agent_insights"
+	AIFeatureStatementExtract  AIFeature = "statement_extract"
+	AIFeatureStatementClassify AIFeature = "statement_classify"
+)
+
+// AIUsage is the AI consumption of a user, either of a single call or
+// aggregated over a month.
+type AIUsage struct {
+	Requests     int64 `json:"requests"`
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+func (u AIUsage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens
+}
+
+func (u AIUsage) Add(other AIUsage) AIUsage {
+	return AIUsage{
+		Requests:     u.Requests + other.Requests,
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+	}
+}
+
+// AIQuotaOverride replaces the plan AI quota of a single user, granted by an
+// admin. Zero values mean unlimited, as in the plan quota.
+type AIQuotaOverride struct {
+	UserID           string     `json:"user_id"`
+	TokensPerMonth   int        `json:"tokens_per_month"`
+	RequestsPerMonth int        `json:"requests_per_month"`
+	Reason           string     `json:"reason,omitempty"`
+	GrantedBy        string     `json:"granted_by"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+func (o AIQuotaOverride) IsActive(now time.Time) bool {
+	return o.ExpiresAt == nil || now.Before(*o.ExpiresAt)
+}
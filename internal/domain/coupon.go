@@ -22,6 +22,17 @@ const (
 	CouponRedemptionCancelled CouponRedemptionStatus = "cancelled"
 )
 
+// CouponCodeStatus is the redemption status of a single-use campaign code.
+// Only available and redeemed are stored; pending means some user has a
+// pending redemption with the code, waiting for the checkout to complete.
+type CouponCodeStatus string
+
+const (
+	CouponCodeAvailable CouponCodeStatus = "available"
+	CouponCodePending   CouponCodeStatus = "pending"
+	CouponCodeRedeemed  CouponCodeStatus = "redeemed"
+)
+
 type Coupon struct {
 	ID                string
 	Code              string
@@ -44,6 +55,7 @@ type CouponRedemption struct {
 	CouponID       string
 	PlanID         string
 	SubscriptionID *uuid.UUID
+	Code           string // campaign code, empty for the shared coupon code
 	OriginalPrice  float64
 	LockedPrice    float64
 	Status         CouponRedemptionStatus
@@ -51,6 +63,45 @@ type CouponRedemption struct {
 	CancelledAt    *time.Time
 }
 
+// CouponCampaign groups single-use codes that redeem the same coupon, e.g. the
+// codes handed out by one influencer or partner.
+type CouponCampaign struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	CouponID string `json:"coupon_id"`
+	// StripeCouponID is the Stripe coupon behind the promotion code created for
+	// each code on web checkout.
+	StripeCouponID string    `json:"stripe_coupon_id,omitempty"`
+	CodePrefix     string    `json:"code_prefix,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CouponCampaignCode is one single-use code of a campaign.
+type CouponCampaignCode struct {
+	Code                  string           `json:"code"`
+	CampaignID            string           `json:"campaign_id"`
+	Status                CouponCodeStatus `json:"status"`
+	RedeemedBy            string           `json:"redeemed_by,omitempty"`
+	RedemptionID          *uuid.UUID       `json:"redemption_id,omitempty"`
+	RedeemedAt            *time.Time       `json:"redeemed_at,omitempty"`
+	StripePromotionCodeID string           `json:"stripe_promotion_code_id,omitempty"`
+	CreatedAt             time.Time        `json:"created_at"`
+	// ReservedBy holds the code for the user who applied it at checkout until
+	// ReservedUntil, so no one else can apply it meanwhile.
+	ReservedBy    string     `json:"-"`
+	ReservedUntil *time.Time `json:"-"`
+}
+
+// CouponCodeReservation is how long applying a campaign code at checkout holds
+// it for the user; a Stripe checkout session expires within a day.
+const CouponCodeReservation = 24 * time.Hour
+
+// ReservedByOther tells whether another user holds the code at now.
+func (c CouponCampaignCode) ReservedByOther(userID string, now time.Time) bool {
+	return c.ReservedBy != "" && c.ReservedBy != userID &&
+		c.ReservedUntil != nil && c.ReservedUntil.After(now)
+}
+
 var (
 	ErrCouponNotFound          = errors.New("coupon not found")
 	ErrCouponInactive          = errors.New("coupon is not active")
@@ -61,4 +112,7 @@ var (
 	ErrCouponInvalidPrice      = errors.New("coupon would result in non-positive price")
 	ErrCouponRedemptionMissing = errors.New("coupon redemption not found")
 	ErrCouponNotOnStripe       = errors.New("coupon is not available for web checkout")
+	ErrCouponCodeAlreadyUsed   = errors.New("coupon code already used")
+	ErrCouponCodeNotFound      = errors.New("coupon code not found")
+	ErrCouponCampaignNotFound  = errors.New("coupon campaign not found")
 )
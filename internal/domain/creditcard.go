@@ -15,6 +15,7 @@ type CreditCard struct {
 	Color           string     `json:"color,omitempty"`
 	DefaultWalletID *uuid.UUID `json:"default_wallet_id"`
 	DefaultWallet   Wallet     `json:"wallets,omitempty"`
+	AutoDebit       bool       `json:"auto_debit"`
 	UserID          string     `json:"user_id"`
 	DateCreate      time.Time  `json:"date_create"`
 	DateUpdate      time.Time  `json:"date_update"`
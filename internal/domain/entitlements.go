@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// FreePlanID is the plan whose entitlements apply to users without a paid plan.
+// It is never sold.
+const FreePlanID = "free"
+
+type Feature string
+
+const (
+	FeatureAgentChat       Feature = "agent_chat"
+	FeatureStatementImport Feature = "statement_import"
+)
+
+// Features lists every feature a plan can turn off.
+var Features = []Feature{FeatureAgentChat, FeatureStatementImport}
+
+type LimitName string
+
+const (
+	LimitWallets             LimitName = "wallets"
+	LimitCreditCards         LimitName = "credit_cards"
+	LimitMovementsPerMonth   LimitName = "movements_per_month"
+	LimitRecurrencesPerMonth LimitName = "recurrences_per_month"
+	LimitAITokensPerMonth    LimitName = "ai_tokens_per_month"
+	LimitAIRequestsPerMonth  LimitName = "ai_requests_per_month"
+)
+
+// LimitNames lists every limit a plan can set.
+var LimitNames = []LimitName{
+	LimitWallets,
+	LimitCreditCards,
+	LimitMovementsPerMonth,
+	LimitRecurrencesPerMonth,
+	LimitAITokensPerMonth,
+	LimitAIRequestsPerMonth,
+}
+
+// Entitlements is what a plan allows. A limit missing from Limits is unlimited
+// and a feature missing from Features is enabled.
+type Entitlements struct {
+	Limits   map[LimitName]int `json:"limits"`
+	Features map[Feature]bool  `json:"features"`
+}
+
+// Limit returns the limit and false when it is unlimited.
+func (e Entitlements) Limit(name LimitName) (int, bool) {
+	limit, ok := e.Limits[name]
+	return limit, ok
+}
+
+func (e Entitlements) HasFeature(feature Feature) bool {
+	enabled, ok := e.Features[feature]
+	return !ok || enabled
+}
+
+// Validate rejects unknown limits and features and limits that are not
+// positive; a feature is turned off instead of given a zero limit.
+func (e Entitlements) Validate() error {
+	for name, limit := range e.Limits {
+		if !slices.Contains(LimitNames, name) {
+			return WrapInvalidInput(New(fmt.Sprintf("unknown limit %q", name)), "validate entitlements")
+		}
+		if limit <= 0 {
+			return WrapInvalidInput(New(fmt.Sprintf("limit %q must be positive", name)), "validate entitlements")
+		}
+	}
+	for feature := range e.Features {
+		if !slices.Contains(Features, feature) {
+			return WrapInvalidInput(New(fmt.Sprintf("unknown feature %q", feature)), "validate entitlements")
+		}
+	}
+	return nil
+}
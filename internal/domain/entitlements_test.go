@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntitlements(t *testing.T) {
+	ent := Entitlements{
+		Limits:   map[LimitName]int{LimitWallets: 2},
+		Features: map[Feature]bool{FeatureAgentChat: false},
+	}
+
+	t.Run("should treat a missing limit as unlimited", func(t *testing.T) {
+		limit, ok := ent.Limit(LimitWallets)
+		assert.True(t, ok)
+		assert.Equal(t, 2, limit)
+
+		_, ok = ent.Limit(LimitCreditCards)
+		assert.False(t, ok)
+	})
+
+	t.Run("should treat a missing feature as enabled", func(t *testing.T) {
+		assert.False(t, ent.HasFeature(FeatureAgentChat))
+		assert.True(t, ent.HasFeature(FeatureStatementImport))
+	})
+}
+
+func TestEntitlements_Validate(t *testing.T) {
+	tests := map[string]struct {
+		ent         Entitlements
+		expectedErr bool
+	}{
+		"should accept known limits and features": {
+			ent: Entitlements{
+				Limits:   map[LimitName]int{LimitMovementsPerMonth: 50, LimitAIRequestsPerMonth: 30},
+				Features: map[Feature]bool{FeatureStatementImport: false},
+			},
+		},
+		"should accept empty entitlements": {},
+		"should reject an unknown limit": {
+			ent:         Entitlements{Limits: map[LimitName]int{"budgets": 3}},
+			expectedErr: true,
+		},
+		"should reject a zero limit": {
+			ent:         Entitlements{Limits: map[LimitName]int{LimitWallets: 0}},
+			expectedErr: true,
+		},
+		"should reject an unknown feature": {
+			ent:         Entitlements{Features: map[Feature]bool{"export_pdf": true}},
+			expectedErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.ent.Validate()
+
+			if tt.expectedErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
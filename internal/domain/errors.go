@@ -18,6 +18,10 @@ var (
 	ErrAgentMemoryNotFound    = errors.New("agent memory not found")
 	ErrAgentPIIDetected       = errors.New("PII detected in agent memory content")
 	ErrAgentInvalidMemoryType = errors.New("invalid agent memory type")
+	ErrAgentInvalidAction     = errors.New("invalid agent action")
+	ErrAgentActionNotFound    = errors.New("agent action not found")
+	ErrAgentActionNotPending  = errors.New("agent action is no longer pending")
+	ErrAgentActionExpired     = errors.New("agent action expired")
 )
 
 func WrapInvalidInput(err error, context string) error {
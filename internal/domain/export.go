@@ -15,9 +15,20 @@ type UserDataExport struct {
 	CreditCards   []CreditCard            `json:"credit_cards,omitempty"`
 	Invoices      []Invoice               `json:"invoices,omitempty"`
 	Estimates     UserDataExportEstimates `json:"estimates,omitempty"`
+	Agent         *UserDataExportAgent    `json:"agent,omitempty"`
 }
 
 type UserDataExportEstimates struct {
 	Categories    []EstimateCategories    `json:"categories,omitempty"`
 	SubCategories []EstimateSubCategories `json:"sub_categories,omitempty"`
 }
+
+// UserDataExportAgent is everything the agent keeps about the user.
+type UserDataExportAgent struct {
+	Settings          AgentSettings       `json:"settings"`
+	InsightPreference *InsightPreference  `json:"insight_preference,omitempty"`
+	Memories          []AgentMemory       `json:"memories"`
+	Conversations     []AgentConversation `json:"conversations"`
+	Actions           []AgentAction       `json:"actions"`
+	Audit             []AgentAuditRecord  `json:"audit"`
+}
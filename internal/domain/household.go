@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HouseholdRole string
+
+const (
+	// HouseholdRoleOwner created the household; it manages members, invitations
+	// and cannot leave it.
+	HouseholdRoleOwner  HouseholdRole = "owner"
+	HouseholdRoleEditor HouseholdRole = "editor"
+	HouseholdRoleViewer HouseholdRole = "viewer"
+)
+
+func (r HouseholdRole) IsValid() bool {
+	switch r {
+	case HouseholdRoleOwner, HouseholdRoleEditor, HouseholdRoleViewer:
+		return true
+	}
+	return false
+}
+
+// CanWrite reports whether the role may add and change movements in the
+// resources shared with the household.
+func (r HouseholdRole) CanWrite() bool {
+	return r == HouseholdRoleOwner || r == HouseholdRoleEditor
+}
+
+// HouseholdResource is the kind of resource a member can share with a
+// household.
+type HouseholdResource string
+
+const (
+	HouseholdResourceWallet     HouseholdResource = "wallet"
+	HouseholdResourceCreditCard HouseholdResource = "credit_card"
+	HouseholdResourceCategory   HouseholdResource = "category"
+	HouseholdResourceEstimate   HouseholdResource = "estimate"
+)
+
+func (r HouseholdResource) IsValid() bool {
+	switch r {
+	case HouseholdResourceWallet, HouseholdResourceCreditCard, HouseholdResourceCategory, HouseholdResourceEstimate:
+		return true
+	}
+	return false
+}
+
+// Household groups users that share wallets, cards, categories and estimates.
+type Household struct {
+	ID        uuid.UUID         `json:"id"`
+	Name      string            `json:"name"`
+	OwnerID   string            `json:"owner_id"`
+	Members   []HouseholdMember `json:"members"`
+	Shares    []HouseholdShare  `json:"shares"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type HouseholdMember struct {
+	HouseholdID uuid.UUID     `json:"-"`
+	UserID      string        `json:"user_id"`
+	Role        HouseholdRole `json:"role"`
+	JoinedAt    time.Time     `json:"joined_at"`
+}
+
+// HouseholdShare makes a resource of one member visible to the others. Editors
+// and the owner can also add movements to it.
+type HouseholdShare struct {
+	HouseholdID  uuid.UUID         `json:"-"`
+	ResourceType HouseholdResource `json:"resource_type"`
+	ResourceID   uuid.UUID         `json:"resource_id"`
+	SharedBy     string            `json:"shared_by"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// HouseholdInvitation lets a user join a household with a role. Only the hash
+// of the invitation token is stored; the token is shown once, when created.
+type HouseholdInvitation struct {
+	ID          uuid.UUID     `json:"id"`
+	HouseholdID uuid.UUID     `json:"household_id"`
+	Email       string        `json:"email"`
+	Role        HouseholdRole `json:"role"`
+	TokenHash   string        `json:"-"`
+	InvitedBy   string        `json:"invited_by"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	AcceptedBy  string        `json:"accepted_by,omitempty"`
+	AcceptedAt  *time.Time    `json:"accepted_at,omitempty"`
+	RevokedAt   *time.Time    `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// IsPending reports whether the invitation can still be accepted at now.
+func (i HouseholdInvitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// HouseholdMembership is the role of the current user in one household, used
+// to scope the queries to the resources shared with it.
+type HouseholdMembership struct {
+	HouseholdID uuid.UUID
+	Role        HouseholdRole
+}
+
+var (
+	ErrHouseholdNotFound           = errors.New("household not found")
+	ErrHouseholdMemberNotFound     = errors.New("household member not found")
+	ErrHouseholdForbidden          = errors.New("household role does not allow this action")
+	ErrHouseholdAlreadyMember      = errors.New("user is already a household member")
+	ErrHouseholdInvitationNotFound = errors.New("household invitation not found")
+	ErrHouseholdInvitationInvalid  = errors.New("household invitation is invalid, expired or revoked")
+	ErrHouseholdShareNotFound      = errors.New("household share not found")
+)
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// SplitInstallmentAmounts divide o valor total de uma compra parcelada em
+// `count` parcelas. Com taxa mensal > 0 o total financiado segue a tabela
+// Price (parcelas fixas). Os centavos que sobram da divisão vão para as
+// primeiras parcelas, um centavo cada, para que a soma bata exatamente com o
+// total e o resultado seja sempre o mesmo para a mesma entrada.
+func SplitInstallmentAmounts(total float64, count int, monthlyRate float64) []float64 {
+	if count < 1 {
+		return []float64{}
+	}
+
+	sign := int64(1)
+	if total < 0 {
+		sign = -1
+	}
+
+	financed := math.Abs(total)
+	if monthlyRate > 0 {
+		installment := financed * monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(count)))
+		financed = installment * float64(count)
+	}
+
+	totalCents := int64(math.Round(financed * 100))
+	baseCents := totalCents / int64(count)
+	remainderCents := totalCents % int64(count)
+
+	amounts := make([]float64, count)
+	for i := range amounts {
+		cents := baseCents
+		if int64(i) < remainderCents {
+			cents++
+		}
+		amounts[i] = float64(sign*cents) / 100
+	}
+
+	return amounts
+}
+
+// AddMonthsClamped soma meses mantendo o dia, limitado ao último dia do mês
+// de destino (31/01 + 1 mês = 29/02), evitando que a normalização do
+// time.AddDate pule um mês — e, com ele, uma fatura.
+func AddMonthsClamped(date time.Time, months int) time.Time {
+	firstOfTarget := time.Date(date.Year(), date.Month()+time.Month(months), 1,
+		date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+
+	day := date.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return firstOfTarget.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,267 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/domain/fixture"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitInstallmentAmounts(t *testing.T) {
+	tests := map[string]struct {
+		total    float64
+		count    int
+		rate     float64
+		expected []float64
+	}{
+		"should split evenly without remainder": {
+			total:    -300.0,
+			count:    3,
+			expected: []float64{-100.0, -100.0, -100.0},
+		},
+		"should distribute cent remainder to first installments": {
+			total:    -100.0,
+			count:    3,
+			expected: []float64{-33.34, -33.33, -33.33},
+		},
+		"should keep sign for positive totals": {
+			total:    10.0,
+			count:    4,
+			expected: []float64{2.5, 2.5, 2.5, 2.5},
+		},
+		"should apply monthly interest with price table": {
+			total:    -1000.0,
+			count:    3,
+			rate:     0.02,
+			expected: []float64{-346.76, -346.75, -346.75},
+		},
+		"should return empty list for invalid count": {
+			total:    -100.0,
+			count:    0,
+			expected: []float64{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := domain.SplitInstallmentAmounts(tt.total, tt.count, tt.rate)
+
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestAddMonthsClamped(t *testing.T) {
+	tests := map[string]struct {
+		date     time.Time
+		months   int
+		expected time.Time
+	}{
+		"should keep day when it exists in target month": {
+			date:     time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			months:   1,
+			expected: time.Date(2024, 2, 15, 10, 0, 0, 0, time.UTC),
+		},
+		"should clamp to last day of shorter month": {
+			date:     time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			months:   1,
+			expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		"should roll over year": {
+			date:     time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC),
+			months:   3,
+			expected: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.AddMonthsClamped(tt.date, tt.months))
+		})
+	}
+}
+
+func TestMovement_GenerateInstallmentMovements_WithPlan(t *testing.T) {
+	totalAmount := -1000.0
+	interestRate := 0.02
+
+	tests := map[string]struct {
+		movementInput   domain.Movement
+		expectedAmounts []float64
+		expectedDates   []time.Time
+	}{
+		"should derive installment amounts from total amount": {
+			movementInput: func() domain.Movement {
+				m := fixture.MovementMock(
+					fixture.WithMovementTypePayment(string(domain.TypePaymentCreditCard)),
+					fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+					fixture.WithMovementInstallment(1, 3),
+					fixture.WithMovementDate(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)),
+				)
+				total := -100.0
+				m.CreditCardInfo.TotalAmount = &total
+				return m
+			}(),
+			expectedAmounts: []float64{-33.34, -33.33, -33.33},
+			expectedDates: []time.Time{
+				time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		"should apply interest rate to installment amounts": {
+			movementInput: func() domain.Movement {
+				m := fixture.MovementMock(
+					fixture.WithMovementTypePayment(string(domain.TypePaymentCreditCard)),
+					fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+					fixture.WithMovementInstallment(1, 3),
+					fixture.WithMovementDate(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)),
+				)
+				m.CreditCardInfo.TotalAmount = &totalAmount
+				m.CreditCardInfo.InterestRate = &interestRate
+				return m
+			}(),
+			expectedAmounts: []float64{-346.76, -346.75, -346.75},
+			expectedDates: []time.Time{
+				time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		"should use plan amounts of remaining installments when starting mid-plan": {
+			movementInput: func() domain.Movement {
+				m := fixture.MovementMock(
+					fixture.WithMovementTypePayment(string(domain.TypePaymentCreditCard)),
+					fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+					fixture.WithMovementInstallment(2, 3),
+					fixture.WithMovementDate(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)),
+				)
+				total := -100.0
+				m.CreditCardInfo.TotalAmount = &total
+				return m
+			}(),
+			expectedAmounts: []float64{-33.33, -33.33},
+			expectedDates: []time.Time{
+				time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			installments := tt.movementInput.GenerateInstallmentMovements()
+
+			assert.Len(t, installments, len(tt.expectedAmounts))
+			groupID := installments[0].CreditCardInfo.InstallmentGroupID
+			for i, installment := range installments {
+				assert.Equal(t, tt.expectedAmounts[i], installment.Amount)
+				assert.Equal(t, tt.expectedDates[i], *installment.Date)
+				assert.Equal(t, groupID, installment.CreditCardInfo.InstallmentGroupID)
+			}
+		})
+	}
+}
+
+func TestMovement_GenerateInstallmentMovements_WithoutPlanKeepsAddDate(t *testing.T) {
+	m := fixture.MovementMock(
+		fixture.WithMovementTypePayment(string(domain.TypePaymentCreditCard)),
+		fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+		fixture.WithMovementInstallment(1, 2),
+		fixture.WithMovementDate(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)),
+	)
+
+	installments := m.GenerateInstallmentMovements()
+
+	assert.Len(t, installments, 2)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), *installments[1].Date)
+}
+
+func TestMovement_ValidateInstallmentPlan(t *testing.T) {
+	zero := 0.0
+	total := -100.0
+	negativeRate := -0.01
+
+	tests := map[string]struct {
+		movementInput domain.Movement
+		expectError   bool
+	}{
+		"should accept movement without plan": {
+			movementInput: fixture.MovementMock(
+				fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+				fixture.WithMovementInstallment(1, 3),
+			),
+		},
+		"should accept valid plan": {
+			movementInput: func() domain.Movement {
+				m := fixture.MovementMock(
+					fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+					fixture.WithMovementInstallment(1, 3),
+				)
+				m.CreditCardInfo.TotalAmount = &total
+				return m
+			}(),
+		},
+		"should reject zero total amount": {
+			movementInput: func() domain.Movement {
+				m := fixture.MovementMock(
+					fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+					fixture.WithMovementInstallment(1, 3),
+				)
+				m.CreditCardInfo.TotalAmount = &zero
+				return m
+			}(),
+			expectError: true,
+		},
+		"should reject negative interest rate": {
+			movementInput: func() domain.Movement {
+				m := fixture.MovementMock(
+					fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+					fixture.WithMovementInstallment(1, 3),
+				)
+				m.CreditCardInfo.TotalAmount = &total
+				m.CreditCardInfo.InterestRate = &negativeRate
+				return m
+			}(),
+			expectError: true,
+		},
+		"should reject total amount with a sign other than amount": {
+			movementInput: func() domain.Movement {
+				m := fixture.MovementMock(
+					fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+					fixture.WithMovementInstallment(1, 3),
+				)
+				income := 100.0
+				m.CreditCardInfo.TotalAmount = &income
+				return m
+			}(),
+			expectError: true,
+		},
+		"should reject installment number above total": {
+			movementInput: func() domain.Movement {
+				m := fixture.MovementMock(
+					fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+					fixture.WithMovementInstallment(4, 3),
+				)
+				m.CreditCardInfo.TotalAmount = &total
+				return m
+			}(),
+			expectError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.movementInput.ValidateInstallmentPlan()
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -23,11 +23,34 @@ type Invoice struct {
 	IsPaid       bool       `json:"is_paid"`
 	WalletID     *uuid.UUID `json:"wallet_id,omitempty"`
 	Wallet       Wallet     `json:"wallets,omitempty"`
+	// ClosedAt e ClosedAmount registram o fechamento da fatura: o valor fica congelado
+	// no momento em que o período termina.
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	ClosedAmount *float64   `json:"closed_amount,omitempty"`
 	UserID       string     `json:"user_id"`
 	DateCreate   time.Time  `json:"date_create"`
 	DateUpdate   time.Time  `json:"date_update"`
 }
 
+func (i Invoice) IsClosed() bool {
+	return i.ClosedAt != nil
+}
+
+// AutoDebitAmount retorna o valor a ser debitado automaticamente no vencimento.
+// Quando o valor congelado no fechamento é menor que o atual, paga-se o congelado
+// e a diferença segue como saldo remanescente para a próxima fatura; nil indica
+// pagamento integral.
+func (i Invoice) AutoDebitAmount() *float64 {
+	if i.ClosedAmount == nil {
+		return nil
+	}
+	closed := *i.ClosedAmount
+	if closed >= 0 || closed <= i.Amount {
+		return nil
+	}
+	return &closed
+}
+
 func calculateInvoicePeriod(creditCardClosingDay int, date time.Time) (time.Time, time.Time) {
 	year := date.Year()
 	month := date.Month()
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestInvoice_AutoDebitAmount(t *testing.T) {
+	floatPtr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name         string
+		amount       float64
+		closedAmount *float64
+		want         *float64
+	}{
+		{
+			name:   "fatura não fechada paga valor integral",
+			amount: -1000.0,
+			want:   nil,
+		},
+		{
+			name:         "valor congelado igual ao atual paga valor integral",
+			amount:       -1000.0,
+			closedAmount: floatPtr(-1000.0),
+			want:         nil,
+		},
+		{
+			name:         "compras após o fechamento pagam apenas o valor congelado",
+			amount:       -1200.0,
+			closedAmount: floatPtr(-1000.0),
+			want:         floatPtr(-1000.0),
+		},
+		{
+			name:         "estorno após o fechamento paga valor integral",
+			amount:       -800.0,
+			closedAmount: floatPtr(-1000.0),
+			want:         nil,
+		},
+		{
+			name:         "fatura fechada sem saldo devedor paga valor integral",
+			amount:       -100.0,
+			closedAmount: floatPtr(0),
+			want:         nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := Invoice{Amount: tt.amount, ClosedAmount: tt.closedAmount}
+			got := invoice.AutoDebitAmount()
+
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("AutoDebitAmount() = %v, want %v", got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Errorf("AutoDebitAmount() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
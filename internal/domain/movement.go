@@ -13,6 +13,7 @@ type (
 		Amount         float64             `json:"amount"`
 		Date           *time.Time          `json:"date"`
 		UserID         string              `json:"user_id"`
+		CreatedBy      string              `json:"created_by,omitempty"` // quem lançou; difere de UserID em carteira compartilhada
 		IsPaid         bool                `json:"is_paid"`
 		IsRecurrent    bool                `json:"is_recurrent"`
 		RecurrentID    *uuid.UUID          `json:"recurrent_id"`
@@ -36,6 +37,11 @@ type (
 		InstallmentGroupID *uuid.UUID `json:"installment_group_id,omitempty"`
 		InstallmentNumber  *int       `json:"installment_number,omitempty"`
 		TotalInstallments  *int       `json:"total_installments,omitempty"`
+		// TotalAmount, quando informado, é o valor total da compra parcelada;
+		// o valor de cada parcela é calculado a partir dele (ver SplitInstallmentAmounts).
+		TotalAmount *float64 `json:"total_amount,omitempty"`
+		// InterestRate é a taxa de juros mensal (ex.: 0.0199 para 1,99% a.m.).
+		InterestRate *float64 `json:"interest_rate,omitempty"`
 	}
 
 	MovementList []Movement
@@ -100,18 +106,55 @@ func (m Movement) IsCreditCardMovement() bool {
 	return m.TypePayment == TypePaymentCreditCard
 }
 
+func (m Movement) IsTransfer() bool {
+	return m.PairID != nil
+}
+
 func (m Movement) IsInstallmentMovement() bool {
 	return m.CreditCardInfo != nil &&
 		m.CreditCardInfo.InstallmentNumber != nil &&
 		m.CreditCardInfo.TotalInstallments != nil
 }
 
+func (m Movement) HasInstallmentPlan() bool {
+	return m.IsInstallmentMovement() && m.CreditCardInfo.TotalAmount != nil
+}
+
+func (m Movement) ValidateInstallmentPlan() error {
+	if !m.HasInstallmentPlan() {
+		return nil
+	}
+
+	info := m.CreditCardInfo
+	if *info.TotalInstallments < 1 {
+		return WrapInvalidInput(New("total_installments must be at least 1"), "validate installment plan")
+	}
+	if *info.InstallmentNumber < 1 || *info.InstallmentNumber > *info.TotalInstallments {
+		return WrapInvalidInput(New("installment_number must be between 1 and total_installments"), "validate installment plan")
+	}
+	if *info.TotalAmount == 0 {
+		return WrapInvalidInput(New("total_amount must not be zero"), "validate installment plan")
+	}
+	if m.Amount != 0 && (*info.TotalAmount > 0) != (m.Amount > 0) {
+		return WrapInvalidInput(New("total_amount must have the same sign as amount"), "validate installment plan")
+	}
+	if info.InterestRate != nil && *info.InterestRate < 0 {
+		return WrapInvalidInput(New("interest_rate must not be negative"), "validate installment plan")
+	}
+
+	return nil
+}
+
 func (m Movement) BuildInstallmentMovement(installmentNumber int, date time.Time) Movement {
+	return m.BuildInstallmentMovementWithAmount(installmentNumber, date, m.Amount)
+}
+
+func (m Movement) BuildInstallmentMovementWithAmount(installmentNumber int, date time.Time, amount float64) Movement {
 	id := uuid.New()
 	return Movement{
 		ID:          &id,
 		Description: m.Description,
-		Amount:      m.Amount,
+		Amount:      amount,
 		Date:        &date,
 		UserID:      m.UserID,
 		IsPaid:      m.IsPaid,
@@ -121,6 +164,8 @@ func (m Movement) BuildInstallmentMovement(installmentNumber int, date time.Time
 			InstallmentGroupID: m.CreditCardInfo.InstallmentGroupID,
 			InstallmentNumber:  &installmentNumber,
 			TotalInstallments:  m.CreditCardInfo.TotalInstallments,
+			TotalAmount:        m.CreditCardInfo.TotalAmount,
+			InterestRate:       m.CreditCardInfo.InterestRate,
 		},
 		WalletID:      m.WalletID,
 		TypePayment:   m.TypePayment,
@@ -139,16 +184,41 @@ func (m Movement) GenerateInstallmentMovements() MovementList {
 		return MovementList{}
 	}
 
+	var amounts []float64
+	if m.HasInstallmentPlan() {
+		rate := float64(0)
+		if m.CreditCardInfo.InterestRate != nil {
+			rate = *m.CreditCardInfo.InterestRate
+		}
+		amounts = SplitInstallmentAmounts(*m.CreditCardInfo.TotalAmount, *m.CreditCardInfo.TotalInstallments, rate)
+	}
+
 	groupID := uuid.New()
 
-	m.CreditCardInfo.InstallmentGroupID = &groupID
-	movements := MovementList{m}
+	creditCardInfo := *m.CreditCardInfo
+	creditCardInfo.InstallmentGroupID = &groupID
+	m.CreditCardInfo = &creditCardInfo
 
 	installment := *m.CreditCardInfo.InstallmentNumber
+	if amounts != nil {
+		m.Amount = amounts[installment-1]
+	}
+	movements := MovementList{m}
+
 	for i := 0; i < remainingInstallments; i++ {
+		// Só os planos com total_amount limitam o dia ao fim do mês; as demais
+		// compras parceladas seguem com AddDate, como antes.
 		installmentDate := m.Date.AddDate(0, i+1, 0)
+		if amounts != nil {
+			installmentDate = AddMonthsClamped(*m.Date, i+1)
+		}
 		installment++
-		movements = append(movements, m.BuildInstallmentMovement(installment, installmentDate))
+
+		amount := m.Amount
+		if amounts != nil {
+			amount = amounts[installment-1]
+		}
+		movements = append(movements, m.BuildInstallmentMovementWithAmount(installment, installmentDate, amount))
 	}
 
 	return movements
@@ -16,6 +16,7 @@ type CreditCardOutput struct {
 	DueDay        int          `json:"due_day"`
 	Color         string       `json:"color,omitempty"`
 	DefaultWallet WalletOutput `json:"default_wallet,omitempty"`
+	AutoDebit     bool         `json:"auto_debit"`
 	DateUpdate    time.Time    `json:"date_update"`
 }
 
@@ -28,6 +29,7 @@ func ToCreditCardOutput(input domain.CreditCard) CreditCardOutput {
 		DueDay:        input.DueDay,
 		Color:         input.Color,
 		DefaultWallet: ToWalletOutput(input.DefaultWallet),
+		AutoDebit:     input.AutoDebit,
 		DateUpdate:    input.DateUpdate,
 	}
 }
@@ -23,6 +23,7 @@ type MovementOutput struct {
 	Category       CategoryOutput            `json:"category,omitempty"`
 	SubCategory    SubCategoryOutput         `json:"sub_category,omitempty"`
 	DateUpdate     *time.Time                `json:"date_update,omitempty"`
+	CreatedBy      string                    `json:"created_by,omitempty"`
 }
 
 type MovementListOutput []MovementOutput
@@ -43,6 +44,7 @@ func ToMovementOutput(input domain.Movement) *MovementOutput {
 		Category:       ToCategoryOutput(input.Category),
 		SubCategory:    ToSubCategoryOutput(input.SubCategory),
 		DateUpdate:     &input.DateUpdate,
+		CreatedBy:      input.CreatedBy,
 	}
 	return output
 }
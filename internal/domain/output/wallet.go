@@ -11,9 +11,11 @@ import (
 type WalletOutput struct {
 	ID             *uuid.UUID `json:"id,omitempty"`
 	Description    string     `json:"description,omitempty"`
+	Type           string     `json:"type,omitempty"`
 	Balance        float64    `json:"balance"`
 	InitialBalance float64    `json:"initial_balance,omitempty"`
 	InitialDate    *time.Time `json:"initial_date,omitempty"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
 }
 
 func ToWalletOutput(input domain.Wallet) WalletOutput {
@@ -25,9 +27,11 @@ func ToWalletOutput(input domain.Wallet) WalletOutput {
 	return WalletOutput{
 		ID:             input.ID,
 		Description:    input.Description,
+		Type:           string(input.Type),
 		Balance:        input.Balance,
 		InitialBalance: input.InitialBalance,
 		InitialDate:    truncated,
+		ArchivedAt:     input.ArchivedAt,
 	}
 }
 
@@ -42,3 +46,30 @@ func ToWalletOutputDTO(input domain.Wallet) WalletOutputDTO {
 		Description: input.Description,
 	}
 }
+
+type WalletBalancePointOutput struct {
+	Date    string  `json:"date"`
+	Balance float64 `json:"balance"`
+}
+
+type WalletBalanceHistoryOutput struct {
+	WalletID    uuid.UUID                  `json:"wallet_id"`
+	Granularity string                     `json:"granularity"`
+	Points      []WalletBalancePointOutput `json:"points"`
+}
+
+func ToWalletBalanceHistoryOutput(walletID uuid.UUID, granularity domain.BalanceGranularity, points []domain.WalletBalancePoint) WalletBalanceHistoryOutput {
+	result := make([]WalletBalancePointOutput, len(points))
+	for i, p := range points {
+		result[i] = WalletBalancePointOutput{
+			Date:    p.Date.Format("2006-01-02"),
+			Balance: p.Balance,
+		}
+	}
+
+	return WalletBalanceHistoryOutput{
+		WalletID:    walletID,
+		Granularity: string(granularity),
+		Points:      result,
+	}
+}
@@ -20,6 +20,13 @@ type RecurrentMovement struct {
 	WalletID      *uuid.UUID  `json:"wallet_id,omitempty"`
 	Wallet        Wallet      `json:"wallets,omitempty"`
 	TypePayment   TypePayment `json:"type_payment,omitempty"`
+	PairID        *uuid.UUID  `json:"pair_id,omitempty"`
+}
+
+// IsTransfer indica que a recorrência é uma das pontas de uma transferência
+// recorrente entre carteiras; as duas pontas compartilham o mesmo PairID.
+func (r RecurrentMovement) IsTransfer() bool {
+	return r.PairID != nil
 }
 
 func ToRecurrentMovement(movement Movement) RecurrentMovement {
@@ -52,6 +59,7 @@ func FromRecurrentMovement(recurrent RecurrentMovement, date time.Time) Movement
 		WalletID:      recurrent.WalletID,
 		Wallet:        recurrent.Wallet,
 		TypePayment:   recurrent.TypePayment,
+		PairID:        recurrent.PairID,
 	}
 }
 
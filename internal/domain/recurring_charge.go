@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RecurringChargePeriod string
+
+const (
+	RecurringChargeMonthly RecurringChargePeriod = "monthly"
+	RecurringChargeYearly  RecurringChargePeriod = "yearly"
+)
+
+const (
+	// RecurringChargeLookbackMonths cobre duas cobranças de uma assinatura anual.
+	RecurringChargeLookbackMonths = 13
+
+	// recurringChargeAmountTolerance é a variação máxima entre duas cobranças
+	// seguidas da mesma série; acima disso são tratadas como compras diferentes.
+	recurringChargeAmountTolerance = 0.2
+	// recurringChargeMinGapDays evita que duas compras no mesmo mês entrem na mesma série.
+	recurringChargeMinGapDays       = 20
+	recurringChargeActiveGraceDays  = 10
+	recurringChargeMinPriceIncrease = 0.01
+
+	// Assinaturas pequenas no cartão são as que mais passam despercebidas.
+	forgottenChargeMaxMonthlyAmount = 100.0
+	forgottenChargeMinOccurrences   = 3
+)
+
+type recurringChargeRule struct {
+	period          RecurringChargePeriod
+	minIntervalDays int
+	maxIntervalDays int
+	minOccurrences  int
+	periodDays      int
+	monthsPerCharge int
+}
+
+var recurringChargeRules = []recurringChargeRule{
+	{period: RecurringChargeMonthly, minIntervalDays: 25, maxIntervalDays: 35, minOccurrences: 3, periodDays: 31, monthsPerCharge: 1},
+	{period: RecurringChargeYearly, minIntervalDays: 350, maxIntervalDays: 380, minOccurrences: 2, periodDays: 366, monthsPerCharge: 12},
+}
+
+// RecurringChargePriceChange é o último aumento de preço de uma cobrança recorrente.
+type RecurringChargePriceChange struct {
+	PreviousAmount float64   `json:"previous_amount"`
+	CurrentAmount  float64   `json:"current_amount"`
+	ChangedAt      time.Time `json:"changed_at"`
+	IncreasePct    float64   `json:"increase_pct"`
+}
+
+// DetectedRecurringCharge é uma despesa que se repete no histórico sem estar
+// cadastrada como recorrente. Os valores seguem o sinal das movimentações
+// (negativos), exceto MonthlyAmount, que é o impacto mensal em módulo.
+type DetectedRecurringCharge struct {
+	Description           string                      `json:"description"`
+	NormalizedDescription string                      `json:"normalized_description"`
+	Period                RecurringChargePeriod       `json:"period"`
+	Amount                float64                     `json:"amount"`
+	MonthlyAmount         float64                     `json:"monthly_amount"`
+	Occurrences           int                         `json:"occurrences"`
+	FirstDate             time.Time                   `json:"first_date"`
+	LastDate              time.Time                   `json:"last_date"`
+	NextExpectedDate      time.Time                   `json:"next_expected_date"`
+	TypePayment           TypePayment                 `json:"type_payment,omitempty"`
+	PossiblyForgotten     bool                        `json:"possibly_forgotten"`
+	PriceIncrease         *RecurringChargePriceChange `json:"price_increase,omitempty"`
+	MovementIDs           []uuid.UUID                 `json:"movement_ids"`
+	// SuggestedRecurrent é a recorrência proposta a partir da próxima cobrança,
+	// para não duplicar os meses já lançados. Só existe para cobranças mensais,
+	// que é a periodicidade das recorrências.
+	SuggestedRecurrent *RecurrentMovement `json:"suggested_recurrent,omitempty"`
+}
+
+// DetectRecurringCharges agrupa as despesas pela descrição normalizada, separa
+// em séries de valores próximos e mantém as séries com intervalo mensal ou
+// anual que ainda estão ativas em now. O resultado vem ordenado pelo impacto
+// mensal, do maior para o menor.
+func DetectRecurringCharges(movements MovementList, now time.Time) []DetectedRecurringCharge {
+	groups := make(map[string]MovementList)
+	var keys []string
+	for _, movement := range movements {
+		if movement.Amount >= 0 || movement.Date == nil {
+			continue
+		}
+		key := NormalizeDescription(movement.Description)
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], movement)
+	}
+
+	var detected []DetectedRecurringCharge
+	for _, key := range keys {
+		for _, series := range splitChargeSeries(groups[key]) {
+			if charge, ok := classifyChargeSeries(key, series, now); ok {
+				detected = append(detected, charge)
+			}
+		}
+	}
+
+	sort.SliceStable(detected, func(i, j int) bool {
+		return detected[i].MonthlyAmount > detected[j].MonthlyAmount
+	})
+	return detected
+}
+
+// splitChargeSeries distribui as movimentações, em ordem de data, na série cujo
+// último valor é o mais próximo dentro da tolerância.
+func splitChargeSeries(movements MovementList) []MovementList {
+	sorted := make(MovementList, len(movements))
+	copy(sorted, movements)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(*sorted[j].Date)
+	})
+
+	var series []MovementList
+	for _, movement := range sorted {
+		best := -1
+		bestDiff := math.MaxFloat64
+		for i, s := range series {
+			last := s[len(s)-1]
+			diff := math.Abs(movement.Amount-last.Amount) / math.Abs(last.Amount)
+			if diff > recurringChargeAmountTolerance || daysBetween(*last.Date, *movement.Date) < recurringChargeMinGapDays {
+				continue
+			}
+			if diff < bestDiff {
+				best, bestDiff = i, diff
+			}
+		}
+		if best < 0 {
+			series = append(series, MovementList{movement})
+			continue
+		}
+		series[best] = append(series[best], movement)
+	}
+	return series
+}
+
+func classifyChargeSeries(key string, series MovementList, now time.Time) (DetectedRecurringCharge, bool) {
+	for _, rule := range recurringChargeRules {
+		if len(series) < rule.minOccurrences || !matchesInterval(series, rule) {
+			continue
+		}
+
+		first, last := series[0], series[len(series)-1]
+		if daysBetween(*last.Date, now) > rule.periodDays+recurringChargeActiveGraceDays {
+			return DetectedRecurringCharge{}, false
+		}
+
+		monthly := math.Round(math.Abs(last.Amount)/float64(rule.monthsPerCharge)*100) / 100
+		charge := DetectedRecurringCharge{
+			Description:           last.Description,
+			NormalizedDescription: key,
+			Period:                rule.period,
+			Amount:                last.Amount,
+			MonthlyAmount:         monthly,
+			Occurrences:           len(series),
+			FirstDate:             *first.Date,
+			LastDate:              *last.Date,
+			NextExpectedDate:      last.Date.AddDate(0, rule.monthsPerCharge, 0),
+			TypePayment:           last.TypePayment,
+			PriceIncrease:         lastPriceIncrease(series),
+		}
+		charge.PossiblyForgotten = last.TypePayment == TypePaymentCreditCard &&
+			monthly <= forgottenChargeMaxMonthlyAmount &&
+			len(series) >= forgottenChargeMinOccurrences
+		for _, movement := range series {
+			if movement.ID != nil {
+				charge.MovementIDs = append(charge.MovementIDs, *movement.ID)
+			}
+		}
+		if rule.period == RecurringChargeMonthly {
+			suggested := ToRecurrentMovement(last)
+			suggested.InitialDate = &charge.NextExpectedDate
+			charge.SuggestedRecurrent = &suggested
+		}
+		return charge, true
+	}
+	return DetectedRecurringCharge{}, false
+}
+
+// matchesInterval exige que ao menos 3/4 dos intervalos estejam na faixa da
+// periodicidade, tolerando um mês pulado ou cobrado fora do dia.
+func matchesInterval(series MovementList, rule recurringChargeRule) bool {
+	inRange := 0
+	for i := 1; i < len(series); i++ {
+		days := daysBetween(*series[i-1].Date, *series[i].Date)
+		if days >= rule.minIntervalDays && days <= rule.maxIntervalDays {
+			inRange++
+		}
+	}
+	return inRange*4 >= (len(series)-1)*3
+}
+
+func lastPriceIncrease(series MovementList) *RecurringChargePriceChange {
+	for i := len(series) - 1; i > 0; i-- {
+		previous, current := math.Abs(series[i-1].Amount), math.Abs(series[i].Amount)
+		if current == previous {
+			continue
+		}
+		if current < previous*(1+recurringChargeMinPriceIncrease) {
+			return nil
+		}
+		return &RecurringChargePriceChange{
+			PreviousAmount: series[i-1].Amount,
+			CurrentAmount:  series[i].Amount,
+			ChangedAt:      *series[i].Date,
+			IncreasePct:    math.Round((current/previous-1)*1000) / 10,
+		}
+	}
+	return nil
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
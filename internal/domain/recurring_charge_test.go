@@ -0,0 +1,129 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chargeMovement(description string, amount float64, date time.Time, typePayment domain.TypePayment) domain.Movement {
+	id := uuid.New()
+	return domain.Movement{
+		ID:          &id,
+		Description: description,
+		Amount:      amount,
+		Date:        &date,
+		UserID:      "user-1",
+		TypePayment: typePayment,
+	}
+}
+
+func monthlyCharges(description string, amounts []float64, from time.Time, typePayment domain.TypePayment) domain.MovementList {
+	var movements domain.MovementList
+	for i, amount := range amounts {
+		movements = append(movements, chargeMovement(description, amount, from.AddDate(0, i, 0), typePayment))
+	}
+	return movements
+}
+
+func TestDetectRecurringCharges(t *testing.T) {
+	now := time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2025, time.October, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should detect a monthly card subscription and propose it as recurrent", func(t *testing.T) {
+		movements := monthlyCharges("NETFLIX.COM", []float64{-39.9, -39.9, -39.9, -39.9, -39.9, -39.9}, start, domain.TypePaymentCreditCard)
+
+		detected := domain.DetectRecurringCharges(movements, now)
+
+		require.Len(t, detected, 1)
+		charge := detected[0]
+		assert.Equal(t, domain.RecurringChargeMonthly, charge.Period)
+		assert.Equal(t, "netflixcom", charge.NormalizedDescription)
+		assert.Equal(t, -39.9, charge.Amount)
+		assert.Equal(t, 39.9, charge.MonthlyAmount)
+		assert.Equal(t, 6, charge.Occurrences)
+		assert.Equal(t, time.Date(2026, time.April, 5, 0, 0, 0, 0, time.UTC), charge.NextExpectedDate)
+		assert.True(t, charge.PossiblyForgotten)
+		assert.Nil(t, charge.PriceIncrease)
+		assert.Len(t, charge.MovementIDs, 6)
+		require.NotNil(t, charge.SuggestedRecurrent)
+		assert.Equal(t, "NETFLIX.COM", charge.SuggestedRecurrent.Description)
+		assert.Equal(t, -39.9, charge.SuggestedRecurrent.Amount)
+		assert.Equal(t, charge.NextExpectedDate, *charge.SuggestedRecurrent.InitialDate)
+	})
+
+	t.Run("should report the latest price increase", func(t *testing.T) {
+		movements := monthlyCharges("Spotify", []float64{-21.9, -21.9, -21.9, -23.9, -23.9, -23.9}, start, domain.TypePaymentCreditCard)
+
+		detected := domain.DetectRecurringCharges(movements, now)
+
+		require.Len(t, detected, 1)
+		require.NotNil(t, detected[0].PriceIncrease)
+		assert.Equal(t, -21.9, detected[0].PriceIncrease.PreviousAmount)
+		assert.Equal(t, -23.9, detected[0].PriceIncrease.CurrentAmount)
+		assert.Equal(t, start.AddDate(0, 3, 0), detected[0].PriceIncrease.ChangedAt)
+		assert.Equal(t, 9.1, detected[0].PriceIncrease.IncreasePct)
+	})
+
+	t.Run("should detect a yearly charge without proposing a monthly recurrence", func(t *testing.T) {
+		movements := domain.MovementList{
+			chargeMovement("Amazon Prime anual", -119, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), domain.TypePaymentCreditCard),
+			chargeMovement("Amazon Prime anual", -166.8, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), domain.TypePaymentCreditCard),
+		}
+
+		detected := domain.DetectRecurringCharges(movements, now)
+
+		assert.Empty(t, detected, "a 40% jump is a different purchase")
+
+		movements[1].Amount = -139
+		detected = domain.DetectRecurringCharges(movements, now)
+
+		require.Len(t, detected, 1)
+		assert.Equal(t, domain.RecurringChargeYearly, detected[0].Period)
+		assert.Equal(t, 11.58, detected[0].MonthlyAmount)
+		assert.Nil(t, detected[0].SuggestedRecurrent)
+		assert.NotNil(t, detected[0].PriceIncrease)
+	})
+
+	t.Run("should separate purchases with the same description but different amounts", func(t *testing.T) {
+		movements := append(
+			monthlyCharges("Academia", []float64{-120, -120, -120, -120}, start.AddDate(0, 2, 0), domain.TypePaymentPix),
+			chargeMovement("Academia", -450, start.AddDate(0, 3, 10), domain.TypePaymentPix),
+		)
+
+		detected := domain.DetectRecurringCharges(movements, now)
+
+		require.Len(t, detected, 1)
+		assert.Equal(t, 4, detected[0].Occurrences)
+		assert.False(t, detected[0].PossiblyForgotten, "only card charges are flagged")
+	})
+
+	t.Run("should ignore cancelled charges and irregular purchases", func(t *testing.T) {
+		movements := append(
+			monthlyCharges("Disney Plus", []float64{-33.9, -33.9, -33.9}, start.AddDate(-1, 0, 0), domain.TypePaymentCreditCard),
+			chargeMovement("Uber", -25, start, domain.TypePaymentCreditCard),
+			chargeMovement("Uber", -27, start.AddDate(0, 0, 9), domain.TypePaymentCreditCard),
+			chargeMovement("Uber", -24, start.AddDate(0, 2, 17), domain.TypePaymentCreditCard),
+		)
+
+		assert.Empty(t, domain.DetectRecurringCharges(movements, now))
+	})
+
+	t.Run("should sort by monthly impact", func(t *testing.T) {
+		movements := append(
+			monthlyCharges("Spotify", []float64{-21.9, -21.9, -21.9}, start.AddDate(0, 3, 0), domain.TypePaymentCreditCard),
+			monthlyCharges("Aluguel", []float64{-2000, -2000, -2000}, start.AddDate(0, 3, 0), domain.TypePaymentPix)...,
+		)
+
+		detected := domain.DetectRecurringCharges(movements, now)
+
+		require.Len(t, detected, 2)
+		assert.Equal(t, "Aluguel", detected[0].Description)
+		assert.Equal(t, "Spotify", detected[1].Description)
+	})
+}
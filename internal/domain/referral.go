@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReferralStatus string
+
+const (
+	// ReferralPending waits for the first paid subscription of the referee.
+	ReferralPending   ReferralStatus = "pending"
+	ReferralQualified ReferralStatus = "qualified"
+	ReferralRejected  ReferralStatus = "rejected"
+)
+
+// ReferralRejectSameDevice is set when the referee shares a device with the
+// referrer.
+const ReferralRejectSameDevice = "same_device"
+
+type ReferralRewardStatus string
+
+const (
+	// ReferralRewardPending waits for the user to be without a paid plan, so the
+	// free months do not overlap a subscription being charged.
+	ReferralRewardPending ReferralRewardStatus = "pending"
+	// ReferralRewardApplying is claimed by a run that fixed PlusUntil but may not
+	// have finished granting it; a retry grants the same PlusUntil.
+	ReferralRewardApplying ReferralRewardStatus = "applying"
+	ReferralRewardApplied  ReferralRewardStatus = "applied"
+)
+
+type ReferralRole string
+
+const (
+	ReferralRoleReferrer ReferralRole = "referrer"
+	ReferralRoleReferee  ReferralRole = "referee"
+)
+
+// ReferralCode is the code a user shares to refer others.
+type ReferralCode struct {
+	UserID    string
+	Code      string
+	CreatedAt time.Time
+}
+
+// Referral links the user who signed up with a code to the owner of the code.
+type Referral struct {
+	ID           uuid.UUID
+	ReferrerID   string
+	RefereeID    string
+	Code         string
+	Status       ReferralStatus
+	RejectReason string
+	CreatedAt    time.Time
+	QualifiedAt  *time.Time
+}
+
+// ReferralReward is the free Plus months one side of a qualified referral gets.
+type ReferralReward struct {
+	ID         uuid.UUID            `json:"id"`
+	ReferralID uuid.UUID            `json:"referral_id"`
+	UserID     string               `json:"-"`
+	Role       ReferralRole         `json:"role"`
+	Months     int                  `json:"months"`
+	Status     ReferralRewardStatus `json:"status"`
+	CreatedAt  time.Time            `json:"created_at"`
+	AppliedAt  *time.Time           `json:"applied_at,omitempty"`
+	// PlusUntil is the plan expiry set when the reward was claimed.
+	PlusUntil *time.Time `json:"plus_until,omitempty"`
+}
+
+var (
+	ErrReferralNotFound     = errors.New("referral not found")
+	ErrReferralCodeNotFound = errors.New("referral code not found")
+	ErrReferralSelf         = errors.New("users cannot refer themselves")
+)
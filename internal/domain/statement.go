@@ -30,6 +30,7 @@ type ExtractedMovement struct {
 type StatementExtractResult struct {
 	Movements []ExtractedMovement `json:"movements"`
 	Errors    []string            `json:"errors,omitempty"`
+	Usage     AIUsage             `json:"-"`
 }
 type StatementConfirmInput struct {
 	Movements []ExtractedMovement `json:"movements"`
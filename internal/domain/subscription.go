@@ -24,6 +24,7 @@ const (
 	SubscriptionStatusExpired   SubscriptionStatus = "expired"
 	SubscriptionStatusPaused    SubscriptionStatus = "paused"
 	SubscriptionStatusPastDue   SubscriptionStatus = "past_due"
+	SubscriptionStatusTrialing  SubscriptionStatus = "trialing"
 )
 
 type Subscription struct {
@@ -39,6 +40,95 @@ type Subscription struct {
 	StartedAt         time.Time
 	CurrentPeriodEnd  *time.Time
 	CancelledAt       *time.Time
+	TrialEndsAt       *time.Time
+	GraceEndsAt       *time.Time // when a past_due subscription loses the paid plan
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
+
+// AccessUntil tells whether the subscription grants the paid plan at now and
+// until when. A nil time means access lasts while the subscription renews.
+// pastDueGrace is how long a past_due subscription keeps the paid plan after
+// its period ends, while the provider retries the charge; it only applies to
+// subscriptions without a GraceEndsAt, which the webhooks record.
+func (s Subscription) AccessUntil(now time.Time, pastDueGrace time.Duration) (*time.Time, bool) {
+	switch s.Status {
+	case SubscriptionStatusActive, SubscriptionStatusTrialing:
+		return nil, true
+	case SubscriptionStatusCancelled, SubscriptionStatusPaused:
+		// Paid period already charged is honored.
+		if s.CurrentPeriodEnd != nil && s.CurrentPeriodEnd.After(now) {
+			end := *s.CurrentPeriodEnd
+			return &end, true
+		}
+	case SubscriptionStatusPastDue:
+		if s.GraceEndsAt != nil {
+			if s.GraceEndsAt.After(now) {
+				end := *s.GraceEndsAt
+				return &end, true
+			}
+			return nil, false
+		}
+		base := s.UpdatedAt
+		if s.CurrentPeriodEnd != nil {
+			base = *s.CurrentPeriodEnd
+		}
+		if end := base.Add(pastDueGrace); end.After(now) {
+			return &end, true
+		}
+	}
+	return nil, false
+}
+
+// EffectiveSubscription picks, among the subscriptions of a user, the one that
+// grants the longest access at now: a renewing subscription wins over any end
+// date. It returns false when none grants the paid plan.
+func EffectiveSubscription(subs []Subscription, now time.Time, pastDueGrace time.Duration) (Subscription, *time.Time, bool) {
+	var (
+		best      Subscription
+		bestUntil *time.Time
+		found     bool
+	)
+	for _, sub := range subs {
+		until, ok := sub.AccessUntil(now, pastDueGrace)
+		if !ok {
+			continue
+		}
+		switch {
+		case !found:
+		case bestUntil == nil:
+			continue
+		case until != nil && !until.After(*bestUntil):
+			continue
+		}
+		best, bestUntil, found = sub, until, true
+	}
+	return best, bestUntil, found
+}
+
+// EndedAt tells when the subscription stopped granting the paid plan. It
+// returns false while it still grants it, or when it never started.
+func (s Subscription) EndedAt(now time.Time, pastDueGrace time.Duration) (time.Time, bool) {
+	if s.Status == SubscriptionStatusPending {
+		return time.Time{}, false
+	}
+	if _, ok := s.AccessUntil(now, pastDueGrace); ok {
+		return time.Time{}, false
+	}
+
+	end := s.UpdatedAt
+	switch {
+	case s.Status == SubscriptionStatusPastDue && s.GraceEndsAt != nil:
+		end = *s.GraceEndsAt
+	case s.Status == SubscriptionStatusPastDue && s.CurrentPeriodEnd != nil:
+		end = s.CurrentPeriodEnd.Add(pastDueGrace)
+	case s.CurrentPeriodEnd != nil:
+		end = *s.CurrentPeriodEnd
+	case s.CancelledAt != nil:
+		end = *s.CancelledAt
+	}
+	if end.Before(s.StartedAt) {
+		end = s.StartedAt
+	}
+	return end, true
+}
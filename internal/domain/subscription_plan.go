@@ -9,4 +9,7 @@ type SubscriptionPlan struct {
 	FrequencyType string  `json:"frequency_type"`
 	IsActive      bool    `json:"is_active"`
 	StripePriceID string  `json:"-"`
+	TrialDays     int     `json:"trial_days,omitempty"`
+	// Entitlements is nil when the plan uses the defaults of its tier.
+	Entitlements *Entitlements `json:"entitlements,omitempty"`
 }
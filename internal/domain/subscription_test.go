@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscription_AccessUntil(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	future := now.Add(48 * time.Hour)
+	past := now.Add(-48 * time.Hour)
+	longPast := now.Add(-30 * 24 * time.Hour)
+	fourDaysAgo := now.Add(-4 * 24 * time.Hour)
+	grace := 3 * 24 * time.Hour
+	graceEnd := past.Add(grace)
+
+	tests := map[string]struct {
+		sub           Subscription
+		expectedUntil *time.Time
+		expectedOK    bool
+	}{
+		"should grant an active subscription while it renews": {
+			sub:        Subscription{Status: SubscriptionStatusActive, CurrentPeriodEnd: &past},
+			expectedOK: true,
+		},
+		"should grant a cancelled subscription until the period ends": {
+			sub:           Subscription{Status: SubscriptionStatusCancelled, CurrentPeriodEnd: &future},
+			expectedUntil: &future,
+			expectedOK:    true,
+		},
+		"should not grant a cancelled subscription after the period ends": {
+			sub: Subscription{Status: SubscriptionStatusCancelled, CurrentPeriodEnd: &past},
+		},
+		"should grant a past due subscription during the grace": {
+			sub:           Subscription{Status: SubscriptionStatusPastDue, CurrentPeriodEnd: &past},
+			expectedUntil: &graceEnd,
+			expectedOK:    true,
+		},
+		"should not grant a past due subscription after the grace": {
+			sub: Subscription{Status: SubscriptionStatusPastDue, CurrentPeriodEnd: &longPast},
+		},
+		"should apply the configured grace": {
+			sub: Subscription{Status: SubscriptionStatusPastDue, CurrentPeriodEnd: &fourDaysAgo},
+		},
+		"should grant a past due subscription until its recorded grace ends": {
+			sub:           Subscription{Status: SubscriptionStatusPastDue, CurrentPeriodEnd: &longPast, GraceEndsAt: &future},
+			expectedUntil: &future,
+			expectedOK:    true,
+		},
+		"should not grant a past due subscription after its recorded grace ends": {
+			sub: Subscription{Status: SubscriptionStatusPastDue, CurrentPeriodEnd: &future, GraceEndsAt: &past},
+		},
+		"should grant a trialing subscription while it renews": {
+			sub:        Subscription{Status: SubscriptionStatusTrialing, TrialEndsAt: &future},
+			expectedOK: true,
+		},
+		"should not grant an expired subscription": {
+			sub: Subscription{Status: SubscriptionStatusExpired, CurrentPeriodEnd: &future},
+		},
+		"should not grant a pending subscription": {
+			sub: Subscription{Status: SubscriptionStatusPending},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			until, ok := tt.sub.AccessUntil(now, grace)
+
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedUntil, until)
+		})
+	}
+}
+
+func TestEffectiveSubscription(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	soon := now.Add(24 * time.Hour)
+	later := now.Add(72 * time.Hour)
+
+	cancelledSoon := Subscription{ExternalID: "soon", Status: SubscriptionStatusCancelled, CurrentPeriodEnd: &soon}
+	cancelledLater := Subscription{ExternalID: "later", Status: SubscriptionStatusCancelled, CurrentPeriodEnd: &later}
+	active := Subscription{ExternalID: "active", Status: SubscriptionStatusActive}
+	expired := Subscription{ExternalID: "expired", Status: SubscriptionStatusExpired}
+
+	t.Run("should prefer a renewing subscription", func(t *testing.T) {
+		sub, until, ok := EffectiveSubscription([]Subscription{cancelledLater, active, cancelledSoon}, now, 0)
+
+		assert.True(t, ok)
+		assert.Equal(t, "active", sub.ExternalID)
+		assert.Nil(t, until)
+	})
+
+	t.Run("should prefer the latest end date", func(t *testing.T) {
+		sub, until, ok := EffectiveSubscription([]Subscription{cancelledSoon, expired, cancelledLater}, now, 0)
+
+		assert.True(t, ok)
+		assert.Equal(t, "later", sub.ExternalID)
+		assert.Equal(t, &later, until)
+	})
+
+	t.Run("should find nothing without access", func(t *testing.T) {
+		_, _, ok := EffectiveSubscription([]Subscription{expired}, now, 0)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestSubscription_EndedAt(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-90 * 24 * time.Hour)
+	future := now.Add(48 * time.Hour)
+	past := now.Add(-48 * time.Hour)
+	cancelledAt := now.Add(-72 * time.Hour)
+	longPast := now.Add(-30 * 24 * time.Hour)
+	grace := 3 * 24 * time.Hour
+
+	tests := map[string]struct {
+		sub           Subscription
+		expectedEnd   time.Time
+		expectedEnded bool
+	}{
+		"should not end an active subscription": {
+			sub: Subscription{Status: SubscriptionStatusActive, StartedAt: started},
+		},
+		"should not end a cancelled subscription before its period ends": {
+			sub: Subscription{Status: SubscriptionStatusCancelled, StartedAt: started, CurrentPeriodEnd: &future},
+		},
+		"should end a cancelled subscription at its period end": {
+			sub:           Subscription{Status: SubscriptionStatusCancelled, StartedAt: started, CurrentPeriodEnd: &past, CancelledAt: &cancelledAt},
+			expectedEnd:   past,
+			expectedEnded: true,
+		},
+		"should end an expired subscription without period at its cancellation": {
+			sub:           Subscription{Status: SubscriptionStatusExpired, StartedAt: started, CancelledAt: &cancelledAt},
+			expectedEnd:   cancelledAt,
+			expectedEnded: true,
+		},
+		"should end a past due subscription when its grace ended": {
+			sub:           Subscription{Status: SubscriptionStatusPastDue, StartedAt: started, CurrentPeriodEnd: &future, GraceEndsAt: &past},
+			expectedEnd:   past,
+			expectedEnded: true,
+		},
+		"should end a past due subscription without recorded grace after the configured grace": {
+			sub:           Subscription{Status: SubscriptionStatusPastDue, StartedAt: started, CurrentPeriodEnd: &longPast},
+			expectedEnd:   longPast.Add(grace),
+			expectedEnded: true,
+		},
+		"should not end a pending subscription": {
+			sub: Subscription{Status: SubscriptionStatusPending, StartedAt: started},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			end, ended := tt.sub.EndedAt(now, grace)
+
+			assert.Equal(t, tt.expectedEnded, ended)
+			assert.Equal(t, tt.expectedEnd, end)
+		})
+	}
+}
@@ -6,17 +6,41 @@ import (
 	"github.com/google/uuid"
 )
 
+type WalletType string
+
+const (
+	WalletTypeChecking   WalletType = "checking"
+	WalletTypeSavings    WalletType = "savings"
+	WalletTypeCash       WalletType = "cash"
+	WalletTypeBenefit    WalletType = "benefit"
+	WalletTypeInvestment WalletType = "investment"
+)
+
+func (t WalletType) IsValid() bool {
+	switch t {
+	case WalletTypeChecking, WalletTypeSavings, WalletTypeCash, WalletTypeBenefit, WalletTypeInvestment:
+		return true
+	}
+	return false
+}
+
 type Wallet struct {
 	ID             *uuid.UUID `json:"id,omitempty" gorm:"primaryKey"`
 	Description    string     `json:"description,omitempty"`
+	Type           WalletType `json:"type,omitempty"`
 	Balance        float64    `json:"balance"`
 	UserID         string     `json:"user_id"`
 	InitialBalance float64    `json:"initial_balance"`
 	InitialDate    time.Time  `json:"initial_date"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
 	DateCreate     time.Time  `json:"date_create"`
 	DateUpdate     time.Time  `json:"date_update"`
 }
 
+func (w *Wallet) IsArchived() bool {
+	return w.ArchivedAt != nil
+}
+
 func (w *Wallet) HasSufficientBalance(amount float64) bool {
 	if amount >= 0 {
 		return true
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+type BalanceGranularity string
+
+const (
+	BalanceGranularityDay   BalanceGranularity = "day"
+	BalanceGranularityMonth BalanceGranularity = "month"
+)
+
+func (g BalanceGranularity) IsValid() bool {
+	return g == BalanceGranularityDay || g == BalanceGranularityMonth
+}
+
+// WalletBalanceEntry é o valor de uma movimentação paga na data em que afetou o saldo.
+type WalletBalanceEntry struct {
+	Date   time.Time `json:"date"`
+	Amount float64   `json:"amount"`
+}
+
+type WalletBalancePoint struct {
+	Date    time.Time `json:"date"`
+	Balance float64   `json:"balance"`
+}
+
+// BuildBalanceHistory calcula o saldo de fechamento de cada dia (ou mês) entre from e to,
+// partindo de initialBalance e somando as entradas até o fim de cada período. O último
+// período fecha no dia to, mesmo que o mês continue.
+func BuildBalanceHistory(
+	initialBalance float64,
+	entries []WalletBalanceEntry,
+	from, to time.Time,
+	granularity BalanceGranularity,
+) []WalletBalancePoint {
+	sorted := make([]WalletBalanceEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	start := truncateToGranularity(from, granularity)
+	limit := nextPeriod(truncateToGranularity(to, BalanceGranularityDay), BalanceGranularityDay)
+	balance := initialBalance
+	next := 0
+
+	var points []WalletBalancePoint
+	for period := start; !period.After(to); period = nextPeriod(period, granularity) {
+		end := nextPeriod(period, granularity)
+		if end.After(limit) {
+			end = limit
+		}
+		for next < len(sorted) && sorted[next].Date.Before(end) {
+			balance += sorted[next].Amount
+			next++
+		}
+
+		points = append(points, WalletBalancePoint{
+			Date:    period,
+			Balance: balance,
+		})
+	}
+
+	return points
+}
+
+func truncateToGranularity(date time.Time, granularity BalanceGranularity) time.Time {
+	if granularity == BalanceGranularityMonth {
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+func nextPeriod(date time.Time, granularity BalanceGranularity) time.Time {
+	if granularity == BalanceGranularityMonth {
+		return date.AddDate(0, 1, 0)
+	}
+	return date.AddDate(0, 0, 1)
+}
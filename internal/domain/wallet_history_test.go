@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildBalanceHistory(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	entries := []WalletBalanceEntry{
+		{Date: day(time.March, 2).Add(15 * time.Hour), Amount: -30},
+		{Date: day(time.February, 20), Amount: 200},
+		{Date: day(time.March, 1).Add(9 * time.Hour), Amount: 50},
+		{Date: day(time.April, 10), Amount: -20},
+	}
+
+	tests := map[string]struct {
+		from, to    time.Time
+		granularity BalanceGranularity
+		expected    []WalletBalancePoint
+	}{
+		"should carry entries before from into the first day": {
+			from:        day(time.March, 1),
+			to:          day(time.March, 3),
+			granularity: BalanceGranularityDay,
+			expected: []WalletBalancePoint{
+				{Date: day(time.March, 1), Balance: 1250},
+				{Date: day(time.March, 2), Balance: 1220},
+				{Date: day(time.March, 3), Balance: 1220},
+			},
+		},
+		"should close each month with its entries": {
+			from:        day(time.February, 15),
+			to:          day(time.April, 5),
+			granularity: BalanceGranularityMonth,
+			expected: []WalletBalancePoint{
+				{Date: day(time.February, 1), Balance: 1200},
+				{Date: day(time.March, 1), Balance: 1220},
+				{Date: day(time.April, 1), Balance: 1220},
+			},
+		},
+		"should keep initial balance when there are no entries until to": {
+			from:        day(time.January, 1),
+			to:          day(time.January, 2),
+			granularity: BalanceGranularityDay,
+			expected: []WalletBalancePoint{
+				{Date: day(time.January, 1), Balance: 1000},
+				{Date: day(time.January, 2), Balance: 1000},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := BuildBalanceHistory(1000, entries, tt.from, tt.to, tt.granularity)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
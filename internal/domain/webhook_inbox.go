@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookProvider string
+
+const (
+	WebhookProviderMercadoPago WebhookProvider = "mercadopago"
+	WebhookProviderStripe      WebhookProvider = "stripe"
+	WebhookProviderRevenueCat  WebhookProvider = "revenuecat"
+)
+
+type WebhookEventStatus string
+
+const (
+	WebhookEventStatusPending   WebhookEventStatus = "pending"
+	WebhookEventStatusProcessed WebhookEventStatus = "processed"
+	WebhookEventStatusDead      WebhookEventStatus = "dead"
+)
+
+const (
+	// WebhookMaxAttempts is how many times an event is processed before it is
+	// dead-lettered.
+	WebhookMaxAttempts     = 8
+	WebhookRetryBaseDelay  = time.Minute
+	WebhookRetryMaxDelay   = 6 * time.Hour
+	WebhookProcessingLease = 5 * time.Minute
+)
+
+// WebhookInboxEvent is a verified webhook as received from a billing provider.
+// The provider event ID deduplicates deliveries of the same event.
+type WebhookInboxEvent struct {
+	ID            uuid.UUID          `json:"id"`
+	Provider      WebhookProvider    `json:"provider"`
+	EventID       string             `json:"event_id"`
+	EventType     string             `json:"event_type"`
+	Payload       json.RawMessage    `json:"payload"`
+	Status        WebhookEventStatus `json:"status"`
+	Attempts      int                `json:"attempts"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	LastError     string             `json:"last_error,omitempty"`
+	ReceivedAt    time.Time          `json:"received_at"`
+	ProcessedAt   *time.Time         `json:"processed_at,omitempty"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// NewWebhookInboxEvent builds a pending event due now. Without an event ID from
+// the provider, the payload hash is used so identical deliveries still collapse.
+func NewWebhookInboxEvent(provider WebhookProvider, eventID, eventType string, payload []byte, now time.Time) WebhookInboxEvent {
+	if eventID == "" {
+		sum := sha256.Sum256(payload)
+		eventID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return WebhookInboxEvent{
+		ID:            uuid.New(),
+		Provider:      provider,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        WebhookEventStatusPending,
+		NextAttemptAt: now,
+		ReceivedAt:    now,
+		UpdatedAt:     now,
+	}
+}
+
+// WebhookRetryDelay is the wait after the given failed attempt: it doubles from
+// WebhookRetryBaseDelay up to WebhookRetryMaxDelay.
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := WebhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= WebhookRetryMaxDelay {
+			return WebhookRetryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := map[string]struct {
+		attempts int
+		expected time.Duration
+	}{
+		"should wait the base delay after the first attempt": {attempts: 1, expected: time.Minute},
+		"should double the delay on each attempt":            {attempts: 4, expected: 8 * time.Minute},
+		"should cap the delay":                               {attempts: 20, expected: 6 * time.Hour},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, WebhookRetryDelay(tt.attempts))
+		})
+	}
+}
+
+func TestNewWebhookInboxEvent(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should keep the provider event id", func(t *testing.T) {
+		event := NewWebhookInboxEvent(WebhookProviderStripe, "evt_123", "customer.subscription.updated", []byte(`{}`), now)
+
+		assert.Equal(t, "evt_123", event.EventID)
+		assert.Equal(t, WebhookEventStatusPending, event.Status)
+		assert.Equal(t, now, event.NextAttemptAt)
+	})
+
+	t.Run("should derive the id from the payload when the provider sends none", func(t *testing.T) {
+		first := NewWebhookInboxEvent(WebhookProviderMercadoPago, "", "preapproval", []byte(`{"data":{"id":"sub-1"}}`), now)
+		again := NewWebhookInboxEvent(WebhookProviderMercadoPago, "", "preapproval", []byte(`{"data":{"id":"sub-1"}}`), now)
+		other := NewWebhookInboxEvent(WebhookProviderMercadoPago, "", "preapproval", []byte(`{"data":{"id":"sub-2"}}`), now)
+
+		assert.Equal(t, first.EventID, again.EventID)
+		assert.NotEqual(t, first.EventID, other.EventID)
+	})
+}
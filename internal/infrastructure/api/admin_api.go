@@ -61,6 +61,9 @@ type (
 		Frequency     int     `json:"frequency" binding:"required,gt=0"`
 		FrequencyType string  `json:"frequency_type" binding:"required"`
 		IsActive      bool    `json:"is_active"`
+		TrialDays     int     `json:"trial_days" binding:"gte=0"`
+		// Entitlements is optional; without it the plan uses the Plus defaults.
+		Entitlements *domain.Entitlements `json:"entitlements,omitempty"`
 	}
 )
 
@@ -116,6 +119,8 @@ func (h SubscriptionPlanAdminHandler) CreatePlan() gin.HandlerFunc {
 			Frequency:     req.Frequency,
 			FrequencyType: req.FrequencyType,
 			IsActive:      req.IsActive,
+			TrialDays:     req.TrialDays,
+			Entitlements:  req.Entitlements,
 		}
 
 		if err := h.usecase.CreatePlan(ctx, plan); err != nil {
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"personal-finance/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	AgentActionUseCase interface {
+		ListPending(ctx context.Context) ([]domain.AgentAction, error)
+		Confirm(ctx context.Context, id uuid.UUID) (domain.AgentAction, error)
+		Reject(ctx context.Context, id uuid.UUID) (domain.AgentAction, error)
+	}
+
+	AgentActionHandler struct {
+		usecase AgentActionUseCase
+	}
+)
+
+func NewAgentActionHandlers(r *gin.Engine, srv AgentActionUseCase) {
+	handler := AgentActionHandler{usecase: srv}
+
+	actionGroup := r.Group("/agent/actions")
+
+	actionGroup.GET("", handler.ListPending())
+	actionGroup.POST("/:id/confirm", handler.Confirm())
+	actionGroup.POST("/:id/reject", handler.Reject())
+}
+
+func (h AgentActionHandler) ListPending() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		actions, err := h.usecase.ListPending(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, actions)
+	}
+}
+
+func (h AgentActionHandler) Confirm() gin.HandlerFunc {
+	return h.resolve(h.usecase.Confirm)
+}
+
+func (h AgentActionHandler) Reject() gin.HandlerFunc {
+	return h.resolve(h.usecase.Reject)
+}
+
+func (h AgentActionHandler) resolve(fn func(ctx context.Context, id uuid.UUID) (domain.AgentAction, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be a valid UUID"))
+			return
+		}
+
+		action, err := fn(ctx, id)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, action)
+	}
+}
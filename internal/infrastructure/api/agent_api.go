@@ -6,6 +6,7 @@ import (
 
 	"personal-finance/internal/domain"
 	"personal-finance/internal/usecase"
+	"personal-finance/pkg/log"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ import (
 type (
 	AgentUseCase interface {
 		Chat(ctx context.Context, input usecase.AgentChatInput) (usecase.AgentChatOutput, error)
+		ChatStream(ctx context.Context, input usecase.AgentChatInput, emit domain.AgentStreamEmitter) (usecase.AgentChatOutput, error)
 		SaveMemory(ctx context.Context, input usecase.SaveMemoryInput) (domain.AgentMemory, error)
 		DeleteMemory(ctx context.Context, id uuid.UUID) error
 		UpdateMemory(ctx context.Context, id uuid.UUID, content string, metadata map[string]any) (domain.AgentMemory, error)
@@ -36,8 +38,9 @@ type (
 	}
 
 	AgentChatResponse struct {
-		ConversationID string `json:"conversation_id"`
-		Response       string `json:"response"`
+		ConversationID string               `json:"conversation_id"`
+		Response       string               `json:"response"`
+		PendingActions []domain.AgentAction `json:"pending_actions,omitempty"`
 	}
 
 	AgentSaveMemoryRequest struct {
@@ -56,13 +59,15 @@ type (
 	}
 )
 
-func NewAgentHandlers(r *gin.Engine, srv AgentUseCase) {
+func NewAgentHandlers(r *gin.Engine, srv AgentUseCase, featureGate gin.HandlerFunc) {
 	handler := AgentHandler{usecase: srv}
 
 	agentGroup := r.Group("/agent")
+	agentGroup.Use(featureGate)
 
 	// Chat
 	agentGroup.POST("/chat", handler.Chat())
+	agentGroup.POST("/chat/stream", handler.ChatStream())
 
 	// Conversations
 	agentGroup.GET("/conversations", handler.ListConversations())
@@ -88,25 +93,12 @@ func (h AgentHandler) Chat() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 
-		var req AgentChatRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+		input, err := bindAgentChatInput(c)
+		if err != nil {
+			HandleErr(c, ctx, err)
 			return
 		}
 
-		input := usecase.AgentChatInput{
-			Message: req.Message,
-		}
-
-		if req.ConversationID != nil && *req.ConversationID != "" {
-			convID, err := uuid.Parse(*req.ConversationID)
-			if err != nil {
-				HandleErr(c, ctx, domain.WrapInvalidInput(err, "conversation_id must be a valid UUID"))
-				return
-			}
-			input.ConversationID = &convID
-		}
-
 		output, err := h.usecase.Chat(ctx, input)
 		if err != nil {
 			HandleErr(c, ctx, err)
@@ -116,10 +108,74 @@ func (h AgentHandler) Chat() gin.HandlerFunc {
 		c.JSON(http.StatusOK, AgentChatResponse{
 			ConversationID: output.ConversationID.String(),
 			Response:       output.Response,
+			PendingActions: output.PendingActions,
 		})
 	}
 }
 
+// ChatStream answers with Server-Sent Events. Errors raised before the first event
+// are returned as regular JSON responses; after that they become an error event.
+func (h AgentHandler) ChatStream() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		input, err := bindAgentChatInput(c)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		started := false
+		emit := func(event domain.AgentStreamEvent) {
+			if !started {
+				c.Header("Content-Type", "text/event-stream")
+				c.Header("Cache-Control", "no-cache")
+				c.Header("Connection", "keep-alive")
+				c.Header("X-Accel-Buffering", "no")
+				c.Status(http.StatusOK)
+				started = true
+			}
+			c.SSEvent(string(event.Type), event)
+			c.Writer.Flush()
+		}
+
+		_, err = h.usecase.ChatStream(ctx, input, emit)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		if !started {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		log.ErrorContext(ctx, "error handled", log.Err(err))
+		c.SSEvent(string(domain.AgentStreamEventError), toAPIError(err).Error)
+		c.Writer.Flush()
+	}
+}
+
+func bindAgentChatInput(c *gin.Context) (usecase.AgentChatInput, error) {
+	var req AgentChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return usecase.AgentChatInput{}, domain.WrapInvalidInput(err, "invalid json body")
+	}
+
+	input := usecase.AgentChatInput{
+		Message: req.Message,
+	}
+
+	if req.ConversationID != nil && *req.ConversationID != "" {
+		convID, err := uuid.Parse(*req.ConversationID)
+		if err != nil {
+			return usecase.AgentChatInput{}, domain.WrapInvalidInput(err, "conversation_id must be a valid UUID")
+		}
+		input.ConversationID = &convID
+	}
+
+	return input, nil
+}
+
 // --- Conversations ---
 
 func (h AgentHandler) ListConversations() gin.HandlerFunc {
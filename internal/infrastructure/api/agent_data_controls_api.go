@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"personal-finance/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	AgentDataControlsUseCase interface {
+		ListAudit(ctx context.Context) ([]domain.AgentAuditConversation, error)
+		DeleteConversation(ctx context.Context, id uuid.UUID) error
+		DeleteAllMemories(ctx context.Context) error
+		GetSettings(ctx context.Context) (domain.AgentSettings, error)
+		SetSettings(ctx context.Context, memoryEnabled bool) (domain.AgentSettings, error)
+	}
+
+	AgentDataControlsHandler struct {
+		usecase AgentDataControlsUseCase
+	}
+
+	SetAgentSettingsRequest struct {
+		MemoryEnabled *bool `json:"memory_enabled" binding:"required"`
+	}
+)
+
+func NewAgentDataControlsHandlers(r *gin.Engine, srv AgentDataControlsUseCase) {
+	handler := AgentDataControlsHandler{usecase: srv}
+
+	agentGroup := r.Group("/agent")
+	agentGroup.GET("/audit", handler.ListAudit())
+	agentGroup.DELETE("/conversations/:id", handler.DeleteConversation())
+	agentGroup.DELETE("/memories", handler.DeleteAllMemories())
+	agentGroup.GET("/settings", handler.GetSettings())
+	agentGroup.PUT("/settings", handler.SetSettings())
+}
+
+func (h AgentDataControlsHandler) ListAudit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		summaries, err := h.usecase.ListAudit(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, summaries)
+	}
+}
+
+func (h AgentDataControlsHandler) DeleteConversation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be a valid UUID"))
+			return
+		}
+
+		if err := h.usecase.DeleteConversation(ctx, id); err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (h AgentDataControlsHandler) DeleteAllMemories() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if err := h.usecase.DeleteAllMemories(ctx); err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (h AgentDataControlsHandler) GetSettings() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		settings, err := h.usecase.GetSettings(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+func (h AgentDataControlsHandler) SetSettings() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var req SetAgentSettingsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		settings, err := h.usecase.SetSettings(ctx, *req.MemoryEnabled)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, settings)
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	AgentInsightsUseCase interface {
+		GetPreference(ctx context.Context) (domain.InsightPreference, error)
+		SetPreference(ctx context.Context, enabled bool) (domain.InsightPreference, error)
+		SendWeeklyDigests(ctx context.Context, now time.Time) (usecase.InsightJobResult, error)
+	}
+
+	AgentInsightsHandler struct {
+		usecase AgentInsightsUseCase
+	}
+
+	SetInsightPreferenceRequest struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+
+	InsightJobResponse struct {
+		usecase.InsightJobResult
+		Date string `json:"date"`
+	}
+)
+
+func NewAgentInsightsHandlers(r *gin.Engine, srv AgentInsightsUseCase) {
+	handler := AgentInsightsHandler{usecase: srv}
+
+	agentGroup := r.Group("/agent")
+	agentGroup.GET("/insights/preference", handler.GetPreference())
+	agentGroup.PUT("/insights/preference", handler.SetPreference())
+}
+
+func NewAgentInsightsJobHandlers(jobsGroup *gin.RouterGroup, srv AgentInsightsUseCase) {
+	handler := AgentInsightsHandler{usecase: srv}
+
+	jobsGroup.POST("/agent/weekly-insights", handler.SendWeeklyDigests())
+}
+
+func (h AgentInsightsHandler) GetPreference() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		pref, err := h.usecase.GetPreference(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, pref)
+	}
+}
+
+func (h AgentInsightsHandler) SetPreference() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var req SetInsightPreferenceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		pref, err := h.usecase.SetPreference(ctx, *req.Enabled)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, pref)
+	}
+}
+
+func (h AgentInsightsHandler) SendWeeklyDigests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		now := time.Now().UTC()
+		if dateStr := c.Query("date"); dateStr != "" {
+			parsedDate, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid date format, use YYYY-MM-DD"))
+				return
+			}
+			now = parsedDate
+		}
+
+		result, err := h.usecase.SendWeeklyDigests(ctx, now)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, InsightJobResponse{
+			InsightJobResult: result,
+			Date:             now.Format("2006-01-02"),
+		})
+	}
+}
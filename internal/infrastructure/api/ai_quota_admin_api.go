@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	AIQuotaAdminUseCase interface {
+		GetOverride(ctx context.Context, userID string) (domain.AIQuotaOverride, error)
+		SetOverride(ctx context.Context, override domain.AIQuotaOverride) (domain.AIQuotaOverride, error)
+		DeleteOverride(ctx context.Context, userID string) error
+	}
+
+	AIQuotaAdminHandler struct {
+		usecase AIQuotaAdminUseCase
+	}
+
+	// SetAIQuotaOverrideRequest replaces the plan AI quota of a user. Zero means unlimited.
+	SetAIQuotaOverrideRequest struct {
+		TokensPerMonth   int        `json:"tokens_per_month"`
+		RequestsPerMonth int        `json:"requests_per_month"`
+		Reason           string     `json:"reason"`
+		ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	}
+)
+
+func NewAIQuotaAdminHandlers(r *gin.Engine, srv AIQuotaAdminUseCase) {
+	handler := AIQuotaAdminHandler{usecase: srv}
+
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(authentication.AdminAuth())
+
+	adminGroup.GET("/users/:id/ai-quota", handler.Get())
+	adminGroup.PUT("/users/:id/ai-quota", handler.Set())
+	adminGroup.DELETE("/users/:id/ai-quota", handler.Delete())
+}
+
+func (h AIQuotaAdminHandler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		override, err := h.usecase.GetOverride(ctx, c.Param("id"))
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, override)
+	}
+}
+
+func (h AIQuotaAdminHandler) Set() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var req SetAIQuotaOverrideRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		override, err := h.usecase.SetOverride(ctx, domain.AIQuotaOverride{
+			UserID:           c.Param("id"),
+			TokensPerMonth:   req.TokensPerMonth,
+			RequestsPerMonth: req.RequestsPerMonth,
+			Reason:           req.Reason,
+			ExpiresAt:        req.ExpiresAt,
+		})
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, override)
+	}
+}
+
+func (h AIQuotaAdminHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if err := h.usecase.DeleteOverride(ctx, c.Param("id")); err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	CouponCampaignAdminUseCase interface {
+		CreateCampaign(ctx context.Context, campaign domain.CouponCampaign) (string, error)
+		ListCampaigns(ctx context.Context) ([]domain.CouponCampaign, error)
+		GetCampaign(ctx context.Context, id string) (usecase.CouponCampaignSummary, error)
+		GenerateCodes(ctx context.Context, campaignID string, quantity int) (int, error)
+		ListCampaignCodes(ctx context.Context, campaignID string) ([]domain.CouponCampaignCode, error)
+	}
+
+	CouponCampaignAdminHandler struct {
+		usecase CouponCampaignAdminUseCase
+	}
+
+	CreateCouponCampaignRequest struct {
+		ID             string `json:"id" binding:"required"`
+		Name           string `json:"name" binding:"required"`
+		CouponID       string `json:"coupon_id" binding:"required"`
+		StripeCouponID string `json:"stripe_coupon_id"`
+		CodePrefix     string `json:"code_prefix"`
+	}
+
+	GenerateCouponCodesRequest struct {
+		Quantity int `json:"quantity" binding:"required,gt=0"`
+	}
+)
+
+func NewCouponCampaignAdminHandlers(r *gin.Engine, srv CouponCampaignAdminUseCase) {
+	handler := CouponCampaignAdminHandler{usecase: srv}
+
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(authentication.AdminAuth())
+
+	adminGroup.POST("/coupon-campaigns", handler.Create())
+	adminGroup.GET("/coupon-campaigns", handler.List())
+	adminGroup.GET("/coupon-campaigns/:id", handler.Get())
+	adminGroup.POST("/coupon-campaigns/:id/codes", handler.GenerateCodes())
+	adminGroup.GET("/coupon-campaigns/:id/codes", handler.ListCodes())
+}
+
+func (h CouponCampaignAdminHandler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var req CreateCouponCampaignRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		campaign := domain.CouponCampaign{
+			ID:             req.ID,
+			Name:           req.Name,
+			CouponID:       req.CouponID,
+			StripeCouponID: req.StripeCouponID,
+			CodePrefix:     req.CodePrefix,
+		}
+		disabledSharedCode, err := h.usecase.CreateCampaign(ctx, campaign)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{
+			"message":              "coupon campaign created successfully",
+			"disabled_shared_code": disabledSharedCode,
+		})
+	}
+}
+
+func (h CouponCampaignAdminHandler) List() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		campaigns, err := h.usecase.ListCampaigns(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusOK, campaigns)
+	}
+}
+
+func (h CouponCampaignAdminHandler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		summary, err := h.usecase.GetCampaign(ctx, c.Param("id"))
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+func (h CouponCampaignAdminHandler) GenerateCodes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var req GenerateCouponCodesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		created, err := h.usecase.GenerateCodes(ctx, c.Param("id"), req.Quantity)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"created": created})
+	}
+}
+
+// ListCodes returns the codes of the campaign as JSON, or as CSV with
+// format=csv to hand them out to the partner.
+func (h CouponCampaignAdminHandler) ListCodes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		campaignID := c.Param("id")
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "csv" {
+			HandleErr(c, ctx, domain.WrapInvalidInput(domain.New("format must be json or csv"), "coupon codes format"))
+			return
+		}
+
+		codes, err := h.usecase.ListCampaignCodes(ctx, campaignID)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		if format == "json" {
+			c.JSON(http.StatusOK, codes)
+			return
+		}
+
+		filename := fmt.Sprintf("coupon-codes-%s.csv", campaignID)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+
+		w := csv.NewWriter(c.Writer)
+		_ = w.Write([]string{"code", "status", "redeemed_by", "redeemed_at"})
+		for _, code := range codes {
+			redeemedAt := ""
+			if code.RedeemedAt != nil {
+				redeemedAt = code.RedeemedAt.UTC().Format(time.RFC3339)
+			}
+			_ = w.Write([]string{code.Code, string(code.Status), code.RedeemedBy, redeemedAt})
+		}
+		w.Flush()
+	}
+}
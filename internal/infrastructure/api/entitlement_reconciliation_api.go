@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	EntitlementReconciliationUseCase interface {
+		Reconcile(ctx context.Context, now time.Time, dryRun bool) (usecase.EntitlementReconciliationResult, error)
+	}
+
+	EntitlementReconciliationHandler struct {
+		usecase EntitlementReconciliationUseCase
+	}
+)
+
+func NewEntitlementReconciliationJobHandlers(jobsGroup *gin.RouterGroup, srv EntitlementReconciliationUseCase) {
+	handler := EntitlementReconciliationHandler{usecase: srv}
+
+	jobsGroup.POST("/entitlements/reconcile", handler.Reconcile())
+}
+
+func (h EntitlementReconciliationHandler) Reconcile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		dryRun := c.Query("dry_run") == "true"
+
+		result, err := h.usecase.Reconcile(ctx, time.Now(), dryRun)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	// FeatureGate tells whether the plan of the current user includes a feature.
+	FeatureGate interface {
+		CheckFeature(ctx context.Context, feature domain.Feature) error
+	}
+
+	PlanEntitlementsAdminUseCase interface {
+		GetPlan(ctx context.Context, planID string) (domain.SubscriptionPlan, error)
+		SetPlanEntitlements(ctx context.Context, planID string, ent *domain.Entitlements) (domain.SubscriptionPlan, error)
+	}
+
+	PlanEntitlementsAdminHandler struct {
+		usecase PlanEntitlementsAdminUseCase
+	}
+)
+
+// RequireFeature gates a whole route group: requests are answered with 403 when
+// the plan of the user turns the feature off.
+func RequireFeature(gate FeatureGate, feature domain.Feature) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if err := gate.CheckFeature(ctx, feature); err != nil {
+			HandleErr(c, ctx, err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func NewPlanEntitlementsAdminHandlers(r *gin.Engine, srv PlanEntitlementsAdminUseCase) {
+	handler := PlanEntitlementsAdminHandler{usecase: srv}
+
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(authentication.AdminAuth())
+
+	adminGroup.GET("/subscription-plans/:id", handler.GetPlan())
+	adminGroup.PUT("/subscription-plans/:id/entitlements", handler.SetEntitlements())
+	adminGroup.DELETE("/subscription-plans/:id/entitlements", handler.ResetEntitlements())
+}
+
+func (h PlanEntitlementsAdminHandler) GetPlan() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		plan, err := h.usecase.GetPlan(ctx, c.Param("id"))
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+func (h PlanEntitlementsAdminHandler) SetEntitlements() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var req domain.Entitlements
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		plan, err := h.usecase.SetPlanEntitlements(ctx, c.Param("id"), &req)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+func (h PlanEntitlementsAdminHandler) ResetEntitlements() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		plan, err := h.usecase.SetPlanEntitlements(ctx, c.Param("id"), nil)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
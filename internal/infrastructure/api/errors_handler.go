@@ -38,7 +38,17 @@ func toAPIError(err error) errorResponse {
 		domain.Is(err, repository.ErrCategoryNotFound),
 		domain.Is(err, repository.ErrSubCategoryNotFound),
 		domain.Is(err, repository.ErrDeviceNotFound),
-		domain.Is(err, usecase.ErrSubscriptionPlanNotFound):
+		domain.Is(err, repository.ErrAIQuotaOverrideNotFound),
+		domain.Is(err, repository.ErrWebhookEventNotFound),
+		domain.Is(err, domain.ErrCouponCampaignNotFound),
+		domain.Is(err, domain.ErrAccessTokenNotFound),
+		domain.Is(err, domain.ErrHouseholdNotFound),
+		domain.Is(err, domain.ErrHouseholdMemberNotFound),
+		domain.Is(err, domain.ErrHouseholdInvitationNotFound),
+		domain.Is(err, domain.ErrHouseholdShareNotFound),
+		domain.Is(err, usecase.ErrSubscriptionPlanNotFound),
+		domain.Is(err, repository.ErrSubscriptionPlanNotFound),
+		domain.Is(err, usecase.ErrTransferNotFound):
 		return newErrorResponse(http.StatusNotFound, "Resource not found")
 
 	case domain.Is(err, domain.ErrInvalidInput),
@@ -54,7 +64,16 @@ func toAPIError(err error) errorResponse {
 		return newErrorResponse(http.StatusBadRequest, "Invalid data provided")
 
 	case domain.Is(err, usecase.ErrInvalidFrequencyType),
-		domain.Is(err, usecase.ErrCreditCardNoDefaultWallet):
+		domain.Is(err, usecase.ErrCreditCardNoDefaultWallet),
+		domain.Is(err, usecase.ErrInvalidTransferEndDate),
+		domain.Is(err, usecase.ErrTransferNotRecurrent),
+		domain.Is(err, usecase.ErrTransferPairInconsistent),
+		domain.Is(err, usecase.ErrInvalidWalletType),
+		domain.Is(err, usecase.ErrInvalidBalanceGranularity),
+		domain.Is(err, usecase.ErrInvalidHistoryPeriod),
+		domain.Is(err, usecase.ErrHistoryPeriodTooLong),
+		domain.Is(err, usecase.ErrInvalidAIQuota),
+		domain.Is(err, domain.ErrHouseholdInvitationInvalid):
 		return newErrorResponse(http.StatusBadRequest, err.Error())
 
 	case domain.Is(err, domain.ErrUnauthorized),
@@ -65,9 +84,17 @@ func toAPIError(err error) errorResponse {
 		domain.Is(err, usecase.ErrWalletLimitReached),
 		domain.Is(err, usecase.ErrCreditCardLimitReached),
 		domain.Is(err, usecase.ErrMovementLimitReached),
-		domain.Is(err, usecase.ErrRecurrenceLimitReached):
+		domain.Is(err, usecase.ErrRecurrenceLimitReached),
+		domain.Is(err, domain.ErrAccessTokenLimitReached),
+		domain.Is(err, domain.ErrHouseholdForbidden):
 		return newErrorResponse(http.StatusForbidden, err.Error())
 
+	case domain.Is(err, usecase.ErrFeatureNotInPlan):
+		return newErrorResponseTyped(http.StatusForbidden, err.Error(), "feature_not_in_plan")
+
+	case domain.Is(err, usecase.ErrAIQuotaExceeded):
+		return newErrorResponseTyped(http.StatusTooManyRequests, err.Error(), "ai_quota_exceeded")
+
 	case domain.Is(err, usecase.ErrMPCrossCountry):
 		return newErrorResponse(http.StatusUnprocessableEntity, "Mercado Pago subscriptions are only available for accounts registered in the same country as our payment provider. Please use another payment method.")
 
@@ -80,6 +107,11 @@ func toAPIError(err error) errorResponse {
 		domain.Is(err, repository.ErrDuplicateWallet):
 		return newErrorResponse(http.StatusConflict, "Resource conflict")
 
+	case domain.Is(err, usecase.ErrTransferMovementChange),
+		domain.Is(err, domain.ErrHouseholdAlreadyMember),
+		domain.Is(err, domain.ErrCouponCodeAlreadyUsed):
+		return newErrorResponse(http.StatusConflict, err.Error())
+
 	case domain.Is(err, domain.ErrAgentMemoryCapExceeded):
 		return newErrorResponse(http.StatusUnprocessableEntity, "Memory limit reached. Delete stale memories first.")
 
@@ -92,6 +124,16 @@ func toAPIError(err error) errorResponse {
 	case domain.Is(err, domain.ErrAgentMemoryNotFound):
 		return newErrorResponse(http.StatusNotFound, "Agent memory not found")
 
+	case domain.Is(err, domain.ErrAgentActionNotFound):
+		return newErrorResponse(http.StatusNotFound, "Agent action not found")
+
+	case domain.Is(err, domain.ErrAgentInvalidAction):
+		return newErrorResponse(http.StatusBadRequest, err.Error())
+
+	case domain.Is(err, domain.ErrAgentActionNotPending),
+		domain.Is(err, domain.ErrAgentActionExpired):
+		return newErrorResponse(http.StatusConflict, err.Error())
+
 	case domain.Is(err, domain.ErrStatementPasswordRequired):
 		return newErrorResponseTyped(http.StatusUnprocessableEntity,
 			"This PDF is password protected. Please provide the password.",
@@ -0,0 +1,317 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/usecase"
+	"personal-finance/pkg/log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	HouseholdUseCase interface {
+		Create(ctx context.Context, name string) (domain.Household, error)
+		List(ctx context.Context) ([]domain.Household, error)
+		Delete(ctx context.Context, householdID uuid.UUID) error
+		Invite(ctx context.Context, householdID uuid.UUID, input usecase.InviteHouseholdMemberInput) (usecase.CreatedHouseholdInvitation, error)
+		ListInvitations(ctx context.Context, householdID uuid.UUID) ([]domain.HouseholdInvitation, error)
+		RevokeInvitation(ctx context.Context, householdID, invitationID uuid.UUID) error
+		AcceptInvitation(ctx context.Context, token string) (domain.Household, error)
+		UpdateMemberRole(ctx context.Context, householdID uuid.UUID, memberID string, role domain.HouseholdRole) error
+		RemoveMember(ctx context.Context, householdID uuid.UUID, memberID string) error
+		Share(ctx context.Context, householdID uuid.UUID, input usecase.HouseholdShareInput) (domain.HouseholdShare, error)
+		Unshare(ctx context.Context, householdID uuid.UUID, resource domain.HouseholdResource, resourceID uuid.UUID) error
+	}
+
+	HouseholdMembershipsUseCase interface {
+		Memberships(ctx context.Context) ([]domain.HouseholdMembership, error)
+	}
+
+	HouseholdHandler struct {
+		usecase HouseholdUseCase
+	}
+
+	CreateHouseholdRequest struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	InviteHouseholdMemberRequest struct {
+		Email string `json:"email" binding:"required"`
+		Role  string `json:"role" binding:"required"`
+	}
+
+	AcceptHouseholdInvitationRequest struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	UpdateHouseholdMemberRequest struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	ShareHouseholdResourceRequest struct {
+		ResourceType string    `json:"resource_type" binding:"required"`
+		ResourceID   uuid.UUID `json:"resource_id" binding:"required"`
+	}
+)
+
+func NewHouseholdHandlers(r *gin.Engine, srv HouseholdUseCase) {
+	handler := HouseholdHandler{usecase: srv}
+
+	group := r.Group("/v2/households")
+
+	group.POST("", handler.Create())
+	group.GET("", handler.List())
+	group.DELETE("/:id", handler.Delete())
+	group.POST("/invitations/accept", handler.AcceptInvitation())
+	group.POST("/:id/invitations", handler.Invite())
+	group.GET("/:id/invitations", handler.ListInvitations())
+	group.DELETE("/:id/invitations/:invitation_id", handler.RevokeInvitation())
+	group.PUT("/:id/members/:user_id", handler.UpdateMember())
+	group.DELETE("/:id/members/:user_id", handler.RemoveMember())
+	group.POST("/:id/shares", handler.Share())
+	group.DELETE("/:id/shares/:resource_type/:resource_id", handler.Unshare())
+}
+
+// HouseholdScope loads the households of the user into the request context,
+// so the queries also reach the resources shared with them. When they cannot
+// be loaded the request goes on with the user's own data only.
+func HouseholdScope(srv HouseholdMembershipsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		memberships, err := srv.Memberships(ctx)
+		if err != nil {
+			log.WarnContext(ctx, "error loading household memberships", log.Err(err))
+			return
+		}
+		if len(memberships) > 0 {
+			c.Request = c.Request.WithContext(repository.ContextWithHouseholds(ctx, memberships))
+		}
+	}
+}
+
+func (h HouseholdHandler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var req CreateHouseholdRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		household, err := h.usecase.Create(ctx, req.Name)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusCreated, household)
+	}
+}
+
+func (h HouseholdHandler) List() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		households, err := h.usecase.List(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusOK, households)
+	}
+}
+
+func (h HouseholdHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		householdID, ok := householdIDParam(c)
+		if !ok {
+			return
+		}
+
+		if err := h.usecase.Delete(ctx, householdID); err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (h HouseholdHandler) Invite() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		householdID, ok := householdIDParam(c)
+		if !ok {
+			return
+		}
+		var req InviteHouseholdMemberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		created, err := h.usecase.Invite(ctx, householdID, usecase.InviteHouseholdMemberInput{
+			Email: req.Email,
+			Role:  domain.HouseholdRole(req.Role),
+		})
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h HouseholdHandler) ListInvitations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		householdID, ok := householdIDParam(c)
+		if !ok {
+			return
+		}
+
+		invitations, err := h.usecase.ListInvitations(ctx, householdID)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusOK, invitations)
+	}
+}
+
+func (h HouseholdHandler) RevokeInvitation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		householdID, ok := householdIDParam(c)
+		if !ok {
+			return
+		}
+		invitationID, err := uuid.Parse(c.Param("invitation_id"))
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid invitation id"))
+			return
+		}
+
+		if err := h.usecase.RevokeInvitation(ctx, householdID, invitationID); err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (h HouseholdHandler) AcceptInvitation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var req AcceptHouseholdInvitationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		household, err := h.usecase.AcceptInvitation(ctx, req.Token)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusOK, household)
+	}
+}
+
+func (h HouseholdHandler) UpdateMember() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		householdID, ok := householdIDParam(c)
+		if !ok {
+			return
+		}
+		var req UpdateHouseholdMemberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		err := h.usecase.UpdateMemberRole(ctx, householdID, c.Param("user_id"), domain.HouseholdRole(req.Role))
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (h HouseholdHandler) RemoveMember() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		householdID, ok := householdIDParam(c)
+		if !ok {
+			return
+		}
+
+		if err := h.usecase.RemoveMember(ctx, householdID, c.Param("user_id")); err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (h HouseholdHandler) Share() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		householdID, ok := householdIDParam(c)
+		if !ok {
+			return
+		}
+		var req ShareHouseholdResourceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		share, err := h.usecase.Share(ctx, householdID, usecase.HouseholdShareInput{
+			ResourceType: domain.HouseholdResource(req.ResourceType),
+			ResourceID:   req.ResourceID,
+		})
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusCreated, share)
+	}
+}
+
+func (h HouseholdHandler) Unshare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		householdID, ok := householdIDParam(c)
+		if !ok {
+			return
+		}
+		resourceID, err := uuid.Parse(c.Param("resource_id"))
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid resource id"))
+			return
+		}
+
+		err = h.usecase.Unshare(ctx, householdID, domain.HouseholdResource(c.Param("resource_type")), resourceID)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func householdIDParam(c *gin.Context) (uuid.UUID, bool) {
+	householdID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		HandleErr(c, c.Request.Context(), domain.WrapInvalidInput(err, "invalid household id"))
+		return uuid.Nil, false
+	}
+	return householdID, true
+}
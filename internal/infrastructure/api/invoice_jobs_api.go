@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	InvoiceJobsUseCase interface {
+		CloseAndAutoDebit(ctx context.Context, date time.Time) (usecase.InvoiceJobResult, error)
+	}
+
+	InvoiceJobsHandler struct {
+		usecase InvoiceJobsUseCase
+	}
+
+	InvoiceJobResponse struct {
+		InvoicesClosed int    `json:"invoices_closed"`
+		CloseFailed    int    `json:"close_failed"`
+		InvoicesDue    int    `json:"invoices_due"`
+		InvoicesPaid   int    `json:"invoices_paid"`
+		PaymentFailed  int    `json:"payment_failed"`
+		Skipped        int    `json:"skipped"`
+		Date           string `json:"date"`
+	}
+)
+
+func NewInvoiceJobHandlers(jobsGroup *gin.RouterGroup, srv InvoiceJobsUseCase) {
+	handler := InvoiceJobsHandler{
+		usecase: srv,
+	}
+
+	jobsGroup.POST("/invoices", handler.CloseAndAutoDebit())
+}
+
+func (h InvoiceJobsHandler) CloseAndAutoDebit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		dateStr := c.Query("date")
+		var date time.Time
+
+		if dateStr != "" {
+			parsedDate, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid date format, use YYYY-MM-DD"))
+				return
+			}
+			date = parsedDate
+		} else {
+			date = time.Now().UTC()
+		}
+
+		result, err := h.usecase.CloseAndAutoDebit(ctx, date)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, InvoiceJobResponse{
+			InvoicesClosed: result.InvoicesClosed,
+			CloseFailed:    result.CloseFailed,
+			InvoicesDue:    result.InvoicesDue,
+			InvoicesPaid:   result.InvoicesPaid,
+			PaymentFailed:  result.PaymentFailed,
+			Skipped:        result.Skipped,
+			Date:           date.Format("2006-01-02"),
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	PersonalAccessTokenUseCase interface {
+		CreateToken(ctx context.Context, input usecase.CreateAccessTokenInput) (usecase.CreatedAccessToken, error)
+		ListTokens(ctx context.Context) ([]domain.PersonalAccessToken, error)
+		RevokeToken(ctx context.Context, id string) error
+	}
+
+	PersonalAccessTokenHandler struct {
+		usecase PersonalAccessTokenUseCase
+	}
+
+	CreateAccessTokenRequest struct {
+		Name          string   `json:"name" binding:"required"`
+		Scopes        []string `json:"scopes" binding:"required"`
+		ExpiresInDays int      `json:"expires_in_days"`
+	}
+)
+
+func NewPersonalAccessTokenHandlers(r *gin.Engine, srv PersonalAccessTokenUseCase) {
+	handler := PersonalAccessTokenHandler{usecase: srv}
+
+	tokensGroup := r.Group(authentication.AccessTokensPath)
+
+	tokensGroup.POST("", handler.Create())
+	tokensGroup.GET("", handler.List())
+	tokensGroup.DELETE("/:id", handler.Revoke())
+}
+
+func (h PersonalAccessTokenHandler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var req CreateAccessTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		created, err := h.usecase.CreateToken(ctx, usecase.CreateAccessTokenInput{
+			Name:          req.Name,
+			Scopes:        req.Scopes,
+			ExpiresInDays: req.ExpiresInDays,
+		})
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h PersonalAccessTokenHandler) List() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tokens, err := h.usecase.ListTokens(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+func (h PersonalAccessTokenHandler) Revoke() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if err := h.usecase.RevokeToken(ctx, c.Param("id")); err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	RecurringChargeUseCase interface {
+		Detect(ctx context.Context, now time.Time) ([]domain.DetectedRecurringCharge, error)
+	}
+
+	RecurringChargeHandler struct {
+		usecase RecurringChargeUseCase
+	}
+)
+
+func NewRecurringChargeHandlers(r *gin.Engine, srv RecurringChargeUseCase) {
+	handler := RecurringChargeHandler{usecase: srv}
+
+	movementGroup := r.Group("/v2/movements")
+	movementGroup.GET("/recurring-charges", handler.Detect())
+}
+
+func (h RecurringChargeHandler) Detect() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		charges, err := h.usecase.Detect(ctx, time.Now().UTC())
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, charges)
+	}
+}
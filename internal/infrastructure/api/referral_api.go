@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	ReferralUseCase interface {
+		Overview(ctx context.Context) (usecase.ReferralOverview, error)
+	}
+
+	ReferralRewardsUseCase interface {
+		ApplyPendingRewards(ctx context.Context, now time.Time) (usecase.ReferralRewardsResult, error)
+	}
+
+	ReferralHandler struct {
+		usecase ReferralUseCase
+	}
+
+	ReferralJobHandler struct {
+		usecase ReferralRewardsUseCase
+	}
+)
+
+func NewReferralHandlers(r *gin.Engine, srv ReferralUseCase) {
+	handler := ReferralHandler{usecase: srv}
+
+	meGroup := r.Group("/me")
+
+	meGroup.GET("/referrals", handler.Overview())
+}
+
+func NewReferralJobHandlers(jobsGroup *gin.RouterGroup, srv ReferralRewardsUseCase) {
+	handler := ReferralJobHandler{usecase: srv}
+
+	jobsGroup.POST("/referrals/rewards", handler.ApplyPendingRewards())
+}
+
+func (h ReferralHandler) Overview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		overview, err := h.usecase.Overview(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, overview)
+	}
+}
+
+func (h ReferralJobHandler) ApplyPendingRewards() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		result, err := h.usecase.ApplyPendingRewards(ctx, time.Now())
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -22,12 +22,13 @@ type (
 	}
 )
 
-func NewStatementHandlers(r *gin.Engine, srv StatementUsecase) {
+func NewStatementHandlers(r *gin.Engine, srv StatementUsecase, featureGate gin.HandlerFunc) {
 	handler := StatementHandler{
 		usecase: srv,
 	}
 
 	group := r.Group("/v2/statements")
+	group.Use(featureGate)
 
 	group.POST("/extract", handler.Extract())
 	group.POST("/classify", handler.Classify())
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SubscriptionAnalyticsUseCase interface {
+		Report(ctx context.Context, period usecase.AnalyticsPeriod, now time.Time) (usecase.SubscriptionAnalyticsReport, error)
+	}
+
+	SubscriptionAnalyticsHandler struct {
+		usecase SubscriptionAnalyticsUseCase
+	}
+)
+
+func NewSubscriptionAnalyticsAdminHandlers(r *gin.Engine, srv SubscriptionAnalyticsUseCase) {
+	handler := SubscriptionAnalyticsHandler{usecase: srv}
+
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(authentication.AdminAuth())
+
+	adminGroup.GET("/subscriptions/analytics", handler.Report())
+}
+
+// Report returns every report as JSON, or one of them as CSV with
+// format=csv&report=<name>.
+func (h SubscriptionAnalyticsHandler) Report() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		now := time.Now()
+
+		period, err := usecase.NewAnalyticsPeriod(c.Query("from"), c.Query("to"), now)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "csv" {
+			HandleErr(c, ctx, domain.WrapInvalidInput(domain.New("format must be json or csv"), "analytics format"))
+			return
+		}
+		reportName := c.Query("report")
+		if format == "csv" && reportName == "" {
+			HandleErr(c, ctx, domain.WrapInvalidInput(domain.New("report is required for csv"), "analytics report"))
+			return
+		}
+
+		report, err := h.usecase.Report(ctx, period, now)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		if format == "json" {
+			c.JSON(http.StatusOK, report)
+			return
+		}
+
+		header, rows, ok := report.Table(reportName)
+		if !ok {
+			HandleErr(c, ctx, domain.WrapInvalidInput(domain.New("unknown report "+reportName), "analytics report"))
+			return
+		}
+
+		filename := fmt.Sprintf("subscriptions-%s-%s-%s.csv", reportName, report.From, report.To)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+
+		w := csv.NewWriter(c.Writer)
+		_ = w.Write(header)
+		_ = w.WriteAll(rows)
+	}
+}
@@ -16,6 +16,13 @@ import (
 type (
 	TransferUseCase interface {
 		Execute(ctx context.Context, input usecase.TransferInput) (usecase.TransferOutput, error)
+		UpdateOne(ctx context.Context, id uuid.UUID, input usecase.TransferUpdateInput) (usecase.TransferOutput, error)
+		UpdateAllNext(ctx context.Context, id uuid.UUID, input usecase.TransferUpdateInput) (usecase.TransferOutput, error)
+		DeleteOne(ctx context.Context, id uuid.UUID, date time.Time) error
+		DeleteAllNext(ctx context.Context, id uuid.UUID, date time.Time) error
+		FindByPairID(ctx context.Context, pairID uuid.UUID) (usecase.TransferOutput, error)
+		Update(ctx context.Context, pairID uuid.UUID, input usecase.TransferUpdateInput) (usecase.TransferOutput, error)
+		Delete(ctx context.Context, pairID uuid.UUID) error
 	}
 
 	TransferHandler struct {
@@ -29,10 +36,20 @@ type (
 		Date                string    `json:"date" binding:"required"`
 		Description         string    `json:"description"`
 		IsPaid              bool      `json:"is_paid"`
+		IsRecurrent         bool      `json:"is_recurrent"`
+		EndDate             string    `json:"end_date"`
+	}
+
+	TransferUpdateRequest struct {
+		Amount      float64 `json:"amount" binding:"required,gt=0"`
+		Date        string  `json:"date" binding:"required"`
+		Description string  `json:"description"`
+		IsPaid      bool    `json:"is_paid"`
 	}
 
 	TransferResponse struct {
 		PairID              uuid.UUID             `json:"pair_id"`
+		RecurrencePairID    *uuid.UUID            `json:"recurrence_pair_id,omitempty"`
 		OriginMovement      output.MovementOutput `json:"origin_movement"`
 		DestinationMovement output.MovementOutput `json:"destination_movement"`
 	}
@@ -46,6 +63,13 @@ func NewTransferHandlers(r *gin.Engine, srv TransferUseCase) {
 	transferGroup := r.Group("/v2/transfers")
 
 	transferGroup.POST("/", handler.Add())
+	transferGroup.GET("/:id", handler.FindByPairID())
+	transferGroup.PUT("/:id", handler.Update())
+	transferGroup.DELETE("/:id", handler.Delete())
+	transferGroup.PUT("/occurrences/:id", handler.UpdateOne())
+	transferGroup.PUT("/occurrences/:id/all-next", handler.UpdateAllNext())
+	transferGroup.DELETE("/occurrences/:id", handler.DeleteOne())
+	transferGroup.DELETE("/occurrences/:id/all-next", handler.DeleteAllNext())
 }
 
 func (h TransferHandler) Add() gin.HandlerFunc {
@@ -71,6 +95,16 @@ func (h TransferHandler) Add() gin.HandlerFunc {
 			Date:                date,
 			Description:         req.Description,
 			IsPaid:              req.IsPaid,
+			IsRecurrent:         req.IsRecurrent,
+		}
+
+		if req.EndDate != "" {
+			endDate, err := time.Parse("2006-01-02", req.EndDate)
+			if err != nil {
+				HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid end_date format, expected YYYY-MM-DD"))
+				return
+			}
+			input.EndDate = &endDate
 		}
 
 		result, err := h.usecase.Execute(ctx, input)
@@ -79,12 +113,145 @@ func (h TransferHandler) Add() gin.HandlerFunc {
 			return
 		}
 
-		response := TransferResponse{
-			PairID:              result.PairID,
-			OriginMovement:      *output.ToMovementOutput(result.OriginMovement),
-			DestinationMovement: *output.ToMovementOutput(result.DestinationMovement),
+		c.JSON(http.StatusCreated, toTransferResponse(result))
+	}
+}
+
+func (h TransferHandler) FindByPairID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		idParam := c.Param("id")
+
+		pairID, err := uuid.Parse(idParam)
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be valid"))
+			return
+		}
+
+		result, err := h.usecase.FindByPairID(ctx, pairID)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toTransferResponse(result))
+	}
+}
+
+func (h TransferHandler) Update() gin.HandlerFunc {
+	return h.update(h.usecase.Update)
+}
+
+func (h TransferHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		idParam := c.Param("id")
+
+		pairID, err := uuid.Parse(idParam)
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be valid"))
+			return
+		}
+
+		if err := h.usecase.Delete(ctx, pairID); err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (h TransferHandler) UpdateOne() gin.HandlerFunc {
+	return h.update(h.usecase.UpdateOne)
+}
+
+func (h TransferHandler) UpdateAllNext() gin.HandlerFunc {
+	return h.update(h.usecase.UpdateAllNext)
+}
+
+func (h TransferHandler) DeleteOne() gin.HandlerFunc {
+	return h.delete(h.usecase.DeleteOne)
+}
+
+func (h TransferHandler) DeleteAllNext() gin.HandlerFunc {
+	return h.delete(h.usecase.DeleteAllNext)
+}
+
+func (h TransferHandler) update(
+	fn func(ctx context.Context, id uuid.UUID, input usecase.TransferUpdateInput) (usecase.TransferOutput, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		idParam := c.Param("id")
+
+		id, err := uuid.Parse(idParam)
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be valid"))
+			return
+		}
+
+		var req TransferUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid json body"))
+			return
+		}
+
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid date format, expected YYYY-MM-DD"))
+			return
+		}
+
+		result, err := fn(ctx, id, usecase.TransferUpdateInput{
+			Amount:      req.Amount,
+			Date:        date,
+			Description: req.Description,
+			IsPaid:      req.IsPaid,
+		})
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toTransferResponse(result))
+	}
+}
+
+func (h TransferHandler) delete(fn func(ctx context.Context, id uuid.UUID, date time.Time) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		idParam := c.Param("id")
+
+		id, err := uuid.Parse(idParam)
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be valid"))
+			return
+		}
+
+		var date time.Time
+		if dateString := c.Query("date"); dateString != "" {
+			date, err = time.Parse("2006-01-02", dateString)
+			if err != nil {
+				HandleErr(c, ctx, domain.WrapInvalidInput(err, "invalid date format"))
+				return
+			}
+		}
+
+		if err := fn(ctx, id, date); err != nil {
+			HandleErr(c, ctx, err)
+			return
 		}
 
-		c.JSON(http.StatusCreated, response)
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func toTransferResponse(result usecase.TransferOutput) TransferResponse {
+	return TransferResponse{
+		PairID:              result.PairID,
+		RecurrencePairID:    result.RecurrencePairID,
+		OriginMovement:      *output.ToMovementOutput(result.OriginMovement),
+		DestinationMovement: *output.ToMovementOutput(result.DestinationMovement),
 	}
 }
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"personal-finance/internal/domain"
 	"personal-finance/internal/domain/output"
@@ -14,11 +15,14 @@ import (
 type (
 	WalletUsecase interface {
 		Add(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error)
-		FindAll(ctx context.Context) ([]domain.Wallet, error)
+		FindAll(ctx context.Context, includeArchived bool) ([]domain.Wallet, error)
 		FindByID(ctx context.Context, id *uuid.UUID) (domain.Wallet, error)
 		Update(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error)
 		Delete(ctx context.Context, id *uuid.UUID) error
 		RecalculateBalance(ctx context.Context, walletID *uuid.UUID) error
+		Archive(ctx context.Context, id *uuid.UUID) (domain.Wallet, error)
+		Unarchive(ctx context.Context, id *uuid.UUID) (domain.Wallet, error)
+		BalanceHistory(ctx context.Context, id *uuid.UUID, from, to time.Time, granularity domain.BalanceGranularity) ([]domain.WalletBalancePoint, error)
 	}
 
 	WalletHandler struct {
@@ -36,6 +40,9 @@ func NewWalletV2Handlers(r *gin.Engine, srv WalletUsecase) {
 	group.PUT("/:id", handler.Update())
 	group.DELETE("/:id", handler.Delete())
 	group.POST("/:id/recalculate", handler.RecalculateBalance())
+	group.POST("/:id/archive", handler.Archive())
+	group.POST("/:id/unarchive", handler.Unarchive())
+	group.GET("/:id/history", handler.BalanceHistory())
 }
 
 func (h WalletHandler) Add() gin.HandlerFunc {
@@ -60,7 +67,8 @@ func (h WalletHandler) Add() gin.HandlerFunc {
 func (h WalletHandler) FindAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
-		wallets, err := h.usecase.FindAll(ctx)
+		includeArchived := c.Query("include_archived") == "true"
+		wallets, err := h.usecase.FindAll(ctx, includeArchived)
 		if err != nil {
 			HandleErr(c, ctx, err)
 			return
@@ -155,3 +163,85 @@ func (h WalletHandler) RecalculateBalance() gin.HandlerFunc {
 		c.Status(http.StatusNoContent)
 	}
 }
+
+func (h WalletHandler) Archive() gin.HandlerFunc {
+	return h.setArchived(h.usecase.Archive)
+}
+
+func (h WalletHandler) Unarchive() gin.HandlerFunc {
+	return h.setArchived(h.usecase.Unarchive)
+}
+
+func (h WalletHandler) setArchived(fn func(ctx context.Context, id *uuid.UUID) (domain.Wallet, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be valid"))
+			return
+		}
+
+		wallet, err := fn(ctx, &id)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, output.ToWalletOutput(wallet))
+	}
+}
+
+func (h WalletHandler) BalanceHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be valid"))
+			return
+		}
+
+		period, err := h.parsePeriod(c)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		granularity := domain.BalanceGranularity(c.DefaultQuery("granularity", string(domain.BalanceGranularityDay)))
+
+		points, err := h.usecase.BalanceHistory(ctx, &id, period.From, period.To, granularity)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, output.ToWalletBalanceHistoryOutput(id, granularity, points))
+	}
+}
+
+func (h WalletHandler) parsePeriod(c *gin.Context) (domain.Period, error) {
+	var period domain.Period
+	var err error
+
+	fromString := c.Query("from")
+	if fromString != "" {
+		period.From, err = time.Parse("2006-01-02", fromString)
+		if err != nil {
+			return domain.Period{}, domain.WrapInvalidInput(err, "invalid from date format")
+		}
+	}
+
+	toString := c.Query("to")
+	if toString != "" {
+		period.To, err = time.Parse("2006-01-02", toString)
+		if err != nil {
+			return domain.Period{}, domain.WrapInvalidInput(err, "invalid to date format")
+		}
+	}
+
+	err = period.Validate()
+	if err != nil {
+		return domain.Period{}, domain.WrapInvalidInput(err, "invalid period")
+	}
+
+	return period, nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	WebhookInboxUseCase interface {
+		ProcessDue(ctx context.Context, now time.Time) (usecase.WebhookInboxJobResult, error)
+		ListDeadLetters(ctx context.Context) ([]domain.WebhookInboxEvent, error)
+		Replay(ctx context.Context, id uuid.UUID) (domain.WebhookInboxEvent, error)
+	}
+
+	WebhookInboxHandler struct {
+		usecase WebhookInboxUseCase
+	}
+)
+
+func NewWebhookInboxAdminHandlers(r *gin.Engine, srv WebhookInboxUseCase) {
+	handler := WebhookInboxHandler{usecase: srv}
+
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(authentication.AdminAuth())
+
+	adminGroup.GET("/webhooks/dead-letters", handler.ListDeadLetters())
+	adminGroup.POST("/webhooks/:id/replay", handler.Replay())
+}
+
+func NewWebhookInboxJobHandlers(jobsGroup *gin.RouterGroup, srv WebhookInboxUseCase) {
+	handler := WebhookInboxHandler{usecase: srv}
+
+	jobsGroup.POST("/webhooks", handler.ProcessDue())
+}
+
+func (h WebhookInboxHandler) ProcessDue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		result, err := h.usecase.ProcessDue(ctx, time.Now())
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func (h WebhookInboxHandler) ListDeadLetters() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		events, err := h.usecase.ListDeadLetters(ctx)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, events)
+	}
+}
+
+func (h WebhookInboxHandler) Replay() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			HandleErr(c, ctx, domain.WrapInvalidInput(err, "id must be a valid UUID"))
+			return
+		}
+
+		event, err := h.usecase.Replay(ctx, id)
+		if err != nil {
+			HandleErr(c, ctx, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, event)
+	}
+}
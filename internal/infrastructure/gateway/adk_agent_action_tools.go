@@ -0,0 +1,124 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+)
+
+// --- Action tool arg/result DTOs ---
+
+type proposeActionArgs[T any] struct {
+	// Summary is a short sentence, in Portuguese, shown to the user when asking for confirmation.
+	Summary string `json:"summary"`
+	Params  T      `json:"params"`
+}
+
+type proposeActionResult struct {
+	ActionID string `json:"action_id"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+}
+
+// actionProposals collects the actions proposed during a single chat turn.
+type actionProposals struct {
+	mu      sync.Mutex
+	actions []domain.AgentAction
+}
+
+func (p *actionProposals) add(action domain.AgentAction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.actions = append(p.actions, action)
+}
+
+func (p *actionProposals) list() []domain.AgentAction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]domain.AgentAction(nil), p.actions...)
+}
+
+// buildActionTools returns the write tools. They never change data: each call only
+// records a proposal that the user has to confirm outside the conversation.
+func (g *ADKAgentGateway) buildActionTools(ctx context.Context, proposals *actionProposals) ([]tool.Tool, error) {
+	createMovementTool, err := newActionTool[domain.AgentCreateMovementParams](ctx, proposals,
+		domain.AgentActionCreateMovement,
+		"Propõe registrar uma nova transação. Use valor negativo para despesas e positivo para receitas. "+
+			"wallet_id e category_id vêm das ferramentas de leitura; date no formato YYYY-MM-DD.",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	payMovementTool, err := newActionTool[domain.AgentPayMovementParams](ctx, proposals,
+		domain.AgentActionPayMovement,
+		"Propõe marcar uma transação existente como paga. movement_id vem da ferramenta get_movements; "+
+			"date (YYYY-MM-DD) é opcional e, se omitida, usa a data de hoje.",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	createEstimateTool, err := newActionTool[domain.AgentCreateEstimateParams](ctx, proposals,
+		domain.AgentActionCreateEstimate,
+		"Propõe definir o orçamento (estimativa) de uma categoria no mês. Se já existir, o valor é substituído. "+
+			"Use valor negativo para categorias de despesa e positivo para receitas.",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	createTransferTool, err := newActionTool[domain.AgentCreateTransferParams](ctx, proposals,
+		domain.AgentActionCreateTransfer,
+		"Propõe uma transferência entre duas carteiras do usuário. amount é sempre positivo; date no formato YYYY-MM-DD.",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return []tool.Tool{createMovementTool, payMovementTool, createEstimateTool, createTransferTool}, nil
+}
+
+func newActionTool[T any](
+	ctx context.Context,
+	proposals *actionProposals,
+	actionType domain.AgentActionType,
+	description string,
+) (tool.Tool, error) {
+	name := "propose_" + string(actionType)
+
+	t, err := functiontool.New(functiontool.Config{
+		Name: name,
+		Description: description + " A ação NÃO é executada: ela fica pendente até o usuário confirmar no app. " +
+			"Nunca diga que a ação já foi realizada.",
+	}, func(_ tool.Context, args proposeActionArgs[T]) (proposeActionResult, error) {
+		log.InfoContext(ctx, "agent tool called", log.String("tool", name))
+		userID := authentication.UserIDFromContext(ctx)
+		if userID == "" {
+			return proposeActionResult{}, fmt.Errorf("usuário não autenticado")
+		}
+
+		action, err := domain.NewAgentAction(userID, actionType, args.Summary, args.Params)
+		if err != nil {
+			return proposeActionResult{}, fmt.Errorf("parâmetros inválidos: %w", err)
+		}
+		proposals.add(action)
+
+		return proposeActionResult{
+			ActionID: action.ID.String(),
+			Status:   string(action.Status),
+			Message:  "Ação registrada e aguardando confirmação do usuário.",
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", name, err)
+	}
+	return t, nil
+}
@@ -2,14 +2,14 @@ package gateway
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/google/uuid"
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
-	"google.golang.org/adk/model/gemini"
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
@@ -28,14 +28,14 @@ const (
 	appName         = "personal_finance_agent"
 )
 
-// ADKAgentGateway implements domain.AgentGateway using Google ADK + Vertex AI.
+// ADKAgentGateway implements domain.AgentGateway using Google ADK. The model behind
+// the agent comes from an LLMProvider (Vertex AI, OpenAI-compatible or fake).
 // ADK lives ONLY here — never imported by domain or usecase layers.
 type ADKAgentGateway struct {
 	memoryRepo    MemoryRepository
+	settingsRepo  SettingsRepository
 	financialRepo FinancialRepository
-	projectID     string
-	location      string
-	modelName     string
+	provider      LLMProvider
 }
 
 // MemoryRepository is the minimal interface the gateway needs to execute memory tool calls.
@@ -44,8 +44,15 @@ type MemoryRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (domain.AgentMemory, error)
 	Update(ctx context.Context, memory domain.AgentMemory) (domain.AgentMemory, error)
 	Delete(ctx context.Context, id uuid.UUID) error
-	SearchByContent(ctx context.Context, userID string, query string) ([]domain.AgentMemory, error)
+	SearchSimilar(ctx context.Context, userID string, query string, limit int) ([]domain.AgentMemory, error)
 	FindByUserID(ctx context.Context, userID string) ([]domain.AgentMemory, error)
+	CountByUserID(ctx context.Context, userID string) (int64, error)
+	UpsertRiskProfile(ctx context.Context, memory domain.AgentMemory) (domain.AgentMemory, error)
+}
+
+// SettingsRepository tells whether the user lets the agent save memories.
+type SettingsRepository interface {
+	IsMemoryEnabled(ctx context.Context, userID string) (bool, error)
 }
 
 // FinancialRepository is the minimal interface the gateway needs to execute financial query tools.
@@ -56,29 +63,22 @@ type FinancialRepository interface {
 	GetMovements(ctx context.Context, month, year, limit int) (domain.AgentMovementsList, error)
 	GetRecurringSummary(ctx context.Context) (domain.AgentRecurringSummary, error)
 	GetBudgetStatus(ctx context.Context, month, year int) (domain.AgentBudgetStatus, error)
+	GetRecurringCharges(ctx context.Context) (domain.AgentRecurringCharges, error)
 }
 
-// NewADKAgentGateway creates a new ADKAgentGateway.
-func NewADKAgentGateway(memoryRepo MemoryRepository, financialRepo FinancialRepository) *ADKAgentGateway {
-	location := os.Getenv("GOOGLE_CLOUD_LOCATION")
-	if location == "" {
-		location = defaultLocation
-	}
-
-	modelName := os.Getenv("VERTEX_MODEL")
-	if modelName == "" {
-		modelName = defaultModel
-	}
-
+// NewADKAgentGateway creates a new ADKAgentGateway. A nil settingsRepo leaves
+// memory saving enabled for every user.
+func NewADKAgentGateway(memoryRepo MemoryRepository, settingsRepo SettingsRepository, financialRepo FinancialRepository, provider LLMProvider) *ADKAgentGateway {
 	return &ADKAgentGateway{
 		memoryRepo:    memoryRepo,
+		settingsRepo:  settingsRepo,
 		financialRepo: financialRepo,
-		projectID:     os.Getenv("GOOGLE_PROJECT_ID"),
-		location:      location,
-		modelName:     modelName,
+		provider:      provider,
 	}
 }
 
+var errMemoryDisabled = errors.New("o usuário desativou o salvamento de memórias")
+
 // --- Tool argument/result DTOs ---
 
 type saveMemoryArgs struct {
@@ -128,22 +128,85 @@ type memoryItem struct {
 
 // --- Chat implementation ---
 
-// Chat sends the user message to Vertex AI via ADK and returns the agent response.
+// Chat sends the user message to the provider model via ADK and returns the agent response.
 func (g *ADKAgentGateway) Chat(
 	ctx context.Context,
 	systemPrompt string,
 	userMessage string,
 	history []domain.AgentMessage,
 ) (domain.AgentGatewayResponse, error) {
+	return g.run(ctx, systemPrompt, userMessage, history, agent.StreamingModeNone, func(domain.AgentStreamEvent) {})
+}
 
-	// 1. Build the Gemini model pointing to Vertex AI
-	geminiModel, err := gemini.NewModel(ctx, g.modelName, &genai.ClientConfig{
-		Project:  g.projectID,
-		Location: g.location,
-		Backend:  genai.BackendVertexAI,
-	})
+// ChatStream works like Chat but emits text deltas and tool calls as they happen.
+// When the run is interrupted (e.g. the client disconnected) the partial response
+// collected so far is returned together with the error.
+func (g *ADKAgentGateway) ChatStream(
+	ctx context.Context,
+	systemPrompt string,
+	userMessage string,
+	history []domain.AgentMessage,
+	emit domain.AgentStreamEmitter,
+) (domain.AgentGatewayResponse, error) {
+	return g.run(ctx, systemPrompt, userMessage, history, agent.StreamingModeSSE, emit)
+}
+
+// Summarize sends a single prompt to the model, without tools or session. It is
+// used to condense long conversations and to phrase the weekly insights digest.
+func (g *ADKAgentGateway) Summarize(ctx context.Context, prompt string) (domain.AgentGatewayResponse, error) {
+	llm, err := g.provider.NewModel(ctx)
+	if err != nil {
+		return domain.AgentGatewayResponse{}, err
+	}
+
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)},
+		Config:   &genai.GenerateContentConfig{},
+	}
+
+	var text strings.Builder
+	var inputTokens, outputTokens int
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return domain.AgentGatewayResponse{}, fmt.Errorf("failed to generate summary: %w", err)
+		}
+		if resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				if part.Text != "" && !part.Thought {
+					text.WriteString(part.Text)
+				}
+			}
+		}
+		if resp.UsageMetadata != nil {
+			inputTokens += int(resp.UsageMetadata.PromptTokenCount)
+			outputTokens += int(resp.UsageMetadata.CandidatesTokenCount)
+		}
+	}
+
+	metrics.IncAITokens(ctx, "agent_summary", g.provider.ModelName(), inputTokens, outputTokens)
+
+	return domain.AgentGatewayResponse{
+		Content:      text.String(),
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		Provider:     g.provider.Name(),
+		Region:       g.provider.Region(),
+	}, nil
+}
+
+func (g *ADKAgentGateway) run(
+	ctx context.Context,
+	systemPrompt string,
+	userMessage string,
+	history []domain.AgentMessage,
+	mode agent.StreamingMode,
+	emit domain.AgentStreamEmitter,
+) (domain.AgentGatewayResponse, error) {
+
+	// 1. Build the model of the configured provider
+	llm, err := g.provider.NewModel(ctx)
 	if err != nil {
-		return domain.AgentGatewayResponse{}, fmt.Errorf("failed to create gemini model: %w", err)
+		return domain.AgentGatewayResponse{}, err
 	}
 
 	// 2. Build the full instruction from system prompt + conversation history
@@ -158,13 +221,19 @@ func (g *ADKAgentGateway) Chat(
 	if err != nil {
 		return domain.AgentGatewayResponse{}, fmt.Errorf("failed to build financial tools: %w", err)
 	}
+	proposals := &actionProposals{}
+	actionTools, err := g.buildActionTools(ctx, proposals)
+	if err != nil {
+		return domain.AgentGatewayResponse{}, fmt.Errorf("failed to build action tools: %w", err)
+	}
 	tools := append(memoryTools, financialTools...)
+	tools = append(tools, actionTools...)
 
 	// 4. Create the ADK LLM agent
 	agentInstance, err := llmagent.New(llmagent.Config{
 		Name:        "finance_assistant",
 		Description: "Assistente financeiro pessoal inteligente e empático",
-		Model:       geminiModel,
+		Model:       llm,
 		Instruction: fullInstruction,
 		Tools:       tools,
 	})
@@ -202,21 +271,41 @@ func (g *ADKAgentGateway) Chat(
 	var responseBuilder strings.Builder
 	var toolsCalled []string
 	var inputTokens, outputTokens int
+	// streamed tracks whether the text of the current response already went out as
+	// partial deltas, so the aggregated final event is not emitted twice.
+	streamed := false
+
+	response := func() domain.AgentGatewayResponse {
+		return domain.AgentGatewayResponse{
+			Content:         responseBuilder.String(),
+			ToolsCalled:     toolsCalled,
+			InputTokens:     inputTokens,
+			OutputTokens:    outputTokens,
+			ProposedActions: proposals.list(),
+			Provider:        g.provider.Name(),
+			Region:          g.provider.Region(),
+		}
+	}
 
-	for event, err := range agentRunner.Run(ctx, userID, sessionID, userContent, agent.RunConfig{}) {
+	for event, err := range agentRunner.Run(ctx, userID, sessionID, userContent, agent.RunConfig{StreamingMode: mode}) {
 		if err != nil {
-			return domain.AgentGatewayResponse{}, fmt.Errorf("agent run error: %w", err)
+			metrics.IncAITokens(ctx, "agent", g.provider.ModelName(), inputTokens, outputTokens)
+			return response(), fmt.Errorf("agent run error: %w", err)
 		}
 		if event == nil {
 			continue
 		}
 
-		if event.Content != nil {
-			for _, part := range event.Content.Parts {
-				if part.FunctionCall != nil {
-					toolsCalled = append(toolsCalled, part.FunctionCall.Name)
+		if event.LLMResponse.Partial {
+			if event.Content != nil {
+				for _, part := range event.Content.Parts {
+					if part.Text != "" && !part.Thought {
+						streamed = true
+						emit(domain.AgentStreamEvent{Type: domain.AgentStreamEventDelta, Text: part.Text})
+					}
 				}
 			}
+			continue
 		}
 
 		if event.LLMResponse.UsageMetadata != nil {
@@ -224,23 +313,30 @@ func (g *ADKAgentGateway) Chat(
 			outputTokens += int(event.LLMResponse.UsageMetadata.CandidatesTokenCount)
 		}
 
-		if !event.LLMResponse.Partial && event.Content != nil {
-			for _, part := range event.Content.Parts {
-				if part.Text != "" && !part.Thought {
-					responseBuilder.WriteString(part.Text)
+		if event.Content == nil {
+			continue
+		}
+
+		for _, part := range event.Content.Parts {
+			switch {
+			case part.FunctionCall != nil:
+				toolsCalled = append(toolsCalled, part.FunctionCall.Name)
+				emit(domain.AgentStreamEvent{Type: domain.AgentStreamEventToolStart, Tool: part.FunctionCall.Name})
+			case part.FunctionResponse != nil:
+				emit(domain.AgentStreamEvent{Type: domain.AgentStreamEventToolEnd, Tool: part.FunctionResponse.Name})
+			case part.Text != "" && !part.Thought:
+				responseBuilder.WriteString(part.Text)
+				if !streamed {
+					emit(domain.AgentStreamEvent{Type: domain.AgentStreamEventDelta, Text: part.Text})
 				}
 			}
 		}
+		streamed = false
 	}
 
-	metrics.IncAITokens(ctx, "agent", g.modelName, inputTokens, outputTokens)
+	metrics.IncAITokens(ctx, "agent", g.provider.ModelName(), inputTokens, outputTokens)
 
-	return domain.AgentGatewayResponse{
-		Content:      responseBuilder.String(),
-		ToolsCalled:  toolsCalled,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-	}, nil
+	return response(), nil
 }
 
 func buildInstruction(systemPrompt string, history []domain.AgentMessage) string {
@@ -263,13 +359,60 @@ func buildInstruction(systemPrompt string, history []domain.AgentMessage) string
 	return sb.String()
 }
 
+// memoryEnabled reads the memory toggle of the user once per chat. A failure
+// to read it blocks saving: the user may have turned it off.
+func (g *ADKAgentGateway) memoryEnabled(ctx context.Context) bool {
+	if g.settingsRepo == nil {
+		return true
+	}
+	enabled, err := g.settingsRepo.IsMemoryEnabled(ctx, authentication.UserIDFromContext(ctx))
+	if err != nil {
+		log.WarnContext(ctx, "failed to read agent settings, memory saving blocked", log.Err(err))
+		return false
+	}
+	return enabled
+}
+
+// saveMemory applies the same rules as the memories API: one risk profile,
+// capped total.
+func (g *ADKAgentGateway) saveMemory(ctx context.Context, memory domain.AgentMemory) (domain.AgentMemory, error) {
+	var saved domain.AgentMemory
+	var err error
+	if memory.Type == domain.MemoryTypeRiskProfile {
+		saved, err = g.memoryRepo.UpsertRiskProfile(ctx, memory)
+	} else {
+		var count int64
+		count, err = g.memoryRepo.CountByUserID(ctx, memory.UserID)
+		if err != nil {
+			return domain.AgentMemory{}, fmt.Errorf("erro ao salvar memória: %w", err)
+		}
+		if count >= int64(domain.MaxMemoriesPerUser) {
+			return domain.AgentMemory{}, fmt.Errorf("limite de %d memórias atingido; atualize ou remova uma memória antes de salvar outra", domain.MaxMemoriesPerUser)
+		}
+		saved, err = g.memoryRepo.Save(ctx, memory)
+	}
+	if err != nil {
+		return domain.AgentMemory{}, fmt.Errorf("erro ao salvar memória: %w", err)
+	}
+	return saved, nil
+}
+
 func (g *ADKAgentGateway) buildMemoryTools(ctx context.Context) ([]tool.Tool, error) {
+	memoryEnabled := g.memoryEnabled(ctx)
+	disabledNote := ""
+	if !memoryEnabled {
+		disabledNote = " Indisponível: o usuário desativou o salvamento de memórias."
+	}
+
 	saveTool, err := functiontool.New(functiontool.Config{
 		Name: "save_memory",
 		Description: "Salva uma nova memória persistente sobre o usuário. " +
-			"O campo memory_type deve ser um dos valores: goal, fact, constraint, insight, commitment, risk_profile, life_event.",
+			"O campo memory_type deve ser um dos valores: goal, fact, constraint, insight, commitment, risk_profile, life_event." + disabledNote,
 	}, func(_ tool.Context, args saveMemoryArgs) (saveMemoryResult, error) {
 		log.InfoContext(ctx, "agent tool called", log.String("tool", "save_memory"), log.String("memory_type", args.MemoryType))
+		if !memoryEnabled {
+			return saveMemoryResult{}, errMemoryDisabled
+		}
 		userID := authentication.UserIDFromContext(ctx)
 		if userID == "" {
 			return saveMemoryResult{}, fmt.Errorf("usuário não autenticado")
@@ -280,6 +423,10 @@ func (g *ADKAgentGateway) buildMemoryTools(ctx context.Context) ([]tool.Tool, er
 			return saveMemoryResult{}, fmt.Errorf("tipo de memória inválido: %s", args.MemoryType)
 		}
 
+		if domain.ContainsPII(args.Content) {
+			return saveMemoryResult{}, fmt.Errorf("a memória não pode conter CPF ou e-mail")
+		}
+
 		source := domain.MemorySourceExplicit
 		if memType == domain.MemoryTypeInsight {
 			source = domain.MemorySourceDerived
@@ -290,9 +437,9 @@ func (g *ADKAgentGateway) buildMemoryTools(ctx context.Context) ([]tool.Tool, er
 			memory.Metadata = args.Metadata
 		}
 
-		saved, err := g.memoryRepo.Save(ctx, memory)
+		saved, err := g.saveMemory(ctx, memory)
 		if err != nil {
-			return saveMemoryResult{}, fmt.Errorf("erro ao salvar memória: %w", err)
+			return saveMemoryResult{}, err
 		}
 
 		return saveMemoryResult{
@@ -306,9 +453,12 @@ func (g *ADKAgentGateway) buildMemoryTools(ctx context.Context) ([]tool.Tool, er
 
 	updateTool, err := functiontool.New(functiontool.Config{
 		Name:        "update_memory",
-		Description: "Atualiza o conteúdo ou metadados de uma memória existente pelo ID.",
+		Description: "Atualiza o conteúdo ou metadados de uma memória existente pelo ID." + disabledNote,
 	}, func(_ tool.Context, args updateMemoryArgs) (updateMemoryResult, error) {
 		log.InfoContext(ctx, "agent tool called", log.String("tool", "update_memory"), log.String("memory_id", args.ID))
+		if !memoryEnabled {
+			return updateMemoryResult{}, errMemoryDisabled
+		}
 		parsedID, err := uuid.Parse(args.ID)
 		if err != nil {
 			return updateMemoryResult{}, fmt.Errorf("id inválido: %s", args.ID)
@@ -319,6 +469,10 @@ func (g *ADKAgentGateway) buildMemoryTools(ctx context.Context) ([]tool.Tool, er
 			return updateMemoryResult{}, fmt.Errorf("memória não encontrada: %s", args.ID)
 		}
 
+		if domain.ContainsPII(args.Content) {
+			return updateMemoryResult{}, fmt.Errorf("a memória não pode conter CPF ou e-mail")
+		}
+
 		existing.Content = args.Content
 		if args.Metadata != nil {
 			existing.Metadata = args.Metadata
@@ -356,8 +510,9 @@ func (g *ADKAgentGateway) buildMemoryTools(ctx context.Context) ([]tool.Tool, er
 	}
 
 	searchTool, err := functiontool.New(functiontool.Config{
-		Name:        "search_memories",
-		Description: "Busca memórias persistentes do usuário.",
+		Name: "search_memories",
+		Description: "Busca memórias persistentes do usuário pelo significado, não só pelas palavras exatas " +
+			"(ex.: 'viagem' encontra 'férias na Europa'). Sem query, lista todas as memórias ativas.",
 	}, func(_ tool.Context, args searchMemoriesArgs) (searchMemoriesResult, error) {
 		log.InfoContext(ctx, "agent tool called", log.String("tool", "search_memories"), log.String("query", args.Query))
 		userID := authentication.UserIDFromContext(ctx)
@@ -367,7 +522,7 @@ func (g *ADKAgentGateway) buildMemoryTools(ctx context.Context) ([]tool.Tool, er
 		if args.Query == "" {
 			memories, err = g.memoryRepo.FindByUserID(ctx, userID)
 		} else {
-			memories, err = g.memoryRepo.SearchByContent(ctx, userID, args.Query)
+			memories, err = g.memoryRepo.SearchSimilar(ctx, userID, args.Query, domain.MemorySearchLimit)
 		}
 		if err != nil {
 			return searchMemoriesResult{}, fmt.Errorf("erro ao buscar memórias: %w", err)
@@ -485,5 +640,20 @@ func (g *ADKAgentGateway) buildFinancialTools(ctx context.Context) ([]tool.Tool,
 		return nil, fmt.Errorf("failed to create get_budget_status tool: %w", err)
 	}
 
-	return []tool.Tool{overviewTool, breakdownTool, creditCardsTool, movementsTool, recurringTool, budgetTool}, nil
+	recurringChargesTool, err := functiontool.New(functiontool.Config{
+		Name: "find_recurring_charges",
+		Description: "Analisa o histórico de despesas e encontra cobranças que se repetem todo mês ou todo ano sem estar cadastradas como recorrentes, " +
+			"com o impacto mensal, a próxima cobrança esperada e o último aumento de preço. " +
+			"possibly_forgotten indica assinaturas pequenas no cartão que podem ter sido esquecidas. " +
+			"Use para responder 'tenho alguma assinatura esquecida?' ou 'quais cobranças aumentaram?'. " +
+			"Para converter uma cobrança mensal em recorrente, oriente o usuário a usar a sugestão de cobranças recorrentes do app.",
+	}, func(_ tool.Context, _ struct{}) (domain.AgentRecurringCharges, error) {
+		log.InfoContext(ctx, "agent tool called", log.String("tool", "find_recurring_charges"))
+		return g.financialRepo.GetRecurringCharges(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create find_recurring_charges tool: %w", err)
+	}
+
+	return []tool.Tool{overviewTool, breakdownTool, creditCardsTool, movementsTool, recurringTool, budgetTool, recurringChargesTool}, nil
 }
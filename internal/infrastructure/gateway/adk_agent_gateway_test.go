@@ -0,0 +1,202 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+)
+
+type stubFinancialRepository struct {
+	FinancialRepository
+	overviewCalls int
+}
+
+func (s *stubFinancialRepository) GetFinancialOverview(_ context.Context, month, year int) (domain.AgentFinancialOverview, error) {
+	s.overviewCalls++
+	return domain.AgentFinancialOverview{}, nil
+}
+
+type stubMemoryRepository struct {
+	MemoryRepository
+	count        int64
+	saveErr      error
+	saved        []domain.AgentMemory
+	riskProfiles []domain.AgentMemory
+}
+
+func (s *stubMemoryRepository) CountByUserID(_ context.Context, _ string) (int64, error) {
+	return s.count, nil
+}
+
+func (s *stubMemoryRepository) Save(_ context.Context, memory domain.AgentMemory) (domain.AgentMemory, error) {
+	if s.saveErr != nil {
+		return domain.AgentMemory{}, s.saveErr
+	}
+	s.saved = append(s.saved, memory)
+	return memory, nil
+}
+
+func (s *stubMemoryRepository) UpsertRiskProfile(_ context.Context, memory domain.AgentMemory) (domain.AgentMemory, error) {
+	s.riskProfiles = append(s.riskProfiles, memory)
+	return memory, nil
+}
+
+type stubSettingsRepository struct {
+	enabled bool
+	err     error
+}
+
+func (s stubSettingsRepository) IsMemoryEnabled(_ context.Context, _ string) (bool, error) {
+	return s.enabled, s.err
+}
+
+func TestADKAgentGateway_WithFakeProvider(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1"})
+
+	t.Run("should replay tool calls and report the provider used", func(t *testing.T) {
+		financialRepo := &stubFinancialRepository{}
+		g := NewADKAgentGateway(nil, nil, financialRepo, NewFakeProvider("testdata/agent_fake_estimate.json"))
+
+		resp, err := g.Chat(ctx, "system", "quanto posso gastar com mercado?", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Seu saldo de março está positivo. Confirme o orçamento proposto.", resp.Content)
+		assert.ElementsMatch(t, []string{"get_financial_overview", "propose_create_estimate"}, resp.ToolsCalled)
+		assert.Equal(t, 320, resp.InputTokens)
+		assert.Equal(t, 45, resp.OutputTokens)
+		assert.Equal(t, ProviderFake, resp.Provider)
+		assert.Equal(t, "local", resp.Region)
+		assert.Equal(t, 1, financialRepo.overviewCalls)
+
+		require.Len(t, resp.ProposedActions, 1)
+		assert.Equal(t, domain.AgentActionCreateEstimate, resp.ProposedActions[0].Type)
+		assert.Equal(t, "user-1", resp.ProposedActions[0].UserID)
+	})
+
+	t.Run("should stream the scripted text as deltas", func(t *testing.T) {
+		g := NewADKAgentGateway(nil, nil, &stubFinancialRepository{}, NewFakeProvider(""))
+
+		var deltas []string
+		resp, err := g.ChatStream(ctx, "system", "oi", nil, func(event domain.AgentStreamEvent) {
+			if event.Type == domain.AgentStreamEventDelta {
+				deltas = append(deltas, event.Text)
+			}
+		})
+
+		require.NoError(t, err)
+		assert.Greater(t, len(deltas), 1)
+		assert.Equal(t, resp.Content, strings.Join(deltas, ""))
+	})
+
+	t.Run("should fail when the fixture does not exist", func(t *testing.T) {
+		g := NewADKAgentGateway(nil, nil, &stubFinancialRepository{}, NewFakeProvider("testdata/missing.json"))
+
+		_, err := g.Chat(ctx, "system", "oi", nil)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should apply the memory rules of the API to the save_memory tool", func(t *testing.T) {
+		tests := map[string]struct {
+			count            int64
+			expectedSaved    []string
+			expectedProfiles int
+		}{
+			"should drop PII and save the rest": {
+				count:            3,
+				expectedSaved:    []string{"Juntar R$ 10.000,00 para a reserva de emergência"},
+				expectedProfiles: 1,
+			},
+			"should only replace the risk profile when the cap is reached": {
+				count:            domain.MaxMemoriesPerUser,
+				expectedSaved:    nil,
+				expectedProfiles: 1,
+			},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				memoryRepo := &stubMemoryRepository{count: tt.count}
+				g := NewADKAgentGateway(memoryRepo, nil, &stubFinancialRepository{}, NewFakeProvider("testdata/agent_fake_memory_guard.json"))
+
+				resp, err := g.Chat(ctx, "system", "guarda isso", nil)
+
+				require.NoError(t, err)
+				assert.Equal(t, "Anotado.", resp.Content)
+				var saved []string
+				for _, m := range memoryRepo.saved {
+					saved = append(saved, m.Content)
+				}
+				assert.Equal(t, tt.expectedSaved, saved)
+				assert.Len(t, memoryRepo.riskProfiles, tt.expectedProfiles)
+			})
+		}
+	})
+
+	t.Run("should block save_memory when the user disabled memories", func(t *testing.T) {
+		tests := map[string]stubSettingsRepository{
+			"should block when disabled":              {enabled: false},
+			"should block when settings fail to load": {err: errors.New("connection refused")},
+		}
+
+		for name, settings := range tests {
+			t.Run(name, func(t *testing.T) {
+				memoryRepo := &stubMemoryRepository{}
+				g := NewADKAgentGateway(memoryRepo, settings, &stubFinancialRepository{}, NewFakeProvider("testdata/agent_fake_memory_guard.json"))
+
+				resp, err := g.Chat(ctx, "system", "guarda isso", nil)
+
+				require.NoError(t, err)
+				assert.Equal(t, "Anotado.", resp.Content)
+				assert.Empty(t, memoryRepo.saved)
+				assert.Empty(t, memoryRepo.riskProfiles)
+			})
+		}
+	})
+}
+
+func TestADKAgentGateway_SaveMemory(t *testing.T) {
+	ctx := context.Background()
+	memory := domain.NewAgentMemory("user-1", domain.MemoryTypeGoal, "Juntar R$ 10.000,00", domain.MemorySourceExplicit)
+
+	tests := map[string]struct {
+		repo        *stubMemoryRepository
+		expectedErr string
+	}{
+		"should save the memory": {
+			repo: &stubMemoryRepository{count: 3},
+		},
+		"should fail when the cap is reached": {
+			repo:        &stubMemoryRepository{count: domain.MaxMemoriesPerUser},
+			expectedErr: "limite de",
+		},
+		"should fail when the repository fails to save": {
+			repo:        &stubMemoryRepository{count: 3, saveErr: errors.New("connection refused")},
+			expectedErr: "connection refused",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewADKAgentGateway(tt.repo, nil, &stubFinancialRepository{}, NewFakeProvider(""))
+
+			saved, err := g.saveMemory(ctx, memory)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.Empty(t, saved.ID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, memory.ID, saved.ID)
+		})
+	}
+}
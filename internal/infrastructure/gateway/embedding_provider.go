@@ -0,0 +1,285 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genai"
+
+	"personal-finance/internal/domain"
+	"personal-finance/pkg/log"
+)
+
+const (
+	ProviderLocal = "local"
+
+	defaultVertexEmbeddingModel = "text-multilingual-embedding-002"
+	defaultOpenAIEmbeddingModel = "nomic-embed-text"
+	localEmbeddingDimensions    = 256
+	localStemRunes              = 5
+)
+
+// NewEmbeddingProviderFromEnv picks the memory embedding provider from
+// AGENT_EMBEDDING_PROVIDER, defaulting to the provider of the agent model so the
+// memories are processed where the conversation is:
+//   - vertex_ai: Vertex AI embeddings (GOOGLE_PROJECT_ID, GOOGLE_CLOUD_LOCATION, VERTEX_EMBEDDING_MODEL)
+//   - openai_compatible: any /embeddings endpoint (OPENAI_BASE_URL, OPENAI_API_KEY, OPENAI_EMBEDDING_MODEL)
+//   - local: hashed bag of words computed in process, for offline use and the fake provider
+func NewEmbeddingProviderFromEnv() domain.EmbeddingProvider {
+	name := os.Getenv("AGENT_EMBEDDING_PROVIDER")
+	if name == "" {
+		name = os.Getenv("AGENT_LLM_PROVIDER")
+	}
+
+	switch name {
+	case "", ProviderVertexAI:
+		return newVertexEmbeddingProviderFromEnv()
+	case ProviderOpenAICompatible:
+		modelName := os.Getenv("OPENAI_EMBEDDING_MODEL")
+		if modelName == "" {
+			modelName = defaultOpenAIEmbeddingModel
+		}
+		return NewOpenAICompatibleEmbeddingProvider(os.Getenv("OPENAI_BASE_URL"), os.Getenv("OPENAI_API_KEY"), modelName)
+	case ProviderLocal, ProviderFake:
+		return NewLocalEmbeddingProvider()
+	default:
+		log.Error("unknown AGENT_EMBEDDING_PROVIDER, falling back to local", log.String("provider", name))
+		return NewLocalEmbeddingProvider()
+	}
+}
+
+// --- Vertex AI ---
+
+type vertexEmbeddingProvider struct {
+	projectID string
+	location  string
+	modelName string
+}
+
+func newVertexEmbeddingProviderFromEnv() *vertexEmbeddingProvider {
+	location := os.Getenv("GOOGLE_CLOUD_LOCATION")
+	if location == "" {
+		location = defaultLocation
+	}
+
+	modelName := os.Getenv("VERTEX_EMBEDDING_MODEL")
+	if modelName == "" {
+		modelName = defaultVertexEmbeddingModel
+	}
+
+	return &vertexEmbeddingProvider{
+		projectID: os.Getenv("GOOGLE_PROJECT_ID"),
+		location:  location,
+		modelName: modelName,
+	}
+}
+
+func (p *vertexEmbeddingProvider) Name() string { return ProviderVertexAI + "/" + p.modelName }
+
+func (p *vertexEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		Project:  p.projectID,
+		Location: p.location,
+		Backend:  genai.BackendVertexAI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genai client: %w", err)
+	}
+
+	contents := make([]*genai.Content, len(texts))
+	for i, text := range texts {
+		contents[i] = genai.NewContentFromText(text, genai.RoleUser)
+	}
+
+	resp, err := client.Models.EmbedContent(ctx, p.modelName, contents, &genai.EmbedContentConfig{AutoTruncate: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed content: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Embeddings))
+	}
+
+	vectors := make([][]float32, len(resp.Embeddings))
+	for i, embedding := range resp.Embeddings {
+		vectors[i] = embedding.Values
+	}
+	return vectors, nil
+}
+
+// --- OpenAI-compatible ---
+
+type openAICompatibleEmbeddingProvider struct {
+	baseURL   string
+	apiKey    string
+	modelName string
+	client    *http.Client
+}
+
+func NewOpenAICompatibleEmbeddingProvider(baseURL, apiKey, modelName string) domain.EmbeddingProvider {
+	return &openAICompatibleEmbeddingProvider{
+		baseURL:   baseURL,
+		apiKey:    apiKey,
+		modelName: modelName,
+		client:    &http.Client{Timeout: openAIHTTPTimeout},
+	}
+}
+
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (p *openAICompatibleEmbeddingProvider) Name() string {
+	return ProviderOpenAICompatible + "/" + p.modelName
+}
+
+func (p *openAICompatibleEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if p.baseURL == "" {
+		return nil, fmt.Errorf("OPENAI_BASE_URL is required for the %s embedding provider", ProviderOpenAICompatible)
+	}
+
+	body, err := json.Marshal(openAIEmbeddingRequest{Model: p.modelName, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embedding request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(p.baseURL, "/")+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build embedding request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("embedding returned status %d: %s", resp.StatusCode, string(msg))
+	}
+
+	var decoded openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode embedding response: %w", err)
+	}
+	if len(decoded.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(decoded.Data))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range decoded.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding index %d out of range", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
+
+// --- Local ---
+
+// localEmbeddingProvider hashes words, their stems and their character trigrams
+// into a fixed vector. It matches shared words and inflections ("viagem",
+// "viagens") but not synonyms, which need a model.
+type localEmbeddingProvider struct{}
+
+func NewLocalEmbeddingProvider() domain.EmbeddingProvider {
+	return localEmbeddingProvider{}
+}
+
+var localStopWords = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "de": true, "da": true, "do": true, "das": true, "dos": true,
+	"e": true, "em": true, "na": true, "no": true, "nas": true, "nos": true, "um": true, "uma": true,
+	"para": true, "pra": true, "por": true, "com": true, "que": true, "se": true, "meu": true, "minha": true,
+	"ao": true, "quanto": true, "qual": true, "como": true, "eu": true,
+}
+
+var accentFolding = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e", "ë", "e",
+	"í", "i", "î", "i", "ì", "i", "ï", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ò", "o", "ö", "o",
+	"ú", "u", "û", "u", "ù", "u", "ü", "u",
+	"ç", "c",
+)
+
+func (localEmbeddingProvider) Name() string { return ProviderLocal + "/hashing-v1" }
+
+func (localEmbeddingProvider) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = hashEmbedding(text)
+	}
+	return vectors, nil
+}
+
+func hashEmbedding(text string) []float32 {
+	vector := make([]float32, localEmbeddingDimensions)
+	words := strings.FieldsFunc(accentFolding.Replace(strings.ToLower(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if localStopWords[word] {
+			continue
+		}
+		addHashedFeature(vector, "w:"+word, 1)
+
+		// The first letters work as a crude stem for Portuguese inflections.
+		if runes := []rune(word); len(runes) > localStemRunes {
+			addHashedFeature(vector, "s:"+string(runes[:localStemRunes]), 1)
+		} else {
+			addHashedFeature(vector, "s:"+word, 1)
+		}
+
+		padded := []rune("^" + word + "$")
+		for i := 0; i+3 <= len(padded); i++ {
+			addHashedFeature(vector, "t:"+string(padded[i:i+3]), 0.5)
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vector {
+			vector[i] *= scale
+		}
+	}
+	return vector
+}
+
+// addHashedFeature uses the hash for the position and one bit for the sign, so
+// collisions tend to cancel out instead of adding up.
+func addHashedFeature(vector []float32, feature string, weight float32) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(feature))
+	sum := h.Sum32()
+	if sum&1 == 1 {
+		weight = -weight
+	}
+	vector[(sum>>1)%uint32(len(vector))] += weight
+}
@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalEmbeddingProvider(t *testing.T) {
+	provider := NewLocalEmbeddingProvider()
+
+	vectors, err := provider.Embed(context.Background(), []string{
+		"Quanto falta para as viagens?",
+		"Viagem para a Europa em julho",
+		"Trocar de carro",
+		"",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, vectors, 4)
+	related := domain.CosineSimilarity(vectors[0], vectors[1])
+	unrelated := domain.CosineSimilarity(vectors[0], vectors[2])
+	assert.Greater(t, related, unrelated+0.15)
+	assert.Zero(t, domain.CosineSimilarity(vectors[0], vectors[3]), "empty text has no features")
+	assert.Equal(t, "local/hashing-v1", provider.Name())
+}
+
+func TestOpenAICompatibleEmbeddingProvider(t *testing.T) {
+	t.Run("should return the vectors in input order", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/embeddings", r.URL.Path)
+			assert.Equal(t, "Bearer TEST-KEY", r.Header.Get("Authorization"))
+
+			var req openAIEmbeddingRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "nomic-embed-text", req.Model)
+			assert.Equal(t, []string{"férias", "carro"}, req.Input)
+
+			_, _ = w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+		}))
+		t.Cleanup(server.Close)
+		provider := NewOpenAICompatibleEmbeddingProvider(server.URL+"/v1", "TEST-KEY", "nomic-embed-text")
+
+		vectors, err := provider.Embed(context.Background(), []string{"férias", "carro"})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+		assert.Equal(t, "openai_compatible/nomic-embed-text", provider.Name())
+	})
+
+	t.Run("should fail on a non-200 response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "model not found", http.StatusNotFound)
+		}))
+		t.Cleanup(server.Close)
+
+		_, err := NewOpenAICompatibleEmbeddingProvider(server.URL, "", "missing").Embed(context.Background(), []string{"férias"})
+
+		assert.ErrorContains(t, err, "status 404")
+	})
+}
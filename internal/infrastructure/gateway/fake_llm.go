@@ -0,0 +1,112 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"strings"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// fakeScript is the fixture format of the fake provider. Each step is one model
+// turn: either tool calls (the agent runs them and asks the model again) or a
+// final text. Steps are picked by how many model turns the request already has,
+// so a run replays the script deterministically from the start.
+type fakeScript struct {
+	Steps []fakeStep `json:"steps"`
+}
+
+type fakeStep struct {
+	ToolCalls    []fakeToolCall `json:"tool_calls,omitempty"`
+	Text         string         `json:"text,omitempty"`
+	InputTokens  int32          `json:"input_tokens,omitempty"`
+	OutputTokens int32          `json:"output_tokens,omitempty"`
+}
+
+type fakeToolCall struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+var defaultFakeScript = fakeScript{
+	Steps: []fakeStep{{Text: "Resposta simulada do assistente financeiro."}},
+}
+
+func loadFakeScript(path string) (fakeScript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fakeScript{}, fmt.Errorf("failed to read fake agent fixture: %w", err)
+	}
+
+	var script fakeScript
+	if err := json.Unmarshal(data, &script); err != nil {
+		return fakeScript{}, fmt.Errorf("failed to parse fake agent fixture: %w", err)
+	}
+	if len(script.Steps) == 0 {
+		return fakeScript{}, fmt.Errorf("fake agent fixture %s has no steps", path)
+	}
+	return script, nil
+}
+
+// scriptedModel is an ADK model.LLM that replays a fakeScript.
+type scriptedModel struct {
+	script fakeScript
+}
+
+func (m *scriptedModel) Name() string {
+	return "scripted"
+}
+
+func (m *scriptedModel) GenerateContent(_ context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		turn := 0
+		for _, content := range req.Contents {
+			if content != nil && content.Role == genai.RoleModel {
+				turn++
+			}
+		}
+		if turn >= len(m.script.Steps) {
+			yield(nil, fmt.Errorf("fake agent script exhausted after %d steps", len(m.script.Steps)))
+			return
+		}
+		step := m.script.Steps[turn]
+
+		content := &genai.Content{Role: genai.RoleModel}
+		for _, call := range step.ToolCalls {
+			content.Parts = append(content.Parts, &genai.Part{
+				FunctionCall: &genai.FunctionCall{Name: call.Name, Args: call.Args},
+			})
+		}
+
+		if step.Text != "" {
+			if stream {
+				// One partial per word keeps streaming clients exercised deterministically.
+				words := strings.SplitAfter(step.Text, " ")
+				for _, word := range words {
+					partial := &model.LLMResponse{
+						Content: genai.NewContentFromText(word, genai.RoleModel),
+						Partial: true,
+					}
+					if !yield(partial, nil) {
+						return
+					}
+				}
+			}
+			content.Parts = append(content.Parts, genai.NewPartFromText(step.Text))
+		}
+
+		yield(&model.LLMResponse{
+			Content: content,
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+				PromptTokenCount:     step.InputTokens,
+				CandidatesTokenCount: step.OutputTokens,
+			},
+			TurnComplete: true,
+			FinishReason: genai.FinishReasonStop,
+		}, nil)
+	}
+}
@@ -4,18 +4,18 @@ import (
 	"context"
 	"fmt"
 
-	"firebase.google.com/go/v4/auth"
-
 	"personal-finance/internal/plataform/authentication"
 )
 
+// FirebaseGateway reads and writes the plan claims of the users in the claims
+// store of the authenticator: Firebase, or the local store in development.
 type FirebaseGateway struct {
-	authClient *auth.Client
+	claims authentication.ClaimsStore
 }
 
-func NewFirebaseGateway(authClient *auth.Client) *FirebaseGateway {
+func NewFirebaseGateway(claims authentication.ClaimsStore) *FirebaseGateway {
 	return &FirebaseGateway{
-		authClient: authClient,
+		claims: claims,
 	}
 }
 
@@ -28,13 +28,13 @@ type UserClaims struct {
 }
 
 func (g *FirebaseGateway) GetUserClaims(ctx context.Context, userID string) (UserClaims, error) {
-	user, err := g.authClient.GetUser(ctx, userID)
+	customClaims, err := g.claims.GetCustomClaims(ctx, userID)
 	if err != nil {
-		return UserClaims{}, fmt.Errorf("error getting user from firebase: %w", err)
+		return UserClaims{}, fmt.Errorf("error getting user claims: %w", err)
 	}
 
 	plan := authentication.PlanFree
-	if p, ok := user.CustomClaims["plan"].(string); ok {
+	if p, ok := customClaims["plan"].(string); ok {
 		switch authentication.Plan(p) {
 		case authentication.PlanFree, authentication.PlanPlus:
 			plan = authentication.Plan(p)
@@ -42,7 +42,7 @@ func (g *FirebaseGateway) GetUserClaims(ctx context.Context, userID string) (Use
 	}
 
 	role := authentication.RoleUser
-	if r, ok := user.CustomClaims["role"].(string); ok {
+	if r, ok := customClaims["role"].(string); ok {
 		switch authentication.Role(r) {
 		case authentication.RoleUser, authentication.RoleAdmin:
 			role = authentication.Role(r)
@@ -50,12 +50,12 @@ func (g *FirebaseGateway) GetUserClaims(ctx context.Context, userID string) (Use
 	}
 
 	mpSubscriptionID := ""
-	if mpID, ok := user.CustomClaims["mp_subscription_id"].(string); ok {
+	if mpID, ok := customClaims["mp_subscription_id"].(string); ok {
 		mpSubscriptionID = mpID
 	}
 
 	subscriptionSource := authentication.SubscriptionSourceNone
-	if source, ok := user.CustomClaims["subscription_source"].(string); ok {
+	if source, ok := customClaims["subscription_source"].(string); ok {
 		switch authentication.SubscriptionSource(source) {
 		case authentication.SubscriptionSourceMP, authentication.SubscriptionSourceIAP, authentication.SubscriptionSourceStripe:
 			subscriptionSource = authentication.SubscriptionSource(source)
@@ -63,7 +63,7 @@ func (g *FirebaseGateway) GetUserClaims(ctx context.Context, userID string) (Use
 	}
 
 	planExpiresAt := int64(0)
-	if expiresAt, ok := user.CustomClaims["plan_expires_at"].(float64); ok {
+	if expiresAt, ok := customClaims["plan_expires_at"].(float64); ok {
 		planExpiresAt = int64(expiresAt)
 	}
 
@@ -77,12 +77,11 @@ func (g *FirebaseGateway) GetUserClaims(ctx context.Context, userID string) (Use
 }
 
 func (g *FirebaseGateway) SetUserPlan(ctx context.Context, userID string, plan authentication.Plan, expiresAt *int64) error {
-	user, err := g.authClient.GetUser(ctx, userID)
+	claims, err := g.claims.GetCustomClaims(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("error getting user from firebase: %w", err)
+		return fmt.Errorf("error getting user claims: %w", err)
 	}
 
-	claims := user.CustomClaims
 	if claims == nil {
 		claims = make(map[string]interface{})
 	}
@@ -99,7 +98,7 @@ func (g *FirebaseGateway) SetUserPlan(ctx context.Context, userID string, plan a
 		delete(claims, "plan_expires_at")
 	}
 
-	err = g.authClient.SetCustomUserClaims(ctx, userID, claims)
+	err = g.claims.SetCustomClaims(ctx, userID, claims)
 	if err != nil {
 		return fmt.Errorf("error setting custom claims: %w", err)
 	}
@@ -108,12 +107,11 @@ func (g *FirebaseGateway) SetUserPlan(ctx context.Context, userID string, plan a
 }
 
 func (g *FirebaseGateway) SetUserSubscription(ctx context.Context, userID string, plan authentication.Plan, mpSubscriptionID string, subscriptionSource authentication.SubscriptionSource, expiresAt int64) error {
-	user, err := g.authClient.GetUser(ctx, userID)
+	claims, err := g.claims.GetCustomClaims(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("error getting user from firebase: %w", err)
+		return fmt.Errorf("error getting user claims: %w", err)
 	}
 
-	claims := user.CustomClaims
 	if claims == nil {
 		claims = make(map[string]interface{})
 	}
@@ -137,7 +135,7 @@ func (g *FirebaseGateway) SetUserSubscription(ctx context.Context, userID string
 		delete(claims, "plan_expires_at")
 	}
 
-	err = g.authClient.SetCustomUserClaims(ctx, userID, claims)
+	err = g.claims.SetCustomClaims(ctx, userID, claims)
 	if err != nil {
 		return fmt.Errorf("error setting custom claims: %w", err)
 	}
@@ -146,19 +144,18 @@ func (g *FirebaseGateway) SetUserSubscription(ctx context.Context, userID string
 }
 
 func (g *FirebaseGateway) SetUserRole(ctx context.Context, userID string, role authentication.Role) error {
-	user, err := g.authClient.GetUser(ctx, userID)
+	claims, err := g.claims.GetCustomClaims(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("error getting user from firebase: %w", err)
+		return fmt.Errorf("error getting user claims: %w", err)
 	}
 
-	claims := user.CustomClaims
 	if claims == nil {
 		claims = make(map[string]interface{})
 	}
 
 	claims["role"] = string(role)
 
-	err = g.authClient.SetCustomUserClaims(ctx, userID, claims)
+	err = g.claims.SetCustomClaims(ctx, userID, claims)
 	if err != nil {
 		return fmt.Errorf("error setting custom claims: %w", err)
 	}
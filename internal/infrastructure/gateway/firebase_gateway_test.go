@@ -0,0 +1,45 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirebaseGateway_LocalClaimsStore(t *testing.T) {
+	ctx := context.Background()
+	g := NewFirebaseGateway(authentication.NewLocalClaimsStore(""))
+
+	t.Run("should default an unknown user to the free plan", func(t *testing.T) {
+		claims, err := g.GetUserClaims(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, authentication.PlanFree, claims.Plan)
+		assert.Equal(t, authentication.RoleUser, claims.Role)
+	})
+
+	t.Run("should update plan and role", func(t *testing.T) {
+		expiresAt := int64(1900000000)
+		require.NoError(t, g.SetUserPlan(ctx, "user-1", authentication.PlanPlus, &expiresAt))
+		require.NoError(t, g.SetUserRole(ctx, "user-1", authentication.RoleAdmin))
+
+		claims, err := g.GetUserClaims(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, authentication.PlanPlus, claims.Plan)
+		assert.Equal(t, authentication.RoleAdmin, claims.Role)
+		assert.Equal(t, expiresAt, claims.PlanExpiresAt)
+	})
+
+	t.Run("should update the subscription", func(t *testing.T) {
+		require.NoError(t, g.SetUserSubscription(ctx, "user-1", authentication.PlanPlus, "", authentication.SubscriptionSourceStripe, 0))
+
+		claims, err := g.GetUserClaims(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, authentication.SubscriptionSourceStripe, claims.SubscriptionSource)
+		assert.Zero(t, claims.PlanExpiresAt)
+		assert.Equal(t, authentication.RoleAdmin, claims.Role)
+	})
+}
@@ -53,14 +53,14 @@ func (g *GeminiClassificationGateway) ClassifyMovements(
 	ctx context.Context,
 	movements []domain.ExtractedMovement,
 	categories []domain.Category,
-) ([]domain.CategorySuggestion, error) {
+) ([]domain.CategorySuggestion, domain.AIUsage, error) {
 	if len(movements) == 0 {
-		return nil, nil
+		return nil, domain.AIUsage{}, nil
 	}
 
 	categoriesJSON, err := buildCategoriesJSON(categories)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build categories JSON: %w", err)
+		return nil, domain.AIUsage{}, fmt.Errorf("failed to build categories JSON: %w", err)
 	}
 
 	requests := make([]classificationRequest, len(movements))
@@ -74,7 +74,7 @@ func (g *GeminiClassificationGateway) ClassifyMovements(
 
 	movementsJSON, err := json.Marshal(requests)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal movements for classification: %w", err)
+		return nil, domain.AIUsage{}, fmt.Errorf("failed to marshal movements for classification: %w", err)
 	}
 
 	prompt := buildClassificationPrompt(categoriesJSON, string(movementsJSON))
@@ -85,17 +85,17 @@ func (g *GeminiClassificationGateway) ClassifyMovements(
 		Backend:  genai.BackendVertexAI,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create genai client: %w", err)
+		return nil, domain.AIUsage{}, fmt.Errorf("failed to create genai client: %w", err)
 	}
 
 	resp, err := client.Models.GenerateContent(ctx, g.modelName, []*genai.Content{
 		genai.NewContentFromParts([]*genai.Part{genai.NewPartFromText(prompt)}, "user"),
 	}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("gemini classification call failed: %w", err)
+		return nil, domain.AIUsage{}, fmt.Errorf("gemini classification call failed: %w", err)
 	}
 
-	recordTokenUsage(ctx, "statement_classify", g.modelName, resp)
+	usage := recordTokenUsage(ctx, "statement_classify", g.modelName, resp)
 
 	var responseText string
 	if resp != nil && len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
@@ -107,17 +107,17 @@ func (g *GeminiClassificationGateway) ClassifyMovements(
 	}
 
 	if responseText == "" {
-		return buildFallbackSuggestions(movements), nil
+		return buildFallbackSuggestions(movements), usage, nil
 	}
 
 	responseText = cleanJSONResponse(responseText)
 
 	var rawResults []classificationResponse
 	if err := json.Unmarshal([]byte(responseText), &rawResults); err != nil {
-		return buildFallbackSuggestions(movements), nil
+		return buildFallbackSuggestions(movements), usage, nil
 	}
 
-	return mapClassificationResults(movements, rawResults), nil
+	return mapClassificationResults(movements, rawResults), usage, nil
 }
 
 func buildCategoriesJSON(categories []domain.Category) (string, error) {
@@ -87,7 +87,7 @@ func (g *GeminiVisionGateway) ExtractMovements(ctx context.Context, fileBytes []
 		return domain.StatementExtractResult{}, fmt.Errorf("gemini vision call failed: %w", err)
 	}
 
-	recordTokenUsage(ctx, "statement_extract", g.modelName, resp)
+	usage := recordTokenUsage(ctx, "statement_extract", g.modelName, resp)
 
 	var responseText string
 	if resp != nil && len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
@@ -135,20 +135,23 @@ func (g *GeminiVisionGateway) ExtractMovements(ctx context.Context, fileBytes []
 	return domain.StatementExtractResult{
 		Movements: valid,
 		Errors:    errors,
+		Usage:     usage,
 	}, nil
 }
 
 // recordTokenUsage emits the unified biz_ai_tokens_total KPI from a Gemini
-// GenerateContent response, attributing the cost to the given feature/model.
-// It is a no-op when the model returns no usage metadata.
-func recordTokenUsage(ctx context.Context, feature, model string, resp *genai.GenerateContentResponse) {
+// GenerateContent response, attributing the cost to the given feature/model,
+// and returns the usage of the call for the user quota. Tokens are zero when
+// the model returns no usage metadata.
+func recordTokenUsage(ctx context.Context, feature, model string, resp *genai.GenerateContentResponse) domain.AIUsage {
+	usage := domain.AIUsage{Requests: 1}
 	if resp == nil || resp.UsageMetadata == nil {
-		return
+		return usage
 	}
-	metrics.IncAITokens(ctx, feature, model,
-		int(resp.UsageMetadata.PromptTokenCount),
-		int(resp.UsageMetadata.CandidatesTokenCount),
-	)
+	usage.InputTokens = int64(resp.UsageMetadata.PromptTokenCount)
+	usage.OutputTokens = int64(resp.UsageMetadata.CandidatesTokenCount)
+	metrics.IncAITokens(ctx, feature, model, int(usage.InputTokens), int(usage.OutputTokens))
+	return usage
 }
 
 func cleanJSONResponse(s string) string {
@@ -0,0 +1,165 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/gemini"
+	"google.golang.org/genai"
+
+	"personal-finance/pkg/log"
+)
+
+const (
+	ProviderVertexAI         = "vertex_ai"
+	ProviderOpenAICompatible = "openai_compatible"
+	ProviderFake             = "fake"
+
+	defaultOpenAIModel = "llama3"
+	openAIHTTPTimeout  = 120 * time.Second
+)
+
+// LLMProvider builds the model that drives the ADK agent and identifies it in the
+// audit trail (LGPD Art. 37), so records reflect where the data was processed.
+type LLMProvider interface {
+	Name() string
+	Region() string
+	ModelName() string
+	NewModel(ctx context.Context) (model.LLM, error)
+}
+
+// NewLLMProviderFromEnv picks the provider from AGENT_LLM_PROVIDER:
+//   - vertex_ai (default): Gemini on Vertex AI (GOOGLE_PROJECT_ID, GOOGLE_CLOUD_LOCATION, VERTEX_MODEL)
+//   - openai_compatible: any /chat/completions endpoint (OPENAI_BASE_URL, OPENAI_API_KEY, OPENAI_MODEL, AGENT_LLM_REGION)
+//   - fake: scripted replies replayed from AGENT_FAKE_FIXTURE, for dev and CI without GCP
+func NewLLMProviderFromEnv() LLMProvider {
+	switch name := os.Getenv("AGENT_LLM_PROVIDER"); name {
+	case "", ProviderVertexAI:
+		return newVertexProviderFromEnv()
+	case ProviderOpenAICompatible:
+		modelName := os.Getenv("OPENAI_MODEL")
+		if modelName == "" {
+			modelName = defaultOpenAIModel
+		}
+		return NewOpenAICompatibleProvider(
+			os.Getenv("OPENAI_BASE_URL"),
+			os.Getenv("OPENAI_API_KEY"),
+			modelName,
+			os.Getenv("AGENT_LLM_REGION"),
+		)
+	case ProviderFake:
+		return NewFakeProvider(os.Getenv("AGENT_FAKE_FIXTURE"))
+	default:
+		log.Error("unknown AGENT_LLM_PROVIDER, falling back to vertex_ai", log.String("provider", name))
+		return newVertexProviderFromEnv()
+	}
+}
+
+// --- Vertex AI ---
+
+type vertexProvider struct {
+	projectID string
+	location  string
+	modelName string
+}
+
+func newVertexProviderFromEnv() *vertexProvider {
+	location := os.Getenv("GOOGLE_CLOUD_LOCATION")
+	if location == "" {
+		location = defaultLocation
+	}
+
+	modelName := os.Getenv("VERTEX_MODEL")
+	if modelName == "" {
+		modelName = defaultModel
+	}
+
+	return &vertexProvider{
+		projectID: os.Getenv("GOOGLE_PROJECT_ID"),
+		location:  location,
+		modelName: modelName,
+	}
+}
+
+func (p *vertexProvider) Name() string      { return ProviderVertexAI }
+func (p *vertexProvider) Region() string    { return p.location }
+func (p *vertexProvider) ModelName() string { return p.modelName }
+
+func (p *vertexProvider) NewModel(ctx context.Context) (model.LLM, error) {
+	llm, err := gemini.NewModel(ctx, p.modelName, &genai.ClientConfig{
+		Project:  p.projectID,
+		Location: p.location,
+		Backend:  genai.BackendVertexAI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gemini model: %w", err)
+	}
+	return llm, nil
+}
+
+// --- OpenAI-compatible ---
+
+type openAICompatibleProvider struct {
+	baseURL   string
+	apiKey    string
+	modelName string
+	region    string
+	client    *http.Client
+}
+
+func NewOpenAICompatibleProvider(baseURL, apiKey, modelName, region string) LLMProvider {
+	return &openAICompatibleProvider{
+		baseURL:   baseURL,
+		apiKey:    apiKey,
+		modelName: modelName,
+		region:    region,
+		client:    &http.Client{Timeout: openAIHTTPTimeout},
+	}
+}
+
+func (p *openAICompatibleProvider) Name() string      { return ProviderOpenAICompatible }
+func (p *openAICompatibleProvider) Region() string    { return p.region }
+func (p *openAICompatibleProvider) ModelName() string { return p.modelName }
+
+func (p *openAICompatibleProvider) NewModel(_ context.Context) (model.LLM, error) {
+	if p.baseURL == "" {
+		return nil, fmt.Errorf("OPENAI_BASE_URL is required for the %s provider", ProviderOpenAICompatible)
+	}
+	return &openAICompatibleModel{
+		baseURL:   p.baseURL,
+		apiKey:    p.apiKey,
+		modelName: p.modelName,
+		client:    p.client,
+	}, nil
+}
+
+// --- Fake ---
+
+type fakeProvider struct {
+	fixturePath string
+}
+
+// NewFakeProvider replays the script at fixturePath; an empty path uses a single canned reply.
+func NewFakeProvider(fixturePath string) LLMProvider {
+	return &fakeProvider{fixturePath: fixturePath}
+}
+
+func (p *fakeProvider) Name() string      { return ProviderFake }
+func (p *fakeProvider) Region() string    { return "local" }
+func (p *fakeProvider) ModelName() string { return "scripted" }
+
+func (p *fakeProvider) NewModel(_ context.Context) (model.LLM, error) {
+	if p.fixturePath == "" {
+		return &scriptedModel{script: defaultFakeScript}, nil
+	}
+
+	script, err := loadFakeScript(p.fixturePath)
+	if err != nil {
+		return nil, err
+	}
+	return &scriptedModel{script: script}, nil
+}
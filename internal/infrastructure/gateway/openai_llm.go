@@ -0,0 +1,345 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"sort"
+	"strings"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// openAICompatibleModel is an ADK model.LLM backed by an OpenAI-compatible
+// /chat/completions endpoint (OpenAI, llama.cpp server, vLLM, Ollama...).
+type openAICompatibleModel struct {
+	baseURL   string
+	apiKey    string
+	modelName string
+	client    *http.Client
+}
+
+// --- Wire DTOs ---
+
+type openAIChatRequest struct {
+	Model         string               `json:"model"`
+	Messages      []openAIMessage      `json:"messages"`
+	Tools         []openAITool         `json:"tools,omitempty"`
+	Stream        bool                 `json:"stream,omitempty"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAITool struct {
+	Type     string             `json:"type"`
+	Function openAIToolFunction `json:"function"`
+}
+
+type openAIToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+type openAIToolCall struct {
+	Index    int                `json:"index"`
+	ID       string             `json:"id,omitempty"`
+	Type     string             `json:"type,omitempty"`
+	Function openAIFunctionCall `json:"function"`
+}
+
+type openAIFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int32 `json:"prompt_tokens"`
+	CompletionTokens int32 `json:"completion_tokens"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		Delta        openAIMessage `json:"delta"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// --- model.LLM ---
+
+func (m *openAICompatibleModel) Name() string {
+	return m.modelName
+}
+
+func (m *openAICompatibleModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		body, err := json.Marshal(m.buildRequest(req, stream))
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to encode chat request: %w", err))
+			return
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(m.baseURL, "/")+"/chat/completions", bytes.NewReader(body))
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to build chat request: %w", err))
+			return
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if m.apiKey != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+m.apiKey)
+		}
+
+		resp, err := m.client.Do(httpReq)
+		if err != nil {
+			yield(nil, fmt.Errorf("chat completion request failed: %w", err))
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			yield(nil, fmt.Errorf("chat completion returned status %d: %s", resp.StatusCode, string(msg)))
+			return
+		}
+
+		if stream {
+			m.readStream(resp.Body, yield)
+			return
+		}
+
+		var decoded openAIChatResponse
+		if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+			yield(nil, fmt.Errorf("failed to decode chat completion: %w", err))
+			return
+		}
+		if len(decoded.Choices) == 0 {
+			yield(nil, fmt.Errorf("chat completion returned no choices"))
+			return
+		}
+
+		message := decoded.Choices[0].Message
+		yield(toLLMResponse(message.Content, message.ToolCalls, decoded.Usage), nil)
+	}
+}
+
+// readStream forwards text deltas as partial responses and closes with the
+// aggregated response, the same contract the Gemini model follows.
+func (m *openAICompatibleModel) readStream(body io.Reader, yield func(*model.LLMResponse, error) bool) {
+	var text strings.Builder
+	calls := map[int]*openAIToolCall{}
+	var usage *openAIUsage
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIChatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			yield(nil, fmt.Errorf("failed to decode chat completion chunk: %w", err))
+			return
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta
+		for _, call := range delta.ToolCalls {
+			acc, ok := calls[call.Index]
+			if !ok {
+				acc = &openAIToolCall{Index: call.Index}
+				calls[call.Index] = acc
+			}
+			if call.ID != "" {
+				acc.ID = call.ID
+			}
+			if call.Function.Name != "" {
+				acc.Function.Name = call.Function.Name
+			}
+			acc.Function.Arguments += call.Function.Arguments
+		}
+
+		if delta.Content != "" {
+			text.WriteString(delta.Content)
+			partial := &model.LLMResponse{
+				Content: genai.NewContentFromText(delta.Content, genai.RoleModel),
+				Partial: true,
+			}
+			if !yield(partial, nil) {
+				return
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		yield(nil, fmt.Errorf("failed to read chat completion stream: %w", err))
+		return
+	}
+
+	indexes := make([]int, 0, len(calls))
+	for index := range calls {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	toolCalls := make([]openAIToolCall, 0, len(indexes))
+	for _, index := range indexes {
+		toolCalls = append(toolCalls, *calls[index])
+	}
+
+	yield(toLLMResponse(text.String(), toolCalls, usage), nil)
+}
+
+func (m *openAICompatibleModel) buildRequest(req *model.LLMRequest, stream bool) openAIChatRequest {
+	chatReq := openAIChatRequest{Model: m.modelName, Stream: stream}
+	if stream {
+		chatReq.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+
+	if req.Config != nil {
+		if system := contentText(req.Config.SystemInstruction); system != "" {
+			chatReq.Messages = append(chatReq.Messages, openAIMessage{Role: "system", Content: system})
+		}
+
+		for _, t := range req.Config.Tools {
+			if t == nil {
+				continue
+			}
+			for _, decl := range t.FunctionDeclarations {
+				var params any = decl.ParametersJsonSchema
+				if params == nil && decl.Parameters != nil {
+					params = decl.Parameters
+				}
+				chatReq.Tools = append(chatReq.Tools, openAITool{
+					Type: "function",
+					Function: openAIToolFunction{
+						Name:        decl.Name,
+						Description: decl.Description,
+						Parameters:  params,
+					},
+				})
+			}
+		}
+	}
+
+	for _, content := range req.Contents {
+		chatReq.Messages = append(chatReq.Messages, toOpenAIMessages(content)...)
+	}
+
+	return chatReq
+}
+
+// toOpenAIMessages maps a genai content to chat messages. Function responses
+// become one "tool" message each, matched to the call by its ID.
+func toOpenAIMessages(content *genai.Content) []openAIMessage {
+	if content == nil {
+		return nil
+	}
+
+	var messages []openAIMessage
+	var text strings.Builder
+	var toolCalls []openAIToolCall
+
+	for _, part := range content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			args, _ := json.Marshal(part.FunctionCall.Args)
+			toolCalls = append(toolCalls, openAIToolCall{
+				ID:       part.FunctionCall.ID,
+				Type:     "function",
+				Function: openAIFunctionCall{Name: part.FunctionCall.Name, Arguments: string(args)},
+			})
+		case part.FunctionResponse != nil:
+			response, _ := json.Marshal(part.FunctionResponse.Response)
+			messages = append(messages, openAIMessage{
+				Role:       "tool",
+				Content:    string(response),
+				ToolCallID: part.FunctionResponse.ID,
+			})
+		case part.Text != "" && !part.Thought:
+			text.WriteString(part.Text)
+		}
+	}
+
+	if text.Len() > 0 || len(toolCalls) > 0 {
+		role := "user"
+		if content.Role == genai.RoleModel {
+			role = "assistant"
+		}
+		messages = append(messages, openAIMessage{Role: role, Content: text.String(), ToolCalls: toolCalls})
+	}
+
+	return messages
+}
+
+func toLLMResponse(text string, toolCalls []openAIToolCall, usage *openAIUsage) *model.LLMResponse {
+	content := &genai.Content{Role: genai.RoleModel}
+	if text != "" {
+		content.Parts = append(content.Parts, genai.NewPartFromText(text))
+	}
+	for _, call := range toolCalls {
+		var args map[string]any
+		if call.Function.Arguments != "" {
+			_ = json.Unmarshal([]byte(call.Function.Arguments), &args)
+		}
+		content.Parts = append(content.Parts, &genai.Part{
+			FunctionCall: &genai.FunctionCall{ID: call.ID, Name: call.Function.Name, Args: args},
+		})
+	}
+
+	resp := &model.LLMResponse{
+		Content:      content,
+		TurnComplete: true,
+		FinishReason: genai.FinishReasonStop,
+	}
+	if usage != nil {
+		resp.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:     usage.PromptTokens,
+			CandidatesTokenCount: usage.CompletionTokens,
+		}
+	}
+	return resp
+}
+
+func contentText(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range content.Parts {
+		if part.Text != "" {
+			if sb.Len() > 0 {
+				sb.WriteString("\n\n")
+			}
+			sb.WriteString(part.Text)
+		}
+	}
+	return sb.String()
+}
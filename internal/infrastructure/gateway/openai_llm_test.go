@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func newTestOpenAIModel(t *testing.T, handler http.HandlerFunc) *openAICompatibleModel {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	llm, err := NewOpenAICompatibleProvider(server.URL+"/v1", "TEST-KEY", "llama3", "local").NewModel(context.Background())
+	require.NoError(t, err)
+	return llm.(*openAICompatibleModel)
+}
+
+func TestOpenAICompatibleModel_GenerateContent(t *testing.T) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			genai.NewContentFromText("quanto gastei?", genai.RoleUser),
+			{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "get_movements", Args: map[string]any{"limit": 5}}}}},
+			{Role: genai.RoleUser, Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{ID: "call-1", Name: "get_movements", Response: map[string]any{"total": 2}}}}},
+		},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText("você é um assistente", genai.RoleUser),
+			Tools: []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{
+				Name:                 "get_movements",
+				Description:          "lista movimentações",
+				ParametersJsonSchema: map[string]any{"type": "object"},
+			}}}},
+		},
+	}
+
+	t.Run("should map the conversation and decode tool calls", func(t *testing.T) {
+		var captured openAIChatRequest
+		llm := newTestOpenAIModel(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+			assert.Equal(t, "Bearer TEST-KEY", r.Header.Get("Authorization"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"","tool_calls":[{"id":"call-2","type":"function","function":{"name":"get_budget_status","arguments":"{\"month\":3}"}}]}}],"usage":{"prompt_tokens":50,"completion_tokens":7}}`))
+		})
+
+		var responses []*model.LLMResponse
+		for resp, err := range llm.GenerateContent(context.Background(), req, false) {
+			require.NoError(t, err)
+			responses = append(responses, resp)
+		}
+
+		require.Len(t, captured.Messages, 4)
+		assert.Equal(t, "system", captured.Messages[0].Role)
+		assert.Equal(t, "user", captured.Messages[1].Role)
+		assert.Equal(t, "assistant", captured.Messages[2].Role)
+		require.Len(t, captured.Messages[2].ToolCalls, 1)
+		assert.Equal(t, "call-1", captured.Messages[2].ToolCalls[0].ID)
+		assert.Equal(t, "tool", captured.Messages[3].Role)
+		assert.Equal(t, "call-1", captured.Messages[3].ToolCallID)
+		require.Len(t, captured.Tools, 1)
+		assert.Equal(t, "get_movements", captured.Tools[0].Function.Name)
+
+		require.Len(t, responses, 1)
+		call := responses[0].Content.Parts[0].FunctionCall
+		require.NotNil(t, call)
+		assert.Equal(t, "get_budget_status", call.Name)
+		assert.Equal(t, float64(3), call.Args["month"])
+		assert.Equal(t, int32(50), responses[0].UsageMetadata.PromptTokenCount)
+		assert.Equal(t, int32(7), responses[0].UsageMetadata.CandidatesTokenCount)
+	})
+
+	t.Run("should stream text deltas and close with the aggregated response", func(t *testing.T) {
+		llm := newTestOpenAIModel(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Olá, \"}}]}\n\n" +
+				"data: {\"choices\":[{\"delta\":{\"content\":\"tudo certo.\"}}]}\n\n" +
+				"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":10,\"completion_tokens\":4}}\n\n" +
+				"data: [DONE]\n\n"))
+		})
+
+		var responses []*model.LLMResponse
+		for resp, err := range llm.GenerateContent(context.Background(), req, true) {
+			require.NoError(t, err)
+			responses = append(responses, resp)
+		}
+
+		require.Len(t, responses, 3)
+		assert.True(t, responses[0].Partial)
+		assert.Equal(t, "Olá, ", responses[0].Content.Parts[0].Text)
+		assert.True(t, responses[1].Partial)
+		final := responses[2]
+		assert.False(t, final.Partial)
+		assert.Equal(t, "Olá, tudo certo.", final.Content.Parts[0].Text)
+		assert.Equal(t, int32(4), final.UsageMetadata.CandidatesTokenCount)
+	})
+
+	t.Run("should return an error on non-200 status", func(t *testing.T) {
+		llm := newTestOpenAIModel(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		var gotErr error
+		for _, err := range llm.GenerateContent(context.Background(), req, false) {
+			gotErr = err
+		}
+
+		assert.ErrorContains(t, gotErr, "status 503")
+	})
+}
@@ -41,6 +41,8 @@ type StripeCheckoutParams struct {
 	CancelURL       string
 	PromotionCodeID string
 	RedemptionID    string
+	// TrialDays starts the subscription with a free trial when positive.
+	TrialDays int
 }
 
 func (g *StripeGateway) CreateCheckoutSession(ctx context.Context, p StripeCheckoutParams) (string, error) {
@@ -81,6 +83,10 @@ func (g *StripeGateway) CreateCheckoutSession(ctx context.Context, p StripeCheck
 		}
 	}
 
+	if p.TrialDays > 0 {
+		params.SubscriptionData.TrialPeriodDays = stripe.Int64(int64(p.TrialDays))
+	}
+
 	sess, err := session.New(params)
 	if err != nil {
 		return "", fmt.Errorf("error creating stripe checkout session: %w", err)
@@ -118,6 +124,26 @@ func (g *StripeGateway) ValidatePromotionCode(ctx context.Context, code string)
 	return StripePromotionCode{}, false, nil
 }
 
+// CreatePromotionCode creates a single-use promotion code for the Stripe
+// coupon.
+func (g *StripeGateway) CreatePromotionCode(ctx context.Context, couponID, code string) (StripePromotionCode, error) {
+	params := &stripe.PromotionCodeParams{
+		Code:           stripe.String(code),
+		MaxRedemptions: stripe.Int64(1),
+		Promotion: &stripe.PromotionCodePromotionParams{
+			Type:   stripe.String("coupon"),
+			Coupon: stripe.String(couponID),
+		},
+	}
+	params.Context = ctx
+
+	pc, err := promotioncode.New(params)
+	if err != nil {
+		return StripePromotionCode{}, fmt.Errorf("error creating stripe promotion code: %w", err)
+	}
+	return StripePromotionCode{ID: pc.ID, Code: pc.Code, Active: pc.Active, CouponID: couponID}, nil
+}
+
 func (g *StripeGateway) CancelSubscription(ctx context.Context, subID string, atPeriodEnd bool) error {
 	if atPeriodEnd {
 		params := &stripe.SubscriptionParams{CancelAtPeriodEnd: stripe.Bool(true)}
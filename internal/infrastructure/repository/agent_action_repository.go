@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AgentActionRepository struct {
+	db *gorm.DB
+}
+
+func NewAgentActionRepository(db *gorm.DB) *AgentActionRepository {
+	return &AgentActionRepository{db: db}
+}
+
+func (r *AgentActionRepository) Save(ctx context.Context, action domain.AgentAction) (domain.AgentAction, error) {
+	dbModel := FromAgentActionDomain(action)
+	err := r.db.WithContext(ctx).Create(&dbModel).Error
+	if err != nil {
+		return domain.AgentAction{}, fmt.Errorf("error saving agent action: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return dbModel.ToDomain(), nil
+}
+
+func (r *AgentActionRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.AgentAction, error) {
+	var dbModel AgentActionDB
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&dbModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.AgentAction{}, fmt.Errorf("agent action not found: %w", domain.ErrAgentActionNotFound)
+		}
+		return domain.AgentAction{}, fmt.Errorf("error finding agent action: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return dbModel.ToDomain(), nil
+}
+
+func (r *AgentActionRepository) FindPendingByUserID(ctx context.Context, userID string) ([]domain.AgentAction, error) {
+	var dbModels []AgentActionDB
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, string(domain.AgentActionStatusPending)).
+		Order("created_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding pending agent actions: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	actions := make([]domain.AgentAction, len(dbModels))
+	for i, dbModel := range dbModels {
+		actions[i] = dbModel.ToDomain()
+	}
+	return actions, nil
+}
+
+func (r *AgentActionRepository) FindAllByUserID(ctx context.Context, userID string) ([]domain.AgentAction, error) {
+	var dbModels []AgentActionDB
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding agent actions: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	actions := make([]domain.AgentAction, len(dbModels))
+	for i, dbModel := range dbModels {
+		actions[i] = dbModel.ToDomain()
+	}
+	return actions, nil
+}
+
+// UpdateStatus persists the action's status and outcome, but only if the stored
+// status is still from. Concurrent confirmations of the same action therefore
+// cannot both claim it.
+func (r *AgentActionRepository) UpdateStatus(ctx context.Context, action domain.AgentAction, from domain.AgentActionStatus) error {
+	result := r.db.WithContext(ctx).
+		Model(&AgentActionDB{}).
+		Where("id = ? AND status = ?", action.ID, string(from)).
+		Updates(map[string]any{
+			"status":      string(action.Status),
+			"result_id":   action.ResultID,
+			"error":       action.Error,
+			"resolved_at": action.ResolvedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("error updating agent action status: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAgentActionNotPending
+	}
+	return nil
+}
+
+func (r *AgentActionRepository) DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&AgentActionDB{}).Error
+	if err != nil {
+		return fmt.Errorf("error deleting agent actions: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
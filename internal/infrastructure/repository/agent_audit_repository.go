@@ -28,6 +28,24 @@ func (r *AgentAuditRepository) Log(ctx context.Context, record domain.AgentAudit
 	return nil
 }
 
+// FindByUserID returns the audit records of the user, oldest first.
+func (r *AgentAuditRepository) FindByUserID(ctx context.Context, userID string) ([]domain.AgentAuditRecord, error) {
+	var dbModels []AgentAuditLogDB
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding audit logs: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	records := make([]domain.AgentAuditRecord, len(dbModels))
+	for i, dbModel := range dbModels {
+		records[i] = dbModel.ToDomain()
+	}
+	return records, nil
+}
+
 func (r *AgentAuditRepository) DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
 	db := r.db
 	if tx != nil {
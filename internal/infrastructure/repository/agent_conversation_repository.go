@@ -79,6 +79,34 @@ func (r *AgentConversationRepository) FindByUserID(ctx context.Context, userID s
 	return convs, nil
 }
 
+// FindAllWithMessagesByUserID returns every conversation of the user with its
+// messages, for the data export.
+func (r *AgentConversationRepository) FindAllWithMessagesByUserID(ctx context.Context, userID string) ([]domain.AgentConversation, error) {
+	convs, err := r.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgModels []AgentMessageDB
+	err = r.db.WithContext(ctx).
+		Where("conversation_id IN (SELECT id FROM agent_conversations WHERE user_id = ?)", userID).
+		Order("created_at ASC").
+		Find(&msgModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading messages: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	byConversation := make(map[uuid.UUID][]domain.AgentMessage, len(convs))
+	for _, msg := range msgModels {
+		m := msg.ToDomain()
+		byConversation[m.ConversationID] = append(byConversation[m.ConversationID], m)
+	}
+	for i := range convs {
+		convs[i].Messages = byConversation[convs[i].ID]
+	}
+	return convs, nil
+}
+
 func (r *AgentConversationRepository) SaveMessage(ctx context.Context, msg domain.AgentMessage) (domain.AgentMessage, error) {
 	dbModel := FromAgentMessageDomain(msg)
 	err := r.db.WithContext(ctx).Create(&dbModel).Error
@@ -109,6 +137,21 @@ func (r *AgentConversationRepository) UpdateTitle(ctx context.Context, id uuid.U
 	return nil
 }
 
+// UpdateSummary stores the rolling summary and how many leading messages it covers.
+func (r *AgentConversationRepository) UpdateSummary(ctx context.Context, id uuid.UUID, summary string, summarizedMessages int) error {
+	err := r.db.WithContext(ctx).
+		Model(&AgentConversationDB{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"summary":             summary,
+			"summarized_messages": summarizedMessages,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("error updating conversation summary: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
 func (r *AgentConversationRepository) DeleteExpired(ctx context.Context) (int64, error) {
 	result := r.db.WithContext(ctx).
 		Where("expires_at < ?", time.Now()).
@@ -119,6 +162,25 @@ func (r *AgentConversationRepository) DeleteExpired(ctx context.Context) (int64,
 	return result.RowsAffected, nil
 }
 
+// Delete removes the conversation and its messages. The audit records of the
+// conversation are kept, as the record of the processing.
+func (r *AgentConversationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("conversation_id = ?", id).Delete(&AgentMessageDB{}).Error; err != nil {
+			return fmt.Errorf("error deleting agent messages: %w: %s", ErrDatabaseError, err.Error())
+		}
+
+		result := tx.Where("id = ?", id).Delete(&AgentConversationDB{})
+		if result.Error != nil {
+			return fmt.Errorf("error deleting agent conversation: %w: %s", ErrDatabaseError, result.Error.Error())
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("agent conversation not found: %w", domain.ErrNotFound)
+		}
+		return nil
+	})
+}
+
 func (r *AgentConversationRepository) DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
 	db := r.db
 	if tx != nil {
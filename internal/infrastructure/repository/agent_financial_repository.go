@@ -57,6 +57,7 @@ type overviewRow struct {
 }
 
 type walletRow struct {
+	ID      string  `gorm:"column:id"`
 	Name    string  `gorm:"column:name"`
 	Balance float64 `gorm:"column:balance"`
 }
@@ -94,7 +95,7 @@ func (r *AgentFinancialRepository) GetFinancialOverview(ctx context.Context, mon
 
 	var walletRows []walletRow
 	err = r.db.WithContext(ctx).Raw(`
-		SELECT description AS name, balance
+		SELECT id, description AS name, balance
 		FROM wallets
 		WHERE user_id = ?
 		ORDER BY balance DESC
@@ -105,7 +106,7 @@ func (r *AgentFinancialRepository) GetFinancialOverview(ctx context.Context, mon
 
 	wallets := make([]domain.AgentWalletItem, 0, len(walletRows))
 	for _, w := range walletRows {
-		wallets = append(wallets, domain.AgentWalletItem{Name: w.Name, Balance: w.Balance})
+		wallets = append(wallets, domain.AgentWalletItem{ID: w.ID, Name: w.Name, Balance: w.Balance})
 	}
 
 	return domain.AgentFinancialOverview{
@@ -120,6 +121,7 @@ func (r *AgentFinancialRepository) GetFinancialOverview(ctx context.Context, mon
 // --- GetSpendingBreakdown ---
 
 type spendingRow struct {
+	CategoryID   string  `gorm:"column:category_id"`
 	CategoryName string  `gorm:"column:category_name"`
 	IsIncome     bool    `gorm:"column:is_income"`
 	Amount       float64 `gorm:"column:amount"`
@@ -133,6 +135,7 @@ func (r *AgentFinancialRepository) GetSpendingBreakdown(ctx context.Context, mon
 	var rows []spendingRow
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT
+			COALESCE(CAST(c.id AS TEXT), '') AS category_id,
 			COALESCE(c.description, 'Sem categoria') AS category_name,
 			COALESCE(c.is_income, false) AS is_income,
 			SUM(m.amount) AS amount
@@ -143,7 +146,7 @@ func (r *AgentFinancialRepository) GetSpendingBreakdown(ctx context.Context, mon
 		  AND m.date >= ?
 		  AND m.date < ?
 		  AND m.type_payment NOT IN ('invoice_payment', 'internal_transfer')
-		GROUP BY c.description, c.is_income
+		GROUP BY c.id, c.description, c.is_income
 		ORDER BY ABS(SUM(m.amount)) DESC
 	`, userID, p.start, p.end).Scan(&rows).Error
 	if err != nil {
@@ -169,6 +172,7 @@ func (r *AgentFinancialRepository) GetSpendingBreakdown(ctx context.Context, mon
 			pct = math.Round((abs/totalExpenses)*1000) / 10
 		}
 		categories = append(categories, domain.AgentCategoryItem{
+			ID:       row.CategoryID,
 			Name:     row.CategoryName,
 			Amount:   abs,
 			Pct:      pct,
@@ -266,9 +270,11 @@ func (r *AgentFinancialRepository) GetCreditCardsSummary(ctx context.Context) (d
 // --- GetMovements ---
 
 type movementRow struct {
+	ID          string    `gorm:"column:id"`
 	Date        time.Time `gorm:"column:date"`
 	Description string    `gorm:"column:description"`
 	Amount      float64   `gorm:"column:amount"`
+	IsPaid      bool      `gorm:"column:is_paid"`
 	Category    string    `gorm:"column:category"`
 	Wallet      string    `gorm:"column:wallet"`
 }
@@ -285,9 +291,11 @@ func (r *AgentFinancialRepository) GetMovements(ctx context.Context, month, year
 	var rows []movementRow
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT
+			m.id,
 			m.date,
 			m.description,
 			m.amount,
+			m.is_paid,
 			COALESCE(c.description, 'Sem categoria') AS category,
 			COALESCE(w.description, '') AS wallet
 		FROM movements m
@@ -325,9 +333,11 @@ func (r *AgentFinancialRepository) GetMovements(ctx context.Context, month, year
 	movements := make([]domain.AgentMovementItem, 0, len(rows))
 	for _, row := range rows {
 		movements = append(movements, domain.AgentMovementItem{
+			ID:          row.ID,
 			Date:        row.Date.Format("2006-01-02"),
 			Description: row.Description,
 			Amount:      row.Amount,
+			IsPaid:      row.IsPaid,
 			Category:    row.Category,
 			Wallet:      row.Wallet,
 		})
@@ -377,14 +387,17 @@ func (r *AgentFinancialRepository) GetRecurringSummary(ctx context.Context) (dom
 	for _, row := range rows {
 		total += row.Amount
 		day := 1
+		startDate := ""
 		if !row.InitialDate.IsZero() {
 			day = row.InitialDate.Day()
+			startDate = row.InitialDate.Format("2006-01-02")
 		}
 		items = append(items, domain.AgentRecurringItem{
 			Description: row.Description,
 			Amount:      row.Amount,
 			Category:    row.CategoryName,
 			Day:         day,
+			StartDate:   startDate,
 		})
 	}
 
@@ -394,9 +407,84 @@ func (r *AgentFinancialRepository) GetRecurringSummary(ctx context.Context) (dom
 	}, nil
 }
 
+// --- GetRecurringCharges ---
+
+type recurringChargeRow struct {
+	Description string    `gorm:"column:description"`
+	Amount      float64   `gorm:"column:amount"`
+	Date        time.Time `gorm:"column:date"`
+	TypePayment string    `gorm:"column:type_payment"`
+}
+
+// GetRecurringCharges detects repeated expenses that are not registered as
+// recurring, over the same window used by the recurring charges endpoint.
+func (r *AgentFinancialRepository) GetRecurringCharges(ctx context.Context) (domain.AgentRecurringCharges, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).
+		AddDate(0, -domain.RecurringChargeLookbackMonths, 0)
+
+	var rows []recurringChargeRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT m.description, m.amount, m.date, m.type_payment
+		FROM movements m
+		WHERE m.user_id = ?
+		  AND m.date >= ?
+		  AND m.amount < 0
+		  AND m.recurrent_id IS NULL
+		  AND m.installment_group_id IS NULL
+		  AND m.pair_id IS NULL
+		  AND m.type_payment NOT IN ('invoice_payment', 'invoice_remainder', 'internal_transfer')
+		ORDER BY m.date ASC
+	`, userID, from).Scan(&rows).Error
+	if err != nil {
+		return domain.AgentRecurringCharges{}, fmt.Errorf("recurring charges query: %w", err)
+	}
+
+	movements := make(domain.MovementList, 0, len(rows))
+	for _, row := range rows {
+		date := row.Date
+		movements = append(movements, domain.Movement{
+			Description: row.Description,
+			Amount:      row.Amount,
+			Date:        &date,
+			TypePayment: domain.TypePayment(row.TypePayment),
+		})
+	}
+
+	var total float64
+	detected := domain.DetectRecurringCharges(movements, now)
+	items := make([]domain.AgentRecurringChargeItem, 0, len(detected))
+	for _, charge := range detected {
+		total += charge.MonthlyAmount
+		item := domain.AgentRecurringChargeItem{
+			Description:       charge.Description,
+			Period:            string(charge.Period),
+			Amount:            charge.Amount,
+			MonthlyAmount:     charge.MonthlyAmount,
+			Occurrences:       charge.Occurrences,
+			LastDate:          charge.LastDate.Format("2006-01-02"),
+			NextExpectedDate:  charge.NextExpectedDate.Format("2006-01-02"),
+			TypePayment:       string(charge.TypePayment),
+			PossiblyForgotten: charge.PossiblyForgotten,
+		}
+		if charge.PriceIncrease != nil {
+			item.PreviousAmount = charge.PriceIncrease.PreviousAmount
+			item.IncreasePct = charge.PriceIncrease.IncreasePct
+		}
+		items = append(items, item)
+	}
+
+	return domain.AgentRecurringCharges{
+		TotalMonthly: math.Round(total*100) / 100,
+		Items:        items,
+	}, nil
+}
+
 // --- GetBudgetStatus ---
 
 type budgetRow struct {
+	CategoryID   string  `gorm:"column:category_id"`
 	CategoryName string  `gorm:"column:category_name"`
 	Estimated    float64 `gorm:"column:estimated"`
 	Actual       float64 `gorm:"column:actual"`
@@ -412,6 +500,7 @@ func (r *AgentFinancialRepository) GetBudgetStatus(ctx context.Context, month, y
 	var rows []budgetRow
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT
+			ec.category_id,
 			ec.category_name,
 			ec.amount AS estimated,
 			COALESCE(SUM(m.amount), 0) AS actual
@@ -425,7 +514,7 @@ func (r *AgentFinancialRepository) GetBudgetStatus(ctx context.Context, month, y
 		WHERE ec.user_id = ?
 		  AND ec.month = ?
 		  AND ec.year = ?
-		GROUP BY ec.category_name, ec.amount
+		GROUP BY ec.category_id, ec.category_name, ec.amount
 		ORDER BY ABS(ec.amount) DESC
 	`, userID, p.start, p.end, userID, int(monthNum), yearNum).Scan(&rows).Error
 	if err != nil {
@@ -440,6 +529,7 @@ func (r *AgentFinancialRepository) GetBudgetStatus(ctx context.Context, month, y
 			variancePct = math.Round((variance/math.Abs(row.Estimated))*1000) / 10
 		}
 		categories = append(categories, domain.AgentBudgetItem{
+			CategoryID:  row.CategoryID,
 			Name:        row.CategoryName,
 			Estimated:   row.Estimated,
 			Actual:      row.Actual,
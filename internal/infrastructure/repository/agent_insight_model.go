@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"time"
+
+	"personal-finance/internal/domain"
+)
+
+// --- Agent Insight Preference DB Model ---
+
+type AgentInsightPreferenceDB struct {
+	UserID       string     `gorm:"primaryKey;column:user_id"`
+	Enabled      bool       `gorm:"column:enabled"`
+	LastDigestAt *time.Time `gorm:"column:last_digest_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at"`
+}
+
+func (AgentInsightPreferenceDB) TableName() string {
+	return "agent_insight_preferences"
+}
+
+func (m AgentInsightPreferenceDB) ToDomain() domain.InsightPreference {
+	return domain.InsightPreference{
+		UserID:       m.UserID,
+		Enabled:      m.Enabled,
+		LastDigestAt: m.LastDigestAt,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+func FromInsightPreferenceDomain(p domain.InsightPreference) AgentInsightPreferenceDB {
+	return AgentInsightPreferenceDB{
+		UserID:       p.UserID,
+		Enabled:      p.Enabled,
+		LastDigestAt: p.LastDigestAt,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsightPreferenceNotFound = errors.New("insight preference not found")
+)
+
+type AgentInsightPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewAgentInsightPreferenceRepository(db *gorm.DB) *AgentInsightPreferenceRepository {
+	return &AgentInsightPreferenceRepository{db: db}
+}
+
+func (r *AgentInsightPreferenceRepository) FindByUserID(ctx context.Context, userID string) (domain.InsightPreference, error) {
+	var dbModel AgentInsightPreferenceDB
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&dbModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.InsightPreference{}, ErrInsightPreferenceNotFound
+		}
+		return domain.InsightPreference{}, fmt.Errorf("error finding insight preference: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return dbModel.ToDomain(), nil
+}
+
+// Upsert saves the opt-in of the user, keeping when the last digest was sent.
+func (r *AgentInsightPreferenceRepository) Upsert(ctx context.Context, pref domain.InsightPreference) (domain.InsightPreference, error) {
+	now := time.Now()
+	dbModel := FromInsightPreferenceDomain(pref)
+	dbModel.CreatedAt = now
+	dbModel.UpdatedAt = now
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+		}).
+		Create(&dbModel).Error
+	if err != nil {
+		return domain.InsightPreference{}, fmt.Errorf("error upserting insight preference: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	return r.FindByUserID(ctx, pref.UserID)
+}
+
+// FindDue returns the opted-in users whose last digest was sent at or before
+// notAfter, or never.
+func (r *AgentInsightPreferenceRepository) FindDue(ctx context.Context, notAfter time.Time) ([]domain.InsightPreference, error) {
+	var dbModels []AgentInsightPreferenceDB
+	err := r.db.WithContext(ctx).
+		Where("enabled = ? AND (last_digest_at IS NULL OR last_digest_at <= ?)", true, notAfter).
+		Order("user_id").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding due insight preferences: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	prefs := make([]domain.InsightPreference, 0, len(dbModels))
+	for _, m := range dbModels {
+		prefs = append(prefs, m.ToDomain())
+	}
+	return prefs, nil
+}
+
+// ClaimDigest marks the digest of the user as sent at sentAt, only if the last one
+// was sent at or before notAfter. It returns false when another run already
+// claimed it, so concurrent jobs never send two digests in the same week.
+func (r *AgentInsightPreferenceRepository) ClaimDigest(ctx context.Context, userID string, sentAt, notAfter time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&AgentInsightPreferenceDB{}).
+		Where("user_id = ? AND enabled = ? AND (last_digest_at IS NULL OR last_digest_at <= ?)", userID, true, notAfter).
+		Updates(map[string]any{"last_digest_at": sentAt, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, fmt.Errorf("error claiming insight digest: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *AgentInsightPreferenceRepository) DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&AgentInsightPreferenceDB{}).Error
+	if err != nil {
+		return fmt.Errorf("error deleting insight preferences: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupInsightPreferenceTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&AgentInsightPreferenceDB{}))
+	return db
+}
+
+func TestAgentInsightPreferenceRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	notAfter := now.Add(-7 * 24 * time.Hour)
+
+	t.Run("should keep the last digest when the opt-in changes", func(t *testing.T) {
+		db := setupInsightPreferenceTestDB(t)
+		repo := NewAgentInsightPreferenceRepository(db)
+
+		_, err := repo.Upsert(ctx, domain.InsightPreference{UserID: "user-1", Enabled: true})
+		require.NoError(t, err)
+		claimed, err := repo.ClaimDigest(ctx, "user-1", now, notAfter)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		pref, err := repo.Upsert(ctx, domain.InsightPreference{UserID: "user-1", Enabled: false})
+
+		require.NoError(t, err)
+		assert.False(t, pref.Enabled)
+		require.NotNil(t, pref.LastDigestAt)
+		assert.True(t, pref.LastDigestAt.Equal(now))
+	})
+
+	t.Run("should list only enabled users without a digest in the week", func(t *testing.T) {
+		db := setupInsightPreferenceTestDB(t)
+		repo := NewAgentInsightPreferenceRepository(db)
+		lastWeek := now.AddDate(0, 0, -8)
+		yesterday := now.AddDate(0, 0, -1)
+		require.NoError(t, db.Create(&[]AgentInsightPreferenceDB{
+			{UserID: "never-sent", Enabled: true},
+			{UserID: "sent-last-week", Enabled: true, LastDigestAt: &lastWeek},
+			{UserID: "sent-yesterday", Enabled: true, LastDigestAt: &yesterday},
+			{UserID: "opted-out", Enabled: false},
+		}).Error)
+
+		prefs, err := repo.FindDue(ctx, notAfter)
+
+		require.NoError(t, err)
+		require.Len(t, prefs, 2)
+		assert.Equal(t, "never-sent", prefs[0].UserID)
+		assert.Equal(t, "sent-last-week", prefs[1].UserID)
+	})
+
+	t.Run("should claim the digest of the week only once", func(t *testing.T) {
+		db := setupInsightPreferenceTestDB(t)
+		repo := NewAgentInsightPreferenceRepository(db)
+		require.NoError(t, db.Create(&AgentInsightPreferenceDB{UserID: "user-1", Enabled: true}).Error)
+
+		first, err := repo.ClaimDigest(ctx, "user-1", now, notAfter)
+		require.NoError(t, err)
+		second, err := repo.ClaimDigest(ctx, "user-1", now.Add(time.Hour), notAfter.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.True(t, first)
+		assert.False(t, second)
+	})
+
+	t.Run("should return not found for a user who never opted in", func(t *testing.T) {
+		repo := NewAgentInsightPreferenceRepository(setupInsightPreferenceTestDB(t))
+
+		_, err := repo.FindByUserID(ctx, "user-1")
+
+		assert.ErrorIs(t, err, ErrInsightPreferenceNotFound)
+	})
+}
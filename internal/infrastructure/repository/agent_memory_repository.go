@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"personal-finance/internal/domain"
@@ -14,15 +17,25 @@ import (
 )
 
 type AgentMemoryRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	embedder domain.EmbeddingProvider
 }
 
 func NewAgentMemoryRepository(db *gorm.DB) *AgentMemoryRepository {
 	return &AgentMemoryRepository{db: db}
 }
 
+// WithEmbeddings returns a repository that embeds the memory content on save and
+// update and ranks memories by similarity. An embedding that fails is left empty
+// and computed again on the next search, which falls back to text matching while
+// the provider is unavailable.
+func (r *AgentMemoryRepository) WithEmbeddings(embedder domain.EmbeddingProvider) *AgentMemoryRepository {
+	return &AgentMemoryRepository{db: r.db, embedder: embedder}
+}
+
 func (r *AgentMemoryRepository) Save(ctx context.Context, memory domain.AgentMemory) (domain.AgentMemory, error) {
 	dbModel := FromAgentMemoryDomain(memory)
+	dbModel.Embedding, dbModel.EmbeddingModel = r.embed(ctx, memory.Content)
 	err := r.db.WithContext(ctx).Create(&dbModel).Error
 	if err != nil {
 		return domain.AgentMemory{}, fmt.Errorf("error saving agent memory: %w: %s", ErrDatabaseError, err.Error())
@@ -47,13 +60,9 @@ func (r *AgentMemoryRepository) FindByID(ctx context.Context, id uuid.UUID) (dom
 }
 
 func (r *AgentMemoryRepository) FindByUserID(ctx context.Context, userID string) ([]domain.AgentMemory, error) {
-	var dbModels []AgentMemoryDB
-	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now()).
-		Order("created_at DESC").
-		Find(&dbModels).Error
+	dbModels, err := r.findActive(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("error finding agent memories: %w: %s", ErrDatabaseError, err.Error())
+		return nil, err
 	}
 
 	memories := make([]domain.AgentMemory, len(dbModels))
@@ -98,18 +107,62 @@ func (r *AgentMemoryRepository) SearchByContent(ctx context.Context, userID stri
 	return memories, nil
 }
 
+// SearchSimilar returns up to limit memories closest in meaning to query. Without
+// an embedding provider, or when it fails, it falls back to SearchByContent.
+func (r *AgentMemoryRepository) SearchSimilar(ctx context.Context, userID string, query string, limit int) ([]domain.AgentMemory, error) {
+	if strings.TrimSpace(query) == "" {
+		return r.FindByUserID(ctx, userID)
+	}
+
+	dbModels, err := r.findActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	scored, ok := r.score(ctx, dbModels, query)
+	if !ok {
+		return r.SearchByContent(ctx, userID, query)
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Similarity > scored[j].Similarity
+	})
+
+	memories := make([]domain.AgentMemory, 0, limit)
+	for _, s := range scored {
+		if len(memories) == limit || s.Similarity <= 0 {
+			break
+		}
+		memories = append(memories, s.Memory)
+	}
+	return memories, nil
+}
+
+// FindRelevant returns all active memories of the user scored against query, in
+// the FindByUserID order. Scores are zero when there is no query or embedding.
+func (r *AgentMemoryRepository) FindRelevant(ctx context.Context, userID string, query string) ([]domain.ScoredMemory, error) {
+	dbModels, err := r.findActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	scored, _ := r.score(ctx, dbModels, query)
+	return scored, nil
+}
+
 func (r *AgentMemoryRepository) Update(ctx context.Context, memory domain.AgentMemory) (domain.AgentMemory, error) {
 	dbModel := FromAgentMemoryDomain(memory)
+	dbModel.Embedding, dbModel.EmbeddingModel = r.embed(ctx, memory.Content)
 	err := r.db.WithContext(ctx).
 		Model(&AgentMemoryDB{}).
 		Where("id = ? AND user_id = ?", memory.ID, memory.UserID).
 		Updates(map[string]interface{}{
-			"content":        dbModel.Content,
-			"metadata":       dbModel.Metadata,
-			"confidence":     dbModel.Confidence,
-			"updated_at":     time.Now(),
-			"last_validated": dbModel.LastValidated,
-			"expires_at":     dbModel.ExpiresAt,
+			"content":         dbModel.Content,
+			"metadata":        dbModel.Metadata,
+			"confidence":      dbModel.Confidence,
+			"updated_at":      time.Now(),
+			"last_validated":  dbModel.LastValidated,
+			"expires_at":      dbModel.ExpiresAt,
+			"embedding":       dbModel.Embedding,
+			"embedding_model": dbModel.EmbeddingModel,
 		}).Error
 	if err != nil {
 		return domain.AgentMemory{}, fmt.Errorf("error updating agent memory: %w: %s", ErrDatabaseError, err.Error())
@@ -189,3 +242,81 @@ func (r *AgentMemoryRepository) DeleteAllByUserID(ctx context.Context, tx *gorm.
 	}
 	return nil
 }
+
+func (r *AgentMemoryRepository) findActive(ctx context.Context, userID string) ([]AgentMemoryDB, error) {
+	var dbModels []AgentMemoryDB
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding agent memories: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return dbModels, nil
+}
+
+func (r *AgentMemoryRepository) embed(ctx context.Context, content string) ([]byte, string) {
+	if r.embedder == nil {
+		return nil, ""
+	}
+	vectors, err := r.embedder.Embed(ctx, []string{content})
+	if err != nil || len(vectors) != 1 {
+		return nil, ""
+	}
+	encoded, err := json.Marshal(vectors[0])
+	if err != nil {
+		return nil, ""
+	}
+	return encoded, r.embedder.Name()
+}
+
+// score embeds the query together with the memories missing a vector of the
+// current model, stores the new vectors and computes the similarities. It
+// reports false when nothing could be compared.
+func (r *AgentMemoryRepository) score(ctx context.Context, dbModels []AgentMemoryDB, query string) ([]domain.ScoredMemory, bool) {
+	scored := make([]domain.ScoredMemory, len(dbModels))
+	for i, dbModel := range dbModels {
+		scored[i] = domain.ScoredMemory{Memory: dbModel.ToDomain()}
+	}
+	if r.embedder == nil || strings.TrimSpace(query) == "" {
+		return scored, false
+	}
+
+	texts := []string{query}
+	var stale []int
+	for i, dbModel := range dbModels {
+		if len(dbModel.Embedding) == 0 || dbModel.EmbeddingModel != r.embedder.Name() {
+			texts = append(texts, dbModel.Content)
+			stale = append(stale, i)
+		}
+	}
+	vectors, err := r.embedder.Embed(ctx, texts)
+	if err != nil || len(vectors) != len(texts) {
+		return scored, false
+	}
+
+	for n, i := range stale {
+		encoded, err := json.Marshal(vectors[n+1])
+		if err != nil {
+			continue
+		}
+		dbModels[i].Embedding, dbModels[i].EmbeddingModel = encoded, r.embedder.Name()
+		// The vector is recomputed on the next search if storing it fails.
+		_ = r.db.WithContext(ctx).
+			Model(&AgentMemoryDB{}).
+			Where("id = ?", dbModels[i].ID).
+			Updates(map[string]interface{}{
+				"embedding":       encoded,
+				"embedding_model": r.embedder.Name(),
+			}).Error
+	}
+
+	for i, dbModel := range dbModels {
+		var vector []float32
+		if err := json.Unmarshal(dbModel.Embedding, &vector); err != nil {
+			continue
+		}
+		scored[i].Similarity = domain.CosineSimilarity(vectors[0], vector)
+	}
+	return scored, true
+}
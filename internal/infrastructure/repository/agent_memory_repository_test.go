@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// topicEmbedder maps each text to one axis per topic, so "viagem" and "férias"
+// land on the same axis like a real model would place paraphrases.
+type topicEmbedder struct {
+	name  string
+	calls int
+	err   error
+}
+
+var embedderTopics = [][]string{{"viagem", "férias", "europa"}, {"carro"}, {"casa", "aluguel"}}
+
+func (e *topicEmbedder) Name() string { return e.name }
+
+func (e *topicEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(embedderTopics))
+		for axis, words := range embedderTopics {
+			for _, word := range words {
+				vectors[i][axis] += float32(strings.Count(strings.ToLower(text), word))
+			}
+		}
+	}
+	return vectors, nil
+}
+
+func setupAgentMemoryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&AgentMemoryDB{}))
+	return db
+}
+
+func saveMemories(t *testing.T, repo *AgentMemoryRepository, contents ...string) []domain.AgentMemory {
+	saved := make([]domain.AgentMemory, 0, len(contents))
+	for _, content := range contents {
+		memory, err := repo.Save(context.Background(), domain.NewAgentMemory("user-1", domain.MemoryTypeGoal, content, domain.MemorySourceExplicit))
+		require.NoError(t, err)
+		saved = append(saved, memory)
+	}
+	return saved
+}
+
+func TestAgentMemoryRepository_SearchSimilar(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1"})
+
+	t.Run("should rank paraphrases first and drop unrelated memories", func(t *testing.T) {
+		embedder := &topicEmbedder{name: "topic-v1"}
+		repo := NewAgentMemoryRepository(setupAgentMemoryTestDB(t)).WithEmbeddings(embedder)
+		saveMemories(t, repo, "Trocar de carro em 2027", "Férias na Europa em julho", "Juntar entrada da casa")
+
+		memories, err := repo.SearchSimilar(ctx, "user-1", "quanto falta para a viagem?", 5)
+
+		require.NoError(t, err)
+		require.Len(t, memories, 1)
+		assert.Equal(t, "Férias na Europa em julho", memories[0].Content)
+		assert.Equal(t, 4, embedder.calls, "one call per save and one for the query")
+	})
+
+	t.Run("should embed memories saved without a vector or with another model", func(t *testing.T) {
+		db := setupAgentMemoryTestDB(t)
+		saveMemories(t, NewAgentMemoryRepository(db), "Férias na Europa em julho")
+		saveMemories(t, NewAgentMemoryRepository(db).WithEmbeddings(&topicEmbedder{name: "old-model"}), "Comprar casa")
+		embedder := &topicEmbedder{name: "topic-v2"}
+		repo := NewAgentMemoryRepository(db).WithEmbeddings(embedder)
+
+		memories, err := repo.SearchSimilar(ctx, "user-1", "viagem", 5)
+		require.NoError(t, err)
+		require.Len(t, memories, 1)
+
+		var stored []AgentMemoryDB
+		require.NoError(t, db.Find(&stored).Error)
+		for _, m := range stored {
+			assert.Equal(t, "topic-v2", m.EmbeddingModel)
+			assert.NotEmpty(t, m.Embedding)
+		}
+
+		_, err = repo.SearchSimilar(ctx, "user-1", "viagem", 5)
+		require.NoError(t, err)
+		assert.Equal(t, 2, embedder.calls, "stored vectors are reused")
+	})
+
+	t.Run("should re-embed the memory on update", func(t *testing.T) {
+		repo := NewAgentMemoryRepository(setupAgentMemoryTestDB(t)).WithEmbeddings(&topicEmbedder{name: "topic-v1"})
+		memory := saveMemories(t, repo, "Trocar de carro em 2027")[0]
+
+		memory.Content = "Férias na Europa em julho"
+		_, err := repo.Update(ctx, memory)
+		require.NoError(t, err)
+
+		memories, err := repo.SearchSimilar(ctx, "user-1", "viagem", 5)
+		require.NoError(t, err)
+		require.Len(t, memories, 1)
+		assert.Equal(t, memory.ID, memories[0].ID)
+	})
+
+	t.Run("should leave scores empty when the provider fails", func(t *testing.T) {
+		embedder := &topicEmbedder{name: "topic-v1"}
+		repo := NewAgentMemoryRepository(setupAgentMemoryTestDB(t)).WithEmbeddings(embedder)
+		saveMemories(t, repo, "Férias na Europa em julho")
+		embedder.err = errors.New("provider unavailable")
+
+		scored, err := repo.FindRelevant(ctx, "user-1", "viagem")
+
+		require.NoError(t, err)
+		require.Len(t, scored, 1)
+		assert.Zero(t, scored[0].Similarity)
+	})
+}
@@ -24,6 +24,9 @@ type AgentMemoryDB struct {
 	UpdatedAt     time.Time  `gorm:"updated_at"`
 	LastValidated time.Time  `gorm:"last_validated"`
 	ExpiresAt     *time.Time `gorm:"expires_at"`
+	// Embedding is the JSON vector of Content, computed by EmbeddingModel.
+	Embedding      []byte `gorm:"type:jsonb"`
+	EmbeddingModel string `gorm:"embedding_model"`
 }
 
 func (AgentMemoryDB) TableName() string {
@@ -80,12 +83,14 @@ func FromAgentMemoryDomain(d domain.AgentMemory) AgentMemoryDB {
 // --- Agent Conversation DB Model ---
 
 type AgentConversationDB struct {
-	ID        *uuid.UUID `gorm:"primaryKey"`
-	UserID    string     `gorm:"user_id"`
-	Title     string     `gorm:"title"`
-	CreatedAt time.Time  `gorm:"created_at"`
-	UpdatedAt time.Time  `gorm:"updated_at"`
-	ExpiresAt time.Time  `gorm:"expires_at"`
+	ID                 *uuid.UUID `gorm:"primaryKey"`
+	UserID             string     `gorm:"user_id"`
+	Title              string     `gorm:"title"`
+	CreatedAt          time.Time  `gorm:"created_at"`
+	UpdatedAt          time.Time  `gorm:"updated_at"`
+	ExpiresAt          time.Time  `gorm:"expires_at"`
+	Summary            string     `gorm:"summary"`
+	SummarizedMessages int        `gorm:"summarized_messages"`
 }
 
 func (AgentConversationDB) TableName() string {
@@ -98,23 +103,27 @@ func (c AgentConversationDB) ToDomain() domain.AgentConversation {
 		id = *c.ID
 	}
 	return domain.AgentConversation{
-		ID:        id,
-		UserID:    c.UserID,
-		Title:     c.Title,
-		CreatedAt: c.CreatedAt,
-		UpdatedAt: c.UpdatedAt,
-		ExpiresAt: c.ExpiresAt,
+		ID:                 id,
+		UserID:             c.UserID,
+		Title:              c.Title,
+		CreatedAt:          c.CreatedAt,
+		UpdatedAt:          c.UpdatedAt,
+		ExpiresAt:          c.ExpiresAt,
+		Summary:            c.Summary,
+		SummarizedMessages: c.SummarizedMessages,
 	}
 }
 
 func FromAgentConversationDomain(d domain.AgentConversation) AgentConversationDB {
 	return AgentConversationDB{
-		ID:        &d.ID,
-		UserID:    d.UserID,
-		Title:     d.Title,
-		CreatedAt: d.CreatedAt,
-		UpdatedAt: d.UpdatedAt,
-		ExpiresAt: d.ExpiresAt,
+		ID:                 &d.ID,
+		UserID:             d.UserID,
+		Title:              d.Title,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
+		ExpiresAt:          d.ExpiresAt,
+		Summary:            d.Summary,
+		SummarizedMessages: d.SummarizedMessages,
 	}
 }
 
@@ -169,6 +178,10 @@ type AgentAuditLogDB struct {
 	ToolsCalled    pq.StringArray `gorm:"type:text[];tools_called"`
 	InputTokens    int            `gorm:"input_tokens"`
 	OutputTokens   int            `gorm:"output_tokens"`
+	ActionID       *uuid.UUID     `gorm:"action_id"`
+	ActionType     string         `gorm:"action_type"`
+	ActionStatus   string         `gorm:"action_status"`
+	ActionError    string         `gorm:"action_error"`
 	Provider       string         `gorm:"provider"`
 	Region         string         `gorm:"region"`
 	CreatedAt      time.Time      `gorm:"created_at"`
@@ -178,6 +191,28 @@ func (AgentAuditLogDB) TableName() string {
 	return "agent_audit_log"
 }
 
+func (a AgentAuditLogDB) ToDomain() domain.AgentAuditRecord {
+	id := uuid.Nil
+	if a.ID != nil {
+		id = *a.ID
+	}
+	return domain.AgentAuditRecord{
+		ID:             id,
+		UserID:         a.UserID,
+		ConversationID: a.ConversationID,
+		ToolsCalled:    a.ToolsCalled,
+		InputTokens:    a.InputTokens,
+		OutputTokens:   a.OutputTokens,
+		ActionID:       a.ActionID,
+		ActionType:     a.ActionType,
+		ActionStatus:   a.ActionStatus,
+		ActionError:    a.ActionError,
+		Provider:       a.Provider,
+		Region:         a.Region,
+		CreatedAt:      a.CreatedAt,
+	}
+}
+
 func FromAgentAuditRecordDomain(d domain.AgentAuditRecord) AgentAuditLogDB {
 	return AgentAuditLogDB{
 		ID:             &d.ID,
@@ -186,8 +221,77 @@ func FromAgentAuditRecordDomain(d domain.AgentAuditRecord) AgentAuditLogDB {
 		ToolsCalled:    d.ToolsCalled,
 		InputTokens:    d.InputTokens,
 		OutputTokens:   d.OutputTokens,
+		ActionID:       d.ActionID,
+		ActionType:     d.ActionType,
+		ActionStatus:   d.ActionStatus,
+		ActionError:    d.ActionError,
 		Provider:       d.Provider,
 		Region:         d.Region,
 		CreatedAt:      d.CreatedAt,
 	}
 }
+
+// --- Agent Action DB Model ---
+
+type AgentActionDB struct {
+	ID             *uuid.UUID `gorm:"primaryKey"`
+	UserID         string     `gorm:"user_id"`
+	ConversationID *uuid.UUID `gorm:"conversation_id"`
+	ActionType     string     `gorm:"action_type"`
+	Summary        string     `gorm:"summary"`
+	Params         []byte     `gorm:"type:jsonb;default:'{}'"`
+	Status         string     `gorm:"status"`
+	ResultID       *uuid.UUID `gorm:"result_id"`
+	Error          string     `gorm:"error"`
+	CreatedAt      time.Time  `gorm:"created_at"`
+	ExpiresAt      time.Time  `gorm:"expires_at"`
+	ResolvedAt     *time.Time `gorm:"resolved_at"`
+	Provider       string     `gorm:"provider"`
+	Region         string     `gorm:"region"`
+}
+
+func (AgentActionDB) TableName() string {
+	return "agent_actions"
+}
+
+func (a AgentActionDB) ToDomain() domain.AgentAction {
+	id := uuid.Nil
+	if a.ID != nil {
+		id = *a.ID
+	}
+	return domain.AgentAction{
+		ID:             id,
+		UserID:         a.UserID,
+		ConversationID: a.ConversationID,
+		Type:           domain.AgentActionType(a.ActionType),
+		Summary:        a.Summary,
+		Params:         json.RawMessage(a.Params),
+		Status:         domain.AgentActionStatus(a.Status),
+		ResultID:       a.ResultID,
+		Error:          a.Error,
+		CreatedAt:      a.CreatedAt,
+		ExpiresAt:      a.ExpiresAt,
+		ResolvedAt:     a.ResolvedAt,
+		Provider:       a.Provider,
+		Region:         a.Region,
+	}
+}
+
+func FromAgentActionDomain(d domain.AgentAction) AgentActionDB {
+	return AgentActionDB{
+		ID:             &d.ID,
+		UserID:         d.UserID,
+		ConversationID: d.ConversationID,
+		ActionType:     string(d.Type),
+		Summary:        d.Summary,
+		Params:         d.Params,
+		Status:         string(d.Status),
+		ResultID:       d.ResultID,
+		Error:          d.Error,
+		CreatedAt:      d.CreatedAt,
+		ExpiresAt:      d.ExpiresAt,
+		ResolvedAt:     d.ResolvedAt,
+		Provider:       d.Provider,
+		Region:         d.Region,
+	}
+}
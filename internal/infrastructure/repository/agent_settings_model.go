@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"time"
+
+	"personal-finance/internal/domain"
+)
+
+// --- Agent Settings DB Model ---
+
+type AgentSettingsDB struct {
+	UserID        string    `gorm:"primaryKey;column:user_id"`
+	MemoryEnabled bool      `gorm:"column:memory_enabled"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at"`
+}
+
+func (AgentSettingsDB) TableName() string {
+	return "agent_settings"
+}
+
+func (m AgentSettingsDB) ToDomain() domain.AgentSettings {
+	return domain.AgentSettings{
+		UserID:        m.UserID,
+		MemoryEnabled: m.MemoryEnabled,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+func FromAgentSettingsDomain(s domain.AgentSettings) AgentSettingsDB {
+	return AgentSettingsDB{
+		UserID:        s.UserID,
+		MemoryEnabled: s.MemoryEnabled,
+		UpdatedAt:     s.UpdatedAt,
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAgentSettingsNotFound = errors.New("agent settings not found")
+)
+
+type AgentSettingsRepository struct {
+	db *gorm.DB
+}
+
+func NewAgentSettingsRepository(db *gorm.DB) *AgentSettingsRepository {
+	return &AgentSettingsRepository{db: db}
+}
+
+func (r *AgentSettingsRepository) FindByUserID(ctx context.Context, userID string) (domain.AgentSettings, error) {
+	var dbModel AgentSettingsDB
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&dbModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.AgentSettings{}, ErrAgentSettingsNotFound
+		}
+		return domain.AgentSettings{}, fmt.Errorf("error finding agent settings: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return dbModel.ToDomain(), nil
+}
+
+// IsMemoryEnabled reports whether the agent may save memories for the user,
+// which is the default when the user never changed the settings.
+func (r *AgentSettingsRepository) IsMemoryEnabled(ctx context.Context, userID string) (bool, error) {
+	settings, err := r.FindByUserID(ctx, userID)
+	if errors.Is(err, ErrAgentSettingsNotFound) {
+		return domain.DefaultAgentSettings(userID).MemoryEnabled, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return settings.MemoryEnabled, nil
+}
+
+func (r *AgentSettingsRepository) Upsert(ctx context.Context, settings domain.AgentSettings) (domain.AgentSettings, error) {
+	now := time.Now()
+	dbModel := FromAgentSettingsDomain(settings)
+	dbModel.CreatedAt = now
+	dbModel.UpdatedAt = now
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"memory_enabled", "updated_at"}),
+		}).
+		Create(&dbModel).Error
+	if err != nil {
+		return domain.AgentSettings{}, fmt.Errorf("error upserting agent settings: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	return r.FindByUserID(ctx, settings.UserID)
+}
+
+func (r *AgentSettingsRepository) DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&AgentSettingsDB{}).Error
+	if err != nil {
+		return fmt.Errorf("error deleting agent settings: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
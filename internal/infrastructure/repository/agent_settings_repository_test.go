@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAgentDataTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&AgentSettingsDB{}, &AgentConversationDB{}, &AgentMessageDB{}))
+	return db
+}
+
+func TestAgentSettingsRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should enable memories for a user without settings", func(t *testing.T) {
+		repo := NewAgentSettingsRepository(setupAgentDataTestDB(t))
+
+		_, err := repo.FindByUserID(ctx, "user-1")
+		assert.ErrorIs(t, err, ErrAgentSettingsNotFound)
+
+		enabled, err := repo.IsMemoryEnabled(ctx, "user-1")
+		require.NoError(t, err)
+		assert.True(t, enabled)
+	})
+
+	t.Run("should update the toggle of an existing user", func(t *testing.T) {
+		repo := NewAgentSettingsRepository(setupAgentDataTestDB(t))
+
+		_, err := repo.Upsert(ctx, domain.AgentSettings{UserID: "user-1", MemoryEnabled: true})
+		require.NoError(t, err)
+		settings, err := repo.Upsert(ctx, domain.AgentSettings{UserID: "user-1", MemoryEnabled: false})
+
+		require.NoError(t, err)
+		assert.False(t, settings.MemoryEnabled)
+		enabled, err := repo.IsMemoryEnabled(ctx, "user-1")
+		require.NoError(t, err)
+		assert.False(t, enabled)
+	})
+}
+
+func TestAgentConversationRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	db := setupAgentDataTestDB(t)
+	repo := NewAgentConversationRepository(db)
+
+	conv, err := repo.Save(ctx, domain.NewAgentConversation("user-1"))
+	require.NoError(t, err)
+	_, err = repo.SaveMessage(ctx, domain.NewAgentMessage(conv.ID, "user", "Quanto gastei?"))
+	require.NoError(t, err)
+	other, err := repo.Save(ctx, domain.NewAgentConversation("user-1"))
+	require.NoError(t, err)
+	_, err = repo.SaveMessage(ctx, domain.NewAgentMessage(other.ID, "user", "E no mês passado?"))
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Delete(ctx, conv.ID))
+
+	_, err = repo.FindByID(ctx, conv.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, conv.ID), domain.ErrNotFound)
+
+	convs, err := repo.FindAllWithMessagesByUserID(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, convs, 1)
+	assert.Equal(t, other.ID, convs[0].ID)
+	require.Len(t, convs[0].Messages, 1)
+	assert.Equal(t, "E no mês passado?", convs[0].Messages[0].Content)
+}
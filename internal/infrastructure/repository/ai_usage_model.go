@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"time"
+
+	"personal-finance/internal/domain"
+)
+
+// --- AI Usage DB Model ---
+
+type AIUsageDB struct {
+	UserID       string    `gorm:"primaryKey;column:user_id"`
+	Year         int       `gorm:"primaryKey;column:year"`
+	Month        int       `gorm:"primaryKey;column:month"`
+	Feature      string    `gorm:"primaryKey;column:feature"`
+	Requests     int64     `gorm:"column:requests"`
+	InputTokens  int64     `gorm:"column:input_tokens"`
+	OutputTokens int64     `gorm:"column:output_tokens"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
+
+func (AIUsageDB) TableName() string {
+	return "ai_usage"
+}
+
+// --- AI Quota Override DB Model ---
+
+type AIQuotaOverrideDB struct {
+	UserID           string     `gorm:"primaryKey;column:user_id"`
+	TokensPerMonth   int        `gorm:"column:tokens_per_month"`
+	RequestsPerMonth int        `gorm:"column:requests_per_month"`
+	Reason           string     `gorm:"column:reason"`
+	GrantedBy        string     `gorm:"column:granted_by"`
+	ExpiresAt        *time.Time `gorm:"column:expires_at"`
+	CreatedAt        time.Time  `gorm:"column:created_at"`
+	UpdatedAt        time.Time  `gorm:"column:updated_at"`
+}
+
+func (AIQuotaOverrideDB) TableName() string {
+	return "ai_quota_overrides"
+}
+
+func (m AIQuotaOverrideDB) ToDomain() domain.AIQuotaOverride {
+	return domain.AIQuotaOverride{
+		UserID:           m.UserID,
+		TokensPerMonth:   m.TokensPerMonth,
+		RequestsPerMonth: m.RequestsPerMonth,
+		Reason:           m.Reason,
+		GrantedBy:        m.GrantedBy,
+		ExpiresAt:        m.ExpiresAt,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
+
+func FromAIQuotaOverrideDomain(o domain.AIQuotaOverride) AIQuotaOverrideDB {
+	return AIQuotaOverrideDB{
+		UserID:           o.UserID,
+		TokensPerMonth:   o.TokensPerMonth,
+		RequestsPerMonth: o.RequestsPerMonth,
+		Reason:           o.Reason,
+		GrantedBy:        o.GrantedBy,
+		ExpiresAt:        o.ExpiresAt,
+		CreatedAt:        o.CreatedAt,
+		UpdatedAt:        o.UpdatedAt,
+	}
+}
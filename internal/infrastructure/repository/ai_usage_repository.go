@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAIQuotaOverrideNotFound = errors.New("AI quota override not found")
+)
+
+// --- AI Usage ---
+
+type AIUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewAIUsageRepository(db *gorm.DB) *AIUsageRepository {
+	return &AIUsageRepository{db: db}
+}
+
+// Increment adds usage to the monthly counter of the feature in a single
+// upsert, so concurrent calls of the same user never lose an increment.
+func (r *AIUsageRepository) Increment(ctx context.Context, userID string, year int, month time.Month, feature domain.AIFeature, usage domain.AIUsage) error {
+	dbModel := AIUsageDB{
+		UserID:       userID,
+		Year:         year,
+		Month:        int(month),
+		Feature:      string(feature),
+		Requests:     usage.Requests,
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+		UpdatedAt:    time.Now(),
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "year"}, {Name: "month"}, {Name: "feature"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"requests":      gorm.Expr("ai_usage.requests + ?", usage.Requests),
+				"input_tokens":  gorm.Expr("ai_usage.input_tokens + ?", usage.InputTokens),
+				"output_tokens": gorm.Expr("ai_usage.output_tokens + ?", usage.OutputTokens),
+				"updated_at":    dbModel.UpdatedAt,
+			}),
+		}).
+		Create(&dbModel).Error
+	if err != nil {
+		return fmt.Errorf("error incrementing ai usage: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+func (r *AIUsageRepository) SumByUserIDAndMonth(ctx context.Context, userID string, year int, month time.Month) (domain.AIUsage, error) {
+	var usage domain.AIUsage
+	err := r.db.WithContext(ctx).
+		Model(&AIUsageDB{}).
+		Select("COALESCE(SUM(requests), 0) AS requests, COALESCE(SUM(input_tokens), 0) AS input_tokens, COALESCE(SUM(output_tokens), 0) AS output_tokens").
+		Where("user_id = ? AND year = ? AND month = ?", userID, year, int(month)).
+		Scan(&usage).Error
+	if err != nil {
+		return domain.AIUsage{}, fmt.Errorf("error summing ai usage: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return usage, nil
+}
+
+func (r *AIUsageRepository) DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&AIUsageDB{}).Error
+	if err != nil {
+		return fmt.Errorf("error deleting ai usage: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+// --- AI Quota Overrides ---
+
+type AIQuotaOverrideRepository struct {
+	db *gorm.DB
+}
+
+func NewAIQuotaOverrideRepository(db *gorm.DB) *AIQuotaOverrideRepository {
+	return &AIQuotaOverrideRepository{db: db}
+}
+
+func (r *AIQuotaOverrideRepository) FindByUserID(ctx context.Context, userID string) (domain.AIQuotaOverride, error) {
+	var dbModel AIQuotaOverrideDB
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&dbModel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.AIQuotaOverride{}, ErrAIQuotaOverrideNotFound
+		}
+		return domain.AIQuotaOverride{}, fmt.Errorf("error finding ai quota override: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return dbModel.ToDomain(), nil
+}
+
+func (r *AIQuotaOverrideRepository) Upsert(ctx context.Context, override domain.AIQuotaOverride) (domain.AIQuotaOverride, error) {
+	now := time.Now()
+	dbModel := FromAIQuotaOverrideDomain(override)
+	dbModel.CreatedAt = now
+	dbModel.UpdatedAt = now
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tokens_per_month", "requests_per_month", "reason", "granted_by", "expires_at", "updated_at"}),
+		}).
+		Create(&dbModel).Error
+	if err != nil {
+		return domain.AIQuotaOverride{}, fmt.Errorf("error upserting ai quota override: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	return r.FindByUserID(ctx, override.UserID)
+}
+
+func (r *AIQuotaOverrideRepository) Delete(ctx context.Context, userID string) error {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&AIQuotaOverrideDB{})
+	if result.Error != nil {
+		return fmt.Errorf("error deleting ai quota override: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return ErrAIQuotaOverrideNotFound
+	}
+	return nil
+}
+
+func (r *AIQuotaOverrideRepository) DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&AIQuotaOverrideDB{}).Error
+	if err != nil {
+		return fmt.Errorf("error deleting ai quota override: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
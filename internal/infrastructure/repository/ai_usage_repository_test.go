@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAIUsageTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&AIUsageDB{}, &AIQuotaOverrideDB{}))
+	return db
+}
+
+func TestAIUsageRepository_Increment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should accumulate usage across features of the month", func(t *testing.T) {
+		repo := NewAIUsageRepository(setupAIUsageTestDB(t))
+
+		require.NoError(t, repo.Increment(ctx, "user-1", 2026, time.March, domain.AIFeatureAgent, domain.AIUsage{Requests: 1, InputTokens: 100, OutputTokens: 10}))
+		require.NoError(t, repo.Increment(ctx, "user-1", 2026, time.March, domain.AIFeatureAgent, domain.AIUsage{Requests: 1, InputTokens: 200, OutputTokens: 20}))
+		require.NoError(t, repo.Increment(ctx, "user-1", 2026, time.March, domain.AIFeatureStatementExtract, domain.AIUsage{Requests: 1, InputTokens: 900}))
+		require.NoError(t, repo.Increment(ctx, "user-1", 2026, time.April, domain.AIFeatureAgent, domain.AIUsage{Requests: 1, InputTokens: 50}))
+		require.NoError(t, repo.Increment(ctx, "user-2", 2026, time.March, domain.AIFeatureAgent, domain.AIUsage{Requests: 1, InputTokens: 70}))
+
+		usage, err := repo.SumByUserIDAndMonth(ctx, "user-1", 2026, time.March)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.AIUsage{Requests: 3, InputTokens: 1200, OutputTokens: 30}, usage)
+	})
+
+	t.Run("should return zero usage for a month without calls", func(t *testing.T) {
+		repo := NewAIUsageRepository(setupAIUsageTestDB(t))
+
+		usage, err := repo.SumByUserIDAndMonth(ctx, "user-1", 2026, time.March)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.AIUsage{}, usage)
+	})
+}
+
+func TestAIQuotaOverrideRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should replace the override of the user on upsert", func(t *testing.T) {
+		repo := NewAIQuotaOverrideRepository(setupAIUsageTestDB(t))
+
+		_, err := repo.Upsert(ctx, domain.AIQuotaOverride{UserID: "user-1", TokensPerMonth: 1000, GrantedBy: "admin-1"})
+		require.NoError(t, err)
+		saved, err := repo.Upsert(ctx, domain.AIQuotaOverride{UserID: "user-1", TokensPerMonth: 5000, RequestsPerMonth: 40, GrantedBy: "admin-2"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 5000, saved.TokensPerMonth)
+		assert.Equal(t, 40, saved.RequestsPerMonth)
+		assert.Equal(t, "admin-2", saved.GrantedBy)
+	})
+
+	t.Run("should report missing overrides as not found", func(t *testing.T) {
+		repo := NewAIQuotaOverrideRepository(setupAIUsageTestDB(t))
+
+		_, err := repo.FindByUserID(ctx, "user-1")
+		assert.ErrorIs(t, err, ErrAIQuotaOverrideNotFound)
+		assert.ErrorIs(t, repo.Delete(ctx, "user-1"), ErrAIQuotaOverrideNotFound)
+	})
+}
+
+func TestAIUsageRepositories_DeleteAllByUserID(t *testing.T) {
+	ctx := context.Background()
+	db := setupAIUsageTestDB(t)
+	usageRepo := NewAIUsageRepository(db)
+	overrideRepo := NewAIQuotaOverrideRepository(db)
+
+	require.NoError(t, usageRepo.Increment(ctx, "user-1", 2026, time.March, domain.AIFeatureAgent, domain.AIUsage{Requests: 1, InputTokens: 100}))
+	require.NoError(t, usageRepo.Increment(ctx, "user-2", 2026, time.March, domain.AIFeatureAgent, domain.AIUsage{Requests: 1, InputTokens: 70}))
+	_, err := overrideRepo.Upsert(ctx, domain.AIQuotaOverride{UserID: "user-1", TokensPerMonth: 1000, GrantedBy: "admin-1"})
+	require.NoError(t, err)
+
+	require.NoError(t, usageRepo.DeleteAllByUserID(ctx, nil, "user-1"))
+	require.NoError(t, overrideRepo.DeleteAllByUserID(ctx, nil, "user-1"))
+
+	usage, err := usageRepo.SumByUserIDAndMonth(ctx, "user-1", 2026, time.March)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AIUsage{}, usage)
+	_, err = overrideRepo.FindByUserID(ctx, "user-1")
+	assert.ErrorIs(t, err, ErrAIQuotaOverrideNotFound)
+
+	usage, err = usageRepo.SumByUserIDAndMonth(ctx, "user-2", 2026, time.March)
+	require.NoError(t, err)
+	assert.Equal(t, int64(70), usage.InputTokens, "other users keep their usage")
+}
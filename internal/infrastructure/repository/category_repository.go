@@ -44,12 +44,13 @@ func (r *CategoryRepository) Add(ctx context.Context, category domain.Category)
 }
 
 func (r *CategoryRepository) FindAll(ctx context.Context) ([]domain.Category, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := userScope(ctx, "user_id", "id", domain.HouseholdResourceCategory, false)
 
 	var dbModels []CategoryDB
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? OR user_id = ?", userID, DefaultCategoryUserID).
-		Preload("SubCategories", r.db.Where("user_id IN(?,?)", userID, DefaultCategoryUserID)).
+		Where(scope, scopeArgs...).
+		Or("user_id = ?", DefaultCategoryUserID).
+		Preload("SubCategories", r.subCategoriesScope(ctx)).
 		Order("description").
 		Find(&dbModels).Error
 	if err != nil {
@@ -70,12 +71,13 @@ func (r *CategoryRepository) FindAll(ctx context.Context) ([]domain.Category, er
 }
 
 func (r *CategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Category, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := userScope(ctx, "user_id", "id", domain.HouseholdResourceCategory, false)
 
 	var dbModel CategoryDB
 	err := r.db.WithContext(ctx).
-		Where("id = ? AND (user_id = ? OR user_id = ?)", id, userID, DefaultCategoryUserID).
-		Preload("SubCategories", r.db.Where("user_id IN(?,?)", userID, DefaultCategoryUserID)).
+		Where("id = ?", id).
+		Where(fmt.Sprintf("(%s OR user_id = ?)", scope), append(scopeArgs, DefaultCategoryUserID)...).
+		Preload("SubCategories", r.subCategoriesScope(ctx)).
 		First(&dbModel).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -92,6 +94,13 @@ func (r *CategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (domain
 	return cat, nil
 }
 
+// subCategoriesScope carrega as subcategorias do usuário, as padrão e as das
+// categorias compartilhadas com os households dele.
+func (r *CategoryRepository) subCategoriesScope(ctx context.Context) *gorm.DB {
+	scope, scopeArgs := userScope(ctx, "user_id", "category_id", domain.HouseholdResourceCategory, false)
+	return r.db.Where(scope, scopeArgs...).Or("user_id = ?", DefaultCategoryUserID)
+}
+
 func (r *CategoryRepository) Update(ctx context.Context, id uuid.UUID, category domain.Category) (domain.Category, error) {
 	userID := ctx.Value(authentication.UserID).(string)
 
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const couponCodeBatchSize = 500
+
+type CouponCampaignRepository struct {
+	db *gorm.DB
+}
+
+func NewCouponCampaignRepository(db *gorm.DB) *CouponCampaignRepository {
+	return &CouponCampaignRepository{db: db}
+}
+
+func (r *CouponCampaignRepository) Create(ctx context.Context, campaign domain.CouponCampaign) error {
+	if campaign.CreatedAt.IsZero() {
+		campaign.CreatedAt = time.Now()
+	}
+
+	row := FromCouponCampaignDomain(campaign)
+	err := r.db.WithContext(ctx).Create(&row).Error
+	if err != nil {
+		return fmt.Errorf("error creating coupon campaign: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+func (r *CouponCampaignRepository) FindByID(ctx context.Context, id string) (domain.CouponCampaign, error) {
+	var row CouponCampaignDB
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.CouponCampaign{}, domain.ErrCouponCampaignNotFound
+		}
+		return domain.CouponCampaign{}, fmt.Errorf("error finding coupon campaign: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+func (r *CouponCampaignRepository) List(ctx context.Context) ([]domain.CouponCampaign, error) {
+	var rows []CouponCampaignDB
+	err := r.db.WithContext(ctx).Order("created_at desc").Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing coupon campaigns: %w: %s", ErrDatabaseError, err.Error())
+	}
+	out := make([]domain.CouponCampaign, len(rows))
+	for i, row := range rows {
+		out[i] = row.ToDomain()
+	}
+	return out, nil
+}
+
+// ExistsForCoupon tells whether the coupon backs a campaign. The shared code
+// of such a coupon is not redeemable, only the campaign codes are.
+func (r *CouponCampaignRepository) ExistsForCoupon(ctx context.Context, couponID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&CouponCampaignDB{}).
+		Where("coupon_id = ?", couponID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("error checking coupon campaigns: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return count > 0, nil
+}
+
+// CreateCodes inserts the codes as available, skipping the ones that already
+// exist. Returns how many were inserted so the caller can generate more to
+// replace the collisions.
+func (r *CouponCampaignRepository) CreateCodes(ctx context.Context, campaignID string, codes []string) (int, error) {
+	if len(codes) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	rows := make([]CouponCampaignCodeDB, len(codes))
+	for i, code := range codes {
+		rows[i] = CouponCampaignCodeDB{
+			Code:       code,
+			CampaignID: campaignID,
+			Status:     string(domain.CouponCodeAvailable),
+			CreatedAt:  now,
+		}
+	}
+
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&rows, couponCodeBatchSize)
+	if res.Error != nil {
+		return 0, fmt.Errorf("error creating coupon codes: %w: %s", ErrDatabaseError, res.Error.Error())
+	}
+	return int(res.RowsAffected), nil
+}
+
+// FindCode returns the campaign code together with its campaign.
+func (r *CouponCampaignRepository) FindCode(ctx context.Context, code string) (domain.CouponCampaignCode, domain.CouponCampaign, error) {
+	var row CouponCampaignCodeDB
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.CouponCampaignCode{}, domain.CouponCampaign{}, domain.ErrCouponCodeNotFound
+		}
+		return domain.CouponCampaignCode{}, domain.CouponCampaign{}, fmt.Errorf("error finding coupon code: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	campaign, err := r.FindByID(ctx, row.CampaignID)
+	if err != nil {
+		return domain.CouponCampaignCode{}, domain.CouponCampaign{}, err
+	}
+	return row.ToDomain(), campaign, nil
+}
+
+// ListCodes lists the codes of a campaign. Available codes with a pending
+// redemption are reported as pending.
+func (r *CouponCampaignRepository) ListCodes(ctx context.Context, campaignID string) ([]domain.CouponCampaignCode, error) {
+	var rows []CouponCampaignCodeDB
+	err := r.db.WithContext(ctx).
+		Where("campaign_id = ?", campaignID).
+		Order("code asc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing coupon codes: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	var pending []string
+	err = r.db.WithContext(ctx).
+		Model(&CouponRedemptionDB{}).
+		Distinct("code").
+		Where("status = ? AND code IN (?)", string(domain.CouponRedemptionPending),
+			r.db.Model(&CouponCampaignCodeDB{}).Select("code").Where("campaign_id = ?", campaignID)).
+		Pluck("code", &pending).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing pending coupon codes: %w: %s", ErrDatabaseError, err.Error())
+	}
+	pendingCodes := make(map[string]bool, len(pending))
+	for _, code := range pending {
+		pendingCodes[code] = true
+	}
+
+	out := make([]domain.CouponCampaignCode, len(rows))
+	for i, row := range rows {
+		out[i] = row.ToDomain()
+		if out[i].Status == domain.CouponCodeAvailable && pendingCodes[row.Code] {
+			out[i].Status = domain.CouponCodePending
+		}
+	}
+	return out, nil
+}
+
+// ReserveCode holds an available code for the user until the given time.
+// Returns ErrCouponCodeAlreadyUsed when the code was redeemed or another user
+// holds it.
+func (r *CouponCampaignRepository) ReserveCode(ctx context.Context, code, userID string, until time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&CouponCampaignCodeDB{}).
+		Where("code = ? AND status = ?", code, string(domain.CouponCodeAvailable)).
+		Where("(reserved_by IS NULL OR reserved_by = ? OR reserved_until < ?)", userID, time.Now()).
+		Updates(map[string]interface{}{
+			"reserved_by":    userID,
+			"reserved_until": until,
+		})
+	if res.Error != nil {
+		return fmt.Errorf("error reserving coupon code: %w: %s", ErrDatabaseError, res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrCouponCodeAlreadyUsed
+	}
+	return nil
+}
+
+// MarkCodeRedeemed consumes an available code for the redemption. Returns
+// ErrCouponCodeAlreadyUsed when the code was redeemed by someone else first.
+// Callers should run this inside the transaction that marks the redemption
+// active.
+func (r *CouponCampaignRepository) MarkCodeRedeemed(ctx context.Context, tx *gorm.DB, code, userID string, redemptionID uuid.UUID) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	res := db.WithContext(ctx).
+		Model(&CouponCampaignCodeDB{}).
+		Where("code = ? AND status = ?", code, string(domain.CouponCodeAvailable)).
+		Updates(map[string]interface{}{
+			"status":        string(domain.CouponCodeRedeemed),
+			"redeemed_by":   userID,
+			"redemption_id": redemptionID,
+			"redeemed_at":   time.Now(),
+		})
+	if res.Error != nil {
+		return fmt.Errorf("error marking coupon code redeemed: %w: %s", ErrDatabaseError, res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrCouponCodeAlreadyUsed
+	}
+	return nil
+}
+
+func (r *CouponCampaignRepository) SetStripePromotionCode(ctx context.Context, code, promotionCodeID string) error {
+	res := r.db.WithContext(ctx).
+		Model(&CouponCampaignCodeDB{}).
+		Where("code = ?", code).
+		Update("stripe_promotion_code_id", promotionCodeID)
+	if res.Error != nil {
+		return fmt.Errorf("error setting stripe promotion code: %w: %s", ErrDatabaseError, res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrCouponCodeNotFound
+	}
+	return nil
+}
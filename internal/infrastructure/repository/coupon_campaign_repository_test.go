@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCouponCampaignRepository_CodesLifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupCouponTestDB(t)
+	couponRepo := NewCouponRepository(db)
+	redRepo := NewCouponRedemptionRepository(db)
+	repo := NewCouponCampaignRepository(db)
+
+	assert.NoError(t, couponRepo.Create(ctx, sampleCoupon("c1", "INFLUENCER", nil)))
+	assert.NoError(t, repo.Create(ctx, domain.CouponCampaign{ID: "camp-1", Name: "Influencer", CouponID: "c1", StripeCouponID: "stripe-c1"}))
+
+	inCampaign, err := repo.ExistsForCoupon(ctx, "c1")
+	assert.NoError(t, err)
+	assert.True(t, inCampaign)
+
+	created, err := repo.CreateCodes(ctx, "camp-1", []string{"AAA", "BBB", "CCC"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, created)
+
+	created, err = repo.CreateCodes(ctx, "camp-1", []string{"CCC", "DDD"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created, "existing codes must be skipped")
+
+	code, campaign, err := repo.FindCode(ctx, "BBB")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CouponCodeAvailable, code.Status)
+	assert.Equal(t, "stripe-c1", campaign.StripeCouponID)
+
+	_, _, err = repo.FindCode(ctx, "ZZZ")
+	assert.ErrorIs(t, err, domain.ErrCouponCodeNotFound)
+
+	_, err = redRepo.Create(ctx, domain.CouponRedemption{UserID: "user-1", CouponID: "c1", PlanID: "plus_monthly", Code: "BBB"})
+	assert.NoError(t, err)
+
+	redemptionID := uuid.New()
+	assert.NoError(t, repo.MarkCodeRedeemed(ctx, nil, "CCC", "user-2", redemptionID))
+	assert.ErrorIs(t, repo.MarkCodeRedeemed(ctx, nil, "CCC", "user-3", uuid.New()), domain.ErrCouponCodeAlreadyUsed)
+
+	assert.NoError(t, repo.SetStripePromotionCode(ctx, "AAA", "promo_1"))
+	assert.ErrorIs(t, repo.SetStripePromotionCode(ctx, "ZZZ", "promo_2"), domain.ErrCouponCodeNotFound)
+
+	codes, err := repo.ListCodes(ctx, "camp-1")
+	assert.NoError(t, err)
+	assert.Len(t, codes, 4)
+
+	byCode := make(map[string]domain.CouponCampaignCode)
+	for _, c := range codes {
+		byCode[c.Code] = c
+	}
+	assert.Equal(t, domain.CouponCodeAvailable, byCode["AAA"].Status)
+	assert.Equal(t, "promo_1", byCode["AAA"].StripePromotionCodeID)
+	assert.Equal(t, domain.CouponCodePending, byCode["BBB"].Status)
+	assert.Equal(t, domain.CouponCodeRedeemed, byCode["CCC"].Status)
+	assert.Equal(t, "user-2", byCode["CCC"].RedeemedBy)
+	assert.Equal(t, redemptionID, *byCode["CCC"].RedemptionID)
+	assert.NotNil(t, byCode["CCC"].RedeemedAt)
+}
+
+func TestCouponCampaignRepository_ReserveCode(t *testing.T) {
+	ctx := context.Background()
+	db := setupCouponTestDB(t)
+	couponRepo := NewCouponRepository(db)
+	repo := NewCouponCampaignRepository(db)
+
+	assert.NoError(t, couponRepo.Create(ctx, sampleCoupon("c1", "INFLUENCER", nil)))
+	assert.NoError(t, repo.Create(ctx, domain.CouponCampaign{ID: "camp-1", Name: "Influencer", CouponID: "c1"}))
+	_, err := repo.CreateCodes(ctx, "camp-1", []string{"AAA"})
+	assert.NoError(t, err)
+
+	until := time.Now().Add(time.Hour)
+	assert.NoError(t, repo.ReserveCode(ctx, "AAA", "user-1", until))
+	assert.NoError(t, repo.ReserveCode(ctx, "AAA", "user-1", until), "the holder may reserve again")
+	assert.ErrorIs(t, repo.ReserveCode(ctx, "AAA", "user-2", until), domain.ErrCouponCodeAlreadyUsed)
+
+	code, _, err := repo.FindCode(ctx, "AAA")
+	assert.NoError(t, err)
+	assert.True(t, code.ReservedByOther("user-2", time.Now()))
+	assert.False(t, code.ReservedByOther("user-1", time.Now()))
+
+	assert.NoError(t, repo.ReserveCode(ctx, "AAA", "user-1", time.Now().Add(-time.Minute)))
+	assert.NoError(t, repo.ReserveCode(ctx, "AAA", "user-2", until), "an expired hold is taken over")
+
+	assert.NoError(t, repo.MarkCodeRedeemed(ctx, nil, "AAA", "user-2", uuid.New()))
+	assert.ErrorIs(t, repo.ReserveCode(ctx, "AAA", "user-2", until), domain.ErrCouponCodeAlreadyUsed,
+		"a redeemed code is not reserved")
+}
+
+func TestCouponCampaignRepository_FindByIDNotFound(t *testing.T) {
+	repo := NewCouponCampaignRepository(setupCouponTestDB(t))
+
+	_, err := repo.FindByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, domain.ErrCouponCampaignNotFound)
+}
@@ -102,11 +102,14 @@ func (r *CouponRedemptionRepository) MarkActive(ctx context.Context, tx *gorm.DB
 	return nil
 }
 
-func (r *CouponRedemptionRepository) RefreshPending(ctx context.Context, userID, couponID string, originalPrice, lockedPrice float64) (domain.CouponRedemption, error) {
+// RefreshPending updates the prices of the pending redemption and the campaign
+// code it will consume, since a retry may use another code of the campaign.
+func (r *CouponRedemptionRepository) RefreshPending(ctx context.Context, userID, couponID, code string, originalPrice, lockedPrice float64) (domain.CouponRedemption, error) {
 	res := r.db.WithContext(ctx).
 		Model(&CouponRedemptionDB{}).
 		Where("user_id = ? AND coupon_id = ? AND status = ?", userID, couponID, string(domain.CouponRedemptionPending)).
 		Updates(map[string]interface{}{
+			"code":           code,
 			"original_price": originalPrice,
 			"locked_price":   lockedPrice,
 			"redeemed_at":    time.Now(),
@@ -131,3 +134,22 @@ func (r *CouponRedemptionRepository) MarkCancelledBySubscription(ctx context.Con
 	}
 	return nil
 }
+
+// ListWithSubscription lists the redemptions confirmed by a subscription,
+// including the cancelled ones.
+func (r *CouponRedemptionRepository) ListWithSubscription(ctx context.Context) ([]domain.CouponRedemption, error) {
+	var rows []CouponRedemptionDB
+	err := r.db.WithContext(ctx).
+		Where("subscription_id IS NOT NULL").
+		Order("redeemed_at asc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing redemptions: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	out := make([]domain.CouponRedemption, len(rows))
+	for i, row := range rows {
+		out[i] = row.ToDomain()
+	}
+	return out, nil
+}
@@ -80,3 +80,21 @@ func TestCouponRedemptionRepository_MarkCancelledBySubscription(t *testing.T) {
 	assert.Equal(t, domain.CouponRedemptionCancelled, found.Status)
 	assert.NotNil(t, found.CancelledAt)
 }
+
+func TestCouponRedemptionRepository_ListWithSubscription(t *testing.T) {
+	ctx := context.Background()
+	redRepo := NewCouponRedemptionRepository(setupCouponTestDB(t))
+
+	confirmed, err := redRepo.Create(ctx, domain.CouponRedemption{UserID: "user-1", CouponID: "c1", PlanID: "plus_monthly", OriginalPrice: 9.90, LockedPrice: 6.93})
+	assert.NoError(t, err)
+	_, err = redRepo.Create(ctx, domain.CouponRedemption{UserID: "user-2", CouponID: "c1", PlanID: "plus_monthly", OriginalPrice: 9.90, LockedPrice: 6.93})
+	assert.NoError(t, err)
+	assert.NoError(t, redRepo.MarkActive(ctx, nil, confirmed.ID, uuid.New()))
+
+	redemptions, err := redRepo.ListWithSubscription(ctx)
+
+	assert.NoError(t, err)
+	if assert.Len(t, redemptions, 1) {
+		assert.Equal(t, confirmed.ID, redemptions[0].ID)
+	}
+}
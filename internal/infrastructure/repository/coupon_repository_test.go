@@ -16,7 +16,7 @@ func setupCouponTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
-	assert.NoError(t, db.AutoMigrate(&CouponDB{}, &CouponRedemptionDB{}))
+	assert.NoError(t, db.AutoMigrate(&CouponDB{}, &CouponRedemptionDB{}, &CouponCampaignDB{}, &CouponCampaignCodeDB{}))
 	return db
 }
 
@@ -63,7 +63,7 @@ func (r *CreditCardRepository) FindByID(ctx context.Context, id uuid.UUID) (doma
 	var dbModel CreditCardDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "id", domain.HouseholdResourceCreditCard)
 	query = r.appendPreloads(query)
 
 	if err := query.First(&dbModel, fmt.Sprintf("%s.id = ?", tableName), id).Error; err != nil {
@@ -80,7 +80,7 @@ func (r *CreditCardRepository) FindNameByID(ctx context.Context, id uuid.UUID) (
 	var name string
 	tableName := "credit_cards"
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "id", domain.HouseholdResourceCreditCard)
 
 	if err := query.Select("name").Where(fmt.Sprintf("%s.id = ?", tableName), id).Scan(&name).Error; err != nil {
 		return "", fmt.Errorf("error finding credit card: %w: %s", ErrDatabaseError, err.Error())
@@ -93,11 +93,14 @@ func (r *CreditCardRepository) FindNameByID(ctx context.Context, id uuid.UUID) (
 	return name, nil
 }
 
+// FindAll também lista os cartões compartilhados com os households do usuário,
+// assim como FindByID e FindNameByID. Editores lançam compras no cartão
+// compartilhado (UpdateLimitDelta), mas só o dono altera ou remove o cartão.
 func (r *CreditCardRepository) FindAll(ctx context.Context) ([]domain.CreditCard, error) {
 	var dbModel CreditCardDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "id", domain.HouseholdResourceCreditCard)
 	query = r.appendPreloads(query)
 
 	var dbCreditCards []CreditCardDB
@@ -165,7 +168,8 @@ func (r *CreditCardRepository) UpdateLimitDelta(ctx context.Context, tx *gorm.DB
 	var dbModel CreditCardDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, tx, tableName)
+	scope, scopeArgs := userScope(ctx, tableName+".user_id", tableName+".id", domain.HouseholdResourceCreditCard, true)
+	query := tx.WithContext(ctx).Table(tableName).Where(scope, scopeArgs...)
 	if err := query.First(&dbModel, fmt.Sprintf("%s.id = ?", tableName), id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return domain.CreditCard{}, fmt.Errorf("error finding credit card: %w: %s", ErrCreditCardNotFound, err.Error())
@@ -190,7 +194,7 @@ func (r *CreditCardRepository) UpdateLimitDelta(ctx context.Context, tx *gorm.DB
 	}
 
 	// Reload to get updated values
-	query = BuildBaseQuery(ctx, r.db, tableName)
+	query = BuildSharedQuery(ctx, r.db, tableName, "id", domain.HouseholdResourceCreditCard)
 	query = r.appendPreloads(query)
 	if err := query.First(&dbModel, fmt.Sprintf("%s.id = ?", tableName), id).Error; err != nil {
 		return domain.CreditCard{}, fmt.Errorf("error reloading credit card: %w: %s", ErrDatabaseError, err.Error())
@@ -53,12 +53,12 @@ func (EstimateSubCategoryDB) TableName() string {
 }
 
 func (r *EstimateRepository) FindAllCategoriesByUserID(ctx context.Context) ([]domain.EstimateCategories, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := estimateCategoriesScope(ctx, false)
 
 	var dbModels []EstimateCategoryDB
 	err := r.db.WithContext(ctx).
 		Table("estimate_categories").
-		Where("estimate_categories.user_id = ?", userID).
+		Where(scope, scopeArgs...).
 		Joins("LEFT JOIN categories c ON estimate_categories.category_id = c.id").
 		Select("estimate_categories.*, c.description as category_name, c.is_income as is_category_income").
 		Order("year DESC, month DESC").
@@ -76,12 +76,12 @@ func (r *EstimateRepository) FindAllCategoriesByUserID(ctx context.Context) ([]d
 }
 
 func (r *EstimateRepository) FindAllSubCategoriesByUserID(ctx context.Context) ([]domain.EstimateSubCategories, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := estimateSubCategoriesScope(ctx, false)
 
 	var dbModels []EstimateSubCategoryDB
 	err := r.db.WithContext(ctx).
 		Table("estimate_sub_categories").
-		Where("estimate_sub_categories.user_id = ?", userID).
+		Where(scope, scopeArgs...).
 		Joins("LEFT JOIN sub_categories sc ON estimate_sub_categories.sub_category_id = sc.id").
 		Select("estimate_sub_categories.*, sc.description as sub_category_name").
 		Order("year DESC, month DESC").
@@ -98,6 +98,17 @@ func (r *EstimateRepository) FindAllSubCategoriesByUserID(ctx context.Context) (
 	return result, nil
 }
 
+// estimateCategoriesScope inclui as estimativas compartilhadas com os
+// households do usuário; write considera só os households em que ele edita.
+func estimateCategoriesScope(ctx context.Context, write bool) (string, []interface{}) {
+	return userScope(ctx, "estimate_categories.user_id", "estimate_categories.id", domain.HouseholdResourceEstimate, write)
+}
+
+// estimateSubCategoriesScope segue o compartilhamento da estimativa da categoria.
+func estimateSubCategoriesScope(ctx context.Context, write bool) (string, []interface{}) {
+	return userScope(ctx, "estimate_sub_categories.user_id", "estimate_sub_categories.estimate_category_id", domain.HouseholdResourceEstimate, write)
+}
+
 func toEstimateCategoryDomain(m EstimateCategoryDB) domain.EstimateCategories {
 	var catID, id *uuid.UUID
 	if m.CategoryID != nil {
@@ -147,12 +158,12 @@ func toEstimateSubCategoryDomain(m EstimateSubCategoryDB) domain.EstimateSubCate
 }
 
 func (r *EstimateRepository) FindCategoriesByMonth(ctx context.Context, month int, year int) ([]domain.EstimateCategories, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := estimateCategoriesScope(ctx, false)
 
 	var dbModels []EstimateCategoryDB
 	err := r.db.WithContext(ctx).
 		Table("estimate_categories").
-		Where("estimate_categories.user_id = ?", userID).
+		Where(scope, scopeArgs...).
 		Where("estimate_categories.month = ? AND estimate_categories.year = ?", month, year).
 		Joins("LEFT JOIN categories c ON estimate_categories.category_id = c.id").
 		Select("estimate_categories.*, c.description as category_name, c.is_income as is_category_income").
@@ -170,12 +181,12 @@ func (r *EstimateRepository) FindCategoriesByMonth(ctx context.Context, month in
 }
 
 func (r *EstimateRepository) FindSubcategoriesByMonth(ctx context.Context, month int, year int) ([]domain.EstimateSubCategories, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := estimateSubCategoriesScope(ctx, false)
 
 	var dbModels []EstimateSubCategoryDB
 	err := r.db.WithContext(ctx).
 		Table("estimate_sub_categories").
-		Where("estimate_sub_categories.user_id = ?", userID).
+		Where(scope, scopeArgs...).
 		Where("estimate_sub_categories.month = ? AND estimate_sub_categories.year = ?", month, year).
 		Joins("LEFT JOIN sub_categories sc ON estimate_sub_categories.sub_category_id = sc.id").
 		Select("estimate_sub_categories.*, sc.description as sub_category_name").
@@ -258,12 +269,13 @@ func (r *EstimateRepository) AddEstimateSubCategory(ctx context.Context, subEsti
 }
 
 func (r *EstimateRepository) UpdateEstimateCategoryAmount(ctx context.Context, id *uuid.UUID, amount float64) (domain.EstimateCategories, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := estimateCategoriesScope(ctx, true)
 	idStr := id.String()
 
 	result := r.db.WithContext(ctx).
 		Model(&EstimateCategoryDB{}).
-		Where("id = ? AND user_id = ?", idStr, userID).
+		Where("estimate_categories.id = ?", idStr).
+		Where(scope, scopeArgs...).
 		Update("amount", amount)
 	if err := result.Error; err != nil {
 		return domain.EstimateCategories{}, fmt.Errorf("error updating estimate category amount: %w", err)
@@ -284,12 +296,13 @@ func (r *EstimateRepository) UpdateEstimateCategoryAmount(ctx context.Context, i
 }
 
 func (r *EstimateRepository) UpdateEstimateSubCategoryAmount(ctx context.Context, id *uuid.UUID, amount float64) (domain.EstimateSubCategories, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := estimateSubCategoriesScope(ctx, true)
 	idStr := id.String()
 
 	result := r.db.WithContext(ctx).
 		Model(&EstimateSubCategoryDB{}).
-		Where("id = ? AND user_id = ?", idStr, userID).
+		Where("estimate_sub_categories.id = ?", idStr).
+		Where(scope, scopeArgs...).
 		Update("amount", amount)
 	if err := result.Error; err != nil {
 		return domain.EstimateSubCategories{}, fmt.Errorf("error updating estimate sub category amount: %w", err)
@@ -0,0 +1,382 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// householdResourceTables maps each shareable resource to the table that
+// holds it and its owner.
+var householdResourceTables = map[domain.HouseholdResource]string{
+	domain.HouseholdResourceWallet:     WalletDB{}.TableName(),
+	domain.HouseholdResourceCreditCard: CreditCardDB{}.TableName(),
+	domain.HouseholdResourceCategory:   CategoryDB{}.TableName(),
+	domain.HouseholdResourceEstimate:   EstimateCategoryDB{}.TableName(),
+}
+
+type HouseholdRepository struct {
+	db *gorm.DB
+}
+
+func NewHouseholdRepository(db *gorm.DB) *HouseholdRepository {
+	return &HouseholdRepository{db: db}
+}
+
+// Create stores the household with its owner as the first member.
+func (r *HouseholdRepository) Create(ctx context.Context, household domain.Household) (domain.Household, error) {
+	if household.ID == uuid.Nil {
+		household.ID = uuid.New()
+	}
+	if household.CreatedAt.IsZero() {
+		household.CreatedAt = time.Now()
+	}
+
+	row := HouseholdDB{
+		ID:        household.ID,
+		Name:      household.Name,
+		OwnerID:   household.OwnerID,
+		CreatedAt: household.CreatedAt,
+	}
+	owner := HouseholdMemberDB{
+		HouseholdID: household.ID,
+		UserID:      household.OwnerID,
+		Role:        string(domain.HouseholdRoleOwner),
+		JoinedAt:    household.CreatedAt,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&row).Error; err != nil {
+			return err
+		}
+		return tx.Create(&owner).Error
+	})
+	if err != nil {
+		return domain.Household{}, fmt.Errorf("error creating household: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	created := row.ToDomain()
+	created.Members = []domain.HouseholdMember{owner.ToDomain()}
+	return created, nil
+}
+
+// FindByID returns the household with its members and shares.
+func (r *HouseholdRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Household, error) {
+	var row HouseholdDB
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Household{}, domain.ErrHouseholdNotFound
+		}
+		return domain.Household{}, fmt.Errorf("error finding household: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	households, err := r.withMembersAndShares(ctx, []HouseholdDB{row})
+	if err != nil {
+		return domain.Household{}, err
+	}
+	return households[0], nil
+}
+
+// ListByMember returns the households the user is a member of, oldest first.
+func (r *HouseholdRepository) ListByMember(ctx context.Context, userID string) ([]domain.Household, error) {
+	var rows []HouseholdDB
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&HouseholdMemberDB{}).Select("household_id").Where("user_id = ?", userID)).
+		Order("created_at").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing households: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return r.withMembersAndShares(ctx, rows)
+}
+
+func (r *HouseholdRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&HouseholdShareDB{}, &HouseholdInvitationDB{}, &HouseholdMemberDB{}} {
+			if err := tx.Where("household_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("id = ?", id).Delete(&HouseholdDB{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting household: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+// Memberships returns the role of the user in each of its households.
+func (r *HouseholdRepository) Memberships(ctx context.Context, userID string) ([]domain.HouseholdMembership, error) {
+	var rows []HouseholdMemberDB
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error finding household memberships: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	memberships := make([]domain.HouseholdMembership, len(rows))
+	for i, row := range rows {
+		memberships[i] = domain.HouseholdMembership{
+			HouseholdID: row.HouseholdID,
+			Role:        domain.HouseholdRole(row.Role),
+		}
+	}
+	return memberships, nil
+}
+
+func (r *HouseholdRepository) FindMember(ctx context.Context, householdID uuid.UUID, userID string) (domain.HouseholdMember, error) {
+	var row HouseholdMemberDB
+	err := r.db.WithContext(ctx).
+		Where("household_id = ? AND user_id = ?", householdID, userID).
+		First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.HouseholdMember{}, domain.ErrHouseholdMemberNotFound
+		}
+		return domain.HouseholdMember{}, fmt.Errorf("error finding household member: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+func (r *HouseholdRepository) UpdateMemberRole(ctx context.Context, householdID uuid.UUID, userID string, role domain.HouseholdRole) error {
+	result := r.db.WithContext(ctx).
+		Model(&HouseholdMemberDB{}).
+		Where("household_id = ? AND user_id = ?", householdID, userID).
+		Update("role", string(role))
+	if result.Error != nil {
+		return fmt.Errorf("error updating household member: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrHouseholdMemberNotFound
+	}
+	return nil
+}
+
+// RemoveMember removes the member and the resources it shared with the
+// household.
+func (r *HouseholdRepository) RemoveMember(ctx context.Context, householdID uuid.UUID, userID string) error {
+	var removed int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("household_id = ? AND shared_by = ?", householdID, userID).
+			Delete(&HouseholdShareDB{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("household_id = ? AND user_id = ?", householdID, userID).
+			Delete(&HouseholdMemberDB{})
+		removed = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("error removing household member: %w: %s", ErrDatabaseError, err.Error())
+	}
+	if removed == 0 {
+		return domain.ErrHouseholdMemberNotFound
+	}
+	return nil
+}
+
+func (r *HouseholdRepository) CreateInvitation(ctx context.Context, invitation domain.HouseholdInvitation) (domain.HouseholdInvitation, error) {
+	if invitation.ID == uuid.Nil {
+		invitation.ID = uuid.New()
+	}
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
+
+	row := HouseholdInvitationDB{
+		ID:          invitation.ID,
+		HouseholdID: invitation.HouseholdID,
+		Email:       invitation.Email,
+		Role:        string(invitation.Role),
+		TokenHash:   invitation.TokenHash,
+		InvitedBy:   invitation.InvitedBy,
+		ExpiresAt:   invitation.ExpiresAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return domain.HouseholdInvitation{}, fmt.Errorf("error creating household invitation: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+func (r *HouseholdRepository) ListInvitations(ctx context.Context, householdID uuid.UUID) ([]domain.HouseholdInvitation, error) {
+	var rows []HouseholdInvitationDB
+	err := r.db.WithContext(ctx).
+		Where("household_id = ?", householdID).
+		Order("created_at desc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing household invitations: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	invitations := make([]domain.HouseholdInvitation, len(rows))
+	for i, row := range rows {
+		invitations[i] = row.ToDomain()
+	}
+	return invitations, nil
+}
+
+func (r *HouseholdRepository) FindInvitationByHash(ctx context.Context, tokenHash string) (domain.HouseholdInvitation, error) {
+	var row HouseholdInvitationDB
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.HouseholdInvitation{}, domain.ErrHouseholdInvitationNotFound
+		}
+		return domain.HouseholdInvitation{}, fmt.Errorf("error finding household invitation: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+// RevokeInvitation revokes a pending invitation of the household.
+func (r *HouseholdRepository) RevokeInvitation(ctx context.Context, householdID, id uuid.UUID, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&HouseholdInvitationDB{}).
+		Where("id = ? AND household_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id, householdID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return fmt.Errorf("error revoking household invitation: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrHouseholdInvitationNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation marks the invitation as accepted and adds the member. The
+// invitation is only accepted once, even by concurrent requests.
+func (r *HouseholdRepository) AcceptInvitation(ctx context.Context, invitation domain.HouseholdInvitation, member domain.HouseholdMember) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&HouseholdInvitationDB{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitation.ID).
+			Updates(map[string]interface{}{
+				"accepted_by": member.UserID,
+				"accepted_at": member.JoinedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("error accepting household invitation: %w: %s", ErrDatabaseError, result.Error.Error())
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrHouseholdInvitationInvalid
+		}
+
+		row := HouseholdMemberDB{
+			HouseholdID: member.HouseholdID,
+			UserID:      member.UserID,
+			Role:        string(member.Role),
+			JoinedAt:    member.JoinedAt,
+		}
+		if err := tx.Create(&row).Error; err != nil {
+			return fmt.Errorf("error adding household member: %w: %s", ErrDatabaseError, err.Error())
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *HouseholdRepository) AddShare(ctx context.Context, share domain.HouseholdShare) (domain.HouseholdShare, error) {
+	if share.CreatedAt.IsZero() {
+		share.CreatedAt = time.Now()
+	}
+
+	row := HouseholdShareDB{
+		HouseholdID:  share.HouseholdID,
+		ResourceType: string(share.ResourceType),
+		ResourceID:   share.ResourceID,
+		SharedBy:     share.SharedBy,
+		CreatedAt:    share.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return domain.HouseholdShare{}, fmt.Errorf("error creating household share: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+func (r *HouseholdRepository) FindShare(ctx context.Context, householdID uuid.UUID, resource domain.HouseholdResource, resourceID uuid.UUID) (domain.HouseholdShare, error) {
+	var row HouseholdShareDB
+	err := r.db.WithContext(ctx).
+		Where("household_id = ? AND resource_type = ? AND resource_id = ?", householdID, string(resource), resourceID).
+		First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.HouseholdShare{}, domain.ErrHouseholdShareNotFound
+		}
+		return domain.HouseholdShare{}, fmt.Errorf("error finding household share: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+func (r *HouseholdRepository) RemoveShare(ctx context.Context, householdID uuid.UUID, resource domain.HouseholdResource, resourceID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("household_id = ? AND resource_type = ? AND resource_id = ?", householdID, string(resource), resourceID).
+		Delete(&HouseholdShareDB{})
+	if result.Error != nil {
+		return fmt.Errorf("error deleting household share: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrHouseholdShareNotFound
+	}
+	return nil
+}
+
+// ResourceOwner returns the user that owns the resource, or ErrNotFound when it
+// does not exist.
+func (r *HouseholdRepository) ResourceOwner(ctx context.Context, resource domain.HouseholdResource, resourceID uuid.UUID) (string, error) {
+	table, ok := householdResourceTables[resource]
+	if !ok {
+		return "", domain.WrapInvalidInput(domain.New(fmt.Sprintf("unknown resource type %q", resource)), "household share")
+	}
+
+	var owners []string
+	err := r.db.WithContext(ctx).
+		Table(table).
+		Where("id = ?", resourceID).
+		Pluck("user_id", &owners).Error
+	if err != nil {
+		return "", fmt.Errorf("error finding resource owner: %w: %s", ErrDatabaseError, err.Error())
+	}
+	if len(owners) == 0 {
+		return "", domain.WrapNotFound(domain.New("resource not found"), string(resource))
+	}
+	return owners[0], nil
+}
+
+func (r *HouseholdRepository) withMembersAndShares(ctx context.Context, rows []HouseholdDB) ([]domain.Household, error) {
+	households := make([]domain.Household, len(rows))
+	if len(rows) == 0 {
+		return households, nil
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	byID := make(map[uuid.UUID]*domain.Household, len(rows))
+	for i, row := range rows {
+		households[i] = row.ToDomain()
+		ids[i] = row.ID
+		byID[row.ID] = &households[i]
+	}
+
+	var members []HouseholdMemberDB
+	if err := r.db.WithContext(ctx).Where("household_id IN ?", ids).Order("joined_at").Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("error finding household members: %w: %s", ErrDatabaseError, err.Error())
+	}
+	for _, member := range members {
+		household := byID[member.HouseholdID]
+		household.Members = append(household.Members, member.ToDomain())
+	}
+
+	var shares []HouseholdShareDB
+	if err := r.db.WithContext(ctx).Where("household_id IN ?", ids).Order("created_at").Find(&shares).Error; err != nil {
+		return nil, fmt.Errorf("error finding household shares: %w: %s", ErrDatabaseError, err.Error())
+	}
+	for _, share := range shares {
+		household := byID[share.HouseholdID]
+		household.Shares = append(household.Shares, share.ToDomain())
+	}
+
+	return households, nil
+}
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupHouseholdTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&HouseholdDB{}, &HouseholdMemberDB{}, &HouseholdInvitationDB{}, &HouseholdShareDB{},
+		&WalletDB{}, &MovementDB{}, &CreditCardDB{}, &InvoiceDB{},
+	))
+	return db
+}
+
+func householdUserContext(userID string, memberships ...domain.HouseholdMembership) context.Context {
+	ctx := context.WithValue(context.Background(), authentication.UserID, userID)
+	if len(memberships) > 0 {
+		ctx = ContextWithHouseholds(ctx, memberships)
+	}
+	return ctx
+}
+
+func TestHouseholdRepository_Members(t *testing.T) {
+	ctx := context.Background()
+	repo := NewHouseholdRepository(setupHouseholdTestDB(t))
+
+	household, err := repo.Create(ctx, domain.Household{Name: "Casa", OwnerID: "owner"})
+	require.NoError(t, err)
+
+	owner, err := repo.FindMember(ctx, household.ID, "owner")
+	require.NoError(t, err)
+	assert.Equal(t, domain.HouseholdRoleOwner, owner.Role, "the creator joins as owner")
+
+	now := time.Now()
+	invitation, err := repo.CreateInvitation(ctx, domain.HouseholdInvitation{
+		HouseholdID: household.ID,
+		Email:       "member@example.com",
+		Role:        domain.HouseholdRoleViewer,
+		TokenHash:   "hash",
+		InvitedBy:   "owner",
+		ExpiresAt:   now.Add(time.Hour),
+		CreatedAt:   now,
+	})
+	require.NoError(t, err)
+
+	member := domain.HouseholdMember{HouseholdID: household.ID, UserID: "member", Role: domain.HouseholdRoleViewer, JoinedAt: now}
+	require.NoError(t, repo.AcceptInvitation(ctx, invitation, member))
+	assert.ErrorIs(t, repo.AcceptInvitation(ctx, invitation, member), domain.ErrHouseholdInvitationInvalid,
+		"an invitation is accepted once")
+
+	found, err := repo.FindInvitationByHash(ctx, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, "member", found.AcceptedBy)
+	assert.NotNil(t, found.AcceptedAt)
+
+	memberships, err := repo.Memberships(ctx, "member")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.HouseholdMembership{{HouseholdID: household.ID, Role: domain.HouseholdRoleViewer}}, memberships)
+
+	require.NoError(t, repo.UpdateMemberRole(ctx, household.ID, "member", domain.HouseholdRoleEditor))
+	_, err = repo.AddShare(ctx, domain.HouseholdShare{
+		HouseholdID:  household.ID,
+		ResourceType: domain.HouseholdResourceWallet,
+		ResourceID:   uuid.New(),
+		SharedBy:     "member",
+		CreatedAt:    now,
+	})
+	require.NoError(t, err)
+
+	households, err := repo.ListByMember(ctx, "member")
+	require.NoError(t, err)
+	require.Len(t, households, 1)
+	assert.Len(t, households[0].Members, 2)
+	assert.Len(t, households[0].Shares, 1)
+
+	require.NoError(t, repo.RemoveMember(ctx, household.ID, "member"))
+	_, err = repo.FindMember(ctx, household.ID, "member")
+	assert.ErrorIs(t, err, domain.ErrHouseholdMemberNotFound)
+
+	household, err = repo.FindByID(ctx, household.ID)
+	require.NoError(t, err)
+	assert.Empty(t, household.Shares, "the shares of a removed member are dropped")
+
+	require.NoError(t, repo.Delete(ctx, household.ID))
+	_, err = repo.FindByID(ctx, household.ID)
+	assert.ErrorIs(t, err, domain.ErrHouseholdNotFound)
+}
+
+func TestHouseholdScoping(t *testing.T) {
+	db := setupHouseholdTestDB(t)
+	repo := NewHouseholdRepository(db)
+	wallets := NewWalletRepository(db)
+	movements := NewMovementRepository(db)
+
+	ctx := context.Background()
+	household, err := repo.Create(ctx, domain.Household{Name: "Casa", OwnerID: "owner"})
+	require.NoError(t, err)
+
+	walletID := uuid.New()
+	require.NoError(t, db.Create(&WalletDB{ID: &walletID, Description: "Conta conjunta", UserID: "owner", Balance: 100}).Error)
+
+	owner, err := repo.ResourceOwner(ctx, domain.HouseholdResourceWallet, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, "owner", owner)
+
+	_, err = repo.AddShare(ctx, domain.HouseholdShare{
+		HouseholdID:  household.ID,
+		ResourceType: domain.HouseholdResourceWallet,
+		ResourceID:   walletID,
+		SharedBy:     "owner",
+		CreatedAt:    time.Now(),
+	})
+	require.NoError(t, err)
+
+	editorCtx := householdUserContext("editor", domain.HouseholdMembership{HouseholdID: household.ID, Role: domain.HouseholdRoleEditor})
+	viewerCtx := householdUserContext("viewer", domain.HouseholdMembership{HouseholdID: household.ID, Role: domain.HouseholdRoleViewer})
+	outsiderCtx := householdUserContext("outsider")
+	date := time.Now()
+
+	t.Run("should list the shared wallet for members only", func(t *testing.T) {
+		found, err := wallets.FindAll(viewerCtx)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, walletID, *found[0].ID)
+
+		found, err = wallets.FindAll(outsiderCtx)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("should not let a viewer update the shared wallet", func(t *testing.T) {
+		assert.Error(t, wallets.UpdateAmount(viewerCtx, nil, &walletID, 50))
+		assert.NoError(t, wallets.UpdateAmount(editorCtx, nil, &walletID, 50))
+		assert.Error(t, wallets.RecalculateBalance(viewerCtx, &walletID))
+		assert.NoError(t, wallets.RecalculateBalance(editorCtx, &walletID))
+	})
+
+	t.Run("should book the movement of an editor to the wallet owner", func(t *testing.T) {
+		movement, err := movements.Add(editorCtx, nil, domain.Movement{
+			Description: "Mercado",
+			Amount:      -30,
+			WalletID:    &walletID,
+			Date:        &date,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "owner", movement.UserID)
+		assert.Equal(t, "editor", movement.CreatedBy)
+
+		found, err := movements.FindByID(viewerCtx, *movement.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "editor", found.CreatedBy)
+	})
+
+	t.Run("should not let a viewer add movements to the shared wallet", func(t *testing.T) {
+		_, err := movements.Add(viewerCtx, nil, domain.Movement{
+			Description: "Mercado",
+			Amount:      -30,
+			WalletID:    &walletID,
+			Date:        &date,
+		})
+		assert.ErrorIs(t, err, domain.ErrHouseholdForbidden)
+	})
+
+	t.Run("should book a movement moved to another wallet to that wallet owner", func(t *testing.T) {
+		editorWalletID := uuid.New()
+		strangerWalletID := uuid.New()
+		require.NoError(t, db.Create(&WalletDB{ID: &editorWalletID, Description: "Conta do editor", UserID: "editor"}).Error)
+		require.NoError(t, db.Create(&WalletDB{ID: &strangerWalletID, Description: "Conta de outro", UserID: "stranger"}).Error)
+
+		movement, err := movements.Add(editorCtx, nil, domain.Movement{
+			Description: "Farmácia",
+			Amount:      -20,
+			WalletID:    &walletID,
+			Date:        &date,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "owner", movement.UserID)
+
+		movement.WalletID = &strangerWalletID
+		_, err = movements.Update(editorCtx, nil, *movement.ID, movement)
+		assert.ErrorIs(t, err, domain.ErrHouseholdForbidden, "a wallet the user cannot write to is rejected")
+
+		movement.WalletID = &editorWalletID
+		updated, err := movements.Update(editorCtx, nil, *movement.ID, movement)
+		require.NoError(t, err)
+		assert.Equal(t, "editor", updated.UserID)
+
+		found, err := movements.FindByID(editorCtx, *movement.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "editor", found.UserID)
+		assert.Equal(t, editorWalletID, *found.WalletID)
+
+		_, err = movements.FindByID(viewerCtx, *movement.ID)
+		assert.Error(t, err, "the movement left the shared wallet")
+	})
+
+	t.Run("should find the shared credit card for members only", func(t *testing.T) {
+		cardID := uuid.New()
+		require.NoError(t, db.Create(&CreditCardDB{ID: &cardID, Name: "Cartão da casa", UserID: "owner", CreditLimit: 1000}).Error)
+		_, err := repo.AddShare(ctx, domain.HouseholdShare{
+			HouseholdID:  household.ID,
+			ResourceType: domain.HouseholdResourceCreditCard,
+			ResourceID:   cardID,
+			SharedBy:     "owner",
+			CreatedAt:    time.Now(),
+		})
+		require.NoError(t, err)
+		cards := NewCreditCardRepository(db)
+
+		card, err := cards.FindByID(viewerCtx, cardID)
+		require.NoError(t, err)
+		assert.Equal(t, "owner", card.UserID)
+
+		name, err := cards.FindNameByID(viewerCtx, cardID)
+		require.NoError(t, err)
+		assert.Equal(t, "Cartão da casa", name)
+
+		_, err = cards.FindByID(outsiderCtx, cardID)
+		assert.ErrorIs(t, err, ErrCreditCardNotFound)
+
+		_, err = cards.UpdateLimitDelta(viewerCtx, nil, cardID, -100)
+		assert.ErrorIs(t, err, ErrCreditCardNotFound, "viewers cannot charge a shared card")
+
+		card, err = cards.UpdateLimitDelta(editorCtx, nil, cardID, -100)
+		require.NoError(t, err)
+		assert.Equal(t, float64(900), card.CreditLimit)
+	})
+
+	t.Run("should book an editor purchase on a shared card to the owner invoice", func(t *testing.T) {
+		invoices := NewInvoiceRepository(db)
+		cardID := uuid.New()
+		cardWalletID := uuid.New()
+		require.NoError(t, db.Create(&WalletDB{ID: &cardWalletID, Description: "Conta do cartão", UserID: "owner"}).Error)
+		require.NoError(t, db.Create(&CreditCardDB{ID: &cardID, Name: "Cartão da família", UserID: "owner", CreditLimit: 1000, DefaultWalletID: &cardWalletID}).Error)
+		_, err := repo.AddShare(ctx, domain.HouseholdShare{
+			HouseholdID:  household.ID,
+			ResourceType: domain.HouseholdResourceCreditCard,
+			ResourceID:   cardID,
+			SharedBy:     "owner",
+			CreatedAt:    time.Now(),
+		})
+		require.NoError(t, err)
+
+		ownerCtx := householdUserContext("owner", domain.HouseholdMembership{HouseholdID: household.ID, Role: domain.HouseholdRoleOwner})
+		periodStart := date.AddDate(0, 0, -10)
+		invoice, err := invoices.Add(ownerCtx, nil, domain.Invoice{
+			CreditCardID: &cardID,
+			PeriodStart:  periodStart,
+			PeriodEnd:    date.AddDate(0, 0, 10),
+			DueDate:      date.AddDate(0, 0, 20),
+			Amount:       -50,
+			WalletID:     &cardWalletID,
+		})
+		require.NoError(t, err)
+
+		found, err := invoices.FindByMonthAndCreditCard(editorCtx, date, cardID)
+		require.NoError(t, err)
+		require.Equal(t, *invoice.ID, *found.ID, "the editor charges the owner invoice")
+
+		_, err = invoices.UpdateAmount(viewerCtx, nil, *invoice.ID, found.Amount-30)
+		assert.ErrorIs(t, err, ErrInvoiceNotFound, "viewers cannot charge a shared card")
+		_, err = invoices.UpdateAmount(editorCtx, nil, *invoice.ID, found.Amount-30)
+		require.NoError(t, err)
+
+		purchase, err := movements.Add(editorCtx, nil, domain.Movement{
+			Description:    "Mercado",
+			Amount:         -30,
+			WalletID:       &cardWalletID,
+			TypePayment:    domain.TypePaymentCreditCard,
+			Date:           &date,
+			CreditCardInfo: &domain.CreditCardMovement{InvoiceID: invoice.ID, CreditCardID: &cardID},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "owner", purchase.UserID)
+		assert.Equal(t, "editor", purchase.CreatedBy)
+
+		next, err := invoices.Add(editorCtx, nil, domain.Invoice{
+			CreditCardID: &cardID,
+			PeriodStart:  date.AddDate(0, 0, 11),
+			PeriodEnd:    date.AddDate(0, 1, 10),
+			DueDate:      date.AddDate(0, 1, 20),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "owner", next.UserID, "a new invoice is booked to the card owner")
+
+		var count int64
+		require.NoError(t, db.Model(&InvoiceDB{}).Where("credit_card_id = ? AND period_start = ?", cardID, periodStart).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+
+		owned, err := invoices.FindByID(ownerCtx, *invoice.ID)
+		require.NoError(t, err)
+		assert.Equal(t, float64(-80), owned.Amount)
+
+		listed, err := movements.FindByInvoiceID(viewerCtx, *invoice.ID)
+		require.NoError(t, err)
+		assert.Len(t, listed, 1, "members list the movements of a shared invoice")
+
+		require.NoError(t, movements.PayByInvoiceID(editorCtx, nil, *invoice.ID))
+		paid, err := movements.FindByID(ownerCtx, *purchase.ID)
+		require.NoError(t, err)
+		assert.True(t, paid.IsPaid)
+
+		_, err = invoices.FindByID(outsiderCtx, *invoice.ID)
+		assert.ErrorIs(t, err, ErrInvoiceNotFound)
+	})
+}
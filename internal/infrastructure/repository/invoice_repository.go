@@ -35,14 +35,18 @@ func (r *InvoiceRepository) Add(ctx context.Context, tx *gorm.DB, invoice domain
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	ownerID, err := r.creditCardOwner(ctx, tx, invoice.CreditCardID)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+
 	now := time.Now()
 	id := uuid.New()
 
 	invoice.ID = &id
 	invoice.DateCreate = now
 	invoice.DateUpdate = now
-	invoice.UserID = userID
+	invoice.UserID = ownerID
 
 	dbInvoice := FromInvoiceDomain(invoice)
 
@@ -59,11 +63,44 @@ func (r *InvoiceRepository) Add(ctx context.Context, tx *gorm.DB, invoice domain
 	return dbInvoice.ToDomain(), nil
 }
 
+// creditCardOwner returns the user the invoice is booked to: the owner of the
+// credit card, who is not the user when a household shares the card with it.
+// Only owners and editors of that household can charge it.
+func (r *InvoiceRepository) creditCardOwner(ctx context.Context, tx *gorm.DB, creditCardID *uuid.UUID) (string, error) {
+	userID := ctx.Value(authentication.UserID).(string)
+	if creditCardID == nil || len(householdIDsFromContext(ctx, false)) == 0 {
+		return userID, nil
+	}
+
+	scope, scopeArgs := userScope(ctx, "user_id", "id", domain.HouseholdResourceCreditCard, true)
+
+	var owners []string
+	err := tx.WithContext(ctx).
+		Model(&CreditCardDB{}).
+		Where("id = ?", creditCardID).
+		Where(scope, scopeArgs...).
+		Pluck("user_id", &owners).Error
+	if err != nil {
+		return "", fmt.Errorf("error finding credit card owner: %w: %s", ErrDatabaseError, err.Error())
+	}
+	if len(owners) == 0 {
+		return "", fmt.Errorf("error finding credit card owner: %w", domain.ErrHouseholdForbidden)
+	}
+
+	return owners[0], nil
+}
+
+// invoiceScope lets the households a credit card is shared with reach its
+// invoices. write only considers the households where the user can edit.
+func invoiceScope(ctx context.Context, write bool) (string, []interface{}) {
+	return userScope(ctx, "invoices.user_id", "invoices.credit_card_id", domain.HouseholdResourceCreditCard, write)
+}
+
 func (r *InvoiceRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Invoice, error) {
 	var dbModel InvoiceDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "credit_card_id", domain.HouseholdResourceCreditCard)
 	query = r.appendPreloads(query)
 
 	if err := query.First(&dbModel, fmt.Sprintf("%s.id = ?", tableName), id).Error; err != nil {
@@ -80,7 +117,7 @@ func (r *InvoiceRepository) FindOpenByMonth(ctx context.Context, date time.Time)
 	var dbModel InvoiceDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "credit_card_id", domain.HouseholdResourceCreditCard)
 	query = r.appendPreloads(query)
 
 	firstDay := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
@@ -108,7 +145,7 @@ func (r *InvoiceRepository) FindByMonth(ctx context.Context, date time.Time) ([]
 	var dbModel InvoiceDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "credit_card_id", domain.HouseholdResourceCreditCard)
 	query = r.appendPreloads(query)
 
 	firstDay := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
@@ -136,7 +173,7 @@ func (r *InvoiceRepository) FindByMonthAndCreditCard(ctx context.Context, date t
 	var dbModel InvoiceDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "credit_card_id", domain.HouseholdResourceCreditCard)
 	query = r.appendPreloads(query)
 
 	var dbInvoices InvoiceDB
@@ -159,7 +196,7 @@ func (r *InvoiceRepository) FindOpenByCreditCard(ctx context.Context, creditCard
 	var dbModel InvoiceDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "credit_card_id", domain.HouseholdResourceCreditCard)
 	query = r.appendPreloads(query)
 
 	query.Where(
@@ -192,7 +229,8 @@ func (r *InvoiceRepository) UpdateAmount(ctx context.Context, tx *gorm.DB, id uu
 	var invoiceDB InvoiceDB
 	tableName := invoiceDB.TableName()
 
-	query := BuildBaseQuery(ctx, tx, tableName)
+	scope, scopeArgs := invoiceScope(ctx, true)
+	query := tx.WithContext(ctx).Table(tableName).Where(scope, scopeArgs...)
 	query = r.appendPreloads(query)
 	if err := query.First(&invoiceDB, fmt.Sprintf("%s.id = ?", tableName), id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -236,7 +274,8 @@ func (r *InvoiceRepository) UpdateStatus(
 	var dbModel InvoiceDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, tx, tableName)
+	scope, scopeArgs := invoiceScope(ctx, true)
+	query := tx.WithContext(ctx).Table(tableName).Where(scope, scopeArgs...)
 	query = r.appendPreloads(query)
 	if err := query.First(&dbModel, fmt.Sprintf("%s.id = ?", tableName), id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -264,6 +303,81 @@ func (r *InvoiceRepository) UpdateStatus(
 	return dbModel.ToDomain(), nil
 }
 
+// FindToClose busca, sem escopo de usuário, as faturas cujo período terminou antes
+// da data informada e que ainda não foram fechadas. Usado pelo job interno.
+func (r *InvoiceRepository) FindToClose(ctx context.Context, date time.Time) ([]domain.Invoice, error) {
+	var dbModel InvoiceDB
+	tableName := dbModel.TableName()
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	var dbInvoices []InvoiceDB
+	err := r.db.WithContext(ctx).
+		Table(tableName).
+		Where(fmt.Sprintf("%s.period_end < ? AND %s.closed_at IS NULL", tableName, tableName), startOfDay).
+		Find(&dbInvoices).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding invoices to close: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	invoices := make([]domain.Invoice, len(dbInvoices))
+	for i, dbInvoice := range dbInvoices {
+		invoices[i] = dbInvoice.ToDomain()
+	}
+
+	return invoices, nil
+}
+
+// FindDueForAutoDebit busca, sem escopo de usuário, as faturas fechadas e não pagas
+// que vencem até a data informada e cujo cartão tem débito automático habilitado.
+func (r *InvoiceRepository) FindDueForAutoDebit(ctx context.Context, date time.Time) ([]domain.Invoice, error) {
+	var dbModel InvoiceDB
+	tableName := dbModel.TableName()
+	endOfDay := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 999999999, time.UTC)
+
+	var dbInvoices []InvoiceDB
+	err := r.db.WithContext(ctx).
+		Table(tableName).
+		Preload("CreditCard").
+		Joins(fmt.Sprintf("JOIN credit_cards ON credit_cards.id = %s.credit_card_id", tableName)).
+		Where("credit_cards.auto_debit = ?", true).
+		Where(fmt.Sprintf("%s.is_paid = ? AND %s.closed_at IS NOT NULL", tableName, tableName), false).
+		Where(fmt.Sprintf("%s.due_date <= ?", tableName), endOfDay).
+		Find(&dbInvoices).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding invoices due for auto debit: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	invoices := make([]domain.Invoice, len(dbInvoices))
+	for i, dbInvoice := range dbInvoices {
+		invoices[i] = dbInvoice.ToDomain()
+	}
+
+	return invoices, nil
+}
+
+// Close congela o valor atual da fatura em closed_amount. A condição closed_at IS NULL
+// torna a operação idempotente: retorna false quando a fatura já estava fechada.
+func (r *InvoiceRepository) Close(ctx context.Context, tx *gorm.DB, id uuid.UUID, closedAt time.Time) (bool, error) {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+
+	result := db.WithContext(ctx).
+		Model(&InvoiceDB{}).
+		Where("id = ? AND closed_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"closed_at":     closedAt,
+			"closed_amount": gorm.Expr("amount"),
+			"date_update":   time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("error closing invoice: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
 func (r *InvoiceRepository) appendPreloads(query *gorm.DB) *gorm.DB {
 	return query.Preload("CreditCard").Preload("Wallet")
 }
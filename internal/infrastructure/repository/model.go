@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"personal-finance/internal/domain"
@@ -14,6 +16,7 @@ type MovementDB struct {
 	Amount             float64       `gorm:"amount"`
 	Date               *time.Time    `gorm:"date"`
 	UserID             string        `gorm:"user_id"`
+	CreatedBy          *string       `gorm:"created_by"`
 	IsPaid             bool          `gorm:"is_paid"`
 	RecurrentID        *uuid.UUID    `gorm:"recurrent_id"`
 	PairID             *uuid.UUID    `gorm:"pair_id"`
@@ -22,6 +25,8 @@ type MovementDB struct {
 	InstallmentGroupID *uuid.UUID    `gorm:"installment_group_id"`
 	InstallmentNumber  *int          `gorm:"installment_number"`
 	TotalInstallments  *int          `gorm:"total_installments"`
+	TotalAmount        *float64      `gorm:"total_amount"`
+	InterestRate       *float64      `gorm:"interest_rate"`
 	WalletID           *uuid.UUID    `gorm:"wallet_id"`
 	Wallet             WalletDB      `gorm:"wallets"`
 	TypePayment        string        `gorm:"type_payment"`
@@ -61,12 +66,18 @@ func (m MovementDB) ToDomain() domain.Movement {
 		DateUpdate:    m.DateUpdate,
 	}
 
+	if m.CreatedBy != nil {
+		movement.CreatedBy = *m.CreatedBy
+	}
+
 	if m.InvoiceID != nil || m.InstallmentGroupID != nil {
 		creditCardInfo := &domain.CreditCardMovement{
 			InvoiceID:          m.InvoiceID,
 			InstallmentGroupID: m.InstallmentGroupID,
 			InstallmentNumber:  m.InstallmentNumber,
 			TotalInstallments:  m.TotalInstallments,
+			TotalAmount:        m.TotalAmount,
+			InterestRate:       m.InterestRate,
 		}
 
 		if m.InvoiceID != nil && m.Invoice.ID != nil {
@@ -98,11 +109,17 @@ func FromMovementDomain(d domain.Movement) MovementDB {
 		DateUpdate:      d.DateUpdate,
 	}
 
+	if d.CreatedBy != "" {
+		movementDB.CreatedBy = &d.CreatedBy
+	}
+
 	if d.CreditCardInfo != nil {
 		movementDB.InvoiceID = d.CreditCardInfo.InvoiceID
 		movementDB.InstallmentGroupID = d.CreditCardInfo.InstallmentGroupID
 		movementDB.InstallmentNumber = d.CreditCardInfo.InstallmentNumber
 		movementDB.TotalInstallments = d.CreditCardInfo.TotalInstallments
+		movementDB.TotalAmount = d.CreditCardInfo.TotalAmount
+		movementDB.InterestRate = d.CreditCardInfo.InterestRate
 	}
 
 	return movementDB
@@ -180,6 +197,7 @@ type CreditCardDB struct {
 	Color           string
 	DefaultWalletID *uuid.UUID
 	DefaultWallet   WalletDB `gorm:"foreignKey:DefaultWalletID"`
+	AutoDebit       bool
 	UserID          string
 	DateCreate      time.Time
 	DateUpdate      time.Time
@@ -199,6 +217,7 @@ func (c CreditCardDB) ToDomain() domain.CreditCard {
 		Color:           c.Color,
 		DefaultWalletID: c.DefaultWalletID,
 		DefaultWallet:   c.DefaultWallet.ToDomain(),
+		AutoDebit:       c.AutoDebit,
 		UserID:          c.UserID,
 		DateCreate:      c.DateCreate,
 		DateUpdate:      c.DateUpdate,
@@ -214,6 +233,7 @@ func FromCreditCardDomain(creditCard domain.CreditCard) CreditCardDB {
 		DueDay:          creditCard.DueDay,
 		Color:           creditCard.Color,
 		DefaultWalletID: creditCard.DefaultWalletID,
+		AutoDebit:       creditCard.AutoDebit,
 		UserID:          creditCard.UserID,
 		DateCreate:      creditCard.DateCreate,
 		DateUpdate:      creditCard.DateUpdate,
@@ -232,6 +252,8 @@ type InvoiceDB struct {
 	IsPaid       bool
 	WalletID     *uuid.UUID
 	Wallet       WalletDB `gorm:"foreignKey:WalletID"`
+	ClosedAt     *time.Time
+	ClosedAmount *float64
 	UserID       string
 	DateCreate   time.Time
 	DateUpdate   time.Time
@@ -254,6 +276,8 @@ func (i InvoiceDB) ToDomain() domain.Invoice {
 		IsPaid:       i.IsPaid,
 		WalletID:     i.WalletID,
 		Wallet:       i.Wallet.ToDomain(),
+		ClosedAt:     i.ClosedAt,
+		ClosedAmount: i.ClosedAmount,
 		UserID:       i.UserID,
 		DateCreate:   i.DateCreate,
 		DateUpdate:   i.DateUpdate,
@@ -272,6 +296,8 @@ func FromInvoiceDomain(invoice domain.Invoice) InvoiceDB {
 		Amount:       invoice.Amount,
 		IsPaid:       invoice.IsPaid,
 		WalletID:     invoice.WalletID,
+		ClosedAt:     invoice.ClosedAt,
+		ClosedAmount: invoice.ClosedAmount,
 		UserID:       invoice.UserID,
 		DateCreate:   invoice.DateCreate,
 		DateUpdate:   invoice.DateUpdate,
@@ -303,6 +329,7 @@ type RecurrentMovementDB struct {
 	SubCategoryID *uuid.UUID    `gorm:"sub_category_id"`
 	SubCategory   SubCategoryDB `gorm:"sub_categories"`
 	TypePayment   string        `gorm:"type_payment"`
+	PairID        *uuid.UUID    `gorm:"pair_id"`
 }
 
 func (RecurrentMovementDB) TableName() string {
@@ -324,6 +351,7 @@ func (r RecurrentMovementDB) ToDomain() domain.RecurrentMovement {
 		Category:      r.Category.ToDomain(),
 		SubCategoryID: r.SubCategoryID,
 		SubCategory:   r.SubCategory.ToDomain(),
+		PairID:        r.PairID,
 	}
 }
 
@@ -339,16 +367,19 @@ func FromRecurrentMovementDomain(d domain.RecurrentMovement) RecurrentMovementDB
 		TypePayment:   string(d.TypePayment),
 		CategoryID:    d.CategoryID,
 		SubCategoryID: d.SubCategoryID,
+		PairID:        d.PairID,
 	}
 }
 
 type WalletDB struct {
 	ID             *uuid.UUID `gorm:"primaryKey"`
 	Description    string     `gorm:"description"`
+	Type           string     `gorm:"type"`
 	Balance        float64    `gorm:"balance"`
 	UserID         string     `gorm:"user_id"`
 	InitialBalance float64    `gorm:"initial_balance"`
 	InitialDate    time.Time  `gorm:"initial_date"`
+	ArchivedAt     *time.Time `gorm:"archived_at"`
 	DateCreate     time.Time  `gorm:"date_create"`
 	DateUpdate     time.Time  `gorm:"date_update"`
 }
@@ -361,10 +392,12 @@ func (w WalletDB) ToDomain() domain.Wallet {
 	return domain.Wallet{
 		ID:             w.ID,
 		Description:    w.Description,
+		Type:           domain.WalletType(w.Type),
 		Balance:        w.Balance,
 		UserID:         w.UserID,
 		InitialBalance: w.InitialBalance,
 		InitialDate:    w.InitialDate,
+		ArchivedAt:     w.ArchivedAt,
 		DateCreate:     w.DateCreate,
 		DateUpdate:     w.DateUpdate,
 	}
@@ -374,10 +407,12 @@ func FromWalletDomain(d domain.Wallet) WalletDB {
 	return WalletDB{
 		ID:             d.ID,
 		Description:    d.Description,
+		Type:           string(d.Type),
 		Balance:        d.Balance,
 		UserID:         d.UserID,
 		InitialBalance: d.InitialBalance,
 		InitialDate:    d.InitialDate,
+		ArchivedAt:     d.ArchivedAt,
 		DateCreate:     d.DateCreate,
 		DateUpdate:     d.DateUpdate,
 	}
@@ -503,6 +538,8 @@ type SubscriptionPlanDB struct {
 	AppleProductID  *string `gorm:"column:apple_product_id"`
 	GoogleProductID *string `gorm:"column:google_product_id"`
 	StripePriceID   *string `gorm:"column:stripe_price_id"`
+	Entitlements    []byte  `gorm:"column:entitlements;type:jsonb"`
+	TrialDays       int     `gorm:"column:trial_days"`
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -525,9 +562,31 @@ func (p SubscriptionPlanDB) ToDomain() domain.SubscriptionPlan {
 		FrequencyType: p.FrequencyType,
 		IsActive:      p.IsActive,
 		StripePriceID: stripePriceID,
+		TrialDays:     p.TrialDays,
+		Entitlements:  entitlementsToDomain(p.Entitlements),
 	}
 }
 
+// entitlementsToDomain leaves the plan on its tier defaults when the column is
+// empty or unreadable; entitlements are validated before they are stored.
+func entitlementsToDomain(raw []byte) *domain.Entitlements {
+	if len(raw) == 0 {
+		return nil
+	}
+	var ent domain.Entitlements
+	if err := json.Unmarshal(raw, &ent); err != nil {
+		return nil
+	}
+	return &ent
+}
+
+func entitlementsFromDomain(ent *domain.Entitlements) ([]byte, error) {
+	if ent == nil {
+		return nil, nil
+	}
+	return json.Marshal(ent)
+}
+
 type SubscriptionDB struct {
 	ID                uuid.UUID  `gorm:"primaryKey;column:id"`
 	UserID            string     `gorm:"column:user_id"`
@@ -541,6 +600,8 @@ type SubscriptionDB struct {
 	StartedAt         time.Time  `gorm:"column:started_at"`
 	CurrentPeriodEnd  *time.Time `gorm:"column:current_period_end"`
 	CancelledAt       *time.Time `gorm:"column:cancelled_at"`
+	TrialEndsAt       *time.Time `gorm:"column:trial_ends_at"`
+	GraceEndsAt       *time.Time `gorm:"column:grace_ends_at"`
 	CreatedAt         time.Time  `gorm:"column:created_at"`
 	UpdatedAt         time.Time  `gorm:"column:updated_at"`
 }
@@ -567,6 +628,8 @@ func (s SubscriptionDB) ToDomain() domain.Subscription {
 		StartedAt:         s.StartedAt,
 		CurrentPeriodEnd:  s.CurrentPeriodEnd,
 		CancelledAt:       s.CancelledAt,
+		TrialEndsAt:       s.TrialEndsAt,
+		GraceEndsAt:       s.GraceEndsAt,
 		CreatedAt:         s.CreatedAt,
 		UpdatedAt:         s.UpdatedAt,
 	}
@@ -634,6 +697,7 @@ type CouponRedemptionDB struct {
 	CouponID       string     `gorm:"column:coupon_id;uniqueIndex:idx_coupon_redemptions_user_coupon"`
 	PlanID         string     `gorm:"column:plan_id"`
 	SubscriptionID *uuid.UUID `gorm:"column:subscription_id"`
+	Code           string     `gorm:"column:code"`
 	OriginalPrice  float64    `gorm:"column:original_price"`
 	LockedPrice    float64    `gorm:"column:locked_price"`
 	Status         string     `gorm:"column:status"`
@@ -652,6 +716,7 @@ func (r CouponRedemptionDB) ToDomain() domain.CouponRedemption {
 		CouponID:       r.CouponID,
 		PlanID:         r.PlanID,
 		SubscriptionID: r.SubscriptionID,
+		Code:           r.Code,
 		OriginalPrice:  r.OriginalPrice,
 		LockedPrice:    r.LockedPrice,
 		Status:         domain.CouponRedemptionStatus(r.Status),
@@ -667,6 +732,7 @@ func FromCouponRedemptionDomain(d domain.CouponRedemption) CouponRedemptionDB {
 		CouponID:       d.CouponID,
 		PlanID:         d.PlanID,
 		SubscriptionID: d.SubscriptionID,
+		Code:           d.Code,
 		OriginalPrice:  d.OriginalPrice,
 		LockedPrice:    d.LockedPrice,
 		Status:         string(d.Status),
@@ -675,6 +741,310 @@ func FromCouponRedemptionDomain(d domain.CouponRedemption) CouponRedemptionDB {
 	}
 }
 
+type CouponCampaignDB struct {
+	ID             string    `gorm:"primaryKey;column:id"`
+	Name           string    `gorm:"column:name"`
+	CouponID       string    `gorm:"column:coupon_id;index:idx_coupon_campaigns_coupon"`
+	StripeCouponID string    `gorm:"column:stripe_coupon_id"`
+	CodePrefix     string    `gorm:"column:code_prefix"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+func (CouponCampaignDB) TableName() string {
+	return "coupon_campaigns"
+}
+
+func (c CouponCampaignDB) ToDomain() domain.CouponCampaign {
+	return domain.CouponCampaign{
+		ID:             c.ID,
+		Name:           c.Name,
+		CouponID:       c.CouponID,
+		StripeCouponID: c.StripeCouponID,
+		CodePrefix:     c.CodePrefix,
+		CreatedAt:      c.CreatedAt,
+	}
+}
+
+func FromCouponCampaignDomain(d domain.CouponCampaign) CouponCampaignDB {
+	return CouponCampaignDB{
+		ID:             d.ID,
+		Name:           d.Name,
+		CouponID:       d.CouponID,
+		StripeCouponID: d.StripeCouponID,
+		CodePrefix:     d.CodePrefix,
+		CreatedAt:      d.CreatedAt,
+	}
+}
+
+type CouponCampaignCodeDB struct {
+	Code                  string     `gorm:"primaryKey;column:code"`
+	CampaignID            string     `gorm:"column:campaign_id;index:idx_coupon_campaign_codes_campaign"`
+	Status                string     `gorm:"column:status"`
+	RedeemedBy            *string    `gorm:"column:redeemed_by"`
+	RedemptionID          *uuid.UUID `gorm:"column:redemption_id"`
+	RedeemedAt            *time.Time `gorm:"column:redeemed_at"`
+	StripePromotionCodeID *string    `gorm:"column:stripe_promotion_code_id"`
+	ReservedBy            *string    `gorm:"column:reserved_by"`
+	ReservedUntil         *time.Time `gorm:"column:reserved_until"`
+	CreatedAt             time.Time  `gorm:"column:created_at"`
+}
+
+func (CouponCampaignCodeDB) TableName() string {
+	return "coupon_campaign_codes"
+}
+
+func (c CouponCampaignCodeDB) ToDomain() domain.CouponCampaignCode {
+	code := domain.CouponCampaignCode{
+		Code:         c.Code,
+		CampaignID:   c.CampaignID,
+		Status:       domain.CouponCodeStatus(c.Status),
+		RedemptionID: c.RedemptionID,
+		RedeemedAt:    c.RedeemedAt,
+		ReservedUntil: c.ReservedUntil,
+		CreatedAt:     c.CreatedAt,
+	}
+	if c.RedeemedBy != nil {
+		code.RedeemedBy = *c.RedeemedBy
+	}
+	if c.ReservedBy != nil {
+		code.ReservedBy = *c.ReservedBy
+	}
+	if c.StripePromotionCodeID != nil {
+		code.StripePromotionCodeID = *c.StripePromotionCodeID
+	}
+	return code
+}
+
+type ReferralCodeDB struct {
+	UserID    string    `gorm:"primaryKey;column:user_id"`
+	Code      string    `gorm:"column:code;uniqueIndex:idx_referral_codes_code"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (ReferralCodeDB) TableName() string {
+	return "referral_codes"
+}
+
+func (r ReferralCodeDB) ToDomain() domain.ReferralCode {
+	return domain.ReferralCode{
+		UserID:    r.UserID,
+		Code:      r.Code,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type ReferralDB struct {
+	ID           uuid.UUID  `gorm:"primaryKey;column:id"`
+	ReferrerID   string     `gorm:"column:referrer_id;index:idx_referrals_referrer"`
+	RefereeID    string     `gorm:"column:referee_id;uniqueIndex:idx_referrals_referee"`
+	Code         string     `gorm:"column:code"`
+	Status       string     `gorm:"column:status"`
+	RejectReason *string    `gorm:"column:reject_reason"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	QualifiedAt  *time.Time `gorm:"column:qualified_at"`
+}
+
+func (ReferralDB) TableName() string {
+	return "referrals"
+}
+
+func (r ReferralDB) ToDomain() domain.Referral {
+	referral := domain.Referral{
+		ID:          r.ID,
+		ReferrerID:  r.ReferrerID,
+		RefereeID:   r.RefereeID,
+		Code:        r.Code,
+		Status:      domain.ReferralStatus(r.Status),
+		CreatedAt:   r.CreatedAt,
+		QualifiedAt: r.QualifiedAt,
+	}
+	if r.RejectReason != nil {
+		referral.RejectReason = *r.RejectReason
+	}
+	return referral
+}
+
+type ReferralRewardDB struct {
+	ID         uuid.UUID  `gorm:"primaryKey;column:id"`
+	ReferralID uuid.UUID  `gorm:"column:referral_id;uniqueIndex:idx_referral_rewards_referral_user"`
+	UserID     string     `gorm:"column:user_id;uniqueIndex:idx_referral_rewards_referral_user"`
+	Role       string     `gorm:"column:role"`
+	Months     int        `gorm:"column:months"`
+	Status     string     `gorm:"column:status"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	AppliedAt  *time.Time `gorm:"column:applied_at"`
+	PlusUntil  *time.Time `gorm:"column:plus_until"`
+}
+
+func (ReferralRewardDB) TableName() string {
+	return "referral_rewards"
+}
+
+func (r ReferralRewardDB) ToDomain() domain.ReferralReward {
+	return domain.ReferralReward{
+		ID:         r.ID,
+		ReferralID: r.ReferralID,
+		UserID:     r.UserID,
+		Role:       domain.ReferralRole(r.Role),
+		Months:     r.Months,
+		Status:     domain.ReferralRewardStatus(r.Status),
+		CreatedAt:  r.CreatedAt,
+		AppliedAt:  r.AppliedAt,
+		PlusUntil:  r.PlusUntil,
+	}
+}
+
+func FromReferralRewardDomain(d domain.ReferralReward) ReferralRewardDB {
+	return ReferralRewardDB{
+		ID:         d.ID,
+		ReferralID: d.ReferralID,
+		UserID:     d.UserID,
+		Role:       string(d.Role),
+		Months:     d.Months,
+		Status:     string(d.Status),
+		CreatedAt:  d.CreatedAt,
+		AppliedAt:  d.AppliedAt,
+		PlusUntil:  d.PlusUntil,
+	}
+}
+
+type PersonalAccessTokenDB struct {
+	ID         uuid.UUID  `gorm:"primaryKey;column:id"`
+	UserID     string     `gorm:"column:user_id;index"`
+	Name       string     `gorm:"column:name"`
+	TokenHash  string     `gorm:"column:token_hash;uniqueIndex"`
+	Prefix     string     `gorm:"column:prefix"`
+	Scopes     string     `gorm:"column:scopes"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+}
+
+func (PersonalAccessTokenDB) TableName() string {
+	return "personal_access_tokens"
+}
+
+func (t PersonalAccessTokenDB) ToDomain() domain.PersonalAccessToken {
+	scopes := []string{}
+	if t.Scopes != "" {
+		scopes = strings.Split(t.Scopes, ",")
+	}
+	return domain.PersonalAccessToken{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		TokenHash:  t.TokenHash,
+		Prefix:     t.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		RevokedAt:  t.RevokedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+type HouseholdDB struct {
+	ID        uuid.UUID `gorm:"primaryKey;column:id"`
+	Name      string    `gorm:"column:name"`
+	OwnerID   string    `gorm:"column:owner_id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (HouseholdDB) TableName() string {
+	return "households"
+}
+
+func (h HouseholdDB) ToDomain() domain.Household {
+	return domain.Household{
+		ID:        h.ID,
+		Name:      h.Name,
+		OwnerID:   h.OwnerID,
+		Members:   []domain.HouseholdMember{},
+		Shares:    []domain.HouseholdShare{},
+		CreatedAt: h.CreatedAt,
+	}
+}
+
+type HouseholdMemberDB struct {
+	HouseholdID uuid.UUID `gorm:"primaryKey;column:household_id"`
+	UserID      string    `gorm:"primaryKey;column:user_id;index:idx_household_members_user"`
+	Role        string    `gorm:"column:role"`
+	JoinedAt    time.Time `gorm:"column:joined_at"`
+}
+
+func (HouseholdMemberDB) TableName() string {
+	return "household_members"
+}
+
+func (m HouseholdMemberDB) ToDomain() domain.HouseholdMember {
+	return domain.HouseholdMember{
+		HouseholdID: m.HouseholdID,
+		UserID:      m.UserID,
+		Role:        domain.HouseholdRole(m.Role),
+		JoinedAt:    m.JoinedAt,
+	}
+}
+
+type HouseholdInvitationDB struct {
+	ID          uuid.UUID  `gorm:"primaryKey;column:id"`
+	HouseholdID uuid.UUID  `gorm:"column:household_id;index:idx_household_invitations_household"`
+	Email       string     `gorm:"column:email"`
+	Role        string     `gorm:"column:role"`
+	TokenHash   string     `gorm:"column:token_hash;uniqueIndex"`
+	InvitedBy   string     `gorm:"column:invited_by"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at"`
+	AcceptedBy  *string    `gorm:"column:accepted_by"`
+	AcceptedAt  *time.Time `gorm:"column:accepted_at"`
+	RevokedAt   *time.Time `gorm:"column:revoked_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+}
+
+func (HouseholdInvitationDB) TableName() string {
+	return "household_invitations"
+}
+
+func (i HouseholdInvitationDB) ToDomain() domain.HouseholdInvitation {
+	invitation := domain.HouseholdInvitation{
+		ID:          i.ID,
+		HouseholdID: i.HouseholdID,
+		Email:       i.Email,
+		Role:        domain.HouseholdRole(i.Role),
+		TokenHash:   i.TokenHash,
+		InvitedBy:   i.InvitedBy,
+		ExpiresAt:   i.ExpiresAt,
+		AcceptedAt:  i.AcceptedAt,
+		RevokedAt:   i.RevokedAt,
+		CreatedAt:   i.CreatedAt,
+	}
+	if i.AcceptedBy != nil {
+		invitation.AcceptedBy = *i.AcceptedBy
+	}
+	return invitation
+}
+
+type HouseholdShareDB struct {
+	HouseholdID  uuid.UUID `gorm:"primaryKey;column:household_id"`
+	ResourceType string    `gorm:"primaryKey;column:resource_type"`
+	ResourceID   uuid.UUID `gorm:"primaryKey;column:resource_id"`
+	SharedBy     string    `gorm:"column:shared_by"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+func (HouseholdShareDB) TableName() string {
+	return "household_shares"
+}
+
+func (s HouseholdShareDB) ToDomain() domain.HouseholdShare {
+	return domain.HouseholdShare{
+		HouseholdID:  s.HouseholdID,
+		ResourceType: domain.HouseholdResource(s.ResourceType),
+		ResourceID:   s.ResourceID,
+		SharedBy:     s.SharedBy,
+		CreatedAt:    s.CreatedAt,
+	}
+}
+
 func FromSubscriptionDomain(d domain.Subscription) SubscriptionDB {
 	var planID *string
 	if d.PlanID != "" {
@@ -693,6 +1063,8 @@ func FromSubscriptionDomain(d domain.Subscription) SubscriptionDB {
 		StartedAt:         d.StartedAt,
 		CurrentPeriodEnd:  d.CurrentPeriodEnd,
 		CancelledAt:       d.CancelledAt,
+		TrialEndsAt:       d.TrialEndsAt,
+		GraceEndsAt:       d.GraceEndsAt,
 		CreatedAt:         d.CreatedAt,
 		UpdatedAt:         d.UpdatedAt,
 	}
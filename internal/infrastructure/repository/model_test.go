@@ -44,6 +44,20 @@ func TestMovementModelToDomain(t *testing.T) {
 	assert.Equal(t, domainMovement.TypePayment, resultDomain.TypePayment)
 }
 
+func TestMovementModel_InstallmentPlan(t *testing.T) {
+	totalAmount := -300.0
+	interestRate := 0.0199
+	groupID := uuid.New()
+	domainMovement := fixture.MovementMock(fixture.WithMovementInstallment(1, 3), fixture.WithMovementInstallmentGroupID(&groupID))
+	domainMovement.CreditCardInfo.TotalAmount = &totalAmount
+	domainMovement.CreditCardInfo.InterestRate = &interestRate
+
+	resultDomain := FromMovementDomain(domainMovement).ToDomain()
+
+	assert.Equal(t, &totalAmount, resultDomain.CreditCardInfo.TotalAmount)
+	assert.Equal(t, &interestRate, resultDomain.CreditCardInfo.InterestRate)
+}
+
 func TestToSubCategoryModel(t *testing.T) {
 	domainSubCategory := fixture.SubCategoryMock()
 
@@ -32,13 +32,22 @@ func (r *MovementRepository) Add(ctx context.Context, tx *gorm.DB, movement doma
 	}
 
 	userID := ctx.Value(authentication.UserID).(string)
+	ownerID, err := r.walletOwner(ctx, tx, movement.WalletID)
+	if movement.IsCreditCardMovement() && movement.CreditCardInfo != nil && movement.CreditCardInfo.InvoiceID != nil {
+		ownerID, err = r.invoiceOwner(ctx, tx, movement.CreditCardInfo.InvoiceID)
+	}
+	if err != nil {
+		return domain.Movement{}, err
+	}
+
 	now := time.Now()
 	id := uuid.New()
 
 	movement.ID = &id
 	movement.DateCreate = now
 	movement.DateUpdate = now
-	movement.UserID = userID
+	movement.UserID = ownerID
+	movement.CreatedBy = userID
 
 	dbMovement := FromMovementDomain(movement)
 
@@ -55,11 +64,64 @@ func (r *MovementRepository) Add(ctx context.Context, tx *gorm.DB, movement doma
 	return dbMovement.ToDomain(), nil
 }
 
+// walletOwner returns the user the movement is booked to: the owner of the
+// wallet, who is not the user when a household shares the wallet with it.
+// Only owners and editors of that household can add movements to it.
+func (r *MovementRepository) walletOwner(ctx context.Context, tx *gorm.DB, walletID *uuid.UUID) (string, error) {
+	userID := ctx.Value(authentication.UserID).(string)
+	if walletID == nil || len(householdIDsFromContext(ctx, false)) == 0 {
+		return userID, nil
+	}
+
+	scope, scopeArgs := userScope(ctx, "user_id", "id", domain.HouseholdResourceWallet, true)
+
+	var owners []string
+	err := tx.WithContext(ctx).
+		Model(&WalletDB{}).
+		Where("id = ?", walletID).
+		Where(scope, scopeArgs...).
+		Pluck("user_id", &owners).Error
+	if err != nil {
+		return "", fmt.Errorf("error finding wallet owner: %w: %s", ErrDatabaseError, err.Error())
+	}
+	if len(owners) == 0 {
+		return "", fmt.Errorf("error finding wallet owner: %w", domain.ErrHouseholdForbidden)
+	}
+
+	return owners[0], nil
+}
+
+// invoiceOwner returns the user a credit card purchase is booked to: the owner
+// of the invoice, and so of the card, which the user must be able to charge.
+func (r *MovementRepository) invoiceOwner(ctx context.Context, tx *gorm.DB, invoiceID *uuid.UUID) (string, error) {
+	userID := ctx.Value(authentication.UserID).(string)
+	if len(householdIDsFromContext(ctx, false)) == 0 {
+		return userID, nil
+	}
+
+	scope, scopeArgs := invoiceScope(ctx, true)
+
+	var owners []string
+	err := tx.WithContext(ctx).
+		Model(&InvoiceDB{}).
+		Where("invoices.id = ?", invoiceID).
+		Where(scope, scopeArgs...).
+		Pluck("user_id", &owners).Error
+	if err != nil {
+		return "", fmt.Errorf("error finding invoice owner: %w: %s", ErrDatabaseError, err.Error())
+	}
+	if len(owners) == 0 {
+		return "", fmt.Errorf("error finding invoice owner: %w", domain.ErrHouseholdForbidden)
+	}
+
+	return owners[0], nil
+}
+
 func (r *MovementRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Movement, error) {
 	var dbModel MovementDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "wallet_id", domain.HouseholdResourceWallet)
 	query = r.appendPreloads(query)
 
 	if err := query.First(&dbModel, fmt.Sprintf("%s.id = ?", tableName), id).Error; err != nil {
@@ -76,7 +138,7 @@ func (r *MovementRepository) FindByInstallmentGroupFromNumber(ctx context.Contex
 	var dbModel MovementDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "wallet_id", domain.HouseholdResourceWallet)
 	query = r.appendPreloads(query)
 
 	var dbMovements []MovementDB
@@ -99,7 +161,7 @@ func (r *MovementRepository) FindByPeriod(ctx context.Context, period domain.Per
 	var dbModel MovementDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "wallet_id", domain.HouseholdResourceWallet)
 	query = r.appendPreloads(query)
 
 	var dbMovements []MovementDB
@@ -129,11 +191,12 @@ func (r *MovementRepository) UpdateIsPaid(ctx context.Context, tx *gorm.DB, id u
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := movementWriteScope(ctx)
 	now := time.Now()
 
 	result := tx.Model(&MovementDB{}).
-		Where("id = ? AND user_id = ?", id, userID).
+		Where("id = ?", id).
+		Where(scope, scopeArgs...).
 		Updates(map[string]interface{}{
 			"is_paid":     movement.IsPaid,
 			"date_update": now,
@@ -164,15 +227,28 @@ func (r *MovementRepository) Update(ctx context.Context, tx *gorm.DB, id uuid.UU
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := movementWriteScope(ctx)
 	now := time.Now()
 
+	columns := []interface{}{"amount", "date", "wallet_id", "category_id", "sub_category_id", "type_payment", "recurrent_id", "date_update"}
+	if movement.WalletID != nil {
+		// A movement moved to another wallet is booked to the owner of that
+		// wallet, which the user must be able to write to.
+		ownerID, err := r.walletOwner(ctx, tx, movement.WalletID)
+		if err != nil {
+			return domain.Movement{}, err
+		}
+		movement.UserID = ownerID
+		columns = append(columns, "user_id")
+	}
+
 	movement.DateUpdate = now
 	dbMovement := FromMovementDomain(movement)
 
 	result := tx.Model(&MovementDB{}).
-		Where("id = ? AND user_id = ?", id, userID).
-		Select("description", "amount", "date", "wallet_id", "category_id", "sub_category_id", "type_payment", "recurrent_id", "date_update").
+		Where("id = ?", id).
+		Where(scope, scopeArgs...).
+		Select("description", columns...).
 		Updates(dbMovement)
 
 	if err := result.Error; err != nil {
@@ -192,6 +268,41 @@ func (r *MovementRepository) Update(ctx context.Context, tx *gorm.DB, id uuid.UU
 	return dbMovement.ToDomain(), nil
 }
 
+func (r *MovementRepository) UpdateInvoiceID(ctx context.Context, tx *gorm.DB, id uuid.UUID, invoiceID uuid.UUID) error {
+	var isLocalTx bool
+	if tx == nil {
+		isLocalTx = true
+		tx = r.db.WithContext(ctx).Begin()
+		defer tx.Rollback()
+	}
+
+	userID := ctx.Value(authentication.UserID).(string)
+	now := time.Now()
+
+	result := tx.Model(&MovementDB{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"invoice_id":  invoiceID,
+			"date_update": now,
+		})
+
+	if err := result.Error; err != nil {
+		return fmt.Errorf("error updating movement invoice: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("error updating movement invoice: %w", ErrMovementNotFound)
+	}
+
+	if isLocalTx {
+		if err := tx.Commit().Error; err != nil {
+			return fmt.Errorf("error committing transaction: %w: %s", ErrDatabaseError, err.Error())
+		}
+	}
+
+	return nil
+}
+
 func (r *MovementRepository) FindByRecurrentIDAndMonth(ctx context.Context, recurrentID uuid.UUID, month time.Time) (*domain.Movement, error) {
 	var dbModel MovementDB
 	tableName := dbModel.TableName()
@@ -199,7 +310,7 @@ func (r *MovementRepository) FindByRecurrentIDAndMonth(ctx context.Context, recu
 	firstDay := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
 	lastDay := firstDay.AddDate(0, 1, -1)
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "wallet_id", domain.HouseholdResourceWallet)
 	err := query.
 		Where(fmt.Sprintf("%s.recurrent_id = ? AND %s.date BETWEEN ? AND ?", tableName, tableName), recurrentID, firstDay, lastDay).
 		First(&dbModel).Error
@@ -223,11 +334,12 @@ func (r *MovementRepository) UpdateStatementLink(ctx context.Context, tx *gorm.D
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := movementWriteScope(ctx)
 	now := time.Now()
 
 	result := tx.Model(&MovementDB{}).
-		Where("id = ? AND user_id = ?", id, userID).
+		Where("id = ?", id).
+		Where(scope, scopeArgs...).
 		Updates(map[string]interface{}{
 			"description": movement.Description,
 			"amount":      movement.Amount,
@@ -254,6 +366,20 @@ func (r *MovementRepository) UpdateStatementLink(ctx context.Context, tx *gorm.D
 	return movement, nil
 }
 
+// movementWriteScope lets the owners and editors of a household change the
+// movements of the wallets shared with it.
+func movementWriteScope(ctx context.Context) (string, []interface{}) {
+	return userScope(ctx, "user_id", "wallet_id", domain.HouseholdResourceWallet, true)
+}
+
+// invoiceMovementScope lets the households a credit card is shared with reach
+// the movements of its invoices.
+func invoiceMovementScope(ctx context.Context, write bool) (string, []interface{}) {
+	return userScope(ctx, "movements.user_id",
+		"(SELECT invoices.credit_card_id FROM invoices WHERE invoices.id = movements.invoice_id)",
+		domain.HouseholdResourceCreditCard, write)
+}
+
 func (r *MovementRepository) appendPreloads(query *gorm.DB) *gorm.DB {
 	return query.Preload("Category").Preload("SubCategory").Preload("Wallet").Preload("Invoice")
 }
@@ -262,7 +388,8 @@ func (r *MovementRepository) FindByInvoiceID(ctx context.Context, invoiceID uuid
 	var dbModel MovementDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	scope, scopeArgs := invoiceMovementScope(ctx, false)
+	query := r.db.WithContext(ctx).Table(tableName).Where(scope, scopeArgs...)
 	query = r.appendPreloads(query)
 
 	var dbMovements []MovementDB
@@ -288,10 +415,11 @@ func (r *MovementRepository) Delete(ctx context.Context, tx *gorm.DB, id uuid.UU
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := movementWriteScope(ctx)
 
 	result := tx.WithContext(ctx).
-		Where("id = ? AND user_id = ?", id, userID).
+		Where("id = ?", id).
+		Where(scope, scopeArgs...).
 		Delete(&MovementDB{})
 
 	if err := result.Error; err != nil {
@@ -319,10 +447,11 @@ func (r *MovementRepository) DeleteByInvoiceID(ctx context.Context, tx *gorm.DB,
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := invoiceMovementScope(ctx, true)
 
 	result := tx.WithContext(ctx).
-		Where("invoice_id = ? AND type_payment = ? AND user_id = ?", invoiceID, domain.TypePaymentInvoicePayment, userID).
+		Where("invoice_id = ? AND type_payment = ?", invoiceID, domain.TypePaymentInvoicePayment).
+		Where(scope, scopeArgs...).
 		Delete(&MovementDB{})
 
 	if err := result.Error; err != nil {
@@ -346,14 +475,15 @@ func (r *MovementRepository) PayByInvoiceID(ctx context.Context, tx *gorm.DB, in
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := invoiceMovementScope(ctx, true)
 	now := time.Now()
 
 	result := tx.Model(&MovementDB{}).
-		Where("invoice_id = ? AND user_id = ? AND type_payment NOT IN ?", invoiceID, userID, []domain.TypePayment{
+		Where("invoice_id = ? AND type_payment NOT IN ?", invoiceID, []domain.TypePayment{
 			domain.TypePaymentInvoicePayment,
 			domain.TypePaymentInvoiceRemainder,
 		}).
+		Where(scope, scopeArgs...).
 		Updates(map[string]interface{}{
 			"is_paid":     true,
 			"date_update": now,
@@ -376,7 +506,8 @@ func (r *MovementRepository) FindInvoicePaymentByInvoiceID(ctx context.Context,
 	var dbModel MovementDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	scope, scopeArgs := invoiceMovementScope(ctx, false)
+	query := r.db.WithContext(ctx).Table(tableName).Where(scope, scopeArgs...)
 	query = r.appendPreloads(query)
 
 	if err := query.Where(fmt.Sprintf("%s.invoice_id = ? AND %s.type_payment = ?", tableName, tableName), invoiceID, domain.TypePaymentInvoicePayment).
@@ -398,14 +529,15 @@ func (r *MovementRepository) RevertPayByInvoiceID(ctx context.Context, tx *gorm.
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := invoiceMovementScope(ctx, true)
 	now := time.Now()
 
 	result := tx.Model(&MovementDB{}).
-		Where("invoice_id = ? AND user_id = ? AND type_payment NOT IN ?", invoiceID, userID, []domain.TypePayment{
+		Where("invoice_id = ? AND type_payment NOT IN ?", invoiceID, []domain.TypePayment{
 			domain.TypePaymentInvoicePayment,
 			domain.TypePaymentInvoiceRemainder,
 		}).
+		Where(scope, scopeArgs...).
 		Updates(map[string]interface{}{
 			"is_paid":     false,
 			"date_update": now,
@@ -444,12 +576,45 @@ func (r *MovementRepository) FindAllByUserID(ctx context.Context) ([]domain.Move
 	return movements, nil
 }
 
+// FindStandaloneExpensesSince returns the expenses of the user from the given date
+// that are not yet tied to a recurrence, an installment plan or a transfer, oldest
+// first. They are the candidates for recurring charge detection.
+func (r *MovementRepository) FindStandaloneExpensesSince(ctx context.Context, from time.Time) (domain.MovementList, error) {
+	var dbModel MovementDB
+	tableName := dbModel.TableName()
+
+	query := BuildBaseQuery(ctx, r.db, tableName)
+	query = r.appendPreloads(query)
+
+	var dbMovements []MovementDB
+	err := query.Where(fmt.Sprintf("%s.date >= ?", tableName), from).
+		Where(fmt.Sprintf("%s.amount < 0", tableName)).
+		Where(fmt.Sprintf("%s.recurrent_id IS NULL AND %s.installment_group_id IS NULL AND %s.pair_id IS NULL", tableName, tableName, tableName)).
+		Where(fmt.Sprintf("%s.type_payment NOT IN ?", tableName), []domain.TypePayment{
+			domain.TypePaymentInvoicePayment,
+			domain.TypePaymentInvoiceRemainder,
+			domain.TypePaymentInternalTransfer,
+		}).
+		Order(fmt.Sprintf("%s.date ASC", tableName)).
+		Find(&dbMovements).Error
+	if err != nil {
+		return domain.MovementList{}, fmt.Errorf("error finding standalone expenses: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	movements := make(domain.MovementList, len(dbMovements))
+	for i, dbMovement := range dbMovements {
+		movements[i] = dbMovement.ToDomain()
+	}
+
+	return movements, nil
+}
+
 func (r *MovementRepository) FindAllByRecurrentID(ctx context.Context, recurrentID uuid.UUID) (domain.MovementList, error) {
 	var dbModels []MovementDB
 	var dbModel MovementDB
 	tableName := dbModel.TableName()
 
-	query := BuildBaseQuery(ctx, r.db, tableName)
+	query := BuildSharedQuery(ctx, r.db, tableName, "wallet_id", domain.HouseholdResourceWallet)
 	query = r.appendPreloads(query)
 
 	err := query.
@@ -466,6 +631,28 @@ func (r *MovementRepository) FindAllByRecurrentID(ctx context.Context, recurrent
 	return result, nil
 }
 
+func (r *MovementRepository) FindByPairID(ctx context.Context, pairID uuid.UUID) (domain.MovementList, error) {
+	var dbModels []MovementDB
+	var dbModel MovementDB
+	tableName := dbModel.TableName()
+
+	query := BuildSharedQuery(ctx, r.db, tableName, "wallet_id", domain.HouseholdResourceWallet)
+	query = r.appendPreloads(query)
+
+	err := query.
+		Where(fmt.Sprintf("%s.pair_id = ?", tableName), pairID).
+		Find(&dbModels).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding movements by pair: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	result := make(domain.MovementList, len(dbModels))
+	for i, m := range dbModels {
+		result[i] = m.ToDomain()
+	}
+	return result, nil
+}
+
 func (r *MovementRepository) DeleteAllByRecurrentID(ctx context.Context, tx *gorm.DB, recurrentID uuid.UUID) error {
 	db := r.db
 	if tx != nil {
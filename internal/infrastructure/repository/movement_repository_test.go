@@ -953,3 +953,66 @@ func TestMovementRepository_FindByInstallmentGroupFromNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestMovementRepository_FindStandaloneExpensesSince(t *testing.T) {
+	from := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		prepareDB      func(ctx context.Context) *MovementRepository
+		expectedErr    error
+		expectedDescrs []string
+	}{
+		"should return only standalone expenses in date order": {
+			prepareDB: func(ctx context.Context) *MovementRepository {
+				db := setupTestDB()
+				repo := NewMovementRepository(db)
+				groupID := uuid.New()
+
+				movements := []domain.Movement{
+					fixture.MovementMock(fixture.WithMovementDescription("Spotify março"), fixture.WithMovementDate(from.AddDate(0, 2, 0))),
+					fixture.MovementMock(fixture.WithMovementDescription("Spotify fevereiro"), fixture.WithMovementDate(from.AddDate(0, 1, 0))),
+					fixture.MovementMock(fixture.WithMovementDescription("Antes da janela"), fixture.WithMovementDate(from.AddDate(0, 0, -1))),
+					fixture.MovementMock(fixture.WithMovementDescription("Salário"), fixture.WithMovementDate(from), fixture.WithMovementAmount(5000)),
+					fixture.MovementMock(fixture.WithMovementDescription("Aluguel"), fixture.WithMovementDate(from), fixture.WithMovementRecurrentID()),
+					fixture.MovementMock(fixture.WithMovementDescription("Parcela 1/3"), fixture.WithMovementDate(from), fixture.WithMovementInstallmentGroupID(&groupID)),
+					fixture.MovementMock(fixture.WithMovementDescription("Pagamento fatura"), fixture.WithMovementDate(from), fixture.WithMovementTypePayment(string(domain.TypePaymentInvoicePayment))),
+					fixture.MovementMock(fixture.WithMovementDescription("Outro usuário"), fixture.WithMovementDate(from), fixture.WithMovementUserID("other-user")),
+				}
+				for _, movement := range movements {
+					movement.ID = &[]uuid.UUID{uuid.New()}[0]
+					dbMovement := FromMovementDomain(movement)
+					db.WithContext(ctx).Create(&dbMovement)
+				}
+
+				return repo
+			},
+			expectedDescrs: []string{"Spotify fevereiro", "Spotify março"},
+		},
+		"should return error when database fails": {
+			prepareDB: func(ctx context.Context) *MovementRepository {
+				db := setupTestDB()
+				_ = db.Callback().Query().Before("gorm:query").Register("force_error", func(db *gorm.DB) {
+					_ = db.AddError(assert.AnError)
+				})
+				return NewMovementRepository(db)
+			},
+			expectedErr: fmt.Errorf("error finding standalone expenses: %w: %s", ErrDatabaseError, assert.AnError.Error()),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := createTestContext()
+			repo := tc.prepareDB(ctx)
+
+			results, err := repo.FindStandaloneExpensesSince(ctx, from)
+
+			assert.Equal(t, tc.expectedErr, err)
+			var descriptions []string
+			for _, movement := range results {
+				descriptions = append(descriptions, movement.Description)
+			}
+			assert.Equal(t, tc.expectedDescrs, descriptions)
+		})
+	}
+}
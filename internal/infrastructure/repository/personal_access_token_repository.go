@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{db: db}
+}
+
+func (r *PersonalAccessTokenRepository) Create(ctx context.Context, token domain.PersonalAccessToken) (domain.PersonalAccessToken, error) {
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	row := PersonalAccessTokenDB{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		Name:      token.Name,
+		TokenHash: token.TokenHash,
+		Prefix:    token.Prefix,
+		Scopes:    strings.Join(token.Scopes, ","),
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return domain.PersonalAccessToken{}, fmt.Errorf("error creating access token: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+func (r *PersonalAccessTokenRepository) ListByUser(ctx context.Context, userID string) ([]domain.PersonalAccessToken, error) {
+	var rows []PersonalAccessTokenDB
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at desc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing access tokens: %w: %s", ErrDatabaseError, err.Error())
+	}
+	out := make([]domain.PersonalAccessToken, len(rows))
+	for i, row := range rows {
+		out[i] = row.ToDomain()
+	}
+	return out, nil
+}
+
+// CountActive counts the tokens of the user that are neither revoked nor
+// expired at now.
+func (r *PersonalAccessTokenRepository) CountActive(ctx context.Context, userID string, now time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&PersonalAccessTokenDB{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("error counting access tokens: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return int(count), nil
+}
+
+func (r *PersonalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (domain.PersonalAccessToken, error) {
+	var row PersonalAccessTokenDB
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.PersonalAccessToken{}, domain.ErrAccessTokenNotFound
+		}
+		return domain.PersonalAccessToken{}, fmt.Errorf("error finding access token: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+// Revoke revokes a token of the user. Revoking it again keeps the first
+// revocation time.
+func (r *PersonalAccessTokenRepository) Revoke(ctx context.Context, userID string, id uuid.UUID, revokedAt time.Time) error {
+	var row PersonalAccessTokenDB
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrAccessTokenNotFound
+		}
+		return fmt.Errorf("error finding access token: %w: %s", ErrDatabaseError, err.Error())
+	}
+	if row.RevokedAt != nil {
+		return nil
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&PersonalAccessTokenDB{}).
+		Where("id = ?", id).
+		Update("revoked_at", revokedAt).Error
+	if err != nil {
+		return fmt.Errorf("error revoking access token: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+func (r *PersonalAccessTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&PersonalAccessTokenDB{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+	if err != nil {
+		return fmt.Errorf("error updating access token usage: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
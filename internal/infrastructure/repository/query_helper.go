@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 
+	"personal-finance/internal/domain"
 	"personal-finance/internal/plataform/authentication"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+type householdsContextKey struct{}
+
 func BuildBaseQuery(ctx context.Context, query *gorm.DB, tableName string) *gorm.DB {
 	userID := ctx.Value(authentication.UserID).(string)
 
@@ -16,3 +20,52 @@ func BuildBaseQuery(ctx context.Context, query *gorm.DB, tableName string) *gorm
 		Table(tableName).
 		Where(fmt.Sprintf("%s.user_id = ?", tableName), userID)
 }
+
+// BuildSharedQuery scopes like BuildBaseQuery and also reaches the rows shared
+// with the households of the user. sharedColumn is matched against the shares
+// of the resource: the row id for the shared resources themselves, the wallet
+// id for movements.
+func BuildSharedQuery(ctx context.Context, query *gorm.DB, tableName, sharedColumn string, resource domain.HouseholdResource) *gorm.DB {
+	condition, args := userScope(ctx, tableName+".user_id", tableName+"."+sharedColumn, resource, false)
+
+	return query.WithContext(ctx).
+		Table(tableName).
+		Where(condition, args...)
+}
+
+// ContextWithHouseholds keeps the household memberships of the user, loaded
+// once per request, for the queries that reach shared resources. Without them
+// those queries only see what the user owns.
+func ContextWithHouseholds(ctx context.Context, memberships []domain.HouseholdMembership) context.Context {
+	return context.WithValue(ctx, householdsContextKey{}, memberships)
+}
+
+// userScope is the condition "owned by the user or shared with one of its
+// households". write only considers the households where the user can edit.
+func userScope(ctx context.Context, ownerColumn, sharedColumn string, resource domain.HouseholdResource, write bool) (string, []interface{}) {
+	userID := ctx.Value(authentication.UserID).(string)
+
+	householdIDs := householdIDsFromContext(ctx, write)
+	if len(householdIDs) == 0 {
+		return fmt.Sprintf("%s = ?", ownerColumn), []interface{}{userID}
+	}
+
+	condition := fmt.Sprintf(
+		"(%s = ? OR %s IN (SELECT resource_id FROM household_shares WHERE resource_type = ? AND household_id IN ?))",
+		ownerColumn, sharedColumn,
+	)
+	return condition, []interface{}{userID, string(resource), householdIDs}
+}
+
+func householdIDsFromContext(ctx context.Context, write bool) []uuid.UUID {
+	memberships, _ := ctx.Value(householdsContextKey{}).([]domain.HouseholdMembership)
+
+	ids := make([]uuid.UUID, 0, len(memberships))
+	for _, membership := range memberships {
+		if write && !membership.Role.CanWrite() {
+			continue
+		}
+		ids = append(ids, membership.HouseholdID)
+	}
+	return ids
+}
@@ -72,6 +72,29 @@ func (r *RecurrentMovementRepository) FindByID(ctx context.Context, id uuid.UUID
 	return dbModel.ToDomain(), nil
 }
 
+func (r *RecurrentMovementRepository) FindByPairID(ctx context.Context, pairID uuid.UUID) ([]domain.RecurrentMovement, error) {
+	var dbRecurrentMovements []RecurrentMovementDB
+	var dbModel RecurrentMovementDB
+	tableName := dbModel.TableName()
+
+	query := BuildBaseQuery(ctx, r.db, tableName)
+	query = r.appendPreloads(query)
+
+	err := query.
+		Where(fmt.Sprintf("%s.pair_id = ?", tableName), pairID).
+		Find(&dbRecurrentMovements).Error
+	if err != nil {
+		return nil, domain.WrapInternalError(err, "error finding recurrent movements by pair")
+	}
+
+	result := make([]domain.RecurrentMovement, len(dbRecurrentMovements))
+	for i, rm := range dbRecurrentMovements {
+		result[i] = rm.ToDomain()
+	}
+
+	return result, nil
+}
+
 func (r *RecurrentMovementRepository) FindByMonth(ctx context.Context, date time.Time) ([]domain.RecurrentMovement, error) {
 	var dbRecurrentMovements []RecurrentMovementDB
 	var dbModel RecurrentMovementDB
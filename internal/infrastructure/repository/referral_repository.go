@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReferralRepository struct {
+	db *gorm.DB
+}
+
+func NewReferralRepository(db *gorm.DB) *ReferralRepository {
+	return &ReferralRepository{db: db}
+}
+
+func (r *ReferralRepository) FindCodeByUser(ctx context.Context, userID string) (domain.ReferralCode, error) {
+	var row ReferralCodeDB
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ReferralCode{}, domain.ErrReferralCodeNotFound
+		}
+		return domain.ReferralCode{}, fmt.Errorf("error finding referral code: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+func (r *ReferralRepository) FindCode(ctx context.Context, code string) (domain.ReferralCode, error) {
+	var row ReferralCodeDB
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ReferralCode{}, domain.ErrReferralCodeNotFound
+		}
+		return domain.ReferralCode{}, fmt.Errorf("error finding referral code: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+// CreateCode stores the code of the user unless the user or the code already
+// has one. Returns false when nothing was inserted.
+func (r *ReferralRepository) CreateCode(ctx context.Context, code domain.ReferralCode) (bool, error) {
+	row := ReferralCodeDB{
+		UserID:    code.UserID,
+		Code:      code.Code,
+		CreatedAt: code.CreatedAt,
+	}
+	if row.CreatedAt.IsZero() {
+		row.CreatedAt = time.Now()
+	}
+
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&row)
+	if res.Error != nil {
+		return false, fmt.Errorf("error creating referral code: %w: %s", ErrDatabaseError, res.Error.Error())
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// Create records the referral of a new user. A user is referred at most once;
+// later attempts are ignored.
+func (r *ReferralRepository) Create(ctx context.Context, referral domain.Referral) error {
+	if referral.ID == uuid.Nil {
+		referral.ID = uuid.New()
+	}
+	if referral.CreatedAt.IsZero() {
+		referral.CreatedAt = time.Now()
+	}
+	if referral.Status == "" {
+		referral.Status = domain.ReferralPending
+	}
+
+	row := ReferralDB{
+		ID:          referral.ID,
+		ReferrerID:  referral.ReferrerID,
+		RefereeID:   referral.RefereeID,
+		Code:        referral.Code,
+		Status:      string(referral.Status),
+		CreatedAt:   referral.CreatedAt,
+		QualifiedAt: referral.QualifiedAt,
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&row).Error
+	if err != nil {
+		return fmt.Errorf("error creating referral: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+func (r *ReferralRepository) FindByReferee(ctx context.Context, refereeID string) (domain.Referral, error) {
+	var row ReferralDB
+	err := r.db.WithContext(ctx).Where("referee_id = ?", refereeID).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Referral{}, domain.ErrReferralNotFound
+		}
+		return domain.Referral{}, fmt.Errorf("error finding referral: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+func (r *ReferralRepository) ListByReferrer(ctx context.Context, referrerID string) ([]domain.Referral, error) {
+	var rows []ReferralDB
+	err := r.db.WithContext(ctx).
+		Where("referrer_id = ?", referrerID).
+		Order("created_at desc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing referrals: %w: %s", ErrDatabaseError, err.Error())
+	}
+	out := make([]domain.Referral, len(rows))
+	for i, row := range rows {
+		out[i] = row.ToDomain()
+	}
+	return out, nil
+}
+
+func (r *ReferralRepository) Reject(ctx context.Context, referralID uuid.UUID, reason string) error {
+	err := r.db.WithContext(ctx).
+		Model(&ReferralDB{}).
+		Where("id = ? AND status = ?", referralID, string(domain.ReferralPending)).
+		Updates(map[string]interface{}{
+			"status":        string(domain.ReferralRejected),
+			"reject_reason": reason,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("error rejecting referral: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+// Qualify marks a pending referral as qualified and creates its rewards in one
+// transaction. Returns false when the referral was not pending anymore, so a
+// replayed webhook does not reward twice.
+func (r *ReferralRepository) Qualify(ctx context.Context, referralID uuid.UUID, qualifiedAt time.Time, rewards []domain.ReferralReward) (bool, error) {
+	qualified := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&ReferralDB{}).
+			Where("id = ? AND status = ?", referralID, string(domain.ReferralPending)).
+			Updates(map[string]interface{}{
+				"status":       string(domain.ReferralQualified),
+				"qualified_at": qualifiedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		rows := make([]ReferralRewardDB, len(rewards))
+		for i, reward := range rewards {
+			if reward.ID == uuid.Nil {
+				reward.ID = uuid.New()
+			}
+			rows[i] = FromReferralRewardDomain(reward)
+		}
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		qualified = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("error qualifying referral: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return qualified, nil
+}
+
+func (r *ReferralRepository) ListRewardsByUser(ctx context.Context, userID string) ([]domain.ReferralReward, error) {
+	return r.listRewards(ctx, "user_id = ?", userID)
+}
+
+// ListPendingRewards lists the rewards not applied yet, including the ones a
+// previous run claimed without finishing.
+func (r *ReferralRepository) ListPendingRewards(ctx context.Context) ([]domain.ReferralReward, error) {
+	return r.listRewards(ctx, "status IN ?", []string{string(domain.ReferralRewardPending), string(domain.ReferralRewardApplying)})
+}
+
+func (r *ReferralRepository) listRewards(ctx context.Context, where string, args ...interface{}) ([]domain.ReferralReward, error) {
+	var rows []ReferralRewardDB
+	err := r.db.WithContext(ctx).Where(where, args...).Order("created_at asc").Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing referral rewards: %w: %s", ErrDatabaseError, err.Error())
+	}
+	out := make([]domain.ReferralReward, len(rows))
+	for i, row := range rows {
+		out[i] = row.ToDomain()
+	}
+	return out, nil
+}
+
+// ClaimReward fixes the plan expiry of a pending reward before it is granted.
+// It returns false when another run claimed the reward first.
+func (r *ReferralRepository) ClaimReward(ctx context.Context, rewardID uuid.UUID, plusUntil time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&ReferralRewardDB{}).
+		Where("id = ? AND status = ?", rewardID, string(domain.ReferralRewardPending)).
+		Updates(map[string]interface{}{
+			"status":     string(domain.ReferralRewardApplying),
+			"plus_until": plusUntil,
+		})
+	if res.Error != nil {
+		return false, fmt.Errorf("error claiming referral reward: %w: %s", ErrDatabaseError, res.Error.Error())
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *ReferralRepository) MarkRewardApplied(ctx context.Context, rewardID uuid.UUID, appliedAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&ReferralRewardDB{}).
+		Where("id = ?", rewardID).
+		Updates(map[string]interface{}{
+			"status":     string(domain.ReferralRewardApplied),
+			"applied_at": appliedAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("error marking referral reward applied: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
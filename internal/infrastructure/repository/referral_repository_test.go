@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupReferralTestDB(t *testing.T) *ReferralRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&ReferralCodeDB{}, &ReferralDB{}, &ReferralRewardDB{}))
+	return NewReferralRepository(db)
+}
+
+func TestReferralRepository_CreateCode(t *testing.T) {
+	ctx := context.Background()
+	repo := setupReferralTestDB(t)
+
+	created, err := repo.CreateCode(ctx, domain.ReferralCode{UserID: "user-1", Code: "AAAA2222"})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = repo.CreateCode(ctx, domain.ReferralCode{UserID: "user-2", Code: "AAAA2222"})
+	require.NoError(t, err)
+	assert.False(t, created, "a code belongs to one user")
+
+	created, err = repo.CreateCode(ctx, domain.ReferralCode{UserID: "user-1", Code: "BBBB3333"})
+	require.NoError(t, err)
+	assert.False(t, created, "a user has one code")
+
+	code, err := repo.FindCode(ctx, "AAAA2222")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", code.UserID)
+
+	_, err = repo.FindCodeByUser(ctx, "user-2")
+	assert.ErrorIs(t, err, domain.ErrReferralCodeNotFound)
+}
+
+func TestReferralRepository_Qualify(t *testing.T) {
+	ctx := context.Background()
+	repo := setupReferralTestDB(t)
+
+	require.NoError(t, repo.Create(ctx, domain.Referral{ReferrerID: "referrer", RefereeID: "referee", Code: "AAAA2222"}))
+	require.NoError(t, repo.Create(ctx, domain.Referral{ReferrerID: "other", RefereeID: "referee", Code: "CCCC4444"}))
+
+	referral, err := repo.FindByReferee(ctx, "referee")
+	require.NoError(t, err)
+	assert.Equal(t, "referrer", referral.ReferrerID, "a user is referred once")
+
+	now := time.Now()
+	rewards := []domain.ReferralReward{
+		{ReferralID: referral.ID, UserID: "referrer", Role: domain.ReferralRoleReferrer, Months: 1, Status: domain.ReferralRewardPending, CreatedAt: now},
+		{ReferralID: referral.ID, UserID: "referee", Role: domain.ReferralRoleReferee, Months: 1, Status: domain.ReferralRewardPending, CreatedAt: now},
+	}
+
+	qualified, err := repo.Qualify(ctx, referral.ID, now, rewards)
+	require.NoError(t, err)
+	assert.True(t, qualified)
+
+	qualified, err = repo.Qualify(ctx, referral.ID, now, rewards)
+	require.NoError(t, err)
+	assert.False(t, qualified, "a qualified referral is not rewarded again")
+
+	require.NoError(t, repo.Reject(ctx, referral.ID, domain.ReferralRejectSameDevice))
+	referral, err = repo.FindByReferee(ctx, "referee")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReferralQualified, referral.Status, "only pending referrals are rejected")
+
+	pending, err := repo.ListPendingRewards(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	plusUntil := now.AddDate(0, 1, 0)
+	claimed, err := repo.ClaimReward(ctx, pending[0].ID, plusUntil)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = repo.ClaimReward(ctx, pending[0].ID, plusUntil.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.False(t, claimed, "a reward is claimed once")
+
+	pending, err = repo.ListPendingRewards(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2, "a claimed reward is retried until applied")
+	assert.Equal(t, domain.ReferralRewardApplying, pending[0].Status)
+	assert.WithinDuration(t, plusUntil, *pending[0].PlusUntil, time.Second)
+
+	require.NoError(t, repo.MarkRewardApplied(ctx, pending[0].ID, now))
+
+	pending, err = repo.ListPendingRewards(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	referrals, err := repo.ListByReferrer(ctx, "referrer")
+	require.NoError(t, err)
+	assert.Len(t, referrals, 1)
+}
@@ -25,12 +25,13 @@ func NewSubCategoryRepository(db *gorm.DB) *SubCategoryRepository {
 }
 
 func (r *SubCategoryRepository) IsSubCategoryBelongsToCategory(ctx context.Context, subCategoryID uuid.UUID, categoryID uuid.UUID) (bool, error) {
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := userScope(ctx, "user_id", "category_id", domain.HouseholdResourceCategory, false)
 
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&SubCategoryDB{}).
-		Where("id = ? AND category_id = ? AND user_id IN (?,?)", subCategoryID, categoryID, userID, repository.DefaultIDCategory).
+		Where("id = ? AND category_id = ?", subCategoryID, categoryID).
+		Where(fmt.Sprintf("(%s OR user_id = ?)", scope), append(scopeArgs, repository.DefaultIDCategory)...).
 		Count(&count).
 		Error
 	if err != nil {
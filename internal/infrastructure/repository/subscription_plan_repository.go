@@ -22,6 +22,11 @@ func NewSubscriptionPlanRepository(db *gorm.DB) *SubscriptionPlanRepository {
 }
 
 func (r *SubscriptionPlanRepository) Create(ctx context.Context, plan domain.SubscriptionPlan) error {
+	entitlements, err := entitlementsFromDomain(plan.Entitlements)
+	if err != nil {
+		return fmt.Errorf("error encoding plan entitlements: %w", err)
+	}
+
 	row := SubscriptionPlanDB{
 		ID:            plan.ID,
 		Name:          plan.Name,
@@ -30,10 +35,12 @@ func (r *SubscriptionPlanRepository) Create(ctx context.Context, plan domain.Sub
 		Frequency:     plan.Frequency,
 		FrequencyType: plan.FrequencyType,
 		IsActive:      plan.IsActive,
+		TrialDays:     plan.TrialDays,
+		Entitlements:  entitlements,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
-	err := r.db.WithContext(ctx).Create(&row).Error
+	err = r.db.WithContext(ctx).Create(&row).Error
 	if err != nil {
 		return fmt.Errorf("error creating plan: %w: %s", ErrDatabaseError, err.Error())
 	}
@@ -65,6 +72,58 @@ func (r *SubscriptionPlanRepository) FindActiveByID(ctx context.Context, id stri
 	return row.ToDomain(), nil
 }
 
+// FindAll lists every plan, sold or not.
+func (r *SubscriptionPlanRepository) FindAll(ctx context.Context) ([]domain.SubscriptionPlan, error) {
+	var rows []SubscriptionPlanDB
+	err := r.db.WithContext(ctx).Order("created_at asc").Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing plans: %w: %s", ErrDatabaseError, err.Error())
+	}
+	plans := make([]domain.SubscriptionPlan, len(rows))
+	for i, row := range rows {
+		plans[i] = row.ToDomain()
+	}
+	return plans, nil
+}
+
+// FindByID finds a plan whether it is sold or not.
+func (r *SubscriptionPlanRepository) FindByID(ctx context.Context, id string) (domain.SubscriptionPlan, error) {
+	var row SubscriptionPlanDB
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.SubscriptionPlan{}, ErrSubscriptionPlanNotFound
+		}
+		return domain.SubscriptionPlan{}, fmt.Errorf("error finding plan %s: %w: %s", id, ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+// UpdateEntitlements replaces the entitlements of the plan; nil puts it back on
+// the defaults of its tier.
+func (r *SubscriptionPlanRepository) UpdateEntitlements(ctx context.Context, id string, ent *domain.Entitlements) (domain.SubscriptionPlan, error) {
+	entitlements, err := entitlementsFromDomain(ent)
+	if err != nil {
+		return domain.SubscriptionPlan{}, fmt.Errorf("error encoding plan entitlements: %w", err)
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&SubscriptionPlanDB{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"entitlements": entitlements,
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return domain.SubscriptionPlan{}, fmt.Errorf("error updating plan entitlements: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return domain.SubscriptionPlan{}, ErrSubscriptionPlanNotFound
+	}
+
+	return r.FindByID(ctx, id)
+}
+
 func (r *SubscriptionPlanRepository) FindIDByStoreProduct(ctx context.Context, store, productID string) (string, error) {
 	if productID == "" {
 		return "", nil
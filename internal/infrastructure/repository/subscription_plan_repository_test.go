@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSubscriptionPlanTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&SubscriptionPlanDB{}))
+	return db
+}
+
+func TestSubscriptionPlanRepository_Entitlements(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should keep a plan without entitlements on the defaults", func(t *testing.T) {
+		repo := NewSubscriptionPlanRepository(setupSubscriptionPlanTestDB(t))
+		require.NoError(t, repo.Create(ctx, domain.SubscriptionPlan{ID: "plus_monthly", Name: "Plus", Price: 9.9}))
+
+		plan, err := repo.FindByID(ctx, "plus_monthly")
+
+		require.NoError(t, err)
+		assert.Nil(t, plan.Entitlements)
+	})
+
+	t.Run("should store, replace and clear the entitlements of a plan not sold", func(t *testing.T) {
+		repo := NewSubscriptionPlanRepository(setupSubscriptionPlanTestDB(t))
+		require.NoError(t, repo.Create(ctx, domain.SubscriptionPlan{
+			ID:           domain.FreePlanID,
+			Name:         "Free",
+			Entitlements: &domain.Entitlements{Limits: map[domain.LimitName]int{domain.LimitWallets: 2}},
+		}))
+
+		plan, err := repo.FindByID(ctx, domain.FreePlanID)
+		require.NoError(t, err)
+		require.NotNil(t, plan.Entitlements)
+		assert.Equal(t, 2, plan.Entitlements.Limits[domain.LimitWallets])
+
+		plan, err = repo.UpdateEntitlements(ctx, domain.FreePlanID, &domain.Entitlements{
+			Features: map[domain.Feature]bool{domain.FeatureAgentChat: false},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, plan.Entitlements)
+		assert.Empty(t, plan.Entitlements.Limits)
+		assert.False(t, plan.Entitlements.HasFeature(domain.FeatureAgentChat))
+
+		plan, err = repo.UpdateEntitlements(ctx, domain.FreePlanID, nil)
+		require.NoError(t, err)
+		assert.Nil(t, plan.Entitlements)
+	})
+
+	t.Run("should return not found for a missing plan", func(t *testing.T) {
+		repo := NewSubscriptionPlanRepository(setupSubscriptionPlanTestDB(t))
+
+		_, err := repo.FindByID(ctx, "missing")
+		assert.ErrorIs(t, err, ErrSubscriptionPlanNotFound)
+
+		_, err = repo.UpdateEntitlements(ctx, "missing", &domain.Entitlements{})
+		assert.ErrorIs(t, err, ErrSubscriptionPlanNotFound)
+	})
+}
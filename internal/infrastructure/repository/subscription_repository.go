@@ -38,15 +38,20 @@ func (r *SubscriptionRepository) Upsert(ctx context.Context, sub domain.Subscrip
 	err := r.db.WithContext(ctx).
 		Clauses(clause.OnConflict{
 			Columns: []clause.Column{{Name: "source"}, {Name: "external_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{
+			DoUpdates: append(clause.AssignmentColumns([]string{
 				"plan_id",
 				"status",
 				"current_price",
 				"currency",
 				"current_period_end",
 				"cancelled_at",
+				"grace_ends_at",
 				"external_product_id",
 				"updated_at",
+			}), clause.Assignment{
+				// Renewals after a trial no longer carry it; keep when it ended.
+				Column: clause.Column{Name: "trial_ends_at"},
+				Value:  gorm.Expr("COALESCE(excluded.trial_ends_at, subscriptions.trial_ends_at)"),
 			}),
 		}).
 		Create(&row).Error
@@ -94,10 +99,28 @@ func (r *SubscriptionRepository) List(ctx context.Context, filter SubscriptionLi
 	return out, nil
 }
 
+func (r *SubscriptionRepository) FindByUserID(ctx context.Context, userID string) ([]domain.Subscription, error) {
+	var rows []SubscriptionDB
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("started_at desc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding user subscriptions: %w: %s", ErrDatabaseError, err.Error())
+	}
+
+	out := make([]domain.Subscription, len(rows))
+	for i, row := range rows {
+		out[i] = row.ToDomain()
+	}
+	return out, nil
+}
+
 func (r *SubscriptionRepository) FindActiveByUserAndSource(ctx context.Context, userID string, source domain.SubscriptionSource) (domain.Subscription, error) {
 	var row SubscriptionDB
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND source = ? AND status = ?", userID, string(source), string(domain.SubscriptionStatusActive)).
+		Where("user_id = ? AND source = ? AND status IN ?", userID, string(source),
+			[]string{string(domain.SubscriptionStatusActive), string(domain.SubscriptionStatusTrialing)}).
 		Order("started_at desc").
 		First(&row).Error
 	if err != nil {
@@ -77,6 +77,63 @@ func TestSubscriptionRepository_Upsert(t *testing.T) {
 		db.Model(&SubscriptionDB{}).Count(&count)
 		assert.Equal(t, int64(1), count)
 	})
+
+	t.Run("keeps the trial end after the trial converts and clears the grace", func(t *testing.T) {
+		repo := NewSubscriptionRepository(setupSubscriptionTestDB(t))
+		trialEnd := start.Add(7 * 24 * time.Hour)
+		graceEnd := trialEnd.Add(7 * 24 * time.Hour)
+
+		_, err := repo.Upsert(ctx, domain.Subscription{
+			UserID:      "user-1",
+			Source:      domain.SubscriptionSourceApple,
+			ExternalID:  "apple-1",
+			Status:      domain.SubscriptionStatusTrialing,
+			StartedAt:   start,
+			TrialEndsAt: &trialEnd,
+		})
+		assert.NoError(t, err)
+
+		pastDue, err := repo.Upsert(ctx, domain.Subscription{
+			UserID:      "user-1",
+			Source:      domain.SubscriptionSourceApple,
+			ExternalID:  "apple-1",
+			Status:      domain.SubscriptionStatusPastDue,
+			StartedAt:   start,
+			GraceEndsAt: &graceEnd,
+		})
+		assert.NoError(t, err)
+		if assert.NotNil(t, pastDue.TrialEndsAt) && assert.NotNil(t, pastDue.GraceEndsAt) {
+			assert.True(t, trialEnd.Equal(*pastDue.TrialEndsAt))
+			assert.True(t, graceEnd.Equal(*pastDue.GraceEndsAt))
+		}
+
+		renewed, err := repo.Upsert(ctx, domain.Subscription{
+			UserID:     "user-1",
+			Source:     domain.SubscriptionSourceApple,
+			ExternalID: "apple-1",
+			Status:     domain.SubscriptionStatusActive,
+			StartedAt:  start,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, renewed.TrialEndsAt)
+		assert.Nil(t, renewed.GraceEndsAt)
+	})
+}
+
+func TestSubscriptionRepository_FindActiveByUserAndSource(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSubscriptionRepository(setupSubscriptionTestDB(t))
+	now := time.Now()
+
+	_, _ = repo.Upsert(ctx, domain.Subscription{UserID: "u1", Source: domain.SubscriptionSourceStripe, ExternalID: "sub_trial", Status: domain.SubscriptionStatusTrialing, StartedAt: now})
+	_, _ = repo.Upsert(ctx, domain.Subscription{UserID: "u2", Source: domain.SubscriptionSourceStripe, ExternalID: "sub_past_due", Status: domain.SubscriptionStatusPastDue, StartedAt: now})
+
+	trialing, err := repo.FindActiveByUserAndSource(ctx, "u1", domain.SubscriptionSourceStripe)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub_trial", trialing.ExternalID)
+
+	_, err = repo.FindActiveByUserAndSource(ctx, "u2", domain.SubscriptionSourceStripe)
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
 }
 
 func TestSubscriptionRepository_List(t *testing.T) {
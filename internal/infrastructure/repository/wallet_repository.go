@@ -36,6 +36,9 @@ func (r *WalletRepository) Add(ctx context.Context, wallet domain.Wallet) (domai
 	if dbModel.InitialDate.IsZero() {
 		dbModel.InitialDate = now
 	}
+	if dbModel.Type == "" {
+		dbModel.Type = string(domain.WalletTypeChecking)
+	}
 	dbModel.Balance = dbModel.InitialBalance
 
 	if err := r.db.WithContext(ctx).Create(&dbModel).Error; err != nil {
@@ -58,6 +61,9 @@ func (r *WalletRepository) AddConsistent(ctx context.Context, tx *gorm.DB, walle
 	if dbModel.InitialDate.IsZero() {
 		dbModel.InitialDate = now
 	}
+	if dbModel.Type == "" {
+		dbModel.Type = string(domain.WalletTypeChecking)
+	}
 	dbModel.Balance = dbModel.InitialBalance
 
 	db := r.db.WithContext(ctx)
@@ -74,7 +80,7 @@ func (r *WalletRepository) AddConsistent(ctx context.Context, tx *gorm.DB, walle
 
 func (r *WalletRepository) FindAll(ctx context.Context) ([]domain.Wallet, error) {
 	var wallets []WalletDB
-	query := BuildBaseQuery(ctx, r.db, WalletDB{}.TableName())
+	query := BuildSharedQuery(ctx, r.db, WalletDB{}.TableName(), "id", domain.HouseholdResourceWallet)
 
 	if err := query.Order("description").Find(&wallets).Error; err != nil {
 		return nil, domain.WrapInternalError(err, "error finding wallets")
@@ -87,9 +93,29 @@ func (r *WalletRepository) FindAll(ctx context.Context) ([]domain.Wallet, error)
 	return result, nil
 }
 
+func (r *WalletRepository) FindActive(ctx context.Context) ([]domain.Wallet, error) {
+	var wallets []WalletDB
+	tableName := WalletDB{}.TableName()
+	query := BuildSharedQuery(ctx, r.db, tableName, "id", domain.HouseholdResourceWallet)
+
+	err := query.
+		Where(fmt.Sprintf("%s.archived_at IS NULL", tableName)).
+		Order("description").
+		Find(&wallets).Error
+	if err != nil {
+		return nil, domain.WrapInternalError(err, "error finding active wallets")
+	}
+
+	result := make([]domain.Wallet, len(wallets))
+	for i, w := range wallets {
+		result[i] = w.ToDomain()
+	}
+	return result, nil
+}
+
 func (r *WalletRepository) FindByID(ctx context.Context, id *uuid.UUID) (domain.Wallet, error) {
 	var wallet WalletDB
-	query := BuildBaseQuery(ctx, r.db, wallet.TableName())
+	query := BuildSharedQuery(ctx, r.db, wallet.TableName(), "id", domain.HouseholdResourceWallet)
 
 	result := query.First(&wallet, id)
 	if err := result.Error; err != nil {
@@ -111,6 +137,9 @@ func (r *WalletRepository) Update(ctx context.Context, wallet domain.Wallet) (do
 	if wallet.Description != "" {
 		existing.Description = wallet.Description
 	}
+	if wallet.Type != "" {
+		existing.Type = wallet.Type
+	}
 
 	var shouldRecalculate bool
 	if wallet.InitialBalance != 0 && wallet.InitialBalance != existing.InitialBalance {
@@ -157,9 +186,59 @@ func (r *WalletRepository) Delete(ctx context.Context, id *uuid.UUID) error {
 	return nil
 }
 
-func (r *WalletRepository) RecalculateBalance(ctx context.Context, walletID *uuid.UUID) error {
+func (r *WalletRepository) UpdateArchivedAt(ctx context.Context, id *uuid.UUID, archivedAt *time.Time) error {
 	userID := ctx.Value(authentication.UserID).(string)
 
+	result := r.db.WithContext(ctx).Model(&WalletDB{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"archived_at": archivedAt,
+			"date_update": time.Now(),
+		})
+
+	if result.Error != nil {
+		return domain.WrapInternalError(result.Error, "error updating wallet archive status")
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.WrapNotFound(ErrWalletNotFound, "wallet")
+	}
+
+	return nil
+}
+
+// FindPaidEntries retorna data e valor das movimentações pagas da carteira no
+// intervalo [from, until), em ordem cronológica.
+func (r *WalletRepository) FindPaidEntries(ctx context.Context, walletID *uuid.UUID, from, until time.Time) ([]domain.WalletBalanceEntry, error) {
+	scope, scopeArgs := userScope(ctx, "movements.user_id", "movements.wallet_id", domain.HouseholdResourceWallet, false)
+
+	var rows []struct {
+		Date   time.Time
+		Amount float64
+	}
+	err := r.db.WithContext(ctx).
+		Table("movements").
+		Select("date, amount").
+		Where(scope, scopeArgs...).
+		Where("wallet_id = ?", walletID).
+		Where("date >= ? AND date < ?", from, until).
+		Where("is_paid = ?", true).
+		Order("date").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, domain.WrapInternalError(err, "error finding paid wallet entries")
+	}
+
+	result := make([]domain.WalletBalanceEntry, len(rows))
+	for i, row := range rows {
+		result[i] = domain.WalletBalanceEntry{Date: row.Date, Amount: row.Amount}
+	}
+	return result, nil
+}
+
+// RecalculateBalance soma as movimentações do dono da carteira, que também
+// recebe as lançadas por membros de um household com quem ela é compartilhada.
+func (r *WalletRepository) RecalculateBalance(ctx context.Context, walletID *uuid.UUID) error {
 	wallet, err := r.FindByID(ctx, walletID)
 	if err != nil {
 		return err
@@ -168,7 +247,7 @@ func (r *WalletRepository) RecalculateBalance(ctx context.Context, walletID *uui
 	var recalculatedBalance float64
 	err = r.db.WithContext(ctx).
 		Table("movements").
-		Where("movements.user_id = ?", userID).
+		Where("movements.user_id = ?", wallet.UserID).
 		Where("wallet_id = ?", walletID).
 		Where("date BETWEEN ? AND ?", wallet.InitialDate, time.Now()).
 		Where("is_paid = ?", true).
@@ -179,10 +258,12 @@ func (r *WalletRepository) RecalculateBalance(ctx context.Context, walletID *uui
 	}
 
 	newBalance := wallet.InitialBalance + recalculatedBalance
+	scope, scopeArgs := userScope(ctx, "user_id", "id", domain.HouseholdResourceWallet, true)
 	now := time.Now()
 
 	result := r.db.WithContext(ctx).Model(&WalletDB{}).
-		Where("id = ? AND user_id = ?", walletID, userID).
+		Where("id = ? AND user_id = ?", walletID, wallet.UserID).
+		Where(scope, scopeArgs...).
 		Updates(map[string]interface{}{
 			"balance":     newBalance,
 			"date_update": now,
@@ -192,6 +273,10 @@ func (r *WalletRepository) RecalculateBalance(ctx context.Context, walletID *uui
 		return domain.WrapInternalError(result.Error, "error updating wallet balance")
 	}
 
+	if result.RowsAffected == 0 {
+		return domain.WrapNotFound(ErrWalletNotFound, "wallet")
+	}
+
 	return nil
 }
 
@@ -203,11 +288,12 @@ func (r *WalletRepository) UpdateAmount(ctx context.Context, tx *gorm.DB, id *uu
 		defer tx.Rollback()
 	}
 
-	userID := ctx.Value(authentication.UserID).(string)
+	scope, scopeArgs := userScope(ctx, "user_id", "id", domain.HouseholdResourceWallet, true)
 	now := time.Now()
 
 	result := tx.Model(&WalletDB{}).
-		Where("id = ? AND user_id = ?", id, userID).
+		Where("id = ?", id).
+		Where(scope, scopeArgs...).
 		Updates(map[string]interface{}{
 			"balance":     balance,
 			"date_update": now,
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"personal-finance/internal/domain"
 	"personal-finance/internal/domain/fixture"
@@ -232,3 +233,81 @@ func TestWalletRepository_UpdateAmount(t *testing.T) {
 		})
 	}
 }
+
+func TestWalletRepository_FindActive(t *testing.T) {
+	db := setupWalletTestDB()
+	repo := NewWalletRepository(db)
+	ctx := context.WithValue(context.Background(), authentication.UserID, "user-test-id")
+
+	active := FromWalletDomain(fixture.WalletMock(fixture.WithWalletID(uuid.New()), fixture.WithWalletDescription("Conta")))
+	archived := FromWalletDomain(fixture.WalletMock(fixture.WithWalletID(uuid.New()), fixture.WithWalletDescription("Antiga")))
+	db.Create(&active)
+	db.Create(&archived)
+
+	archivedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	err := repo.UpdateArchivedAt(ctx, archived.ID, &archivedAt)
+	assert.NoError(t, err)
+
+	wallets, err := repo.FindActive(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 1)
+	assert.Equal(t, active.ID, wallets[0].ID)
+
+	all, err := repo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	err = repo.UpdateArchivedAt(ctx, archived.ID, nil)
+	assert.NoError(t, err)
+
+	wallets, err = repo.FindActive(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 2)
+}
+
+func TestWalletRepository_UpdateArchivedAt(t *testing.T) {
+	db := setupWalletTestDB()
+	repo := NewWalletRepository(db)
+	ctx := context.WithValue(context.Background(), authentication.UserID, "user-test-id")
+
+	id := uuid.New()
+	err := repo.UpdateArchivedAt(ctx, &id, nil)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestWalletRepository_FindPaidEntries(t *testing.T) {
+	db := setupWalletTestDB()
+	_ = db.AutoMigrate(&MovementDB{})
+	repo := NewWalletRepository(db)
+	ctx := context.WithValue(context.Background(), authentication.UserID, "user-test-id")
+
+	walletID := uuid.New()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	movements := []domain.Movement{
+		fixture.MovementMock(fixture.WithMovementDate(from.AddDate(0, 0, 10)), fixture.AsMovementExpense(40)),
+		fixture.MovementMock(fixture.WithMovementDate(from.AddDate(0, 0, 2)), fixture.AsMovementIncome(100)),
+		fixture.MovementMock(fixture.WithMovementDate(from.AddDate(0, 0, 5)), fixture.WithMovementIsPaid(false)),
+		fixture.MovementMock(fixture.WithMovementDate(until)),
+		fixture.MovementMock(fixture.WithMovementDate(from.AddDate(0, 0, 1)), fixture.WithMovementWalletID(uuid.New())),
+	}
+	for i, m := range movements {
+		if i < 4 {
+			m.WalletID = &walletID
+		}
+		m.ID = nil
+		dbModel := FromMovementDomain(m)
+		id := uuid.New()
+		dbModel.ID = &id
+		db.Create(&dbModel)
+	}
+
+	entries, err := repo.FindPaidEntries(ctx, &walletID, from, until)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, 100.0, entries[0].Amount)
+	assert.Equal(t, -40.0, entries[1].Amount)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// --- Webhook Inbox DB Model ---
+
+type WebhookInboxEventDB struct {
+	ID            uuid.UUID  `gorm:"primaryKey;column:id"`
+	Provider      string     `gorm:"column:provider;uniqueIndex:uq_webhook_inbox_events_provider_event"`
+	EventID       string     `gorm:"column:event_id;uniqueIndex:uq_webhook_inbox_events_provider_event"`
+	EventType     string     `gorm:"column:event_type"`
+	Payload       []byte     `gorm:"column:payload;type:jsonb"`
+	Status        string     `gorm:"column:status"`
+	Attempts      int        `gorm:"column:attempts"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at"`
+	LastError     string     `gorm:"column:last_error"`
+	ReceivedAt    time.Time  `gorm:"column:received_at"`
+	ProcessedAt   *time.Time `gorm:"column:processed_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at"`
+}
+
+func (WebhookInboxEventDB) TableName() string {
+	return "webhook_inbox_events"
+}
+
+func (m WebhookInboxEventDB) ToDomain() domain.WebhookInboxEvent {
+	return domain.WebhookInboxEvent{
+		ID:            m.ID,
+		Provider:      domain.WebhookProvider(m.Provider),
+		EventID:       m.EventID,
+		EventType:     m.EventType,
+		Payload:       m.Payload,
+		Status:        domain.WebhookEventStatus(m.Status),
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		ReceivedAt:    m.ReceivedAt,
+		ProcessedAt:   m.ProcessedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+func FromWebhookInboxEventDomain(e domain.WebhookInboxEvent) WebhookInboxEventDB {
+	return WebhookInboxEventDB{
+		ID:            e.ID,
+		Provider:      string(e.Provider),
+		EventID:       e.EventID,
+		EventType:     e.EventType,
+		Payload:       e.Payload,
+		Status:        string(e.Status),
+		Attempts:      e.Attempts,
+		NextAttemptAt: e.NextAttemptAt,
+		LastError:     e.LastError,
+		ReceivedAt:    e.ReceivedAt,
+		ProcessedAt:   e.ProcessedAt,
+		UpdatedAt:     e.UpdatedAt,
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrWebhookEventNotFound = errors.New("webhook event not found")
+
+type WebhookInboxRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookInboxRepository(db *gorm.DB) *WebhookInboxRepository {
+	return &WebhookInboxRepository{db: db}
+}
+
+// Insert stores the event unless the provider already delivered it. It reports
+// whether the event is new.
+func (r *WebhookInboxRepository) Insert(ctx context.Context, event domain.WebhookInboxEvent) (bool, error) {
+	row := FromWebhookInboxEventDomain(event)
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(&row)
+	if result.Error != nil {
+		return false, fmt.Errorf("error inserting webhook event: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *WebhookInboxRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.WebhookInboxEvent, error) {
+	var row WebhookInboxEventDB
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.WebhookInboxEvent{}, fmt.Errorf("%w: %s", ErrWebhookEventNotFound, id)
+		}
+		return domain.WebhookInboxEvent{}, fmt.Errorf("error finding webhook event: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return row.ToDomain(), nil
+}
+
+// FindDue returns the pending events whose next attempt is due, oldest first.
+func (r *WebhookInboxRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookInboxEvent, error) {
+	var rows []WebhookInboxEventDB
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookEventStatusPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding due webhook events: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return toWebhookInboxEvents(rows), nil
+}
+
+func (r *WebhookInboxRepository) ListByStatus(ctx context.Context, status domain.WebhookEventStatus, limit int) ([]domain.WebhookInboxEvent, error) {
+	var rows []WebhookInboxEventDB
+	err := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhook events: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return toWebhookInboxEvents(rows), nil
+}
+
+// Claim takes the event for one attempt: it counts the attempt and pushes the
+// next attempt to leaseUntil, so a concurrent run skips it and a crash retries
+// it once the lease ends. It fails when another run claimed it first.
+func (r *WebhookInboxRepository) Claim(ctx context.Context, event domain.WebhookInboxEvent, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&WebhookInboxEventDB{}).
+		Where("id = ? AND status = ? AND attempts = ?", event.ID, domain.WebhookEventStatusPending, event.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": leaseUntil,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("error claiming webhook event: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *WebhookInboxRepository) MarkProcessed(ctx context.Context, id uuid.UUID, processedAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&WebhookInboxEventDB{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       domain.WebhookEventStatusProcessed,
+			"last_error":   "",
+			"processed_at": processedAt,
+			"updated_at":   processedAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("error marking webhook event processed: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+// MarkFailed records the error and schedules the next attempt, or moves the
+// event to the dead letters when status is dead.
+func (r *WebhookInboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, status domain.WebhookEventStatus, nextAttemptAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&WebhookInboxEventDB{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+			"updated_at":      time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("error marking webhook event failed: %w: %s", ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+// Requeue makes the event pending and due at now with a fresh attempt count,
+// whatever its status.
+func (r *WebhookInboxRepository) Requeue(ctx context.Context, id uuid.UUID, now time.Time) (domain.WebhookInboxEvent, error) {
+	result := r.db.WithContext(ctx).
+		Model(&WebhookInboxEventDB{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          domain.WebhookEventStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		return domain.WebhookInboxEvent{}, fmt.Errorf("error requeueing webhook event: %w: %s", ErrDatabaseError, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return domain.WebhookInboxEvent{}, fmt.Errorf("%w: %s", ErrWebhookEventNotFound, id)
+	}
+	return r.FindByID(ctx, id)
+}
+
+func toWebhookInboxEvents(rows []WebhookInboxEventDB) []domain.WebhookInboxEvent {
+	events := make([]domain.WebhookInboxEvent, len(rows))
+	for i, row := range rows {
+		events[i] = row.ToDomain()
+	}
+	return events
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupWebhookInboxTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&WebhookInboxEventDB{}))
+	return db
+}
+
+func TestWebhookInboxRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should store each provider event once", func(t *testing.T) {
+		repo := NewWebhookInboxRepository(setupWebhookInboxTestDB(t))
+		event := domain.NewWebhookInboxEvent(domain.WebhookProviderStripe, "evt_1", "customer.subscription.updated", []byte(`{"id":"evt_1"}`), now)
+
+		created, err := repo.Insert(ctx, event)
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		redelivered := domain.NewWebhookInboxEvent(domain.WebhookProviderStripe, "evt_1", "customer.subscription.updated", []byte(`{"id":"evt_1"}`), now)
+		created, err = repo.Insert(ctx, redelivered)
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		otherProvider := domain.NewWebhookInboxEvent(domain.WebhookProviderRevenueCat, "evt_1", "RENEWAL", []byte(`{}`), now)
+		created, err = repo.Insert(ctx, otherProvider)
+		require.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("should let only one run claim a due event", func(t *testing.T) {
+		repo := NewWebhookInboxRepository(setupWebhookInboxTestDB(t))
+		event := domain.NewWebhookInboxEvent(domain.WebhookProviderStripe, "evt_1", "customer.subscription.updated", []byte(`{}`), now)
+		_, err := repo.Insert(ctx, event)
+		require.NoError(t, err)
+
+		due, err := repo.FindDue(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+
+		lease := now.Add(domain.WebhookProcessingLease)
+		claimed, err := repo.Claim(ctx, due[0], lease)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		claimed, err = repo.Claim(ctx, due[0], lease)
+		require.NoError(t, err)
+		assert.False(t, claimed)
+
+		due, err = repo.FindDue(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, due, "leased until the attempt ends")
+	})
+
+	t.Run("should list dead letters and requeue them", func(t *testing.T) {
+		repo := NewWebhookInboxRepository(setupWebhookInboxTestDB(t))
+		event := domain.NewWebhookInboxEvent(domain.WebhookProviderMercadoPago, "123", "preapproval", []byte(`{}`), now)
+		_, err := repo.Insert(ctx, event)
+		require.NoError(t, err)
+		require.NoError(t, repo.MarkFailed(ctx, event.ID, "no external_reference", domain.WebhookEventStatusDead, now))
+
+		dead, err := repo.ListByStatus(ctx, domain.WebhookEventStatusDead, 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, "no external_reference", dead[0].LastError)
+
+		requeued, err := repo.Requeue(ctx, event.ID, now)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WebhookEventStatusPending, requeued.Status)
+		assert.Equal(t, 0, requeued.Attempts)
+
+		_, err = repo.FindByID(ctx, requeued.ID)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,105 @@
+package authentication
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"personal-finance/pkg/log"
+)
+
+// AccessTokenPrefix marks personal access tokens. They are sent in the
+// user_token header in place of a Firebase ID token.
+const AccessTokenPrefix = "pft_"
+
+// AccessTokensPath is where tokens are managed. Only Firebase sessions reach
+// it, so a leaked token cannot create more tokens.
+const AccessTokensPath = "/me/tokens"
+
+type Scope string
+
+const (
+	// ScopeRead allows every GET request but the data export.
+	ScopeRead Scope = "read"
+	// ScopeMovementsWrite allows creating, changing and importing movements.
+	ScopeMovementsWrite Scope = "movements:write"
+	// ScopeExport allows the full data export.
+	ScopeExport Scope = "export"
+)
+
+func (s Scope) IsValid() bool {
+	switch s {
+	case ScopeRead, ScopeMovementsWrite, ScopeExport:
+		return true
+	}
+	return false
+}
+
+// movementWritePaths are the route groups a movements:write token may change.
+var movementWritePaths = []string{"/v2/movements", "/movements", "/v2/statements"}
+
+type AccessTokenVerifier interface {
+	VerifyAccessToken(ctx context.Context, token string) (AuthContext, error)
+}
+
+// AuthenticateWithAccessTokens accepts personal access tokens and hands any
+// other token to next, the Firebase authentication.
+func AuthenticateWithAccessTokens(verifier AccessTokenVerifier, next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userToken := c.GetHeader(UserToken)
+		if !strings.HasPrefix(userToken, AccessTokenPrefix) {
+			next(c)
+			return
+		}
+
+		ctx := c.Request.Context()
+		authCtx, err := verifier.VerifyAccessToken(ctx, userToken)
+		if err != nil {
+			log.WarnContext(ctx, "error verifying access token", log.Err(err))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
+			c.Abort()
+			return
+		}
+
+		if !accessTokenAllows(authCtx.Scopes, c.Request.Method, c.FullPath()) {
+			log.WarnContext(ctx, "forbidden: access token scope",
+				log.String("user_id", authCtx.UserID),
+				log.String("path", c.FullPath()),
+			)
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden: access token scope does not allow this request"})
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(contextWithUser(ctx, authCtx))
+	}
+}
+
+func accessTokenAllows(scopes []Scope, method, path string) bool {
+	has := func(scope Scope) bool {
+		for _, s := range scopes {
+			if s == scope {
+				return true
+			}
+		}
+		return false
+	}
+
+	if strings.HasPrefix(path, AccessTokensPath) {
+		return false
+	}
+	if path == "/me/export" {
+		return has(ScopeExport)
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return has(ScopeRead)
+	}
+	for _, prefix := range movementWritePaths {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return has(ScopeMovementsWrite)
+		}
+	}
+	return false
+}
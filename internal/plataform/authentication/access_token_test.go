@@ -0,0 +1,127 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"personal-finance/pkg/log"
+)
+
+type fakeAccessTokenVerifier struct {
+	scopes []Scope
+}
+
+func (f fakeAccessTokenVerifier) VerifyAccessToken(_ context.Context, token string) (AuthContext, error) {
+	if token != AccessTokenPrefix+"valid" {
+		return AuthContext{}, errors.New("invalid token")
+	}
+	authCtx := NewAuthContext("user-1", "", PlanPlus, RoleUser, "", SubscriptionSourceNone, true)
+	authCtx.Scopes = f.scopes
+	return authCtx, nil
+}
+
+func TestAuthenticateWithAccessTokens(t *testing.T) {
+	log.Initialize()
+	gin.SetMode(gin.TestMode)
+
+	tests := map[string]struct {
+		token          string
+		scopes         []Scope
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		"should hand other tokens to the firebase authentication": {
+			token:          "firebase-id-token",
+			method:         http.MethodPost,
+			path:           "/v2/wallets",
+			expectedStatus: http.StatusTeapot,
+		},
+		"should reject an invalid token": {
+			token:          AccessTokenPrefix + "revoked",
+			scopes:         []Scope{ScopeRead},
+			method:         http.MethodGet,
+			path:           "/v2/wallets",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"should allow reads with the read scope": {
+			token:          AccessTokenPrefix + "valid",
+			scopes:         []Scope{ScopeRead},
+			method:         http.MethodGet,
+			path:           "/v2/wallets",
+			expectedStatus: http.StatusOK,
+		},
+		"should not allow movement writes with the read scope": {
+			token:          AccessTokenPrefix + "valid",
+			scopes:         []Scope{ScopeRead},
+			method:         http.MethodPost,
+			path:           "/v2/movements",
+			expectedStatus: http.StatusForbidden,
+		},
+		"should allow movement writes with the movements:write scope": {
+			token:          AccessTokenPrefix + "valid",
+			scopes:         []Scope{ScopeMovementsWrite},
+			method:         http.MethodPost,
+			path:           "/v2/movements",
+			expectedStatus: http.StatusOK,
+		},
+		"should not allow other writes with the movements:write scope": {
+			token:          AccessTokenPrefix + "valid",
+			scopes:         []Scope{ScopeRead, ScopeMovementsWrite},
+			method:         http.MethodPost,
+			path:           "/v2/wallets",
+			expectedStatus: http.StatusForbidden,
+		},
+		"should require the export scope for the export": {
+			token:          AccessTokenPrefix + "valid",
+			scopes:         []Scope{ScopeRead},
+			method:         http.MethodGet,
+			path:           "/me/export",
+			expectedStatus: http.StatusForbidden,
+		},
+		"should allow the export with the export scope": {
+			token:          AccessTokenPrefix + "valid",
+			scopes:         []Scope{ScopeExport},
+			method:         http.MethodGet,
+			path:           "/me/export",
+			expectedStatus: http.StatusOK,
+		},
+		"should not manage tokens with a token": {
+			token:          AccessTokenPrefix + "valid",
+			scopes:         []Scope{ScopeRead, ScopeMovementsWrite, ScopeExport},
+			method:         http.MethodGet,
+			path:           AccessTokensPath,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			firebase := func(c *gin.Context) {
+				c.AbortWithStatus(http.StatusTeapot)
+			}
+
+			r := gin.New()
+			r.Use(AuthenticateWithAccessTokens(fakeAccessTokenVerifier{scopes: tt.scopes}, firebase))
+			r.Handle(tt.method, tt.path, func(c *gin.Context) {
+				auth, ok := AuthFromContext(c.Request.Context())
+				assert.True(t, ok)
+				assert.Equal(t, "user-1", auth.UserID)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(UserToken, tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
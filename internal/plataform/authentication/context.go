@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Plan string
@@ -37,6 +38,9 @@ type AuthContext struct {
 	MPSubscriptionID   string
 	SubscriptionSource SubscriptionSource
 	Provisioned        bool
+	// Scopes limit what a personal access token may do. Nil for Firebase
+	// sessions, which are not restricted.
+	Scopes []Scope
 }
 
 type authContextKey struct{}
@@ -90,6 +94,14 @@ type PlanLimits struct {
 	CreditCards         int `json:"credit_cards"`
 	MovementsPerMonth   int `json:"movements_per_month"`
 	RecurrencesPerMonth int `json:"recurrences_per_month"`
+	AIQuota
+}
+
+// AIQuota is the monthly AI usage allowed (agent chat and statement import).
+// Zero means unlimited.
+type AIQuota struct {
+	TokensPerMonth   int `json:"ai_tokens_per_month"`
+	RequestsPerMonth int `json:"ai_requests_per_month"`
 }
 
 func GetFreePlanLimits() PlanLimits {
@@ -98,7 +110,35 @@ func GetFreePlanLimits() PlanLimits {
 		CreditCards:         getEnvInt("PLAN_FREE_CREDIT_CARDS_LIMIT", 1),
 		MovementsPerMonth:   getEnvInt("PLAN_FREE_MOVEMENTS_PER_MONTH_LIMIT", 50),
 		RecurrencesPerMonth: getEnvInt("PLAN_FREE_RECURRENCES_PER_MONTH_LIMIT", 3),
+		AIQuota:             GetPlanAIQuota(PlanFree),
+	}
+}
+
+// GetPlanAIQuota returns the AI quota of a plan. Unlike the other limits, Plus
+// is capped as well, since every AI call has a direct cost.
+func GetPlanAIQuota(plan Plan) AIQuota {
+	if plan == PlanPlus {
+		return AIQuota{
+			TokensPerMonth:   getEnvInt("PLAN_PLUS_AI_TOKENS_PER_MONTH_LIMIT", 2000000),
+			RequestsPerMonth: getEnvInt("PLAN_PLUS_AI_REQUESTS_PER_MONTH_LIMIT", 600),
+		}
 	}
+	return AIQuota{
+		TokensPerMonth:   getEnvInt("PLAN_FREE_AI_TOKENS_PER_MONTH_LIMIT", 100000),
+		RequestsPerMonth: getEnvInt("PLAN_FREE_AI_REQUESTS_PER_MONTH_LIMIT", 30),
+	}
+}
+
+// GetPastDueGrace returns how long a subscription whose payment failed keeps
+// Plus while the store retries the charge.
+func GetPastDueGrace() time.Duration {
+	return time.Duration(getEnvInt("SUBSCRIPTION_PAST_DUE_GRACE_DAYS", 7)) * 24 * time.Hour
+}
+
+// GetReferralRewardMonths returns the free Plus months each side of a
+// qualified referral gets.
+func GetReferralRewardMonths() int {
+	return getEnvInt("REFERRAL_REWARD_MONTHS", 1)
 }
 
 func getEnvInt(key string, defaultValue int) int {
@@ -23,7 +23,14 @@ const (
 type Authenticator interface {
 	Authenticate() gin.HandlerFunc
 	DeleteUser(ctx context.Context, userID string) error
-	AuthClient() *auth.Client
+	ClaimsStore() ClaimsStore
+}
+
+// ClaimsStore holds the custom claims of the users (plan, role, subscription).
+// Tokens carry the claims stored when they were issued.
+type ClaimsStore interface {
+	GetCustomClaims(ctx context.Context, userID string) (map[string]interface{}, error)
+	SetCustomClaims(ctx context.Context, userID string, claims map[string]interface{}) error
 }
 
 type firebaseAuth struct {
@@ -50,8 +57,24 @@ func NewFirebaseAuth() Authenticator {
 	}
 }
 
-func (f *firebaseAuth) AuthClient() *auth.Client {
-	return f.authClient
+func (f *firebaseAuth) ClaimsStore() ClaimsStore {
+	return firebaseClaimsStore{authClient: f.authClient}
+}
+
+type firebaseClaimsStore struct {
+	authClient *auth.Client
+}
+
+func (s firebaseClaimsStore) GetCustomClaims(ctx context.Context, userID string) (map[string]interface{}, error) {
+	user, err := s.authClient.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user from firebase: %w", err)
+	}
+	return user.CustomClaims, nil
+}
+
+func (s firebaseClaimsStore) SetCustomClaims(ctx context.Context, userID string, claims map[string]interface{}) error {
+	return s.authClient.SetCustomUserClaims(ctx, userID, claims)
 }
 
 func (f *firebaseAuth) Authenticate() gin.HandlerFunc {
@@ -72,21 +95,32 @@ func (f *firebaseAuth) Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		plan := f.extractPlanFromClaims(c.Request.Context(), token.UID, token.Claims)
-		role := extractRoleFromClaims(token.Claims)
-		mpSubscriptionID := extractMPSubscriptionIDFromClaims(token.Claims)
-		email := extractEmailFromClaims(token.Claims)
-		subscriptionSource := extractSubscriptionSourceFromClaims(token.Claims)
-		provisioned := extractProvisionedFromClaims(token.Claims)
-
-		authCtx := NewAuthContext(token.UID, email, plan, role, mpSubscriptionID, subscriptionSource, provisioned)
-		ctx := ContextWithAuth(c.Request.Context(), authCtx)
-		ctx = context.WithValue(ctx, UserID, token.UID)
-		c.Request = c.Request.WithContext(ctx)
+		authCtx := authContextFromClaims(token.UID, token.Claims)
+		c.Request = c.Request.WithContext(contextWithUser(c.Request.Context(), authCtx))
 	}
 }
 
-func (f *firebaseAuth) extractPlanFromClaims(ctx context.Context, uid string, claims map[string]interface{}) Plan {
+func authContextFromClaims(userID string, claims map[string]interface{}) AuthContext {
+	plan := extractPlanFromClaims(claims)
+	role := extractRoleFromClaims(claims)
+	mpSubscriptionID := extractMPSubscriptionIDFromClaims(claims)
+	email := extractEmailFromClaims(claims)
+	subscriptionSource := extractSubscriptionSourceFromClaims(claims)
+	provisioned := extractProvisionedFromClaims(claims)
+
+	return NewAuthContext(userID, email, plan, role, mpSubscriptionID, subscriptionSource, provisioned)
+}
+
+// contextWithUser stores the authenticated user the way every authenticator
+// does, for AuthFromContext and for the legacy handlers reading UserID.
+func contextWithUser(ctx context.Context, authCtx AuthContext) context.Context {
+	ctx = ContextWithAuth(ctx, authCtx)
+	return context.WithValue(ctx, UserID, authCtx.UserID)
+}
+
+// extractPlanFromClaims reads an expired plan as free. The claims themselves are
+// downgraded by the entitlement reconciliation job.
+func extractPlanFromClaims(claims map[string]interface{}) Plan {
 	if plan, ok := claims["plan"].(string); ok {
 		currentPlan := PlanFree
 		switch Plan(plan) {
@@ -97,31 +131,6 @@ func (f *firebaseAuth) extractPlanFromClaims(ctx context.Context, uid string, cl
 		if currentPlan == PlanPlus {
 			if expiresAt, ok := claims["plan_expires_at"].(float64); ok {
 				if time.Now().Unix() > int64(expiresAt) {
-					go func() {
-						bgCtx := context.Background()
-
-						// Firebase ID token claims contains reserved words (iss, aud, exp, etc). 
-						// To update Custom Claims we must fetch the raw Custom Claims from the user record.
-						user, err := f.authClient.GetUser(bgCtx, uid)
-						if err != nil {
-							log.ErrorContext(bgCtx, "failed to fetch user to downgrade expired subscription", log.Err(err))
-							return
-						}
-
-						userClaims := user.CustomClaims
-						if userClaims == nil {
-							userClaims = make(map[string]interface{})
-						}
-
-						userClaims["plan"] = string(PlanFree)
-						delete(userClaims, "plan_expires_at")
-
-						err = f.authClient.SetCustomUserClaims(bgCtx, uid, userClaims)
-						if err != nil {
-							log.ErrorContext(bgCtx, "failed hard downgrading expired subscription", log.Err(err))
-						}
-					}()
-
 					return PlanFree
 				}
 			}
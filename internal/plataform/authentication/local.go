@@ -0,0 +1,312 @@
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+
+	"personal-finance/internal/model"
+	"personal-finance/pkg/log"
+)
+
+const (
+	AuthProviderEnvName  = "AUTH_PROVIDER"
+	AuthProviderFirebase = "firebase"
+	AuthProviderLocal    = "local"
+
+	LocalAuthAlgorithmEnvName  = "LOCAL_AUTH_ALGORITHM"
+	LocalAuthSecretEnvName     = "LOCAL_AUTH_SECRET"
+	LocalAuthPrivateKeyEnvName = "LOCAL_AUTH_PRIVATE_KEY_FILE"
+	LocalAuthClaimsFileEnvName = "LOCAL_AUTH_CLAIMS_FILE"
+
+	localAuthIssuer            = "personal-finance-local"
+	localAuthDefaultClaimsFile = "local-auth-claims.json"
+)
+
+// NewAuthenticator returns the authenticator selected by AUTH_PROVIDER:
+// Firebase by default, or the local JWT authenticator, which is refused in
+// production.
+func NewAuthenticator(production bool) Authenticator {
+	if os.Getenv(AuthProviderEnvName) != AuthProviderLocal {
+		return NewFirebaseAuth()
+	}
+	if production {
+		log.Fatal("local authentication is not allowed in production")
+	}
+
+	cfg, err := LocalAuthConfigFromEnv()
+	if err != nil {
+		log.Fatal("error reading local auth config", log.Err(err))
+	}
+	localAuth, err := NewLocalAuth(cfg)
+	if err != nil {
+		log.Fatal("error initializing local auth", log.Err(err))
+	}
+	log.Warn("using local authentication; tokens are not verified by Firebase")
+	return localAuth
+}
+
+// LocalAuthConfig configures the local authenticator. HS256 signs with Secret;
+// RS256 signs with PrivateKeyPEM and verifies with its public key.
+type LocalAuthConfig struct {
+	Algorithm     string
+	Secret        []byte
+	PrivateKeyPEM []byte
+	// ClaimsFile keeps the claims between runs and between the API and the
+	// token command. Empty keeps them in memory.
+	ClaimsFile string
+}
+
+func LocalAuthConfigFromEnv() (LocalAuthConfig, error) {
+	cfg := LocalAuthConfig{
+		Algorithm:  os.Getenv(LocalAuthAlgorithmEnvName),
+		Secret:     []byte(os.Getenv(LocalAuthSecretEnvName)),
+		ClaimsFile: os.Getenv(LocalAuthClaimsFileEnvName),
+	}
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = jwt.SigningMethodHS256.Alg()
+	}
+	if cfg.ClaimsFile == "" {
+		cfg.ClaimsFile = localAuthDefaultClaimsFile
+	}
+
+	if keyFile := os.Getenv(LocalAuthPrivateKeyEnvName); keyFile != "" {
+		pem, err := os.ReadFile(keyFile)
+		if err != nil {
+			return LocalAuthConfig{}, fmt.Errorf("error reading %s: %w", LocalAuthPrivateKeyEnvName, err)
+		}
+		cfg.PrivateKeyPEM = pem
+	}
+	return cfg, nil
+}
+
+// LocalAuth issues and verifies JWTs carrying the same claims as the Firebase
+// ID tokens, for development and integration tests.
+type LocalAuth struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	claims    *LocalClaimsStore
+}
+
+func NewLocalAuth(cfg LocalAuthConfig) (*LocalAuth, error) {
+	auth := &LocalAuth{claims: NewLocalClaimsStore(cfg.ClaimsFile)}
+
+	switch cfg.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if len(cfg.Secret) == 0 {
+			return nil, fmt.Errorf("%s is required for HS256", LocalAuthSecretEnvName)
+		}
+		auth.method = jwt.SigningMethodHS256
+		auth.signKey = cfg.Secret
+		auth.verifyKey = cfg.Secret
+	case jwt.SigningMethodRS256.Alg():
+		if len(cfg.PrivateKeyPEM) == 0 {
+			return nil, fmt.Errorf("%s is required for RS256", LocalAuthPrivateKeyEnvName)
+		}
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(cfg.PrivateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing RS256 private key: %w", err)
+		}
+		auth.method = jwt.SigningMethodRS256
+		auth.signKey = key
+		auth.verifyKey = &key.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported %s %q: must be HS256 or RS256", LocalAuthAlgorithmEnvName, cfg.Algorithm)
+	}
+	return auth, nil
+}
+
+// MintToken issues a token for the user carrying the claims stored for it,
+// like a refreshed Firebase ID token.
+func (a *LocalAuth) MintToken(ctx context.Context, userID, email string, ttl time.Duration) (string, error) {
+	if userID == "" {
+		return "", errors.New("user id is required")
+	}
+
+	custom, err := a.claims.GetCustomClaims(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{}
+	for key, value := range custom {
+		claims[key] = value
+	}
+	claims["iss"] = localAuthIssuer
+	claims["sub"] = userID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
+	if email != "" {
+		claims["email"] = email
+	}
+
+	token, err := jwt.NewWithClaims(a.method, claims).SignedString(a.signKey)
+	if err != nil {
+		return "", fmt.Errorf("error signing local token: %w", err)
+	}
+	return token, nil
+}
+
+func (a *LocalAuth) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userToken := c.GetHeader(UserToken)
+		if userToken == "" {
+			log.ErrorContext(c.Request.Context(), "empty token")
+			c.JSON(http.StatusUnauthorized, model.ErrEmptyToken.Error())
+			c.Abort()
+			return
+		}
+
+		userID, claims, err := a.verify(userToken)
+		if err != nil {
+			log.ErrorContext(c.Request.Context(), "error verifying local token", log.Err(err))
+			c.JSON(http.StatusUnauthorized, "error verifying ID token: internal error")
+			c.Abort()
+			return
+		}
+
+		authCtx := authContextFromClaims(userID, claims)
+		c.Request = c.Request.WithContext(contextWithUser(c.Request.Context(), authCtx))
+	}
+}
+
+func (a *LocalAuth) verify(userToken string) (string, map[string]interface{}, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{a.method.Alg()}))
+
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(userToken, claims, func(*jwt.Token) (interface{}, error) {
+		return a.verifyKey, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !claims.VerifyIssuer(localAuthIssuer, true) {
+		return "", nil, errors.New("invalid issuer")
+	}
+	userID, _ := claims["sub"].(string)
+	if userID == "" {
+		return "", nil, errors.New("missing subject")
+	}
+	return userID, claims, nil
+}
+
+func (a *LocalAuth) DeleteUser(ctx context.Context, userID string) error {
+	return a.claims.Delete(ctx, userID)
+}
+
+func (a *LocalAuth) ClaimsStore() ClaimsStore {
+	return a.claims
+}
+
+// LocalClaimsStore keeps the custom claims of the local users in a JSON file,
+// read on every access so the API sees the changes of the token command.
+type LocalClaimsStore struct {
+	mu     sync.Mutex
+	path   string
+	memory map[string]map[string]interface{}
+}
+
+func NewLocalClaimsStore(path string) *LocalClaimsStore {
+	return &LocalClaimsStore{path: path, memory: map[string]map[string]interface{}{}}
+}
+
+// GetCustomClaims returns the claims of the user. Every user exists locally,
+// so an unknown user has no claims.
+func (s *LocalClaimsStore) GetCustomClaims(_ context.Context, userID string) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	claims := map[string]interface{}{}
+	for key, value := range all[userID] {
+		claims[key] = value
+	}
+	return claims, nil
+}
+
+// SetCustomClaims replaces the claims of the user. The claims go through JSON
+// so numbers read back as float64, as they do from Firebase.
+func (s *LocalClaimsStore) SetCustomClaims(_ context.Context, userID string, claims map[string]interface{}) error {
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return fmt.Errorf("error encoding local claims: %w", err)
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return fmt.Errorf("error decoding local claims: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+	all[userID] = normalized
+	return s.save(all)
+}
+
+func (s *LocalClaimsStore) Delete(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+	delete(all, userID)
+	return s.save(all)
+}
+
+func (s *LocalClaimsStore) load() (map[string]map[string]interface{}, error) {
+	if s.path == "" {
+		return s.memory, nil
+	}
+
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading local claims: %w", err)
+	}
+
+	all := map[string]map[string]interface{}{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &all); err != nil {
+			return nil, fmt.Errorf("error decoding local claims: %w", err)
+		}
+	}
+	return all, nil
+}
+
+func (s *LocalClaimsStore) save(all map[string]map[string]interface{}) error {
+	if s.path == "" {
+		s.memory = all
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding local claims: %w", err)
+	}
+	if err := os.WriteFile(s.path, raw, 0o600); err != nil {
+		return fmt.Errorf("error writing local claims: %w", err)
+	}
+	return nil
+}
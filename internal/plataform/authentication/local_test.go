@@ -0,0 +1,199 @@
+package authentication
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-finance/pkg/log"
+)
+
+func newRSAKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestLocalAuth_MintAndVerify(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]LocalAuthConfig{
+		"HS256": {Algorithm: "HS256", Secret: []byte("dev-secret")},
+		"RS256": {Algorithm: "RS256", PrivateKeyPEM: newRSAKeyPEM(t)},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			auth, err := NewLocalAuth(cfg)
+			require.NoError(t, err)
+			require.NoError(t, auth.ClaimsStore().SetCustomClaims(ctx, "user-1", map[string]interface{}{
+				"plan": "plus",
+				"role": "admin",
+			}))
+
+			token, err := auth.MintToken(ctx, "user-1", "user@test.com", time.Hour)
+			require.NoError(t, err)
+
+			userID, claims, err := auth.verify(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", userID)
+			assert.Equal(t, "plus", claims["plan"])
+			assert.Equal(t, "admin", claims["role"])
+			assert.Equal(t, "user@test.com", claims["email"])
+		})
+	}
+}
+
+func TestLocalAuth_RejectsInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	auth, err := NewLocalAuth(LocalAuthConfig{Algorithm: "HS256", Secret: []byte("dev-secret")})
+	require.NoError(t, err)
+
+	sign := func(claims jwt.MapClaims, secret string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"iss": localAuthIssuer, "sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+	}
+
+	expired := valid()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	otherIssuer := valid()
+	otherIssuer["iss"] = "someone-else"
+	noSubject := valid()
+	delete(noSubject, "sub")
+
+	rsaAuth, err := NewLocalAuth(LocalAuthConfig{Algorithm: "RS256", PrivateKeyPEM: newRSAKeyPEM(t)})
+	require.NoError(t, err)
+	rsaToken, err := rsaAuth.MintToken(ctx, "user-1", "", time.Hour)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"wrong secret":    sign(valid(), "other-secret"),
+		"expired":         sign(expired, "dev-secret"),
+		"wrong issuer":    sign(otherIssuer, "dev-secret"),
+		"missing subject": sign(noSubject, "dev-secret"),
+		"other algorithm": rsaToken,
+		"malformed":       "not-a-jwt",
+	}
+
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := auth.verify(token)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewLocalAuth_Config(t *testing.T) {
+	tests := map[string]LocalAuthConfig{
+		"HS256 without secret":      {Algorithm: "HS256"},
+		"RS256 without private key": {Algorithm: "RS256"},
+		"RS256 with invalid key":    {Algorithm: "RS256", PrivateKeyPEM: []byte("invalid")},
+		"unsupported algorithm":     {Algorithm: "ES256", Secret: []byte("dev-secret")},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewLocalAuth(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLocalAuth_Authenticate(t *testing.T) {
+	log.Initialize()
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	auth, err := NewLocalAuth(LocalAuthConfig{Algorithm: "HS256", Secret: []byte("dev-secret")})
+	require.NoError(t, err)
+	require.NoError(t, auth.ClaimsStore().SetCustomClaims(ctx, "user-1", map[string]interface{}{
+		"plan":                "plus",
+		"role":                "admin",
+		"subscription_source": "stripe",
+		"provisioned":         true,
+	}))
+	token, err := auth.MintToken(ctx, "user-1", "user@test.com", time.Hour)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		token          string
+		expectedStatus int
+	}{
+		"should authenticate a local token": {token: token, expectedStatus: http.StatusOK},
+		"should reject an empty token":      {token: "", expectedStatus: http.StatusUnauthorized},
+		"should reject an invalid token":    {token: "not-a-jwt", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var authCtx AuthContext
+			r := gin.New()
+			r.Use(auth.Authenticate())
+			r.GET("/v2/wallets", func(c *gin.Context) {
+				authCtx, _ = AuthFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/v2/wallets", nil)
+			req.Header.Set(UserToken, tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "user-1", authCtx.UserID)
+				assert.Equal(t, "user@test.com", authCtx.Email)
+				assert.Equal(t, PlanPlus, authCtx.Plan)
+				assert.Equal(t, RoleAdmin, authCtx.Role)
+				assert.Equal(t, SubscriptionSourceStripe, authCtx.SubscriptionSource)
+				assert.True(t, authCtx.Provisioned)
+			}
+		})
+	}
+}
+
+func TestLocalClaimsStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "claims.json")
+
+	store := NewLocalClaimsStore(path)
+	claims, err := store.GetCustomClaims(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, claims)
+
+	require.NoError(t, store.SetCustomClaims(ctx, "user-1", map[string]interface{}{
+		"plan":            "plus",
+		"plan_expires_at": int64(1900000000),
+	}))
+
+	t.Run("should read the claims from the file", func(t *testing.T) {
+		claims, err := NewLocalClaimsStore(path).GetCustomClaims(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, "plus", claims["plan"])
+		assert.Equal(t, float64(1900000000), claims["plan_expires_at"])
+	})
+
+	t.Run("should delete the claims of the user", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, "user-1"))
+		claims, err := NewLocalClaimsStore(path).GetCustomClaims(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Empty(t, claims)
+	})
+}
@@ -3,7 +3,6 @@ package authentication
 import (
 	"context"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 )
@@ -22,6 +21,6 @@ func (m *Mock) DeleteUser(_ context.Context, _ string) error {
 	return nil
 }
 
-func (m *Mock) AuthClient() *auth.Client {
+func (m *Mock) ClaimsStore() ClaimsStore {
 	return nil
 }
@@ -6,15 +6,22 @@ import (
 	"personal-finance/pkg/log"
 	"personal-finance/pkg/metrics"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 )
 
+// ReferralCodeHeader carries the referral code a new user signed up with. It is
+// only read on the request that provisions the user.
+const ReferralCodeHeader = "X-Referral-Code"
+
 type UserProvisioner interface {
 	EnsureExists(ctx context.Context, userID string) (bool, error)
 }
 
-func LazyProvisionUser(provisioner UserProvisioner, authClient *auth.Client) gin.HandlerFunc {
+type ReferralAttributor interface {
+	Attribute(ctx context.Context, refereeID, code string) error
+}
+
+func LazyProvisionUser(provisioner UserProvisioner, referrals ReferralAttributor, claims ClaimsStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 
@@ -35,32 +42,46 @@ func LazyProvisionUser(provisioner UserProvisioner, authClient *auth.Client) gin
 
 		if created {
 			metrics.IncBusiness(ctx, "biz_users_provisioned_total", 1)
+			attributeReferral(c, referrals, authCtx.UserID)
 		}
 
-		if authClient == nil {
+		if claims == nil {
 			return
 		}
 
-		go setProvisionedClaim(authClient, authCtx.UserID)
+		go setProvisionedClaim(claims, authCtx.UserID)
+	}
+}
+
+// attributeReferral never fails the request: a wrong code only loses the
+// referral.
+func attributeReferral(c *gin.Context, referrals ReferralAttributor, userID string) {
+	code := c.GetHeader(ReferralCodeHeader)
+	if referrals == nil || code == "" {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := referrals.Attribute(ctx, userID, code); err != nil {
+		log.WarnContext(ctx, "failed to attribute referral", log.Err(err))
 	}
 }
 
-func setProvisionedClaim(authClient *auth.Client, userID string) {
+func setProvisionedClaim(store ClaimsStore, userID string) {
 	ctx := context.Background()
 
-	user, err := authClient.GetUser(ctx, userID)
+	claims, err := store.GetCustomClaims(ctx, userID)
 	if err != nil {
 		log.ErrorContext(ctx, "failed to fetch user to set provisioned claim", log.Err(err))
 		return
 	}
 
-	claims := user.CustomClaims
 	if claims == nil {
 		claims = make(map[string]interface{})
 	}
 	claims["provisioned"] = true
 
-	if err := authClient.SetCustomUserClaims(ctx, userID, claims); err != nil {
+	if err := store.SetCustomClaims(ctx, userID, claims); err != nil {
 		log.ErrorContext(ctx, "failed to set provisioned claim", log.Err(err))
 	}
 }
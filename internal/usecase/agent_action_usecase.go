@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/google/uuid"
+)
+
+// --- Interfaces ---
+
+type AgentActionRepository interface {
+	Save(ctx context.Context, action domain.AgentAction) (domain.AgentAction, error)
+	FindByID(ctx context.Context, id uuid.UUID) (domain.AgentAction, error)
+	FindPendingByUserID(ctx context.Context, userID string) ([]domain.AgentAction, error)
+	UpdateStatus(ctx context.Context, action domain.AgentAction, from domain.AgentActionStatus) error
+}
+
+type AgentMovementService interface {
+	Add(ctx context.Context, movement domain.Movement) (domain.Movement, error)
+	Pay(ctx context.Context, id uuid.UUID, date time.Time) (domain.Movement, error)
+}
+
+type AgentTransferService interface {
+	Execute(ctx context.Context, input TransferInput) (TransferOutput, error)
+}
+
+// --- Use Case ---
+
+// AgentActions executes the write actions proposed by the agent once the user
+// confirms them. Execution always goes through the regular use cases, so their
+// validations and plan limits apply exactly as in the app.
+type AgentActions struct {
+	actionRepo      AgentActionRepository
+	auditRepo       AgentAuditRepository
+	movementService AgentMovementService
+	transferService AgentTransferService
+	estimateService Estimate
+	limitsValidator PlanLimitsValidatorInterface
+}
+
+func NewAgentActions(
+	actionRepo AgentActionRepository,
+	auditRepo AgentAuditRepository,
+	movementService AgentMovementService,
+	transferService AgentTransferService,
+	estimateService Estimate,
+	limitsValidator PlanLimitsValidatorInterface,
+) *AgentActions {
+	return &AgentActions{
+		actionRepo:      actionRepo,
+		auditRepo:       auditRepo,
+		movementService: movementService,
+		transferService: transferService,
+		estimateService: estimateService,
+		limitsValidator: limitsValidator,
+	}
+}
+
+func (u *AgentActions) ListPending(ctx context.Context) ([]domain.AgentAction, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	return u.actionRepo.FindPendingByUserID(ctx, userID)
+}
+
+// Confirm runs a pending action on behalf of the user and audits the execution,
+// whether it succeeds or fails.
+// A failed execution is kept as failed; the agent has to propose it again.
+func (u *AgentActions) Confirm(ctx context.Context, id uuid.UUID) (domain.AgentAction, error) {
+	action, err := u.findPending(ctx, id)
+	if err != nil {
+		return domain.AgentAction{}, err
+	}
+
+	action.Status = domain.AgentActionStatusExecuting
+	if err := u.actionRepo.UpdateStatus(ctx, action, domain.AgentActionStatusPending); err != nil {
+		return domain.AgentAction{}, err
+	}
+
+	resultID, execErr := u.execute(ctx, action)
+	if execErr != nil {
+		action.Resolve(domain.AgentActionStatusFailed, nil, execErr)
+	} else {
+		action.Resolve(domain.AgentActionStatusExecuted, resultID, nil)
+	}
+
+	// Audit log (LGPD Art. 37), recorded for failed executions as well
+	_ = u.auditRepo.Log(ctx, domain.AgentAuditRecord{
+		UserID:         action.UserID,
+		ConversationID: action.ConversationID,
+		ToolsCalled:    []string{string(action.Type)},
+		ActionID:       &action.ID,
+		ActionType:     string(action.Type),
+		ActionStatus:   string(action.Status),
+		ActionError:    action.Error,
+		Provider:       action.Provider,
+		Region:         action.Region,
+		CreatedAt:      time.Now(),
+	})
+
+	if err := u.actionRepo.UpdateStatus(ctx, action, domain.AgentActionStatusExecuting); err != nil {
+		return domain.AgentAction{}, err
+	}
+
+	if execErr != nil {
+		return domain.AgentAction{}, execErr
+	}
+
+	return action, nil
+}
+
+func (u *AgentActions) Reject(ctx context.Context, id uuid.UUID) (domain.AgentAction, error) {
+	action, err := u.findPending(ctx, id)
+	if err != nil {
+		return domain.AgentAction{}, err
+	}
+
+	action.Resolve(domain.AgentActionStatusRejected, nil, nil)
+	if err := u.actionRepo.UpdateStatus(ctx, action, domain.AgentActionStatusPending); err != nil {
+		return domain.AgentAction{}, err
+	}
+
+	return action, nil
+}
+
+// findPending loads an action of the current user that can still be decided.
+// Expired proposals are marked as such on the way.
+func (u *AgentActions) findPending(ctx context.Context, id uuid.UUID) (domain.AgentAction, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.AgentAction{}, domain.ErrUnauthorized
+	}
+
+	action, err := u.actionRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.AgentAction{}, err
+	}
+	if action.UserID != userID {
+		return domain.AgentAction{}, domain.ErrAgentActionNotFound
+	}
+	if !action.IsPending() {
+		return domain.AgentAction{}, domain.ErrAgentActionNotPending
+	}
+
+	if action.IsExpired(time.Now()) {
+		action.Resolve(domain.AgentActionStatusExpired, nil, nil)
+		if err := u.actionRepo.UpdateStatus(ctx, action, domain.AgentActionStatusPending); err != nil {
+			return domain.AgentAction{}, err
+		}
+		return domain.AgentAction{}, domain.ErrAgentActionExpired
+	}
+
+	return action, nil
+}
+
+func (u *AgentActions) execute(ctx context.Context, action domain.AgentAction) (*uuid.UUID, error) {
+	switch action.Type {
+	case domain.AgentActionCreateMovement:
+		return u.createMovement(ctx, action)
+	case domain.AgentActionPayMovement:
+		return u.payMovement(ctx, action)
+	case domain.AgentActionCreateEstimate:
+		return u.createEstimate(ctx, action)
+	case domain.AgentActionCreateTransfer:
+		return u.createTransfer(ctx, action)
+	}
+	return nil, fmt.Errorf("%w: unknown action type %q", domain.ErrAgentInvalidAction, action.Type)
+}
+
+func (u *AgentActions) createMovement(ctx context.Context, action domain.AgentAction) (*uuid.UUID, error) {
+	var params domain.AgentCreateMovementParams
+	if err := action.DecodeParams(&params); err != nil {
+		return nil, err
+	}
+
+	date, err := domain.ParseAgentDate(params.Date)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid movement date")
+	}
+	walletID, err := uuid.Parse(params.WalletID)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid wallet_id")
+	}
+	categoryID, err := domain.ParseOptionalAgentUUID(params.CategoryID)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid category_id")
+	}
+	subCategoryID, err := domain.ParseOptionalAgentUUID(params.SubCategoryID)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid sub_category_id")
+	}
+
+	created, err := u.movementService.Add(ctx, domain.Movement{
+		Description:   params.Description,
+		Amount:        params.Amount,
+		Date:          &date,
+		IsPaid:        params.IsPaid,
+		WalletID:      &walletID,
+		CategoryID:    categoryID,
+		SubCategoryID: subCategoryID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created.ID, nil
+}
+
+func (u *AgentActions) payMovement(ctx context.Context, action domain.AgentAction) (*uuid.UUID, error) {
+	var params domain.AgentPayMovementParams
+	if err := action.DecodeParams(&params); err != nil {
+		return nil, err
+	}
+
+	movementID, err := uuid.Parse(params.MovementID)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid movement_id")
+	}
+
+	date := time.Now()
+	if params.Date != "" {
+		if date, err = domain.ParseAgentDate(params.Date); err != nil {
+			return nil, domain.WrapInvalidInput(err, "invalid payment date")
+		}
+	}
+
+	paid, err := u.movementService.Pay(ctx, movementID, date)
+	if err != nil {
+		return nil, err
+	}
+	return paid.ID, nil
+}
+
+// createEstimate sets the category estimate of the month, updating it when one exists.
+func (u *AgentActions) createEstimate(ctx context.Context, action domain.AgentAction) (*uuid.UUID, error) {
+	var params domain.AgentCreateEstimateParams
+	if err := action.DecodeParams(&params); err != nil {
+		return nil, err
+	}
+
+	categoryID, err := uuid.Parse(params.CategoryID)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid category_id")
+	}
+
+	estimates, err := u.estimateService.FindByMonth(ctx, params.Month, params.Year)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, estimate := range estimates {
+		if estimate.CategoryID != nil && *estimate.CategoryID == categoryID {
+			updated, err := u.estimateService.UpdateEstimateCategoryAmount(ctx, estimate.ID, params.Amount)
+			if err != nil {
+				return nil, err
+			}
+			if updated.ID == nil {
+				return estimate.ID, nil
+			}
+			return updated.ID, nil
+		}
+	}
+
+	created, err := u.estimateService.AddEstimateCategory(ctx, domain.EstimateCategories{
+		CategoryID: &categoryID,
+		Month:      time.Month(params.Month),
+		Year:       params.Year,
+		Amount:     params.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created.ID, nil
+}
+
+func (u *AgentActions) createTransfer(ctx context.Context, action domain.AgentAction) (*uuid.UUID, error) {
+	var params domain.AgentCreateTransferParams
+	if err := action.DecodeParams(&params); err != nil {
+		return nil, err
+	}
+
+	date, err := domain.ParseAgentDate(params.Date)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid transfer date")
+	}
+	originID, err := uuid.Parse(params.OriginWalletID)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid origin_wallet_id")
+	}
+	destinationID, err := uuid.Parse(params.DestinationWalletID)
+	if err != nil {
+		return nil, domain.WrapInvalidInput(err, "invalid destination_wallet_id")
+	}
+
+	// Transfer.Execute only checks the recurrence limit; a transfer still creates
+	// movements, so the movement limit is checked here as Movement.Add would.
+	if u.limitsValidator != nil {
+		if err := u.limitsValidator.ValidateMovementCreation(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := u.transferService.Execute(ctx, TransferInput{
+		OriginWalletID:      originID,
+		DestinationWalletID: destinationID,
+		Amount:              params.Amount,
+		Date:                date,
+		Description:         params.Description,
+		IsPaid:              params.IsPaid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &output.PairID, nil
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const agentActionUserID = "user-1"
+
+func agentActionContext() context.Context {
+	return authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: agentActionUserID})
+}
+
+func pendingAgentAction(t *testing.T, actionType domain.AgentActionType, params any) domain.AgentAction {
+	t.Helper()
+	action, err := domain.NewAgentAction(agentActionUserID, actionType, "Confirma?", params)
+	assert.NoError(t, err)
+	return action
+}
+
+func TestAgentActions_Confirm(t *testing.T) {
+	walletID := uuid.New()
+
+	t.Run("should create the movement through the movement use case and audit the execution", func(t *testing.T) {
+		actionRepo := new(MockAgentActionRepository)
+		auditRepo := new(MockAgentAuditRepository)
+		movementService := new(MockAgentMovementService)
+
+		action := pendingAgentAction(t, domain.AgentActionCreateMovement, domain.AgentCreateMovementParams{
+			Description: "Almoço",
+			Amount:      -45,
+			Date:        "2025-03-10",
+			WalletID:    walletID.String(),
+			IsPaid:      true,
+		})
+		action.Provider = "vertex"
+		action.Region = "southamerica-east1"
+		movementID := uuid.New()
+
+		actionRepo.On("FindByID", action.ID).Return(action, nil)
+		actionRepo.On("UpdateStatus", mock.MatchedBy(func(a domain.AgentAction) bool {
+			return a.Status == domain.AgentActionStatusExecuting
+		}), domain.AgentActionStatusPending).Return(nil).Once()
+		actionRepo.On("UpdateStatus", mock.MatchedBy(func(a domain.AgentAction) bool {
+			return a.Status == domain.AgentActionStatusExecuted && *a.ResultID == movementID
+		}), domain.AgentActionStatusExecuting).Return(nil).Once()
+		movementService.On("Add", mock.MatchedBy(func(m domain.Movement) bool {
+			return m.Amount == -45 && *m.WalletID == walletID && m.IsPaid &&
+				m.Date.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+		})).Return(domain.Movement{ID: &movementID}, nil)
+		auditRepo.On("Log", mock.Anything, mock.MatchedBy(func(r domain.AgentAuditRecord) bool {
+			return *r.ActionID == action.ID && r.ActionType == string(domain.AgentActionCreateMovement) &&
+				r.ActionStatus == string(domain.AgentActionStatusExecuted) && r.ActionError == "" &&
+				r.Provider == "vertex" && r.Region == "southamerica-east1"
+		})).Return(nil).Once()
+
+		usecase := NewAgentActions(actionRepo, auditRepo, movementService, new(MockAgentTransferService), nil, nil)
+		result, err := usecase.Confirm(agentActionContext(), action.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AgentActionStatusExecuted, result.Status)
+		actionRepo.AssertExpectations(t)
+		movementService.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("should mark the action as failed and audit it when the plan limit is reached", func(t *testing.T) {
+		actionRepo := new(MockAgentActionRepository)
+		auditRepo := new(MockAgentAuditRepository)
+		transferService := new(MockAgentTransferService)
+		limitsValidator := new(MockPlanLimitsValidator)
+
+		action := pendingAgentAction(t, domain.AgentActionCreateTransfer, domain.AgentCreateTransferParams{
+			OriginWalletID:      walletID.String(),
+			DestinationWalletID: uuid.NewString(),
+			Amount:              100,
+			Date:                "2025-03-10",
+		})
+
+		actionRepo.On("FindByID", action.ID).Return(action, nil)
+		actionRepo.On("UpdateStatus", mock.Anything, domain.AgentActionStatusPending).Return(nil).Once()
+		actionRepo.On("UpdateStatus", mock.MatchedBy(func(a domain.AgentAction) bool {
+			return a.Status == domain.AgentActionStatusFailed && a.Error != ""
+		}), domain.AgentActionStatusExecuting).Return(nil).Once()
+		limitsValidator.On("ValidateMovementCreation", mock.Anything).Return(ErrMovementLimitReached)
+		auditRepo.On("Log", mock.Anything, mock.MatchedBy(func(r domain.AgentAuditRecord) bool {
+			return *r.ActionID == action.ID && r.ActionStatus == string(domain.AgentActionStatusFailed) &&
+				r.ActionError == ErrMovementLimitReached.Error()
+		})).Return(nil).Once()
+
+		usecase := NewAgentActions(actionRepo, auditRepo, new(MockAgentMovementService), transferService, nil, limitsValidator)
+		_, err := usecase.Confirm(agentActionContext(), action.ID)
+
+		assert.ErrorIs(t, err, ErrMovementLimitReached)
+		transferService.AssertNotCalled(t, "Execute", mock.Anything)
+		actionRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("should refuse an expired action", func(t *testing.T) {
+		actionRepo := new(MockAgentActionRepository)
+
+		action := pendingAgentAction(t, domain.AgentActionPayMovement, domain.AgentPayMovementParams{MovementID: uuid.NewString()})
+		action.ExpiresAt = time.Now().Add(-time.Minute)
+
+		actionRepo.On("FindByID", action.ID).Return(action, nil)
+		actionRepo.On("UpdateStatus", mock.MatchedBy(func(a domain.AgentAction) bool {
+			return a.Status == domain.AgentActionStatusExpired
+		}), domain.AgentActionStatusPending).Return(nil).Once()
+
+		usecase := NewAgentActions(actionRepo, new(MockAgentAuditRepository), new(MockAgentMovementService), new(MockAgentTransferService), nil, nil)
+		_, err := usecase.Confirm(agentActionContext(), action.ID)
+
+		assert.ErrorIs(t, err, domain.ErrAgentActionExpired)
+		actionRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not expose actions of another user", func(t *testing.T) {
+		actionRepo := new(MockAgentActionRepository)
+
+		action := pendingAgentAction(t, domain.AgentActionPayMovement, domain.AgentPayMovementParams{MovementID: uuid.NewString()})
+		action.UserID = "someone-else"
+		actionRepo.On("FindByID", action.ID).Return(action, nil)
+
+		usecase := NewAgentActions(actionRepo, new(MockAgentAuditRepository), new(MockAgentMovementService), new(MockAgentTransferService), nil, nil)
+		_, err := usecase.Confirm(agentActionContext(), action.ID)
+
+		assert.ErrorIs(t, err, domain.ErrAgentActionNotFound)
+		actionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAgentUseCase_ChatStoresProposedActions(t *testing.T) {
+	memoryRepo, convRepo, auditRepo, conv := newAgentStreamMocks(agentActionUserID)
+	actionRepo := new(MockAgentActionRepository)
+	gateway := new(MockAgentGateway)
+
+	proposed := pendingAgentAction(t, domain.AgentActionCreateEstimate, domain.AgentCreateEstimateParams{
+		CategoryID: uuid.NewString(),
+		Month:      3,
+		Year:       2025,
+		Amount:     -1200,
+	})
+	gateway.On("Chat", mock.Anything, "Defina meu orçamento de alimentação", mock.Anything).
+		Return(domain.AgentGatewayResponse{
+			Content:         "Posso definir?",
+			ProposedActions: []domain.AgentAction{proposed},
+			Provider:        "vertex",
+			Region:          "southamerica-east1",
+		}, nil)
+	convRepo.On("SaveMessage", mock.Anything, mock.Anything).Return(domain.AgentMessage{}, nil)
+	auditRepo.On("Log", mock.Anything, mock.Anything).Return(nil)
+	actionRepo.On("Save", mock.MatchedBy(func(a domain.AgentAction) bool {
+		return a.ID == proposed.ID && *a.ConversationID == conv.ID && a.IsPending() &&
+			a.Provider == "vertex" && a.Region == "southamerica-east1"
+	})).Return(proposed, nil).Once()
+
+	usecase := NewAgentUseCase(memoryRepo, convRepo, auditRepo, actionRepo, gateway, nil, AgentContextWindow{})
+	output, err := usecase.Chat(agentActionContext(), AgentChatInput{Message: "Defina meu orçamento de alimentação"})
+
+	assert.NoError(t, err)
+	assert.Len(t, output.PendingActions, 1)
+	actionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"personal-finance/internal/domain"
+	"personal-finance/pkg/log"
+)
+
+const (
+	defaultAgentContextTokenBudget = 6000
+	defaultAgentRecentTurns        = 4
+)
+
+// TokenCounter estimates how many tokens a text takes in the model prompt.
+type TokenCounter interface {
+	Count(text string) int
+}
+
+// ApproxTokenCounter estimates ~4 characters per token, close enough for
+// Portuguese text on Gemini and Llama tokenizers to size the prompt.
+type ApproxTokenCounter struct{}
+
+func (ApproxTokenCounter) Count(text string) int {
+	return (len([]rune(text)) + 3) / 4
+}
+
+// AgentContextWindow bounds the conversation history sent to the model. Once the
+// summary plus the unsummarized messages exceed TokenBudget, everything but the
+// last RecentTurns turns is folded into the stored conversation summary.
+// Zero values fall back to the defaults.
+type AgentContextWindow struct {
+	Counter     TokenCounter
+	TokenBudget int
+	RecentTurns int
+}
+
+func (w AgentContextWindow) withDefaults() AgentContextWindow {
+	if w.Counter == nil {
+		w.Counter = ApproxTokenCounter{}
+	}
+	if w.TokenBudget <= 0 {
+		w.TokenBudget = defaultAgentContextTokenBudget
+	}
+	if w.RecentTurns <= 0 {
+		w.RecentTurns = defaultAgentRecentTurns
+	}
+	return w
+}
+
+// buildHistory returns the messages to send with the new turn, summarizing older
+// turns first when the conversation is over the token budget. The cut is always
+// made between turns, so an answer built on tool results never loses the question
+// it answers. A failed summarization only trims the history for this turn.
+func (u *AgentUseCase) buildHistory(ctx context.Context, conv *domain.AgentConversation) []domain.AgentMessage {
+	window := u.contextWindow.withDefaults()
+	active := conv.ActiveMessages()
+
+	if window.Counter.Count(conv.Summary)+countMessages(window.Counter, active) <= window.TokenBudget {
+		return active
+	}
+
+	turns := splitTurns(active)
+	if len(turns) <= window.RecentTurns {
+		return active
+	}
+
+	var folded []domain.AgentMessage
+	for _, turn := range turns[:len(turns)-window.RecentTurns] {
+		folded = append(folded, turn...)
+	}
+	recent := active[len(folded):]
+
+	resp, err := u.gateway.Summarize(ctx, buildSummaryPrompt(conv.Summary, folded))
+	if err != nil || strings.TrimSpace(resp.Content) == "" {
+		log.WarnContext(ctx, "agent conversation summarization failed, sending recent turns only",
+			log.String("conversation_id", conv.ID.String()), log.Err(err))
+		return recent
+	}
+
+	summary := strings.TrimSpace(resp.Content)
+	summarized := conv.SummarizedMessages + len(folded)
+	if err := u.convRepo.UpdateSummary(ctx, conv.ID, summary, summarized); err != nil {
+		log.WarnContext(ctx, "failed to store agent conversation summary",
+			log.String("conversation_id", conv.ID.String()), log.Err(err))
+	}
+	conv.Summary = summary
+	conv.SummarizedMessages = summarized
+
+	if u.aiQuota != nil {
+		u.aiQuota.Record(ctx, domain.AIFeatureAgent, domain.AIUsage{
+			Requests:     1,
+			InputTokens:  int64(resp.InputTokens),
+			OutputTokens: int64(resp.OutputTokens),
+		})
+	}
+
+	return recent
+}
+
+// splitTurns groups messages into turns, each starting at a user message and
+// holding the assistant replies that follow it.
+func splitTurns(messages []domain.AgentMessage) [][]domain.AgentMessage {
+	var turns [][]domain.AgentMessage
+	for _, msg := range messages {
+		if msg.Role == "user" || len(turns) == 0 {
+			turns = append(turns, []domain.AgentMessage{msg})
+			continue
+		}
+		turns[len(turns)-1] = append(turns[len(turns)-1], msg)
+	}
+	return turns
+}
+
+func countMessages(counter TokenCounter, messages []domain.AgentMessage) int {
+	total := 0
+	for _, msg := range messages {
+		total += counter.Count(msg.Content)
+	}
+	return total
+}
+
+func buildSummaryPrompt(previousSummary string, messages []domain.AgentMessage) string {
+	var sb strings.Builder
+	sb.WriteString(`Resuma a conversa abaixo entre um usuário e seu assistente financeiro.
+Preserve os valores, datas, categorias e carteiras citados, as decisões tomadas e as ações propostas ou pendentes.
+Não invente informações. Responda apenas com o resumo, em português brasileiro, em no máximo 15 linhas.
+`)
+	if previousSummary != "" {
+		sb.WriteString("\nRESUMO ANTERIOR:\n")
+		sb.WriteString(previousSummary)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\nMENSAGENS:\n")
+	for _, msg := range messages {
+		role := "Usuário"
+		if msg.Role == "assistant" {
+			role = "Assistente"
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", role, msg.Content))
+	}
+	return sb.String()
+}
+
+func withConversationSummary(systemPrompt, summary string) string {
+	if summary == "" {
+		return systemPrompt
+	}
+	return systemPrompt + "\nRESUMO DA CONVERSA ATÉ AQUI:\n" + summary + "\n"
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedAgentGateway replays fixed replies and records what the use case sent,
+// so tests can inspect the exact history and prompts of each call.
+type scriptedAgentGateway struct {
+	reply        domain.AgentGatewayResponse
+	summary      domain.AgentGatewayResponse
+	summaryErr   error
+	systemPrompt string
+	history      []domain.AgentMessage
+	summaries    []string
+}
+
+func (g *scriptedAgentGateway) Chat(_ context.Context, systemPrompt string, _ string, history []domain.AgentMessage) (domain.AgentGatewayResponse, error) {
+	g.systemPrompt = systemPrompt
+	g.history = history
+	return g.reply, nil
+}
+
+func (g *scriptedAgentGateway) ChatStream(ctx context.Context, systemPrompt string, userMessage string, history []domain.AgentMessage, _ domain.AgentStreamEmitter) (domain.AgentGatewayResponse, error) {
+	return g.Chat(ctx, systemPrompt, userMessage, history)
+}
+
+func (g *scriptedAgentGateway) Summarize(_ context.Context, prompt string) (domain.AgentGatewayResponse, error) {
+	g.summaries = append(g.summaries, prompt)
+	return g.summary, g.summaryErr
+}
+
+// wordCounter counts one token per word, keeping budgets in tests readable.
+type wordCounter struct{}
+
+func (wordCounter) Count(text string) int {
+	return len(strings.Fields(text))
+}
+
+// conversationWithTurns builds turns whose answers quote tool results, as the
+// agent does after calling the financial tools.
+func conversationWithTurns(userID string, turns int) domain.AgentConversation {
+	conv := domain.NewAgentConversation(userID)
+	conv.Title = "Gastos"
+	for i := 1; i <= turns; i++ {
+		conv.Messages = append(conv.Messages,
+			domain.NewAgentMessage(conv.ID, "user", fmt.Sprintf("quanto gastei no mês %d?", i)),
+			domain.NewAgentMessage(conv.ID, "assistant", fmt.Sprintf("segundo get_spending_breakdown você gastou R$ %d00 no mês %d", i, i)),
+		)
+	}
+	return conv
+}
+
+func newAgentContextUseCase(conv domain.AgentConversation, gateway *scriptedAgentGateway, window AgentContextWindow) (*AgentUseCase, *MockAgentConversationRepository) {
+	memoryRepo := new(MockAgentMemoryRepository)
+	convRepo := new(MockAgentConversationRepository)
+	auditRepo := new(MockAgentAuditRepository)
+
+	memoryRepo.On("FindRelevant", conv.UserID, mock.Anything).Return([]domain.ScoredMemory{}, nil)
+	convRepo.On("FindByID", conv.ID).Return(conv, nil)
+	convRepo.On("SaveMessage", mock.Anything, mock.Anything).Return(domain.AgentMessage{}, nil)
+	auditRepo.On("Log", mock.Anything, mock.Anything).Return(nil)
+
+	usecase := NewAgentUseCase(memoryRepo, convRepo, auditRepo, new(MockAgentActionRepository), gateway, nil, window)
+	return usecase, convRepo
+}
+
+func TestAgentUseCase_ContextWindow(t *testing.T) {
+	log.Initialize()
+	const userID = "user-1"
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: userID})
+	window := AgentContextWindow{Counter: wordCounter{}, TokenBudget: 60, RecentTurns: 2}
+
+	t.Run("should send the whole history while under the token budget", func(t *testing.T) {
+		conv := conversationWithTurns(userID, 2)
+		gateway := &scriptedAgentGateway{reply: domain.AgentGatewayResponse{Content: "ok"}}
+		usecase, convRepo := newAgentContextUseCase(conv, gateway, window)
+
+		_, err := usecase.Chat(ctx, AgentChatInput{Message: "e agora?", ConversationID: &conv.ID})
+
+		require.NoError(t, err)
+		assert.Equal(t, conv.Messages, gateway.history)
+		assert.Empty(t, gateway.summaries)
+		convRepo.AssertNotCalled(t, "UpdateSummary", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fold older turns into the summary and keep whole recent turns", func(t *testing.T) {
+		conv := conversationWithTurns(userID, 5)
+		gateway := &scriptedAgentGateway{
+			reply:   domain.AgentGatewayResponse{Content: "ok"},
+			summary: domain.AgentGatewayResponse{Content: "Gastos: mês 1 R$ 100, mês 2 R$ 200, mês 3 R$ 300."},
+		}
+		usecase, convRepo := newAgentContextUseCase(conv, gateway, window)
+		convRepo.On("UpdateSummary", conv.ID, "Gastos: mês 1 R$ 100, mês 2 R$ 200, mês 3 R$ 300.", 6).Return(nil).Once()
+
+		_, err := usecase.Chat(ctx, AgentChatInput{Message: "e agora?", ConversationID: &conv.ID})
+
+		require.NoError(t, err)
+		convRepo.AssertExpectations(t)
+
+		// Each tool-based answer stays next to the question it answers
+		require.Len(t, gateway.history, 4)
+		assert.Equal(t, conv.Messages[6:], gateway.history)
+		assert.Equal(t, "user", gateway.history[0].Role)
+
+		// Folded turns reach the summarizer complete, answers included
+		require.Len(t, gateway.summaries, 1)
+		for _, msg := range conv.Messages[:6] {
+			assert.Contains(t, gateway.summaries[0], msg.Content)
+		}
+		assert.NotContains(t, gateway.summaries[0], conv.Messages[6].Content)
+		assert.Contains(t, gateway.systemPrompt, "mês 3 R$ 300")
+	})
+
+	t.Run("should extend an existing summary with the newly folded turns", func(t *testing.T) {
+		conv := conversationWithTurns(userID, 7)
+		conv.Summary = "Resumo antigo: mês 1 R$ 100."
+		conv.SummarizedMessages = 2
+		gateway := &scriptedAgentGateway{
+			reply:   domain.AgentGatewayResponse{Content: "ok"},
+			summary: domain.AgentGatewayResponse{Content: "Resumo novo."},
+		}
+		usecase, convRepo := newAgentContextUseCase(conv, gateway, window)
+		convRepo.On("UpdateSummary", conv.ID, "Resumo novo.", 10).Return(nil).Once()
+
+		_, err := usecase.Chat(ctx, AgentChatInput{Message: "e agora?", ConversationID: &conv.ID})
+
+		require.NoError(t, err)
+		convRepo.AssertExpectations(t)
+		assert.Equal(t, conv.Messages[10:], gateway.history)
+		assert.Contains(t, gateway.summaries[0], "Resumo antigo: mês 1 R$ 100.")
+		assert.NotContains(t, gateway.summaries[0], conv.Messages[0].Content)
+		assert.Contains(t, gateway.systemPrompt, "Resumo novo.")
+		assert.NotContains(t, gateway.systemPrompt, "Resumo antigo")
+	})
+
+	t.Run("should send only recent turns when summarization fails", func(t *testing.T) {
+		conv := conversationWithTurns(userID, 5)
+		gateway := &scriptedAgentGateway{
+			reply:      domain.AgentGatewayResponse{Content: "ok"},
+			summaryErr: errors.New("model unavailable"),
+		}
+		usecase, convRepo := newAgentContextUseCase(conv, gateway, window)
+
+		_, err := usecase.Chat(ctx, AgentChatInput{Message: "e agora?", ConversationID: &conv.ID})
+
+		require.NoError(t, err)
+		assert.Equal(t, conv.Messages[6:], gateway.history)
+		convRepo.AssertNotCalled(t, "UpdateSummary", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSplitTurns(t *testing.T) {
+	convID := domain.NewAgentConversation("user-1").ID
+	messages := []domain.AgentMessage{
+		domain.NewAgentMessage(convID, "assistant", "olá"),
+		domain.NewAgentMessage(convID, "user", "a"),
+		domain.NewAgentMessage(convID, "assistant", "b"),
+		domain.NewAgentMessage(convID, "assistant", "c"),
+		domain.NewAgentMessage(convID, "user", "d"),
+	}
+
+	turns := splitTurns(messages)
+
+	require.Len(t, turns, 3)
+	assert.Len(t, turns[0], 1)
+	assert.Len(t, turns[1], 3)
+	assert.Len(t, turns[2], 1)
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+)
+
+// --- Interfaces ---
+
+type AgentDataMemoryRepository interface {
+	FindByUserID(ctx context.Context, userID string) ([]domain.AgentMemory, error)
+	DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error
+}
+
+type AgentDataConversationRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (domain.AgentConversation, error)
+	FindByUserID(ctx context.Context, userID string) ([]domain.AgentConversation, error)
+	FindAllWithMessagesByUserID(ctx context.Context, userID string) ([]domain.AgentConversation, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type AgentDataAuditRepository interface {
+	FindByUserID(ctx context.Context, userID string) ([]domain.AgentAuditRecord, error)
+}
+
+type AgentDataActionRepository interface {
+	FindAllByUserID(ctx context.Context, userID string) ([]domain.AgentAction, error)
+}
+
+type AgentDataInsightPreferenceRepository interface {
+	FindByUserID(ctx context.Context, userID string) (domain.InsightPreference, error)
+}
+
+type AgentSettingsRepository interface {
+	FindByUserID(ctx context.Context, userID string) (domain.AgentSettings, error)
+	Upsert(ctx context.Context, settings domain.AgentSettings) (domain.AgentSettings, error)
+}
+
+// --- Use Case ---
+
+// AgentDataControls lets the user see what the agent processed and delete or
+// stop what it keeps: the audit of each conversation, conversation and memory
+// deletion, the memory toggle and the agent part of the data export.
+type AgentDataControls struct {
+	memoryRepo   AgentDataMemoryRepository
+	convRepo     AgentDataConversationRepository
+	auditRepo    AgentDataAuditRepository
+	actionRepo   AgentDataActionRepository
+	prefRepo     AgentDataInsightPreferenceRepository
+	settingsRepo AgentSettingsRepository
+}
+
+func NewAgentDataControls(
+	memoryRepo AgentDataMemoryRepository,
+	convRepo AgentDataConversationRepository,
+	auditRepo AgentDataAuditRepository,
+	actionRepo AgentDataActionRepository,
+	prefRepo AgentDataInsightPreferenceRepository,
+	settingsRepo AgentSettingsRepository,
+) *AgentDataControls {
+	return &AgentDataControls{
+		memoryRepo:   memoryRepo,
+		convRepo:     convRepo,
+		auditRepo:    auditRepo,
+		actionRepo:   actionRepo,
+		prefRepo:     prefRepo,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// ListAudit sums up the audit log of the current user per conversation. The
+// audit of deleted conversations is kept and listed without a title.
+func (u *AgentDataControls) ListAudit(ctx context.Context) ([]domain.AgentAuditConversation, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, ErrUnauthorized
+	}
+
+	records, err := u.auditRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	convs, err := u.convRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	titles := make(map[uuid.UUID]string, len(convs))
+	for _, conv := range convs {
+		titles[conv.ID] = conv.Title
+	}
+
+	return domain.SummarizeAgentAudit(records, titles), nil
+}
+
+func (u *AgentDataControls) DeleteConversation(ctx context.Context, id uuid.UUID) error {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return ErrUnauthorized
+	}
+
+	conv, err := u.convRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if conv.UserID != userID {
+		return domain.ErrUnauthorized
+	}
+
+	return u.convRepo.Delete(ctx, id)
+}
+
+func (u *AgentDataControls) DeleteAllMemories(ctx context.Context) error {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return ErrUnauthorized
+	}
+
+	return u.memoryRepo.DeleteAllByUserID(ctx, nil, userID)
+}
+
+// GetSettings returns the agent settings of the current user, the defaults when
+// never changed.
+func (u *AgentDataControls) GetSettings(ctx context.Context) (domain.AgentSettings, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.AgentSettings{}, ErrUnauthorized
+	}
+
+	settings, err := u.settingsRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if domain.Is(err, repository.ErrAgentSettingsNotFound) {
+			return domain.DefaultAgentSettings(userID), nil
+		}
+		return domain.AgentSettings{}, err
+	}
+	return settings, nil
+}
+
+func (u *AgentDataControls) SetSettings(ctx context.Context, memoryEnabled bool) (domain.AgentSettings, error) {
+	settings, err := u.GetSettings(ctx)
+	if err != nil {
+		return domain.AgentSettings{}, err
+	}
+
+	settings.MemoryEnabled = memoryEnabled
+	return u.settingsRepo.Upsert(ctx, settings)
+}
+
+// ExportData gathers everything the agent keeps about the current user for the
+// data export.
+func (u *AgentDataControls) ExportData(ctx context.Context) (domain.UserDataExportAgent, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.UserDataExportAgent{}, ErrUnauthorized
+	}
+
+	settings, err := u.GetSettings(ctx)
+	if err != nil {
+		return domain.UserDataExportAgent{}, err
+	}
+
+	memories, err := u.memoryRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return domain.UserDataExportAgent{}, err
+	}
+
+	convs, err := u.convRepo.FindAllWithMessagesByUserID(ctx, userID)
+	if err != nil {
+		return domain.UserDataExportAgent{}, err
+	}
+
+	actions, err := u.actionRepo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return domain.UserDataExportAgent{}, err
+	}
+
+	audit, err := u.auditRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return domain.UserDataExportAgent{}, err
+	}
+
+	export := domain.UserDataExportAgent{
+		Settings:      settings,
+		Memories:      memories,
+		Conversations: convs,
+		Actions:       actions,
+		Audit:         audit,
+	}
+
+	pref, err := u.prefRepo.FindByUserID(ctx, userID)
+	switch {
+	case err == nil:
+		export.InsightPreference = &pref
+	case !domain.Is(err, repository.ErrInsightPreferenceNotFound):
+		return domain.UserDataExportAgent{}, err
+	}
+
+	return export, nil
+}
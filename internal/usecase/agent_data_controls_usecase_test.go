@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type agentDataMocks struct {
+	memory   *MockAgentMemoryRepository
+	conv     *MockAgentConversationRepository
+	audit    *MockAgentAuditRepository
+	action   *MockAgentActionRepository
+	prefs    *MockInsightPreferenceRepository
+	settings *MockAgentSettingsRepository
+}
+
+func newAgentDataControlsWithMocks() (*AgentDataControls, agentDataMocks) {
+	m := agentDataMocks{
+		memory:   new(MockAgentMemoryRepository),
+		conv:     new(MockAgentConversationRepository),
+		audit:    new(MockAgentAuditRepository),
+		action:   new(MockAgentActionRepository),
+		prefs:    new(MockInsightPreferenceRepository),
+		settings: new(MockAgentSettingsRepository),
+	}
+	return NewAgentDataControls(m.memory, m.conv, m.audit, m.action, m.prefs, m.settings), m
+}
+
+func TestAgentDataControls_ListAudit(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1"})
+	kept, deleted := uuid.New(), uuid.New()
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	controls, m := newAgentDataControlsWithMocks()
+	m.audit.On("FindByUserID", "user-1").Return([]domain.AgentAuditRecord{
+		{ConversationID: &deleted, ToolsCalled: []string{"get_movements"}, InputTokens: 10, CreatedAt: now},
+		{ConversationID: &kept, ToolsCalled: []string{"get_financial_overview"}, InputTokens: 20, CreatedAt: now.Add(time.Hour)},
+	}, nil)
+	m.conv.On("FindByUserID", "user-1").Return([]domain.AgentConversation{{ID: kept, UserID: "user-1", Title: "Resumo do mês"}}, nil)
+
+	summaries, err := controls.ListAudit(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "Resumo do mês", summaries[0].Title)
+	assert.Equal(t, []domain.AgentToolUsage{{Name: "get_financial_overview", Calls: 1}}, summaries[0].Tools)
+	assert.Empty(t, summaries[1].Title)
+}
+
+func TestAgentDataControls_DeleteConversation(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1"})
+	id := uuid.New()
+
+	tests := map[string]struct {
+		owner       string
+		findErr     error
+		expectedErr error
+	}{
+		"should delete a conversation of the user": {owner: "user-1"},
+		"should not delete a conversation of another user": {
+			owner:       "user-2",
+			expectedErr: domain.ErrUnauthorized,
+		},
+		"should return not found for a missing conversation": {
+			findErr:     domain.ErrNotFound,
+			expectedErr: domain.ErrNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controls, m := newAgentDataControlsWithMocks()
+			m.conv.On("FindByID", id).Return(domain.AgentConversation{ID: id, UserID: tt.owner}, tt.findErr)
+			m.conv.On("Delete", id).Return(nil)
+
+			err := controls.DeleteConversation(ctx, id)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				m.conv.AssertNotCalled(t, "Delete", id)
+				return
+			}
+			require.NoError(t, err)
+			m.conv.AssertCalled(t, "Delete", id)
+		})
+	}
+}
+
+func TestAgentDataControls_Settings(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1"})
+
+	t.Run("should enable memories for a user who never changed the settings", func(t *testing.T) {
+		controls, m := newAgentDataControlsWithMocks()
+		m.settings.On("FindByUserID", "user-1").Return(domain.AgentSettings{}, repository.ErrAgentSettingsNotFound)
+
+		settings, err := controls.GetSettings(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, settings.MemoryEnabled)
+	})
+
+	t.Run("should store the memory toggle", func(t *testing.T) {
+		controls, m := newAgentDataControlsWithMocks()
+		m.settings.On("FindByUserID", "user-1").Return(domain.AgentSettings{}, repository.ErrAgentSettingsNotFound)
+		expected := domain.AgentSettings{UserID: "user-1", MemoryEnabled: false}
+		m.settings.On("Upsert", expected).Return(expected, nil)
+
+		settings, err := controls.SetSettings(ctx, false)
+
+		require.NoError(t, err)
+		assert.False(t, settings.MemoryEnabled)
+	})
+
+	t.Run("should wipe all memories of the user", func(t *testing.T) {
+		controls, m := newAgentDataControlsWithMocks()
+		m.memory.On("DeleteAllByUserID", (*gorm.DB)(nil), "user-1").Return(nil)
+
+		require.NoError(t, controls.DeleteAllMemories(ctx))
+		m.memory.AssertExpectations(t)
+	})
+}
+
+func TestAgentDataControls_ExportData(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1"})
+
+	controls, m := newAgentDataControlsWithMocks()
+	m.settings.On("FindByUserID", "user-1").Return(domain.AgentSettings{UserID: "user-1", MemoryEnabled: false}, nil)
+	m.memory.On("FindByUserID", "user-1").Return([]domain.AgentMemory{{Content: "Juntar para a viagem"}}, nil)
+	m.conv.On("FindAllWithMessagesByUserID", "user-1").Return([]domain.AgentConversation{{Title: "Viagem"}}, nil)
+	m.action.On("FindAllByUserID", "user-1").Return([]domain.AgentAction{}, nil)
+	m.audit.On("FindByUserID", "user-1").Return([]domain.AgentAuditRecord{{InputTokens: 10}}, nil)
+	m.prefs.On("FindByUserID", "user-1").Return(domain.InsightPreference{}, repository.ErrInsightPreferenceNotFound)
+
+	export, err := controls.ExportData(ctx)
+
+	require.NoError(t, err)
+	assert.False(t, export.Settings.MemoryEnabled)
+	assert.Len(t, export.Memories, 1)
+	assert.Len(t, export.Conversations, 1)
+	assert.Len(t, export.Audit, 1)
+	assert.Nil(t, export.InsightPreference)
+}
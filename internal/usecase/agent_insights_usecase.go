@@ -0,0 +1,449 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+)
+
+const (
+	insightDigestInterval     = 7 * 24 * time.Hour
+	insightMemoryTTLDays      = 14
+	insightTrailingMonths     = 3
+	insightSpikeRatio         = 1.5
+	insightSpikeMinIncrease   = 100.0
+	insightBudgetPaceMargin   = 0.15
+	insightLargeInvoiceAmount = 1000.0
+	insightInvoiceHorizonDays = 7
+	insightPushTitle          = "Seu resumo semanal"
+)
+
+// --- Interfaces ---
+
+type InsightPreferenceRepository interface {
+	FindByUserID(ctx context.Context, userID string) (domain.InsightPreference, error)
+	Upsert(ctx context.Context, pref domain.InsightPreference) (domain.InsightPreference, error)
+	FindDue(ctx context.Context, notAfter time.Time) ([]domain.InsightPreference, error)
+	ClaimDigest(ctx context.Context, userID string, sentAt, notAfter time.Time) (bool, error)
+}
+
+// InsightFinancialRepository reads the user finances from the context user, the
+// same queries the agent tools use.
+type InsightFinancialRepository interface {
+	GetSpendingBreakdown(ctx context.Context, month, year int) (domain.AgentSpendingBreakdown, error)
+	GetRecurringSummary(ctx context.Context) (domain.AgentRecurringSummary, error)
+	GetBudgetStatus(ctx context.Context, month, year int) (domain.AgentBudgetStatus, error)
+	GetCreditCardsSummary(ctx context.Context) (domain.AgentCreditCardsSummary, error)
+}
+
+// InsightPhraser writes the digest text from the computed signals. The agent
+// gateway implements it.
+type InsightPhraser interface {
+	Summarize(ctx context.Context, prompt string) (domain.AgentGatewayResponse, error)
+}
+
+// --- Use Case ---
+
+// AgentInsights computes deterministic weekly signals for opted-in users, stores
+// them as insight memories the agent can use and sends a push digest.
+type AgentInsights struct {
+	prefRepo      InsightPreferenceRepository
+	financialRepo InsightFinancialRepository
+	memoryRepo    AgentMemoryRepository
+	deviceRepo    PushDeviceRepository
+	pushSender    PushSender
+	phraser       InsightPhraser
+	aiQuota       AIQuotaGuard
+}
+
+// NewAgentInsights builds the use case. phraser is optional: without it the
+// digest is written from the signals themselves.
+func NewAgentInsights(
+	prefRepo InsightPreferenceRepository,
+	financialRepo InsightFinancialRepository,
+	memoryRepo AgentMemoryRepository,
+	deviceRepo PushDeviceRepository,
+	pushSender PushSender,
+	phraser InsightPhraser,
+	aiQuota AIQuotaGuard,
+) *AgentInsights {
+	return &AgentInsights{
+		prefRepo:      prefRepo,
+		financialRepo: financialRepo,
+		memoryRepo:    memoryRepo,
+		deviceRepo:    deviceRepo,
+		pushSender:    pushSender,
+		phraser:       phraser,
+		aiQuota:       aiQuota,
+	}
+}
+
+type InsightJobResult struct {
+	UsersDue        int `json:"users_due"`
+	DigestsSent     int `json:"digests_sent"`
+	InsightsCreated int `json:"insights_created"`
+	PushSent        int `json:"push_sent"`
+	PushFailed      int `json:"push_failed"`
+	Skipped         int `json:"skipped"`
+	Failed          int `json:"failed"`
+}
+
+// --- Preference ---
+
+// GetPreference returns the opt-in of the current user, disabled when never set.
+func (u *AgentInsights) GetPreference(ctx context.Context) (domain.InsightPreference, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.InsightPreference{}, ErrUnauthorized
+	}
+
+	pref, err := u.prefRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if domain.Is(err, repository.ErrInsightPreferenceNotFound) {
+			return domain.InsightPreference{UserID: userID}, nil
+		}
+		return domain.InsightPreference{}, err
+	}
+	return pref, nil
+}
+
+func (u *AgentInsights) SetPreference(ctx context.Context, enabled bool) (domain.InsightPreference, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.InsightPreference{}, ErrUnauthorized
+	}
+	return u.prefRepo.Upsert(ctx, domain.InsightPreference{UserID: userID, Enabled: enabled})
+}
+
+// --- Weekly Job ---
+
+// SendWeeklyDigests sends the digest to every opted-in user whose last one is at
+// least a week old. The digest is claimed before it is delivered, so running the
+// job again in the same week, or concurrently, never sends it twice. A week
+// without signals still counts as delivered.
+func (u *AgentInsights) SendWeeklyDigests(ctx context.Context, now time.Time) (InsightJobResult, error) {
+	result := InsightJobResult{}
+	notAfter := now.Add(-insightDigestInterval)
+
+	prefs, err := u.prefRepo.FindDue(ctx, notAfter)
+	if err != nil {
+		return result, fmt.Errorf("error finding users due for insights: %w", err)
+	}
+	result.UsersDue = len(prefs)
+
+	for _, pref := range prefs {
+		userCtx := authentication.ContextWithAuth(ctx, authentication.AuthContext{UserID: pref.UserID})
+
+		insights, err := u.ComputeInsights(userCtx, now)
+		if err != nil {
+			log.Error("error computing insights", log.String("user_id", pref.UserID), log.Err(err))
+			result.Failed++
+			continue
+		}
+
+		claimed, err := u.prefRepo.ClaimDigest(ctx, pref.UserID, now, notAfter)
+		if err != nil {
+			log.Error("error claiming insight digest", log.String("user_id", pref.UserID), log.Err(err))
+			result.Failed++
+			continue
+		}
+		if !claimed || len(insights) == 0 {
+			result.Skipped++
+			continue
+		}
+
+		result.InsightsCreated += u.saveInsights(userCtx, pref.UserID, insights, now)
+		u.sendDigest(userCtx, pref.UserID, u.digestText(userCtx, insights), &result)
+		result.DigestsSent++
+	}
+
+	log.Info("insights job completed",
+		log.Int("users_due", result.UsersDue),
+		log.Int("digests_sent", result.DigestsSent),
+		log.Int("insights_created", result.InsightsCreated),
+		log.Int("push_sent", result.PushSent),
+		log.Int("push_failed", result.PushFailed),
+		log.Int("skipped", result.Skipped),
+		log.Int("failed", result.Failed),
+	)
+
+	return result, nil
+}
+
+// ComputeInsights returns the signals of the context user at now: category
+// spending spikes, recurring charges started in the last week, budgets ahead of
+// the month pace and large invoices due in the next days.
+func (u *AgentInsights) ComputeInsights(ctx context.Context, now time.Time) ([]domain.Insight, error) {
+	var insights []domain.Insight
+
+	spikes, err := u.spendingSpikes(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	insights = append(insights, spikes...)
+
+	recurring, err := u.newRecurringCharges(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	insights = append(insights, recurring...)
+
+	pace, err := u.budgetPace(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	insights = append(insights, pace...)
+
+	invoices, err := u.upcomingInvoices(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	insights = append(insights, invoices...)
+
+	return insights, nil
+}
+
+// spendingSpikes compares the month-to-date spending of each category with its
+// average over the previous months.
+func (u *AgentInsights) spendingSpikes(ctx context.Context, now time.Time) ([]domain.Insight, error) {
+	current, err := u.financialRepo.GetSpendingBreakdown(ctx, int(now.Month()), now.Year())
+	if err != nil {
+		return nil, err
+	}
+
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	trailing := make(map[string]float64)
+	for i := 1; i <= insightTrailingMonths; i++ {
+		month := firstOfMonth.AddDate(0, -i, 0)
+		breakdown, err := u.financialRepo.GetSpendingBreakdown(ctx, int(month.Month()), month.Year())
+		if err != nil {
+			return nil, err
+		}
+		for _, cat := range breakdown.Categories {
+			if !cat.IsIncome {
+				trailing[categoryKey(cat)] += cat.Amount
+			}
+		}
+	}
+
+	var insights []domain.Insight
+	for _, cat := range current.Categories {
+		if cat.IsIncome {
+			continue
+		}
+		avg := trailing[categoryKey(cat)] / insightTrailingMonths
+		if avg <= 0 || cat.Amount < avg*insightSpikeRatio || cat.Amount-avg < insightSpikeMinIncrease {
+			continue
+		}
+		insights = append(insights, domain.Insight{
+			Kind:    domain.InsightSpendingSpike,
+			Subject: cat.Name,
+			Amount:  cat.Amount,
+			Message: fmt.Sprintf("Os gastos com %s já somam R$ %.2f neste mês, %.0f%% acima da média dos últimos 3 meses (R$ %.2f).",
+				cat.Name, cat.Amount, (cat.Amount/avg-1)*100, avg),
+		})
+	}
+	return insights, nil
+}
+
+func categoryKey(cat domain.AgentCategoryItem) string {
+	if cat.ID != "" {
+		return cat.ID
+	}
+	return cat.Name
+}
+
+func (u *AgentInsights) newRecurringCharges(ctx context.Context, now time.Time) ([]domain.Insight, error) {
+	summary, err := u.financialRepo.GetRecurringSummary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since := now.Add(-insightDigestInterval)
+	var insights []domain.Insight
+	for _, item := range summary.Items {
+		if item.Amount >= 0 {
+			continue
+		}
+		start, err := time.Parse("2006-01-02", item.StartDate)
+		if err != nil || start.Before(since) || start.After(now) {
+			continue
+		}
+		amount := math.Abs(item.Amount)
+		insights = append(insights, domain.Insight{
+			Kind:    domain.InsightNewRecurring,
+			Subject: item.Description,
+			Amount:  amount,
+			Message: fmt.Sprintf("Nova cobrança recorrente: %s, R$ %.2f por mês (%s).", item.Description, amount, item.Category),
+		})
+	}
+	return insights, nil
+}
+
+// budgetPace flags expense budgets consumed faster than the month elapses.
+func (u *AgentInsights) budgetPace(ctx context.Context, now time.Time) ([]domain.Insight, error) {
+	status, err := u.financialRepo.GetBudgetStatus(ctx, int(now.Month()), now.Year())
+	if err != nil {
+		return nil, err
+	}
+
+	daysInMonth := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	elapsed := float64(now.Day()) / float64(daysInMonth)
+
+	var insights []domain.Insight
+	for _, cat := range status.Categories {
+		if cat.Estimated >= 0 {
+			continue
+		}
+		used := math.Abs(cat.Actual) / math.Abs(cat.Estimated)
+		if used < elapsed+insightBudgetPaceMargin {
+			continue
+		}
+		insights = append(insights, domain.Insight{
+			Kind:    domain.InsightBudgetPace,
+			Subject: cat.Name,
+			Amount:  math.Abs(cat.Actual),
+			Message: fmt.Sprintf("Você já usou %.0f%% do orçamento de %s, com %.0f%% do mês decorrido.", used*100, cat.Name, elapsed*100),
+		})
+	}
+	return insights, nil
+}
+
+func (u *AgentInsights) upcomingInvoices(ctx context.Context, now time.Time) ([]domain.Insight, error) {
+	summary, err := u.financialRepo.GetCreditCardsSummary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	horizon := today.AddDate(0, 0, insightInvoiceHorizonDays)
+
+	var insights []domain.Insight
+	for _, card := range summary.Cards {
+		if card.NextDueAmount < insightLargeInvoiceAmount {
+			continue
+		}
+		due, err := time.Parse("2006-01-02", card.NextDueDate)
+		if err != nil || due.Before(today) || due.After(horizon) {
+			continue
+		}
+		insights = append(insights, domain.Insight{
+			Kind:    domain.InsightUpcomingInvoice,
+			Subject: card.Name,
+			Amount:  card.NextDueAmount,
+			Message: fmt.Sprintf("A fatura do cartão %s, de R$ %.2f, vence em %s.", card.Name, card.NextDueAmount, due.Format("02/01")),
+		})
+	}
+	return insights, nil
+}
+
+// saveInsights stores the signals as short-lived insight memories, so the agent
+// can bring them up in the next conversations. Returns how many were saved.
+func (u *AgentInsights) saveInsights(ctx context.Context, userID string, insights []domain.Insight, now time.Time) int {
+	expiresAt := now.AddDate(0, 0, insightMemoryTTLDays)
+	saved := 0
+	for _, insight := range insights {
+		memory := domain.NewAgentMemory(userID, domain.MemoryTypeInsight, insight.Message, domain.MemorySourceDerived)
+		memory.Metadata["kind"] = string(insight.Kind)
+		memory.Metadata["subject"] = insight.Subject
+		memory.Metadata["amount"] = insight.Amount
+		memory.ExpiresAt = &expiresAt
+
+		if _, err := u.memoryRepo.Save(ctx, memory); err != nil {
+			log.Error("error saving insight memory", log.String("user_id", userID), log.Err(err))
+			continue
+		}
+		saved++
+	}
+	return saved
+}
+
+// digestText asks the phraser for the push text, falling back to the signals
+// themselves when there is no phraser, the AI quota is over or the call fails.
+func (u *AgentInsights) digestText(ctx context.Context, insights []domain.Insight) string {
+	fallback := insights[0].Message
+	if len(insights) > 1 {
+		fallback = fmt.Sprintf("%s E mais %d alerta(s) no app.", fallback, len(insights)-1)
+	}
+
+	if u.phraser == nil {
+		return fallback
+	}
+	if u.aiQuota != nil {
+		if err := u.aiQuota.Check(ctx); err != nil {
+			return fallback
+		}
+	}
+
+	resp, err := u.phraser.Summarize(ctx, buildInsightsPrompt(insights))
+	if err != nil || strings.TrimSpace(resp.Content) == "" {
+		log.WarnContext(ctx, "insight digest phrasing failed, using the signals text", log.Err(err))
+		return fallback
+	}
+
+	if u.aiQuota != nil {
+		u.aiQuota.Record(ctx, domain.AIFeatureAgentInsights, domain.AIUsage{
+			Requests:     1,
+			InputTokens:  int64(resp.InputTokens),
+			OutputTokens: int64(resp.OutputTokens),
+		})
+	}
+
+	return strings.TrimSpace(resp.Content)
+}
+
+func (u *AgentInsights) sendDigest(ctx context.Context, userID string, body string, result *InsightJobResult) {
+	devices, err := u.deviceRepo.FindByUserIDs(ctx, []string{userID})
+	if err != nil {
+		log.Error("error finding devices for insight digest", log.String("user_id", userID), log.Err(err))
+		result.PushFailed++
+		return
+	}
+	if len(devices) == 0 {
+		return
+	}
+
+	tokens := make([]string, 0, len(devices))
+	for _, device := range devices {
+		tokens = append(tokens, device.ExpoPushToken)
+	}
+
+	sendResult, err := u.pushSender.Send(ctx, tokens, insightPushTitle, body)
+	if err != nil {
+		log.Error("error sending insight digest", log.String("user_id", userID), log.Err(err))
+		result.PushFailed++
+		return
+	}
+
+	result.PushSent += sendResult.SuccessCount
+	result.PushFailed += sendResult.FailureCount
+
+	if len(sendResult.InvalidTokens) > 0 {
+		if err := u.deviceRepo.DeleteByTokens(ctx, sendResult.InvalidTokens); err != nil {
+			log.Error("error deleting invalid tokens", log.Err(err))
+		}
+	}
+}
+
+func buildInsightsPrompt(insights []domain.Insight) string {
+	var sb strings.Builder
+	sb.WriteString(`Escreva uma notificação curta (no máximo 2 frases, até 180 caracteres) para o usuário de um app de finanças pessoais,
+resumindo os alertas da semana abaixo. Use um tom amigável, em português brasileiro, sem inventar valores nem dar ordens.
+Responda apenas com o texto da notificação.
+
+ALERTAS:
+`)
+	for _, insight := range insights {
+		sb.WriteString("- ")
+		sb.WriteString(insight.Message)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
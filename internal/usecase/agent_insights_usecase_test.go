@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/push"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var insightsNow = time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+// setupBusyFinances mocks a month with one signal of each kind: a grocery spike,
+// a new streaming subscription, the grocery budget ahead of pace and a large
+// invoice due in five days.
+func setupBusyFinances(fin *MockInsightFinancialRepository) {
+	fin.On("GetSpendingBreakdown", 3, 2026).Return(domain.AgentSpendingBreakdown{Categories: []domain.AgentCategoryItem{
+		{ID: "cat-food", Name: "Mercado", Amount: 900},
+		{ID: "cat-fun", Name: "Lazer", Amount: 50},
+		{ID: "cat-salary", Name: "Salário", Amount: 5000, IsIncome: true},
+	}}, nil)
+	for _, month := range [][2]int{{2, 2026}, {1, 2026}, {12, 2025}} {
+		fin.On("GetSpendingBreakdown", month[0], month[1]).Return(domain.AgentSpendingBreakdown{Categories: []domain.AgentCategoryItem{
+			{ID: "cat-food", Name: "Mercado", Amount: 400},
+			{ID: "cat-fun", Name: "Lazer", Amount: 40},
+			{ID: "cat-salary", Name: "Salário", Amount: 5000, IsIncome: true},
+		}}, nil)
+	}
+	fin.On("GetRecurringSummary").Return(domain.AgentRecurringSummary{Items: []domain.AgentRecurringItem{
+		{Description: "Aluguel", Amount: -2000, Category: "Moradia", StartDate: "2025-01-05"},
+		{Description: "Netflix", Amount: -55.9, Category: "Lazer", StartDate: "2026-03-05"},
+		{Description: "Salário", Amount: 5000, Category: "Salário", StartDate: "2026-03-06"},
+	}}, nil)
+	fin.On("GetBudgetStatus", 3, 2026).Return(domain.AgentBudgetStatus{Categories: []domain.AgentBudgetItem{
+		{Name: "Mercado", Estimated: -1500, Actual: -900},
+		{Name: "Lazer", Estimated: -500, Actual: -50},
+		{Name: "Salário", Estimated: 5000, Actual: 5000},
+	}}, nil)
+	fin.On("GetCreditCardsSummary").Return(domain.AgentCreditCardsSummary{Cards: []domain.AgentCreditCardItem{
+		{Name: "Nubank", NextDueDate: "2026-03-15", NextDueAmount: 2300},
+		{Name: "Inter", NextDueDate: "2026-03-12", NextDueAmount: 300},
+		{Name: "Itaú", NextDueDate: "2026-04-20", NextDueAmount: 5000},
+	}}, nil)
+}
+
+func setupQuietFinances(fin *MockInsightFinancialRepository) {
+	fin.On("GetSpendingBreakdown", mock.Anything, mock.Anything).Return(domain.AgentSpendingBreakdown{}, nil)
+	fin.On("GetRecurringSummary").Return(domain.AgentRecurringSummary{}, nil)
+	fin.On("GetBudgetStatus", mock.Anything, mock.Anything).Return(domain.AgentBudgetStatus{}, nil)
+	fin.On("GetCreditCardsSummary").Return(domain.AgentCreditCardsSummary{}, nil)
+}
+
+func TestAgentInsights_ComputeInsights(t *testing.T) {
+	fin := new(MockInsightFinancialRepository)
+	setupBusyFinances(fin)
+	insights := NewAgentInsights(nil, fin, nil, nil, nil, nil, nil)
+
+	result, err := insights.ComputeInsights(context.Background(), insightsNow)
+
+	require.NoError(t, err)
+	require.Len(t, result, 4)
+	assert.Equal(t, domain.Insight{
+		Kind:    domain.InsightSpendingSpike,
+		Subject: "Mercado",
+		Amount:  900,
+		Message: "Os gastos com Mercado já somam R$ 900.00 neste mês, 125% acima da média dos últimos 3 meses (R$ 400.00).",
+	}, result[0])
+	assert.Equal(t, domain.InsightNewRecurring, result[1].Kind)
+	assert.Equal(t, "Netflix", result[1].Subject)
+	assert.Equal(t, 55.9, result[1].Amount)
+	assert.Equal(t, domain.InsightBudgetPace, result[2].Kind)
+	assert.Equal(t, "Mercado", result[2].Subject)
+	assert.Equal(t, domain.InsightUpcomingInvoice, result[3].Kind)
+	assert.Equal(t, "Nubank", result[3].Subject)
+	assert.Equal(t, "A fatura do cartão Nubank, de R$ 2300.00, vence em 15/03.", result[3].Message)
+}
+
+func TestAgentInsights_SendWeeklyDigests(t *testing.T) {
+	log.Initialize()
+	notAfter := insightsNow.Add(-7 * 24 * time.Hour)
+	devices := []domain.Device{{ID: uuid.New(), UserID: "user-1", ExpoPushToken: "token-1"}}
+	fallbackText := "Os gastos com Mercado já somam R$ 900.00 neste mês, 125% acima da média dos últimos 3 meses (R$ 400.00). E mais 3 alerta(s) no app."
+
+	type mocks struct {
+		prefs   *MockInsightPreferenceRepository
+		fin     *MockInsightFinancialRepository
+		memory  *MockAgentMemoryRepository
+		devices *MockPushDeviceRepository
+		sender  *MockPushSender
+		phraser *MockAgentGateway
+		quota   *MockAIQuotaGuard
+	}
+
+	tests := map[string]struct {
+		withPhraser    bool
+		mockSetup      func(m mocks)
+		expectedResult InsightJobResult
+		expectedErr    bool
+	}{
+		"should store insights as expiring memories and push the digest": {
+			mockSetup: func(m mocks) {
+				m.prefs.On("FindDue", notAfter).Return([]domain.InsightPreference{{UserID: "user-1", Enabled: true}}, nil)
+				setupBusyFinances(m.fin)
+				m.prefs.On("ClaimDigest", "user-1", insightsNow, notAfter).Return(true, nil)
+				m.memory.On("Save", mock.MatchedBy(func(memory domain.AgentMemory) bool {
+					return memory.UserID == "user-1" &&
+						memory.Type == domain.MemoryTypeInsight &&
+						memory.Source == domain.MemorySourceDerived &&
+						memory.ExpiresAt != nil && memory.ExpiresAt.Equal(insightsNow.AddDate(0, 0, 14))
+				})).Return(domain.AgentMemory{}, nil).Times(4)
+				m.devices.On("FindByUserIDs", []string{"user-1"}).Return(devices, nil)
+				m.sender.On("Send", []string{"token-1"}, "Seu resumo semanal", fallbackText).Return(push.SendResult{SuccessCount: 1}, nil)
+			},
+			expectedResult: InsightJobResult{UsersDue: 1, DigestsSent: 1, InsightsCreated: 4, PushSent: 1},
+		},
+		"should let the agent phrase the digest within the AI quota": {
+			withPhraser: true,
+			mockSetup: func(m mocks) {
+				m.prefs.On("FindDue", notAfter).Return([]domain.InsightPreference{{UserID: "user-1", Enabled: true}}, nil)
+				setupBusyFinances(m.fin)
+				m.prefs.On("ClaimDigest", "user-1", insightsNow, notAfter).Return(true, nil)
+				m.memory.On("Save", mock.Anything).Return(domain.AgentMemory{}, nil)
+				m.quota.On("Check").Return(nil)
+				m.phraser.On("Summarize", mock.MatchedBy(func(prompt string) bool {
+					return strings.Contains(prompt, "- Nova cobrança recorrente: Netflix, R$ 55.90 por mês (Lazer).")
+				})).Return(domain.AgentGatewayResponse{Content: " Mercado acima da média e fatura do Nubank chegando. ", InputTokens: 200, OutputTokens: 20}, nil)
+				m.quota.On("Record", domain.AIFeatureAgentInsights, domain.AIUsage{Requests: 1, InputTokens: 200, OutputTokens: 20}).Return()
+				m.devices.On("FindByUserIDs", []string{"user-1"}).Return(devices, nil)
+				m.sender.On("Send", []string{"token-1"}, "Seu resumo semanal", "Mercado acima da média e fatura do Nubank chegando.").Return(push.SendResult{SuccessCount: 1}, nil)
+			},
+			expectedResult: InsightJobResult{UsersDue: 1, DigestsSent: 1, InsightsCreated: 4, PushSent: 1},
+		},
+		"should write the digest from the signals when over the AI quota": {
+			withPhraser: true,
+			mockSetup: func(m mocks) {
+				m.prefs.On("FindDue", notAfter).Return([]domain.InsightPreference{{UserID: "user-1", Enabled: true}}, nil)
+				setupBusyFinances(m.fin)
+				m.prefs.On("ClaimDigest", "user-1", insightsNow, notAfter).Return(true, nil)
+				m.memory.On("Save", mock.Anything).Return(domain.AgentMemory{}, nil)
+				m.quota.On("Check").Return(ErrAIQuotaExceeded)
+				m.devices.On("FindByUserIDs", []string{"user-1"}).Return(devices, nil)
+				m.sender.On("Send", []string{"token-1"}, "Seu resumo semanal", fallbackText).Return(push.SendResult{SuccessCount: 1}, nil)
+			},
+			expectedResult: InsightJobResult{UsersDue: 1, DigestsSent: 1, InsightsCreated: 4, PushSent: 1},
+		},
+		"should not send a second digest in the same week": {
+			mockSetup: func(m mocks) {
+				m.prefs.On("FindDue", notAfter).Return([]domain.InsightPreference{{UserID: "user-1", Enabled: true}}, nil)
+				setupBusyFinances(m.fin)
+				m.prefs.On("ClaimDigest", "user-1", insightsNow, notAfter).Return(false, nil)
+			},
+			expectedResult: InsightJobResult{UsersDue: 1, Skipped: 1},
+		},
+		"should claim the week without pushing when there are no signals": {
+			mockSetup: func(m mocks) {
+				m.prefs.On("FindDue", notAfter).Return([]domain.InsightPreference{{UserID: "user-1", Enabled: true}}, nil)
+				setupQuietFinances(m.fin)
+				m.prefs.On("ClaimDigest", "user-1", insightsNow, notAfter).Return(true, nil)
+			},
+			expectedResult: InsightJobResult{UsersDue: 1, Skipped: 1},
+		},
+		"should count users whose signals fail without claiming their week": {
+			mockSetup: func(m mocks) {
+				m.prefs.On("FindDue", notAfter).Return([]domain.InsightPreference{{UserID: "user-1", Enabled: true}}, nil)
+				m.fin.On("GetSpendingBreakdown", mock.Anything, mock.Anything).Return(domain.AgentSpendingBreakdown{}, errors.New("db down"))
+			},
+			expectedResult: InsightJobResult{UsersDue: 1, Failed: 1},
+		},
+		"should return error when due users cannot be listed": {
+			mockSetup: func(m mocks) {
+				m.prefs.On("FindDue", notAfter).Return([]domain.InsightPreference{}, errors.New("db down"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := mocks{
+				prefs:   new(MockInsightPreferenceRepository),
+				fin:     new(MockInsightFinancialRepository),
+				memory:  new(MockAgentMemoryRepository),
+				devices: new(MockPushDeviceRepository),
+				sender:  new(MockPushSender),
+				phraser: new(MockAgentGateway),
+				quota:   new(MockAIQuotaGuard),
+			}
+			tt.mockSetup(m)
+
+			var phraser InsightPhraser
+			if tt.withPhraser {
+				phraser = m.phraser
+			}
+			insights := NewAgentInsights(m.prefs, m.fin, m.memory, m.devices, m.sender, phraser, m.quota)
+
+			result, err := insights.SendWeeklyDigests(context.Background(), insightsNow)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+			m.prefs.AssertExpectations(t)
+			m.memory.AssertExpectations(t)
+			m.sender.AssertExpectations(t)
+			m.phraser.AssertExpectations(t)
+			m.quota.AssertExpectations(t)
+			if result.Failed > 0 {
+				m.prefs.AssertNotCalled(t, "ClaimDigest", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAgentInsights_Preference(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1"})
+
+	t.Run("should report a user who never opted in as disabled", func(t *testing.T) {
+		prefs := new(MockInsightPreferenceRepository)
+		prefs.On("FindByUserID", "user-1").Return(domain.InsightPreference{}, repository.ErrInsightPreferenceNotFound)
+
+		pref, err := NewAgentInsights(prefs, nil, nil, nil, nil, nil, nil).GetPreference(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.InsightPreference{UserID: "user-1"}, pref)
+	})
+
+	t.Run("should save the opt-in of the context user", func(t *testing.T) {
+		prefs := new(MockInsightPreferenceRepository)
+		prefs.On("Upsert", domain.InsightPreference{UserID: "user-1", Enabled: true}).
+			Return(domain.InsightPreference{UserID: "user-1", Enabled: true}, nil)
+
+		pref, err := NewAgentInsights(prefs, nil, nil, nil, nil, nil, nil).SetPreference(ctx, true)
+
+		require.NoError(t, err)
+		assert.True(t, pref.Enabled)
+		prefs.AssertExpectations(t)
+	})
+
+	t.Run("should require an authenticated user", func(t *testing.T) {
+		_, err := NewAgentInsights(nil, nil, nil, nil, nil, nil, nil).SetPreference(context.Background(), true)
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
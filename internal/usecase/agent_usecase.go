@@ -3,7 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,6 +15,22 @@ import (
 
 const maxConversationTitleRunes = 80
 
+const (
+	memoryRecentValidation = 30 * 24 * time.Hour
+	memoryRecencyBonus     = 0.05
+)
+
+// memoryTypeWeights keeps goals and constraints ahead of other memories when the
+// message is not related to any of them.
+var memoryTypeWeights = map[domain.AgentMemoryType]float64{
+	domain.MemoryTypeGoal:       0.3,
+	domain.MemoryTypeConstraint: 0.3,
+	domain.MemoryTypeFact:       0.2,
+	domain.MemoryTypeCommitment: 0.2,
+	domain.MemoryTypeLifeEvent:  0.15,
+	domain.MemoryTypeInsight:    0.1,
+}
+
 // --- Interfaces ---
 
 type AgentMemoryRepository interface {
@@ -22,7 +38,8 @@ type AgentMemoryRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (domain.AgentMemory, error)
 	FindByUserID(ctx context.Context, userID string) ([]domain.AgentMemory, error)
 	FindByUserIDAndType(ctx context.Context, userID string, memType domain.AgentMemoryType) ([]domain.AgentMemory, error)
-	SearchByContent(ctx context.Context, userID string, query string) ([]domain.AgentMemory, error)
+	SearchSimilar(ctx context.Context, userID string, query string, limit int) ([]domain.AgentMemory, error)
+	FindRelevant(ctx context.Context, userID string, query string) ([]domain.ScoredMemory, error)
 	Update(ctx context.Context, memory domain.AgentMemory) (domain.AgentMemory, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	CountByUserID(ctx context.Context, userID string) (int64, error)
@@ -36,6 +53,7 @@ type AgentConversationRepository interface {
 	FindByUserID(ctx context.Context, userID string) ([]domain.AgentConversation, error)
 	SaveMessage(ctx context.Context, msg domain.AgentMessage) (domain.AgentMessage, error)
 	UpdateTitle(ctx context.Context, id uuid.UUID, title string) error
+	UpdateSummary(ctx context.Context, id uuid.UUID, summary string, summarizedMessages int) error
 	DeleteExpired(ctx context.Context) (int64, error)
 }
 
@@ -45,6 +63,9 @@ type AgentAuditRepository interface {
 
 type AgentGateway interface {
 	Chat(ctx context.Context, systemPrompt string, userMessage string, history []domain.AgentMessage) (domain.AgentGatewayResponse, error)
+	ChatStream(ctx context.Context, systemPrompt string, userMessage string, history []domain.AgentMessage, emit domain.AgentStreamEmitter) (domain.AgentGatewayResponse, error)
+	// Summarize runs a single model call without tools.
+	Summarize(ctx context.Context, prompt string) (domain.AgentGatewayResponse, error)
 }
 
 // --- Input/Output DTOs ---
@@ -55,8 +76,9 @@ type AgentChatInput struct {
 }
 
 type AgentChatOutput struct {
-	ConversationID uuid.UUID `json:"conversation_id"`
-	Response       string    `json:"response"`
+	ConversationID uuid.UUID            `json:"conversation_id"`
+	Response       string               `json:"response"`
+	PendingActions []domain.AgentAction `json:"pending_actions,omitempty"`
 }
 
 type SaveMemoryInput struct {
@@ -71,20 +93,31 @@ type AgentUseCase struct {
 	memoryRepo AgentMemoryRepository
 	convRepo   AgentConversationRepository
 	auditRepo  AgentAuditRepository
+	actionRepo AgentActionRepository
 	gateway    AgentGateway
+	aiQuota    AIQuotaGuard
+
+	contextWindow AgentContextWindow
 }
 
 func NewAgentUseCase(
 	memoryRepo AgentMemoryRepository,
 	convRepo AgentConversationRepository,
 	auditRepo AgentAuditRepository,
+	actionRepo AgentActionRepository,
 	gateway AgentGateway,
+	aiQuota AIQuotaGuard,
+	contextWindow AgentContextWindow,
 ) *AgentUseCase {
 	return &AgentUseCase{
 		memoryRepo: memoryRepo,
 		convRepo:   convRepo,
 		auditRepo:  auditRepo,
+		actionRepo: actionRepo,
 		gateway:    gateway,
+		aiQuota:    aiQuota,
+
+		contextWindow: contextWindow,
 	}
 }
 
@@ -96,74 +129,177 @@ func (u *AgentUseCase) Chat(ctx context.Context, input AgentChatInput) (AgentCha
 		return AgentChatOutput{}, domain.ErrUnauthorized
 	}
 
+	conv, systemPrompt, history, err := u.startChat(ctx, userID, input)
+	if err != nil {
+		return AgentChatOutput{}, err
+	}
+
+	// 4. Call the LLM gateway
+	gatewayResp, err := u.gateway.Chat(ctx, systemPrompt, input.Message, history)
+	if err != nil {
+		return AgentChatOutput{}, fmt.Errorf("agent gateway error: %w", err)
+	}
+
+	actions, err := u.finishChat(ctx, userID, &conv, input.Message, gatewayResp)
+	if err != nil {
+		return AgentChatOutput{}, err
+	}
+
+	return AgentChatOutput{
+		ConversationID: conv.ID,
+		Response:       gatewayResp.Content,
+		PendingActions: actions,
+	}, nil
+}
+
+// ChatStream runs the same flow as Chat, forwarding the gateway events to emit and
+// closing with a done event. If the client disconnects mid-stream, the partial
+// response is still persisted and audited.
+func (u *AgentUseCase) ChatStream(ctx context.Context, input AgentChatInput, emit domain.AgentStreamEmitter) (AgentChatOutput, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return AgentChatOutput{}, domain.ErrUnauthorized
+	}
+
+	conv, systemPrompt, history, err := u.startChat(ctx, userID, input)
+	if err != nil {
+		return AgentChatOutput{}, err
+	}
+
+	gatewayResp, err := u.gateway.ChatStream(ctx, systemPrompt, input.Message, history, emit)
+	if err != nil && ctx.Err() == nil {
+		return AgentChatOutput{}, fmt.Errorf("agent gateway error: %w", err)
+	}
+
+	// The request context is already cancelled when the client goes away.
+	actions, err := u.finishChat(context.WithoutCancel(ctx), userID, &conv, input.Message, gatewayResp)
+	if err != nil {
+		return AgentChatOutput{}, err
+	}
+
+	if ctx.Err() != nil {
+		return AgentChatOutput{}, ctx.Err()
+	}
+
+	emit(domain.AgentStreamEvent{
+		Type:           domain.AgentStreamEventDone,
+		ConversationID: &conv.ID,
+		InputTokens:    gatewayResp.InputTokens,
+		OutputTokens:   gatewayResp.OutputTokens,
+		PendingActions: actions,
+	})
+
+	return AgentChatOutput{
+		ConversationID: conv.ID,
+		Response:       gatewayResp.Content,
+		PendingActions: actions,
+	}, nil
+}
+
+// startChat checks the AI quota, resolves the conversation and builds the system
+// prompt and the history window for a new turn.
+func (u *AgentUseCase) startChat(ctx context.Context, userID string, input AgentChatInput) (domain.AgentConversation, string, []domain.AgentMessage, error) {
+	if u.aiQuota != nil {
+		if err := u.aiQuota.Check(ctx); err != nil {
+			return domain.AgentConversation{}, "", nil, err
+		}
+	}
+
 	// 1. Resolve or create conversation
 	var conv domain.AgentConversation
 	if input.ConversationID != nil {
 		found, err := u.convRepo.FindByID(ctx, *input.ConversationID)
 		if err != nil {
-			return AgentChatOutput{}, err
+			return domain.AgentConversation{}, "", nil, err
 		}
 		if found.UserID != userID {
-			return AgentChatOutput{}, domain.ErrUnauthorized
+			return domain.AgentConversation{}, "", nil, domain.ErrUnauthorized
 		}
 		conv = found
 	} else {
 		newConv := domain.NewAgentConversation(userID)
 		saved, err := u.convRepo.Save(ctx, newConv)
 		if err != nil {
-			return AgentChatOutput{}, err
+			return domain.AgentConversation{}, "", nil, err
 		}
 		conv = saved
 	}
 
-	// 2. Load memories for system prompt
-	memories, err := u.memoryRepo.FindByUserID(ctx, userID)
+	// 2. Load memories for system prompt, scored against the new message
+	memories, err := u.memoryRepo.FindRelevant(ctx, userID, input.Message)
 	if err != nil {
-		return AgentChatOutput{}, err
+		return domain.AgentConversation{}, "", nil, err
 	}
 
-	selectedMemories := selectMemoriesForPrompt(memories)
-	systemPrompt := buildSystemPrompt(selectedMemories)
+	// 3. Bound the history, summarizing older turns when over budget
+	history := u.buildHistory(ctx, &conv)
 
-	// 3. Call the LLM gateway
-	gatewayResp, err := u.gateway.Chat(ctx, systemPrompt, input.Message, conv.Messages)
-	if err != nil {
-		return AgentChatOutput{}, fmt.Errorf("agent gateway error: %w", err)
-	}
+	selectedMemories := selectMemoriesForPrompt(memories, time.Now())
+	systemPrompt := withConversationSummary(buildSystemPrompt(selectedMemories), conv.Summary)
+	return conv, systemPrompt, history, nil
+}
 
+// finishChat titles the conversation, persists the turn, stores the actions proposed
+// by the agent and writes the audit record. It returns the stored actions.
+func (u *AgentUseCase) finishChat(
+	ctx context.Context,
+	userID string,
+	conv *domain.AgentConversation,
+	message string,
+	gatewayResp domain.AgentGatewayResponse,
+) ([]domain.AgentAction, error) {
 	if conv.Title == "" {
-		if t := deriveConversationTitle(input.Message); t != "" {
+		if t := deriveConversationTitle(message); t != "" {
 			if err := u.convRepo.UpdateTitle(ctx, conv.ID, t); err != nil {
-				return AgentChatOutput{}, err
+				return nil, err
 			}
 			conv.Title = t
 		}
 	}
 
-	// 4. Persist messages
-	userMsg := domain.NewAgentMessage(conv.ID, "user", input.Message)
+	// 5. Persist messages
+	userMsg := domain.NewAgentMessage(conv.ID, "user", message)
 	_, _ = u.convRepo.SaveMessage(ctx, userMsg)
 
 	assistantMsg := domain.NewAgentMessage(conv.ID, "assistant", gatewayResp.Content)
 	_, _ = u.convRepo.SaveMessage(ctx, assistantMsg)
 
-	// 5. Audit log (LGPD Art. 37)
+	// 6. Store proposed actions; they only run after the user confirms them
+	actions := make([]domain.AgentAction, 0, len(gatewayResp.ProposedActions))
+	for _, action := range gatewayResp.ProposedActions {
+		action.UserID = userID
+		action.ConversationID = &conv.ID
+		action.Provider = gatewayResp.Provider
+		action.Region = gatewayResp.Region
+		saved, err := u.actionRepo.Save(ctx, action)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, saved)
+	}
+
+	// 7. Audit log (LGPD Art. 37)
 	auditRecord := domain.AgentAuditRecord{
 		UserID:         userID,
 		ConversationID: &conv.ID,
 		ToolsCalled:    gatewayResp.ToolsCalled,
 		InputTokens:    gatewayResp.InputTokens,
 		OutputTokens:   gatewayResp.OutputTokens,
-		Provider:       "vertex_ai",
-		Region:         "southamerica-east1",
+		Provider:       gatewayResp.Provider,
+		Region:         gatewayResp.Region,
 		CreatedAt:      time.Now(),
 	}
 	_ = u.auditRepo.Log(ctx, auditRecord)
 
-	return AgentChatOutput{
-		ConversationID: conv.ID,
-		Response:       gatewayResp.Content,
-	}, nil
+	if u.aiQuota != nil {
+		u.aiQuota.Record(ctx, domain.AIFeatureAgent, domain.AIUsage{
+			Requests:     1,
+			InputTokens:  int64(gatewayResp.InputTokens),
+			OutputTokens: int64(gatewayResp.OutputTokens),
+		})
+	}
+
+	return actions, nil
 }
 
 // --- Memory Management (Agent Tools) ---
@@ -180,7 +316,7 @@ func (u *AgentUseCase) SaveMemory(ctx context.Context, input SaveMemoryInput) (d
 	}
 
 	// PII check
-	if containsPII(input.Content) {
+	if domain.ContainsPII(input.Content) {
 		return domain.AgentMemory{}, domain.ErrAgentPIIDetected
 	}
 
@@ -225,7 +361,7 @@ func (u *AgentUseCase) UpdateMemory(ctx context.Context, id uuid.UUID, content s
 		return domain.AgentMemory{}, domain.ErrUnauthorized
 	}
 
-	if containsPII(content) {
+	if domain.ContainsPII(content) {
 		return domain.AgentMemory{}, domain.ErrAgentPIIDetected
 	}
 
@@ -244,7 +380,7 @@ func (u *AgentUseCase) SearchMemories(ctx context.Context, query string) ([]doma
 	if query == "" {
 		return u.memoryRepo.FindByUserID(ctx, userID)
 	}
-	return u.memoryRepo.SearchByContent(ctx, userID, query)
+	return u.memoryRepo.SearchSimilar(ctx, userID, query, domain.MemorySearchLimit)
 }
 
 func (u *AgentUseCase) GetMemoriesByType(ctx context.Context, memType domain.AgentMemoryType) ([]domain.AgentMemory, error) {
@@ -284,21 +420,6 @@ func (u *AgentUseCase) PurgeExpiredConversations(ctx context.Context) (int64, er
 
 // --- Internal Helpers ---
 
-var (
-	cpfRegex   = regexp.MustCompile(`\d{3}\.?\d{3}\.?\d{3}-?\d{2}`)
-	emailRegex = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
-)
-
-func containsPII(content string) bool {
-	if cpfRegex.MatchString(content) {
-		return true
-	}
-	if emailRegex.MatchString(content) {
-		return true
-	}
-	return false
-}
-
 func deriveConversationTitle(message string) string {
 	s := strings.TrimSpace(message)
 	s = strings.Join(strings.Fields(s), " ")
@@ -312,75 +433,38 @@ func deriveConversationTitle(message string) string {
 	return string(runes[:maxConversationTitleRunes-1]) + "…"
 }
 
-func selectMemoriesForPrompt(all []domain.AgentMemory) []domain.AgentMemory {
-	if len(all) <= domain.MaxMemoriesPerPrompt {
-		return all
+// selectMemoriesForPrompt ranks the memories by similarity to the message plus a
+// weight per type and a bonus for recently validated ones, and keeps the best
+// MaxMemoriesPerPrompt. The risk profile is always included.
+func selectMemoriesForPrompt(scored []domain.ScoredMemory, now time.Time) []domain.AgentMemory {
+	type ranked struct {
+		memory domain.AgentMemory
+		score  float64
 	}
 
 	var selected []domain.AgentMemory
-
-	// Always include: risk_profile (1)
-	for _, m := range all {
-		if m.Type == domain.MemoryTypeRiskProfile {
-			selected = append(selected, m)
-			break
+	candidates := make([]ranked, 0, len(scored))
+	for _, s := range scored {
+		if s.Memory.Type == domain.MemoryTypeRiskProfile && len(selected) == 0 {
+			selected = append(selected, s.Memory)
+			continue
 		}
-	}
-
-	// Active goals, sorted by priority (max 3)
-	goalCount := 0
-	for _, m := range all {
-		if m.Type == domain.MemoryTypeGoal && goalCount < 3 {
-			selected = append(selected, m)
-			goalCount++
-		}
-	}
-
-	// Life events within 6 months (max 2)
-	eventCount := 0
-	sixMonths := time.Now().AddDate(0, 6, 0)
-	for _, m := range all {
-		if m.Type == domain.MemoryTypeLifeEvent && eventCount < 2 {
-			if m.ExpiresAt == nil || m.ExpiresAt.Before(sixMonths) {
-				selected = append(selected, m)
-				eventCount++
-			}
+		score := s.Similarity + memoryTypeWeights[s.Memory.Type]
+		if now.Sub(s.Memory.LastValidated) <= memoryRecentValidation {
+			score += memoryRecencyBonus
 		}
+		candidates = append(candidates, ranked{memory: s.Memory, score: score})
 	}
 
-	// Facts and Constraints (max 5)
-	factCount := 0
-	for _, m := range all {
-		if (m.Type == domain.MemoryTypeFact || m.Type == domain.MemoryTypeConstraint) && factCount < 5 {
-			selected = append(selected, m)
-			factCount++
-		}
-	}
-
-	// Insights, most recently validated (max 3)
-	insightCount := 0
-	for _, m := range all {
-		if m.Type == domain.MemoryTypeInsight && insightCount < 3 {
-			selected = append(selected, m)
-			insightCount++
-		}
-	}
-
-	// Commitments with check_date this month (max 2)
-	commitCount := 0
-	now := time.Now()
-	for _, m := range all {
-		if m.Type == domain.MemoryTypeCommitment && commitCount < 2 {
-			_ = now
-			selected = append(selected, m)
-			commitCount++
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	for _, c := range candidates {
+		if len(selected) == domain.MaxMemoriesPerPrompt {
+			break
 		}
+		selected = append(selected, c.memory)
 	}
-
-	if len(selected) > domain.MaxMemoriesPerPrompt {
-		selected = selected[:domain.MaxMemoriesPerPrompt]
-	}
-
 	return selected
 }
 
@@ -399,6 +483,14 @@ FERRAMENTAS FINANCEIRAS DISPONÍVEIS — USE SEMPRE QUE NECESSÁRIO:
 - get_movements: lista de transações do período
 - get_recurring_expenses: despesas e receitas recorrentes fixas
 - get_budget_status: orçamento planejado vs realizado por categoria
+- find_recurring_charges: cobranças repetidas ainda não cadastradas como recorrentes, assinaturas esquecidas e aumentos de preço
+
+AÇÕES (exigem confirmação do usuário no app):
+- propose_create_movement: registrar uma transação
+- propose_pay_movement: marcar uma transação como paga
+- propose_create_estimate: definir o orçamento de uma categoria no mês
+- propose_create_transfer: transferir entre carteiras
+As ações propostas ficam pendentes até o usuário confirmar. Nunca diga que uma ação já foi executada.
 
 REGRAS:
 1. Sempre responda em português brasileiro.
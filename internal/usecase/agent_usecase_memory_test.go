@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scoredMemory(memType domain.AgentMemoryType, content string, similarity float64, validated time.Time) domain.ScoredMemory {
+	memory := domain.NewAgentMemory("user-1", memType, content, domain.MemorySourceExplicit)
+	memory.LastValidated = validated
+	return domain.ScoredMemory{Memory: memory, Similarity: similarity}
+}
+
+func contents(memories []domain.AgentMemory) []string {
+	out := make([]string, len(memories))
+	for i, m := range memories {
+		out[i] = m.Content
+	}
+	return out
+}
+
+func TestSelectMemoriesForPrompt(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -3)
+	old := now.AddDate(0, -6, 0)
+
+	t.Run("should put memories related to the message ahead of the type order", func(t *testing.T) {
+		selected := selectMemoriesForPrompt([]domain.ScoredMemory{
+			scoredMemory(domain.MemoryTypeGoal, "Trocar de carro", 0.05, old),
+			scoredMemory(domain.MemoryTypeInsight, "Gasto com viagens subiu", 0.7, old),
+			scoredMemory(domain.MemoryTypeFact, "Mora de aluguel", 0, old),
+			scoredMemory(domain.MemoryTypeLifeEvent, "Férias na Europa em julho", 0.8, old),
+		}, now)
+
+		assert.Equal(t, []string{
+			"Férias na Europa em julho",
+			"Gasto com viagens subiu",
+			"Trocar de carro",
+			"Mora de aluguel",
+		}, contents(selected))
+	})
+
+	t.Run("should prefer recently validated memories on a tie", func(t *testing.T) {
+		selected := selectMemoriesForPrompt([]domain.ScoredMemory{
+			scoredMemory(domain.MemoryTypeFact, "Fato antigo", 0, old),
+			scoredMemory(domain.MemoryTypeFact, "Fato recente", 0, recent),
+		}, now)
+
+		assert.Equal(t, []string{"Fato recente", "Fato antigo"}, contents(selected))
+	})
+
+	t.Run("should always keep the risk profile and cap the prompt", func(t *testing.T) {
+		scored := make([]domain.ScoredMemory, 0, domain.MaxMemoriesPerPrompt+5)
+		for i := 0; i < domain.MaxMemoriesPerPrompt+4; i++ {
+			scored = append(scored, scoredMemory(domain.MemoryTypeGoal, fmt.Sprintf("Meta %d", i), 0.9, recent))
+		}
+		scored = append(scored, scoredMemory(domain.MemoryTypeRiskProfile, "Conservador", 0, old))
+
+		selected := selectMemoriesForPrompt(scored, now)
+
+		require.Len(t, selected, domain.MaxMemoriesPerPrompt)
+		assert.Equal(t, "Conservador", selected[0].Content)
+	})
+}
+
+func TestAgentUseCase_SearchMemories(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1"})
+
+	t.Run("should search by similarity when there is a query", func(t *testing.T) {
+		memoryRepo := &MockAgentMemoryRepository{}
+		found := []domain.AgentMemory{domain.NewAgentMemory("user-1", domain.MemoryTypeGoal, "Férias na Europa", domain.MemorySourceExplicit)}
+		memoryRepo.On("SearchSimilar", "user-1", "viagem", domain.MemorySearchLimit).Return(found, nil)
+		uc := NewAgentUseCase(memoryRepo, nil, nil, nil, nil, nil, AgentContextWindow{})
+
+		memories, err := uc.SearchMemories(ctx, "viagem")
+
+		require.NoError(t, err)
+		assert.Equal(t, found, memories)
+		memoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("should list all memories without a query", func(t *testing.T) {
+		memoryRepo := &MockAgentMemoryRepository{}
+		memoryRepo.On("FindByUserID", "user-1").Return([]domain.AgentMemory{}, nil)
+		uc := NewAgentUseCase(memoryRepo, nil, nil, nil, nil, nil, AgentContextWindow{})
+
+		_, err := uc.SearchMemories(ctx, "")
+
+		require.NoError(t, err)
+		memoryRepo.AssertNotCalled(t, "SearchSimilar")
+	})
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAgentStreamMocks(userID string) (*MockAgentMemoryRepository, *MockAgentConversationRepository, *MockAgentAuditRepository, domain.AgentConversation) {
+	memoryRepo := new(MockAgentMemoryRepository)
+	convRepo := new(MockAgentConversationRepository)
+	auditRepo := new(MockAgentAuditRepository)
+
+	conv := domain.NewAgentConversation(userID)
+	convRepo.On("Save", mock.Anything).Return(conv, nil)
+	convRepo.On("UpdateTitle", conv.ID, mock.Anything).Return(nil)
+	memoryRepo.On("FindRelevant", userID, mock.Anything).Return([]domain.ScoredMemory{}, nil)
+
+	return memoryRepo, convRepo, auditRepo, conv
+}
+
+func TestAgentUseCase_ChatStream(t *testing.T) {
+	const userID = "user-1"
+
+	t.Run("should forward gateway events, persist the turn and finish with a done event", func(t *testing.T) {
+		memoryRepo, convRepo, auditRepo, conv := newAgentStreamMocks(userID)
+		gateway := new(MockAgentGateway)
+
+		gateway.On("ChatStream", mock.Anything, mock.Anything, "Quanto gastei?", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				emit := args.Get(4).(domain.AgentStreamEmitter)
+				emit(domain.AgentStreamEvent{Type: domain.AgentStreamEventToolStart, Tool: "get_spending_breakdown"})
+				emit(domain.AgentStreamEvent{Type: domain.AgentStreamEventToolEnd, Tool: "get_spending_breakdown"})
+				emit(domain.AgentStreamEvent{Type: domain.AgentStreamEventDelta, Text: "Você gastou "})
+				emit(domain.AgentStreamEvent{Type: domain.AgentStreamEventDelta, Text: "R$ 100"})
+			}).
+			Return(domain.AgentGatewayResponse{
+				Content:      "Você gastou R$ 100",
+				ToolsCalled:  []string{"get_spending_breakdown"},
+				InputTokens:  10,
+				OutputTokens: 5,
+			}, nil)
+		convRepo.On("SaveMessage", mock.Anything, mock.Anything).Return(domain.AgentMessage{}, nil).Twice()
+		auditRepo.On("Log", mock.Anything, mock.MatchedBy(func(r domain.AgentAuditRecord) bool {
+			return r.InputTokens == 10 && r.OutputTokens == 5 && len(r.ToolsCalled) == 1
+		})).Return(nil).Once()
+
+		var events []domain.AgentStreamEvent
+		usecase := NewAgentUseCase(memoryRepo, convRepo, auditRepo, new(MockAgentActionRepository), gateway, nil, AgentContextWindow{})
+		ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: userID})
+
+		output, err := usecase.ChatStream(ctx, AgentChatInput{Message: "Quanto gastei?"}, func(e domain.AgentStreamEvent) {
+			events = append(events, e)
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, conv.ID, output.ConversationID)
+		assert.Len(t, events, 5)
+		done := events[len(events)-1]
+		assert.Equal(t, domain.AgentStreamEventDone, done.Type)
+		assert.Equal(t, conv.ID, *done.ConversationID)
+		assert.Equal(t, 10, done.InputTokens)
+		assert.Equal(t, 5, done.OutputTokens)
+		convRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("should persist the partial response when the client disconnects", func(t *testing.T) {
+		memoryRepo, convRepo, auditRepo, _ := newAgentStreamMocks(userID)
+		gateway := new(MockAgentGateway)
+
+		ctx, cancel := context.WithCancel(authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: userID}))
+		gateway.On("ChatStream", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(domain.AgentGatewayResponse{Content: "Você gas", InputTokens: 10}, context.Canceled)
+
+		notCancelled := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+		convRepo.On("SaveMessage", notCancelled, mock.MatchedBy(func(m domain.AgentMessage) bool {
+			return m.Role == "assistant" && m.Content == "Você gas"
+		})).Return(domain.AgentMessage{}, nil).Once()
+		convRepo.On("SaveMessage", notCancelled, mock.Anything).Return(domain.AgentMessage{}, nil).Once()
+		auditRepo.On("Log", notCancelled, mock.Anything).Return(nil).Once()
+
+		var events []domain.AgentStreamEvent
+		usecase := NewAgentUseCase(memoryRepo, convRepo, auditRepo, new(MockAgentActionRepository), gateway, nil, AgentContextWindow{})
+
+		_, err := usecase.ChatStream(ctx, AgentChatInput{Message: "Quanto gastei?"}, func(e domain.AgentStreamEvent) {
+			events = append(events, e)
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, events)
+		convRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not persist anything when the gateway fails", func(t *testing.T) {
+		memoryRepo, convRepo, auditRepo, _ := newAgentStreamMocks(userID)
+		gateway := new(MockAgentGateway)
+
+		gateway.On("ChatStream", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(domain.AgentGatewayResponse{}, errors.New("quota exceeded"))
+
+		usecase := NewAgentUseCase(memoryRepo, convRepo, auditRepo, new(MockAgentActionRepository), gateway, nil, AgentContextWindow{})
+		ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: userID})
+
+		_, err := usecase.ChatStream(ctx, AgentChatInput{Message: "Oi"}, func(domain.AgentStreamEvent) {})
+
+		assert.Error(t, err)
+		convRepo.AssertNotCalled(t, "SaveMessage", mock.Anything, mock.Anything)
+		auditRepo.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should record the AI usage of the turn", func(t *testing.T) {
+		memoryRepo, convRepo, auditRepo, _ := newAgentStreamMocks(userID)
+		gateway := new(MockAgentGateway)
+		quota := new(MockAIQuotaGuard)
+
+		gateway.On("ChatStream", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(domain.AgentGatewayResponse{Content: "Oi!", InputTokens: 150, OutputTokens: 20}, nil)
+		convRepo.On("SaveMessage", mock.Anything, mock.Anything).Return(domain.AgentMessage{}, nil)
+		auditRepo.On("Log", mock.Anything, mock.Anything).Return(nil)
+		quota.On("Check").Return(nil).Once()
+		quota.On("Record", domain.AIFeatureAgent, domain.AIUsage{Requests: 1, InputTokens: 150, OutputTokens: 20}).Return().Once()
+
+		usecase := NewAgentUseCase(memoryRepo, convRepo, auditRepo, new(MockAgentActionRepository), gateway, quota, AgentContextWindow{})
+		ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: userID})
+
+		_, err := usecase.ChatStream(ctx, AgentChatInput{Message: "Oi"}, func(domain.AgentStreamEvent) {})
+
+		assert.NoError(t, err)
+		quota.AssertExpectations(t)
+	})
+
+	t.Run("should stop before the gateway when the AI quota is exhausted", func(t *testing.T) {
+		memoryRepo, convRepo, auditRepo, _ := newAgentStreamMocks(userID)
+		gateway := new(MockAgentGateway)
+		quota := new(MockAIQuotaGuard)
+		quota.On("Check").Return(ErrAIQuotaExceeded)
+
+		usecase := NewAgentUseCase(memoryRepo, convRepo, auditRepo, new(MockAgentActionRepository), gateway, quota, AgentContextWindow{})
+		ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: userID})
+
+		_, err := usecase.ChatStream(ctx, AgentChatInput{Message: "Oi"}, func(domain.AgentStreamEvent) {})
+
+		assert.ErrorIs(t, err, ErrAIQuotaExceeded)
+		convRepo.AssertNotCalled(t, "Save", mock.Anything)
+		gateway.AssertNotCalled(t, "ChatStream", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+	"personal-finance/pkg/metrics"
+)
+
+// --- Interfaces ---
+
+type AIUsageRepository interface {
+	Increment(ctx context.Context, userID string, year int, month time.Month, feature domain.AIFeature, usage domain.AIUsage) error
+	SumByUserIDAndMonth(ctx context.Context, userID string, year int, month time.Month) (domain.AIUsage, error)
+}
+
+type AIQuotaOverrideRepository interface {
+	FindByUserID(ctx context.Context, userID string) (domain.AIQuotaOverride, error)
+	Upsert(ctx context.Context, override domain.AIQuotaOverride) (domain.AIQuotaOverride, error)
+	Delete(ctx context.Context, userID string) error
+}
+
+// AIQuotaGuard is what the AI features need: a check before calling the model
+// and the record of what the call consumed.
+type AIQuotaGuard interface {
+	Check(ctx context.Context) error
+	Record(ctx context.Context, feature domain.AIFeature, usage domain.AIUsage)
+}
+
+// --- Use Case ---
+
+// AIQuota enforces the monthly AI quota of the user plan, or of the override an
+// admin granted. Tokens are only known after the call, so the last call of the
+// month may exceed the token quota; the next one is blocked.
+type AIQuota struct {
+	usageRepo    AIUsageRepository
+	overrideRepo AIQuotaOverrideRepository
+	entitlements EntitlementResolver
+}
+
+func NewAIQuota(usageRepo AIUsageRepository, overrideRepo AIQuotaOverrideRepository, entitlements EntitlementResolver) *AIQuota {
+	return &AIQuota{
+		usageRepo:    usageRepo,
+		overrideRepo: overrideRepo,
+		entitlements: entitlements,
+	}
+}
+
+func (q *AIQuota) Check(ctx context.Context) error {
+	auth, ok := authentication.AuthFromContext(ctx)
+	if !ok {
+		return ErrUnauthorized
+	}
+
+	quota, err := q.EffectiveQuota(ctx)
+	if err != nil {
+		return err
+	}
+	if quota.TokensPerMonth == 0 && quota.RequestsPerMonth == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	usage, err := q.usageRepo.SumByUserIDAndMonth(ctx, auth.UserID, now.Year(), now.Month())
+	if err != nil {
+		return err
+	}
+
+	if (quota.RequestsPerMonth > 0 && usage.Requests >= int64(quota.RequestsPerMonth)) ||
+		(quota.TokensPerMonth > 0 && usage.Tokens() >= int64(quota.TokensPerMonth)) {
+		metrics.IncBusiness(ctx, "biz_plan_limit_hits_total", 1, metrics.String("limit_type", "ai_quota"))
+		return ErrAIQuotaExceeded
+	}
+
+	return nil
+}
+
+// Record adds the usage of one AI call to the current month. Failures are only
+// logged: the call already happened and the user must still get its result.
+func (q *AIQuota) Record(ctx context.Context, feature domain.AIFeature, usage domain.AIUsage) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return
+	}
+
+	now := time.Now()
+	if err := q.usageRepo.Increment(ctx, userID, now.Year(), now.Month(), feature, usage); err != nil {
+		log.ErrorContext(ctx, "failed to record AI usage", log.Err(err), log.String("feature", string(feature)))
+	}
+}
+
+// EffectiveQuota returns the quota of the current user: an active override or
+// the AI limits of the plan entitlements.
+func (q *AIQuota) EffectiveQuota(ctx context.Context) (authentication.AIQuota, error) {
+	auth, ok := authentication.AuthFromContext(ctx)
+	if !ok {
+		return authentication.AIQuota{}, ErrUnauthorized
+	}
+
+	override, err := q.overrideRepo.FindByUserID(ctx, auth.UserID)
+	if err != nil && !domain.Is(err, repository.ErrAIQuotaOverrideNotFound) {
+		return authentication.AIQuota{}, err
+	}
+	if err == nil && override.IsActive(time.Now()) {
+		return authentication.AIQuota{
+			TokensPerMonth:   override.TokensPerMonth,
+			RequestsPerMonth: override.RequestsPerMonth,
+		}, nil
+	}
+
+	ent, err := q.entitlements.Resolve(ctx)
+	if err != nil {
+		return authentication.AIQuota{}, err
+	}
+	return aiQuotaFromEntitlements(ent), nil
+}
+
+func (q *AIQuota) MonthlyUsage(ctx context.Context, year int, month time.Month) (domain.AIUsage, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.AIUsage{}, ErrUnauthorized
+	}
+	return q.usageRepo.SumByUserIDAndMonth(ctx, userID, year, month)
+}
+
+// --- Admin ---
+
+func (q *AIQuota) GetOverride(ctx context.Context, userID string) (domain.AIQuotaOverride, error) {
+	return q.overrideRepo.FindByUserID(ctx, userID)
+}
+
+func (q *AIQuota) SetOverride(ctx context.Context, override domain.AIQuotaOverride) (domain.AIQuotaOverride, error) {
+	if override.TokensPerMonth < 0 || override.RequestsPerMonth < 0 {
+		return domain.AIQuotaOverride{}, ErrInvalidAIQuota
+	}
+	override.GrantedBy = authentication.UserIDFromContext(ctx)
+	return q.overrideRepo.Upsert(ctx, override)
+}
+
+func (q *AIQuota) DeleteOverride(ctx context.Context, userID string) error {
+	return q.overrideRepo.Delete(ctx, userID)
+}
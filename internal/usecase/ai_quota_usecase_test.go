@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func aiQuotaContext(plan authentication.Plan) context.Context {
+	return authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1", Plan: plan})
+}
+
+func TestAIQuota_Check(t *testing.T) {
+	entitlements := new(MockEntitlementResolver)
+	entitlements.On("Resolve").Return(domain.Entitlements{Limits: map[domain.LimitName]int{
+		domain.LimitAITokensPerMonth:   1000,
+		domain.LimitAIRequestsPerMonth: 10,
+	}}, nil)
+
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-24 * time.Hour)
+
+	tests := map[string]struct {
+		override    domain.AIQuotaOverride
+		overrideErr error
+		usage       domain.AIUsage
+		expectedErr error
+	}{
+		"should allow usage below the plan quota": {
+			overrideErr: repository.ErrAIQuotaOverrideNotFound,
+			usage:       domain.AIUsage{Requests: 3, InputTokens: 400, OutputTokens: 100},
+		},
+		"should block when the monthly requests are used up": {
+			overrideErr: repository.ErrAIQuotaOverrideNotFound,
+			usage:       domain.AIUsage{Requests: 10},
+			expectedErr: ErrAIQuotaExceeded,
+		},
+		"should block when the monthly tokens are used up": {
+			overrideErr: repository.ErrAIQuotaOverrideNotFound,
+			usage:       domain.AIUsage{Requests: 2, InputTokens: 800, OutputTokens: 200},
+			expectedErr: ErrAIQuotaExceeded,
+		},
+		"should use an active override instead of the plan quota": {
+			override: domain.AIQuotaOverride{UserID: "user-1", TokensPerMonth: 5000, RequestsPerMonth: 50, ExpiresAt: &future},
+			usage:    domain.AIUsage{Requests: 10, InputTokens: 1000},
+		},
+		"should ignore an expired override": {
+			override:    domain.AIQuotaOverride{UserID: "user-1", TokensPerMonth: 5000, RequestsPerMonth: 50, ExpiresAt: &past},
+			usage:       domain.AIUsage{Requests: 10},
+			expectedErr: ErrAIQuotaExceeded,
+		},
+		"should propagate repository errors": {
+			overrideErr: errors.New("db down"),
+			expectedErr: errors.New("db down"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			usageRepo := new(MockAIUsageRepository)
+			overrideRepo := new(MockAIQuotaOverrideRepository)
+			overrideRepo.On("FindByUserID", "user-1").Return(tt.override, tt.overrideErr)
+			usageRepo.On("SumByUserIDAndMonth", "user-1", mock.Anything, mock.Anything).Return(tt.usage, nil)
+
+			err := NewAIQuota(usageRepo, overrideRepo, entitlements).Check(aiQuotaContext(authentication.PlanFree))
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("should not query usage when the override is unlimited", func(t *testing.T) {
+		usageRepo := new(MockAIUsageRepository)
+		overrideRepo := new(MockAIQuotaOverrideRepository)
+		overrideRepo.On("FindByUserID", "user-1").Return(domain.AIQuotaOverride{UserID: "user-1"}, nil)
+
+		err := NewAIQuota(usageRepo, overrideRepo, entitlements).Check(aiQuotaContext(authentication.PlanFree))
+
+		assert.NoError(t, err)
+		usageRepo.AssertNotCalled(t, "SumByUserIDAndMonth", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should require authentication", func(t *testing.T) {
+		err := NewAIQuota(new(MockAIUsageRepository), new(MockAIQuotaOverrideRepository), entitlements).Check(context.Background())
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestAIQuota_Record(t *testing.T) {
+	t.Run("should add the usage to the current month of the user", func(t *testing.T) {
+		usageRepo := new(MockAIUsageRepository)
+		usage := domain.AIUsage{Requests: 1, InputTokens: 300, OutputTokens: 50}
+		now := time.Now()
+		usageRepo.On("Increment", "user-1", now.Year(), now.Month(), domain.AIFeatureAgent, usage).Return(nil)
+
+		NewAIQuota(usageRepo, new(MockAIQuotaOverrideRepository), new(MockEntitlementResolver)).Record(aiQuotaContext(authentication.PlanPlus), domain.AIFeatureAgent, usage)
+
+		usageRepo.AssertExpectations(t)
+	})
+}
+
+func TestAIQuota_SetOverride(t *testing.T) {
+	t.Run("should record the admin who granted the override", func(t *testing.T) {
+		overrideRepo := new(MockAIQuotaOverrideRepository)
+		adminCtx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "admin-1", Role: authentication.RoleAdmin})
+		overrideRepo.On("Upsert", mock.MatchedBy(func(o domain.AIQuotaOverride) bool {
+			return o.UserID == "user-1" && o.GrantedBy == "admin-1" && o.TokensPerMonth == 10000
+		})).Return(domain.AIQuotaOverride{UserID: "user-1"}, nil)
+
+		_, err := NewAIQuota(new(MockAIUsageRepository), overrideRepo, new(MockEntitlementResolver)).SetOverride(adminCtx, domain.AIQuotaOverride{UserID: "user-1", TokensPerMonth: 10000})
+
+		assert.NoError(t, err)
+		overrideRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject negative values", func(t *testing.T) {
+		_, err := NewAIQuota(new(MockAIUsageRepository), new(MockAIQuotaOverrideRepository), new(MockEntitlementResolver)).
+			SetOverride(context.Background(), domain.AIQuotaOverride{UserID: "user-1", RequestsPerMonth: -1})
+
+		assert.ErrorIs(t, err, ErrInvalidAIQuota)
+	})
+}
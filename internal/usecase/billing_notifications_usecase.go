@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"personal-finance/pkg/log"
+)
+
+const (
+	trialEndingPushTitle   = "Seu teste grátis está acabando"
+	paymentFailedPushTitle = "Não conseguimos cobrar sua assinatura"
+)
+
+// BillingNotifications pushes subscription billing notices to the devices of a
+// user.
+type BillingNotifications struct {
+	deviceRepo PushDeviceRepository
+	pushSender PushSender
+}
+
+func NewBillingNotifications(deviceRepo PushDeviceRepository, pushSender PushSender) *BillingNotifications {
+	return &BillingNotifications{
+		deviceRepo: deviceRepo,
+		pushSender: pushSender,
+	}
+}
+
+// NotifyTrialEnding tells the user the free trial ends at endsAt and the
+// subscription will be charged.
+func (u *BillingNotifications) NotifyTrialEnding(ctx context.Context, userID string, endsAt time.Time) error {
+	body := fmt.Sprintf("Seu teste do Plus termina em %s. Depois disso, a assinatura será cobrada automaticamente.", endsAt.Format("02/01"))
+	return u.send(ctx, userID, trialEndingPushTitle, body)
+}
+
+// NotifyPaymentFailed tells the user to update the payment method before the
+// grace ends at graceEndsAt.
+func (u *BillingNotifications) NotifyPaymentFailed(ctx context.Context, userID string, graceEndsAt time.Time) error {
+	body := fmt.Sprintf("Atualize sua forma de pagamento até %s para continuar com o Plus.", graceEndsAt.Format("02/01"))
+	return u.send(ctx, userID, paymentFailedPushTitle, body)
+}
+
+func (u *BillingNotifications) send(ctx context.Context, userID, title, body string) error {
+	devices, err := u.deviceRepo.FindByUserIDs(ctx, []string{userID})
+	if err != nil {
+		return fmt.Errorf("error finding devices: %w", err)
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	tokens := make([]string, 0, len(devices))
+	for _, device := range devices {
+		tokens = append(tokens, device.ExpoPushToken)
+	}
+
+	sendResult, err := u.pushSender.Send(ctx, tokens, title, body)
+	if err != nil {
+		return fmt.Errorf("error sending billing push: %w", err)
+	}
+
+	if len(sendResult.InvalidTokens) > 0 {
+		if err := u.deviceRepo.DeleteByTokens(ctx, sendResult.InvalidTokens); err != nil {
+			log.Error("error deleting invalid tokens", log.Err(err))
+		}
+	}
+	return nil
+}
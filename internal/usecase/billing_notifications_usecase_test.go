@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/push"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBillingNotifications_NotifyPaymentFailed(t *testing.T) {
+	graceEnd := time.Date(2026, time.October, 29, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		mockSetup   func(devRepo *MockPushDeviceRepository, sender *MockPushSender)
+		expectedErr bool
+	}{
+		"should push to every device of the user and drop invalid tokens": {
+			mockSetup: func(devRepo *MockPushDeviceRepository, sender *MockPushSender) {
+				devRepo.On("FindByUserIDs", []string{"user-123"}).Return([]domain.Device{
+					{UserID: "user-123", ExpoPushToken: "token-1"},
+					{UserID: "user-123", ExpoPushToken: "token-2"},
+				}, nil)
+				sender.On("Send", []string{"token-1", "token-2"}, paymentFailedPushTitle, mock.MatchedBy(func(body string) bool {
+					return strings.Contains(body, "29/10")
+				})).Return(push.SendResult{SuccessCount: 1, FailureCount: 1, InvalidTokens: []string{"token-2"}}, nil)
+				devRepo.On("DeleteByTokens", []string{"token-2"}).Return(nil)
+			},
+		},
+		"should not push without devices": {
+			mockSetup: func(devRepo *MockPushDeviceRepository, sender *MockPushSender) {
+				devRepo.On("FindByUserIDs", []string{"user-123"}).Return([]domain.Device{}, nil)
+			},
+		},
+		"should return the send error": {
+			mockSetup: func(devRepo *MockPushDeviceRepository, sender *MockPushSender) {
+				devRepo.On("FindByUserIDs", []string{"user-123"}).Return([]domain.Device{{UserID: "user-123", ExpoPushToken: "token-1"}}, nil)
+				sender.On("Send", []string{"token-1"}, paymentFailedPushTitle, mock.Anything).Return(push.SendResult{}, errors.New("expo down"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			devRepo := new(MockPushDeviceRepository)
+			sender := new(MockPushSender)
+			tt.mockSetup(devRepo, sender)
+
+			u := NewBillingNotifications(devRepo, sender)
+			err := u.NotifyPaymentFailed(context.Background(), "user-123", graceEnd)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			devRepo.AssertExpectations(t)
+			sender.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBillingNotifications_NotifyTrialEnding(t *testing.T) {
+	devRepo := new(MockPushDeviceRepository)
+	sender := new(MockPushSender)
+	devRepo.On("FindByUserIDs", []string{"user-123"}).Return([]domain.Device{{UserID: "user-123", ExpoPushToken: "token-1"}}, nil)
+	sender.On("Send", []string{"token-1"}, trialEndingPushTitle, mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "22/10")
+	})).Return(push.SendResult{SuccessCount: 1}, nil)
+
+	u := NewBillingNotifications(devRepo, sender)
+	err := u.NotifyTrialEnding(context.Background(), "user-123", time.Date(2026, time.October, 22, 12, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	sender.AssertExpectations(t)
+}
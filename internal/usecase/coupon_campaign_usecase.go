@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"personal-finance/internal/domain"
+)
+
+const (
+	// MaxCouponCodesPerRequest caps how many codes one generation call creates.
+	MaxCouponCodesPerRequest = 10000
+
+	couponCodeLength       = 8
+	couponCodeAlphabet     = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	couponCodeMaxAttempts  = 5
+	couponCodePrefixMaxLen = 16
+)
+
+var couponCodePrefixPattern = regexp.MustCompile(`^[A-Z0-9]*$`)
+
+// CouponCampaignSummary is a campaign with the count of its codes by status.
+type CouponCampaignSummary struct {
+	domain.CouponCampaign
+	TotalCodes     int `json:"total_codes"`
+	AvailableCodes int `json:"available_codes"`
+	PendingCodes   int `json:"pending_codes"`
+	RedeemedCodes  int `json:"redeemed_codes"`
+}
+
+// CreateCampaign registers a campaign of single-use codes for an existing
+// coupon. Once it exists, the coupon is only redeemable through the campaign
+// codes: it returns the shared code of the coupon, which stops being accepted.
+func (s *Coupon) CreateCampaign(ctx context.Context, campaign domain.CouponCampaign) (disabledSharedCode string, err error) {
+	campaign.CodePrefix = strings.ToUpper(strings.TrimSpace(campaign.CodePrefix))
+	if err := validateCampaignInput(campaign); err != nil {
+		return "", err
+	}
+	coupon, err := s.couponRepo.FindByID(ctx, campaign.CouponID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
+		return "", err
+	}
+	return coupon.Code, nil
+}
+
+func (s *Coupon) ListCampaigns(ctx context.Context) ([]domain.CouponCampaign, error) {
+	return s.campaignRepo.List(ctx)
+}
+
+func (s *Coupon) GetCampaign(ctx context.Context, id string) (CouponCampaignSummary, error) {
+	campaign, err := s.campaignRepo.FindByID(ctx, id)
+	if err != nil {
+		return CouponCampaignSummary{}, err
+	}
+	codes, err := s.campaignRepo.ListCodes(ctx, id)
+	if err != nil {
+		return CouponCampaignSummary{}, err
+	}
+
+	summary := CouponCampaignSummary{CouponCampaign: campaign, TotalCodes: len(codes)}
+	for _, code := range codes {
+		switch code.Status {
+		case domain.CouponCodeAvailable:
+			summary.AvailableCodes++
+		case domain.CouponCodePending:
+			summary.PendingCodes++
+		case domain.CouponCodeRedeemed:
+			summary.RedeemedCodes++
+		}
+	}
+	return summary, nil
+}
+
+// ListCampaignCodes lists every code of the campaign with its redemption
+// status.
+func (s *Coupon) ListCampaignCodes(ctx context.Context, campaignID string) ([]domain.CouponCampaignCode, error) {
+	if _, err := s.campaignRepo.FindByID(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	return s.campaignRepo.ListCodes(ctx, campaignID)
+}
+
+// GenerateCodes creates quantity new random codes for the campaign. Codes that
+// collide with existing ones are generated again.
+func (s *Coupon) GenerateCodes(ctx context.Context, campaignID string, quantity int) (int, error) {
+	if quantity <= 0 || quantity > MaxCouponCodesPerRequest {
+		return 0, domain.WrapInvalidInput(domain.New(fmt.Sprintf("quantity must be between 1 and %d", MaxCouponCodesPerRequest)), "coupon codes")
+	}
+
+	campaign, err := s.campaignRepo.FindByID(ctx, campaignID)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for attempt := 0; attempt < couponCodeMaxAttempts && created < quantity; attempt++ {
+		codes, err := newCouponCodes(campaign.CodePrefix, quantity-created)
+		if err != nil {
+			return created, err
+		}
+		inserted, err := s.campaignRepo.CreateCodes(ctx, campaign.ID, codes)
+		if err != nil {
+			return created, err
+		}
+		created += inserted
+	}
+	if created < quantity {
+		return created, fmt.Errorf("generated %d of %d coupon codes", created, quantity)
+	}
+	return created, nil
+}
+
+// FindCampaignCode returns the campaign code and its campaign, or
+// domain.ErrCouponCodeNotFound when code is not a campaign code.
+func (s *Coupon) FindCampaignCode(ctx context.Context, code string) (domain.CouponCampaignCode, domain.CouponCampaign, error) {
+	if s.campaignRepo == nil {
+		return domain.CouponCampaignCode{}, domain.CouponCampaign{}, domain.ErrCouponCodeNotFound
+	}
+	return s.campaignRepo.FindCode(ctx, code)
+}
+
+// LinkStripePromotionCode stores the Stripe promotion code created for a
+// campaign code, so the next checkouts reuse it.
+func (s *Coupon) LinkStripePromotionCode(ctx context.Context, code, promotionCodeID string) error {
+	return s.campaignRepo.SetStripePromotionCode(ctx, code, promotionCodeID)
+}
+
+func validateCampaignInput(c domain.CouponCampaign) error {
+	if c.ID == "" {
+		return domain.WrapInvalidInput(domain.New("id is required"), "coupon campaign")
+	}
+	if strings.TrimSpace(c.Name) == "" {
+		return domain.WrapInvalidInput(domain.New("name is required"), "coupon campaign")
+	}
+	if c.CouponID == "" {
+		return domain.WrapInvalidInput(domain.New("coupon_id is required"), "coupon campaign")
+	}
+	if len(c.CodePrefix) > couponCodePrefixMaxLen || !couponCodePrefixPattern.MatchString(c.CodePrefix) {
+		return domain.WrapInvalidInput(domain.New(fmt.Sprintf("code_prefix must have up to %d letters and digits", couponCodePrefixMaxLen)), "coupon campaign")
+	}
+	return nil
+}
+
+// newCouponCodes generates n random codes, as PREFIX-XXXXXXXX when the
+// campaign has a prefix. The alphabet leaves out 0, O, 1 and I.
+func newCouponCodes(prefix string, n int) ([]string, error) {
+	buf := make([]byte, n*couponCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("error generating coupon codes: %w", err)
+	}
+
+	codes := make([]string, n)
+	for i := range codes {
+		var b strings.Builder
+		if prefix != "" {
+			b.WriteString(prefix)
+			b.WriteByte('-')
+		}
+		for _, r := range buf[i*couponCodeLength : (i+1)*couponCodeLength] {
+			b.WriteByte(couponCodeAlphabet[int(r)%len(couponCodeAlphabet)])
+		}
+		codes[i] = b.String()
+	}
+	return codes, nil
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCouponCampaignUseCase(t *testing.T) (*Coupon, *repository.CouponRepository, *repository.CouponCampaignRepository, *fakePlanRepo) {
+	t.Helper()
+	db := newCouponTestDB(t)
+	couponRepo := repository.NewCouponRepository(db)
+	campaignRepo := repository.NewCouponCampaignRepository(db)
+	planRepo := &fakePlanRepo{plans: map[string]domain.SubscriptionPlan{
+		"plus_monthly": {ID: "plus_monthly", Name: "Plus Mensal", Price: 10.00, Currency: "BRL", IsActive: true},
+	}}
+	uc := NewCoupon(couponRepo, repository.NewCouponRedemptionRepository(db), planRepo, db).WithCampaigns(campaignRepo)
+
+	ctx := context.Background()
+	assert.NoError(t, couponRepo.Create(ctx, validCoupon("c1", "PARTNER")))
+	disabledSharedCode, err := uc.CreateCampaign(ctx, domain.CouponCampaign{ID: "camp-1", Name: "Parceiro", CouponID: "c1", CodePrefix: "parc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "PARTNER", disabledSharedCode)
+	return uc, couponRepo, campaignRepo, planRepo
+}
+
+func TestCoupon_CreateCampaign_Validation(t *testing.T) {
+	tests := map[string]struct {
+		campaign    domain.CouponCampaign
+		expectedErr error
+	}{
+		"should require a name": {
+			campaign:    domain.CouponCampaign{ID: "camp-2", CouponID: "c1"},
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should reject a prefix with symbols": {
+			campaign:    domain.CouponCampaign{ID: "camp-2", Name: "X", CouponID: "c1", CodePrefix: "A-B"},
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should require an existing coupon": {
+			campaign:    domain.CouponCampaign{ID: "camp-2", Name: "X", CouponID: "missing"},
+			expectedErr: domain.ErrCouponNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			uc, _, _, _ := newCouponCampaignUseCase(t)
+			_, err := uc.CreateCampaign(context.Background(), tt.campaign)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestCoupon_GenerateCodes(t *testing.T) {
+	uc, _, campaignRepo, _ := newCouponCampaignUseCase(t)
+	ctx := context.Background()
+
+	created, err := uc.GenerateCodes(ctx, "camp-1", 50)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, created)
+
+	codes, err := campaignRepo.ListCodes(ctx, "camp-1")
+	assert.NoError(t, err)
+	assert.Len(t, codes, 50)
+	for _, code := range codes {
+		assert.True(t, strings.HasPrefix(code.Code, "PARC-"), code.Code)
+		assert.Len(t, code.Code, len("PARC-")+couponCodeLength)
+	}
+
+	_, err = uc.GenerateCodes(ctx, "camp-1", MaxCouponCodesPerRequest+1)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	_, err = uc.GenerateCodes(ctx, "missing", 1)
+	assert.ErrorIs(t, err, domain.ErrCouponCampaignNotFound)
+}
+
+func TestCoupon_CampaignCode_SingleUse(t *testing.T) {
+	uc, couponRepo, campaignRepo, planRepo := newCouponCampaignUseCase(t)
+	ctx := context.Background()
+	_, err := campaignRepo.CreateCodes(ctx, "camp-1", []string{"PARC-AAAA", "PARC-BBBB"})
+	assert.NoError(t, err)
+	plan, _ := planRepo.FindActiveByID(ctx, "plus_monthly")
+
+	preview, err := uc.Preview(ctx, "user-1", "plus_monthly", "PARC-AAAA")
+	assert.NoError(t, err)
+	assert.True(t, preview.Valid)
+	assert.Equal(t, 7.0, preview.DiscountedPrice)
+
+	preview, err = uc.Preview(ctx, "user-1", "plus_monthly", "PARTNER")
+	assert.NoError(t, err)
+	assert.False(t, preview.Valid, "the shared code of a campaign coupon must not be redeemable")
+
+	_, redemptionID, err := uc.ApplyAtCheckout(ctx, "user-1", plan, "PARC-AAAA")
+	assert.NoError(t, err)
+	assert.NoError(t, uc.Confirm(ctx, redemptionID, uuid.New()))
+
+	summary, err := uc.GetCampaign(ctx, "camp-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.TotalCodes)
+	assert.Equal(t, 1, summary.RedeemedCodes)
+	assert.Equal(t, 1, summary.AvailableCodes)
+
+	coupon, _ := couponRepo.FindByID(ctx, "c1")
+	assert.Equal(t, 1, coupon.RedemptionCount)
+
+	preview, err = uc.Preview(ctx, "user-2", "plus_monthly", "PARC-AAAA")
+	assert.NoError(t, err)
+	assert.False(t, preview.Valid)
+	assert.Equal(t, "coupon code already used", preview.Reason)
+
+	_, err = uc.ApplyWebCheckout(ctx, "user-2", plan, "PARC-AAAA")
+	assert.ErrorIs(t, err, domain.ErrCouponCodeAlreadyUsed)
+}
+
+func TestCoupon_CampaignCode_ConcurrentPendingRedemptions(t *testing.T) {
+	uc, _, campaignRepo, planRepo := newCouponCampaignUseCase(t)
+	ctx := context.Background()
+	_, err := campaignRepo.CreateCodes(ctx, "camp-1", []string{"PARC-AAAA"})
+	assert.NoError(t, err)
+	plan, _ := planRepo.FindActiveByID(ctx, "plus_monthly")
+
+	first, err := uc.ApplyWebCheckout(ctx, "user-1", plan, "PARC-AAAA")
+	assert.NoError(t, err)
+
+	_, err = uc.ApplyWebCheckout(ctx, "user-2", plan, "PARC-AAAA")
+	assert.ErrorIs(t, err, domain.ErrCouponCodeAlreadyUsed, "the code is held by the pending checkout of user-1")
+	preview, err := uc.Preview(ctx, "user-2", "plus_monthly", "PARC-AAAA")
+	assert.NoError(t, err)
+	assert.False(t, preview.Valid)
+
+	again, err := uc.ApplyWebCheckout(ctx, "user-1", plan, "PARC-AAAA")
+	assert.NoError(t, err)
+	assert.Equal(t, first, again, "the holder can apply the code again")
+
+	// Once the hold expires, another user may take over the code.
+	assert.NoError(t, campaignRepo.ReserveCode(ctx, "PARC-AAAA", "user-1", time.Now().Add(-time.Minute)))
+	second, err := uc.ApplyWebCheckout(ctx, "user-2", plan, "PARC-AAAA")
+	assert.NoError(t, err)
+
+	codes, err := uc.ListCampaignCodes(ctx, "camp-1")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CouponCodePending, codes[0].Status)
+
+	assert.NoError(t, uc.Confirm(ctx, first, uuid.New()))
+	assert.ErrorIs(t, uc.Confirm(ctx, second, uuid.New()), domain.ErrCouponCodeAlreadyUsed)
+
+	codes, err = uc.ListCampaignCodes(ctx, "camp-1")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CouponCodeRedeemed, codes[0].Status)
+	assert.Equal(t, "user-1", codes[0].RedeemedBy)
+}
@@ -29,16 +29,30 @@ type (
 		FindByID(ctx context.Context, id uuid.UUID) (domain.CouponRedemption, error)
 		FindBySubscription(ctx context.Context, subscriptionID uuid.UUID) (domain.CouponRedemption, error)
 		FindByUserCoupon(ctx context.Context, userID, couponID string) (domain.CouponRedemption, error)
-		RefreshPending(ctx context.Context, userID, couponID string, originalPrice, lockedPrice float64) (domain.CouponRedemption, error)
+		RefreshPending(ctx context.Context, userID, couponID, code string, originalPrice, lockedPrice float64) (domain.CouponRedemption, error)
 		MarkActive(ctx context.Context, tx *gorm.DB, redemptionID, subscriptionID uuid.UUID) error
 		MarkCancelledBySubscription(ctx context.Context, subscriptionID uuid.UUID) error
 	}
+
+	CouponCampaignRepository interface {
+		Create(ctx context.Context, campaign domain.CouponCampaign) error
+		FindByID(ctx context.Context, id string) (domain.CouponCampaign, error)
+		List(ctx context.Context) ([]domain.CouponCampaign, error)
+		ExistsForCoupon(ctx context.Context, couponID string) (bool, error)
+		CreateCodes(ctx context.Context, campaignID string, codes []string) (int, error)
+		FindCode(ctx context.Context, code string) (domain.CouponCampaignCode, domain.CouponCampaign, error)
+		ListCodes(ctx context.Context, campaignID string) ([]domain.CouponCampaignCode, error)
+		ReserveCode(ctx context.Context, code, userID string, until time.Time) error
+		MarkCodeRedeemed(ctx context.Context, tx *gorm.DB, code, userID string, redemptionID uuid.UUID) error
+		SetStripePromotionCode(ctx context.Context, code, promotionCodeID string) error
+	}
 )
 
 type Coupon struct {
 	couponRepo     CouponRepository
 	redemptionRepo CouponRedemptionRepository
 	planRepo       SubscriptionPlanRepository
+	campaignRepo   CouponCampaignRepository
 	db             *gorm.DB
 }
 
@@ -56,6 +70,14 @@ func NewCoupon(
 	}
 }
 
+// WithCampaigns makes the single-use campaign codes redeemable. Without it only
+// the shared coupon codes are.
+func (s *Coupon) WithCampaigns(campaignRepo CouponCampaignRepository) *Coupon {
+	c := *s
+	c.campaignRepo = campaignRepo
+	return &c
+}
+
 type CouponUpdateFields struct {
 	Description       *string
 	DiscountType      *domain.CouponDiscountType
@@ -81,11 +103,14 @@ func (s *Coupon) Preview(ctx context.Context, userID, planID, code string) (Coup
 		return CouponPreview{Valid: false, Reason: "plan not found"}, nil
 	}
 
-	coupon, err := s.couponRepo.FindActiveByCode(ctx, code)
+	coupon, _, err := s.resolveCode(ctx, code, userID)
 	if err != nil {
 		if errors.Is(err, domain.ErrCouponNotFound) {
 			return CouponPreview{Valid: false, Reason: "coupon not found"}, nil
 		}
+		if errors.Is(err, domain.ErrCouponCodeAlreadyUsed) {
+			return CouponPreview{Valid: false, Reason: "coupon code already used", OriginalPrice: plan.Price, Currency: plan.Currency}, nil
+		}
 		return CouponPreview{}, err
 	}
 
@@ -107,11 +132,8 @@ func (s *Coupon) Preview(ctx context.Context, userID, planID, code string) (Coup
 }
 
 func (s *Coupon) ApplyWebCheckout(ctx context.Context, userID string, plan domain.SubscriptionPlan, code string) (redemptionID uuid.UUID, err error) {
-	coupon, err := s.couponRepo.FindActiveByCode(ctx, code)
+	coupon, usedCode, err := s.resolveCode(ctx, code, userID)
 	if err != nil {
-		if errors.Is(err, domain.ErrCouponNotFound) {
-			return uuid.Nil, domain.ErrCouponNotFound
-		}
 		return uuid.Nil, err
 	}
 
@@ -124,9 +146,13 @@ func (s *Coupon) ApplyWebCheckout(ctx context.Context, userID string, plan domai
 		return uuid.Nil, err
 	}
 
+	if err := s.reserveCode(ctx, usedCode, userID); err != nil {
+		return uuid.Nil, err
+	}
+
 	if existing, err := s.redemptionRepo.FindByUserCoupon(ctx, userID, coupon.ID); err == nil &&
 		existing.Status == domain.CouponRedemptionPending {
-		refreshed, err := s.redemptionRepo.RefreshPending(ctx, userID, coupon.ID, plan.Price, locked)
+		refreshed, err := s.redemptionRepo.RefreshPending(ctx, userID, coupon.ID, usedCode, plan.Price, locked)
 		if err != nil {
 			return uuid.Nil, err
 		}
@@ -137,6 +163,7 @@ func (s *Coupon) ApplyWebCheckout(ctx context.Context, userID string, plan domai
 		UserID:        userID,
 		CouponID:      coupon.ID,
 		PlanID:        plan.ID,
+		Code:          usedCode,
 		OriginalPrice: plan.Price,
 		LockedPrice:   locked,
 		Status:        domain.CouponRedemptionPending,
@@ -149,11 +176,8 @@ func (s *Coupon) ApplyWebCheckout(ctx context.Context, userID string, plan domai
 }
 
 func (s *Coupon) ApplyAtCheckout(ctx context.Context, userID string, plan domain.SubscriptionPlan, code string) (lockedPrice float64, redemptionID uuid.UUID, err error) {
-	coupon, err := s.couponRepo.FindActiveByCode(ctx, code)
+	coupon, usedCode, err := s.resolveCode(ctx, code, userID)
 	if err != nil {
-		if errors.Is(err, domain.ErrCouponNotFound) {
-			return 0, uuid.Nil, domain.ErrCouponNotFound
-		}
 		return 0, uuid.Nil, err
 	}
 
@@ -166,9 +190,13 @@ func (s *Coupon) ApplyAtCheckout(ctx context.Context, userID string, plan domain
 		return 0, uuid.Nil, err
 	}
 
+	if err := s.reserveCode(ctx, usedCode, userID); err != nil {
+		return 0, uuid.Nil, err
+	}
+
 	if existing, err := s.redemptionRepo.FindByUserCoupon(ctx, userID, coupon.ID); err == nil &&
 		existing.Status == domain.CouponRedemptionPending {
-		refreshed, err := s.redemptionRepo.RefreshPending(ctx, userID, coupon.ID, plan.Price, locked)
+		refreshed, err := s.redemptionRepo.RefreshPending(ctx, userID, coupon.ID, usedCode, plan.Price, locked)
 		if err != nil {
 			return 0, uuid.Nil, err
 		}
@@ -179,6 +207,7 @@ func (s *Coupon) ApplyAtCheckout(ctx context.Context, userID string, plan domain
 		UserID:        userID,
 		CouponID:      coupon.ID,
 		PlanID:        plan.ID,
+		Code:          usedCode,
 		OriginalPrice: plan.Price,
 		LockedPrice:   locked,
 		Status:        domain.CouponRedemptionPending,
@@ -207,6 +236,11 @@ func (s *Coupon) Confirm(ctx context.Context, redemptionID, subscriptionID uuid.
 		if err := s.couponRepo.IncrementRedemptionCount(ctx, tx, redemption.CouponID); err != nil {
 			return err
 		}
+		if redemption.Code != "" && s.campaignRepo != nil {
+			if err := s.campaignRepo.MarkCodeRedeemed(ctx, tx, redemption.Code, redemption.UserID, redemptionID); err != nil {
+				return err
+			}
+		}
 		return s.redemptionRepo.MarkActive(ctx, tx, redemptionID, subscriptionID)
 	})
 }
@@ -276,6 +310,55 @@ func (s *Coupon) Deactivate(ctx context.Context, id string) error {
 	return s.couponRepo.Update(ctx, current)
 }
 
+// resolveCode finds the coupon redeemed by code, either a single-use campaign
+// code or a shared coupon code, and returns the campaign code to record on the
+// redemption (empty for a shared code). The shared code of a coupon backing a
+// campaign is not redeemable, nor is a campaign code redeemed or held by
+// another user.
+func (s *Coupon) resolveCode(ctx context.Context, code, userID string) (domain.Coupon, string, error) {
+	if s.campaignRepo != nil {
+		campaignCode, campaign, err := s.campaignRepo.FindCode(ctx, code)
+		switch {
+		case err == nil:
+			if campaignCode.Status == domain.CouponCodeRedeemed || campaignCode.ReservedByOther(userID, time.Now()) {
+				return domain.Coupon{}, "", domain.ErrCouponCodeAlreadyUsed
+			}
+			coupon, err := s.couponRepo.FindByID(ctx, campaign.CouponID)
+			if err != nil {
+				return domain.Coupon{}, "", err
+			}
+			return coupon, campaignCode.Code, nil
+		case !errors.Is(err, domain.ErrCouponCodeNotFound):
+			return domain.Coupon{}, "", err
+		}
+	}
+
+	coupon, err := s.couponRepo.FindActiveByCode(ctx, code)
+	if err != nil {
+		return domain.Coupon{}, "", err
+	}
+
+	if s.campaignRepo != nil {
+		inCampaign, err := s.campaignRepo.ExistsForCoupon(ctx, coupon.ID)
+		if err != nil {
+			return domain.Coupon{}, "", err
+		}
+		if inCampaign {
+			return domain.Coupon{}, "", domain.ErrCouponNotFound
+		}
+	}
+	return coupon, "", nil
+}
+
+// reserveCode holds the campaign code for the user while the checkout is
+// pending. Shared coupon codes (empty) are not reserved.
+func (s *Coupon) reserveCode(ctx context.Context, code, userID string) error {
+	if code == "" {
+		return nil
+	}
+	return s.campaignRepo.ReserveCode(ctx, code, userID, time.Now().Add(domain.CouponCodeReservation))
+}
+
 func validateCouponInput(c domain.Coupon) error {
 	if c.ID == "" {
 		return domain.WrapInvalidInput(domain.New("id is required"), "coupon")
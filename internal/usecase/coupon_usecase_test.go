@@ -18,7 +18,7 @@ func newCouponTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
-	assert.NoError(t, db.AutoMigrate(&repository.CouponDB{}, &repository.CouponRedemptionDB{}))
+	assert.NoError(t, db.AutoMigrate(&repository.CouponDB{}, &repository.CouponRedemptionDB{}, &repository.CouponCampaignDB{}, &repository.CouponCampaignCodeDB{}))
 	return db
 }
 
@@ -41,6 +41,13 @@ type DeleteAccountEstimateRepository interface {
 	DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error
 }
 
+// DeleteAccountAgentRepository purges one kind of agent data: memories,
+// conversations, audit log, actions, settings, insight preferences, AI usage
+// and AI quota overrides.
+type DeleteAccountAgentRepository interface {
+	DeleteAllByUserID(ctx context.Context, tx *gorm.DB, userID string) error
+}
+
 type DeleteAccountUserRepository interface {
 	Delete(ctx context.Context, tx *gorm.DB, userID string) error
 }
@@ -61,6 +68,7 @@ type DeleteAccount struct {
 	creditCardRepo  DeleteAccountCreditCardRepository
 	invoiceRepo     DeleteAccountInvoiceRepository
 	estimateRepo    DeleteAccountEstimateRepository
+	agentRepos      []DeleteAccountAgentRepository
 }
 
 func NewDeleteAccount(
@@ -75,6 +83,7 @@ func NewDeleteAccount(
 	creditCardRepo DeleteAccountCreditCardRepository,
 	invoiceRepo DeleteAccountInvoiceRepository,
 	estimateRepo DeleteAccountEstimateRepository,
+	agentRepos []DeleteAccountAgentRepository,
 ) DeleteAccount {
 	return DeleteAccount{
 		txManager:       txManager,
@@ -88,6 +97,7 @@ func NewDeleteAccount(
 		creditCardRepo:  creditCardRepo,
 		invoiceRepo:     invoiceRepo,
 		estimateRepo:    estimateRepo,
+		agentRepos:      agentRepos,
 	}
 }
 
@@ -95,6 +105,12 @@ func (u *DeleteAccount) DeleteUserAccount(ctx context.Context) error {
 	userID := ctx.Value(authentication.UserID).(string)
 
 	return u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		for _, repo := range u.agentRepos {
+			if err := repo.DeleteAllByUserID(ctx, tx, userID); err != nil {
+				return err
+			}
+		}
+
 		if err := u.estimateRepo.DeleteAllByUserID(ctx, tx, userID); err != nil {
 			return err
 		}
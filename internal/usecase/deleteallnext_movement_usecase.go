@@ -24,6 +24,10 @@ func (u *Movement) DeleteAllNext(ctx context.Context, id uuid.UUID, date time.Ti
 			return u.truncateRecurrentByID(ctx, tx, id, date)
 		}
 
+		if existingMovement.IsTransfer() {
+			return ErrTransferMovementChange
+		}
+
 		if existingMovement.IsCreditCardMovement() {
 			return u.deleteAllNextCreditCard(ctx, tx, id, &existingMovement)
 		}
@@ -86,6 +90,10 @@ func (u *Movement) truncateRecurrentByID(ctx context.Context, tx *gorm.DB, id uu
 		return fmt.Errorf("error finding recurrent movement: %w", err)
 	}
 
+	if recurrent.IsTransfer() {
+		return ErrTransferMovementChange
+	}
+
 	if date.IsZero() {
 		return ErrDateRequired
 	}
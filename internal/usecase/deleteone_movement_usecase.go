@@ -24,6 +24,10 @@ func (u *Movement) DeleteOne(ctx context.Context, id uuid.UUID, date time.Time)
 			return u.deleteRecurrentByID(ctx, tx, id, date)
 		}
 
+		if existingMovement.IsTransfer() {
+			return ErrTransferMovementChange
+		}
+
 		if existingMovement.IsCreditCardMovement() {
 			return u.deleteCreditCardMovement(ctx, tx, id, &existingMovement)
 		}
@@ -88,6 +92,10 @@ func (u *Movement) deleteRecurrentByID(ctx context.Context, tx *gorm.DB, id uuid
 		return fmt.Errorf("error finding recurrent movement: %w", err)
 	}
 
+	if recurrent.IsTransfer() {
+		return ErrTransferMovementChange
+	}
+
 	if date.IsZero() {
 		return ErrDateRequired
 	}
@@ -299,6 +299,27 @@ func TestMovement_DeleteOne(t *testing.T) {
 			},
 			expectedError: ErrDateRequired,
 		},
+		"should fail when movement is one side of a transfer": {
+			id:   fixture.MovementID.String(),
+			date: deleteDate,
+			mockSetup: func(mockMovRepo *MockMovementRepository, mockInvoiceRepo *MockInvoiceRepository, mockTxManager *MockTransactionManager, mockCreditCardRepo *MockCreditCardRepository, mockRecurrentRepo *MockRecurrentRepository, mockWalletRepo *MockWalletRepository) {
+				pairID := uuid.New()
+				existingMovement := fixture.MovementMock(
+					fixture.AsMovementExpense(100.0),
+					fixture.WithMovementTypePayment(string(domain.TypePaymentInternalTransfer)),
+				)
+				existingMovement.PairID = &pairID
+
+				mockTxManager.On("WithTransaction", mock.Anything).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(0).(func(*gorm.DB) error)
+						_ = fn(nil)
+					}).Return(ErrTransferMovementChange)
+
+				mockMovRepo.On("FindByID", fixture.MovementID).Return(existingMovement, nil)
+			},
+			expectedError: ErrTransferMovementChange,
+		},
 	}
 
 	for name, tt := range tests {
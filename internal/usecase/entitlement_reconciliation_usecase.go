@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+)
+
+type (
+	EntitlementClaimsGateway interface {
+		GetUserClaims(ctx context.Context, userID string) (gateway.UserClaims, error)
+		SetUserSubscription(ctx context.Context, userID string, plan authentication.Plan, mpSubscriptionID string, subscriptionSource authentication.SubscriptionSource, expiresAt int64) error
+	}
+
+	EntitlementSubscriptionRepository interface {
+		List(ctx context.Context, filter repository.SubscriptionListFilter) ([]domain.Subscription, error)
+	}
+)
+
+// EntitlementReconciliation brings the plan in the Firebase claims in line with
+// the subscriptions table, fixing claims left behind by lost or out-of-order
+// webhooks.
+type EntitlementReconciliation struct {
+	subRepo      EntitlementSubscriptionRepository
+	claims       EntitlementClaimsGateway
+	pastDueGrace time.Duration
+}
+
+// NewEntitlementReconciliation builds the job. pastDueGrace is how long a
+// past_due subscription without a recorded grace keeps Plus.
+func NewEntitlementReconciliation(subRepo EntitlementSubscriptionRepository, claims EntitlementClaimsGateway, pastDueGrace time.Duration) *EntitlementReconciliation {
+	return &EntitlementReconciliation{
+		subRepo:      subRepo,
+		claims:       claims,
+		pastDueGrace: pastDueGrace,
+	}
+}
+
+type EntitlementCorrection struct {
+	UserID             string                            `json:"user_id"`
+	PreviousPlan       authentication.Plan               `json:"previous_plan"`
+	PreviousExpiresAt  int64                             `json:"previous_expires_at,omitempty"`
+	Plan               authentication.Plan               `json:"plan"`
+	SubscriptionSource authentication.SubscriptionSource `json:"subscription_source,omitempty"`
+	ExpiresAt          int64                             `json:"expires_at,omitempty"`
+}
+
+type EntitlementReconciliationResult struct {
+	DryRun       bool                    `json:"dry_run"`
+	UsersChecked int                     `json:"users_checked"`
+	Corrected    int                     `json:"corrected"`
+	Failed       int                     `json:"failed"`
+	Corrections  []EntitlementCorrection `json:"corrections"`
+}
+
+// entitlement is the plan a user's claims should carry.
+type entitlement struct {
+	plan             authentication.Plan
+	source           authentication.SubscriptionSource
+	mpSubscriptionID string
+	expiresAt        int64
+}
+
+// Reconcile checks every user with a subscription and rewrites the claims that
+// drifted from the effective plan. With dryRun the corrections are only
+// reported.
+func (u *EntitlementReconciliation) Reconcile(ctx context.Context, now time.Time, dryRun bool) (EntitlementReconciliationResult, error) {
+	result := EntitlementReconciliationResult{
+		DryRun:      dryRun,
+		Corrections: []EntitlementCorrection{},
+	}
+
+	subs, err := u.subRepo.List(ctx, repository.SubscriptionListFilter{})
+	if err != nil {
+		return result, err
+	}
+
+	var userIDs []string
+	byUser := map[string][]domain.Subscription{}
+	for _, sub := range subs {
+		if _, ok := byUser[sub.UserID]; !ok {
+			userIDs = append(userIDs, sub.UserID)
+		}
+		byUser[sub.UserID] = append(byUser[sub.UserID], sub)
+	}
+
+	for _, userID := range userIDs {
+		result.UsersChecked++
+
+		claims, err := u.claims.GetUserClaims(ctx, userID)
+		if err != nil {
+			log.ErrorContext(ctx, "error reading claims for entitlement reconciliation",
+				log.String("user_id", userID),
+				log.Err(err),
+			)
+			result.Failed++
+			continue
+		}
+
+		target := entitlementFor(byUser[userID], now, u.pastDueGrace)
+		if !claimsDrifted(claims, target, now) {
+			continue
+		}
+
+		if !dryRun {
+			err := u.claims.SetUserSubscription(ctx, userID, target.plan, target.mpSubscriptionID, target.source, target.expiresAt)
+			if err != nil {
+				log.ErrorContext(ctx, "error correcting claims for entitlement reconciliation",
+					log.String("user_id", userID),
+					log.Err(err),
+				)
+				result.Failed++
+				continue
+			}
+		}
+
+		log.InfoContext(ctx, "entitlement claims corrected",
+			log.String("user_id", userID),
+			log.String("previous_plan", string(claims.Plan)),
+			log.String("plan", string(target.plan)),
+			log.Bool("dry_run", dryRun),
+		)
+		result.Corrected++
+		result.Corrections = append(result.Corrections, EntitlementCorrection{
+			UserID:             userID,
+			PreviousPlan:       claims.Plan,
+			PreviousExpiresAt:  claims.PlanExpiresAt,
+			Plan:               target.plan,
+			SubscriptionSource: target.source,
+			ExpiresAt:          target.expiresAt,
+		})
+	}
+
+	log.Info("entitlement reconciliation job completed",
+		log.Bool("dry_run", dryRun),
+		log.Int("users_checked", result.UsersChecked),
+		log.Int("corrected", result.Corrected),
+		log.Int("failed", result.Failed),
+	)
+
+	return result, nil
+}
+
+// entitlementFor mirrors what the webhooks write: a renewing subscription gives
+// Plus without expiry, a cancelled or past due one gives Plus until its access
+// ends, and without any the plan is Free.
+func entitlementFor(subs []domain.Subscription, now time.Time, pastDueGrace time.Duration) entitlement {
+	sub, until, ok := domain.EffectiveSubscription(subs, now, pastDueGrace)
+	if !ok {
+		latest := subs[0]
+		for _, s := range subs[1:] {
+			if s.UpdatedAt.After(latest.UpdatedAt) {
+				latest = s
+			}
+		}
+		return entitlement{
+			plan:   authentication.PlanFree,
+			source: claimsSourceFor(latest.Source),
+		}
+	}
+
+	target := entitlement{
+		plan:   authentication.PlanPlus,
+		source: claimsSourceFor(sub.Source),
+	}
+	if until != nil {
+		target.expiresAt = until.Unix()
+	} else if sub.Source == domain.SubscriptionSourceMercadoPago {
+		target.mpSubscriptionID = sub.ExternalID
+	}
+	return target
+}
+
+func claimsSourceFor(source domain.SubscriptionSource) authentication.SubscriptionSource {
+	switch source {
+	case domain.SubscriptionSourceMercadoPago:
+		return authentication.SubscriptionSourceMP
+	case domain.SubscriptionSourceStripe:
+		return authentication.SubscriptionSourceStripe
+	case domain.SubscriptionSourceApple, domain.SubscriptionSourceGoogle:
+		return authentication.SubscriptionSourceIAP
+	default:
+		return authentication.SubscriptionSourceNone
+	}
+}
+
+// claimsDrifted tells whether the claims grant a different plan than target.
+// A scheduled Stripe cancellation keeps an expiry on an active subscription, so
+// a future expiry is accepted when the subscription renews. Plus granted by an
+// admin, without a subscription source, is kept until it expires.
+func claimsDrifted(claims gateway.UserClaims, target entitlement, now time.Time) bool {
+	claimsPlus := claims.Plan == authentication.PlanPlus &&
+		(claims.PlanExpiresAt == 0 || claims.PlanExpiresAt > now.Unix())
+
+	if target.plan == authentication.PlanFree {
+		if claims.Plan != authentication.PlanPlus {
+			return false
+		}
+		manualGrant := claims.SubscriptionSource == authentication.SubscriptionSourceNone && claims.MPSubscriptionID == ""
+		return !manualGrant || !claimsPlus
+	}
+
+	if !claimsPlus || claims.SubscriptionSource != target.source {
+		return true
+	}
+	if target.mpSubscriptionID != "" && claims.MPSubscriptionID != target.mpSubscriptionID {
+		return true
+	}
+	if target.expiresAt > 0 {
+		return claims.PlanExpiresAt != target.expiresAt
+	}
+	return false
+}
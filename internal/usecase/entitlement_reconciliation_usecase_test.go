@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntitlementReconciliation_Reconcile(t *testing.T) {
+	log.Initialize()
+	ctx := context.Background()
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	periodEnd := now.Add(10 * 24 * time.Hour)
+	lapsed := now.Add(-30 * 24 * time.Hour)
+	graceEnd := now.Add(2 * 24 * time.Hour)
+	unpaid := now.Add(-3 * 24 * time.Hour)
+
+	tests := map[string]struct {
+		grace              time.Duration
+		subs               []domain.Subscription
+		claims             gateway.UserClaims
+		expectedCorrection *EntitlementCorrection
+	}{
+		"should grant plus to an active subscription with free claims": {
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceMercadoPago, ExternalID: "mp-1", Status: domain.SubscriptionStatusActive}},
+			claims: gateway.UserClaims{Plan: authentication.PlanFree},
+			expectedCorrection: &EntitlementCorrection{
+				UserID:             "user-1",
+				PreviousPlan:       authentication.PlanFree,
+				Plan:               authentication.PlanPlus,
+				SubscriptionSource: authentication.SubscriptionSourceMP,
+			},
+		},
+		"should keep the period end of a cancelled subscription": {
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceApple, Status: domain.SubscriptionStatusCancelled, CurrentPeriodEnd: &periodEnd}},
+			claims: gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceIAP},
+			expectedCorrection: &EntitlementCorrection{
+				UserID:             "user-1",
+				PreviousPlan:       authentication.PlanPlus,
+				Plan:               authentication.PlanPlus,
+				SubscriptionSource: authentication.SubscriptionSourceIAP,
+				ExpiresAt:          periodEnd.Unix(),
+			},
+		},
+		"should downgrade when the subscription lapsed": {
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceStripe, Status: domain.SubscriptionStatusPastDue, CurrentPeriodEnd: &lapsed}},
+			claims: gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceStripe},
+			expectedCorrection: &EntitlementCorrection{
+				UserID:             "user-1",
+				PreviousPlan:       authentication.PlanPlus,
+				Plan:               authentication.PlanFree,
+				SubscriptionSource: authentication.SubscriptionSourceStripe,
+			},
+		},
+		"should keep plus until the recorded grace of a past due subscription": {
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceStripe, Status: domain.SubscriptionStatusPastDue, CurrentPeriodEnd: &periodEnd, GraceEndsAt: &graceEnd}},
+			claims: gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceStripe},
+			expectedCorrection: &EntitlementCorrection{
+				UserID:             "user-1",
+				PreviousPlan:       authentication.PlanPlus,
+				Plan:               authentication.PlanPlus,
+				SubscriptionSource: authentication.SubscriptionSourceStripe,
+				ExpiresAt:          graceEnd.Unix(),
+			},
+		},
+		"should keep plus within the configured grace of a past due subscription": {
+			grace:  7 * 24 * time.Hour,
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceStripe, Status: domain.SubscriptionStatusPastDue, CurrentPeriodEnd: &unpaid}},
+			claims: gateway.UserClaims{Plan: authentication.PlanFree},
+			expectedCorrection: &EntitlementCorrection{
+				UserID:             "user-1",
+				PreviousPlan:       authentication.PlanFree,
+				Plan:               authentication.PlanPlus,
+				SubscriptionSource: authentication.SubscriptionSourceStripe,
+				ExpiresAt:          unpaid.Add(7 * 24 * time.Hour).Unix(),
+			},
+		},
+		"should downgrade a past due subscription past the configured grace": {
+			grace:  24 * time.Hour,
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceStripe, Status: domain.SubscriptionStatusPastDue, CurrentPeriodEnd: &unpaid}},
+			claims: gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceStripe},
+			expectedCorrection: &EntitlementCorrection{
+				UserID:             "user-1",
+				PreviousPlan:       authentication.PlanPlus,
+				Plan:               authentication.PlanFree,
+				SubscriptionSource: authentication.SubscriptionSourceStripe,
+			},
+		},
+		"should grant plus to a trial": {
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceApple, Status: domain.SubscriptionStatusTrialing, TrialEndsAt: &periodEnd}},
+			claims: gateway.UserClaims{Plan: authentication.PlanFree},
+			expectedCorrection: &EntitlementCorrection{
+				UserID:             "user-1",
+				PreviousPlan:       authentication.PlanFree,
+				Plan:               authentication.PlanPlus,
+				SubscriptionSource: authentication.SubscriptionSourceIAP,
+			},
+		},
+		"should accept a scheduled cancellation on an active subscription": {
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceStripe, Status: domain.SubscriptionStatusActive}},
+			claims: gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceStripe, PlanExpiresAt: periodEnd.Unix()},
+		},
+		"should keep plus granted by an admin": {
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceStripe, Status: domain.SubscriptionStatusExpired}},
+			claims: gateway.UserClaims{Plan: authentication.PlanPlus, PlanExpiresAt: periodEnd.Unix()},
+		},
+		"should not touch claims in line with the subscriptions": {
+			subs:   []domain.Subscription{{UserID: "user-1", Source: domain.SubscriptionSourceMercadoPago, ExternalID: "mp-1", Status: domain.SubscriptionStatusActive}},
+			claims: gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceMP, MPSubscriptionID: "mp-1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			subRepo := new(MockSubscriptionRepo)
+			claimsGateway := new(MockFirebaseSubGateway)
+			subRepo.On("List", ctx, repository.SubscriptionListFilter{}).Return(tt.subs, nil)
+			claimsGateway.On("GetUserClaims", ctx, "user-1").Return(tt.claims, nil)
+			claimsGateway.On("SetUserSubscription", ctx, "user-1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			grace := tt.grace
+			if grace == 0 {
+				grace = 7 * 24 * time.Hour
+			}
+
+			result, err := NewEntitlementReconciliation(subRepo, claimsGateway, grace).Reconcile(ctx, now, false)
+
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.UsersChecked)
+			if tt.expectedCorrection == nil {
+				assert.Empty(t, result.Corrections)
+				claimsGateway.AssertNotCalled(t, "SetUserSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, []EntitlementCorrection{*tt.expectedCorrection}, result.Corrections)
+			c := tt.expectedCorrection
+			mpSubscriptionID := ""
+			if c.ExpiresAt == 0 && c.SubscriptionSource == authentication.SubscriptionSourceMP && c.Plan == authentication.PlanPlus {
+				mpSubscriptionID = tt.subs[0].ExternalID
+			}
+			claimsGateway.AssertCalled(t, "SetUserSubscription", ctx, "user-1", c.Plan, mpSubscriptionID, c.SubscriptionSource, c.ExpiresAt)
+		})
+	}
+}
+
+func TestEntitlementReconciliation_Reconcile_DryRunAndFailures(t *testing.T) {
+	log.Initialize()
+	ctx := context.Background()
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+
+	subRepo := new(MockSubscriptionRepo)
+	claimsGateway := new(MockFirebaseSubGateway)
+	subRepo.On("List", ctx, repository.SubscriptionListFilter{}).Return([]domain.Subscription{
+		{UserID: "user-1", Source: domain.SubscriptionSourceGoogle, Status: domain.SubscriptionStatusActive},
+		{UserID: "user-2", Source: domain.SubscriptionSourceGoogle, Status: domain.SubscriptionStatusActive},
+	}, nil)
+	claimsGateway.On("GetUserClaims", ctx, "user-1").Return(gateway.UserClaims{Plan: authentication.PlanFree}, nil)
+	claimsGateway.On("GetUserClaims", ctx, "user-2").Return(gateway.UserClaims{}, errors.New("user not found"))
+
+	result, err := NewEntitlementReconciliation(subRepo, claimsGateway, 7*24*time.Hour).Reconcile(ctx, now, true)
+
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 2, result.UsersChecked)
+	assert.Equal(t, 1, result.Corrected)
+	assert.Equal(t, 1, result.Failed)
+	claimsGateway.AssertNotCalled(t, "SetUserSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
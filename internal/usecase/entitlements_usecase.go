@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+)
+
+type (
+	PlanEntitlementsRepository interface {
+		FindByID(ctx context.Context, id string) (domain.SubscriptionPlan, error)
+		UpdateEntitlements(ctx context.Context, id string, ent *domain.Entitlements) (domain.SubscriptionPlan, error)
+	}
+
+	UserSubscriptionsRepository interface {
+		FindByUserID(ctx context.Context, userID string) ([]domain.Subscription, error)
+	}
+
+	// EntitlementResolver gives the entitlements of the current user.
+	EntitlementResolver interface {
+		Resolve(ctx context.Context) (domain.Entitlements, error)
+	}
+)
+
+// Entitlements resolves what the plan of the current user allows. Free users
+// get the entitlements of the free plan; paying users those of the plan of
+// their effective subscription. A plan without entitlements uses the defaults
+// of its tier.
+type Entitlements struct {
+	planRepo     PlanEntitlementsRepository
+	subRepo      UserSubscriptionsRepository
+	pastDueGrace time.Duration
+}
+
+func NewEntitlements(planRepo PlanEntitlementsRepository, subRepo UserSubscriptionsRepository) *Entitlements {
+	return &Entitlements{
+		planRepo:     planRepo,
+		subRepo:      subRepo,
+		pastDueGrace: authentication.GetPastDueGrace(),
+	}
+}
+
+func (u *Entitlements) Resolve(ctx context.Context) (domain.Entitlements, error) {
+	auth, ok := authentication.AuthFromContext(ctx)
+	if !ok {
+		return domain.Entitlements{}, ErrUnauthorized
+	}
+
+	planID := domain.FreePlanID
+	if !auth.IsFree() {
+		subs, err := u.subRepo.FindByUserID(ctx, auth.UserID)
+		if err != nil {
+			return domain.Entitlements{}, err
+		}
+		// Plus granted by an admin has no subscription, hence no plan.
+		sub, _, found := domain.EffectiveSubscription(subs, time.Now(), u.pastDueGrace)
+		planID = ""
+		if found {
+			planID = sub.PlanID
+		}
+	}
+
+	if planID != "" {
+		plan, err := u.planRepo.FindByID(ctx, planID)
+		if err != nil && !errors.Is(err, repository.ErrSubscriptionPlanNotFound) {
+			return domain.Entitlements{}, err
+		}
+		if err == nil && plan.Entitlements != nil {
+			return *plan.Entitlements, nil
+		}
+	}
+
+	return defaultEntitlements(auth.Plan), nil
+}
+
+// CheckFeature returns ErrFeatureNotInPlan when the plan of the current user
+// turns the feature off.
+func (u *Entitlements) CheckFeature(ctx context.Context, feature domain.Feature) error {
+	ent, err := u.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+	if !ent.HasFeature(feature) {
+		return ErrFeatureNotInPlan
+	}
+	return nil
+}
+
+// --- Admin ---
+
+func (u *Entitlements) GetPlan(ctx context.Context, planID string) (domain.SubscriptionPlan, error) {
+	return u.planRepo.FindByID(ctx, planID)
+}
+
+// SetPlanEntitlements replaces the entitlements of a plan; nil puts it back on
+// the defaults of its tier.
+func (u *Entitlements) SetPlanEntitlements(ctx context.Context, planID string, ent *domain.Entitlements) (domain.SubscriptionPlan, error) {
+	if ent != nil {
+		if err := ent.Validate(); err != nil {
+			return domain.SubscriptionPlan{}, err
+		}
+	}
+	return u.planRepo.UpdateEntitlements(ctx, planID, ent)
+}
+
+// defaultEntitlements are the limits from the PLAN_* env vars, with every
+// feature enabled. Plus is only limited in AI usage.
+func defaultEntitlements(plan authentication.Plan) domain.Entitlements {
+	limits := map[domain.LimitName]int{}
+	if plan != authentication.PlanPlus {
+		free := authentication.GetFreePlanLimits()
+		limits[domain.LimitWallets] = free.Wallets
+		limits[domain.LimitCreditCards] = free.CreditCards
+		limits[domain.LimitMovementsPerMonth] = free.MovementsPerMonth
+		limits[domain.LimitRecurrencesPerMonth] = free.RecurrencesPerMonth
+	}
+
+	aiQuota := authentication.GetPlanAIQuota(plan)
+	if aiQuota.TokensPerMonth > 0 {
+		limits[domain.LimitAITokensPerMonth] = aiQuota.TokensPerMonth
+	}
+	if aiQuota.RequestsPerMonth > 0 {
+		limits[domain.LimitAIRequestsPerMonth] = aiQuota.RequestsPerMonth
+	}
+
+	return domain.Entitlements{Limits: limits}
+}
+
+// planLimitsFromEntitlements fills the limits of the /me/limits response, where
+// zero means unlimited.
+func planLimitsFromEntitlements(ent domain.Entitlements) authentication.PlanLimits {
+	limit := func(name domain.LimitName) int {
+		value, _ := ent.Limit(name)
+		return value
+	}
+	return authentication.PlanLimits{
+		Wallets:             limit(domain.LimitWallets),
+		CreditCards:         limit(domain.LimitCreditCards),
+		MovementsPerMonth:   limit(domain.LimitMovementsPerMonth),
+		RecurrencesPerMonth: limit(domain.LimitRecurrencesPerMonth),
+		AIQuota:             aiQuotaFromEntitlements(ent),
+	}
+}
+
+func aiQuotaFromEntitlements(ent domain.Entitlements) authentication.AIQuota {
+	tokens, _ := ent.Limit(domain.LimitAITokensPerMonth)
+	requests, _ := ent.Limit(domain.LimitAIRequestsPerMonth)
+	return authentication.AIQuota{
+		TokensPerMonth:   tokens,
+		RequestsPerMonth: requests,
+	}
+}
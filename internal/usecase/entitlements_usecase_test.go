@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func entitlementsContext(plan authentication.Plan) context.Context {
+	return authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-1", Plan: plan})
+}
+
+func TestEntitlements_Resolve(t *testing.T) {
+	t.Setenv("PLAN_FREE_WALLETS_LIMIT", "2")
+
+	familyEntitlements := domain.Entitlements{Limits: map[domain.LimitName]int{domain.LimitWallets: 10}}
+	noChat := domain.Entitlements{Features: map[domain.Feature]bool{domain.FeatureAgentChat: false}}
+	activeFamily := domain.Subscription{UserID: "user-1", PlanID: "family", Status: domain.SubscriptionStatusActive}
+
+	tests := map[string]struct {
+		plan          authentication.Plan
+		subs          []domain.Subscription
+		planID        string
+		planResult    domain.SubscriptionPlan
+		planErr       error
+		expected      func(t *testing.T, ent domain.Entitlements)
+		expectedErr   error
+		expectNoPlans bool
+	}{
+		"should use the free plan entitlements for a free user": {
+			plan:       authentication.PlanFree,
+			planID:     domain.FreePlanID,
+			planResult: domain.SubscriptionPlan{ID: domain.FreePlanID, Entitlements: &noChat},
+			expected: func(t *testing.T, ent domain.Entitlements) {
+				assert.False(t, ent.HasFeature(domain.FeatureAgentChat))
+			},
+		},
+		"should fall back to the env limits while the free plan has no entitlements": {
+			plan:       authentication.PlanFree,
+			planID:     domain.FreePlanID,
+			planResult: domain.SubscriptionPlan{ID: domain.FreePlanID},
+			expected: func(t *testing.T, ent domain.Entitlements) {
+				limit, ok := ent.Limit(domain.LimitWallets)
+				assert.True(t, ok)
+				assert.Equal(t, 2, limit)
+			},
+		},
+		"should use the plan of the effective subscription for a paying user": {
+			plan:       authentication.PlanPlus,
+			subs:       []domain.Subscription{activeFamily},
+			planID:     "family",
+			planResult: domain.SubscriptionPlan{ID: "family", Entitlements: &familyEntitlements},
+			expected: func(t *testing.T, ent domain.Entitlements) {
+				limit, _ := ent.Limit(domain.LimitWallets)
+				assert.Equal(t, 10, limit)
+			},
+		},
+		"should give the plus defaults to plus granted by an admin": {
+			plan:          authentication.PlanPlus,
+			expectNoPlans: true,
+			expected: func(t *testing.T, ent domain.Entitlements) {
+				_, limited := ent.Limit(domain.LimitWallets)
+				assert.False(t, limited)
+				assert.True(t, ent.HasFeature(domain.FeatureStatementImport))
+			},
+		},
+		"should give the plus defaults when the plan no longer exists": {
+			plan:    authentication.PlanPlus,
+			subs:    []domain.Subscription{activeFamily},
+			planID:  "family",
+			planErr: repository.ErrSubscriptionPlanNotFound,
+			expected: func(t *testing.T, ent domain.Entitlements) {
+				_, limited := ent.Limit(domain.LimitMovementsPerMonth)
+				assert.False(t, limited)
+			},
+		},
+		"should propagate repository errors": {
+			plan:        authentication.PlanFree,
+			planID:      domain.FreePlanID,
+			planErr:     errors.New("db down"),
+			expectedErr: errors.New("db down"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			planRepo := new(MockPlanEntitlementsRepository)
+			subRepo := new(MockUserSubscriptionsRepository)
+			subRepo.On("FindByUserID", "user-1").Return(tt.subs, nil)
+			planRepo.On("FindByID", tt.planID).Return(tt.planResult, tt.planErr)
+
+			ent, err := NewEntitlements(planRepo, subRepo).Resolve(entitlementsContext(tt.plan))
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			tt.expected(t, ent)
+			if tt.expectNoPlans {
+				planRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestEntitlements_CheckFeature(t *testing.T) {
+	planRepo := new(MockPlanEntitlementsRepository)
+	planRepo.On("FindByID", domain.FreePlanID).Return(domain.SubscriptionPlan{
+		Entitlements: &domain.Entitlements{Features: map[domain.Feature]bool{domain.FeatureStatementImport: false}},
+	}, nil)
+	entitlements := NewEntitlements(planRepo, new(MockUserSubscriptionsRepository))
+	ctx := entitlementsContext(authentication.PlanFree)
+
+	assert.ErrorIs(t, entitlements.CheckFeature(ctx, domain.FeatureStatementImport), ErrFeatureNotInPlan)
+	assert.NoError(t, entitlements.CheckFeature(ctx, domain.FeatureAgentChat))
+	assert.ErrorIs(t, entitlements.CheckFeature(context.Background(), domain.FeatureAgentChat), ErrUnauthorized)
+}
+
+func TestEntitlements_SetPlanEntitlements(t *testing.T) {
+	t.Run("should reject invalid entitlements", func(t *testing.T) {
+		planRepo := new(MockPlanEntitlementsRepository)
+
+		_, err := NewEntitlements(planRepo, new(MockUserSubscriptionsRepository)).
+			SetPlanEntitlements(context.Background(), "plus_monthly", &domain.Entitlements{Limits: map[domain.LimitName]int{domain.LimitWallets: -1}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		planRepo.AssertNotCalled(t, "UpdateEntitlements", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reset a plan to its defaults", func(t *testing.T) {
+		planRepo := new(MockPlanEntitlementsRepository)
+		planRepo.On("UpdateEntitlements", "plus_monthly", (*domain.Entitlements)(nil)).Return(domain.SubscriptionPlan{ID: "plus_monthly"}, nil)
+
+		plan, err := NewEntitlements(planRepo, new(MockUserSubscriptionsRepository)).
+			SetPlanEntitlements(context.Background(), "plus_monthly", nil)
+
+		require.NoError(t, err)
+		assert.Nil(t, plan.Entitlements)
+	})
+}
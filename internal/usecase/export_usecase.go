@@ -41,6 +41,10 @@ type ExportEstimateRepository interface {
 	FindAllSubCategoriesByUserID(ctx context.Context) ([]domain.EstimateSubCategories, error)
 }
 
+type ExportAgentData interface {
+	ExportData(ctx context.Context) (domain.UserDataExportAgent, error)
+}
+
 type Export struct {
 	userRepo        UserRepository
 	userConsentRepo UserConsentRepository
@@ -52,6 +56,7 @@ type Export struct {
 	creditCardRepo  ExportCreditCardRepository
 	invoiceRepo     ExportInvoiceRepository
 	estimateRepo    ExportEstimateRepository
+	agentData       ExportAgentData
 }
 
 func NewExport(
@@ -65,6 +70,7 @@ func NewExport(
 	creditCardRepo ExportCreditCardRepository,
 	invoiceRepo ExportInvoiceRepository,
 	estimateRepo ExportEstimateRepository,
+	agentData ExportAgentData,
 ) Export {
 	return Export{
 		userRepo:        userRepo,
@@ -77,6 +83,7 @@ func NewExport(
 		creditCardRepo:  creditCardRepo,
 		invoiceRepo:     invoiceRepo,
 		estimateRepo:    estimateRepo,
+		agentData:       agentData,
 	}
 }
 
@@ -145,6 +152,11 @@ func (u *Export) ExportUserData(ctx context.Context) (domain.UserDataExport, err
 		export.Estimates.SubCategories = estSubCategories
 	}
 
+	agentData, err := u.agentData.ExportData(ctx)
+	if err == nil {
+		export.Agent = &agentData
+	}
+
 	return export, nil
 }
 
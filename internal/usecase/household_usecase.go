@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// HouseholdInvitationPrefix tells invitation tokens apart from access tokens
+	// when pasted by users.
+	HouseholdInvitationPrefix = "hhi_"
+
+	householdInvitationTTL         = 7 * 24 * time.Hour
+	householdInvitationRandomBytes = 24
+	householdNameMaxLen            = 100
+)
+
+type HouseholdRepository interface {
+	Create(ctx context.Context, household domain.Household) (domain.Household, error)
+	FindByID(ctx context.Context, id uuid.UUID) (domain.Household, error)
+	ListByMember(ctx context.Context, userID string) ([]domain.Household, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Memberships(ctx context.Context, userID string) ([]domain.HouseholdMembership, error)
+	FindMember(ctx context.Context, householdID uuid.UUID, userID string) (domain.HouseholdMember, error)
+	UpdateMemberRole(ctx context.Context, householdID uuid.UUID, userID string, role domain.HouseholdRole) error
+	RemoveMember(ctx context.Context, householdID uuid.UUID, userID string) error
+	CreateInvitation(ctx context.Context, invitation domain.HouseholdInvitation) (domain.HouseholdInvitation, error)
+	ListInvitations(ctx context.Context, householdID uuid.UUID) ([]domain.HouseholdInvitation, error)
+	FindInvitationByHash(ctx context.Context, tokenHash string) (domain.HouseholdInvitation, error)
+	RevokeInvitation(ctx context.Context, householdID, id uuid.UUID, revokedAt time.Time) error
+	AcceptInvitation(ctx context.Context, invitation domain.HouseholdInvitation, member domain.HouseholdMember) error
+	AddShare(ctx context.Context, share domain.HouseholdShare) (domain.HouseholdShare, error)
+	FindShare(ctx context.Context, householdID uuid.UUID, resource domain.HouseholdResource, resourceID uuid.UUID) (domain.HouseholdShare, error)
+	RemoveShare(ctx context.Context, householdID uuid.UUID, resource domain.HouseholdResource, resourceID uuid.UUID) error
+	ResourceOwner(ctx context.Context, resource domain.HouseholdResource, resourceID uuid.UUID) (string, error)
+}
+
+// Households manages the households users create to share wallets, cards,
+// categories and estimates, their members and invitations.
+type Households struct {
+	repo HouseholdRepository
+}
+
+func NewHouseholds(repo HouseholdRepository) *Households {
+	return &Households{repo: repo}
+}
+
+type InviteHouseholdMemberInput struct {
+	Email string
+	Role  domain.HouseholdRole
+}
+
+// CreatedHouseholdInvitation carries the invitation token, which is not stored
+// and cannot be shown again.
+type CreatedHouseholdInvitation struct {
+	domain.HouseholdInvitation
+	Token string `json:"token"`
+}
+
+type HouseholdShareInput struct {
+	ResourceType domain.HouseholdResource
+	ResourceID   uuid.UUID
+}
+
+func (u *Households) Create(ctx context.Context, name string) (domain.Household, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.Household{}, domain.ErrUnauthorized
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > householdNameMaxLen {
+		return domain.Household{}, domain.WrapInvalidInput(domain.New(fmt.Sprintf("name is required and must have up to %d characters", householdNameMaxLen)), "household")
+	}
+
+	return u.repo.Create(ctx, domain.Household{Name: name, OwnerID: userID})
+}
+
+func (u *Households) List(ctx context.Context) ([]domain.Household, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	return u.repo.ListByMember(ctx, userID)
+}
+
+// Delete removes the household, its members, invitations and shares. Only the
+// owner can delete it.
+func (u *Households) Delete(ctx context.Context, householdID uuid.UUID) error {
+	if _, err := u.requireRole(ctx, householdID, domain.HouseholdRoleOwner); err != nil {
+		return err
+	}
+	return u.repo.Delete(ctx, householdID)
+}
+
+// Memberships returns the households of the user with its role in each, for
+// the repositories to reach the resources shared with them.
+func (u *Households) Memberships(ctx context.Context) ([]domain.HouseholdMembership, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, nil
+	}
+	return u.repo.Memberships(ctx, userID)
+}
+
+// Invite creates an invitation to join the household as editor or viewer. The
+// owner sends the returned token to the invited user.
+func (u *Households) Invite(ctx context.Context, householdID uuid.UUID, input InviteHouseholdMemberInput) (CreatedHouseholdInvitation, error) {
+	userID, err := u.requireRole(ctx, householdID, domain.HouseholdRoleOwner)
+	if err != nil {
+		return CreatedHouseholdInvitation{}, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if !strings.Contains(email, "@") {
+		return CreatedHouseholdInvitation{}, domain.WrapInvalidInput(domain.New("a valid email is required"), "household invitation")
+	}
+	if err := validateMemberRole(input.Role); err != nil {
+		return CreatedHouseholdInvitation{}, err
+	}
+
+	token, err := newHouseholdInvitationToken()
+	if err != nil {
+		return CreatedHouseholdInvitation{}, err
+	}
+
+	now := time.Now()
+	invitation, err := u.repo.CreateInvitation(ctx, domain.HouseholdInvitation{
+		HouseholdID: householdID,
+		Email:       email,
+		Role:        input.Role,
+		TokenHash:   hashHouseholdInvitationToken(token),
+		InvitedBy:   userID,
+		ExpiresAt:   now.Add(householdInvitationTTL),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return CreatedHouseholdInvitation{}, err
+	}
+	return CreatedHouseholdInvitation{HouseholdInvitation: invitation, Token: token}, nil
+}
+
+func (u *Households) ListInvitations(ctx context.Context, householdID uuid.UUID) ([]domain.HouseholdInvitation, error) {
+	if _, err := u.requireRole(ctx, householdID, domain.HouseholdRoleOwner); err != nil {
+		return nil, err
+	}
+	return u.repo.ListInvitations(ctx, householdID)
+}
+
+func (u *Households) RevokeInvitation(ctx context.Context, householdID, invitationID uuid.UUID) error {
+	if _, err := u.requireRole(ctx, householdID, domain.HouseholdRoleOwner); err != nil {
+		return err
+	}
+	return u.repo.RevokeInvitation(ctx, householdID, invitationID, time.Now())
+}
+
+// AcceptInvitation adds the user to the household of a pending invitation.
+// When the user's email is known it must match the invited email.
+func (u *Households) AcceptInvitation(ctx context.Context, token string) (domain.Household, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.Household{}, domain.ErrUnauthorized
+	}
+
+	invitation, err := u.repo.FindInvitationByHash(ctx, hashHouseholdInvitationToken(strings.TrimSpace(token)))
+	if err != nil {
+		if errors.Is(err, domain.ErrHouseholdInvitationNotFound) {
+			return domain.Household{}, domain.ErrHouseholdInvitationInvalid
+		}
+		return domain.Household{}, err
+	}
+
+	now := time.Now()
+	if !invitation.IsPending(now) {
+		return domain.Household{}, domain.ErrHouseholdInvitationInvalid
+	}
+	if authCtx, ok := authentication.AuthFromContext(ctx); ok && authCtx.Email != "" &&
+		!strings.EqualFold(authCtx.Email, invitation.Email) {
+		return domain.Household{}, domain.ErrHouseholdInvitationInvalid
+	}
+
+	if _, err := u.repo.FindMember(ctx, invitation.HouseholdID, userID); err == nil {
+		return domain.Household{}, domain.ErrHouseholdAlreadyMember
+	} else if !errors.Is(err, domain.ErrHouseholdMemberNotFound) {
+		return domain.Household{}, err
+	}
+
+	err = u.repo.AcceptInvitation(ctx, invitation, domain.HouseholdMember{
+		HouseholdID: invitation.HouseholdID,
+		UserID:      userID,
+		Role:        invitation.Role,
+		JoinedAt:    now,
+	})
+	if err != nil {
+		return domain.Household{}, err
+	}
+	return u.repo.FindByID(ctx, invitation.HouseholdID)
+}
+
+// UpdateMemberRole switches a member between editor and viewer.
+func (u *Households) UpdateMemberRole(ctx context.Context, householdID uuid.UUID, memberID string, role domain.HouseholdRole) error {
+	userID, err := u.requireRole(ctx, householdID, domain.HouseholdRoleOwner)
+	if err != nil {
+		return err
+	}
+	if memberID == userID {
+		return domain.WrapInvalidInput(domain.New("the owner role cannot be changed"), "household member")
+	}
+	if err := validateMemberRole(role); err != nil {
+		return err
+	}
+	return u.repo.UpdateMemberRole(ctx, householdID, memberID, role)
+}
+
+// RemoveMember removes a member, or lets a member leave the household. The
+// owner cannot leave; it deletes the household instead.
+func (u *Households) RemoveMember(ctx context.Context, householdID uuid.UUID, memberID string) error {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.ErrUnauthorized
+	}
+
+	member, err := u.member(ctx, householdID, userID)
+	if err != nil {
+		return err
+	}
+	if memberID == userID {
+		if member.Role == domain.HouseholdRoleOwner {
+			return domain.WrapInvalidInput(domain.New("the owner cannot leave the household"), "household member")
+		}
+		return u.repo.RemoveMember(ctx, householdID, memberID)
+	}
+
+	if member.Role != domain.HouseholdRoleOwner {
+		return domain.ErrHouseholdForbidden
+	}
+	return u.repo.RemoveMember(ctx, householdID, memberID)
+}
+
+// Share shares a resource of the user with the household. Viewers only see
+// what the others share.
+func (u *Households) Share(ctx context.Context, householdID uuid.UUID, input HouseholdShareInput) (domain.HouseholdShare, error) {
+	userID, err := u.requireRole(ctx, householdID, domain.HouseholdRoleEditor)
+	if err != nil {
+		return domain.HouseholdShare{}, err
+	}
+	if !input.ResourceType.IsValid() {
+		return domain.HouseholdShare{}, domain.WrapInvalidInput(
+			domain.New(fmt.Sprintf("invalid resource_type %q: must be one of [wallet, credit_card, category, estimate]", input.ResourceType)),
+			"household share",
+		)
+	}
+
+	owner, err := u.repo.ResourceOwner(ctx, input.ResourceType, input.ResourceID)
+	if err != nil {
+		return domain.HouseholdShare{}, err
+	}
+	if owner != userID {
+		return domain.HouseholdShare{}, domain.ErrHouseholdForbidden
+	}
+
+	if _, err := u.repo.FindShare(ctx, householdID, input.ResourceType, input.ResourceID); err == nil {
+		return domain.HouseholdShare{}, domain.WrapConflict(domain.New("resource already shared"), "household share")
+	} else if !errors.Is(err, domain.ErrHouseholdShareNotFound) {
+		return domain.HouseholdShare{}, err
+	}
+
+	return u.repo.AddShare(ctx, domain.HouseholdShare{
+		HouseholdID:  householdID,
+		ResourceType: input.ResourceType,
+		ResourceID:   input.ResourceID,
+		SharedBy:     userID,
+		CreatedAt:    time.Now(),
+	})
+}
+
+// Unshare stops sharing a resource. The member who shared it and the owner of
+// the household can do it.
+func (u *Households) Unshare(ctx context.Context, householdID uuid.UUID, resource domain.HouseholdResource, resourceID uuid.UUID) error {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.ErrUnauthorized
+	}
+
+	member, err := u.member(ctx, householdID, userID)
+	if err != nil {
+		return err
+	}
+	share, err := u.repo.FindShare(ctx, householdID, resource, resourceID)
+	if err != nil {
+		return err
+	}
+	if share.SharedBy != userID && member.Role != domain.HouseholdRoleOwner {
+		return domain.ErrHouseholdForbidden
+	}
+	return u.repo.RemoveShare(ctx, householdID, resource, resourceID)
+}
+
+// requireRole returns the user when its role in the household is at least
+// minRole: owner > editor > viewer.
+func (u *Households) requireRole(ctx context.Context, householdID uuid.UUID, minRole domain.HouseholdRole) (string, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return "", domain.ErrUnauthorized
+	}
+
+	member, err := u.member(ctx, householdID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	switch minRole {
+	case domain.HouseholdRoleOwner:
+		if member.Role != domain.HouseholdRoleOwner {
+			return "", domain.ErrHouseholdForbidden
+		}
+	case domain.HouseholdRoleEditor:
+		if !member.Role.CanWrite() {
+			return "", domain.ErrHouseholdForbidden
+		}
+	}
+	return userID, nil
+}
+
+// member hides the households the user is not part of behind not found.
+func (u *Households) member(ctx context.Context, householdID uuid.UUID, userID string) (domain.HouseholdMember, error) {
+	member, err := u.repo.FindMember(ctx, householdID, userID)
+	if errors.Is(err, domain.ErrHouseholdMemberNotFound) {
+		return domain.HouseholdMember{}, domain.ErrHouseholdNotFound
+	}
+	return member, err
+}
+
+func validateMemberRole(role domain.HouseholdRole) error {
+	if role != domain.HouseholdRoleEditor && role != domain.HouseholdRoleViewer {
+		return domain.WrapInvalidInput(domain.New(fmt.Sprintf("invalid role %q: must be editor or viewer", role)), "household member")
+	}
+	return nil
+}
+
+func newHouseholdInvitationToken() (string, error) {
+	buf := make([]byte, householdInvitationRandomBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generating household invitation: %w", err)
+	}
+	return HouseholdInvitationPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashHouseholdInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newHouseholdTestRepo(t *testing.T) (*repository.HouseholdRepository, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&repository.HouseholdDB{}, &repository.HouseholdMemberDB{},
+		&repository.HouseholdInvitationDB{}, &repository.HouseholdShareDB{},
+		&repository.WalletDB{},
+	))
+	return repository.NewHouseholdRepository(db), db
+}
+
+func householdTestContext(userID, email string) context.Context {
+	return authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: userID, Email: email})
+}
+
+// newTestHousehold creates a household of "owner" with an "editor" and a
+// "viewer" member.
+func newTestHousehold(t *testing.T, uc *Households, db *gorm.DB) domain.Household {
+	t.Helper()
+	household, err := uc.Create(householdTestContext("owner", ""), "Casa")
+	require.NoError(t, err)
+	for _, role := range []domain.HouseholdRole{domain.HouseholdRoleEditor, domain.HouseholdRoleViewer} {
+		require.NoError(t, db.Create(&repository.HouseholdMemberDB{
+			HouseholdID: household.ID,
+			UserID:      string(role),
+			Role:        string(role),
+			JoinedAt:    time.Now(),
+		}).Error)
+	}
+	return household
+}
+
+func TestHouseholds_Create(t *testing.T) {
+	repo, _ := newHouseholdTestRepo(t)
+	uc := NewHouseholds(repo)
+
+	_, err := uc.Create(householdTestContext("owner", ""), "   ")
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	_, err = uc.Create(context.Background(), "Casa")
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+
+	household, err := uc.Create(householdTestContext("owner", ""), " Casa ")
+	require.NoError(t, err)
+	assert.Equal(t, "Casa", household.Name)
+	assert.Equal(t, "owner", household.OwnerID)
+}
+
+func TestHouseholds_Invite(t *testing.T) {
+	repo, db := newHouseholdTestRepo(t)
+	uc := NewHouseholds(repo)
+	household := newTestHousehold(t, uc, db)
+
+	tests := map[string]struct {
+		ctx         context.Context
+		input       InviteHouseholdMemberInput
+		expectedErr error
+	}{
+		"should not let an editor invite": {
+			ctx:         householdTestContext("editor", ""),
+			input:       InviteHouseholdMemberInput{Email: "new@example.com", Role: domain.HouseholdRoleViewer},
+			expectedErr: domain.ErrHouseholdForbidden,
+		},
+		"should hide the household from non members": {
+			ctx:         householdTestContext("stranger", ""),
+			input:       InviteHouseholdMemberInput{Email: "new@example.com", Role: domain.HouseholdRoleViewer},
+			expectedErr: domain.ErrHouseholdNotFound,
+		},
+		"should not invite another owner": {
+			ctx:         householdTestContext("owner", ""),
+			input:       InviteHouseholdMemberInput{Email: "new@example.com", Role: domain.HouseholdRoleOwner},
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should reject an invalid email": {
+			ctx:         householdTestContext("owner", ""),
+			input:       InviteHouseholdMemberInput{Email: "new", Role: domain.HouseholdRoleViewer},
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should invite an editor": {
+			ctx:   householdTestContext("owner", ""),
+			input: InviteHouseholdMemberInput{Email: " New@Example.com ", Role: domain.HouseholdRoleEditor},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			created, err := uc.Invite(tt.ctx, household.ID, tt.input)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "new@example.com", created.Email)
+			assert.Equal(t, "owner", created.InvitedBy)
+			assert.Contains(t, created.Token, HouseholdInvitationPrefix)
+			assert.NotContains(t, created.TokenHash, HouseholdInvitationPrefix, "only the hash is stored")
+		})
+	}
+}
+
+func TestHouseholds_AcceptInvitation(t *testing.T) {
+	repo, db := newHouseholdTestRepo(t)
+	uc := NewHouseholds(repo)
+	household := newTestHousehold(t, uc, db)
+	ownerCtx := householdTestContext("owner", "")
+
+	created, err := uc.Invite(ownerCtx, household.ID, InviteHouseholdMemberInput{Email: "member@example.com", Role: domain.HouseholdRoleEditor})
+	require.NoError(t, err)
+
+	t.Run("should reject an unknown token", func(t *testing.T) {
+		_, err := uc.AcceptInvitation(householdTestContext("member", "member@example.com"), HouseholdInvitationPrefix+"unknown")
+		assert.ErrorIs(t, err, domain.ErrHouseholdInvitationInvalid)
+	})
+
+	t.Run("should reject another email", func(t *testing.T) {
+		_, err := uc.AcceptInvitation(householdTestContext("other", "other@example.com"), created.Token)
+		assert.ErrorIs(t, err, domain.ErrHouseholdInvitationInvalid)
+	})
+
+	t.Run("should reject a user already in the household", func(t *testing.T) {
+		_, err := uc.AcceptInvitation(householdTestContext("viewer", ""), created.Token)
+		assert.ErrorIs(t, err, domain.ErrHouseholdAlreadyMember)
+	})
+
+	t.Run("should accept the invitation once", func(t *testing.T) {
+		joined, err := uc.AcceptInvitation(householdTestContext("member", "MEMBER@example.com"), created.Token)
+		require.NoError(t, err)
+		assert.Equal(t, household.ID, joined.ID)
+		assert.Len(t, joined.Members, 4)
+
+		member, err := repo.FindMember(context.Background(), household.ID, "member")
+		require.NoError(t, err)
+		assert.Equal(t, domain.HouseholdRoleEditor, member.Role)
+
+		_, err = uc.AcceptInvitation(householdTestContext("late", ""), created.Token)
+		assert.ErrorIs(t, err, domain.ErrHouseholdInvitationInvalid)
+	})
+
+	t.Run("should reject a revoked invitation", func(t *testing.T) {
+		revoked, err := uc.Invite(ownerCtx, household.ID, InviteHouseholdMemberInput{Email: "revoked@example.com", Role: domain.HouseholdRoleViewer})
+		require.NoError(t, err)
+		require.NoError(t, uc.RevokeInvitation(ownerCtx, household.ID, revoked.ID))
+
+		_, err = uc.AcceptInvitation(householdTestContext("revoked", "revoked@example.com"), revoked.Token)
+		assert.ErrorIs(t, err, domain.ErrHouseholdInvitationInvalid)
+	})
+
+	t.Run("should reject an expired invitation", func(t *testing.T) {
+		token, err := newHouseholdInvitationToken()
+		require.NoError(t, err)
+		_, err = repo.CreateInvitation(context.Background(), domain.HouseholdInvitation{
+			HouseholdID: household.ID,
+			Email:       "expired@example.com",
+			Role:        domain.HouseholdRoleViewer,
+			TokenHash:   hashHouseholdInvitationToken(token),
+			InvitedBy:   "owner",
+			ExpiresAt:   time.Now().Add(-time.Minute),
+			CreatedAt:   time.Now().Add(-householdInvitationTTL),
+		})
+		require.NoError(t, err)
+
+		_, err = uc.AcceptInvitation(householdTestContext("expired", "expired@example.com"), token)
+		assert.ErrorIs(t, err, domain.ErrHouseholdInvitationInvalid)
+	})
+}
+
+func TestHouseholds_RemoveMember(t *testing.T) {
+	tests := map[string]struct {
+		userID      string
+		memberID    string
+		expectedErr error
+	}{
+		"should not let the owner leave": {
+			userID:      "owner",
+			memberID:    "owner",
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should not let an editor remove another member": {
+			userID:      "editor",
+			memberID:    "viewer",
+			expectedErr: domain.ErrHouseholdForbidden,
+		},
+		"should let a viewer leave": {
+			userID:   "viewer",
+			memberID: "viewer",
+		},
+		"should let the owner remove an editor": {
+			userID:   "owner",
+			memberID: "editor",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, db := newHouseholdTestRepo(t)
+			uc := NewHouseholds(repo)
+			household := newTestHousehold(t, uc, db)
+
+			err := uc.RemoveMember(householdTestContext(tt.userID, ""), household.ID, tt.memberID)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			_, err = repo.FindMember(context.Background(), household.ID, tt.memberID)
+			assert.ErrorIs(t, err, domain.ErrHouseholdMemberNotFound)
+		})
+	}
+}
+
+func TestHouseholds_UpdateMemberRole(t *testing.T) {
+	repo, db := newHouseholdTestRepo(t)
+	uc := NewHouseholds(repo)
+	household := newTestHousehold(t, uc, db)
+	ownerCtx := householdTestContext("owner", "")
+
+	err := uc.UpdateMemberRole(ownerCtx, household.ID, "owner", domain.HouseholdRoleViewer)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	err = uc.UpdateMemberRole(householdTestContext("editor", ""), household.ID, "viewer", domain.HouseholdRoleEditor)
+	assert.ErrorIs(t, err, domain.ErrHouseholdForbidden)
+
+	require.NoError(t, uc.UpdateMemberRole(ownerCtx, household.ID, "viewer", domain.HouseholdRoleEditor))
+	member, err := repo.FindMember(context.Background(), household.ID, "viewer")
+	require.NoError(t, err)
+	assert.Equal(t, domain.HouseholdRoleEditor, member.Role)
+}
+
+func TestHouseholds_Share(t *testing.T) {
+	ownerWallet, editorWallet, viewerWallet := uuid.New(), uuid.New(), uuid.New()
+
+	tests := map[string]struct {
+		userID      string
+		input       HouseholdShareInput
+		expectedErr error
+	}{
+		"should reject an invalid resource type": {
+			userID:      "owner",
+			input:       HouseholdShareInput{ResourceType: "account", ResourceID: ownerWallet},
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should not let a viewer share": {
+			userID:      "viewer",
+			input:       HouseholdShareInput{ResourceType: domain.HouseholdResourceWallet, ResourceID: viewerWallet},
+			expectedErr: domain.ErrHouseholdForbidden,
+		},
+		"should not share a resource of another user": {
+			userID:      "editor",
+			input:       HouseholdShareInput{ResourceType: domain.HouseholdResourceWallet, ResourceID: ownerWallet},
+			expectedErr: domain.ErrHouseholdForbidden,
+		},
+		"should not share a missing resource": {
+			userID:      "editor",
+			input:       HouseholdShareInput{ResourceType: domain.HouseholdResourceWallet, ResourceID: uuid.New()},
+			expectedErr: domain.ErrNotFound,
+		},
+		"should let an editor share its wallet": {
+			userID: "editor",
+			input:  HouseholdShareInput{ResourceType: domain.HouseholdResourceWallet, ResourceID: editorWallet},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, db := newHouseholdTestRepo(t)
+			uc := NewHouseholds(repo)
+			household := newTestHousehold(t, uc, db)
+			require.NoError(t, db.Create(&[]repository.WalletDB{
+				{ID: &ownerWallet, Description: "Owner", UserID: "owner"},
+				{ID: &editorWallet, Description: "Editor", UserID: "editor"},
+				{ID: &viewerWallet, Description: "Viewer", UserID: "viewer"},
+			}).Error)
+
+			ctx := householdTestContext(tt.userID, "")
+			share, err := uc.Share(ctx, household.ID, tt.input)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.userID, share.SharedBy)
+
+			_, err = uc.Share(ctx, household.ID, tt.input)
+			assert.ErrorIs(t, err, domain.ErrConflict, "a resource is shared once")
+		})
+	}
+}
+
+func TestHouseholds_Unshare(t *testing.T) {
+	repo, db := newHouseholdTestRepo(t)
+	uc := NewHouseholds(repo)
+	household := newTestHousehold(t, uc, db)
+
+	walletID := uuid.New()
+	require.NoError(t, db.Create(&repository.WalletDB{ID: &walletID, Description: "Editor", UserID: "editor"}).Error)
+	_, err := uc.Share(householdTestContext("editor", ""), household.ID, HouseholdShareInput{
+		ResourceType: domain.HouseholdResourceWallet,
+		ResourceID:   walletID,
+	})
+	require.NoError(t, err)
+
+	err = uc.Unshare(householdTestContext("viewer", ""), household.ID, domain.HouseholdResourceWallet, walletID)
+	assert.ErrorIs(t, err, domain.ErrHouseholdForbidden)
+
+	require.NoError(t, uc.Unshare(householdTestContext("owner", ""), household.ID, domain.HouseholdResourceWallet, walletID))
+	_, err = repo.FindShare(context.Background(), household.ID, domain.HouseholdResourceWallet, walletID)
+	assert.ErrorIs(t, err, domain.ErrHouseholdShareNotFound)
+}
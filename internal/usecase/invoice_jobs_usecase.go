@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvoiceJobRepository interface {
+	FindToClose(ctx context.Context, date time.Time) ([]domain.Invoice, error)
+	FindDueForAutoDebit(ctx context.Context, date time.Time) ([]domain.Invoice, error)
+	Close(ctx context.Context, tx *gorm.DB, id uuid.UUID, closedAt time.Time) (bool, error)
+}
+
+type InvoicePayer interface {
+	Pay(ctx context.Context, id uuid.UUID, walletID uuid.UUID, paymentDate *time.Time, amount *float64) (domain.Invoice, error)
+}
+
+type InvoiceJobs struct {
+	repo  InvoiceJobRepository
+	payer InvoicePayer
+}
+
+func NewInvoiceJobs(repo InvoiceJobRepository, payer InvoicePayer) InvoiceJobs {
+	return InvoiceJobs{
+		repo:  repo,
+		payer: payer,
+	}
+}
+
+type InvoiceJobResult struct {
+	InvoicesClosed int `json:"invoices_closed"`
+	CloseFailed    int `json:"close_failed"`
+	InvoicesDue    int `json:"invoices_due"`
+	InvoicesPaid   int `json:"invoices_paid"`
+	PaymentFailed  int `json:"payment_failed"`
+	Skipped        int `json:"skipped"`
+}
+
+// CloseAndAutoDebit fecha as faturas cujo período terminou e paga, pela carteira
+// padrão do cartão, as faturas com débito automático que vencem até a data.
+// Pode ser executado várias vezes para a mesma data sem efeitos duplicados.
+func (u *InvoiceJobs) CloseAndAutoDebit(ctx context.Context, date time.Time) (InvoiceJobResult, error) {
+	result := InvoiceJobResult{}
+
+	if err := u.closeInvoices(ctx, date, &result); err != nil {
+		return result, err
+	}
+
+	if err := u.payDueInvoices(ctx, date, &result); err != nil {
+		return result, err
+	}
+
+	log.Info("invoice job completed",
+		log.String("date", date.Format("2006-01-02")),
+		log.Int("invoices_closed", result.InvoicesClosed),
+		log.Int("close_failed", result.CloseFailed),
+		log.Int("invoices_due", result.InvoicesDue),
+		log.Int("invoices_paid", result.InvoicesPaid),
+		log.Int("payment_failed", result.PaymentFailed),
+		log.Int("skipped", result.Skipped),
+	)
+
+	return result, nil
+}
+
+func (u *InvoiceJobs) closeInvoices(ctx context.Context, date time.Time, result *InvoiceJobResult) error {
+	invoices, err := u.repo.FindToClose(ctx, date)
+	if err != nil {
+		return fmt.Errorf("error finding invoices to close: %w", err)
+	}
+
+	closedAt := time.Now().UTC()
+	for _, invoice := range invoices {
+		closed, err := u.repo.Close(ctx, nil, *invoice.ID, closedAt)
+		if err != nil {
+			log.Error("error closing invoice",
+				log.String("invoice_id", invoice.ID.String()),
+				log.Err(err),
+			)
+			result.CloseFailed++
+			continue
+		}
+		if !closed {
+			result.Skipped++
+			continue
+		}
+		result.InvoicesClosed++
+	}
+
+	return nil
+}
+
+func (u *InvoiceJobs) payDueInvoices(ctx context.Context, date time.Time, result *InvoiceJobResult) error {
+	invoices, err := u.repo.FindDueForAutoDebit(ctx, date)
+	if err != nil {
+		return fmt.Errorf("error finding invoices due for auto debit: %w", err)
+	}
+
+	result.InvoicesDue = len(invoices)
+
+	for _, invoice := range invoices {
+		walletID := invoice.CreditCard.DefaultWalletID
+		if walletID == nil {
+			log.Error("credit card without default wallet for auto debit",
+				log.String("invoice_id", invoice.ID.String()),
+			)
+			result.PaymentFailed++
+			continue
+		}
+
+		userCtx := context.WithValue(ctx, authentication.UserID, invoice.UserID)
+		dueDate := invoice.DueDate
+
+		_, err := u.payer.Pay(userCtx, *invoice.ID, *walletID, &dueDate, invoice.AutoDebitAmount())
+		if err != nil {
+			if errors.Is(err, ErrInvoiceAlreadyPaid) {
+				result.Skipped++
+				continue
+			}
+			log.Error("error paying invoice by auto debit",
+				log.String("invoice_id", invoice.ID.String()),
+				log.Err(err),
+			)
+			result.PaymentFailed++
+			continue
+		}
+		result.InvoicesPaid++
+	}
+
+	return nil
+}
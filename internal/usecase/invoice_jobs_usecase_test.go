@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/domain/fixture"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockInvoiceJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceJobRepository) FindToClose(_ context.Context, date time.Time) ([]domain.Invoice, error) {
+	args := m.Called(date)
+	return args.Get(0).([]domain.Invoice), args.Error(1)
+}
+
+func (m *MockInvoiceJobRepository) FindDueForAutoDebit(_ context.Context, date time.Time) ([]domain.Invoice, error) {
+	args := m.Called(date)
+	return args.Get(0).([]domain.Invoice), args.Error(1)
+}
+
+func (m *MockInvoiceJobRepository) Close(_ context.Context, tx *gorm.DB, id uuid.UUID, closedAt time.Time) (bool, error) {
+	args := m.Called(tx, id, closedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockInvoicePayer struct {
+	mock.Mock
+}
+
+func (m *MockInvoicePayer) Pay(ctx context.Context, id uuid.UUID, walletID uuid.UUID, paymentDate *time.Time, amount *float64) (domain.Invoice, error) {
+	args := m.Called(ctx, id, walletID, paymentDate, amount)
+	return args.Get(0).(domain.Invoice), args.Error(1)
+}
+
+func TestInvoiceJobs_CloseAndAutoDebit(t *testing.T) {
+	log.Initialize()
+	date := time.Date(2023, 11, 22, 0, 0, 0, 0, time.UTC)
+	otherID := uuid.MustParse("99999999-9999-9999-9999-999999999999")
+
+	closedAmount := -1200.0
+	dueInvoice := fixture.InvoiceMock(
+		fixture.WithInvoiceUserID("user-1"),
+		fixture.WithInvoiceDueDate(date),
+	)
+	dueInvoice.CreditCard = fixture.CreditCardMock()
+	dueInvoice.ClosedAt = &date
+	dueInvoice.ClosedAmount = &closedAmount
+
+	userCtx := mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(authentication.UserID) == "user-1"
+	})
+
+	tests := map[string]struct {
+		mockSetup      func(repo *MockInvoiceJobRepository, payer *MockInvoicePayer)
+		expectedResult InvoiceJobResult
+		expectedErr    error
+	}{
+		"should close ended invoices and skip already closed ones": {
+			mockSetup: func(repo *MockInvoiceJobRepository, payer *MockInvoicePayer) {
+				repo.On("FindToClose", date).Return([]domain.Invoice{
+					fixture.InvoiceMock(),
+					fixture.InvoiceMock(fixture.WithID(otherID)),
+				}, nil)
+				repo.On("Close", (*gorm.DB)(nil), fixture.InvoiceID, mock.AnythingOfType("time.Time")).Return(true, nil)
+				repo.On("Close", (*gorm.DB)(nil), otherID, mock.AnythingOfType("time.Time")).Return(false, nil)
+				repo.On("FindDueForAutoDebit", date).Return([]domain.Invoice{}, nil)
+			},
+			expectedResult: InvoiceJobResult{
+				InvoicesClosed: 1,
+				Skipped:        1,
+			},
+		},
+		"should pay due invoice with closed amount from default wallet in user context": {
+			mockSetup: func(repo *MockInvoiceJobRepository, payer *MockInvoicePayer) {
+				repo.On("FindToClose", date).Return([]domain.Invoice{}, nil)
+				repo.On("FindDueForAutoDebit", date).Return([]domain.Invoice{dueInvoice}, nil)
+				payer.On("Pay", userCtx, fixture.InvoiceID, fixture.DefaultWalletID, &date, &closedAmount).
+					Return(domain.Invoice{}, nil)
+			},
+			expectedResult: InvoiceJobResult{
+				InvoicesDue:  1,
+				InvoicesPaid: 1,
+			},
+		},
+		"should count already paid invoice as skipped": {
+			mockSetup: func(repo *MockInvoiceJobRepository, payer *MockInvoicePayer) {
+				repo.On("FindToClose", date).Return([]domain.Invoice{}, nil)
+				repo.On("FindDueForAutoDebit", date).Return([]domain.Invoice{dueInvoice}, nil)
+				payer.On("Pay", userCtx, fixture.InvoiceID, fixture.DefaultWalletID, &date, &closedAmount).
+					Return(domain.Invoice{}, ErrInvoiceAlreadyPaid)
+			},
+			expectedResult: InvoiceJobResult{
+				InvoicesDue: 1,
+				Skipped:     1,
+			},
+		},
+		"should continue when payment fails": {
+			mockSetup: func(repo *MockInvoiceJobRepository, payer *MockInvoicePayer) {
+				noWallet := dueInvoice
+				noWallet.ID = &otherID
+				noWallet.CreditCard.DefaultWalletID = nil
+
+				repo.On("FindToClose", date).Return([]domain.Invoice{}, nil)
+				repo.On("FindDueForAutoDebit", date).Return([]domain.Invoice{noWallet, dueInvoice}, nil)
+				payer.On("Pay", userCtx, fixture.InvoiceID, fixture.DefaultWalletID, &date, &closedAmount).
+					Return(domain.Invoice{}, domain.ErrWalletInsufficient)
+			},
+			expectedResult: InvoiceJobResult{
+				InvoicesDue:   2,
+				PaymentFailed: 2,
+			},
+		},
+		"should return error when finding invoices to close fails": {
+			mockSetup: func(repo *MockInvoiceJobRepository, payer *MockInvoicePayer) {
+				repo.On("FindToClose", date).Return([]domain.Invoice{}, errors.New("database error"))
+			},
+			expectedResult: InvoiceJobResult{},
+			expectedErr:    errors.New("error finding invoices to close: database error"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockInvoiceJobRepository)
+			payer := new(MockInvoicePayer)
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(repo, payer)
+			}
+
+			uc := NewInvoiceJobs(repo, payer)
+
+			result, err := uc.CloseAndAutoDebit(context.Background(), date)
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+			repo.AssertExpectations(t)
+			payer.AssertExpectations(t)
+		})
+	}
+}
@@ -61,7 +61,10 @@ func NewInvoice(
 	}
 }
 
-func (uc Invoice) FindOrCreateInvoiceForMovement(ctx context.Context, invoiceID *uuid.UUID, creditCardID *uuid.UUID, movementDate time.Time) (domain.Invoice, error) {
+// FindOrCreateInvoiceForMovement busca a fatura da movimentação ou cria a do
+// período. Uma fatura nova é criada na transação tx de quem chama, para não
+// ficar órfã se ela for desfeita; sem tx, é criada numa transação própria.
+func (uc Invoice) FindOrCreateInvoiceForMovement(ctx context.Context, tx *gorm.DB, invoiceID *uuid.UUID, creditCardID *uuid.UUID, movementDate time.Time) (domain.Invoice, error) {
 	if invoiceID != nil {
 		invoice, err := uc.repo.FindByID(ctx, *invoiceID)
 		if err != nil {
@@ -85,10 +88,10 @@ func (uc Invoice) FindOrCreateInvoiceForMovement(ctx context.Context, invoiceID
 		return invoices, nil
 	}
 
-	return uc.create(ctx, *creditCardID, movementDate)
+	return uc.create(ctx, tx, *creditCardID, movementDate)
 }
 
-func (uc Invoice) create(ctx context.Context, creditCardID uuid.UUID, movementDate time.Time) (domain.Invoice, error) {
+func (uc Invoice) create(ctx context.Context, tx *gorm.DB, creditCardID uuid.UUID, movementDate time.Time) (domain.Invoice, error) {
 	creditCard, err := uc.creditCardRepo.FindByID(ctx, creditCardID)
 	if err != nil {
 		return domain.Invoice{}, fmt.Errorf("error finding credit card: %w", err)
@@ -97,15 +100,20 @@ func (uc Invoice) create(ctx context.Context, creditCardID uuid.UUID, movementDa
 	invoice := domain.BuildInvoice(creditCard, movementDate)
 
 	var result domain.Invoice
-	err = uc.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+	add := func(tx *gorm.DB) error {
 		createdInvoice, err := uc.repo.Add(ctx, tx, invoice)
 		if err != nil {
 			return fmt.Errorf("error creating new invoice: %w", err)
 		}
 		result = createdInvoice
 		return nil
-	})
+	}
 
+	if tx != nil {
+		err = add(tx)
+	} else {
+		err = uc.txManager.WithTransaction(ctx, add)
+	}
 	if err != nil {
 		return domain.Invoice{}, err
 	}
@@ -269,7 +277,7 @@ func (uc Invoice) handleRemainder(ctx context.Context, tx *gorm.DB, invoice doma
 	}
 
 	nextDate := invoice.DueDate.AddDate(0, 0, 1)
-	nextInvoice, err := uc.FindOrCreateInvoiceForMovement(ctx, nil, invoice.CreditCardID, nextDate)
+	nextInvoice, err := uc.FindOrCreateInvoiceForMovement(ctx, tx, nil, invoice.CreditCardID, nextDate)
 	if err != nil {
 		return fmt.Errorf("error finding/creating next invoice: %w", err)
 	}
@@ -343,7 +351,7 @@ func (uc Invoice) RevertPayment(ctx context.Context, id uuid.UUID) (domain.Invoi
 		remainder := invoice.Amount - paidAmount
 		if remainder != 0 {
 			nextDate := invoice.DueDate.AddDate(0, 0, 1)
-			nextInvoice, err := uc.FindOrCreateInvoiceForMovement(ctx, nil, invoice.CreditCardID, nextDate)
+			nextInvoice, err := uc.FindOrCreateInvoiceForMovement(ctx, tx, nil, invoice.CreditCardID, nextDate)
 			if err != nil {
 				return fmt.Errorf("error finding next invoice: %w", err)
 			}
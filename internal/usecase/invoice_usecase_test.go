@@ -132,7 +132,7 @@ func TestInvoice_FindOrCreateInvoiceForMovement(t *testing.T) {
 			tc.mockSetup(mockInvoiceRepo, mockCreditCardRepo, mockWalletRepo, mockTxManager)
 
 			useCase := NewInvoice(mockInvoiceRepo, mockCreditCardRepo, mockWalletRepo, mockMovementRepo, mockTxManager)
-			result, err := useCase.FindOrCreateInvoiceForMovement(context.Background(), tc.invoiceID, &tc.creditCardID, tc.movementDate)
+			result, err := useCase.FindOrCreateInvoiceForMovement(context.Background(), nil, tc.invoiceID, &tc.creditCardID, tc.movementDate)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -150,6 +150,24 @@ func TestInvoice_FindOrCreateInvoiceForMovement(t *testing.T) {
 	}
 }
 
+func TestInvoice_FindOrCreateInvoiceForMovement_CallerTransaction(t *testing.T) {
+	mockInvoiceRepo := &MockInvoiceRepository{}
+	mockCreditCardRepo := &MockCreditCardRepository{}
+	mockTxManager := &MockTransactionManager{}
+	tx := &gorm.DB{}
+
+	mockInvoiceRepo.On("FindByMonthAndCreditCard", mock.Anything, fixture.CreditCardID).Return(domain.Invoice{}, nil)
+	mockCreditCardRepo.On("FindByID", fixture.CreditCardID).Return(fixture.CreditCardMock(), nil)
+	mockInvoiceRepo.On("Add", tx, mock.Anything).Return(fixture.InvoiceMock(fixture.WithInvoiceAmount(0)), nil)
+
+	useCase := NewInvoice(mockInvoiceRepo, mockCreditCardRepo, &MockWalletRepository{}, &MockMovementRepository{}, mockTxManager)
+	_, err := useCase.FindOrCreateInvoiceForMovement(context.Background(), tx, nil, &fixture.CreditCardID, time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	mockInvoiceRepo.AssertExpectations(t)
+	mockTxManager.AssertNotCalled(t, "WithTransaction", mock.Anything)
+}
+
 func TestInvoice_UpdateAmount(t *testing.T) {
 	tests := map[string]struct {
 		invoiceID       uuid.UUID
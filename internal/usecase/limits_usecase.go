@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"personal-finance/internal/domain"
 	"personal-finance/internal/plataform/authentication"
 )
 
@@ -19,17 +20,25 @@ type RecurrentCountRepository interface {
 	CountActiveByUserIDAndMonth(ctx context.Context, year int, month time.Month) (int64, error)
 }
 
+type AIQuotaReader interface {
+	EffectiveQuota(ctx context.Context) (authentication.AIQuota, error)
+	MonthlyUsage(ctx context.Context, year int, month time.Month) (domain.AIUsage, error)
+}
+
 type LimitsUsage struct {
 	Wallets             int64 `json:"wallets"`
 	CreditCards         int64 `json:"credit_cards"`
 	MovementsPerMonth   int64 `json:"movements_per_month"`
 	RecurrencesPerMonth int64 `json:"recurrences_per_month"`
+	AITokensPerMonth    int64 `json:"ai_tokens_per_month"`
+	AIRequestsPerMonth  int64 `json:"ai_requests_per_month"`
 }
 
 type LimitsResponse struct {
 	Plan             string                    `json:"plan"`
 	MPSubscriptionID string                    `json:"mp_subscription_id,omitempty"`
 	Limits           authentication.PlanLimits `json:"limits"`
+	Features         map[domain.Feature]bool   `json:"features"`
 	Usage            LimitsUsage               `json:"usage"`
 	ResetAt          time.Time                 `json:"reset_at"`
 }
@@ -39,6 +48,8 @@ type Limits struct {
 	creditCardRepo LimitsCountRepository
 	movementRepo   MovementCountRepository
 	recurrentRepo  RecurrentCountRepository
+	aiQuota        AIQuotaReader
+	entitlements   EntitlementResolver
 }
 
 func NewLimits(
@@ -46,12 +57,16 @@ func NewLimits(
 	creditCardRepo LimitsCountRepository,
 	movementRepo MovementCountRepository,
 	recurrentRepo RecurrentCountRepository,
+	aiQuota AIQuotaReader,
+	entitlements EntitlementResolver,
 ) *Limits {
 	return &Limits{
 		walletRepo:     walletRepo,
 		creditCardRepo: creditCardRepo,
 		movementRepo:   movementRepo,
 		recurrentRepo:  recurrentRepo,
+		aiQuota:        aiQuota,
+		entitlements:   entitlements,
 	}
 }
 
@@ -84,9 +99,28 @@ func (l *Limits) GetLimits(ctx context.Context) (LimitsResponse, error) {
 		return LimitsResponse{}, err
 	}
 
-	limits := authentication.PlanLimits{}
-	if auth.IsFree() {
-		limits = authentication.GetFreePlanLimits()
+	aiQuota, err := l.aiQuota.EffectiveQuota(ctx)
+	if err != nil {
+		return LimitsResponse{}, err
+	}
+
+	aiUsage, err := l.aiQuota.MonthlyUsage(ctx, year, month)
+	if err != nil {
+		return LimitsResponse{}, err
+	}
+
+	ent, err := l.entitlements.Resolve(ctx)
+	if err != nil {
+		return LimitsResponse{}, err
+	}
+
+	// The AI quota may come from an admin override instead of the plan.
+	limits := planLimitsFromEntitlements(ent)
+	limits.AIQuota = aiQuota
+
+	features := make(map[domain.Feature]bool, len(domain.Features))
+	for _, feature := range domain.Features {
+		features[feature] = ent.HasFeature(feature)
 	}
 
 	firstDayNextMonth := time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
@@ -95,11 +129,14 @@ func (l *Limits) GetLimits(ctx context.Context) (LimitsResponse, error) {
 		Plan:             string(auth.Plan),
 		MPSubscriptionID: auth.MPSubscriptionID,
 		Limits:           limits,
+		Features:         features,
 		Usage: LimitsUsage{
 			Wallets:             walletsCount,
 			CreditCards:         creditCardsCount,
 			MovementsPerMonth:   movementsCount,
 			RecurrencesPerMonth: recurrencesCount,
+			AITokensPerMonth:    aiUsage.Tokens(),
+			AIRequestsPerMonth:  aiUsage.Requests,
 		},
 		ResetAt: firstDayNextMonth,
 	}, nil
@@ -55,6 +55,11 @@ func (m *MockMovementRepository) Update(_ context.Context, tx *gorm.DB, id uuid.
 	return args.Get(0).(domain.Movement), args.Error(1)
 }
 
+func (m *MockMovementRepository) UpdateInvoiceID(_ context.Context, tx *gorm.DB, id uuid.UUID, invoiceID uuid.UUID) error {
+	args := m.Called(tx, id, invoiceID)
+	return args.Error(0)
+}
+
 func (m *MockMovementRepository) Delete(_ context.Context, tx *gorm.DB, id uuid.UUID) error {
 	args := m.Called(tx, id)
 	return args.Error(0)
@@ -65,6 +70,11 @@ func (m *MockMovementRepository) FindAllByRecurrentID(_ context.Context, recurre
 	return args.Get(0).(domain.MovementList), args.Error(1)
 }
 
+func (m *MockMovementRepository) FindByPairID(_ context.Context, pairID uuid.UUID) (domain.MovementList, error) {
+	args := m.Called(pairID)
+	return args.Get(0).(domain.MovementList), args.Error(1)
+}
+
 func (m *MockMovementRepository) DeleteAllByRecurrentID(_ context.Context, tx *gorm.DB, recurrentID uuid.UUID) error {
 	args := m.Called(tx, recurrentID)
 	return args.Error(0)
@@ -104,6 +114,11 @@ func (m *MockRecurrentRepository) FindByID(_ context.Context, id uuid.UUID) (dom
 	return args.Get(0).(domain.RecurrentMovement), args.Error(1)
 }
 
+func (m *MockRecurrentRepository) FindByPairID(_ context.Context, pairID uuid.UUID) ([]domain.RecurrentMovement, error) {
+	args := m.Called(pairID)
+	return args.Get(0).([]domain.RecurrentMovement), args.Error(1)
+}
+
 func (m *MockRecurrentRepository) Update(_ context.Context, tx *gorm.DB, id *uuid.UUID, newRecurrent domain.RecurrentMovement) (domain.RecurrentMovement, error) {
 	args := m.Called(tx, id, newRecurrent)
 	return args.Get(0).(domain.RecurrentMovement), args.Error(1)
@@ -138,6 +153,21 @@ func (m *MockWalletRepository) FindAll(_ context.Context) ([]domain.Wallet, erro
 	return args.Get(0).([]domain.Wallet), args.Error(1)
 }
 
+func (m *MockWalletRepository) FindActive(_ context.Context) ([]domain.Wallet, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) FindPaidEntries(_ context.Context, walletID *uuid.UUID, from, until time.Time) ([]domain.WalletBalanceEntry, error) {
+	args := m.Called(walletID, from, until)
+	return args.Get(0).([]domain.WalletBalanceEntry), args.Error(1)
+}
+
+func (m *MockWalletRepository) UpdateArchivedAt(_ context.Context, id *uuid.UUID, archivedAt *time.Time) error {
+	args := m.Called(id, archivedAt)
+	return args.Error(0)
+}
+
 func (m *MockWalletRepository) Update(_ context.Context, wallet domain.Wallet) (domain.Wallet, error) {
 	args := m.Called(wallet)
 	return args.Get(0).(domain.Wallet), args.Error(1)
@@ -306,7 +336,7 @@ type MockInvoice struct {
 	mock.Mock
 }
 
-func (m *MockInvoice) FindOrCreateInvoiceForMovement(ctx context.Context, invoiceID *uuid.UUID, creditCardID *uuid.UUID, movementDate time.Time) (domain.Invoice, error) {
+func (m *MockInvoice) FindOrCreateInvoiceForMovement(ctx context.Context, _ *gorm.DB, invoiceID *uuid.UUID, creditCardID *uuid.UUID, movementDate time.Time) (domain.Invoice, error) {
 	args := m.Called(ctx, invoiceID, creditCardID, movementDate)
 	return args.Get(0).(domain.Invoice), args.Error(1)
 }
@@ -345,9 +375,9 @@ type MockStatementClassificationGateway struct {
 	mock.Mock
 }
 
-func (m *MockStatementClassificationGateway) ClassifyMovements(_ context.Context, movements []domain.ExtractedMovement, categories []domain.Category) ([]domain.CategorySuggestion, error) {
+func (m *MockStatementClassificationGateway) ClassifyMovements(_ context.Context, movements []domain.ExtractedMovement, categories []domain.Category) ([]domain.CategorySuggestion, domain.AIUsage, error) {
 	args := m.Called(movements, categories)
-	return args.Get(0).([]domain.CategorySuggestion), args.Error(1)
+	return args.Get(0).([]domain.CategorySuggestion), args.Get(1).(domain.AIUsage), args.Error(2)
 }
 
 type MockStatementMovementRepository struct {
@@ -435,3 +465,492 @@ func (m *MockPlanLimitsValidator) ValidateRecurrenceCreation(ctx context.Context
 	args := m.Called(ctx)
 	return args.Error(0)
 }
+
+// --- Agent mocks ---
+
+type MockAgentMemoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockAgentMemoryRepository) Save(_ context.Context, memory domain.AgentMemory) (domain.AgentMemory, error) {
+	args := m.Called(memory)
+	return args.Get(0).(domain.AgentMemory), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) FindByID(_ context.Context, id uuid.UUID) (domain.AgentMemory, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.AgentMemory), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) FindByUserID(_ context.Context, userID string) ([]domain.AgentMemory, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.AgentMemory), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) FindByUserIDAndType(_ context.Context, userID string, memType domain.AgentMemoryType) ([]domain.AgentMemory, error) {
+	args := m.Called(userID, memType)
+	return args.Get(0).([]domain.AgentMemory), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) SearchSimilar(_ context.Context, userID string, query string, limit int) ([]domain.AgentMemory, error) {
+	args := m.Called(userID, query, limit)
+	return args.Get(0).([]domain.AgentMemory), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) FindRelevant(_ context.Context, userID string, query string) ([]domain.ScoredMemory, error) {
+	args := m.Called(userID, query)
+	return args.Get(0).([]domain.ScoredMemory), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) Update(_ context.Context, memory domain.AgentMemory) (domain.AgentMemory, error) {
+	args := m.Called(memory)
+	return args.Get(0).(domain.AgentMemory), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) Delete(_ context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAgentMemoryRepository) CountByUserID(_ context.Context, userID string) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) UpsertRiskProfile(_ context.Context, memory domain.AgentMemory) (domain.AgentMemory, error) {
+	args := m.Called(memory)
+	return args.Get(0).(domain.AgentMemory), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) DeleteExpired(_ context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAgentMemoryRepository) DeleteAllByUserID(_ context.Context, tx *gorm.DB, userID string) error {
+	args := m.Called(tx, userID)
+	return args.Error(0)
+}
+
+type MockAgentConversationRepository struct {
+	mock.Mock
+}
+
+func (m *MockAgentConversationRepository) Save(_ context.Context, conv domain.AgentConversation) (domain.AgentConversation, error) {
+	args := m.Called(conv)
+	return args.Get(0).(domain.AgentConversation), args.Error(1)
+}
+
+func (m *MockAgentConversationRepository) FindByID(_ context.Context, id uuid.UUID) (domain.AgentConversation, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.AgentConversation), args.Error(1)
+}
+
+func (m *MockAgentConversationRepository) FindByUserID(_ context.Context, userID string) ([]domain.AgentConversation, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.AgentConversation), args.Error(1)
+}
+
+func (m *MockAgentConversationRepository) SaveMessage(ctx context.Context, msg domain.AgentMessage) (domain.AgentMessage, error) {
+	args := m.Called(ctx, msg)
+	return args.Get(0).(domain.AgentMessage), args.Error(1)
+}
+
+func (m *MockAgentConversationRepository) UpdateTitle(_ context.Context, id uuid.UUID, title string) error {
+	args := m.Called(id, title)
+	return args.Error(0)
+}
+
+func (m *MockAgentConversationRepository) UpdateSummary(_ context.Context, id uuid.UUID, summary string, summarizedMessages int) error {
+	args := m.Called(id, summary, summarizedMessages)
+	return args.Error(0)
+}
+
+func (m *MockAgentConversationRepository) DeleteExpired(_ context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAgentConversationRepository) FindAllWithMessagesByUserID(_ context.Context, userID string) ([]domain.AgentConversation, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.AgentConversation), args.Error(1)
+}
+
+func (m *MockAgentConversationRepository) Delete(_ context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockAgentAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAgentAuditRepository) Log(ctx context.Context, record domain.AgentAuditRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockAgentAuditRepository) FindByUserID(_ context.Context, userID string) ([]domain.AgentAuditRecord, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.AgentAuditRecord), args.Error(1)
+}
+
+type MockAgentGateway struct {
+	mock.Mock
+}
+
+func (m *MockAgentGateway) Chat(_ context.Context, systemPrompt string, userMessage string, history []domain.AgentMessage) (domain.AgentGatewayResponse, error) {
+	args := m.Called(systemPrompt, userMessage, history)
+	return args.Get(0).(domain.AgentGatewayResponse), args.Error(1)
+}
+
+func (m *MockAgentGateway) ChatStream(ctx context.Context, systemPrompt string, userMessage string, history []domain.AgentMessage, emit domain.AgentStreamEmitter) (domain.AgentGatewayResponse, error) {
+	args := m.Called(ctx, systemPrompt, userMessage, history, emit)
+	return args.Get(0).(domain.AgentGatewayResponse), args.Error(1)
+}
+
+func (m *MockAgentGateway) Summarize(_ context.Context, prompt string) (domain.AgentGatewayResponse, error) {
+	args := m.Called(prompt)
+	return args.Get(0).(domain.AgentGatewayResponse), args.Error(1)
+}
+
+type MockAgentActionRepository struct {
+	mock.Mock
+}
+
+func (m *MockAgentActionRepository) Save(_ context.Context, action domain.AgentAction) (domain.AgentAction, error) {
+	args := m.Called(action)
+	return args.Get(0).(domain.AgentAction), args.Error(1)
+}
+
+func (m *MockAgentActionRepository) FindByID(_ context.Context, id uuid.UUID) (domain.AgentAction, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.AgentAction), args.Error(1)
+}
+
+func (m *MockAgentActionRepository) FindPendingByUserID(_ context.Context, userID string) ([]domain.AgentAction, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.AgentAction), args.Error(1)
+}
+
+func (m *MockAgentActionRepository) UpdateStatus(_ context.Context, action domain.AgentAction, from domain.AgentActionStatus) error {
+	args := m.Called(action, from)
+	return args.Error(0)
+}
+
+func (m *MockAgentActionRepository) FindAllByUserID(_ context.Context, userID string) ([]domain.AgentAction, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.AgentAction), args.Error(1)
+}
+
+type MockAgentSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAgentSettingsRepository) FindByUserID(_ context.Context, userID string) (domain.AgentSettings, error) {
+	args := m.Called(userID)
+	return args.Get(0).(domain.AgentSettings), args.Error(1)
+}
+
+func (m *MockAgentSettingsRepository) Upsert(_ context.Context, settings domain.AgentSettings) (domain.AgentSettings, error) {
+	args := m.Called(settings)
+	return args.Get(0).(domain.AgentSettings), args.Error(1)
+}
+
+type MockAgentMovementService struct {
+	mock.Mock
+}
+
+func (m *MockAgentMovementService) Add(_ context.Context, movement domain.Movement) (domain.Movement, error) {
+	args := m.Called(movement)
+	return args.Get(0).(domain.Movement), args.Error(1)
+}
+
+func (m *MockAgentMovementService) Pay(_ context.Context, id uuid.UUID, date time.Time) (domain.Movement, error) {
+	args := m.Called(id, date)
+	return args.Get(0).(domain.Movement), args.Error(1)
+}
+
+type MockAgentTransferService struct {
+	mock.Mock
+}
+
+func (m *MockAgentTransferService) Execute(_ context.Context, input TransferInput) (TransferOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(TransferOutput), args.Error(1)
+}
+
+// --- AI quota mocks ---
+
+type MockAIUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockAIUsageRepository) Increment(_ context.Context, userID string, year int, month time.Month, feature domain.AIFeature, usage domain.AIUsage) error {
+	args := m.Called(userID, year, month, feature, usage)
+	return args.Error(0)
+}
+
+func (m *MockAIUsageRepository) SumByUserIDAndMonth(_ context.Context, userID string, year int, month time.Month) (domain.AIUsage, error) {
+	args := m.Called(userID, year, month)
+	return args.Get(0).(domain.AIUsage), args.Error(1)
+}
+
+type MockAIQuotaOverrideRepository struct {
+	mock.Mock
+}
+
+func (m *MockAIQuotaOverrideRepository) FindByUserID(_ context.Context, userID string) (domain.AIQuotaOverride, error) {
+	args := m.Called(userID)
+	return args.Get(0).(domain.AIQuotaOverride), args.Error(1)
+}
+
+func (m *MockAIQuotaOverrideRepository) Upsert(_ context.Context, override domain.AIQuotaOverride) (domain.AIQuotaOverride, error) {
+	args := m.Called(override)
+	return args.Get(0).(domain.AIQuotaOverride), args.Error(1)
+}
+
+func (m *MockAIQuotaOverrideRepository) Delete(_ context.Context, userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+type MockAIQuotaGuard struct {
+	mock.Mock
+}
+
+func (m *MockAIQuotaGuard) Check(_ context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockAIQuotaGuard) Record(_ context.Context, feature domain.AIFeature, usage domain.AIUsage) {
+	m.Called(feature, usage)
+}
+
+// --- Entitlements mocks ---
+
+type MockEntitlementResolver struct {
+	mock.Mock
+}
+
+func (m *MockEntitlementResolver) Resolve(_ context.Context) (domain.Entitlements, error) {
+	args := m.Called()
+	return args.Get(0).(domain.Entitlements), args.Error(1)
+}
+
+type MockPlanEntitlementsRepository struct {
+	mock.Mock
+}
+
+func (m *MockPlanEntitlementsRepository) FindByID(_ context.Context, id string) (domain.SubscriptionPlan, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.SubscriptionPlan), args.Error(1)
+}
+
+func (m *MockPlanEntitlementsRepository) UpdateEntitlements(_ context.Context, id string, ent *domain.Entitlements) (domain.SubscriptionPlan, error) {
+	args := m.Called(id, ent)
+	return args.Get(0).(domain.SubscriptionPlan), args.Error(1)
+}
+
+type MockUserSubscriptionsRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserSubscriptionsRepository) FindByUserID(_ context.Context, userID string) ([]domain.Subscription, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.Subscription), args.Error(1)
+}
+
+// --- Agent insights mocks ---
+
+type MockInsightPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockInsightPreferenceRepository) FindByUserID(_ context.Context, userID string) (domain.InsightPreference, error) {
+	args := m.Called(userID)
+	return args.Get(0).(domain.InsightPreference), args.Error(1)
+}
+
+func (m *MockInsightPreferenceRepository) Upsert(_ context.Context, pref domain.InsightPreference) (domain.InsightPreference, error) {
+	args := m.Called(pref)
+	return args.Get(0).(domain.InsightPreference), args.Error(1)
+}
+
+func (m *MockInsightPreferenceRepository) FindDue(_ context.Context, notAfter time.Time) ([]domain.InsightPreference, error) {
+	args := m.Called(notAfter)
+	return args.Get(0).([]domain.InsightPreference), args.Error(1)
+}
+
+func (m *MockInsightPreferenceRepository) ClaimDigest(_ context.Context, userID string, sentAt, notAfter time.Time) (bool, error) {
+	args := m.Called(userID, sentAt, notAfter)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockInsightFinancialRepository struct {
+	mock.Mock
+}
+
+func (m *MockInsightFinancialRepository) GetSpendingBreakdown(_ context.Context, month, year int) (domain.AgentSpendingBreakdown, error) {
+	args := m.Called(month, year)
+	return args.Get(0).(domain.AgentSpendingBreakdown), args.Error(1)
+}
+
+func (m *MockInsightFinancialRepository) GetRecurringSummary(_ context.Context) (domain.AgentRecurringSummary, error) {
+	args := m.Called()
+	return args.Get(0).(domain.AgentRecurringSummary), args.Error(1)
+}
+
+func (m *MockInsightFinancialRepository) GetBudgetStatus(_ context.Context, month, year int) (domain.AgentBudgetStatus, error) {
+	args := m.Called(month, year)
+	return args.Get(0).(domain.AgentBudgetStatus), args.Error(1)
+}
+
+func (m *MockInsightFinancialRepository) GetCreditCardsSummary(_ context.Context) (domain.AgentCreditCardsSummary, error) {
+	args := m.Called()
+	return args.Get(0).(domain.AgentCreditCardsSummary), args.Error(1)
+}
+
+// --- Recurring charges mocks ---
+
+type MockRecurringChargeMovementRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecurringChargeMovementRepository) FindStandaloneExpensesSince(_ context.Context, from time.Time) (domain.MovementList, error) {
+	args := m.Called(from)
+	return args.Get(0).(domain.MovementList), args.Error(1)
+}
+
+type MockWebhookInboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookInboxRepository) Insert(_ context.Context, event domain.WebhookInboxEvent) (bool, error) {
+	args := m.Called(event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhookInboxRepository) FindByID(_ context.Context, id uuid.UUID) (domain.WebhookInboxEvent, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.WebhookInboxEvent), args.Error(1)
+}
+
+func (m *MockWebhookInboxRepository) FindDue(_ context.Context, now time.Time, limit int) ([]domain.WebhookInboxEvent, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]domain.WebhookInboxEvent), args.Error(1)
+}
+
+func (m *MockWebhookInboxRepository) ListByStatus(_ context.Context, status domain.WebhookEventStatus, limit int) ([]domain.WebhookInboxEvent, error) {
+	args := m.Called(status, limit)
+	return args.Get(0).([]domain.WebhookInboxEvent), args.Error(1)
+}
+
+func (m *MockWebhookInboxRepository) Claim(_ context.Context, event domain.WebhookInboxEvent, leaseUntil time.Time) (bool, error) {
+	args := m.Called(event, leaseUntil)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhookInboxRepository) MarkProcessed(_ context.Context, id uuid.UUID, processedAt time.Time) error {
+	args := m.Called(id, processedAt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookInboxRepository) MarkFailed(_ context.Context, id uuid.UUID, lastError string, status domain.WebhookEventStatus, nextAttemptAt time.Time) error {
+	args := m.Called(id, lastError, status, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookInboxRepository) Requeue(_ context.Context, id uuid.UUID, now time.Time) (domain.WebhookInboxEvent, error) {
+	args := m.Called(id, now)
+	return args.Get(0).(domain.WebhookInboxEvent), args.Error(1)
+}
+
+type MockWebhookEventProcessor struct {
+	mock.Mock
+}
+
+func (m *MockWebhookEventProcessor) ProcessWebhookEvent(_ context.Context, event domain.WebhookInboxEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+type MockWebhookEnqueuer struct {
+	mock.Mock
+}
+
+func (m *MockWebhookEnqueuer) Enqueue(_ context.Context, event domain.WebhookInboxEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+type MockBillingNotifier struct {
+	mock.Mock
+}
+
+func (m *MockBillingNotifier) NotifyTrialEnding(_ context.Context, userID string, endsAt time.Time) error {
+	args := m.Called(userID, endsAt)
+	return args.Error(0)
+}
+
+func (m *MockBillingNotifier) NotifyPaymentFailed(_ context.Context, userID string, graceEndsAt time.Time) error {
+	args := m.Called(userID, graceEndsAt)
+	return args.Error(0)
+}
+
+type MockAnalyticsPlanRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsPlanRepository) FindAll(_ context.Context) ([]domain.SubscriptionPlan, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.SubscriptionPlan), args.Error(1)
+}
+
+type MockAnalyticsRedemptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsRedemptionRepository) ListWithSubscription(_ context.Context) ([]domain.CouponRedemption, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.CouponRedemption), args.Error(1)
+}
+
+type MockCouponCheckoutUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCouponCheckoutUseCase) ApplyWebCheckout(_ context.Context, userID string, plan domain.SubscriptionPlan, code string) (uuid.UUID, error) {
+	args := m.Called(userID, plan.ID, code)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockCouponCheckoutUseCase) Confirm(_ context.Context, redemptionID, subscriptionID uuid.UUID) error {
+	args := m.Called(redemptionID, subscriptionID)
+	return args.Error(0)
+}
+
+func (m *MockCouponCheckoutUseCase) MarkCancelledBySubscription(_ context.Context, subscriptionID uuid.UUID) error {
+	args := m.Called(subscriptionID)
+	return args.Error(0)
+}
+
+func (m *MockCouponCheckoutUseCase) FindCampaignCode(_ context.Context, code string) (domain.CouponCampaignCode, domain.CouponCampaign, error) {
+	args := m.Called(code)
+	return args.Get(0).(domain.CouponCampaignCode), args.Get(1).(domain.CouponCampaign), args.Error(2)
+}
+
+func (m *MockCouponCheckoutUseCase) LinkStripePromotionCode(_ context.Context, code, promotionCodeID string) error {
+	args := m.Called(code, promotionCodeID)
+	return args.Error(0)
+}
+
+type MockReferralQualifier struct {
+	mock.Mock
+}
+
+func (m *MockReferralQualifier) QualifyReferral(_ context.Context, refereeID string) error {
+	args := m.Called(refereeID)
+	return args.Error(0)
+}
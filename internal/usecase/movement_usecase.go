@@ -24,21 +24,24 @@ type (
 		FindByInstallmentGroupFromNumber(ctx context.Context, groupID uuid.UUID, fromNumber int) (domain.MovementList, error)
 		UpdateIsPaid(ctx context.Context, tx *gorm.DB, id uuid.UUID, movement domain.Movement) (domain.Movement, error)
 		Update(ctx context.Context, tx *gorm.DB, id uuid.UUID, movement domain.Movement) (domain.Movement, error)
+		UpdateInvoiceID(ctx context.Context, tx *gorm.DB, id uuid.UUID, invoiceID uuid.UUID) error
 		Delete(ctx context.Context, tx *gorm.DB, id uuid.UUID) error
 		DeleteAllByRecurrentID(ctx context.Context, tx *gorm.DB, recurrentID uuid.UUID) error
 		FindAllByRecurrentID(ctx context.Context, recurrentID uuid.UUID) (domain.MovementList, error)
+		FindByPairID(ctx context.Context, pairID uuid.UUID) (domain.MovementList, error)
 	}
 
 	RecurrentRepository interface {
 		Add(ctx context.Context, tx *gorm.DB, recurrent domain.RecurrentMovement) (domain.RecurrentMovement, error)
 		FindByMonth(ctx context.Context, month time.Time) ([]domain.RecurrentMovement, error)
 		FindByID(ctx context.Context, id uuid.UUID) (domain.RecurrentMovement, error)
+		FindByPairID(ctx context.Context, pairID uuid.UUID) ([]domain.RecurrentMovement, error)
 		Update(ctx context.Context, tx *gorm.DB, id *uuid.UUID, newRecurrent domain.RecurrentMovement) (domain.RecurrentMovement, error)
 		Delete(ctx context.Context, tx *gorm.DB, id *uuid.UUID) error
 	}
 
 	InvoiceUseCase interface {
-		FindOrCreateInvoiceForMovement(ctx context.Context, invoiceID *uuid.UUID, creditCardID *uuid.UUID, movementDate time.Time) (domain.Invoice, error)
+		FindOrCreateInvoiceForMovement(ctx context.Context, tx *gorm.DB, invoiceID *uuid.UUID, creditCardID *uuid.UUID, movementDate time.Time) (domain.Invoice, error)
 		UpdateAmount(ctx context.Context, id uuid.UUID, amount float64) (domain.Invoice, error)
 		FindDetailedInvoicesByPeriod(ctx context.Context, period domain.Period) ([]domain.DetailedInvoice, error)
 	}
@@ -100,6 +103,28 @@ func (u *Movement) validateSubCategory(ctx context.Context, subCategoryID, categ
 	return nil
 }
 
+// validateWalletChange impede lançar em uma carteira arquivada. Movimentações que já
+// estão na carteira (current igual a next) continuam editáveis.
+func (u *Movement) validateWalletChange(ctx context.Context, current, next *uuid.UUID) error {
+	if next == nil || (current != nil && *current == *next) {
+		return nil
+	}
+
+	wallet, err := u.walletRepo.FindByID(ctx, next)
+	if err != nil {
+		return err
+	}
+
+	if wallet.IsArchived() {
+		return domain.WrapInvalidInput(
+			domain.New("wallet is archived"),
+			"validate wallet",
+		)
+	}
+
+	return nil
+}
+
 func (u *Movement) updateWalletBalance(ctx context.Context, tx *gorm.DB, walletID *uuid.UUID, amount float64) error {
 	wallet, err := u.walletRepo.FindByID(ctx, walletID)
 	if err != nil {
@@ -135,6 +160,7 @@ func (u *Movement) validateCreditLimit(ctx context.Context, creditCardID *uuid.U
 func (u *Movement) getInvoice(ctx context.Context, tx *gorm.DB, movement *domain.Movement) error {
 	invoice, err := u.invoiceUseCase.FindOrCreateInvoiceForMovement(
 		ctx,
+		tx,
 		movement.CreditCardInfo.InvoiceID,
 		movement.CreditCardInfo.CreditCardID,
 		*movement.Date,
@@ -176,6 +202,10 @@ func (u *Movement) handleCreditCardMovement(
 		return domain.Movement{}, fmt.Errorf("credit_card_info is required for credit card movements")
 	}
 
+	if err := movement.ValidateInstallmentPlan(); err != nil {
+		return domain.Movement{}, err
+	}
+
 	movements := domain.MovementList{*movement}
 	if movement.IsInstallmentMovement() {
 		movements = movement.GenerateInstallmentMovements()
@@ -225,6 +255,12 @@ func (u *Movement) Add(ctx context.Context, movement domain.Movement) (domain.Mo
 		return domain.Movement{}, err
 	}
 
+	if !movement.IsCreditCardMovement() {
+		if err := u.validateWalletChange(ctx, nil, movement.WalletID); err != nil {
+			return domain.Movement{}, err
+		}
+	}
+
 	var result domain.Movement
 
 	err = u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
@@ -340,6 +376,10 @@ func (u *Movement) payMovement(ctx context.Context, tx *gorm.DB, id uuid.UUID, d
 			return domain.Movement{}, err
 		}
 
+		if recurrent.IsTransfer() {
+			return domain.Movement{}, ErrTransferMovementChange
+		}
+
 		if date.IsZero() {
 			return domain.Movement{}, ErrDateRequired
 		}
@@ -359,6 +399,10 @@ func (u *Movement) payMovement(ctx context.Context, tx *gorm.DB, id uuid.UUID, d
 		return domain.Movement{}, ErrMovementAlreadyPaid
 	}
 
+	if movement.IsTransfer() {
+		return domain.Movement{}, ErrTransferMovementChange
+	}
+
 	if movement.IsCreditCardMovement() {
 		return domain.Movement{}, ErrCreditCardPay
 	}
@@ -386,6 +430,10 @@ func (u *Movement) RevertPay(ctx context.Context, id uuid.UUID) (domain.Movement
 			return ErrMovementNotPaid
 		}
 
+		if movement.IsTransfer() {
+			return ErrTransferMovementChange
+		}
+
 		movement.IsPaid = false
 
 		result, err = u.movementRepo.UpdateIsPaid(ctx, tx, id, movement)
@@ -80,6 +80,11 @@ func TestMovement_Add(t *testing.T) {
 					}).Return(nil)
 
 				mockMovRepo.On("Add", mock.Anything, movement).Return(movement, nil)
+
+				mockWalletRepo.On("FindByID", movement.WalletID).Return(domain.Wallet{
+					ID:      movement.WalletID,
+					Balance: 1000.0,
+				}, nil)
 			},
 			expectedMovement: fixture.MovementMock(
 				fixture.WithMovementDescription("Compra parcelada"),
@@ -175,6 +180,11 @@ func TestMovement_Add(t *testing.T) {
 					}).Return(errors.New("error when creating movement"))
 
 				mockMovRepo.On("Add", mock.Anything, movement).Return(domain.Movement{}, errors.New("error when creating movement"))
+
+				mockWalletRepo.On("FindByID", movement.WalletID).Return(domain.Wallet{
+					ID:      movement.WalletID,
+					Balance: 1000.0,
+				}, nil)
 			},
 			expectedMovement: domain.Movement{},
 			expectedError:    errors.New("error when creating movement"),
@@ -190,19 +200,31 @@ func TestMovement_Add(t *testing.T) {
 					fixture.AsMovementExpense(100.0),
 				)
 
-				mockTxManager.On("WithTransaction", mock.Anything).
-					Run(func(args mock.Arguments) {
-						fn := args.Get(0).(func(*gorm.DB) error)
-						_ = fn(nil)
-					}).Return(errors.New("error when searching wallet"))
-
-				mockMovRepo.On("Add", mock.Anything, movement).Return(movement, nil)
-
 				mockWalletRepo.On("FindByID", movement.WalletID).Return(domain.Wallet{}, errors.New("error when searching wallet"))
 			},
 			expectedMovement: domain.Movement{},
 			expectedError:    errors.New("error when searching wallet"),
 		},
+		"should return error when wallet is archived": {
+			movementInput: fixture.MovementMock(
+				fixture.WithMovementDescription("Movimento em carteira arquivada"),
+				fixture.AsMovementExpense(100.0),
+				fixture.AsMovementRecurrent(),
+			),
+			mockSetup: func(mockMovRepo *MockMovementRepository, mockRecRepo *MockRecurrentRepository, mockWalletRepo *MockWalletRepository, mockSubCat *MockSubCategory, mockTxManager *MockTransactionManager, mockInvoiceUseCase *MockInvoice, mockCreditCardRepo *MockCreditCardRepository, mockInvoiceRepo *MockInvoiceRepository) {
+				archivedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+				mockWalletRepo.On("FindByID", &fixture.WalletID).Return(domain.Wallet{
+					ID:         &fixture.WalletID,
+					Balance:    1000.0,
+					ArchivedAt: &archivedAt,
+				}, nil)
+			},
+			expectedMovement: domain.Movement{},
+			expectedError: domain.WrapInvalidInput(
+				domain.New("wallet is archived"),
+				"validate wallet",
+			),
+		},
 		"should return error when fails to update wallet balance": {
 			movementInput: fixture.MovementMock(
 				fixture.WithMovementDescription("Movimento com erro na atualização da carteira"),
@@ -258,6 +280,11 @@ func TestMovement_Add(t *testing.T) {
 				recurrent := domain.ToRecurrentMovement(movement)
 
 				mockRecRepo.On("Add", mock.Anything, recurrent).Return(domain.RecurrentMovement{}, errors.New("error when creating recurrence"))
+
+				mockWalletRepo.On("FindByID", movement.WalletID).Return(domain.Wallet{
+					ID:      movement.WalletID,
+					Balance: 1000.0,
+				}, nil)
 			},
 			expectedMovement: domain.Movement{},
 			expectedError:    errors.New("error when creating recurrence"),
@@ -1447,6 +1474,8 @@ func TestMovement_RevertPay(t *testing.T) {
 }
 
 func TestMovement_UpdateOne(t *testing.T) {
+	archivedWalletID := uuid.New()
+
 	tests := map[string]struct {
 		id               uuid.UUID
 		newMovement      domain.Movement
@@ -1752,6 +1781,35 @@ func TestMovement_UpdateOne(t *testing.T) {
 			),
 			expectedError: nil,
 		},
+		"should return error when moving to an archived wallet": {
+			id: fixture.MovementID,
+			newMovement: fixture.MovementMock(
+				fixture.WithMovementDescription("Movimento atualizado"),
+				fixture.WithMovementWalletID(archivedWalletID),
+				fixture.WithMovementIsPaid(false),
+			),
+			mockSetup: func(mockMovRepo *MockMovementRepository, mockRecRepo *MockRecurrentRepository, mockWalletRepo *MockWalletRepository, mockSubCat *MockSubCategory, mockTxManager *MockTransactionManager) {
+				existingMovement := fixture.MovementMock(
+					fixture.WithMovementDescription("Movimento original"),
+					fixture.WithMovementIsPaid(false),
+				)
+				archivedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+				mockTxManager.On("WithTransaction", mock.Anything).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(0).(func(*gorm.DB) error)
+						_ = fn(nil)
+					}).Return(domain.WrapInvalidInput(domain.New("wallet is archived"), "validate wallet"))
+
+				mockMovRepo.On("FindByID", fixture.MovementID).Return(existingMovement, nil)
+				mockWalletRepo.On("FindByID", &archivedWalletID).Return(domain.Wallet{
+					ID:         &archivedWalletID,
+					ArchivedAt: &archivedAt,
+				}, nil)
+			},
+			expectedMovement: domain.Movement{},
+			expectedError:    domain.WrapInvalidInput(domain.New("wallet is archived"), "validate wallet"),
+		},
 	}
 
 	for name, tt := range tests {
@@ -2328,3 +2386,116 @@ func TestMovement_UpdateOne_CreditCard_InsufficientLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestMovement_UpdateOne_CreditCardInstallment_MoveInvoice(t *testing.T) {
+	groupID := uuid.MustParse("99999999-9999-9999-9999-999999999999")
+	nextInvoiceID := uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	currentDate := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+	nextDate := time.Date(2023, 11, 10, 0, 0, 0, 0, time.UTC)
+
+	existingInstallment := func() domain.Movement {
+		m := fixture.MovementMock(
+			fixture.AsMovementExpense(100.0),
+			fixture.WithMovementTypePayment(string(domain.TypePaymentCreditCard)),
+			fixture.WithMovementCreditCardID(&fixture.CreditCardID),
+			fixture.WithMovementInstallment(2, 3),
+			fixture.WithMovementInstallmentGroupID(&groupID),
+			fixture.WithMovementDate(currentDate),
+			fixture.WithMovementIsPaid(false),
+		)
+		m.CreditCardInfo.InvoiceID = &fixture.InvoiceID
+		return m
+	}
+
+	tests := map[string]struct {
+		newMovement domain.Movement
+		mockSetup   func(mockMovRepo *MockMovementRepository, mockInvoiceRepo *MockInvoiceRepository, mockInvoiceUseCase *MockInvoice, mockCreditCardRepo *MockCreditCardRepository)
+		expectedErr error
+	}{
+		"should move installment to next invoice keeping installment group": {
+			newMovement: fixture.MovementMock(
+				fixture.AsMovementExpense(120.0),
+				fixture.WithMovementDate(nextDate),
+				fixture.WithMovementIsPaid(false),
+			),
+			mockSetup: func(mockMovRepo *MockMovementRepository, mockInvoiceRepo *MockInvoiceRepository, mockInvoiceUseCase *MockInvoice, mockCreditCardRepo *MockCreditCardRepository) {
+				currentInvoice := fixture.InvoiceMock(fixture.WithInvoiceAmount(-500.0))
+				nextInvoice := fixture.InvoiceMock(
+					fixture.WithID(nextInvoiceID),
+					fixture.WithInvoicePeriod(time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 11, 30, 0, 0, 0, 0, time.UTC)),
+					fixture.WithInvoiceAmount(-300.0),
+				)
+
+				mockMovRepo.On("FindByID", fixture.MovementID).Return(existingInstallment(), nil)
+				mockInvoiceRepo.On("FindByID", fixture.InvoiceID).Return(currentInvoice, nil)
+				mockCreditCardRepo.On("FindByID", fixture.CreditCardID).Return(fixture.CreditCardMock(), nil)
+
+				mockInvoiceUseCase.On("FindOrCreateInvoiceForMovement", mock.Anything, (*uuid.UUID)(nil), &fixture.CreditCardID, nextDate).
+					Return(nextInvoice, nil)
+				mockInvoiceRepo.On("UpdateAmount", mock.Anything, fixture.InvoiceID, -400.0).Return(currentInvoice, nil)
+				mockInvoiceRepo.On("UpdateAmount", mock.Anything, nextInvoiceID, -420.0).Return(nextInvoice, nil)
+				mockMovRepo.On("UpdateInvoiceID", mock.Anything, fixture.MovementID, nextInvoiceID).Return(nil)
+				mockCreditCardRepo.On("UpdateLimitDelta", mock.Anything, fixture.CreditCardID, -20.0).Return(fixture.CreditCardMock(), nil)
+
+				mockMovRepo.On("Update", mock.Anything, fixture.MovementID, mock.MatchedBy(func(m domain.Movement) bool {
+					return m.TypePayment == domain.TypePaymentCreditCard &&
+						*m.CreditCardInfo.InvoiceID == nextInvoiceID &&
+						*m.CreditCardInfo.InstallmentGroupID == groupID &&
+						*m.CreditCardInfo.InstallmentNumber == 2
+				})).Return(fixture.MovementMock(), nil)
+			},
+		},
+		"should fail when target invoice is already paid": {
+			newMovement: fixture.MovementMock(
+				fixture.AsMovementExpense(100.0),
+				fixture.WithMovementDate(nextDate),
+				fixture.WithMovementIsPaid(false),
+			),
+			mockSetup: func(mockMovRepo *MockMovementRepository, mockInvoiceRepo *MockInvoiceRepository, mockInvoiceUseCase *MockInvoice, mockCreditCardRepo *MockCreditCardRepository) {
+				mockMovRepo.On("FindByID", fixture.MovementID).Return(existingInstallment(), nil)
+				mockInvoiceRepo.On("FindByID", fixture.InvoiceID).Return(fixture.InvoiceMock(), nil)
+				mockInvoiceUseCase.On("FindOrCreateInvoiceForMovement", mock.Anything, (*uuid.UUID)(nil), &fixture.CreditCardID, nextDate).
+					Return(fixture.InvoiceMock(fixture.WithID(nextInvoiceID), fixture.WithInvoiceIsPaid(true)), nil)
+			},
+			expectedErr: ErrInvoiceAlreadyPaid,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockMovRepo := new(MockMovementRepository)
+			mockInvoiceRepo := new(MockInvoiceRepository)
+			mockInvoiceUseCase := new(MockInvoice)
+			mockCreditCardRepo := new(MockCreditCardRepository)
+			mockTxManager := new(MockTransactionManager)
+			mockTxManager.On("WithTransaction", mock.Anything).Return(nil)
+
+			tt.mockSetup(mockMovRepo, mockInvoiceRepo, mockInvoiceUseCase, mockCreditCardRepo)
+
+			usecase := NewMovement(
+				mockMovRepo,
+				new(MockRecurrentRepository),
+				new(MockWalletRepository),
+				new(MockSubCategory),
+				mockInvoiceRepo,
+				mockInvoiceUseCase,
+				mockCreditCardRepo,
+				mockTxManager,
+				nil,
+			)
+
+			_, err := usecase.UpdateOne(context.Background(), fixture.MovementID, tt.newMovement)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			mockMovRepo.AssertExpectations(t)
+			mockInvoiceRepo.AssertExpectations(t)
+			mockInvoiceUseCase.AssertExpectations(t)
+			mockCreditCardRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxActiveAccessTokens caps the tokens a user may have active at once.
+	MaxActiveAccessTokens = 20
+
+	accessTokenDefaultDays = 90
+	accessTokenMaxDays     = 365
+	accessTokenRandomBytes = 32
+	accessTokenPrefixLen   = 12
+	accessTokenNameMaxLen  = 100
+
+	// accessTokenTouchInterval throttles the last_used_at writes of a token
+	// used by a script in a loop.
+	accessTokenTouchInterval = 5 * time.Minute
+)
+
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, token domain.PersonalAccessToken) (domain.PersonalAccessToken, error)
+	ListByUser(ctx context.Context, userID string) ([]domain.PersonalAccessToken, error)
+	CountActive(ctx context.Context, userID string, now time.Time) (int, error)
+	FindByHash(ctx context.Context, tokenHash string) (domain.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID string, id uuid.UUID, revokedAt time.Time) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+// PersonalAccessTokens manages the tokens users create to call the API from
+// scripts, and verifies them for the authentication middleware.
+type PersonalAccessTokens struct {
+	repo   PersonalAccessTokenRepository
+	claims EntitlementClaimsGateway
+}
+
+func NewPersonalAccessTokens(repo PersonalAccessTokenRepository, claims EntitlementClaimsGateway) *PersonalAccessTokens {
+	return &PersonalAccessTokens{repo: repo, claims: claims}
+}
+
+type CreateAccessTokenInput struct {
+	Name          string
+	Scopes        []string
+	ExpiresInDays int
+}
+
+// CreatedAccessToken carries the token itself, which is not stored and cannot
+// be shown again.
+type CreatedAccessToken struct {
+	domain.PersonalAccessToken
+	Token string `json:"token"`
+}
+
+func (u *PersonalAccessTokens) CreateToken(ctx context.Context, input CreateAccessTokenInput) (CreatedAccessToken, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return CreatedAccessToken{}, domain.ErrUnauthorized
+	}
+
+	scopes, err := validateAccessTokenInput(&input)
+	if err != nil {
+		return CreatedAccessToken{}, err
+	}
+
+	now := time.Now()
+	active, err := u.repo.CountActive(ctx, userID, now)
+	if err != nil {
+		return CreatedAccessToken{}, err
+	}
+	if active >= MaxActiveAccessTokens {
+		return CreatedAccessToken{}, domain.ErrAccessTokenLimitReached
+	}
+
+	token, err := newAccessToken()
+	if err != nil {
+		return CreatedAccessToken{}, err
+	}
+
+	created, err := u.repo.Create(ctx, domain.PersonalAccessToken{
+		UserID:    userID,
+		Name:      input.Name,
+		TokenHash: hashAccessToken(token),
+		Prefix:    token[:accessTokenPrefixLen],
+		Scopes:    scopes,
+		ExpiresAt: now.AddDate(0, 0, input.ExpiresInDays),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return CreatedAccessToken{}, err
+	}
+	return CreatedAccessToken{PersonalAccessToken: created, Token: token}, nil
+}
+
+func (u *PersonalAccessTokens) ListTokens(ctx context.Context) ([]domain.PersonalAccessToken, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	return u.repo.ListByUser(ctx, userID)
+}
+
+func (u *PersonalAccessTokens) RevokeToken(ctx context.Context, id string) error {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return domain.ErrUnauthorized
+	}
+	tokenID, err := uuid.Parse(id)
+	if err != nil {
+		return domain.ErrAccessTokenNotFound
+	}
+	return u.repo.Revoke(ctx, userID, tokenID, time.Now())
+}
+
+// VerifyAccessToken returns the auth context of an active token, with the plan
+// read from the Firebase claims of its user. Tokens never carry the admin role.
+func (u *PersonalAccessTokens) VerifyAccessToken(ctx context.Context, token string) (authentication.AuthContext, error) {
+	stored, err := u.repo.FindByHash(ctx, hashAccessToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrAccessTokenNotFound) {
+			return authentication.AuthContext{}, domain.ErrAccessTokenInvalid
+		}
+		return authentication.AuthContext{}, err
+	}
+
+	now := time.Now()
+	if !stored.IsActive(now) {
+		return authentication.AuthContext{}, domain.ErrAccessTokenInvalid
+	}
+
+	claims, err := u.claims.GetUserClaims(ctx, stored.UserID)
+	if err != nil {
+		return authentication.AuthContext{}, err
+	}
+	plan := claims.Plan
+	if plan == authentication.PlanPlus && claims.PlanExpiresAt > 0 && now.Unix() > claims.PlanExpiresAt {
+		plan = authentication.PlanFree
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) > accessTokenTouchInterval {
+		if err := u.repo.TouchLastUsed(ctx, stored.ID, now); err != nil {
+			log.WarnContext(ctx, "error updating access token usage", log.Err(err))
+		}
+	}
+
+	authCtx := authentication.NewAuthContext(stored.UserID, "", plan, authentication.RoleUser, claims.MPSubscriptionID, claims.SubscriptionSource, true)
+	authCtx.Scopes = make([]authentication.Scope, len(stored.Scopes))
+	for i, scope := range stored.Scopes {
+		authCtx.Scopes[i] = authentication.Scope(scope)
+	}
+	return authCtx, nil
+}
+
+// validateAccessTokenInput trims the input, fills the default expiry and
+// returns the scopes without duplicates.
+func validateAccessTokenInput(input *CreateAccessTokenInput) ([]string, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || len(input.Name) > accessTokenNameMaxLen {
+		return nil, domain.WrapInvalidInput(domain.New(fmt.Sprintf("name is required and must have up to %d characters", accessTokenNameMaxLen)), "access token")
+	}
+
+	if input.ExpiresInDays == 0 {
+		input.ExpiresInDays = accessTokenDefaultDays
+	}
+	if input.ExpiresInDays < 1 || input.ExpiresInDays > accessTokenMaxDays {
+		return nil, domain.WrapInvalidInput(domain.New(fmt.Sprintf("expires_in_days must be between 1 and %d", accessTokenMaxDays)), "access token")
+	}
+
+	if len(input.Scopes) == 0 {
+		return nil, domain.WrapInvalidInput(domain.New("at least one scope is required"), "access token")
+	}
+	seen := map[string]bool{}
+	scopes := make([]string, 0, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		if !authentication.Scope(scope).IsValid() {
+			return nil, domain.WrapInvalidInput(domain.New(fmt.Sprintf("invalid scope %q: must be one of [read, movements:write, export]", scope)), "access token")
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func newAccessToken() (string, error) {
+	buf := make([]byte, accessTokenRandomBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generating access token: %w", err)
+	}
+	return authentication.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
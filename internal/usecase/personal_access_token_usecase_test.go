@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newAccessTokenTestRepo(t *testing.T) *repository.PersonalAccessTokenRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&repository.PersonalAccessTokenDB{}))
+	return repository.NewPersonalAccessTokenRepository(db)
+}
+
+func accessTokenUserContext(userID string) context.Context {
+	return authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: userID})
+}
+
+func TestPersonalAccessTokens_CreateToken_Validation(t *testing.T) {
+	uc := NewPersonalAccessTokens(newAccessTokenTestRepo(t), new(MockFirebaseSubGateway))
+	ctx := accessTokenUserContext("user-1")
+
+	tests := map[string]struct {
+		input       CreateAccessTokenInput
+		expectedErr error
+	}{
+		"should require a name": {
+			input:       CreateAccessTokenInput{Name: " ", Scopes: []string{"read"}},
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should require a scope": {
+			input:       CreateAccessTokenInput{Name: "cron"},
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should reject an unknown scope": {
+			input:       CreateAccessTokenInput{Name: "cron", Scopes: []string{"admin"}},
+			expectedErr: domain.ErrInvalidInput,
+		},
+		"should reject an expiry over a year": {
+			input:       CreateAccessTokenInput{Name: "cron", Scopes: []string{"read"}, ExpiresInDays: 400},
+			expectedErr: domain.ErrInvalidInput,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.CreateToken(ctx, tt.input)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+
+	_, err := uc.CreateToken(context.Background(), CreateAccessTokenInput{Name: "cron", Scopes: []string{"read"}})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+func TestPersonalAccessTokens_CreateAndVerify(t *testing.T) {
+	log.Initialize()
+	repo := newAccessTokenTestRepo(t)
+	claims := new(MockFirebaseSubGateway)
+	claims.On("GetUserClaims", mock.Anything, "user-1").Return(gateway.UserClaims{
+		Plan:               authentication.PlanPlus,
+		Role:               authentication.RoleAdmin,
+		SubscriptionSource: authentication.SubscriptionSourceStripe,
+	}, nil)
+	uc := NewPersonalAccessTokens(repo, claims)
+	ctx := accessTokenUserContext("user-1")
+
+	created, err := uc.CreateToken(ctx, CreateAccessTokenInput{Name: " cron ", Scopes: []string{"read", "export", "read"}})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, authentication.AccessTokenPrefix))
+	assert.True(t, strings.HasPrefix(created.Token, created.Prefix))
+	assert.Equal(t, "cron", created.Name)
+	assert.Equal(t, []string{"read", "export"}, created.Scopes)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, accessTokenDefaultDays), created.ExpiresAt, time.Minute)
+	assert.NotContains(t, created.TokenHash, created.Token[len(authentication.AccessTokenPrefix):], "only the hash is stored")
+
+	authCtx, err := uc.VerifyAccessToken(context.Background(), created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", authCtx.UserID)
+	assert.Equal(t, authentication.PlanPlus, authCtx.Plan)
+	assert.Equal(t, authentication.RoleUser, authCtx.Role, "tokens never carry the admin role")
+	assert.Equal(t, authentication.SubscriptionSourceStripe, authCtx.SubscriptionSource)
+	assert.True(t, authCtx.Provisioned)
+	assert.Equal(t, []authentication.Scope{authentication.ScopeRead, authentication.ScopeExport}, authCtx.Scopes)
+
+	tokens, err := uc.ListTokens(ctx)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.NotNil(t, tokens[0].LastUsedAt)
+
+	_, err = uc.VerifyAccessToken(context.Background(), authentication.AccessTokenPrefix+"unknown")
+	assert.ErrorIs(t, err, domain.ErrAccessTokenInvalid)
+
+	assert.ErrorIs(t, uc.RevokeToken(accessTokenUserContext("user-2"), created.ID.String()), domain.ErrAccessTokenNotFound, "only the owner revokes")
+	require.NoError(t, uc.RevokeToken(ctx, created.ID.String()))
+
+	_, err = uc.VerifyAccessToken(context.Background(), created.Token)
+	assert.ErrorIs(t, err, domain.ErrAccessTokenInvalid)
+}
+
+func TestPersonalAccessTokens_VerifyAccessToken_ExpiredPlan(t *testing.T) {
+	log.Initialize()
+	repo := newAccessTokenTestRepo(t)
+	claims := new(MockFirebaseSubGateway)
+	claims.On("GetUserClaims", mock.Anything, "user-1").Return(gateway.UserClaims{
+		Plan:          authentication.PlanPlus,
+		PlanExpiresAt: time.Now().Add(-time.Hour).Unix(),
+	}, nil)
+	uc := NewPersonalAccessTokens(repo, claims)
+
+	created, err := uc.CreateToken(accessTokenUserContext("user-1"), CreateAccessTokenInput{Name: "cron", Scopes: []string{"read"}, ExpiresInDays: 1})
+	require.NoError(t, err)
+
+	authCtx, err := uc.VerifyAccessToken(context.Background(), created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, authentication.PlanFree, authCtx.Plan)
+}
+
+func TestPersonalAccessTokens_VerifyAccessToken_Expired(t *testing.T) {
+	repo := newAccessTokenTestRepo(t)
+	uc := NewPersonalAccessTokens(repo, new(MockFirebaseSubGateway))
+
+	token := authentication.AccessTokenPrefix + "expired-token"
+	_, err := repo.Create(context.Background(), domain.PersonalAccessToken{
+		ID:        uuid.New(),
+		UserID:    "user-1",
+		Name:      "old",
+		TokenHash: hashAccessToken(token),
+		Prefix:    token[:accessTokenPrefixLen],
+		Scopes:    []string{"read"},
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	_, err = uc.VerifyAccessToken(context.Background(), token)
+	assert.ErrorIs(t, err, domain.ErrAccessTokenInvalid)
+}
+
+func TestPersonalAccessTokens_CreateToken_Limit(t *testing.T) {
+	uc := NewPersonalAccessTokens(newAccessTokenTestRepo(t), new(MockFirebaseSubGateway))
+	ctx := accessTokenUserContext("user-1")
+
+	for i := 0; i < MaxActiveAccessTokens; i++ {
+		_, err := uc.CreateToken(ctx, CreateAccessTokenInput{Name: "cron", Scopes: []string{"read"}})
+		require.NoError(t, err)
+	}
+
+	_, err := uc.CreateToken(ctx, CreateAccessTokenInput{Name: "cron", Scopes: []string{"read"}})
+	assert.ErrorIs(t, err, domain.ErrAccessTokenLimitReached)
+}
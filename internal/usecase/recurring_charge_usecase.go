@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+)
+
+type RecurringChargeMovementRepository interface {
+	FindStandaloneExpensesSince(ctx context.Context, from time.Time) (domain.MovementList, error)
+}
+
+// RecurringCharges procura no histórico do usuário despesas que se repetem sem
+// estar cadastradas como recorrentes.
+type RecurringCharges struct {
+	movementRepo RecurringChargeMovementRepository
+}
+
+func NewRecurringCharges(movementRepo RecurringChargeMovementRepository) *RecurringCharges {
+	return &RecurringCharges{movementRepo: movementRepo}
+}
+
+// Detect analisa os últimos meses completos até now, o suficiente para ver duas
+// cobranças de uma assinatura anual.
+func (uc *RecurringCharges) Detect(ctx context.Context, now time.Time) ([]domain.DetectedRecurringCharge, error) {
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).
+		AddDate(0, -domain.RecurringChargeLookbackMonths, 0)
+
+	movements, err := uc.movementRepo.FindStandaloneExpensesSince(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar despesas para detectar cobranças recorrentes: %w", err)
+	}
+
+	detected := domain.DetectRecurringCharges(movements, now)
+	if detected == nil {
+		detected = []domain.DetectedRecurringCharge{}
+	}
+	return detected, nil
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringCharges_Detect(t *testing.T) {
+	now := time.Date(2026, time.March, 20, 10, 0, 0, 0, time.UTC)
+	from := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should detect charges over the lookback window", func(t *testing.T) {
+		repo := &MockRecurringChargeMovementRepository{}
+		var movements domain.MovementList
+		for i := 0; i < 4; i++ {
+			date := time.Date(2025, time.December, 5, 0, 0, 0, 0, time.UTC).AddDate(0, i, 0)
+			movements = append(movements, domain.Movement{
+				Description: "Spotify",
+				Amount:      -21.9,
+				Date:        &date,
+				TypePayment: domain.TypePaymentCreditCard,
+			})
+		}
+		repo.On("FindStandaloneExpensesSince", from).Return(movements, nil)
+
+		detected, err := NewRecurringCharges(repo).Detect(context.Background(), now)
+
+		require.NoError(t, err)
+		require.Len(t, detected, 1)
+		assert.Equal(t, "Spotify", detected[0].Description)
+		assert.True(t, detected[0].PossiblyForgotten)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return an empty list when nothing repeats", func(t *testing.T) {
+		repo := &MockRecurringChargeMovementRepository{}
+		repo.On("FindStandaloneExpensesSince", from).Return(domain.MovementList{}, nil)
+
+		detected, err := NewRecurringCharges(repo).Detect(context.Background(), now)
+
+		require.NoError(t, err)
+		assert.NotNil(t, detected)
+		assert.Empty(t, detected)
+	})
+
+	t.Run("should wrap repository errors", func(t *testing.T) {
+		repo := &MockRecurringChargeMovementRepository{}
+		repoErr := errors.New("db down")
+		repo.On("FindStandaloneExpensesSince", from).Return(domain.MovementList(nil), repoErr)
+
+		_, err := NewRecurringCharges(repo).Detect(context.Background(), now)
+
+		assert.ErrorIs(t, err, repoErr)
+	})
+}
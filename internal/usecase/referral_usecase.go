@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+)
+
+const referralCodeMaxAttempts = 5
+
+type ReferralRepository interface {
+	FindCodeByUser(ctx context.Context, userID string) (domain.ReferralCode, error)
+	FindCode(ctx context.Context, code string) (domain.ReferralCode, error)
+	CreateCode(ctx context.Context, code domain.ReferralCode) (bool, error)
+	Create(ctx context.Context, referral domain.Referral) error
+	FindByReferee(ctx context.Context, refereeID string) (domain.Referral, error)
+	ListByReferrer(ctx context.Context, referrerID string) ([]domain.Referral, error)
+	Reject(ctx context.Context, referralID uuid.UUID, reason string) error
+	Qualify(ctx context.Context, referralID uuid.UUID, qualifiedAt time.Time, rewards []domain.ReferralReward) (bool, error)
+	ListRewardsByUser(ctx context.Context, userID string) ([]domain.ReferralReward, error)
+	ListPendingRewards(ctx context.Context) ([]domain.ReferralReward, error)
+	ClaimReward(ctx context.Context, rewardID uuid.UUID, plusUntil time.Time) (bool, error)
+	MarkRewardApplied(ctx context.Context, rewardID uuid.UUID, appliedAt time.Time) error
+}
+
+// Referrals attributes new users to the code that referred them and rewards
+// both sides with free Plus months once the referred user pays a subscription.
+type Referrals struct {
+	repo         ReferralRepository
+	deviceRepo   PushDeviceRepository
+	claims       EntitlementClaimsGateway
+	rewardMonths int
+}
+
+func NewReferrals(repo ReferralRepository, deviceRepo PushDeviceRepository, claims EntitlementClaimsGateway) *Referrals {
+	return &Referrals{
+		repo:         repo,
+		deviceRepo:   deviceRepo,
+		claims:       claims,
+		rewardMonths: authentication.GetReferralRewardMonths(),
+	}
+}
+
+// ReferralSummary is a referral made by the user. The referred user is not
+// identified.
+type ReferralSummary struct {
+	Status      domain.ReferralStatus `json:"status"`
+	CreatedAt   time.Time             `json:"created_at"`
+	QualifiedAt *time.Time            `json:"qualified_at,omitempty"`
+}
+
+type ReferralOverview struct {
+	Code      string                  `json:"code"`
+	Referred  bool                    `json:"referred"`
+	Referrals []ReferralSummary       `json:"referrals"`
+	Rewards   []domain.ReferralReward `json:"rewards"`
+}
+
+// Overview returns the referral code of the user, creating it on first use,
+// with the referrals made and the rewards earned.
+func (u *Referrals) Overview(ctx context.Context) (ReferralOverview, error) {
+	userID := authentication.UserIDFromContext(ctx)
+	if userID == "" {
+		return ReferralOverview{}, domain.ErrUnauthorized
+	}
+
+	code, err := u.codeFor(ctx, userID)
+	if err != nil {
+		return ReferralOverview{}, err
+	}
+
+	overview := ReferralOverview{
+		Code:      code.Code,
+		Referrals: []ReferralSummary{},
+	}
+
+	if _, err := u.repo.FindByReferee(ctx, userID); err == nil {
+		overview.Referred = true
+	} else if !errors.Is(err, domain.ErrReferralNotFound) {
+		return ReferralOverview{}, err
+	}
+
+	referrals, err := u.repo.ListByReferrer(ctx, userID)
+	if err != nil {
+		return ReferralOverview{}, err
+	}
+	for _, referral := range referrals {
+		overview.Referrals = append(overview.Referrals, ReferralSummary{
+			Status:      referral.Status,
+			CreatedAt:   referral.CreatedAt,
+			QualifiedAt: referral.QualifiedAt,
+		})
+	}
+
+	overview.Rewards, err = u.repo.ListRewardsByUser(ctx, userID)
+	if err != nil {
+		return ReferralOverview{}, err
+	}
+	return overview, nil
+}
+
+func (u *Referrals) codeFor(ctx context.Context, userID string) (domain.ReferralCode, error) {
+	code, err := u.repo.FindCodeByUser(ctx, userID)
+	if err == nil || !errors.Is(err, domain.ErrReferralCodeNotFound) {
+		return code, err
+	}
+
+	for attempt := 0; attempt < referralCodeMaxAttempts; attempt++ {
+		codes, err := newCouponCodes("", 1)
+		if err != nil {
+			return domain.ReferralCode{}, err
+		}
+		if _, err := u.repo.CreateCode(ctx, domain.ReferralCode{UserID: userID, Code: codes[0]}); err != nil {
+			return domain.ReferralCode{}, err
+		}
+
+		// Nothing inserted means either a concurrent request created the code of
+		// the user, or the random code is taken and another one is tried.
+		code, err = u.repo.FindCodeByUser(ctx, userID)
+		if !errors.Is(err, domain.ErrReferralCodeNotFound) {
+			return code, err
+		}
+	}
+	return domain.ReferralCode{}, fmt.Errorf("error generating referral code for user %s", userID)
+}
+
+// Attribute records that the new user signed up with the referral code. It is
+// called once, when the user is provisioned.
+func (u *Referrals) Attribute(ctx context.Context, refereeID, code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil
+	}
+
+	owner, err := u.repo.FindCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	if owner.UserID == refereeID {
+		return domain.ErrReferralSelf
+	}
+
+	return u.repo.Create(ctx, domain.Referral{
+		ReferrerID: owner.UserID,
+		RefereeID:  refereeID,
+		Code:       code,
+		Status:     domain.ReferralPending,
+	})
+}
+
+// QualifyReferral rewards the referral of the user, if any, on their first paid
+// subscription. A referee sharing a device with the referrer is rejected.
+// Rewards that cannot be applied now are left pending for ApplyPendingRewards:
+// the referee has just paid, so their months only start once that subscription
+// ends.
+func (u *Referrals) QualifyReferral(ctx context.Context, refereeID string) error {
+	referral, err := u.repo.FindByReferee(ctx, refereeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrReferralNotFound) {
+			return nil
+		}
+		return err
+	}
+	if referral.Status != domain.ReferralPending {
+		return nil
+	}
+
+	shared, err := u.shareDevice(ctx, referral.ReferrerID, referral.RefereeID)
+	if err != nil {
+		return err
+	}
+	if shared {
+		log.InfoContext(ctx, "referral rejected",
+			log.String("referral_id", referral.ID.String()),
+			log.String("reason", domain.ReferralRejectSameDevice),
+		)
+		return u.repo.Reject(ctx, referral.ID, domain.ReferralRejectSameDevice)
+	}
+
+	now := time.Now()
+	rewards := []domain.ReferralReward{
+		u.newReward(referral, referral.ReferrerID, domain.ReferralRoleReferrer, now),
+		u.newReward(referral, referral.RefereeID, domain.ReferralRoleReferee, now),
+	}
+	qualified, err := u.repo.Qualify(ctx, referral.ID, now, rewards)
+	if err != nil || !qualified {
+		return err
+	}
+
+	for _, reward := range rewards {
+		if _, err := u.applyReward(ctx, reward, now); err != nil {
+			log.ErrorContext(ctx, "error applying referral reward",
+				log.String("reward_id", reward.ID.String()),
+				log.Err(err),
+			)
+		}
+	}
+	return nil
+}
+
+func (u *Referrals) newReward(referral domain.Referral, userID string, role domain.ReferralRole, now time.Time) domain.ReferralReward {
+	return domain.ReferralReward{
+		ID:         uuid.New(),
+		ReferralID: referral.ID,
+		UserID:     userID,
+		Role:       role,
+		Months:     u.rewardMonths,
+		Status:     domain.ReferralRewardPending,
+		CreatedAt:  now,
+	}
+}
+
+func (u *Referrals) shareDevice(ctx context.Context, referrerID, refereeID string) (bool, error) {
+	devices, err := u.deviceRepo.FindByUserIDs(ctx, []string{referrerID, refereeID})
+	if err != nil {
+		return false, fmt.Errorf("error finding devices: %w", err)
+	}
+
+	owners := map[string]string{}
+	for _, device := range devices {
+		if owner, ok := owners[device.ExpoPushToken]; ok && owner != device.UserID {
+			return true, nil
+		}
+		owners[device.ExpoPushToken] = device.UserID
+	}
+	return false, nil
+}
+
+type ReferralRewardsResult struct {
+	Checked  int `json:"checked"`
+	Applied  int `json:"applied"`
+	Deferred int `json:"deferred"`
+	Failed   int `json:"failed"`
+}
+
+// ApplyPendingRewards applies the rewards left pending because their users
+// were paying a subscription.
+func (u *Referrals) ApplyPendingRewards(ctx context.Context, now time.Time) (ReferralRewardsResult, error) {
+	var result ReferralRewardsResult
+
+	rewards, err := u.repo.ListPendingRewards(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	for _, reward := range rewards {
+		result.Checked++
+		applied, err := u.applyReward(ctx, reward, now)
+		switch {
+		case err != nil:
+			log.ErrorContext(ctx, "error applying referral reward",
+				log.String("reward_id", reward.ID.String()),
+				log.Err(err),
+			)
+			result.Failed++
+		case applied:
+			result.Applied++
+		default:
+			result.Deferred++
+		}
+	}
+
+	log.Info("referral rewards job completed",
+		log.Int("checked", result.Checked),
+		log.Int("applied", result.Applied),
+		log.Int("deferred", result.Deferred),
+		log.Int("failed", result.Failed),
+	)
+	return result, nil
+}
+
+// applyReward grants the free months as Plus without a subscription source,
+// which the entitlement reconciliation keeps until it expires. A user on a
+// paid or unlimited plan keeps the reward pending; a previous grant is
+// extended. The reward is claimed with its expiry before the claims are set,
+// so a retry after a failure grants the same months instead of adding them
+// again.
+func (u *Referrals) applyReward(ctx context.Context, reward domain.ReferralReward, now time.Time) (bool, error) {
+	if reward.Status == domain.ReferralRewardApplying && reward.PlusUntil != nil {
+		return u.grantReward(ctx, reward.ID, reward.UserID, *reward.PlusUntil, now)
+	}
+
+	claims, err := u.claims.GetUserClaims(ctx, reward.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	start := now
+	if claims.Plan == authentication.PlanPlus && (claims.PlanExpiresAt == 0 || claims.PlanExpiresAt > now.Unix()) {
+		granted := claims.SubscriptionSource == authentication.SubscriptionSourceNone && claims.MPSubscriptionID == ""
+		if !granted || claims.PlanExpiresAt == 0 {
+			return false, nil
+		}
+		start = time.Unix(claims.PlanExpiresAt, 0)
+	}
+
+	plusUntil := start.AddDate(0, reward.Months, 0)
+	claimed, err := u.repo.ClaimReward(ctx, reward.ID, plusUntil)
+	if err != nil || !claimed {
+		return false, err
+	}
+	return u.grantReward(ctx, reward.ID, reward.UserID, plusUntil, now)
+}
+
+func (u *Referrals) grantReward(ctx context.Context, rewardID uuid.UUID, userID string, plusUntil, now time.Time) (bool, error) {
+	err := u.claims.SetUserSubscription(ctx, userID, authentication.PlanPlus, "", authentication.SubscriptionSourceNone, plusUntil.Unix())
+	if err != nil {
+		return false, err
+	}
+	if err := u.repo.MarkRewardApplied(ctx, rewardID, now); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/gateway"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newReferralTestRepo(t *testing.T) *repository.ReferralRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&repository.ReferralCodeDB{}, &repository.ReferralDB{}, &repository.ReferralRewardDB{}))
+	return repository.NewReferralRepository(db)
+}
+
+func TestReferrals_Attribute(t *testing.T) {
+	ctx := context.Background()
+	repo := newReferralTestRepo(t)
+	_, err := repo.CreateCode(ctx, domain.ReferralCode{UserID: "referrer", Code: "ABCD2345"})
+	require.NoError(t, err)
+
+	uc := NewReferrals(repo, new(MockPushDeviceRepository), new(MockFirebaseSubGateway))
+
+	tests := map[string]struct {
+		refereeID   string
+		code        string
+		expectedErr error
+	}{
+		"should reject the owner of the code": {
+			refereeID:   "referrer",
+			code:        "ABCD2345",
+			expectedErr: domain.ErrReferralSelf,
+		},
+		"should reject an unknown code": {
+			refereeID:   "referee",
+			code:        "ZZZZ9999",
+			expectedErr: domain.ErrReferralCodeNotFound,
+		},
+		"should attribute a code typed in lowercase": {
+			refereeID: "referee",
+			code:      " abcd2345 ",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := uc.Attribute(ctx, tt.refereeID, tt.code)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			referral, err := repo.FindByReferee(ctx, tt.refereeID)
+			require.NoError(t, err)
+			assert.Equal(t, "referrer", referral.ReferrerID)
+			assert.Equal(t, domain.ReferralPending, referral.Status)
+		})
+	}
+}
+
+func TestReferrals_Overview(t *testing.T) {
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "referrer"})
+	uc := NewReferrals(newReferralTestRepo(t), new(MockPushDeviceRepository), new(MockFirebaseSubGateway))
+
+	first, err := uc.Overview(ctx)
+	require.NoError(t, err)
+	assert.Len(t, first.Code, couponCodeLength)
+	assert.False(t, first.Referred)
+	assert.Empty(t, first.Referrals)
+
+	second, err := uc.Overview(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first.Code, second.Code, "the code is created once")
+}
+
+func TestReferrals_QualifyReferral(t *testing.T) {
+	log.Initialize()
+	ctx := context.Background()
+	grantEnd := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second)
+
+	tests := map[string]struct {
+		devices          []domain.Device
+		referrerClaims   gateway.UserClaims
+		expectedStatus   domain.ReferralStatus
+		expectedReferrer domain.ReferralRewardStatus
+		expectedStart    time.Time
+	}{
+		"should reject a referee on the device of the referrer": {
+			devices: []domain.Device{
+				{UserID: "referrer", ExpoPushToken: "ExponentPushToken[a]"},
+				{UserID: "referee", ExpoPushToken: "ExponentPushToken[a]"},
+			},
+			expectedStatus: domain.ReferralRejected,
+		},
+		"should grant the months to a free referrer": {
+			devices: []domain.Device{
+				{UserID: "referrer", ExpoPushToken: "ExponentPushToken[a]"},
+				{UserID: "referee", ExpoPushToken: "ExponentPushToken[b]"},
+			},
+			referrerClaims:   gateway.UserClaims{Plan: authentication.PlanFree},
+			expectedStatus:   domain.ReferralQualified,
+			expectedReferrer: domain.ReferralRewardApplied,
+		},
+		"should extend a previous grant of the referrer": {
+			referrerClaims:   gateway.UserClaims{Plan: authentication.PlanPlus, PlanExpiresAt: grantEnd.Unix()},
+			expectedStatus:   domain.ReferralQualified,
+			expectedReferrer: domain.ReferralRewardApplied,
+			expectedStart:    grantEnd,
+		},
+		"should defer the reward of a paying referrer": {
+			referrerClaims:   gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceStripe},
+			expectedStatus:   domain.ReferralQualified,
+			expectedReferrer: domain.ReferralRewardPending,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := newReferralTestRepo(t)
+			require.NoError(t, repo.Create(ctx, domain.Referral{ReferrerID: "referrer", RefereeID: "referee", Code: "ABCD2345"}))
+
+			deviceRepo := new(MockPushDeviceRepository)
+			deviceRepo.On("FindByUserIDs", []string{"referrer", "referee"}).Return(tt.devices, nil)
+			claims := new(MockFirebaseSubGateway)
+			claims.On("GetUserClaims", mock.Anything, "referrer").Return(tt.referrerClaims, nil)
+			// The referee has just paid, so their reward waits.
+			claims.On("GetUserClaims", mock.Anything, "referee").Return(gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceIAP}, nil)
+			claims.On("SetUserSubscription", mock.Anything, "referrer", authentication.PlanPlus, "", authentication.SubscriptionSourceNone, mock.Anything).Return(nil)
+
+			uc := NewReferrals(repo, deviceRepo, claims)
+			require.NoError(t, uc.QualifyReferral(ctx, "referee"))
+			// A replayed webhook does not reward again.
+			require.NoError(t, uc.QualifyReferral(ctx, "referee"))
+
+			referral, err := repo.FindByReferee(ctx, "referee")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, referral.Status)
+
+			referrerRewards, err := repo.ListRewardsByUser(ctx, "referrer")
+			require.NoError(t, err)
+			refereeRewards, err := repo.ListRewardsByUser(ctx, "referee")
+			require.NoError(t, err)
+
+			if tt.expectedStatus == domain.ReferralRejected {
+				assert.Equal(t, domain.ReferralRejectSameDevice, referral.RejectReason)
+				assert.Empty(t, referrerRewards)
+				claims.AssertNotCalled(t, "SetUserSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.Len(t, referrerRewards, 1)
+			require.Len(t, refereeRewards, 1)
+			assert.Equal(t, tt.expectedReferrer, referrerRewards[0].Status)
+			assert.Equal(t, domain.ReferralRewardPending, refereeRewards[0].Status)
+
+			if tt.expectedReferrer != domain.ReferralRewardApplied {
+				claims.AssertNotCalled(t, "SetUserSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NotNil(t, referrerRewards[0].PlusUntil)
+			if !tt.expectedStart.IsZero() {
+				assert.Equal(t, tt.expectedStart.AddDate(0, 1, 0).Unix(), referrerRewards[0].PlusUntil.Unix())
+			}
+			claims.AssertCalled(t, "SetUserSubscription", mock.Anything, "referrer", authentication.PlanPlus, "", authentication.SubscriptionSourceNone, referrerRewards[0].PlusUntil.Unix())
+		})
+	}
+}
+
+func TestReferrals_ApplyPendingRewards(t *testing.T) {
+	log.Initialize()
+	ctx := context.Background()
+	now := time.Now()
+	repo := newReferralTestRepo(t)
+	require.NoError(t, repo.Create(ctx, domain.Referral{ReferrerID: "referrer", RefereeID: "referee", Code: "ABCD2345"}))
+	referral, err := repo.FindByReferee(ctx, "referee")
+	require.NoError(t, err)
+
+	uc := NewReferrals(repo, new(MockPushDeviceRepository), new(MockFirebaseSubGateway))
+	_, err = repo.Qualify(ctx, referral.ID, now, []domain.ReferralReward{
+		uc.newReward(referral, "referrer", domain.ReferralRoleReferrer, now),
+		uc.newReward(referral, "referee", domain.ReferralRoleReferee, now),
+	})
+	require.NoError(t, err)
+
+	claims := new(MockFirebaseSubGateway)
+	claims.On("GetUserClaims", mock.Anything, "referrer").Return(gateway.UserClaims{Plan: authentication.PlanFree}, nil)
+	claims.On("GetUserClaims", mock.Anything, "referee").Return(gateway.UserClaims{Plan: authentication.PlanPlus, SubscriptionSource: authentication.SubscriptionSourceStripe}, nil)
+	claims.On("SetUserSubscription", mock.Anything, "referrer", authentication.PlanPlus, "", authentication.SubscriptionSourceNone, now.AddDate(0, 1, 0).Unix()).Return(nil)
+	uc.claims = claims
+
+	result, err := uc.ApplyPendingRewards(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, ReferralRewardsResult{Checked: 2, Applied: 1, Deferred: 1}, result)
+
+	pending, err := repo.ListPendingRewards(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "referee", pending[0].UserID)
+}
+
+// markFailingReferralRepo fails the first MarkRewardApplied, as a database
+// error after the claims were set would.
+type markFailingReferralRepo struct {
+	*repository.ReferralRepository
+	failed bool
+}
+
+func (r *markFailingReferralRepo) MarkRewardApplied(ctx context.Context, rewardID uuid.UUID, appliedAt time.Time) error {
+	if !r.failed {
+		r.failed = true
+		return errors.New("connection reset")
+	}
+	return r.ReferralRepository.MarkRewardApplied(ctx, rewardID, appliedAt)
+}
+
+func TestReferrals_ApplyPendingRewards_RetryAfterMarkFailure(t *testing.T) {
+	log.Initialize()
+	ctx := context.Background()
+	now := time.Now()
+	repo := &markFailingReferralRepo{ReferralRepository: newReferralTestRepo(t)}
+	require.NoError(t, repo.Create(ctx, domain.Referral{ReferrerID: "referrer", RefereeID: "referee", Code: "ABCD2345"}))
+	referral, err := repo.FindByReferee(ctx, "referee")
+	require.NoError(t, err)
+
+	uc := NewReferrals(repo, new(MockPushDeviceRepository), new(MockFirebaseSubGateway))
+	_, err = repo.Qualify(ctx, referral.ID, now, []domain.ReferralReward{
+		uc.newReward(referral, "referrer", domain.ReferralRoleReferrer, now),
+	})
+	require.NoError(t, err)
+
+	plusUntil := now.AddDate(0, 1, 0)
+	claims := new(MockFirebaseSubGateway)
+	claims.On("GetUserClaims", mock.Anything, "referrer").Return(gateway.UserClaims{Plan: authentication.PlanFree}, nil).Once()
+	claims.On("SetUserSubscription", mock.Anything, "referrer", authentication.PlanPlus, "", authentication.SubscriptionSourceNone, plusUntil.Unix()).Return(nil).Twice()
+	uc.claims = claims
+
+	result, err := uc.ApplyPendingRewards(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, ReferralRewardsResult{Checked: 1, Failed: 1}, result)
+
+	// The claims now carry the granted months; the retry must not add them on
+	// top of the new expiry.
+	result, err = uc.ApplyPendingRewards(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, ReferralRewardsResult{Checked: 1, Applied: 1}, result)
+	claims.AssertExpectations(t)
+
+	pending, err := repo.ListPendingRewards(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
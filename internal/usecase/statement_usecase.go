@@ -28,7 +28,7 @@ type StatementPDFDecryptor interface {
 }
 
 type StatementClassificationGateway interface {
-	ClassifyMovements(ctx context.Context, movements []domain.ExtractedMovement, categories []domain.Category) ([]domain.CategorySuggestion, error)
+	ClassifyMovements(ctx context.Context, movements []domain.ExtractedMovement, categories []domain.Category) ([]domain.CategorySuggestion, domain.AIUsage, error)
 }
 
 type StatementMovementRepository interface {
@@ -52,6 +52,7 @@ type StatementUseCase struct {
 	categoryRepo          StatementCategoryRepository
 	limitsValidator       PlanLimitsValidatorInterface
 	pdfDecryptor          StatementPDFDecryptor
+	aiQuota               AIQuotaGuard
 }
 
 func NewStatementUseCase(
@@ -61,6 +62,7 @@ func NewStatementUseCase(
 	categoryRepo StatementCategoryRepository,
 	limitsValidator PlanLimitsValidatorInterface,
 	pdfDecryptor StatementPDFDecryptor,
+	aiQuota AIQuotaGuard,
 ) *StatementUseCase {
 	return &StatementUseCase{
 		visionGateway:         visionGateway,
@@ -69,6 +71,7 @@ func NewStatementUseCase(
 		categoryRepo:          categoryRepo,
 		limitsValidator:       limitsValidator,
 		pdfDecryptor:          pdfDecryptor,
+		aiQuota:               aiQuota,
 	}
 }
 
@@ -103,12 +106,22 @@ func (u *StatementUseCase) Extract(ctx context.Context, fileBytes []byte, mimeTy
 		fileBytes = decrypted
 	}
 
+	if u.aiQuota != nil {
+		if err := u.aiQuota.Check(ctx); err != nil {
+			return domain.StatementExtractResult{}, err
+		}
+	}
+
 	// Call Gemini Vision
 	result, err := u.visionGateway.ExtractMovements(ctx, fileBytes, mimeType)
 	if err != nil {
 		return domain.StatementExtractResult{}, fmt.Errorf("extract movements: %w", err)
 	}
 
+	if u.aiQuota != nil {
+		u.aiQuota.Record(ctx, domain.AIFeatureStatementExtract, result.Usage)
+	}
+
 	metrics.IncBusiness(ctx, "biz_statement_imports_total", 1,
 		metrics.String("mime_type", mimeType),
 	)
@@ -166,12 +179,22 @@ func (u *StatementUseCase) Classify(ctx context.Context, input domain.StatementC
 			toClassify[j] = input.Movements[idx]
 		}
 
-		aiSuggestions, err := u.classificationGateway.ClassifyMovements(ctx, toClassify, categories)
+		if u.aiQuota != nil {
+			if err := u.aiQuota.Check(ctx); err != nil {
+				return domain.StatementClassifyResult{}, err
+			}
+		}
+
+		aiSuggestions, usage, err := u.classificationGateway.ClassifyMovements(ctx, toClassify, categories)
 		if err != nil {
 			// Non-fatal: return what we have from history, AI slots remain zero-value
 			return domain.StatementClassifyResult{Suggestions: suggestions}, nil
 		}
 
+		if u.aiQuota != nil {
+			u.aiQuota.Record(ctx, domain.AIFeatureStatementClassify, usage)
+		}
+
 		for j, idx := range needsAI {
 			if j < len(aiSuggestions) {
 				suggestions[idx] = aiSuggestions[j]
@@ -28,7 +28,7 @@ func newStatementUseCase(
 	movRepo *MockStatementMovementRepository,
 	catRepo *MockStatementCategoryRepository,
 ) *StatementUseCase {
-	return NewStatementUseCase(visionGw, classGw, movRepo, catRepo, nil, nil)
+	return NewStatementUseCase(visionGw, classGw, movRepo, catRepo, nil, nil, nil)
 }
 
 func authedCtx() context.Context {
@@ -78,7 +78,7 @@ func TestStatementUseCase_Classify(t *testing.T) {
 					Return([]domain.CategorySuggestion{
 						{Description: movements[0].Description, CategoryID: &catID, Confidence: 0.9, Source: "ai"},
 						{Description: movements[1].Description, CategoryID: &catID, Confidence: 0.75, Source: "ai"},
-					}, nil)
+					}, domain.AIUsage{}, nil)
 			},
 			expectedSources: []string{"ai", "ai"},
 			expectedCatIDs:  []*uuid.UUID{&catID, &catID},
@@ -93,7 +93,7 @@ func TestStatementUseCase_Classify(t *testing.T) {
 				classGw.On("ClassifyMovements", []domain.ExtractedMovement{movements[1]}, categories).
 					Return([]domain.CategorySuggestion{
 						{Description: movements[1].Description, CategoryID: &catID, Confidence: 0.8, Source: "ai"},
-					}, nil)
+					}, domain.AIUsage{}, nil)
 			},
 			expectedSources: []string{"history", "ai"},
 			expectedCatIDs:  []*uuid.UUID{&catID, &catID},
@@ -175,7 +175,7 @@ func TestStatementUseCase_Extract(t *testing.T) {
 		visionGw.On("ExtractMovements", decryptedBytes, "application/pdf").Return(extracted, nil)
 
 		uc := NewStatementUseCase(visionGw, &MockStatementClassificationGateway{},
-			&MockStatementMovementRepository{}, &MockStatementCategoryRepository{}, nil, decryptor)
+			&MockStatementMovementRepository{}, &MockStatementCategoryRepository{}, nil, decryptor, nil)
 
 		result, err := uc.Extract(authedCtx(), rawBytes, "application/pdf", "s3cret")
 
@@ -192,7 +192,7 @@ func TestStatementUseCase_Extract(t *testing.T) {
 		visionGw.On("ExtractMovements", rawBytes, "image/png").Return(extracted, nil)
 
 		uc := NewStatementUseCase(visionGw, &MockStatementClassificationGateway{},
-			&MockStatementMovementRepository{}, &MockStatementCategoryRepository{}, nil, decryptor)
+			&MockStatementMovementRepository{}, &MockStatementCategoryRepository{}, nil, decryptor, nil)
 
 		result, err := uc.Extract(authedCtx(), rawBytes, "image/png", "")
 
@@ -213,7 +213,7 @@ func TestStatementUseCase_Extract(t *testing.T) {
 			decryptor.On("Prepare", rawBytes, "").Return([]byte(nil), prepErr)
 
 			uc := NewStatementUseCase(visionGw, &MockStatementClassificationGateway{},
-				&MockStatementMovementRepository{}, &MockStatementCategoryRepository{}, nil, decryptor)
+				&MockStatementMovementRepository{}, &MockStatementCategoryRepository{}, nil, decryptor, nil)
 
 			_, err := uc.Extract(authedCtx(), rawBytes, "application/pdf", "")
 
@@ -222,6 +222,39 @@ func TestStatementUseCase_Extract(t *testing.T) {
 			visionGw.AssertNotCalled(t, "ExtractMovements", mock.Anything, mock.Anything)
 		})
 	}
+
+	t.Run("usage of the call is recorded in the AI quota", func(t *testing.T) {
+		visionGw := &MockStatementVisionGateway{}
+		quota := &MockAIQuotaGuard{}
+		withUsage := extracted
+		withUsage.Usage = domain.AIUsage{Requests: 1, InputTokens: 900, OutputTokens: 120}
+
+		quota.On("Check").Return(nil)
+		quota.On("Record", domain.AIFeatureStatementExtract, withUsage.Usage).Return()
+		visionGw.On("ExtractMovements", rawBytes, "image/png").Return(withUsage, nil)
+
+		uc := NewStatementUseCase(visionGw, &MockStatementClassificationGateway{},
+			&MockStatementMovementRepository{}, &MockStatementCategoryRepository{}, nil, nil, quota)
+
+		_, err := uc.Extract(authedCtx(), rawBytes, "image/png", "")
+
+		assert.NoError(t, err)
+		quota.AssertExpectations(t)
+	})
+
+	t.Run("exhausted AI quota blocks the call to vision", func(t *testing.T) {
+		visionGw := &MockStatementVisionGateway{}
+		quota := &MockAIQuotaGuard{}
+		quota.On("Check").Return(ErrAIQuotaExceeded)
+
+		uc := NewStatementUseCase(visionGw, &MockStatementClassificationGateway{},
+			&MockStatementMovementRepository{}, &MockStatementCategoryRepository{}, nil, nil, quota)
+
+		_, err := uc.Extract(authedCtx(), rawBytes, "image/png", "")
+
+		assert.ErrorIs(t, err, ErrAIQuotaExceeded)
+		visionGw.AssertNotCalled(t, "ExtractMovements", mock.Anything, mock.Anything)
+	})
 }
 
 // --- Confirm ---
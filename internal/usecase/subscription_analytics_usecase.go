@@ -0,0 +1,611 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+	"personal-finance/internal/plataform/authentication"
+
+	"github.com/google/uuid"
+)
+
+const (
+	analyticsMonthLayout = "2006-01"
+	analyticsMaxMonths   = 36
+	analyticsMonths      = 12
+)
+
+// Reports of the subscription analytics, also the names accepted by the CSV
+// export.
+const (
+	AnalyticsReportMRR       = "mrr"
+	AnalyticsReportMovements = "movements"
+	AnalyticsReportCoupons   = "coupons"
+	AnalyticsReportTrials    = "trials"
+	AnalyticsReportCohorts   = "cohorts"
+)
+
+type (
+	AnalyticsSubscriptionRepository interface {
+		List(ctx context.Context, filter repository.SubscriptionListFilter) ([]domain.Subscription, error)
+	}
+
+	AnalyticsPlanRepository interface {
+		FindAll(ctx context.Context) ([]domain.SubscriptionPlan, error)
+	}
+
+	AnalyticsRedemptionRepository interface {
+		ListWithSubscription(ctx context.Context) ([]domain.CouponRedemption, error)
+	}
+)
+
+// SubscriptionAnalytics computes the revenue and subscription reports of the
+// admin from the subscriptions and coupon redemptions tables.
+type SubscriptionAnalytics struct {
+	subRepo        AnalyticsSubscriptionRepository
+	planRepo       AnalyticsPlanRepository
+	redemptionRepo AnalyticsRedemptionRepository
+	pastDueGrace   time.Duration
+}
+
+func NewSubscriptionAnalytics(subRepo AnalyticsSubscriptionRepository, planRepo AnalyticsPlanRepository, redemptionRepo AnalyticsRedemptionRepository) *SubscriptionAnalytics {
+	return &SubscriptionAnalytics{
+		subRepo:        subRepo,
+		planRepo:       planRepo,
+		redemptionRepo: redemptionRepo,
+		pastDueGrace:   authentication.GetPastDueGrace(),
+	}
+}
+
+// AnalyticsPeriod is a range of whole months, in UTC.
+type AnalyticsPeriod struct {
+	From time.Time
+	To   time.Time
+}
+
+// NewAnalyticsPeriod parses the from and to months (YYYY-MM). Without them the
+// period is the last 12 months up to the current one.
+func NewAnalyticsPeriod(from, to string, now time.Time) (AnalyticsPeriod, error) {
+	current := monthStart(now)
+
+	period := AnalyticsPeriod{To: current}
+	if to != "" {
+		t, err := time.Parse(analyticsMonthLayout, to)
+		if err != nil {
+			return AnalyticsPeriod{}, domain.WrapInvalidInput(err, "to must be a month as YYYY-MM")
+		}
+		period.To = t
+	}
+	period.From = period.To.AddDate(0, -(analyticsMonths - 1), 0)
+	if from != "" {
+		f, err := time.Parse(analyticsMonthLayout, from)
+		if err != nil {
+			return AnalyticsPeriod{}, domain.WrapInvalidInput(err, "from must be a month as YYYY-MM")
+		}
+		period.From = f
+	}
+
+	if period.From.After(period.To) {
+		return AnalyticsPeriod{}, domain.WrapInvalidInput(domain.New("from is after to"), "analytics period")
+	}
+	if period.To.After(current) {
+		return AnalyticsPeriod{}, domain.WrapInvalidInput(domain.New("to is in the future"), "analytics period")
+	}
+	if len(period.months()) > analyticsMaxMonths {
+		return AnalyticsPeriod{}, domain.WrapInvalidInput(domain.New("period longer than 36 months"), "analytics period")
+	}
+	return period, nil
+}
+
+func (p AnalyticsPeriod) months() []time.Time {
+	var months []time.Time
+	for m := p.From; !m.After(p.To); m = m.AddDate(0, 1, 0) {
+		months = append(months, m)
+	}
+	return months
+}
+
+func (p AnalyticsPeriod) contains(t time.Time) bool {
+	m := monthStart(t)
+	return !m.Before(p.From) && !m.After(p.To)
+}
+
+// MRRRow is the current monthly recurring revenue of the renewing paid
+// subscriptions of a source and plan.
+type MRRRow struct {
+	Source        domain.SubscriptionSource `json:"source"`
+	PlanID        string                    `json:"plan_id"`
+	Currency      string                    `json:"currency"`
+	Subscriptions int                       `json:"subscriptions"`
+	MRR           float64                   `json:"mrr"`
+}
+
+// SubscriptionMovementRow counts subscribers, not subscriptions: moving from
+// one store to another is neither a churn nor a new subscriber.
+type SubscriptionMovementRow struct {
+	Month       string `json:"month"`
+	New         int    `json:"new"`
+	Reactivated int    `json:"reactivated"`
+	Churned     int    `json:"churned"`
+	ActiveAtEnd int    `json:"active_at_end"`
+}
+
+// CouponRevenueRow is the revenue of the subscriptions that came with a coupon.
+// The to-date amounts are estimated from the charges the subscription periods
+// imply.
+type CouponRevenueRow struct {
+	CouponID            string  `json:"coupon_id"`
+	Currency            string  `json:"currency"`
+	Redemptions         int     `json:"redemptions"`
+	ActiveSubscriptions int     `json:"active_subscriptions"`
+	MonthlyRevenue      float64 `json:"monthly_revenue"`
+	MonthlyDiscountCost float64 `json:"monthly_discount_cost"`
+	RevenueToDate       float64 `json:"revenue_to_date"`
+	DiscountCostToDate  float64 `json:"discount_cost_to_date"`
+}
+
+// TrialConversionRow groups the trials by the month they ended.
+type TrialConversionRow struct {
+	Month          string  `json:"month"`
+	TrialsEnded    int     `json:"trials_ended"`
+	Converted      int     `json:"converted"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// CohortRetentionRow tells how many subscribers of the cohort, the month of
+// their first subscription, were still paying at the end of a later month.
+type CohortRetentionRow struct {
+	Cohort           string  `json:"cohort"`
+	MonthsSinceStart int     `json:"months_since_start"`
+	Subscribers      int     `json:"subscribers"`
+	Retained         int     `json:"retained"`
+	RetentionRate    float64 `json:"retention_rate"`
+}
+
+type SubscriptionAnalyticsReport struct {
+	From        string                    `json:"from"`
+	To          string                    `json:"to"`
+	GeneratedAt time.Time                 `json:"generated_at"`
+	MRR         []MRRRow                  `json:"mrr"`
+	Movements   []SubscriptionMovementRow `json:"movements"`
+	Coupons     []CouponRevenueRow        `json:"coupons"`
+	Trials      []TrialConversionRow      `json:"trials"`
+	Cohorts     []CohortRetentionRow      `json:"cohorts"`
+}
+
+// paidInterval is a stretch of time a user had the paid plan; a nil end means
+// it still lasts.
+type paidInterval struct {
+	start time.Time
+	end   *time.Time
+}
+
+func (i paidInterval) covers(t time.Time) bool {
+	return !i.start.After(t) && (i.end == nil || i.end.After(t))
+}
+
+func (u *SubscriptionAnalytics) Report(ctx context.Context, period AnalyticsPeriod, now time.Time) (SubscriptionAnalyticsReport, error) {
+	subs, err := u.subRepo.List(ctx, repository.SubscriptionListFilter{})
+	if err != nil {
+		return SubscriptionAnalyticsReport{}, err
+	}
+	plans, err := u.planRepo.FindAll(ctx)
+	if err != nil {
+		return SubscriptionAnalyticsReport{}, err
+	}
+	redemptions, err := u.redemptionRepo.ListWithSubscription(ctx)
+	if err != nil {
+		return SubscriptionAnalyticsReport{}, err
+	}
+
+	plansByID := make(map[string]domain.SubscriptionPlan, len(plans))
+	for _, plan := range plans {
+		plansByID[plan.ID] = plan
+	}
+	intervals := paidIntervalsByUser(subs, now, u.pastDueGrace)
+
+	return SubscriptionAnalyticsReport{
+		From:        period.From.Format(analyticsMonthLayout),
+		To:          period.To.Format(analyticsMonthLayout),
+		GeneratedAt: now,
+		MRR:         mrrRows(subs, plansByID, redemptions),
+		Movements:   movementRows(intervals, period, now),
+		Coupons:     couponRows(subs, plansByID, redemptions, now, u.pastDueGrace),
+		Trials:      trialRows(subs, period, now),
+		Cohorts:     cohortRows(intervals, period, now),
+	}, nil
+}
+
+// Table gives a report as a header and rows of text, for the CSV export. It
+// returns false for an unknown report.
+func (r SubscriptionAnalyticsReport) Table(report string) ([]string, [][]string, bool) {
+	var rows [][]string
+	switch report {
+	case AnalyticsReportMRR:
+		for _, row := range r.MRR {
+			rows = append(rows, []string{string(row.Source), row.PlanID, row.Currency, strconv.Itoa(row.Subscriptions), formatAmount(row.MRR)})
+		}
+		return []string{"source", "plan_id", "currency", "subscriptions", "mrr"}, rows, true
+	case AnalyticsReportMovements:
+		for _, row := range r.Movements {
+			rows = append(rows, []string{row.Month, strconv.Itoa(row.New), strconv.Itoa(row.Reactivated), strconv.Itoa(row.Churned), strconv.Itoa(row.ActiveAtEnd)})
+		}
+		return []string{"month", "new", "reactivated", "churned", "active_at_end"}, rows, true
+	case AnalyticsReportCoupons:
+		for _, row := range r.Coupons {
+			rows = append(rows, []string{
+				row.CouponID, row.Currency, strconv.Itoa(row.Redemptions), strconv.Itoa(row.ActiveSubscriptions),
+				formatAmount(row.MonthlyRevenue), formatAmount(row.MonthlyDiscountCost),
+				formatAmount(row.RevenueToDate), formatAmount(row.DiscountCostToDate),
+			})
+		}
+		return []string{"coupon_id", "currency", "redemptions", "active_subscriptions", "monthly_revenue", "monthly_discount_cost", "revenue_to_date", "discount_cost_to_date"}, rows, true
+	case AnalyticsReportTrials:
+		for _, row := range r.Trials {
+			rows = append(rows, []string{row.Month, strconv.Itoa(row.TrialsEnded), strconv.Itoa(row.Converted), formatRate(row.ConversionRate)})
+		}
+		return []string{"month", "trials_ended", "converted", "conversion_rate"}, rows, true
+	case AnalyticsReportCohorts:
+		for _, row := range r.Cohorts {
+			rows = append(rows, []string{row.Cohort, strconv.Itoa(row.MonthsSinceStart), strconv.Itoa(row.Subscribers), strconv.Itoa(row.Retained), formatRate(row.RetentionRate)})
+		}
+		return []string{"cohort", "months_since_start", "subscribers", "retained", "retention_rate"}, rows, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// mrrRows sums the renewing paid subscriptions. Trials are not revenue yet, and
+// a subscription with an active coupon pays the locked price.
+func mrrRows(subs []domain.Subscription, plans map[string]domain.SubscriptionPlan, redemptions []domain.CouponRedemption) []MRRRow {
+	lockedPrices := map[uuid.UUID]float64{}
+	for _, red := range redemptions {
+		if red.Status == domain.CouponRedemptionActive {
+			lockedPrices[*red.SubscriptionID] = red.LockedPrice
+		}
+	}
+
+	type key struct {
+		source   domain.SubscriptionSource
+		planID   string
+		currency string
+	}
+	byKey := map[key]*MRRRow{}
+	var keys []key
+	for _, sub := range subs {
+		if sub.Status != domain.SubscriptionStatusActive {
+			continue
+		}
+		price := sub.CurrentPrice
+		if locked, ok := lockedPrices[sub.ID]; ok {
+			price = locked
+		}
+
+		k := key{sub.Source, sub.PlanID, sub.Currency}
+		row, ok := byKey[k]
+		if !ok {
+			row = &MRRRow{Source: sub.Source, PlanID: sub.PlanID, Currency: sub.Currency}
+			byKey[k] = row
+			keys = append(keys, k)
+		}
+		row.Subscriptions++
+		row.MRR += monthlyAmount(price, plans[sub.PlanID])
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.source != b.source {
+			return a.source < b.source
+		}
+		if a.planID != b.planID {
+			return a.planID < b.planID
+		}
+		return a.currency < b.currency
+	})
+	rows := make([]MRRRow, 0, len(keys))
+	for _, k := range keys {
+		row := *byKey[k]
+		row.MRR = roundAmount(row.MRR)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// movementRows counts, per month, the users whose first paid interval started
+// (new), whose later interval started (reactivated) and whose interval ended
+// (churned).
+func movementRows(intervals map[string][]paidInterval, period AnalyticsPeriod, now time.Time) []SubscriptionMovementRow {
+	byMonth := map[string]*SubscriptionMovementRow{}
+	months := period.months()
+	rows := make([]SubscriptionMovementRow, len(months))
+	for i, m := range months {
+		rows[i].Month = m.Format(analyticsMonthLayout)
+		byMonth[rows[i].Month] = &rows[i]
+	}
+
+	for _, userIntervals := range intervals {
+		for i, interval := range userIntervals {
+			if period.contains(interval.start) {
+				row := byMonth[interval.start.Format(analyticsMonthLayout)]
+				if i == 0 {
+					row.New++
+				} else {
+					row.Reactivated++
+				}
+			}
+			if interval.end != nil && period.contains(*interval.end) {
+				byMonth[interval.end.Format(analyticsMonthLayout)].Churned++
+			}
+		}
+		for i, m := range months {
+			if coveredAt(userIntervals, monthEndOrNow(m, now)) {
+				rows[i].ActiveAtEnd++
+			}
+		}
+	}
+	return rows
+}
+
+func couponRows(subs []domain.Subscription, plans map[string]domain.SubscriptionPlan, redemptions []domain.CouponRedemption, now time.Time, pastDueGrace time.Duration) []CouponRevenueRow {
+	subsByID := make(map[uuid.UUID]domain.Subscription, len(subs))
+	for _, sub := range subs {
+		subsByID[sub.ID] = sub
+	}
+
+	type key struct {
+		couponID string
+		currency string
+	}
+	byKey := map[key]*CouponRevenueRow{}
+	var keys []key
+	for _, red := range redemptions {
+		sub, ok := subsByID[*red.SubscriptionID]
+		if !ok {
+			continue
+		}
+		plan := plans[red.PlanID]
+
+		k := key{red.CouponID, plan.Currency}
+		row, ok := byKey[k]
+		if !ok {
+			row = &CouponRevenueRow{CouponID: red.CouponID, Currency: plan.Currency}
+			byKey[k] = row
+			keys = append(keys, k)
+		}
+		row.Redemptions++
+
+		discount := red.OriginalPrice - red.LockedPrice
+		if sub.Status == domain.SubscriptionStatusActive && red.Status == domain.CouponRedemptionActive {
+			row.ActiveSubscriptions++
+			row.MonthlyRevenue += monthlyAmount(red.LockedPrice, plan)
+			row.MonthlyDiscountCost += monthlyAmount(discount, plan)
+		}
+
+		charges := float64(chargesUntil(sub, plan, now, pastDueGrace))
+		row.RevenueToDate += red.LockedPrice * charges
+		row.DiscountCostToDate += discount * charges
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].couponID != keys[j].couponID {
+			return keys[i].couponID < keys[j].couponID
+		}
+		return keys[i].currency < keys[j].currency
+	})
+	rows := make([]CouponRevenueRow, 0, len(keys))
+	for _, k := range keys {
+		row := *byKey[k]
+		row.MonthlyRevenue = roundAmount(row.MonthlyRevenue)
+		row.MonthlyDiscountCost = roundAmount(row.MonthlyDiscountCost)
+		row.RevenueToDate = roundAmount(row.RevenueToDate)
+		row.DiscountCostToDate = roundAmount(row.DiscountCostToDate)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// trialRows counts the trials that ended in each month and how many of them
+// went on to a paid period.
+func trialRows(subs []domain.Subscription, period AnalyticsPeriod, now time.Time) []TrialConversionRow {
+	byMonth := map[string]*TrialConversionRow{}
+	months := period.months()
+	rows := make([]TrialConversionRow, len(months))
+	for i, m := range months {
+		rows[i].Month = m.Format(analyticsMonthLayout)
+		byMonth[rows[i].Month] = &rows[i]
+	}
+
+	for _, sub := range subs {
+		if sub.TrialEndsAt == nil || sub.TrialEndsAt.After(now) || !period.contains(*sub.TrialEndsAt) {
+			continue
+		}
+		row := byMonth[sub.TrialEndsAt.Format(analyticsMonthLayout)]
+		row.TrialsEnded++
+		if trialConverted(sub) {
+			row.Converted++
+		}
+	}
+
+	for i := range rows {
+		rows[i].ConversionRate = rate(rows[i].Converted, rows[i].TrialsEnded)
+	}
+	return rows
+}
+
+// trialConverted tells whether the subscription was charged after its trial. A
+// past due one was not: its first charge is the one failing.
+func trialConverted(sub domain.Subscription) bool {
+	switch sub.Status {
+	case domain.SubscriptionStatusActive:
+		return true
+	case domain.SubscriptionStatusCancelled, domain.SubscriptionStatusExpired, domain.SubscriptionStatusPaused:
+		return sub.CurrentPeriodEnd != nil && sub.CurrentPeriodEnd.After(*sub.TrialEndsAt)
+	default:
+		return false
+	}
+}
+
+// cohortRows follows each cohort of the period from its month to the current
+// one.
+func cohortRows(intervals map[string][]paidInterval, period AnalyticsPeriod, now time.Time) []CohortRetentionRow {
+	cohorts := map[string][][]paidInterval{}
+	for _, userIntervals := range intervals {
+		first := userIntervals[0].start
+		if !period.contains(first) {
+			continue
+		}
+		cohort := first.Format(analyticsMonthLayout)
+		cohorts[cohort] = append(cohorts[cohort], userIntervals)
+	}
+
+	var rows []CohortRetentionRow
+	current := monthStart(now)
+	for _, m := range period.months() {
+		members := cohorts[m.Format(analyticsMonthLayout)]
+		if len(members) == 0 {
+			continue
+		}
+		for offset, month := 0, m; !month.After(current); offset, month = offset+1, month.AddDate(0, 1, 0) {
+			at := monthEndOrNow(month, now)
+			retained := 0
+			for _, userIntervals := range members {
+				if coveredAt(userIntervals, at) {
+					retained++
+				}
+			}
+			rows = append(rows, CohortRetentionRow{
+				Cohort:           m.Format(analyticsMonthLayout),
+				MonthsSinceStart: offset,
+				Subscribers:      len(members),
+				Retained:         retained,
+				RetentionRate:    rate(retained, len(members)),
+			})
+		}
+	}
+	return rows
+}
+
+// paidIntervalsByUser merges the subscriptions of each user into the stretches
+// of time they had the paid plan, in order.
+func paidIntervalsByUser(subs []domain.Subscription, now time.Time, pastDueGrace time.Duration) map[string][]paidInterval {
+	byUser := map[string][]paidInterval{}
+	for _, sub := range subs {
+		if sub.Status == domain.SubscriptionStatusPending || sub.StartedAt.IsZero() {
+			continue
+		}
+		interval := paidInterval{start: sub.StartedAt.UTC()}
+		if end, ended := sub.EndedAt(now, pastDueGrace); ended {
+			end = end.UTC()
+			interval.end = &end
+		}
+		byUser[sub.UserID] = append(byUser[sub.UserID], interval)
+	}
+
+	for userID, intervals := range byUser {
+		sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+		merged := []paidInterval{intervals[0]}
+		for _, interval := range intervals[1:] {
+			last := &merged[len(merged)-1]
+			if last.end != nil && interval.start.After(*last.end) {
+				merged = append(merged, interval)
+				continue
+			}
+			if last.end != nil && (interval.end == nil || interval.end.After(*last.end)) {
+				last.end = interval.end
+			}
+		}
+		byUser[userID] = merged
+	}
+	return byUser
+}
+
+func coveredAt(intervals []paidInterval, t time.Time) bool {
+	for _, interval := range intervals {
+		if interval.covers(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// chargesUntil estimates how many times the subscription was charged: once per
+// plan period from the end of the trial until its access ended.
+func chargesUntil(sub domain.Subscription, plan domain.SubscriptionPlan, now time.Time, pastDueGrace time.Duration) int {
+	start := sub.StartedAt
+	if sub.TrialEndsAt != nil && sub.TrialEndsAt.After(start) {
+		start = *sub.TrialEndsAt
+	}
+	end := now
+	if ended, ok := sub.EndedAt(now, pastDueGrace); ok {
+		end = ended
+	}
+
+	charges := 0
+	for k := 0; addPlanPeriods(start, plan, k).Before(end); k++ {
+		charges++
+	}
+	return charges
+}
+
+// addPlanPeriods moves t by n billing periods of the plan; a plan without a
+// known frequency is taken as monthly.
+func addPlanPeriods(t time.Time, plan domain.SubscriptionPlan, n int) time.Time {
+	frequency := plan.Frequency
+	if frequency <= 0 {
+		frequency = 1
+	}
+	if plan.FrequencyType == "days" {
+		return t.AddDate(0, 0, n*frequency)
+	}
+	return t.AddDate(0, n*frequency, 0)
+}
+
+// monthlyAmount normalizes a price charged once per plan period to a month.
+func monthlyAmount(price float64, plan domain.SubscriptionPlan) float64 {
+	frequency := plan.Frequency
+	if frequency <= 0 {
+		return price
+	}
+	if plan.FrequencyType == "days" {
+		return price * 30 / float64(frequency)
+	}
+	return price / float64(frequency)
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// monthEndOrNow is the last instant of the month, or now for the current one.
+func monthEndOrNow(month, now time.Time) time.Time {
+	end := month.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	if end.After(now) {
+		return now
+	}
+	return end
+}
+
+func rate(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 10000
+}
+
+func roundAmount(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func formatRate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAnalyticsPeriod(t *testing.T) {
+	now := time.Date(2026, time.May, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		from, to     string
+		expectedFrom string
+		expectedTo   string
+		expectedErr  bool
+	}{
+		"should default to the last 12 months": {
+			expectedFrom: "2025-06",
+			expectedTo:   "2026-05",
+		},
+		"should count 12 months back from to": {
+			to:           "2026-03",
+			expectedFrom: "2025-04",
+			expectedTo:   "2026-03",
+		},
+		"should accept an explicit range": {
+			from:         "2026-01",
+			to:           "2026-02",
+			expectedFrom: "2026-01",
+			expectedTo:   "2026-02",
+		},
+		"should reject a malformed month":   {from: "2026-1-01", expectedErr: true},
+		"should reject from after to":       {from: "2026-04", to: "2026-03", expectedErr: true},
+		"should reject a future month":      {to: "2026-06", expectedErr: true},
+		"should reject more than 36 months": {from: "2023-01", expectedErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			period, err := NewAnalyticsPeriod(tt.from, tt.to, now)
+
+			if tt.expectedErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFrom, period.From.Format("2006-01"))
+			assert.Equal(t, tt.expectedTo, period.To.Format("2006-01"))
+		})
+	}
+}
+
+func TestSubscriptionAnalytics_Report(t *testing.T) {
+	now := time.Date(2026, time.May, 15, 12, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	plans := []domain.SubscriptionPlan{
+		{ID: "plus_monthly", Price: 9.90, Currency: "BRL", Frequency: 1, FrequencyType: "months"},
+		{ID: "plus_yearly", Price: 99, Currency: "BRL", Frequency: 12, FrequencyType: "months"},
+	}
+	couponSubID := uuid.New()
+	subs := []domain.Subscription{
+		// With a coupon since January.
+		{ID: couponSubID, UserID: "u1", Source: domain.SubscriptionSourceStripe, PlanID: "plus_monthly", Status: domain.SubscriptionStatusActive, CurrentPrice: 9.90, Currency: "BRL", StartedAt: day(time.January, 10)},
+		{ID: uuid.New(), UserID: "u2", Source: domain.SubscriptionSourceApple, PlanID: "plus_yearly", Status: domain.SubscriptionStatusActive, CurrentPrice: 99, Currency: "BRL", StartedAt: day(time.February, 1)},
+		// Churned in March, back in April on another store.
+		{ID: uuid.New(), UserID: "u3", Source: domain.SubscriptionSourceGoogle, PlanID: "plus_monthly", Status: domain.SubscriptionStatusCancelled, CurrentPrice: 9.90, Currency: "BRL", StartedAt: day(time.January, 5), CurrentPeriodEnd: ptr(day(time.March, 5))},
+		{ID: uuid.New(), UserID: "u3", Source: domain.SubscriptionSourceStripe, PlanID: "plus_monthly", Status: domain.SubscriptionStatusActive, CurrentPrice: 9.90, Currency: "BRL", StartedAt: day(time.April, 20)},
+		// Trial converted, and trial cancelled before its end.
+		{ID: uuid.New(), UserID: "u4", Source: domain.SubscriptionSourceStripe, PlanID: "plus_monthly", Status: domain.SubscriptionStatusActive, CurrentPrice: 9.90, Currency: "BRL", StartedAt: day(time.April, 3), TrialEndsAt: ptr(day(time.April, 10)), CurrentPeriodEnd: ptr(day(time.May, 10))},
+		{ID: uuid.New(), UserID: "u5", Source: domain.SubscriptionSourceStripe, PlanID: "plus_monthly", Status: domain.SubscriptionStatusCancelled, CurrentPrice: 9.90, Currency: "BRL", StartedAt: day(time.April, 1), TrialEndsAt: ptr(day(time.April, 8)), CurrentPeriodEnd: ptr(day(time.April, 8))},
+		{ID: uuid.New(), UserID: "u6", Source: domain.SubscriptionSourceMercadoPago, PlanID: "plus_monthly", Status: domain.SubscriptionStatusPending, StartedAt: day(time.May, 2)},
+	}
+	redemptions := []domain.CouponRedemption{
+		{ID: uuid.New(), UserID: "u1", CouponID: "launch", PlanID: "plus_monthly", SubscriptionID: &couponSubID, OriginalPrice: 9.90, LockedPrice: 6.93, Status: domain.CouponRedemptionActive},
+	}
+
+	newAnalytics := func() *SubscriptionAnalytics {
+		subRepo := new(MockSubscriptionRepo)
+		subRepo.On("List", mock.Anything, repository.SubscriptionListFilter{}).Return(subs, nil)
+		planRepo := new(MockAnalyticsPlanRepository)
+		planRepo.On("FindAll").Return(plans, nil)
+		redemptionRepo := new(MockAnalyticsRedemptionRepository)
+		redemptionRepo.On("ListWithSubscription").Return(redemptions, nil)
+		return NewSubscriptionAnalytics(subRepo, planRepo, redemptionRepo)
+	}
+	period := AnalyticsPeriod{From: day(time.January, 1), To: day(time.May, 1)}
+
+	report, err := newAnalytics().Report(context.Background(), period, now)
+	require.NoError(t, err)
+
+	t.Run("should sum the mrr of renewing subscriptions with coupon prices and yearly plans", func(t *testing.T) {
+		assert.Equal(t, []MRRRow{
+			{Source: domain.SubscriptionSourceApple, PlanID: "plus_yearly", Currency: "BRL", Subscriptions: 1, MRR: 8.25},
+			{Source: domain.SubscriptionSourceStripe, PlanID: "plus_monthly", Currency: "BRL", Subscriptions: 3, MRR: 26.73},
+		}, report.MRR)
+	})
+
+	t.Run("should count new, reactivated and churned subscribers per month", func(t *testing.T) {
+		assert.Equal(t, []SubscriptionMovementRow{
+			{Month: "2026-01", New: 2, ActiveAtEnd: 2},
+			{Month: "2026-02", New: 1, ActiveAtEnd: 3},
+			{Month: "2026-03", Churned: 1, ActiveAtEnd: 2},
+			{Month: "2026-04", New: 2, Reactivated: 1, Churned: 1, ActiveAtEnd: 4},
+			{Month: "2026-05", ActiveAtEnd: 4},
+		}, report.Movements)
+	})
+
+	t.Run("should attribute revenue and discount cost to coupons", func(t *testing.T) {
+		assert.Equal(t, []CouponRevenueRow{{
+			CouponID:            "launch",
+			Currency:            "BRL",
+			Redemptions:         1,
+			ActiveSubscriptions: 1,
+			MonthlyRevenue:      6.93,
+			MonthlyDiscountCost: 2.97,
+			RevenueToDate:       34.65,
+			DiscountCostToDate:  14.85,
+		}}, report.Coupons)
+	})
+
+	t.Run("should compute the trial conversion by the month the trial ended", func(t *testing.T) {
+		require.Len(t, report.Trials, 5)
+		assert.Equal(t, TrialConversionRow{Month: "2026-04", TrialsEnded: 2, Converted: 1, ConversionRate: 0.5}, report.Trials[3])
+		assert.Zero(t, report.Trials[0].TrialsEnded)
+	})
+
+	t.Run("should follow the retention of each cohort", func(t *testing.T) {
+		retained := map[string][]int{}
+		for _, row := range report.Cohorts {
+			retained[row.Cohort] = append(retained[row.Cohort], row.Retained)
+		}
+		assert.Equal(t, map[string][]int{
+			"2026-01": {2, 2, 1, 2, 2},
+			"2026-02": {1, 1, 1, 1},
+			"2026-04": {1, 1},
+		}, retained)
+		assert.Equal(t, CohortRetentionRow{Cohort: "2026-01", MonthsSinceStart: 2, Subscribers: 2, Retained: 1, RetentionRate: 0.5}, report.Cohorts[2])
+	})
+
+	t.Run("should give each report as a table", func(t *testing.T) {
+		header, rows, ok := report.Table(AnalyticsReportMRR)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"source", "plan_id", "currency", "subscriptions", "mrr"}, header)
+		assert.Equal(t, []string{"stripe", "plus_monthly", "BRL", "3", "26.73"}, rows[1])
+
+		_, _, ok = report.Table("unknown")
+		assert.False(t, ok)
+	})
+
+	t.Run("should return repository errors", func(t *testing.T) {
+		subRepo := new(MockSubscriptionRepo)
+		subRepo.On("List", mock.Anything, repository.SubscriptionListFilter{}).Return([]domain.Subscription(nil), errors.New("db down"))
+
+		_, err := NewSubscriptionAnalytics(subRepo, nil, nil).Report(context.Background(), period, now)
+
+		assert.Error(t, err)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"personal-finance/internal/infrastructure/gateway"
 	"personal-finance/internal/infrastructure/repository"
 	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
 
 	"github.com/google/uuid"
 	"github.com/stripe/stripe-go/v85"
@@ -29,6 +31,7 @@ type MercadoPagoSubscriptionGateway interface {
 type StripeSubscriptionGateway interface {
 	CreateCheckoutSession(ctx context.Context, p gateway.StripeCheckoutParams) (string, error)
 	ValidatePromotionCode(ctx context.Context, code string) (gateway.StripePromotionCode, bool, error)
+	CreatePromotionCode(ctx context.Context, couponID, code string) (gateway.StripePromotionCode, error)
 	CancelSubscription(ctx context.Context, subID string, atPeriodEnd bool) error
 	ConstructWebhookEvent(payload []byte, sigHeader string) (stripe.Event, error)
 }
@@ -63,13 +66,33 @@ type (
 	SubscriptionRepository interface {
 		Upsert(ctx context.Context, sub domain.Subscription) (domain.Subscription, error)
 		List(ctx context.Context, filter repository.SubscriptionListFilter) ([]domain.Subscription, error)
+		FindByUserID(ctx context.Context, userID string) ([]domain.Subscription, error)
 		FindActiveByUserAndSource(ctx context.Context, userID string, source domain.SubscriptionSource) (domain.Subscription, error)
 	}
 
+	// WebhookEnqueuer stores verified webhooks for asynchronous processing.
+	WebhookEnqueuer interface {
+		Enqueue(ctx context.Context, event domain.WebhookInboxEvent) error
+	}
+
+	// BillingNotifier warns users about their subscription billing.
+	BillingNotifier interface {
+		NotifyTrialEnding(ctx context.Context, userID string, endsAt time.Time) error
+		NotifyPaymentFailed(ctx context.Context, userID string, graceEndsAt time.Time) error
+	}
+
+	// ReferralQualifier rewards the referral of a user on their first paid
+	// subscription.
+	ReferralQualifier interface {
+		QualifyReferral(ctx context.Context, refereeID string) error
+	}
+
 	CouponCheckoutUseCase interface {
 		ApplyWebCheckout(ctx context.Context, userID string, plan domain.SubscriptionPlan, code string) (redemptionID uuid.UUID, err error)
 		Confirm(ctx context.Context, redemptionID, subscriptionID uuid.UUID) error
 		MarkCancelledBySubscription(ctx context.Context, subscriptionID uuid.UUID) error
+		FindCampaignCode(ctx context.Context, code string) (domain.CouponCampaignCode, domain.CouponCampaign, error)
+		LinkStripePromotionCode(ctx context.Context, code, promotionCodeID string) error
 	}
 
 	SubscriptionUseCase interface {
@@ -89,6 +112,10 @@ type Subscription struct {
 	planRepo         SubscriptionPlanRepository
 	subRepo          SubscriptionRepository
 	couponUseCase    CouponCheckoutUseCase
+	inbox            WebhookEnqueuer
+	notifier         BillingNotifier
+	referrals        ReferralQualifier
+	pastDueGrace     time.Duration
 	webhookSecret    string
 	rcWebhookAuthKey string
 }
@@ -108,11 +135,64 @@ func NewSubscription(
 		planRepo:         planRepo,
 		subRepo:          subRepo,
 		couponUseCase:    couponUseCase,
+		pastDueGrace:     authentication.GetPastDueGrace(),
 		webhookSecret:    os.Getenv("MERCADOPAGO_WEBHOOK_SECRET"),
 		rcWebhookAuthKey: os.Getenv("REVENUECAT_WEBHOOK_AUTH_KEY"),
 	}
 }
 
+// WithWebhookInbox makes the webhook handlers only verify and store the events;
+// the inbox processes them later through ProcessWebhookEvent. Without an inbox
+// the events are processed within the request.
+func (s *Subscription) WithWebhookInbox(inbox WebhookEnqueuer) *Subscription {
+	withInbox := *s
+	withInbox.inbox = inbox
+	return &withInbox
+}
+
+// WithBillingNotifier makes the webhooks push the trial end and payment failure
+// notices. Without a notifier they are not sent.
+func (s *Subscription) WithBillingNotifier(notifier BillingNotifier) *Subscription {
+	withNotifier := *s
+	withNotifier.notifier = notifier
+	return &withNotifier
+}
+
+// WithReferrals makes the webhooks qualify the referral of users whose paid
+// subscription is confirmed. Without it referrals are never rewarded.
+func (s *Subscription) WithReferrals(referrals ReferralQualifier) *Subscription {
+	withReferrals := *s
+	withReferrals.referrals = referrals
+	return &withReferrals
+}
+
+// ProcessWebhookEvent applies a webhook stored by the inbox. The signature was
+// verified when it was received.
+func (s *Subscription) ProcessWebhookEvent(ctx context.Context, event domain.WebhookInboxEvent) error {
+	switch event.Provider {
+	case domain.WebhookProviderMercadoPago:
+		var mpEvent WebhookEvent
+		if err := json.Unmarshal(event.Payload, &mpEvent); err != nil {
+			return fmt.Errorf("error unmarshaling webhook: %w", err)
+		}
+		return s.processMPEvent(ctx, mpEvent)
+	case domain.WebhookProviderStripe:
+		var stripeEvent stripe.Event
+		if err := json.Unmarshal(event.Payload, &stripeEvent); err != nil {
+			return fmt.Errorf("%w: error unmarshaling stripe event: %v", ErrStripeGateway, err)
+		}
+		return s.processStripeEvent(ctx, stripeEvent)
+	case domain.WebhookProviderRevenueCat:
+		var webhook RevenueCatWebhookEvent
+		if err := json.Unmarshal(event.Payload, &webhook); err != nil {
+			return fmt.Errorf("%w: error unmarshaling webhook: %v", ErrRevenueCatWebhook, err)
+		}
+		return s.processRevenueCatEvent(ctx, webhook.Event)
+	default:
+		return fmt.Errorf("unknown webhook provider %q", event.Provider)
+	}
+}
+
 type CheckoutResponse struct {
 	URL string `json:"checkout_url"`
 }
@@ -140,22 +220,28 @@ func (s *Subscription) CreateCheckout(ctx context.Context, planID, successURL, c
 		CancelURL:  cancelURL,
 	}
 
+	if plan.TrialDays > 0 {
+		eligible, err := s.trialEligible(ctx, auth.UserID)
+		if err != nil {
+			return "", err
+		}
+		if eligible {
+			params.TrialDays = plan.TrialDays
+		}
+	}
+
 	if couponCode != "" {
 		redemptionID, err := s.couponUseCase.ApplyWebCheckout(ctx, auth.UserID, plan, couponCode)
 		if err != nil {
 			return "", err
 		}
 
-		// The coupon code is the same string configured as the Stripe promotion_code.
-		pc, found, err := s.stripeGateway.ValidatePromotionCode(ctx, couponCode)
+		promotionCodeID, err := s.stripePromotionCodeID(ctx, couponCode)
 		if err != nil {
-			return "", fmt.Errorf("%w: %v", ErrStripeGateway, err)
-		}
-		if !found {
-			return "", domain.ErrCouponNotOnStripe
+			return "", err
 		}
 
-		params.PromotionCodeID = pc.ID
+		params.PromotionCodeID = promotionCodeID
 		if redemptionID != uuid.Nil {
 			params.RedemptionID = redemptionID.String()
 		}
@@ -168,6 +254,55 @@ func (s *Subscription) CreateCheckout(ctx context.Context, planID, successURL, c
 	return url, nil
 }
 
+// stripePromotionCodeID maps the coupon code to its Stripe promotion code. A
+// shared coupon code is the same string configured as the Stripe
+// promotion_code. A campaign code gets its own single-use promotion code on the
+// campaign's Stripe coupon, created on its first checkout.
+func (s *Subscription) stripePromotionCodeID(ctx context.Context, couponCode string) (string, error) {
+	code, campaign, err := s.couponUseCase.FindCampaignCode(ctx, couponCode)
+	if err != nil && !errors.Is(err, domain.ErrCouponCodeNotFound) {
+		return "", err
+	}
+	isCampaignCode := err == nil
+	if isCampaignCode && code.StripePromotionCodeID != "" {
+		return code.StripePromotionCodeID, nil
+	}
+
+	pc, found, err := s.stripeGateway.ValidatePromotionCode(ctx, couponCode)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrStripeGateway, err)
+	}
+	if !found {
+		if !isCampaignCode || campaign.StripeCouponID == "" {
+			return "", domain.ErrCouponNotOnStripe
+		}
+		pc, err = s.stripeGateway.CreatePromotionCode(ctx, campaign.StripeCouponID, couponCode)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrStripeGateway, err)
+		}
+	}
+
+	if isCampaignCode {
+		if err := s.couponUseCase.LinkStripePromotionCode(ctx, couponCode, pc.ID); err != nil {
+			return "", err
+		}
+	}
+	return pc.ID, nil
+}
+
+// trialEligible tells whether the user may start a free trial: only who never
+// had a subscription, from any store, gets one.
+func (s *Subscription) trialEligible(ctx context.Context, userID string) (bool, error) {
+	if s.subRepo == nil {
+		return true, nil
+	}
+	subs, err := s.subRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return len(subs) == 0, nil
+}
+
 type SubscriptionsSummary struct {
 	TotalSubscriptions      int                `json:"total_subscriptions"`
 	ActiveSubscriptions     int                `json:"active_subscriptions"`
@@ -233,6 +368,14 @@ func (s *Subscription) CreatePlan(ctx context.Context, plan domain.SubscriptionP
 	if !validFrequencyTypes[plan.FrequencyType] {
 		return ErrInvalidFrequencyType
 	}
+	if plan.TrialDays < 0 {
+		return domain.WrapInvalidInput(domain.New("trial_days must not be negative"), "create plan")
+	}
+	if plan.Entitlements != nil {
+		if err := plan.Entitlements.Validate(); err != nil {
+			return err
+		}
+	}
 	if plan.Currency == "" {
 		plan.Currency = "BRL"
 	}
@@ -240,8 +383,10 @@ func (s *Subscription) CreatePlan(ctx context.Context, plan domain.SubscriptionP
 }
 
 type WebhookEvent struct {
-	Action string `json:"action"`
-	Type   string `json:"type"`
+	// ID is the notification id, a number or a string depending on the topic.
+	ID     json.RawMessage `json:"id"`
+	Action string          `json:"action"`
+	Type   string          `json:"type"`
 	Data   struct {
 		ID string `json:"id"`
 	} `json:"data"`
@@ -334,6 +479,14 @@ func (s *Subscription) HandleWebhook(ctx context.Context, xSignature, xRequestId
 		return fmt.Errorf("error unmarshaling webhook: %w", err)
 	}
 
+	if s.inbox != nil {
+		eventID := strings.Trim(string(event.ID), "\"")
+		return s.inbox.Enqueue(ctx, domain.NewWebhookInboxEvent(domain.WebhookProviderMercadoPago, eventID, event.Type, body, time.Now()))
+	}
+	return s.processMPEvent(ctx, event)
+}
+
+func (s *Subscription) processMPEvent(ctx context.Context, event WebhookEvent) error {
 	// We only process subscription pre-approval events
 	if event.Type != "subscription_preapproval" && event.Type != "preapproval" {
 		return nil
@@ -364,12 +517,20 @@ func (s *Subscription) HandleWebhook(ctx context.Context, xSignature, xRequestId
 			return fmt.Errorf("error mirroring pending subscription to db: %w", err)
 		}
 		return nil
-	case "cancelled", "paused":
+	case "cancelled":
 		plan = authentication.PlanFree
 		if parsed := parseMPDate(subscription.NextPaymentDate); !parsed.IsZero() && parsed.After(time.Now()) {
 			plan = authentication.PlanPlus
 			expiresAt = parsed.Unix()
 		}
+	case "paused":
+		// Mercado Pago pauses a subscription whose charges keep failing; it is
+		// past due and keeps Plus until the grace ends.
+		plan = authentication.PlanFree
+		if graceEnd := s.mpGraceEnd(subscription); graceEnd.After(time.Now()) {
+			plan = authentication.PlanPlus
+			expiresAt = graceEnd.Unix()
+		}
 	default:
 		return nil
 	}
@@ -383,7 +544,7 @@ func (s *Subscription) HandleWebhook(ctx context.Context, xSignature, xRequestId
 	if redemptionID != uuid.Nil && upserted.ID != uuid.Nil {
 		switch subscription.Status {
 		case "authorized":
-			_ = s.couponUseCase.Confirm(ctx, redemptionID, upserted.ID)
+			s.confirmCoupon(ctx, uid, redemptionID, upserted.ID)
 		case "cancelled", "paused":
 			_ = s.couponUseCase.MarkCancelledBySubscription(ctx, upserted.ID)
 		}
@@ -394,6 +555,10 @@ func (s *Subscription) HandleWebhook(ctx context.Context, xSignature, xRequestId
 		return fmt.Errorf("error updating firebase subscription data: %w", err)
 	}
 
+	if subscription.Status == "authorized" {
+		s.qualifyReferral(ctx, uid)
+	}
+
 	return nil
 }
 
@@ -422,6 +587,11 @@ func (s *Subscription) upsertMPSubscription(ctx context.Context, userID, planID
 		now := time.Now()
 		cancelledAt = &now
 	}
+	var graceEndsAt *time.Time
+	if status == domain.SubscriptionStatusPastDue {
+		t := s.mpGraceEnd(mp)
+		graceEndsAt = &t
+	}
 
 	sub := domain.Subscription{
 		UserID:           userID,
@@ -434,11 +604,22 @@ func (s *Subscription) upsertMPSubscription(ctx context.Context, userID, planID
 		StartedAt:        startedAt,
 		CurrentPeriodEnd: currentPeriodEnd,
 		CancelledAt:      cancelledAt,
+		GraceEndsAt:      graceEndsAt,
 	}
 
 	return s.subRepo.Upsert(ctx, sub)
 }
 
+// mpGraceEnd is when a paused subscription loses Plus: the grace counts from
+// the payment date that was not charged.
+func (s *Subscription) mpGraceEnd(mp gateway.MPSubscription) time.Time {
+	since := time.Now()
+	if parsed := parseMPDate(mp.NextPaymentDate); !parsed.IsZero() {
+		since = parsed
+	}
+	return since.Add(s.pastDueGrace)
+}
+
 func mapMPStatusToDomain(mpStatus string) domain.SubscriptionStatus {
 	switch mpStatus {
 	case "pending":
@@ -448,7 +629,7 @@ func mapMPStatusToDomain(mpStatus string) domain.SubscriptionStatus {
 	case "cancelled":
 		return domain.SubscriptionStatusCancelled
 	case "paused":
-		return domain.SubscriptionStatusPaused
+		return domain.SubscriptionStatusPastDue
 	default:
 		return ""
 	}
@@ -478,11 +659,22 @@ func (s *Subscription) HandleStripeWebhook(ctx context.Context, payload []byte,
 		return fmt.Errorf("%w: %v", ErrInvalidWebhookSignature, err)
 	}
 
+	if s.inbox != nil {
+		return s.inbox.Enqueue(ctx, domain.NewWebhookInboxEvent(domain.WebhookProviderStripe, event.ID, string(event.Type), payload, time.Now()))
+	}
+	return s.processStripeEvent(ctx, event)
+}
+
+func (s *Subscription) processStripeEvent(ctx context.Context, event stripe.Event) error {
 	switch event.Type {
 	case "customer.subscription.created", "customer.subscription.updated":
 		return s.handleStripeSubscriptionUpsert(ctx, event)
 	case "customer.subscription.deleted":
 		return s.handleStripeSubscriptionDeleted(ctx, event)
+	case "customer.subscription.trial_will_end":
+		return s.handleStripeTrialWillEnd(ctx, event)
+	case "invoice.payment_failed":
+		return s.handleStripePaymentFailed(ctx, event)
 	default:
 		return nil
 	}
@@ -505,9 +697,22 @@ func (s *Subscription) handleStripeSubscriptionUpsert(ctx context.Context, event
 		return fmt.Errorf("error mirroring stripe subscription to db: %w", err)
 	}
 
-	active := sub.Status == stripe.SubscriptionStatusActive || sub.Status == stripe.SubscriptionStatusTrialing
-	if !active {
-		// past_due / unpaid / incomplete: mirror to DB but don't change the claim here.
+	switch sub.Status {
+	case stripe.SubscriptionStatusActive, stripe.SubscriptionStatusTrialing:
+	case stripe.SubscriptionStatusPastDue, stripe.SubscriptionStatusUnpaid:
+		// Keep Plus while Stripe retries the charge, then Free.
+		plan := authentication.PlanFree
+		var expiresAt int64
+		if graceEnd := s.stripeGraceEnd(sub); graceEnd.After(time.Now()) {
+			plan = authentication.PlanPlus
+			expiresAt = graceEnd.Unix()
+		}
+		if err := s.firebaseGateway.SetUserSubscription(ctx, userID, plan, sub.ID, authentication.SubscriptionSourceStripe, expiresAt); err != nil {
+			return fmt.Errorf("error updating firebase subscription data: %w", err)
+		}
+		return nil
+	default:
+		// incomplete / paused: mirror to DB but don't change the claim here.
 		return nil
 	}
 
@@ -521,7 +726,10 @@ func (s *Subscription) handleStripeSubscriptionUpsert(ctx context.Context, event
 	}
 
 	if redemptionID != uuid.Nil && upserted.ID != uuid.Nil {
-		_ = s.couponUseCase.Confirm(ctx, redemptionID, upserted.ID)
+		s.confirmCoupon(ctx, userID, redemptionID, upserted.ID)
+	}
+	if sub.Status == stripe.SubscriptionStatusActive {
+		s.qualifyReferral(ctx, userID)
 	}
 	return nil
 }
@@ -559,6 +767,100 @@ func (s *Subscription) handleStripeSubscriptionDeleted(ctx context.Context, even
 	return nil
 }
 
+func (s *Subscription) handleStripeTrialWillEnd(ctx context.Context, event stripe.Event) error {
+	var sub stripe.Subscription
+	if err := json.Unmarshal(event.Data.Raw, &sub); err != nil {
+		return fmt.Errorf("%w: error unmarshaling stripe subscription: %v", ErrStripeGateway, err)
+	}
+
+	userID := sub.Metadata["app_user_id"]
+	if userID == "" || sub.TrialEnd == 0 {
+		return nil
+	}
+	s.notify(ctx, userID, func(n BillingNotifier) error {
+		return n.NotifyTrialEnding(ctx, userID, time.Unix(sub.TrialEnd, 0))
+	})
+	return nil
+}
+
+// handleStripePaymentFailed warns about the first failed charge of a renewal,
+// including the first one after a trial. The subscription itself moves to
+// past_due through customer.subscription.updated.
+func (s *Subscription) handleStripePaymentFailed(ctx context.Context, event stripe.Event) error {
+	var invoice stripe.Invoice
+	if err := json.Unmarshal(event.Data.Raw, &invoice); err != nil {
+		return fmt.Errorf("%w: error unmarshaling stripe invoice: %v", ErrStripeGateway, err)
+	}
+
+	if invoice.BillingReason == stripe.InvoiceBillingReasonSubscriptionCreate || invoice.AttemptCount > 1 {
+		return nil
+	}
+	if invoice.Parent == nil || invoice.Parent.SubscriptionDetails == nil {
+		return nil
+	}
+	userID := invoice.Parent.SubscriptionDetails.Metadata["app_user_id"]
+	if userID == "" {
+		return nil
+	}
+
+	graceEnd := time.Unix(invoice.Created, 0).Add(s.pastDueGrace)
+	s.notify(ctx, userID, func(n BillingNotifier) error {
+		return n.NotifyPaymentFailed(ctx, userID, graceEnd)
+	})
+	return nil
+}
+
+// notify sends a billing notice when a notifier is configured. A failed push is
+// logged only, so the webhook is not retried over it.
+func (s *Subscription) notify(ctx context.Context, userID string, send func(BillingNotifier) error) {
+	if s.notifier == nil {
+		return
+	}
+	if err := send(s.notifier); err != nil {
+		log.ErrorContext(ctx, "error sending billing notification",
+			log.String("user_id", userID),
+			log.Err(err),
+		)
+	}
+}
+
+// confirmCoupon runs after the subscription was applied, so a failure (e.g. the
+// campaign code was redeemed by someone else first) is only logged and does not
+// make the provider retry the webhook.
+func (s *Subscription) confirmCoupon(ctx context.Context, userID string, redemptionID, subscriptionID uuid.UUID) {
+	if err := s.couponUseCase.Confirm(ctx, redemptionID, subscriptionID); err != nil {
+		log.ErrorContext(ctx, "error confirming coupon redemption",
+			log.String("user_id", userID),
+			log.String("redemption_id", redemptionID.String()),
+			log.Err(err),
+		)
+	}
+}
+
+// qualifyReferral runs after the subscription was applied, so a failure is only
+// logged and does not make the provider retry the webhook.
+func (s *Subscription) qualifyReferral(ctx context.Context, userID string) {
+	if s.referrals == nil {
+		return
+	}
+	if err := s.referrals.QualifyReferral(ctx, userID); err != nil {
+		log.ErrorContext(ctx, "error qualifying referral",
+			log.String("user_id", userID),
+			log.Err(err),
+		)
+	}
+}
+
+// stripeGraceEnd is when a past_due subscription loses Plus: the grace counts
+// from the start of the period whose invoice is unpaid.
+func (s *Subscription) stripeGraceEnd(sub stripe.Subscription) time.Time {
+	since := time.Now()
+	if sub.Items != nil && len(sub.Items.Data) > 0 && sub.Items.Data[0].CurrentPeriodStart > 0 {
+		since = time.Unix(sub.Items.Data[0].CurrentPeriodStart, 0)
+	}
+	return since.Add(s.pastDueGrace)
+}
+
 func (s *Subscription) upsertStripeSubscription(ctx context.Context, userID string, sub stripe.Subscription) (domain.Subscription, error) {
 	if s.subRepo == nil {
 		return domain.Subscription{}, nil
@@ -596,6 +898,16 @@ func (s *Subscription) upsertStripeSubscription(ctx context.Context, userID stri
 		now := time.Now()
 		cancelledAt = &now
 	}
+	var trialEndsAt *time.Time
+	if sub.TrialEnd > 0 {
+		t := time.Unix(sub.TrialEnd, 0)
+		trialEndsAt = &t
+	}
+	var graceEndsAt *time.Time
+	if status == domain.SubscriptionStatusPastDue {
+		t := s.stripeGraceEnd(sub)
+		graceEndsAt = &t
+	}
 
 	return s.subRepo.Upsert(ctx, domain.Subscription{
 		UserID:           userID,
@@ -608,13 +920,17 @@ func (s *Subscription) upsertStripeSubscription(ctx context.Context, userID stri
 		StartedAt:        startedAt,
 		CurrentPeriodEnd: currentPeriodEnd,
 		CancelledAt:      cancelledAt,
+		TrialEndsAt:      trialEndsAt,
+		GraceEndsAt:      graceEndsAt,
 	})
 }
 
 func mapStripeStatusToDomain(st stripe.SubscriptionStatus) domain.SubscriptionStatus {
 	switch st {
-	case stripe.SubscriptionStatusActive, stripe.SubscriptionStatusTrialing:
+	case stripe.SubscriptionStatusActive:
 		return domain.SubscriptionStatusActive
+	case stripe.SubscriptionStatusTrialing:
+		return domain.SubscriptionStatusTrialing
 	case stripe.SubscriptionStatusCanceled:
 		return domain.SubscriptionStatusCancelled
 	case stripe.SubscriptionStatusPastDue, stripe.SubscriptionStatusUnpaid:
@@ -642,6 +958,7 @@ type RevenueCatWebhookEvent struct {
 }
 
 type RevenueCatEventData struct {
+	ID                       string   `json:"id"`
 	Type                     string   `json:"type"`
 	AppUserID                string   `json:"app_user_id"`
 	EntitlementIDs           []string `json:"entitlement_ids"`
@@ -670,8 +987,13 @@ func (s *Subscription) HandleRevenueCatWebhook(ctx context.Context, authHeader s
 		return fmt.Errorf("%w: error unmarshaling webhook: %v", ErrRevenueCatWebhook, err)
 	}
 
-	event := webhook.Event
+	if s.inbox != nil {
+		return s.inbox.Enqueue(ctx, domain.NewWebhookInboxEvent(domain.WebhookProviderRevenueCat, webhook.Event.ID, webhook.Event.Type, body, time.Now()))
+	}
+	return s.processRevenueCatEvent(ctx, webhook.Event)
+}
 
+func (s *Subscription) processRevenueCatEvent(ctx context.Context, event RevenueCatEventData) error {
 	// Stripe (web) subscriptions are handled by our own /webhooks/stripe. RevenueCat receives
 	// Stripe events only for metrics; ignore them here to avoid double-processing.
 	if event.Store == "STRIPE" {
@@ -695,7 +1017,15 @@ func (s *Subscription) HandleRevenueCatWebhook(ctx context.Context, authHeader s
 		if event.ExpirationAtMs > 0 {
 			expiresAt = event.ExpirationAtMs / 1000 // Convert ms to seconds
 		}
-	case "EXPIRATION", "BILLING_ISSUE":
+	case "BILLING_ISSUE":
+		// Keep Plus while the store retries the charge, then Free.
+		if graceEnd := s.rcGraceEnd(event); graceEnd.After(time.Now()) {
+			plan = authentication.PlanPlus
+			expiresAt = graceEnd.Unix()
+		} else {
+			plan = authentication.PlanFree
+		}
+	case "EXPIRATION":
 		plan = authentication.PlanFree
 	default:
 		// Other event types (e.g., TEST, TRANSFER) - no action needed
@@ -711,15 +1041,34 @@ func (s *Subscription) HandleRevenueCatWebhook(ctx context.Context, authHeader s
 		return fmt.Errorf("%w: error updating firebase: %v", ErrRevenueCatWebhook, err)
 	}
 
+	if event.Type == "BILLING_ISSUE" && plan == authentication.PlanPlus {
+		s.notify(ctx, uid, func(n BillingNotifier) error {
+			return n.NotifyPaymentFailed(ctx, uid, time.Unix(expiresAt, 0))
+		})
+	}
+	if (event.Type == "INITIAL_PURCHASE" || event.Type == "RENEWAL") && event.PeriodType != "TRIAL" {
+		s.qualifyReferral(ctx, uid)
+	}
+
 	return nil
 }
 
+// rcGraceEnd is when a subscription with a billing issue loses Plus: the grace
+// counts from the expiration of the unpaid period.
+func (s *Subscription) rcGraceEnd(event RevenueCatEventData) time.Time {
+	since := time.Now()
+	if event.ExpirationAtMs > 0 {
+		since = time.UnixMilli(event.ExpirationAtMs)
+	}
+	return since.Add(s.pastDueGrace)
+}
+
 func (s *Subscription) upsertRCSubscription(ctx context.Context, userID string, event RevenueCatEventData) error {
 	if s.subRepo == nil {
 		return nil
 	}
 
-	status := mapRCStatusToDomain(event.Type)
+	status := mapRCStatusToDomain(event.Type, event.PeriodType)
 	if status == "" {
 		return nil
 	}
@@ -746,6 +1095,16 @@ func (s *Subscription) upsertRCSubscription(ctx context.Context, userID string,
 		cancelledAt = &now
 	}
 
+	var trialEndsAt *time.Time
+	if status == domain.SubscriptionStatusTrialing {
+		trialEndsAt = currentPeriodEnd
+	}
+	var graceEndsAt *time.Time
+	if status == domain.SubscriptionStatusPastDue {
+		t := s.rcGraceEnd(event)
+		graceEndsAt = &t
+	}
+
 	planID, err := s.planRepo.FindIDByStoreProduct(ctx, event.Store, event.ProductID)
 	if err != nil {
 		return fmt.Errorf("error resolving plan id for store product: %w", err)
@@ -763,15 +1122,20 @@ func (s *Subscription) upsertRCSubscription(ctx context.Context, userID string,
 		StartedAt:         startedAt,
 		CurrentPeriodEnd:  currentPeriodEnd,
 		CancelledAt:       cancelledAt,
+		TrialEndsAt:       trialEndsAt,
+		GraceEndsAt:       graceEndsAt,
 	}
 
 	_, err = s.subRepo.Upsert(ctx, sub)
 	return err
 }
 
-func mapRCStatusToDomain(eventType string) domain.SubscriptionStatus {
+func mapRCStatusToDomain(eventType, periodType string) domain.SubscriptionStatus {
 	switch eventType {
 	case "INITIAL_PURCHASE", "RENEWAL", "UNCANCELLATION":
+		if periodType == "TRIAL" {
+			return domain.SubscriptionStatusTrialing
+		}
 		return domain.SubscriptionStatusActive
 	case "CANCELLATION", "PRODUCT_CHANGE":
 		return domain.SubscriptionStatusCancelled
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -11,7 +12,9 @@ import (
 	"personal-finance/internal/infrastructure/gateway"
 	"personal-finance/internal/infrastructure/repository"
 	"personal-finance/internal/plataform/authentication"
+	"personal-finance/pkg/log"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stripe/stripe-go/v85"
@@ -31,6 +34,11 @@ func (m *MockStripeGateway) ValidatePromotionCode(ctx context.Context, code stri
 	return args.Get(0).(gateway.StripePromotionCode), args.Bool(1), args.Error(2)
 }
 
+func (m *MockStripeGateway) CreatePromotionCode(ctx context.Context, couponID, code string) (gateway.StripePromotionCode, error) {
+	args := m.Called(ctx, couponID, code)
+	return args.Get(0).(gateway.StripePromotionCode), args.Error(1)
+}
+
 func (m *MockStripeGateway) CancelSubscription(ctx context.Context, subID string, atPeriodEnd bool) error {
 	args := m.Called(ctx, subID, atPeriodEnd)
 	return args.Error(0)
@@ -98,6 +106,11 @@ func (m *MockSubscriptionRepo) List(ctx context.Context, filter repository.Subsc
 	return args.Get(0).([]domain.Subscription), args.Error(1)
 }
 
+func (m *MockSubscriptionRepo) FindByUserID(ctx context.Context, userID string) ([]domain.Subscription, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Subscription), args.Error(1)
+}
+
 func (m *MockSubscriptionRepo) FindActiveByUserAndSource(ctx context.Context, userID string, source domain.SubscriptionSource) (domain.Subscription, error) {
 	args := m.Called(ctx, userID, source)
 	return args.Get(0).(domain.Subscription), args.Error(1)
@@ -112,6 +125,11 @@ func (m *MockFirebaseSubGateway) SetUserSubscription(ctx context.Context, userID
 	return args.Error(0)
 }
 
+func (m *MockFirebaseSubGateway) GetUserClaims(ctx context.Context, userID string) (gateway.UserClaims, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(gateway.UserClaims), args.Error(1)
+}
+
 var monthlyPlan = domain.SubscriptionPlan{
 	ID:            "plus_monthly",
 	Name:          "Plus Mensal",
@@ -217,6 +235,89 @@ func TestSubscription_CreateCheckout(t *testing.T) {
 	}
 }
 
+func TestSubscription_CreateCheckout_CouponCode(t *testing.T) {
+	redemptionID := uuid.New()
+	campaign := domain.CouponCampaign{ID: "camp-1", CouponID: "c1", StripeCouponID: "stripe-c1"}
+
+	tests := map[string]struct {
+		code          string
+		mockSetup     func(*MockStripeGateway, *MockCouponCheckoutUseCase)
+		expectedPromo string
+		expectedError error
+	}{
+		"shared code uses the stripe promotion code with the same string": {
+			code: "PROMO",
+			mockSetup: func(sg *MockStripeGateway, cu *MockCouponCheckoutUseCase) {
+				cu.On("FindCampaignCode", "PROMO").Return(domain.CouponCampaignCode{}, domain.CouponCampaign{}, domain.ErrCouponCodeNotFound)
+				sg.On("ValidatePromotionCode", mock.Anything, "PROMO").Return(gateway.StripePromotionCode{ID: "promo_shared"}, true, nil)
+			},
+			expectedPromo: "promo_shared",
+		},
+		"shared code missing on stripe": {
+			code: "PROMO",
+			mockSetup: func(sg *MockStripeGateway, cu *MockCouponCheckoutUseCase) {
+				cu.On("FindCampaignCode", "PROMO").Return(domain.CouponCampaignCode{}, domain.CouponCampaign{}, domain.ErrCouponCodeNotFound)
+				sg.On("ValidatePromotionCode", mock.Anything, "PROMO").Return(gateway.StripePromotionCode{}, false, nil)
+			},
+			expectedError: domain.ErrCouponNotOnStripe,
+		},
+		"campaign code reuses its linked promotion code": {
+			code: "PARC-AAAA",
+			mockSetup: func(sg *MockStripeGateway, cu *MockCouponCheckoutUseCase) {
+				cu.On("FindCampaignCode", "PARC-AAAA").Return(domain.CouponCampaignCode{Code: "PARC-AAAA", StripePromotionCodeID: "promo_linked"}, campaign, nil)
+			},
+			expectedPromo: "promo_linked",
+		},
+		"campaign code creates and links a single-use promotion code": {
+			code: "PARC-AAAA",
+			mockSetup: func(sg *MockStripeGateway, cu *MockCouponCheckoutUseCase) {
+				cu.On("FindCampaignCode", "PARC-AAAA").Return(domain.CouponCampaignCode{Code: "PARC-AAAA"}, campaign, nil)
+				sg.On("ValidatePromotionCode", mock.Anything, "PARC-AAAA").Return(gateway.StripePromotionCode{}, false, nil)
+				sg.On("CreatePromotionCode", mock.Anything, "stripe-c1", "PARC-AAAA").Return(gateway.StripePromotionCode{ID: "promo_new"}, nil)
+				cu.On("LinkStripePromotionCode", "PARC-AAAA", "promo_new").Return(nil)
+			},
+			expectedPromo: "promo_new",
+		},
+		"campaign without stripe coupon": {
+			code: "PARC-AAAA",
+			mockSetup: func(sg *MockStripeGateway, cu *MockCouponCheckoutUseCase) {
+				cu.On("FindCampaignCode", "PARC-AAAA").Return(domain.CouponCampaignCode{Code: "PARC-AAAA"}, domain.CouponCampaign{ID: "camp-1"}, nil)
+				sg.On("ValidatePromotionCode", mock.Anything, "PARC-AAAA").Return(gateway.StripePromotionCode{}, false, nil)
+			},
+			expectedError: domain.ErrCouponNotOnStripe,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockStripe := new(MockStripeGateway)
+			mockPlan := new(MockSubscriptionPlanRepo)
+			mockCoupon := new(MockCouponCheckoutUseCase)
+			mockPlan.On("FindActiveByID", mock.Anything, "plus_monthly").Return(monthlyPlanStripe, nil)
+			mockCoupon.On("ApplyWebCheckout", "user-123", "plus_monthly", tc.code).Return(redemptionID, nil)
+			tc.mockSetup(mockStripe, mockCoupon)
+			if tc.expectedError == nil {
+				mockStripe.On("CreateCheckoutSession", mock.Anything, mock.MatchedBy(func(cp gateway.StripeCheckoutParams) bool {
+					return cp.PromotionCodeID == tc.expectedPromo && cp.RedemptionID == redemptionID.String()
+				})).Return("https://stripe.com/pay", nil)
+			}
+
+			s := NewSubscription(nil, mockStripe, nil, mockPlan, nil, mockCoupon)
+			ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-123"})
+
+			_, err := s.CreateCheckout(ctx, "plus_monthly", "https://app/success", "https://app/cancel", tc.code)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockStripe.AssertExpectations(t)
+			mockCoupon.AssertExpectations(t)
+		})
+	}
+}
+
 func TestSubscription_HandleWebhook(t *testing.T) {
 	// Note: We skip signature validation in these tests by not setting MERCADOPAGO_WEBHOOK_SECRET.
 
@@ -704,3 +805,403 @@ func TestSubscription_HandleRevenueCatWebhook_IgnoresStripe(t *testing.T) {
 
 	mockFS.AssertNotCalled(t, "SetUserSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
+
+func TestSubscription_WebhooksWithInbox(t *testing.T) {
+	t.Setenv("REVENUECAT_WEBHOOK_AUTH_KEY", "test-key")
+
+	t.Run("should store verified webhooks keyed by the provider event id", func(t *testing.T) {
+		stripeEvent := stripe.Event{ID: "evt_123", Type: "customer.subscription.updated"}
+		mockStripe := new(MockStripeGateway)
+		mockStripe.On("ConstructWebhookEvent", mock.Anything, "sig").Return(stripeEvent, nil)
+		mockFS := new(MockFirebaseSubGateway)
+		inbox := new(MockWebhookEnqueuer)
+		inbox.On("Enqueue", mock.MatchedBy(func(e domain.WebhookInboxEvent) bool {
+			return e.Provider == domain.WebhookProviderStripe && e.EventID == "evt_123" && e.EventType == "customer.subscription.updated"
+		})).Return(nil).Once()
+		inbox.On("Enqueue", mock.MatchedBy(func(e domain.WebhookInboxEvent) bool {
+			return e.Provider == domain.WebhookProviderMercadoPago && e.EventID == "12345" && e.EventType == "subscription_preapproval"
+		})).Return(nil).Once()
+		inbox.On("Enqueue", mock.MatchedBy(func(e domain.WebhookInboxEvent) bool {
+			return e.Provider == domain.WebhookProviderRevenueCat && e.EventID == "rc-evt-1" && e.EventType == "RENEWAL"
+		})).Return(nil).Once()
+
+		s := NewSubscription(new(MockMPGateway), mockStripe, mockFS, new(MockSubscriptionPlanRepo), nil, nil).WithWebhookInbox(inbox)
+
+		assert.NoError(t, s.HandleStripeWebhook(context.Background(), []byte(`{"id":"evt_123"}`), "sig"))
+		assert.NoError(t, s.HandleWebhook(context.Background(), "", "", []byte(`{"id":12345,"type":"subscription_preapproval","data":{"id":"sub-123"}}`)))
+		assert.NoError(t, s.HandleRevenueCatWebhook(context.Background(), "Bearer test-key", []byte(`{"event":{"id":"rc-evt-1","type":"RENEWAL","app_user_id":"user-123"}}`)))
+
+		inbox.AssertExpectations(t)
+		mockFS.AssertNotCalled(t, "SetUserSubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not store webhooks that fail verification", func(t *testing.T) {
+		inbox := new(MockWebhookEnqueuer)
+		s := NewSubscription(nil, nil, nil, new(MockSubscriptionPlanRepo), nil, nil).WithWebhookInbox(inbox)
+
+		err := s.HandleRevenueCatWebhook(context.Background(), "Bearer wrong", []byte(`{"event":{"id":"rc-evt-1"}}`))
+
+		assert.ErrorIs(t, err, ErrRevenueCatWebhook)
+		inbox.AssertNotCalled(t, "Enqueue", mock.Anything)
+	})
+
+	t.Run("should process a stored stripe event", func(t *testing.T) {
+		payload := `{"id":"evt_123","type":"customer.subscription.updated","data":{"object":{"id":"sub_123","status":"active","metadata":{"app_user_id":"user-123"}}}}`
+		mockFS := new(MockFirebaseSubGateway)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanPlus, "sub_123", authentication.SubscriptionSourceStripe, int64(0)).Return(nil)
+
+		s := NewSubscription(nil, nil, mockFS, new(MockSubscriptionPlanRepo), nil, nil)
+
+		err := s.ProcessWebhookEvent(context.Background(), domain.NewWebhookInboxEvent(domain.WebhookProviderStripe, "evt_123", "customer.subscription.updated", []byte(payload), time.Now()))
+
+		assert.NoError(t, err)
+		mockFS.AssertExpectations(t)
+	})
+}
+
+func TestSubscription_CreateCheckout_Trial(t *testing.T) {
+	trialPlan := monthlyPlanStripe
+	trialPlan.TrialDays = 7
+	ctx := authentication.ContextWithAuth(context.Background(), authentication.AuthContext{UserID: "user-123"})
+
+	tests := map[string]struct {
+		previousSubs      []domain.Subscription
+		expectedTrialDays int
+	}{
+		"should start a trial on the first subscription": {
+			previousSubs:      []domain.Subscription{},
+			expectedTrialDays: 7,
+		},
+		"should not start a trial for a returning subscriber": {
+			previousSubs:      []domain.Subscription{{UserID: "user-123", Source: domain.SubscriptionSourceApple, Status: domain.SubscriptionStatusExpired}},
+			expectedTrialDays: 0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockStripe := new(MockStripeGateway)
+			mockPlan := new(MockSubscriptionPlanRepo)
+			mockSub := new(MockSubscriptionRepo)
+			mockPlan.On("FindActiveByID", mock.Anything, "plus_monthly").Return(trialPlan, nil)
+			mockSub.On("FindByUserID", mock.Anything, "user-123").Return(tc.previousSubs, nil)
+			mockStripe.On("CreateCheckoutSession", mock.Anything, mock.MatchedBy(func(cp gateway.StripeCheckoutParams) bool {
+				return cp.PriceID == "price_123" && cp.TrialDays == tc.expectedTrialDays
+			})).Return("https://stripe.com/pay", nil)
+
+			s := NewSubscription(nil, mockStripe, nil, mockPlan, mockSub, nil)
+
+			url, err := s.CreateCheckout(ctx, "plus_monthly", "", "", "")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "https://stripe.com/pay", url)
+			mockStripe.AssertExpectations(t)
+			mockSub.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscription_HandleStripeWebhook_TrialsAndPastDue(t *testing.T) {
+	log.Initialize()
+	t.Setenv("SUBSCRIPTION_PAST_DUE_GRACE_DAYS", "3")
+
+	periodStart := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	graceEnd := periodStart.Add(3 * 24 * time.Hour)
+	trialEnd := time.Now().Add(3 * 24 * time.Hour).Truncate(time.Second)
+
+	stripeEvent := func(eventType, object string) stripe.Event {
+		return stripe.Event{Type: stripe.EventType(eventType), Data: &stripe.EventData{Raw: json.RawMessage(object)}}
+	}
+
+	t.Run("should keep plus until the grace ends while past due", func(t *testing.T) {
+		subJSON := fmt.Sprintf(`{"id":"sub_123","status":"past_due","metadata":{"app_user_id":"user-123"},"created":1700000000,"items":{"data":[{"current_period_start":%d,"current_period_end":%d,"price":{"id":"price_123","unit_amount":990,"currency":"brl"}}]}}`,
+			periodStart.Unix(), periodStart.Add(30*24*time.Hour).Unix())
+		mockFS := new(MockFirebaseSubGateway)
+		mockSub := new(MockSubscriptionRepo)
+		mockPlan := new(MockSubscriptionPlanRepo)
+		mockPlan.On("FindIDByStoreProduct", mock.Anything, "STRIPE", "price_123").Return("plus_monthly", nil)
+		mockSub.On("Upsert", mock.Anything, mock.MatchedBy(func(sub domain.Subscription) bool {
+			return sub.Status == domain.SubscriptionStatusPastDue &&
+				sub.GraceEndsAt != nil && sub.GraceEndsAt.Equal(graceEnd)
+		})).Return(domain.Subscription{}, nil)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanPlus, "sub_123", authentication.SubscriptionSourceStripe, graceEnd.Unix()).Return(nil)
+
+		s := NewSubscription(nil, nil, mockFS, mockPlan, mockSub, nil)
+
+		err := s.processStripeEvent(context.Background(), stripeEvent("customer.subscription.updated", subJSON))
+
+		assert.NoError(t, err)
+		mockSub.AssertExpectations(t)
+		mockFS.AssertExpectations(t)
+	})
+
+	t.Run("should downgrade to free when the grace is over", func(t *testing.T) {
+		oldStart := time.Now().Add(-10 * 24 * time.Hour)
+		subJSON := fmt.Sprintf(`{"id":"sub_123","status":"unpaid","metadata":{"app_user_id":"user-123"},"items":{"data":[{"current_period_start":%d}]}}`, oldStart.Unix())
+		mockFS := new(MockFirebaseSubGateway)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanFree, "sub_123", authentication.SubscriptionSourceStripe, int64(0)).Return(nil)
+
+		s := NewSubscription(nil, nil, mockFS, new(MockSubscriptionPlanRepo), nil, nil)
+
+		err := s.processStripeEvent(context.Background(), stripeEvent("customer.subscription.updated", subJSON))
+
+		assert.NoError(t, err)
+		mockFS.AssertExpectations(t)
+	})
+
+	t.Run("should mirror a trial and grant plus while it renews", func(t *testing.T) {
+		subJSON := fmt.Sprintf(`{"id":"sub_123","status":"trialing","trial_end":%d,"metadata":{"app_user_id":"user-123"},"items":{"data":[{"price":{"id":"price_123","unit_amount":990,"currency":"brl"}}]}}`, trialEnd.Unix())
+		mockFS := new(MockFirebaseSubGateway)
+		mockSub := new(MockSubscriptionRepo)
+		mockPlan := new(MockSubscriptionPlanRepo)
+		mockPlan.On("FindIDByStoreProduct", mock.Anything, "STRIPE", "price_123").Return("plus_monthly", nil)
+		mockSub.On("Upsert", mock.Anything, mock.MatchedBy(func(sub domain.Subscription) bool {
+			return sub.Status == domain.SubscriptionStatusTrialing &&
+				sub.TrialEndsAt != nil && sub.TrialEndsAt.Equal(trialEnd) &&
+				sub.GraceEndsAt == nil
+		})).Return(domain.Subscription{}, nil)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanPlus, "sub_123", authentication.SubscriptionSourceStripe, int64(0)).Return(nil)
+
+		s := NewSubscription(nil, nil, mockFS, mockPlan, mockSub, nil)
+
+		err := s.processStripeEvent(context.Background(), stripeEvent("customer.subscription.created", subJSON))
+
+		assert.NoError(t, err)
+		mockSub.AssertExpectations(t)
+		mockFS.AssertExpectations(t)
+	})
+
+	t.Run("should notify the trial end", func(t *testing.T) {
+		subJSON := fmt.Sprintf(`{"id":"sub_123","status":"trialing","trial_end":%d,"metadata":{"app_user_id":"user-123"}}`, trialEnd.Unix())
+		notifier := new(MockBillingNotifier)
+		notifier.On("NotifyTrialEnding", "user-123", mock.MatchedBy(func(endsAt time.Time) bool {
+			return endsAt.Equal(trialEnd)
+		})).Return(nil)
+
+		s := NewSubscription(nil, nil, nil, nil, nil, nil).WithBillingNotifier(notifier)
+
+		err := s.processStripeEvent(context.Background(), stripeEvent("customer.subscription.trial_will_end", subJSON))
+
+		assert.NoError(t, err)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("should notify only the first failed charge of a renewal", func(t *testing.T) {
+		invoiceJSON := func(reason string, attempt int) string {
+			return fmt.Sprintf(`{"id":"in_123","created":%d,"billing_reason":%q,"attempt_count":%d,"parent":{"type":"subscription_details","subscription_details":{"metadata":{"app_user_id":"user-123"}}}}`,
+				periodStart.Unix(), reason, attempt)
+		}
+		notifier := new(MockBillingNotifier)
+		notifier.On("NotifyPaymentFailed", "user-123", mock.MatchedBy(func(until time.Time) bool {
+			return until.Equal(graceEnd)
+		})).Return(errors.New("push error")).Once()
+
+		s := NewSubscription(nil, nil, nil, nil, nil, nil).WithBillingNotifier(notifier)
+
+		assert.NoError(t, s.processStripeEvent(context.Background(), stripeEvent("invoice.payment_failed", invoiceJSON("subscription_cycle", 1))))
+		assert.NoError(t, s.processStripeEvent(context.Background(), stripeEvent("invoice.payment_failed", invoiceJSON("subscription_cycle", 2))))
+		assert.NoError(t, s.processStripeEvent(context.Background(), stripeEvent("invoice.payment_failed", invoiceJSON("subscription_create", 1))))
+
+		notifier.AssertExpectations(t)
+	})
+}
+
+func TestSubscription_HandleWebhook_PausedKeepsGrace(t *testing.T) {
+	t.Setenv("SUBSCRIPTION_PAST_DUE_GRACE_DAYS", "3")
+
+	body := []byte(`{"action":"updated","type":"subscription_preapproval","data":{"id":"sub-123"}}`)
+	pausedSub := func(nextPayment time.Time) gateway.MPSubscription {
+		return gateway.MPSubscription{
+			ID:                "sub-123",
+			Status:            "paused",
+			ExternalReference: "user-123",
+			NextPaymentDate:   nextPayment.Format(time.RFC3339),
+		}
+	}
+
+	t.Run("should keep plus until the grace ends while past due", func(t *testing.T) {
+		nextPayment := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		graceEnd := nextPayment.Add(3 * 24 * time.Hour)
+		mockMP := new(MockMPGateway)
+		mockFS := new(MockFirebaseSubGateway)
+		mockSub := new(MockSubscriptionRepo)
+		mockMP.On("GetSubscription", mock.Anything, "sub-123").Return(pausedSub(nextPayment), nil)
+		mockSub.On("Upsert", mock.Anything, mock.MatchedBy(func(sub domain.Subscription) bool {
+			return sub.Status == domain.SubscriptionStatusPastDue &&
+				sub.GraceEndsAt != nil && sub.GraceEndsAt.Equal(graceEnd)
+		})).Return(domain.Subscription{}, nil)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanPlus, "", authentication.SubscriptionSourceMP, graceEnd.Unix()).Return(nil)
+
+		s := NewSubscription(mockMP, nil, mockFS, new(MockSubscriptionPlanRepo), mockSub, nil)
+
+		err := s.HandleWebhook(context.Background(), "", "", body)
+
+		assert.NoError(t, err)
+		mockSub.AssertExpectations(t)
+		mockFS.AssertExpectations(t)
+	})
+
+	t.Run("should downgrade to free when the grace is over", func(t *testing.T) {
+		mockMP := new(MockMPGateway)
+		mockFS := new(MockFirebaseSubGateway)
+		mockMP.On("GetSubscription", mock.Anything, "sub-123").Return(pausedSub(time.Now().Add(-4*24*time.Hour)), nil)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanFree, "", authentication.SubscriptionSourceMP, int64(0)).Return(nil)
+
+		s := NewSubscription(mockMP, nil, mockFS, new(MockSubscriptionPlanRepo), nil, nil)
+
+		err := s.HandleWebhook(context.Background(), "", "", body)
+
+		assert.NoError(t, err)
+		mockFS.AssertExpectations(t)
+	})
+}
+
+func TestSubscription_HandleRevenueCatWebhook_TrialsAndBillingIssues(t *testing.T) {
+	log.Initialize()
+	t.Setenv("REVENUECAT_WEBHOOK_AUTH_KEY", "test-key")
+
+	expiration := time.Now().Add(-24 * time.Hour).Truncate(time.Millisecond)
+	graceEnd := expiration.Add(authentication.GetPastDueGrace())
+
+	rcBody := func(eventType, periodType string, expiration time.Time) []byte {
+		return []byte(fmt.Sprintf(`{"event":{"type":%q,"period_type":%q,"app_user_id":"user-123","store":"APP_STORE","original_transaction_id":"orig-tx-1","product_id":"plus_monthly","expiration_at_ms":%d}}`,
+			eventType, periodType, expiration.UnixMilli()))
+	}
+
+	t.Run("should keep plus during the grace and notify a billing issue", func(t *testing.T) {
+		mockFS := new(MockFirebaseSubGateway)
+		mockSub := new(MockSubscriptionRepo)
+		mockPlan := new(MockSubscriptionPlanRepo)
+		notifier := new(MockBillingNotifier)
+		mockPlan.On("FindIDByStoreProduct", mock.Anything, "APP_STORE", "plus_monthly").Return("plus_monthly", nil)
+		mockSub.On("Upsert", mock.Anything, mock.MatchedBy(func(sub domain.Subscription) bool {
+			return sub.Status == domain.SubscriptionStatusPastDue &&
+				sub.GraceEndsAt != nil && sub.GraceEndsAt.Equal(graceEnd)
+		})).Return(domain.Subscription{}, nil)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanPlus, "", authentication.SubscriptionSourceIAP, graceEnd.Unix()).Return(nil)
+		notifier.On("NotifyPaymentFailed", "user-123", time.Unix(graceEnd.Unix(), 0)).Return(nil)
+
+		s := NewSubscription(nil, nil, mockFS, mockPlan, mockSub, nil).WithBillingNotifier(notifier)
+
+		err := s.HandleRevenueCatWebhook(context.Background(), "Bearer test-key", rcBody("BILLING_ISSUE", "NORMAL", expiration))
+
+		assert.NoError(t, err)
+		mockSub.AssertExpectations(t)
+		mockFS.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("should downgrade a billing issue past the grace without notifying", func(t *testing.T) {
+		mockFS := new(MockFirebaseSubGateway)
+		notifier := new(MockBillingNotifier)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanFree, "", authentication.SubscriptionSourceIAP, int64(0)).Return(nil)
+
+		s := NewSubscription(nil, nil, mockFS, new(MockSubscriptionPlanRepo), nil, nil).WithBillingNotifier(notifier)
+
+		err := s.HandleRevenueCatWebhook(context.Background(), "Bearer test-key", rcBody("BILLING_ISSUE", "NORMAL", time.Now().Add(-30*24*time.Hour)))
+
+		assert.NoError(t, err)
+		mockFS.AssertExpectations(t)
+		notifier.AssertNotCalled(t, "NotifyPaymentFailed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should mirror a store trial", func(t *testing.T) {
+		trialEnd := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Millisecond)
+		mockFS := new(MockFirebaseSubGateway)
+		mockSub := new(MockSubscriptionRepo)
+		mockPlan := new(MockSubscriptionPlanRepo)
+		mockPlan.On("FindIDByStoreProduct", mock.Anything, "APP_STORE", "plus_monthly").Return("plus_monthly", nil)
+		mockSub.On("Upsert", mock.Anything, mock.MatchedBy(func(sub domain.Subscription) bool {
+			return sub.Status == domain.SubscriptionStatusTrialing &&
+				sub.TrialEndsAt != nil && sub.TrialEndsAt.Equal(trialEnd)
+		})).Return(domain.Subscription{}, nil)
+		mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanPlus, "", authentication.SubscriptionSourceIAP, int64(0)).Return(nil)
+
+		s := NewSubscription(nil, nil, mockFS, mockPlan, mockSub, nil)
+
+		err := s.HandleRevenueCatWebhook(context.Background(), "Bearer test-key", rcBody("INITIAL_PURCHASE", "TRIAL", trialEnd))
+
+		assert.NoError(t, err)
+		mockSub.AssertExpectations(t)
+		mockFS.AssertExpectations(t)
+	})
+}
+
+func TestSubscription_Webhooks_QualifyReferral(t *testing.T) {
+	log.Initialize()
+	t.Setenv("REVENUECAT_WEBHOOK_AUTH_KEY", "test-key")
+
+	rcBody := func(eventType, periodType string) []byte {
+		return []byte(fmt.Sprintf(`{"event":{"type":%q,"period_type":%q,"app_user_id":"user-123","store":"APP_STORE","product_id":"plus_monthly"}}`, eventType, periodType))
+	}
+
+	tests := map[string]struct {
+		body            []byte
+		qualifyErr      error
+		expectedQualify bool
+	}{
+		"should qualify on a paid INITIAL_PURCHASE": {
+			body:            rcBody("INITIAL_PURCHASE", "NORMAL"),
+			expectedQualify: true,
+		},
+		"should qualify on RENEWAL": {
+			body:            rcBody("RENEWAL", "NORMAL"),
+			expectedQualify: true,
+		},
+		"should not fail the webhook when the qualification fails": {
+			body:            rcBody("INITIAL_PURCHASE", "NORMAL"),
+			qualifyErr:      errors.New("db down"),
+			expectedQualify: true,
+		},
+		"should not qualify a trial": {
+			body: rcBody("INITIAL_PURCHASE", "TRIAL"),
+		},
+		"should not qualify a cancellation": {
+			body: rcBody("CANCELLATION", "NORMAL"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockFS := new(MockFirebaseSubGateway)
+			mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanPlus, "", authentication.SubscriptionSourceIAP, mock.Anything).Return(nil)
+			referrals := new(MockReferralQualifier)
+			referrals.On("QualifyReferral", "user-123").Return(tt.qualifyErr)
+
+			s := NewSubscription(nil, nil, mockFS, new(MockSubscriptionPlanRepo), nil, nil).WithReferrals(referrals)
+
+			err := s.HandleRevenueCatWebhook(context.Background(), "Bearer test-key", tt.body)
+
+			assert.NoError(t, err)
+			if tt.expectedQualify {
+				referrals.AssertCalled(t, "QualifyReferral", "user-123")
+			} else {
+				referrals.AssertNotCalled(t, "QualifyReferral", mock.Anything)
+			}
+		})
+	}
+
+	for _, status := range []string{"active", "trialing"} {
+		t.Run("stripe "+status, func(t *testing.T) {
+			subJSON := fmt.Sprintf(`{"id":"sub_123","status":%q,"metadata":{"app_user_id":"user-123"},"items":{"data":[{"price":{"id":"price_123"}}]}}`, status)
+			mockStripe := new(MockStripeGateway)
+			mockStripe.On("ConstructWebhookEvent", mock.Anything, "sig").Return(stripe.Event{
+				Type: "customer.subscription.updated",
+				Data: &stripe.EventData{Raw: json.RawMessage(subJSON)},
+			}, nil)
+			mockFS := new(MockFirebaseSubGateway)
+			mockFS.On("SetUserSubscription", mock.Anything, "user-123", authentication.PlanPlus, "sub_123", authentication.SubscriptionSourceStripe, int64(0)).Return(nil)
+			referrals := new(MockReferralQualifier)
+			referrals.On("QualifyReferral", "user-123").Return(nil)
+
+			s := NewSubscription(nil, mockStripe, mockFS, new(MockSubscriptionPlanRepo), nil, nil).WithReferrals(referrals)
+
+			assert.NoError(t, s.HandleStripeWebhook(context.Background(), []byte("{}"), "sig"))
+			if status == "active" {
+				referrals.AssertCalled(t, "QualifyReferral", "user-123")
+			} else {
+				referrals.AssertNotCalled(t, "QualifyReferral", mock.Anything)
+			}
+		})
+	}
+}
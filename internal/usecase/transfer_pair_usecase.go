@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (u *Transfer) FindByPairID(ctx context.Context, pairID uuid.UUID) (TransferOutput, error) {
+	origin, destination, err := u.findPair(ctx, pairID)
+	if err != nil {
+		return TransferOutput{}, err
+	}
+
+	return TransferOutput{
+		PairID:              pairID,
+		OriginMovement:      origin,
+		DestinationMovement: destination,
+	}, nil
+}
+
+// Update altera as duas pontas da transferência e os saldos das duas carteiras na
+// mesma transação. Se o par for uma ocorrência de transferência recorrente, só ela muda.
+func (u *Transfer) Update(ctx context.Context, pairID uuid.UUID, input TransferUpdateInput) (TransferOutput, error) {
+	if err := validateTransferUpdate(input); err != nil {
+		return TransferOutput{}, err
+	}
+
+	origin, destination, err := u.findPair(ctx, pairID)
+	if err != nil {
+		return TransferOutput{}, err
+	}
+
+	if origin.RecurrentID != nil {
+		return u.UpdateOne(ctx, *origin.ID, input)
+	}
+
+	var result TransferOutput
+	err = u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var paidBefore float64
+		if origin.IsPaid {
+			paidBefore = destination.Amount
+		}
+
+		if err := u.rewritePair(ctx, tx, &origin, &destination, paidBefore, input); err != nil {
+			return err
+		}
+
+		result, err = u.updatePair(ctx, tx, origin, destination)
+		return err
+	})
+	if err != nil {
+		return TransferOutput{}, err
+	}
+
+	return result, nil
+}
+
+// Delete remove as duas pontas da transferência, estornando os saldos quando ela já
+// foi paga. Se o par for uma ocorrência de transferência recorrente, só ela é removida.
+func (u *Transfer) Delete(ctx context.Context, pairID uuid.UUID) error {
+	origin, destination, err := u.findPair(ctx, pairID)
+	if err != nil {
+		return err
+	}
+
+	if origin.RecurrentID != nil {
+		return u.DeleteOne(ctx, *origin.ID, *origin.Date)
+	}
+
+	return u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		return u.deletePair(ctx, tx, origin, destination)
+	})
+}
+
+func (u *Transfer) findPair(ctx context.Context, pairID uuid.UUID) (domain.Movement, domain.Movement, error) {
+	pair, err := u.movementRepo.FindByPairID(ctx, pairID)
+	if err != nil {
+		return domain.Movement{}, domain.Movement{}, fmt.Errorf("error finding transfer pair: %w", err)
+	}
+
+	if len(pair) == 0 {
+		return domain.Movement{}, domain.Movement{}, ErrTransferNotFound
+	}
+
+	return splitTransferPair(pair)
+}
+
+// rewritePair aplica input às duas pontas e move entre as carteiras a diferença entre
+// o valor pago antes (paidBefore) e depois da alteração.
+func (u *Transfer) rewritePair(
+	ctx context.Context,
+	tx *gorm.DB,
+	origin, destination *domain.Movement,
+	paidBefore float64,
+	input TransferUpdateInput,
+) error {
+	var paidAfter float64
+	if input.IsPaid {
+		paidAfter = input.Amount
+	}
+
+	for _, movement := range []*domain.Movement{origin, destination} {
+		movement.Date = &input.Date
+		movement.IsPaid = input.IsPaid
+		if input.Description != "" {
+			movement.Description = u.buildDescription(input.Description, origin.Wallet.Description, destination.Wallet.Description)
+		}
+	}
+	origin.Amount = -input.Amount
+	destination.Amount = input.Amount
+
+	return u.applyPaidDelta(ctx, tx, origin.WalletID, destination.WalletID, paidAfter-paidBefore)
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/domain/fixture"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func transferPair(pairID uuid.UUID, amount float64, isPaid bool) domain.MovementList {
+	originID := uuid.New()
+	destinationID := uuid.New()
+
+	return domain.MovementList{
+		{ID: &destinationID, Amount: amount, Date: &transferDate, IsPaid: isPaid, PairID: &pairID, WalletID: &destinationWalletID},
+		{ID: &originID, Amount: -amount, Date: &transferDate, IsPaid: isPaid, PairID: &pairID, WalletID: &originWalletID},
+	}
+}
+
+func TestTransfer_FindByPairID(t *testing.T) {
+	t.Run("should return origin and destination of the pair", func(t *testing.T) {
+		mockMovRepo := new(MockMovementRepository)
+		pairID := uuid.New()
+		mockMovRepo.On("FindByPairID", pairID).Return(transferPair(pairID, 100, false), nil)
+
+		usecase := NewTransfer(mockMovRepo, new(MockRecurrentRepository), new(MockWalletRepository), new(MockTransactionManager), nil)
+		result, err := usecase.FindByPairID(context.Background(), pairID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, pairID, result.PairID)
+		assert.Equal(t, -100.0, result.OriginMovement.Amount)
+		assert.Equal(t, 100.0, result.DestinationMovement.Amount)
+	})
+
+	t.Run("should return not found when pair has no movements", func(t *testing.T) {
+		mockMovRepo := new(MockMovementRepository)
+		pairID := uuid.New()
+		mockMovRepo.On("FindByPairID", pairID).Return(domain.MovementList{}, nil)
+
+		usecase := NewTransfer(mockMovRepo, new(MockRecurrentRepository), new(MockWalletRepository), new(MockTransactionManager), nil)
+		_, err := usecase.FindByPairID(context.Background(), pairID)
+
+		assert.ErrorIs(t, err, ErrTransferNotFound)
+	})
+}
+
+func TestTransfer_Update(t *testing.T) {
+	t.Run("should update both sides and move only the paid difference between wallets", func(t *testing.T) {
+		mockMovRepo := new(MockMovementRepository)
+		mockWalletRepo := new(MockWalletRepository)
+		mockTxManager := new(MockTransactionManager)
+
+		pairID := uuid.New()
+		mockMovRepo.On("FindByPairID", pairID).Return(transferPair(pairID, 100, true), nil)
+		mockTxManager.On("WithTransaction", mock.Anything).Return(nil)
+
+		mockWalletRepo.On("FindByID", &originWalletID).Return(fixture.WalletMock(
+			fixture.WithWalletID(originWalletID),
+			fixture.WithWalletBalance(1000.0),
+		), nil)
+		mockWalletRepo.On("FindByID", &destinationWalletID).Return(fixture.WalletMock(
+			fixture.WithWalletID(destinationWalletID),
+			fixture.WithWalletBalance(500.0),
+		), nil)
+		mockWalletRepo.On("UpdateAmount", mock.Anything, &originWalletID, 950.0).Return(nil).Once()
+		mockWalletRepo.On("UpdateAmount", mock.Anything, &destinationWalletID, 550.0).Return(nil).Once()
+
+		mockMovRepo.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(m domain.Movement) bool {
+			return m.Amount == -150 || m.Amount == 150
+		})).Return(domain.Movement{}, nil).Twice()
+		mockMovRepo.On("UpdateIsPaid", mock.Anything, mock.Anything, mock.Anything).Return(domain.Movement{}, nil).Twice()
+
+		usecase := NewTransfer(mockMovRepo, new(MockRecurrentRepository), mockWalletRepo, mockTxManager, nil)
+		result, err := usecase.Update(context.Background(), pairID, TransferUpdateInput{
+			Amount: 150,
+			Date:   transferDate,
+			IsPaid: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, pairID, result.PairID)
+		mockMovRepo.AssertExpectations(t)
+		mockWalletRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error when amount is not positive", func(t *testing.T) {
+		usecase := NewTransfer(new(MockMovementRepository), new(MockRecurrentRepository), new(MockWalletRepository), new(MockTransactionManager), nil)
+		_, err := usecase.Update(context.Background(), uuid.New(), TransferUpdateInput{Amount: 0, Date: transferDate})
+
+		assert.ErrorIs(t, err, ErrInvalidTransferAmount)
+	})
+}
+
+func TestTransfer_Delete(t *testing.T) {
+	t.Run("should delete both sides and revert balances of a paid transfer", func(t *testing.T) {
+		mockMovRepo := new(MockMovementRepository)
+		mockWalletRepo := new(MockWalletRepository)
+		mockTxManager := new(MockTransactionManager)
+
+		pairID := uuid.New()
+		pair := transferPair(pairID, 100, true)
+		mockMovRepo.On("FindByPairID", pairID).Return(pair, nil)
+		mockTxManager.On("WithTransaction", mock.Anything).Return(nil)
+
+		mockWalletRepo.On("FindByID", &originWalletID).Return(fixture.WalletMock(
+			fixture.WithWalletID(originWalletID),
+			fixture.WithWalletBalance(900.0),
+		), nil)
+		mockWalletRepo.On("FindByID", &destinationWalletID).Return(fixture.WalletMock(
+			fixture.WithWalletID(destinationWalletID),
+			fixture.WithWalletBalance(600.0),
+		), nil)
+		mockWalletRepo.On("UpdateAmount", mock.Anything, &originWalletID, 1000.0).Return(nil).Once()
+		mockWalletRepo.On("UpdateAmount", mock.Anything, &destinationWalletID, 500.0).Return(nil).Once()
+
+		mockMovRepo.On("Delete", mock.Anything, *pair[0].ID).Return(nil).Once()
+		mockMovRepo.On("Delete", mock.Anything, *pair[1].ID).Return(nil).Once()
+
+		usecase := NewTransfer(mockMovRepo, new(MockRecurrentRepository), mockWalletRepo, mockTxManager, nil)
+		err := usecase.Delete(context.Background(), pairID)
+
+		assert.NoError(t, err)
+		mockMovRepo.AssertExpectations(t)
+		mockWalletRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,555 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/infrastructure/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TransferUpdateInput struct {
+	Amount      float64   `json:"amount"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	IsPaid      bool      `json:"is_paid"`
+}
+
+// transferOccurrence é uma ocorrência (mês) de uma transferência recorrente: as duas
+// recorrências da série e, quando a ocorrência já foi materializada, as duas movimentações.
+type transferOccurrence struct {
+	origin              domain.RecurrentMovement
+	destination         domain.RecurrentMovement
+	originMovement      *domain.Movement
+	destinationMovement *domain.Movement
+	date                time.Time
+}
+
+func (o transferOccurrence) isRealized() bool {
+	return o.originMovement != nil && o.destinationMovement != nil
+}
+
+// inSeries indica se o mês da ocorrência está entre o início e o fim da série.
+func (o transferOccurrence) inSeries() bool {
+	month := firstDayOfMonth(o.date)
+	if month.Before(firstDayOfMonth(*o.origin.InitialDate)) {
+		return false
+	}
+	return o.origin.EndDate == nil || !month.After(firstDayOfMonth(*o.origin.EndDate))
+}
+
+func (o transferOccurrence) movements() (domain.Movement, domain.Movement) {
+	if o.isRealized() {
+		return *o.originMovement, *o.destinationMovement
+	}
+	return domain.FromRecurrentMovement(o.origin, o.date), domain.FromRecurrentMovement(o.destination, o.date)
+}
+
+// UpdateOne altera apenas a ocorrência informada, materializando as duas pontas
+// quando ela ainda é uma projeção. O id pode ser de qualquer uma das pontas.
+func (u *Transfer) UpdateOne(ctx context.Context, id uuid.UUID, input TransferUpdateInput) (TransferOutput, error) {
+	if err := validateTransferUpdate(input); err != nil {
+		return TransferOutput{}, err
+	}
+
+	var result TransferOutput
+	err := u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		occurrence, err := u.resolveOccurrence(ctx, id, input.Date)
+		if err != nil {
+			return err
+		}
+
+		origin, destination := occurrence.movements()
+
+		var paidBefore float64
+		if occurrence.isRealized() && origin.IsPaid {
+			paidBefore = destination.Amount
+		}
+
+		if err := u.rewritePair(ctx, tx, &origin, &destination, paidBefore, input); err != nil {
+			return err
+		}
+
+		if occurrence.isRealized() {
+			result, err = u.updatePair(ctx, tx, origin, destination)
+			return err
+		}
+
+		result, err = u.addPair(ctx, tx, origin, destination)
+		return err
+	})
+	if err != nil {
+		return TransferOutput{}, err
+	}
+
+	return result, nil
+}
+
+// UpdateAllNext encerra a série no mês anterior à ocorrência e cria uma nova série
+// a partir dela, levando junto as ocorrências já materializadas dali em diante.
+func (u *Transfer) UpdateAllNext(ctx context.Context, id uuid.UUID, input TransferUpdateInput) (TransferOutput, error) {
+	if err := validateTransferUpdate(input); err != nil {
+		return TransferOutput{}, err
+	}
+
+	var result TransferOutput
+	err := u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		occurrence, err := u.resolveOccurrence(ctx, id, input.Date)
+		if err != nil {
+			return err
+		}
+
+		origin, destination := occurrence.movements()
+		description := origin.Description
+		if input.Description != "" {
+			description = u.buildDescription(input.Description, origin.Wallet.Description, destination.Wallet.Description)
+		}
+
+		var exclude []uuid.UUID
+		if occurrence.isRealized() {
+			exclude = []uuid.UUID{*origin.ID, *destination.ID}
+		}
+
+		seriesID := uuid.New()
+		newOrigin, err := u.continueSeries(ctx, tx, occurrence.origin, occurrence.date, -input.Amount, description, seriesID, exclude)
+		if err != nil {
+			return err
+		}
+		newDestination, err := u.continueSeries(ctx, tx, occurrence.destination, occurrence.date, input.Amount, description, seriesID, exclude)
+		if err != nil {
+			return err
+		}
+
+		if occurrence.isRealized() {
+			if origin.IsPaid {
+				if err := u.applyPaidDelta(ctx, tx, origin.WalletID, destination.WalletID, input.Amount-destination.Amount); err != nil {
+					return err
+				}
+			}
+
+			for _, movement := range []*domain.Movement{&origin, &destination} {
+				movement.Date = &input.Date
+				movement.Description = description
+			}
+			origin.Amount = -input.Amount
+			origin.RecurrentID = newOrigin.ID
+			destination.Amount = input.Amount
+			destination.RecurrentID = newDestination.ID
+
+			result, err = u.updatePair(ctx, tx, origin, destination)
+			if err != nil {
+				return err
+			}
+		} else {
+			result = TransferOutput{
+				PairID:              seriesID,
+				OriginMovement:      domain.FromRecurrentMovement(newOrigin, occurrence.date),
+				DestinationMovement: domain.FromRecurrentMovement(newDestination, occurrence.date),
+			}
+		}
+		result.RecurrencePairID = &seriesID
+
+		if err := u.endSeries(ctx, tx, occurrence.origin, occurrence.date); err != nil {
+			return err
+		}
+		return u.endSeries(ctx, tx, occurrence.destination, occurrence.date)
+	})
+	if err != nil {
+		return TransferOutput{}, err
+	}
+
+	return result, nil
+}
+
+// DeleteOne remove apenas a ocorrência informada, dividindo a série em duas:
+// até o mês anterior e a partir do mês seguinte.
+func (u *Transfer) DeleteOne(ctx context.Context, id uuid.UUID, date time.Time) error {
+	return u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		occurrence, err := u.resolveOccurrence(ctx, id, date)
+		if err != nil {
+			return err
+		}
+
+		if occurrence.isRealized() {
+			if err := u.deletePair(ctx, tx, *occurrence.originMovement, *occurrence.destinationMovement); err != nil {
+				return err
+			}
+		}
+
+		nextDate := domain.SetMonthYear(occurrence.date, occurrence.date.Month()+1, occurrence.date.Year())
+		hasNext := occurrence.origin.EndDate == nil || !nextDate.After(*occurrence.origin.EndDate)
+
+		if hasNext {
+			seriesID := uuid.New()
+			for _, side := range []domain.RecurrentMovement{occurrence.origin, occurrence.destination} {
+				if _, err := u.continueSeries(ctx, tx, side, nextDate, side.Amount, side.Description, seriesID, nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := u.endSeries(ctx, tx, occurrence.origin, occurrence.date); err != nil {
+			return err
+		}
+		return u.endSeries(ctx, tx, occurrence.destination, occurrence.date)
+	})
+}
+
+// DeleteAllNext remove a ocorrência informada e todas as seguintes, estornando
+// os saldos das ocorrências já pagas.
+func (u *Transfer) DeleteAllNext(ctx context.Context, id uuid.UUID, date time.Time) error {
+	return u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		occurrence, err := u.resolveOccurrence(ctx, id, date)
+		if err != nil {
+			return err
+		}
+
+		from := firstDayOfMonth(occurrence.date)
+		for _, side := range []domain.RecurrentMovement{occurrence.origin, occurrence.destination} {
+			movements, err := u.movementRepo.FindAllByRecurrentID(ctx, *side.ID)
+			if err != nil {
+				return fmt.Errorf("error finding recurrent transfer movements: %w", err)
+			}
+
+			for _, movement := range movements {
+				if movement.Date == nil || movement.Date.Before(from) {
+					continue
+				}
+
+				if movement.IsPaid {
+					if err := u.updateWalletBalance(ctx, tx, movement.WalletID, movement.ReverseAmount()); err != nil {
+						return fmt.Errorf("error reverting wallet balance: %w", err)
+					}
+				}
+
+				if err := u.movementRepo.Delete(ctx, tx, *movement.ID); err != nil {
+					return fmt.Errorf("error deleting transfer movement: %w", err)
+				}
+			}
+
+			if err := u.endSeries(ctx, tx, side, occurrence.date); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// resolveOccurrence aceita o id de uma movimentação já materializada ou o id de uma
+// das recorrências (ocorrência projetada, identificada pela data).
+func (u *Transfer) resolveOccurrence(ctx context.Context, id uuid.UUID, date time.Time) (transferOccurrence, error) {
+	movement, err := u.movementRepo.FindByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, repository.ErrMovementNotFound) {
+			return transferOccurrence{}, fmt.Errorf("error finding movement: %w", err)
+		}
+
+		recurrent, err := u.recurrentRepo.FindByID(ctx, id)
+		if err != nil {
+			return transferOccurrence{}, fmt.Errorf("error finding recurrent movement: %w", err)
+		}
+
+		if date.IsZero() {
+			return transferOccurrence{}, ErrDateRequired
+		}
+
+		occurrence, err := u.findSeries(ctx, recurrent)
+		if err != nil {
+			return transferOccurrence{}, err
+		}
+		occurrence.date = domain.SetMonthYear(*recurrent.InitialDate, date.Month(), date.Year())
+
+		if !occurrence.inSeries() {
+			return transferOccurrence{}, domain.WrapInvalidInput(domain.New("date is outside the recurring transfer"), "resolve transfer occurrence")
+		}
+
+		// A ocorrência do mês pode já ter sido materializada: ela é resolvida pelas
+		// movimentações, para não criar um segundo par.
+		realized, err := u.realizedOccurrence(ctx, occurrence)
+		if err != nil || realized == nil {
+			return occurrence, err
+		}
+		return u.resolveOccurrence(ctx, *realized.ID, time.Time{})
+	}
+
+	if movement.PairID == nil || movement.RecurrentID == nil {
+		return transferOccurrence{}, ErrTransferNotRecurrent
+	}
+
+	recurrent, err := u.recurrentRepo.FindByID(ctx, *movement.RecurrentID)
+	if err != nil {
+		return transferOccurrence{}, fmt.Errorf("error finding recurrent movement: %w", err)
+	}
+
+	occurrence, err := u.findSeries(ctx, recurrent)
+	if err != nil {
+		return transferOccurrence{}, err
+	}
+
+	pair, err := u.movementRepo.FindByPairID(ctx, *movement.PairID)
+	if err != nil {
+		return transferOccurrence{}, fmt.Errorf("error finding transfer pair: %w", err)
+	}
+
+	origin, destination, err := splitTransferPair(pair)
+	if err != nil {
+		return transferOccurrence{}, err
+	}
+
+	occurrence.originMovement = &origin
+	occurrence.destinationMovement = &destination
+	occurrence.date = *movement.Date
+
+	return occurrence, nil
+}
+
+// realizedOccurrence busca a movimentação de origem já materializada no mês da
+// ocorrência, ou nil quando ela ainda é uma projeção.
+func (u *Transfer) realizedOccurrence(ctx context.Context, occurrence transferOccurrence) (*domain.Movement, error) {
+	movements, err := u.movementRepo.FindAllByRecurrentID(ctx, *occurrence.origin.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding recurrent transfer movements: %w", err)
+	}
+
+	month := firstDayOfMonth(occurrence.date)
+	for _, movement := range movements {
+		if movement.Date != nil && movement.PairID != nil && firstDayOfMonth(*movement.Date).Equal(month) {
+			return &movement, nil
+		}
+	}
+	return nil, nil
+}
+
+func (u *Transfer) findSeries(ctx context.Context, recurrent domain.RecurrentMovement) (transferOccurrence, error) {
+	if !recurrent.IsTransfer() {
+		return transferOccurrence{}, ErrTransferNotRecurrent
+	}
+
+	sides, err := u.recurrentRepo.FindByPairID(ctx, *recurrent.PairID)
+	if err != nil {
+		return transferOccurrence{}, fmt.Errorf("error finding recurrent transfer pair: %w", err)
+	}
+
+	if len(sides) != 2 || (sides[0].Amount < 0) == (sides[1].Amount < 0) {
+		return transferOccurrence{}, ErrTransferPairInconsistent
+	}
+
+	if sides[0].Amount < 0 {
+		return transferOccurrence{origin: sides[0], destination: sides[1]}, nil
+	}
+	return transferOccurrence{origin: sides[1], destination: sides[0]}, nil
+}
+
+// continueSeries cria a nova recorrência de uma das pontas a partir do mês de from e
+// move para ela as ocorrências já materializadas desse mês em diante (exceto exclude),
+// ajustando valor, descrição e o saldo das que já foram pagas.
+func (u *Transfer) continueSeries(
+	ctx context.Context,
+	tx *gorm.DB,
+	side domain.RecurrentMovement,
+	from time.Time,
+	amount float64,
+	description string,
+	seriesID uuid.UUID,
+	exclude []uuid.UUID,
+) (domain.RecurrentMovement, error) {
+	initialDate := domain.SetMonthYear(*side.InitialDate, from.Month(), from.Year())
+
+	newSide := side
+	newSide.ID = nil
+	newSide.Amount = amount
+	newSide.Description = description
+	newSide.InitialDate = &initialDate
+	newSide.PairID = &seriesID
+
+	created, err := u.recurrentRepo.Add(ctx, tx, newSide)
+	if err != nil {
+		return domain.RecurrentMovement{}, fmt.Errorf("error creating recurrent transfer: %w", err)
+	}
+	created.Wallet = side.Wallet
+
+	movements, err := u.movementRepo.FindAllByRecurrentID(ctx, *side.ID)
+	if err != nil {
+		return domain.RecurrentMovement{}, fmt.Errorf("error finding recurrent transfer movements: %w", err)
+	}
+
+	start := firstDayOfMonth(from)
+	for _, movement := range movements {
+		if movement.Date == nil || movement.Date.Before(start) || containsID(exclude, *movement.ID) {
+			continue
+		}
+
+		if movement.IsPaid && movement.Amount != amount {
+			if err := u.updateWalletBalance(ctx, tx, movement.WalletID, amount-movement.Amount); err != nil {
+				return domain.RecurrentMovement{}, fmt.Errorf("error updating wallet balance: %w", err)
+			}
+		}
+
+		movement.Amount = amount
+		movement.Description = description
+		movement.RecurrentID = created.ID
+		if _, err := u.movementRepo.Update(ctx, tx, *movement.ID, movement); err != nil {
+			return domain.RecurrentMovement{}, fmt.Errorf("error updating transfer movement: %w", err)
+		}
+	}
+
+	return created, nil
+}
+
+// endSeries encerra a recorrência no mês anterior a date ou a remove quando date é
+// o primeiro mês da série.
+func (u *Transfer) endSeries(ctx context.Context, tx *gorm.DB, side domain.RecurrentMovement, date time.Time) error {
+	endDate := domain.SetMonthYear(*side.InitialDate, date.Month()-1, date.Year())
+
+	if endDate.Before(*side.InitialDate) {
+		if err := u.movementRepo.DeleteAllByRecurrentID(ctx, tx, *side.ID); err != nil {
+			return fmt.Errorf("error deleting movements for recurrent transfer: %w", err)
+		}
+		if err := u.recurrentRepo.Delete(ctx, tx, side.ID); err != nil {
+			return fmt.Errorf("error deleting recurrent transfer: %w", err)
+		}
+		return nil
+	}
+
+	side.EndDate = &endDate
+	if _, err := u.recurrentRepo.Update(ctx, tx, side.ID, side); err != nil {
+		return fmt.Errorf("error updating recurrent transfer end date: %w", err)
+	}
+
+	return nil
+}
+
+func (u *Transfer) addPair(ctx context.Context, tx *gorm.DB, origin, destination domain.Movement) (TransferOutput, error) {
+	pairID := uuid.New()
+	origin.PairID = &pairID
+	destination.PairID = &pairID
+
+	createdOrigin, err := u.movementRepo.Add(ctx, tx, origin)
+	if err != nil {
+		return TransferOutput{}, fmt.Errorf("error creating origin movement: %w", err)
+	}
+
+	createdDestination, err := u.movementRepo.Add(ctx, tx, destination)
+	if err != nil {
+		return TransferOutput{}, fmt.Errorf("error creating destination movement: %w", err)
+	}
+
+	return TransferOutput{
+		PairID:              pairID,
+		OriginMovement:      createdOrigin,
+		DestinationMovement: createdDestination,
+	}, nil
+}
+
+func (u *Transfer) updatePair(ctx context.Context, tx *gorm.DB, origin, destination domain.Movement) (TransferOutput, error) {
+	result := TransferOutput{PairID: *origin.PairID}
+
+	for _, movement := range []domain.Movement{origin, destination} {
+		updated, err := u.movementRepo.Update(ctx, tx, *movement.ID, movement)
+		if err != nil {
+			return TransferOutput{}, fmt.Errorf("error updating transfer movement: %w", err)
+		}
+
+		if _, err := u.movementRepo.UpdateIsPaid(ctx, tx, *movement.ID, movement); err != nil {
+			return TransferOutput{}, fmt.Errorf("error updating transfer movement status: %w", err)
+		}
+
+		updated.ID = movement.ID
+		updated.IsPaid = movement.IsPaid
+		updated.PairID = movement.PairID
+		if movement.Amount < 0 {
+			result.OriginMovement = updated
+		} else {
+			result.DestinationMovement = updated
+		}
+	}
+
+	return result, nil
+}
+
+func (u *Transfer) deletePair(ctx context.Context, tx *gorm.DB, origin, destination domain.Movement) error {
+	if origin.IsPaid {
+		if err := u.applyPaidDelta(ctx, tx, origin.WalletID, destination.WalletID, -destination.Amount); err != nil {
+			return err
+		}
+	}
+
+	for _, movement := range []domain.Movement{origin, destination} {
+		if err := u.movementRepo.Delete(ctx, tx, *movement.ID); err != nil {
+			return fmt.Errorf("error deleting transfer movement: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// applyPaidDelta move delta da carteira de origem para a de destino (ou o inverso,
+// quando negativo), validando o saldo da origem ao debitar.
+func (u *Transfer) applyPaidDelta(ctx context.Context, tx *gorm.DB, originWalletID, destinationWalletID *uuid.UUID, delta float64) error {
+	if delta == 0 {
+		return nil
+	}
+
+	if delta > 0 {
+		originWallet, err := u.walletRepo.FindByID(ctx, originWalletID)
+		if err != nil {
+			return fmt.Errorf("error finding origin wallet: %w", err)
+		}
+		if !originWallet.HasSufficientBalance(-delta) {
+			return ErrInsufficientBalance
+		}
+	}
+
+	if err := u.updateWalletBalance(ctx, tx, originWalletID, -delta); err != nil {
+		return fmt.Errorf("error updating origin wallet balance: %w", err)
+	}
+
+	if err := u.updateWalletBalance(ctx, tx, destinationWalletID, delta); err != nil {
+		return fmt.Errorf("error updating destination wallet balance: %w", err)
+	}
+
+	return nil
+}
+
+func validateTransferUpdate(input TransferUpdateInput) error {
+	if input.Amount <= 0 {
+		return ErrInvalidTransferAmount
+	}
+
+	if input.Date.IsZero() {
+		return ErrDateRequired
+	}
+
+	return nil
+}
+
+// splitTransferPair separa as duas movimentações de um par em origem (saída) e destino (entrada).
+func splitTransferPair(pair domain.MovementList) (domain.Movement, domain.Movement, error) {
+	if len(pair) != 2 || (pair[0].Amount < 0) == (pair[1].Amount < 0) {
+		return domain.Movement{}, domain.Movement{}, ErrTransferPairInconsistent
+	}
+
+	if pair[0].Amount < 0 {
+		return pair[0], pair[1], nil
+	}
+	return pair[1], pair[0], nil
+}
+
+func firstDayOfMonth(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
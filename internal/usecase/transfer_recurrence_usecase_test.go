@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/internal/domain/fixture"
+	"personal-finance/internal/infrastructure/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func recurrentTransferSides(seriesID uuid.UUID, initialDate time.Time) (domain.RecurrentMovement, domain.RecurrentMovement) {
+	originID := uuid.New()
+	destinationID := uuid.New()
+
+	origin := domain.RecurrentMovement{
+		ID:          &originID,
+		Description: "Transferência de Conta Corrente para Poupança",
+		Amount:      -300,
+		InitialDate: &initialDate,
+		WalletID:    &originWalletID,
+		TypePayment: domain.TypePaymentInternalTransfer,
+		PairID:      &seriesID,
+	}
+	destination := origin
+	destination.ID = &destinationID
+	destination.Amount = 300
+	destination.WalletID = &destinationWalletID
+
+	return origin, destination
+}
+
+func TestTransfer_ExecuteRecurrent(t *testing.T) {
+	mockMovRepo := new(MockMovementRepository)
+	mockRecurrentRepo := new(MockRecurrentRepository)
+	mockWalletRepo := new(MockWalletRepository)
+	mockTxManager := new(MockTransactionManager)
+
+	endDate := transferDate.AddDate(0, 11, 0)
+	originRecurrentID := uuid.New()
+	destinationRecurrentID := uuid.New()
+
+	mockWalletRepo.On("FindByID", &originWalletID).Return(fixture.WalletMock(
+		fixture.WithWalletID(originWalletID),
+		fixture.WithWalletDescription("Conta Corrente"),
+		fixture.WithWalletBalance(1000.0),
+	), nil)
+	mockWalletRepo.On("FindByID", &destinationWalletID).Return(fixture.WalletMock(
+		fixture.WithWalletID(destinationWalletID),
+		fixture.WithWalletDescription("Poupança"),
+	), nil)
+	mockTxManager.On("WithTransaction", mock.Anything).Return(nil)
+
+	var seriesIDs []uuid.UUID
+	mockRecurrentRepo.On("Add", mock.Anything, mock.MatchedBy(func(r domain.RecurrentMovement) bool {
+		return r.Amount == -300
+	})).Run(func(args mock.Arguments) {
+		seriesIDs = append(seriesIDs, *args.Get(1).(domain.RecurrentMovement).PairID)
+	}).Return(domain.RecurrentMovement{ID: &originRecurrentID}, nil)
+	mockRecurrentRepo.On("Add", mock.Anything, mock.MatchedBy(func(r domain.RecurrentMovement) bool {
+		return r.Amount == 300 && r.EndDate != nil && r.EndDate.Equal(endDate)
+	})).Run(func(args mock.Arguments) {
+		seriesIDs = append(seriesIDs, *args.Get(1).(domain.RecurrentMovement).PairID)
+	}).Return(domain.RecurrentMovement{ID: &destinationRecurrentID}, nil)
+
+	mockMovRepo.On("Add", mock.Anything, mock.MatchedBy(func(m domain.Movement) bool {
+		return m.Amount == -300 && *m.RecurrentID == originRecurrentID
+	})).Return(domain.Movement{Amount: -300, WalletID: &originWalletID}, nil)
+	mockMovRepo.On("Add", mock.Anything, mock.MatchedBy(func(m domain.Movement) bool {
+		return m.Amount == 300 && *m.RecurrentID == destinationRecurrentID
+	})).Return(domain.Movement{Amount: 300, WalletID: &destinationWalletID}, nil)
+
+	usecase := NewTransfer(mockMovRepo, mockRecurrentRepo, mockWalletRepo, mockTxManager, nil)
+
+	result, err := usecase.Execute(context.Background(), TransferInput{
+		OriginWalletID:      originWalletID,
+		DestinationWalletID: destinationWalletID,
+		Amount:              300,
+		Date:                transferDate,
+		IsRecurrent:         true,
+		EndDate:             &endDate,
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.RecurrencePairID)
+	assert.Len(t, seriesIDs, 2)
+	assert.Equal(t, seriesIDs[0], seriesIDs[1])
+	assert.Equal(t, seriesIDs[0], *result.RecurrencePairID)
+	mockMovRepo.AssertExpectations(t)
+	mockRecurrentRepo.AssertExpectations(t)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestTransfer_DeleteOne(t *testing.T) {
+	t.Run("should split both sides of the series around a projected occurrence", func(t *testing.T) {
+		mockMovRepo := new(MockMovementRepository)
+		mockRecurrentRepo := new(MockRecurrentRepository)
+		mockTxManager := new(MockTransactionManager)
+
+		seriesID := uuid.New()
+		initialDate := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+		origin, destination := recurrentTransferSides(seriesID, initialDate)
+		occurrenceDate := time.Date(2023, 6, 10, 0, 0, 0, 0, time.UTC)
+
+		mockTxManager.On("WithTransaction", mock.Anything).Return(nil)
+		mockMovRepo.On("FindByID", *origin.ID).Return(domain.Movement{}, repository.ErrMovementNotFound)
+		mockRecurrentRepo.On("FindByID", *origin.ID).Return(origin, nil)
+		mockRecurrentRepo.On("FindByPairID", seriesID).Return([]domain.RecurrentMovement{destination, origin}, nil)
+
+		var newSeriesIDs []uuid.UUID
+		mockRecurrentRepo.On("Add", mock.Anything, mock.MatchedBy(func(r domain.RecurrentMovement) bool {
+			return r.InitialDate.Month() == time.July && *r.PairID != seriesID
+		})).Run(func(args mock.Arguments) {
+			newSeriesIDs = append(newSeriesIDs, *args.Get(1).(domain.RecurrentMovement).PairID)
+		}).Return(domain.RecurrentMovement{ID: &uuid.UUID{}}, nil)
+		mockMovRepo.On("FindAllByRecurrentID", mock.Anything).Return(domain.MovementList{}, nil)
+
+		for _, side := range []domain.RecurrentMovement{origin, destination} {
+			mockRecurrentRepo.On("Update", mock.Anything, side.ID, mock.MatchedBy(func(r domain.RecurrentMovement) bool {
+				return r.EndDate != nil && r.EndDate.Month() == time.May
+			})).Return(side, nil)
+		}
+
+		usecase := NewTransfer(mockMovRepo, mockRecurrentRepo, new(MockWalletRepository), mockTxManager, nil)
+
+		err := usecase.DeleteOne(context.Background(), *origin.ID, occurrenceDate)
+
+		assert.NoError(t, err)
+		assert.Len(t, newSeriesIDs, 2)
+		assert.Equal(t, newSeriesIDs[0], newSeriesIDs[1])
+		mockMovRepo.AssertExpectations(t)
+		mockRecurrentRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error when movement is not part of a recurring transfer", func(t *testing.T) {
+		mockMovRepo := new(MockMovementRepository)
+		mockTxManager := new(MockTransactionManager)
+
+		movementID := uuid.New()
+		mockTxManager.On("WithTransaction", mock.Anything).Return(nil)
+		mockMovRepo.On("FindByID", movementID).Return(domain.Movement{ID: &movementID, Amount: -50}, nil)
+
+		usecase := NewTransfer(mockMovRepo, new(MockRecurrentRepository), new(MockWalletRepository), mockTxManager, nil)
+		err := usecase.DeleteOne(context.Background(), movementID, transferDate)
+
+		assert.ErrorIs(t, err, ErrTransferNotRecurrent)
+		mockMovRepo.AssertExpectations(t)
+	})
+}
+
+func TestTransfer_ResolveOccurrence(t *testing.T) {
+	seriesID := uuid.New()
+	initialDate := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2023, 12, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should resolve an occurrence already materialized by its movements", func(t *testing.T) {
+		mockMovRepo := new(MockMovementRepository)
+		mockRecurrentRepo := new(MockRecurrentRepository)
+		origin, destination := recurrentTransferSides(seriesID, initialDate)
+
+		pairID := uuid.New()
+		originMovementID := uuid.New()
+		destinationMovementID := uuid.New()
+		occurrenceDate := time.Date(2023, 6, 10, 0, 0, 0, 0, time.UTC)
+		originMovement := domain.Movement{ID: &originMovementID, Amount: -300, Date: &occurrenceDate, PairID: &pairID, RecurrentID: origin.ID}
+		destinationMovement := domain.Movement{ID: &destinationMovementID, Amount: 300, Date: &occurrenceDate, PairID: &pairID, RecurrentID: destination.ID}
+
+		mockMovRepo.On("FindByID", *origin.ID).Return(domain.Movement{}, repository.ErrMovementNotFound)
+		mockRecurrentRepo.On("FindByID", *origin.ID).Return(origin, nil)
+		mockRecurrentRepo.On("FindByPairID", seriesID).Return([]domain.RecurrentMovement{origin, destination}, nil)
+		mockMovRepo.On("FindAllByRecurrentID", *origin.ID).Return(domain.MovementList{originMovement}, nil)
+		mockMovRepo.On("FindByID", originMovementID).Return(originMovement, nil)
+		mockMovRepo.On("FindByPairID", pairID).Return(domain.MovementList{originMovement, destinationMovement}, nil)
+
+		usecase := NewTransfer(mockMovRepo, mockRecurrentRepo, new(MockWalletRepository), new(MockTransactionManager), nil)
+
+		occurrence, err := usecase.resolveOccurrence(context.Background(), *origin.ID, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.NoError(t, err)
+		assert.True(t, occurrence.isRealized(), "the materialized pair is reused instead of creating another")
+		assert.Equal(t, originMovementID, *occurrence.originMovement.ID)
+		assert.Equal(t, destinationMovementID, *occurrence.destinationMovement.ID)
+	})
+
+	for name, date := range map[string]time.Time{
+		"should reject a date before the series starts": time.Date(2022, 12, 10, 0, 0, 0, 0, time.UTC),
+		"should reject a date after the series ends":    time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockMovRepo := new(MockMovementRepository)
+			mockRecurrentRepo := new(MockRecurrentRepository)
+			origin, destination := recurrentTransferSides(seriesID, initialDate)
+			origin.EndDate = &endDate
+			destination.EndDate = &endDate
+
+			mockMovRepo.On("FindByID", *origin.ID).Return(domain.Movement{}, repository.ErrMovementNotFound)
+			mockRecurrentRepo.On("FindByID", *origin.ID).Return(origin, nil)
+			mockRecurrentRepo.On("FindByPairID", seriesID).Return([]domain.RecurrentMovement{origin, destination}, nil)
+
+			usecase := NewTransfer(mockMovRepo, mockRecurrentRepo, new(MockWalletRepository), new(MockTransactionManager), nil)
+
+			_, err := usecase.resolveOccurrence(context.Background(), *origin.ID, date)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			mockMovRepo.AssertNotCalled(t, "FindAllByRecurrentID", mock.Anything)
+		})
+	}
+}
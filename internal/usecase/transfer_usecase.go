@@ -13,35 +13,44 @@ import (
 )
 
 type TransferInput struct {
-	OriginWalletID      uuid.UUID `json:"origin_wallet_id"`
-	DestinationWalletID uuid.UUID `json:"destination_wallet_id"`
-	Amount              float64   `json:"amount"`
-	Date                time.Time `json:"date"`
-	Description         string    `json:"description"`
-	IsPaid              bool      `json:"is_paid"`
+	OriginWalletID      uuid.UUID  `json:"origin_wallet_id"`
+	DestinationWalletID uuid.UUID  `json:"destination_wallet_id"`
+	Amount              float64    `json:"amount"`
+	Date                time.Time  `json:"date"`
+	Description         string     `json:"description"`
+	IsPaid              bool       `json:"is_paid"`
+	IsRecurrent         bool       `json:"is_recurrent"`
+	EndDate             *time.Time `json:"end_date,omitempty"`
 }
 
 type TransferOutput struct {
 	PairID              uuid.UUID       `json:"pair_id"`
+	RecurrencePairID    *uuid.UUID      `json:"recurrence_pair_id,omitempty"`
 	OriginMovement      domain.Movement `json:"origin_movement"`
 	DestinationMovement domain.Movement `json:"destination_movement"`
 }
 
 type Transfer struct {
-	movementRepo MovementRepository
-	walletRepo   WalletRepository
-	txManager    transaction.Manager
+	movementRepo    MovementRepository
+	recurrentRepo   RecurrentRepository
+	walletRepo      WalletRepository
+	txManager       transaction.Manager
+	limitsValidator PlanLimitsValidatorInterface
 }
 
 func NewTransfer(
 	movementRepo MovementRepository,
+	recurrentRepo RecurrentRepository,
 	walletRepo WalletRepository,
 	txManager transaction.Manager,
+	limitsValidator PlanLimitsValidatorInterface,
 ) Transfer {
 	return Transfer{
-		movementRepo: movementRepo,
-		walletRepo:   walletRepo,
-		txManager:    txManager,
+		movementRepo:    movementRepo,
+		recurrentRepo:   recurrentRepo,
+		walletRepo:      walletRepo,
+		txManager:       txManager,
+		limitsValidator: limitsValidator,
 	}
 }
 
@@ -50,6 +59,12 @@ func (u *Transfer) Execute(ctx context.Context, input TransferInput) (TransferOu
 		return TransferOutput{}, err
 	}
 
+	if input.IsRecurrent && u.limitsValidator != nil {
+		if err := u.limitsValidator.ValidateRecurrenceCreation(ctx); err != nil {
+			return TransferOutput{}, err
+		}
+	}
+
 	originWallet, err := u.walletRepo.FindByID(ctx, &input.OriginWalletID)
 	if err != nil {
 		return TransferOutput{}, fmt.Errorf("error finding origin wallet: %w", err)
@@ -60,6 +75,13 @@ func (u *Transfer) Execute(ctx context.Context, input TransferInput) (TransferOu
 		return TransferOutput{}, fmt.Errorf("error finding destination wallet: %w", err)
 	}
 
+	if originWallet.IsArchived() || destinationWallet.IsArchived() {
+		return TransferOutput{}, domain.WrapInvalidInput(
+			domain.New("origin and destination wallets must not be archived"),
+			"validate transfer",
+		)
+	}
+
 	if input.IsPaid && !originWallet.HasSufficientBalance(-input.Amount) {
 		return TransferOutput{}, ErrInsufficientBalance
 	}
@@ -91,8 +113,18 @@ func (u *Transfer) Execute(ctx context.Context, input TransferInput) (TransferOu
 	}
 
 	var result TransferOutput
+	var recurrencePairID *uuid.UUID
 
 	err = u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if input.IsRecurrent {
+			seriesID := uuid.New()
+			recurrencePairID = &seriesID
+
+			if err := u.createRecurrentSides(ctx, tx, &originMovement, &destinationMovement, seriesID, input.EndDate); err != nil {
+				return err
+			}
+		}
+
 		createdOrigin, err := u.movementRepo.Add(ctx, tx, originMovement)
 		if err != nil {
 			return fmt.Errorf("error creating origin movement: %w", err)
@@ -115,6 +147,7 @@ func (u *Transfer) Execute(ctx context.Context, input TransferInput) (TransferOu
 
 		result = TransferOutput{
 			PairID:              pairID,
+			RecurrencePairID:    recurrencePairID,
 			OriginMovement:      createdOrigin,
 			DestinationMovement: createdDestination,
 		}
@@ -142,6 +175,35 @@ func (u *Transfer) validateInput(input TransferInput) error {
 		return ErrDateRequired
 	}
 
+	if input.EndDate != nil && input.EndDate.Before(input.Date) {
+		return ErrInvalidTransferEndDate
+	}
+
+	return nil
+}
+
+// createRecurrentSides cria as duas recorrências (origem e destino) da série,
+// ligadas pelo seriesID, e vincula a primeira ocorrência a elas.
+func (u *Transfer) createRecurrentSides(
+	ctx context.Context,
+	tx *gorm.DB,
+	originMovement, destinationMovement *domain.Movement,
+	seriesID uuid.UUID,
+	endDate *time.Time,
+) error {
+	for _, movement := range []*domain.Movement{originMovement, destinationMovement} {
+		recurrent := domain.ToRecurrentMovement(*movement)
+		recurrent.PairID = &seriesID
+		recurrent.EndDate = endDate
+
+		createdRecurrent, err := u.recurrentRepo.Add(ctx, tx, recurrent)
+		if err != nil {
+			return fmt.Errorf("error creating recurrent transfer: %w", err)
+		}
+
+		movement.RecurrentID = createdRecurrent.ID
+	}
+
 	return nil
 }
 
@@ -264,6 +264,38 @@ func TestTransfer_Execute(t *testing.T) {
 				assert.Equal(t, TransferOutput{}, result)
 			},
 		},
+		"should return error when destination wallet is archived": {
+			input: TransferInput{
+				OriginWalletID:      originWalletID,
+				DestinationWalletID: destinationWalletID,
+				Amount:              500.0,
+				Date:                transferDate,
+				IsPaid:              true,
+			},
+			mockSetup: func(mockMovRepo *MockMovementRepository, mockWalletRepo *MockWalletRepository, mockTxManager *MockTransactionManager) {
+				originWallet := fixture.WalletMock(
+					fixture.WithWalletID(originWalletID),
+					fixture.WithWalletBalance(1000.0),
+				)
+
+				archivedAt := transferDate.AddDate(0, -1, 0)
+				destinationWallet := fixture.WalletMock(
+					fixture.WithWalletID(destinationWalletID),
+					fixture.WithWalletBalance(500.0),
+				)
+				destinationWallet.ArchivedAt = &archivedAt
+
+				mockWalletRepo.On("FindByID", &originWalletID).Return(originWallet, nil)
+				mockWalletRepo.On("FindByID", &destinationWalletID).Return(destinationWallet, nil)
+			},
+			expectedError: domain.WrapInvalidInput(
+				domain.New("origin and destination wallets must not be archived"),
+				"validate transfer",
+			),
+			validateResult: func(t *testing.T, result TransferOutput) {
+				assert.Equal(t, TransferOutput{}, result)
+			},
+		},
 		"should return error when origin wallet has insufficient balance": {
 			input: TransferInput{
 				OriginWalletID:      originWalletID,
@@ -468,8 +500,10 @@ func TestTransfer_Execute(t *testing.T) {
 
 			usecase := NewTransfer(
 				mockMovRepo,
+				new(MockRecurrentRepository),
 				mockWalletRepo,
 				mockTxManager,
+				nil,
 			)
 
 			result, err := usecase.Execute(context.Background(), tt.input)
@@ -29,11 +29,27 @@ func (u *Movement) UpdateAllNext(ctx context.Context, id uuid.UUID, newMovement
 			return findErr
 		}
 
+		if existingMovement.IsTransfer() {
+			return ErrTransferMovementChange
+		}
+
 		recurrent, recErr := u.resolveRecurrent(ctx, &existingMovement, id)
 		if recErr != nil {
 			return recErr
 		}
 
+		if recurrent.IsTransfer() {
+			return ErrTransferMovementChange
+		}
+
+		currentWalletID := existingMovement.WalletID
+		if currentWalletID == nil {
+			currentWalletID = recurrent.WalletID
+		}
+		if err := u.validateWalletChange(ctx, currentWalletID, newMovement.WalletID); err != nil {
+			return err
+		}
+
 		if recurrent.ID != nil {
 			return u.updateAllNextRecurrent(ctx, tx, &existingMovement, &recurrent, newMovement, &result)
 		}
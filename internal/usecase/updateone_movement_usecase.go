@@ -24,6 +24,7 @@ func (u *Movement) UpdateOne(ctx context.Context, id uuid.UUID, newMovement doma
 	result := domain.Movement{}
 	err = u.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
 		existingMovement, err := u.movementRepo.FindByID(ctx, id)
+		currentWalletID := existingMovement.WalletID
 		if err != nil {
 			if !errors.Is(err, repository.ErrMovementNotFound) {
 				return err
@@ -34,10 +35,23 @@ func (u *Movement) UpdateOne(ctx context.Context, id uuid.UUID, newMovement doma
 				return err
 			}
 
+			if recurrent.IsTransfer() {
+				return ErrTransferMovementChange
+			}
+
 			newFromRecurrent := domain.FromRecurrentMovement(recurrent, *newMovement.Date)
+			currentWalletID = recurrent.WalletID
 			newMovement = update(newMovement, newFromRecurrent)
 		}
 
+		if existingMovement.IsTransfer() {
+			return ErrTransferMovementChange
+		}
+
+		if err := u.validateWalletChange(ctx, currentWalletID, newMovement.WalletID); err != nil {
+			return err
+		}
+
 		if existingMovement.IsCreditCardMovement() {
 			err = u.handleCreditCardMovementUpdate(ctx, tx, &existingMovement, &newMovement)
 			if err != nil {
@@ -152,6 +166,8 @@ func (u *Movement) handleCreditCardMovementUpdate(
 		return ErrCreditMovementShouldNotBePaid
 	}
 
+	keepCreditCardInfo(existingMovement, newMovement)
+
 	invoice, err := u.invoiceRepo.FindByID(ctx, *existingMovement.CreditCardInfo.InvoiceID)
 	if err != nil {
 		return fmt.Errorf("error finding invoice: %w", err)
@@ -172,9 +188,15 @@ func (u *Movement) handleCreditCardMovementUpdate(
 		}
 	}
 
-	_, err = u.invoiceRepo.UpdateAmount(ctx, tx, *existingMovement.CreditCardInfo.InvoiceID, invoice.Amount+delta)
-	if err != nil {
-		return fmt.Errorf("error updating invoice amount: %w", err)
+	if movedOutOfInvoicePeriod(invoice, existingMovement, newMovement) {
+		if err := u.moveToInvoice(ctx, tx, invoice, existingMovement, newMovement); err != nil {
+			return err
+		}
+	} else {
+		_, err = u.invoiceRepo.UpdateAmount(ctx, tx, *existingMovement.CreditCardInfo.InvoiceID, invoice.Amount+delta)
+		if err != nil {
+			return fmt.Errorf("error updating invoice amount: %w", err)
+		}
 	}
 
 	if delta != 0 {
@@ -187,6 +209,70 @@ func (u *Movement) handleCreditCardMovementUpdate(
 	return nil
 }
 
+// keepCreditCardInfo preserva o vínculo da compra no cartão (fatura, grupo e
+// número da parcela) ao editar uma única parcela, já que o corpo do PUT
+// normalmente não traz esses campos.
+func keepCreditCardInfo(existingMovement, newMovement *domain.Movement) {
+	newMovement.TypePayment = existingMovement.TypePayment
+
+	info := *existingMovement.CreditCardInfo
+	newMovement.CreditCardInfo = &info
+}
+
+func movedOutOfInvoicePeriod(invoice domain.Invoice, existingMovement, newMovement *domain.Movement) bool {
+	if existingMovement.Date == nil || newMovement.Date == nil || existingMovement.Date.Equal(*newMovement.Date) {
+		return false
+	}
+
+	return newMovement.Date.Before(invoice.PeriodStart) || newMovement.Date.After(invoice.PeriodEnd)
+}
+
+func (u *Movement) moveToInvoice(
+	ctx context.Context,
+	tx *gorm.DB,
+	currentInvoice domain.Invoice,
+	existingMovement *domain.Movement,
+	newMovement *domain.Movement,
+) error {
+	targetInvoice, err := u.invoiceUseCase.FindOrCreateInvoiceForMovement(
+		ctx,
+		tx,
+		nil,
+		existingMovement.CreditCardInfo.CreditCardID,
+		*newMovement.Date,
+	)
+	if err != nil {
+		return fmt.Errorf("error finding/creating target invoice: %w", err)
+	}
+
+	if targetInvoice.IsPaid {
+		return ErrInvoiceAlreadyPaid
+	}
+
+	newAmount := newMovement.Amount
+	if newAmount == 0 {
+		newAmount = existingMovement.Amount
+	}
+
+	_, err = u.invoiceRepo.UpdateAmount(ctx, tx, *currentInvoice.ID, currentInvoice.Amount-existingMovement.Amount)
+	if err != nil {
+		return fmt.Errorf("error updating invoice amount: %w", err)
+	}
+
+	_, err = u.invoiceRepo.UpdateAmount(ctx, tx, *targetInvoice.ID, targetInvoice.Amount+newAmount)
+	if err != nil {
+		return fmt.Errorf("error updating target invoice amount: %w", err)
+	}
+
+	if err := u.movementRepo.UpdateInvoiceID(ctx, tx, *existingMovement.ID, *targetInvoice.ID); err != nil {
+		return fmt.Errorf("error moving movement to target invoice: %w", err)
+	}
+
+	newMovement.CreditCardInfo.InvoiceID = targetInvoice.ID
+
+	return nil
+}
+
 func update(newMovement, movementFound domain.Movement) domain.Movement {
 	if newMovement.Description != "" && newMovement.Description != movementFound.Description {
 		movementFound.Description = newMovement.Description
@@ -23,6 +23,15 @@ var (
 	ErrInvalidPaymentAmount          = errors.New("payment amount must be between invoice amount and zero")
 	ErrSameWalletTransfer            = errors.New("origin and destination wallets must be different")
 	ErrInvalidTransferAmount         = errors.New("transfer amount must be positive")
+	ErrInvalidTransferEndDate        = errors.New("transfer end date must be after the first occurrence")
+	ErrTransferNotRecurrent          = errors.New("movement is not part of a recurring transfer")
+	ErrTransferPairInconsistent      = errors.New("transfer pair must have one origin and one destination movement")
+	ErrTransferMovementChange        = errors.New("transfer movements must be changed through the transfer endpoints")
+	ErrTransferNotFound              = errors.New("transfer not found")
+	ErrInvalidWalletType             = errors.New("invalid wallet type: must be one of [checking, savings, cash, benefit, investment]")
+	ErrInvalidBalanceGranularity     = errors.New("invalid granularity: must be one of [day, month]")
+	ErrInvalidHistoryPeriod          = errors.New("'from' must be before 'to'")
+	ErrHistoryPeriodTooLong          = errors.New("history period must be up to 366 days")
 	ErrLanguageRequired              = errors.New("language is required")
 	ErrInvalidLanguageFormat         = errors.New("language must be in BCP47 format")
 	ErrCurrencyRequired              = errors.New("currency is required")
@@ -37,9 +46,13 @@ var (
 	ErrCreditCardLimitReached = errors.New("credit card limit reached for your plan")
 	ErrMovementLimitReached   = errors.New("movement limit reached for your plan this month")
 	ErrRecurrenceLimitReached = errors.New("recurrence limit reached for your plan this month")
+	ErrFeatureNotInPlan       = errors.New("feature not available in your plan")
 	ErrInvalidPlan            = errors.New("invalid plan")
 	ErrInvalidRole            = errors.New("invalid role")
 
+	ErrAIQuotaExceeded = errors.New("AI usage quota reached for your plan this month")
+	ErrInvalidAIQuota  = errors.New("AI quota values must not be negative")
+
 	ErrInvalidWebhookSignature     = errors.New("invalid webhook signature")
 	ErrMercadoPagoGateway          = errors.New("mercado pago gateway error")
 	ErrMPCrossCountry              = errors.New("mercado pago cross-country restriction")
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"personal-finance/internal/domain"
 
@@ -14,13 +15,19 @@ type WalletRepository interface {
 	Add(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error)
 	AddConsistent(ctx context.Context, tx *gorm.DB, wallet domain.Wallet) (domain.Wallet, error)
 	FindAll(ctx context.Context) ([]domain.Wallet, error)
+	FindActive(ctx context.Context) ([]domain.Wallet, error)
 	FindByID(ctx context.Context, ID *uuid.UUID) (domain.Wallet, error)
+	FindPaidEntries(ctx context.Context, walletID *uuid.UUID, from, until time.Time) ([]domain.WalletBalanceEntry, error)
 	Update(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error)
 	UpdateAmount(ctx context.Context, tx *gorm.DB, walletID *uuid.UUID, amout float64) error
+	UpdateArchivedAt(ctx context.Context, ID *uuid.UUID, archivedAt *time.Time) error
 	Delete(ctx context.Context, ID *uuid.UUID) error
 	RecalculateBalance(ctx context.Context, walletID *uuid.UUID) error
 }
 
+// maxBalanceHistoryDays limita o período do histórico de saldo a um ano (bissexto).
+const maxBalanceHistoryDays = 366
+
 type Wallet struct {
 	repo            WalletRepository
 	limitsValidator PlanLimitsValidatorInterface
@@ -42,6 +49,10 @@ func (uc Wallet) RecalculateBalance(ctx context.Context, walletID *uuid.UUID) er
 }
 
 func (uc Wallet) Add(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error) {
+	if wallet.Type != "" && !wallet.Type.IsValid() {
+		return domain.Wallet{}, ErrInvalidWalletType
+	}
+
 	if uc.limitsValidator != nil {
 		if err := uc.limitsValidator.ValidateWalletCreation(ctx); err != nil {
 			return domain.Wallet{}, err
@@ -55,8 +66,15 @@ func (uc Wallet) Add(ctx context.Context, wallet domain.Wallet) (domain.Wallet,
 	return result, nil
 }
 
-func (uc Wallet) FindAll(ctx context.Context) ([]domain.Wallet, error) {
-	resultList, err := uc.repo.FindAll(ctx)
+// FindAll lista as carteiras exibidas nos seletores; as arquivadas só entram com
+// includeArchived.
+func (uc Wallet) FindAll(ctx context.Context, includeArchived bool) ([]domain.Wallet, error) {
+	find := uc.repo.FindActive
+	if includeArchived {
+		find = uc.repo.FindAll
+	}
+
+	resultList, err := find(ctx)
 	if err != nil {
 		return []domain.Wallet{}, fmt.Errorf("erro ao buscar carteiras: %w", err)
 	}
@@ -72,6 +90,10 @@ func (uc Wallet) FindByID(ctx context.Context, id *uuid.UUID) (domain.Wallet, er
 }
 
 func (uc Wallet) Update(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error) {
+	if wallet.Type != "" && !wallet.Type.IsValid() {
+		return domain.Wallet{}, ErrInvalidWalletType
+	}
+
 	result, err := uc.repo.Update(ctx, wallet)
 	if err != nil {
 		return domain.Wallet{}, fmt.Errorf("erro ao atualizar carteira: %w", err)
@@ -86,3 +108,58 @@ func (uc Wallet) Delete(ctx context.Context, id *uuid.UUID) error {
 	}
 	return nil
 }
+
+func (uc Wallet) Archive(ctx context.Context, id *uuid.UUID) (domain.Wallet, error) {
+	now := time.Now()
+	return uc.setArchivedAt(ctx, id, &now)
+}
+
+func (uc Wallet) Unarchive(ctx context.Context, id *uuid.UUID) (domain.Wallet, error) {
+	return uc.setArchivedAt(ctx, id, nil)
+}
+
+func (uc Wallet) setArchivedAt(ctx context.Context, id *uuid.UUID, archivedAt *time.Time) (domain.Wallet, error) {
+	if err := uc.repo.UpdateArchivedAt(ctx, id, archivedAt); err != nil {
+		return domain.Wallet{}, fmt.Errorf("erro ao arquivar carteira: %w", err)
+	}
+
+	result, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return domain.Wallet{}, fmt.Errorf("erro ao buscar carteira: %w", err)
+	}
+	return result, nil
+}
+
+// BalanceHistory calcula o saldo de fechamento da carteira em cada dia (ou mês) entre
+// from e to a partir do saldo inicial e das movimentações pagas, como em RecalculateBalance.
+func (uc Wallet) BalanceHistory(
+	ctx context.Context,
+	id *uuid.UUID,
+	from, to time.Time,
+	granularity domain.BalanceGranularity,
+) ([]domain.WalletBalancePoint, error) {
+	if !granularity.IsValid() {
+		return nil, ErrInvalidBalanceGranularity
+	}
+
+	if to.Before(from) {
+		return nil, ErrInvalidHistoryPeriod
+	}
+
+	if to.Sub(from) > maxBalanceHistoryDays*24*time.Hour {
+		return nil, ErrHistoryPeriodTooLong
+	}
+
+	wallet, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar carteira: %w", err)
+	}
+
+	until := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, to.Location())
+	entries, err := uc.repo.FindPaidEntries(ctx, id, wallet.InitialDate, until)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar movimentações da carteira: %w", err)
+	}
+
+	return domain.BuildBalanceHistory(wallet.InitialBalance, entries, from, to, granularity), nil
+}
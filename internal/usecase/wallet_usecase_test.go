@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWallet_BalanceHistory_Period(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		to          time.Time
+		expectedErr error
+	}{
+		"should reject a period ending before it starts": {
+			to:          from.AddDate(0, 0, -1),
+			expectedErr: ErrInvalidHistoryPeriod,
+		},
+		"should reject a period longer than 366 days": {
+			to:          from.AddDate(0, 0, 367),
+			expectedErr: ErrHistoryPeriodTooLong,
+		},
+		"should accept a period of 366 days": {
+			to: from.AddDate(0, 0, 366),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			id := uuid.New()
+			repo := new(MockWalletRepository)
+			repo.On("FindByID", &id).Return(domain.Wallet{ID: &id, InitialDate: from}, nil).Maybe()
+			repo.On("FindPaidEntries", &id, from, mock.Anything).Return([]domain.WalletBalanceEntry{}, nil).Maybe()
+
+			points, err := NewWallet(repo, nil).BalanceHistory(context.Background(), &id, from, tt.to, domain.BalanceGranularityMonth)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				repo.AssertNotCalled(t, "FindByID", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, points)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+)
+
+const (
+	webhookBatchSize      = 100
+	webhookDeadLetterList = 200
+)
+
+type WebhookInboxRepository interface {
+	Insert(ctx context.Context, event domain.WebhookInboxEvent) (bool, error)
+	FindByID(ctx context.Context, id uuid.UUID) (domain.WebhookInboxEvent, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookInboxEvent, error)
+	ListByStatus(ctx context.Context, status domain.WebhookEventStatus, limit int) ([]domain.WebhookInboxEvent, error)
+	Claim(ctx context.Context, event domain.WebhookInboxEvent, leaseUntil time.Time) (bool, error)
+	MarkProcessed(ctx context.Context, id uuid.UUID, processedAt time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string, status domain.WebhookEventStatus, nextAttemptAt time.Time) error
+	Requeue(ctx context.Context, id uuid.UUID, now time.Time) (domain.WebhookInboxEvent, error)
+}
+
+// WebhookEventProcessor applies a stored webhook. Subscription implements it.
+type WebhookEventProcessor interface {
+	ProcessWebhookEvent(ctx context.Context, event domain.WebhookInboxEvent) error
+}
+
+// WebhookInbox persists verified billing webhooks and processes them apart from
+// the request that delivered them, retrying with backoff until they succeed or
+// are dead-lettered.
+type WebhookInbox struct {
+	repo      WebhookInboxRepository
+	processor WebhookEventProcessor
+}
+
+func NewWebhookInbox(repo WebhookInboxRepository, processor WebhookEventProcessor) *WebhookInbox {
+	return &WebhookInbox{
+		repo:      repo,
+		processor: processor,
+	}
+}
+
+type WebhookInboxJobResult struct {
+	EventsDue    int `json:"events_due"`
+	Processed    int `json:"processed"`
+	Retried      int `json:"retried"`
+	DeadLettered int `json:"dead_lettered"`
+	Skipped      int `json:"skipped"`
+}
+
+// Enqueue stores the event. A delivery of an event already in the inbox is
+// ignored, whatever its status.
+func (u *WebhookInbox) Enqueue(ctx context.Context, event domain.WebhookInboxEvent) error {
+	created, err := u.repo.Insert(ctx, event)
+	if err != nil {
+		return fmt.Errorf("error storing webhook event: %w", err)
+	}
+	if !created {
+		log.InfoContext(ctx, "duplicate webhook ignored",
+			log.String("provider", string(event.Provider)),
+			log.String("event_id", event.EventID),
+		)
+	}
+	return nil
+}
+
+// ProcessDue processes the events due at now. Each event is claimed first, so
+// overlapping runs do not process it twice.
+func (u *WebhookInbox) ProcessDue(ctx context.Context, now time.Time) (WebhookInboxJobResult, error) {
+	result := WebhookInboxJobResult{}
+
+	events, err := u.repo.FindDue(ctx, now, webhookBatchSize)
+	if err != nil {
+		return result, fmt.Errorf("error finding due webhook events: %w", err)
+	}
+	result.EventsDue = len(events)
+
+	for _, event := range events {
+		claimed, err := u.repo.Claim(ctx, event, now.Add(domain.WebhookProcessingLease))
+		if err != nil {
+			return result, err
+		}
+		if !claimed {
+			result.Skipped++
+			continue
+		}
+		event.Attempts++
+
+		status, err := u.process(ctx, event, now)
+		if err != nil {
+			return result, err
+		}
+		switch status {
+		case domain.WebhookEventStatusProcessed:
+			result.Processed++
+		case domain.WebhookEventStatusDead:
+			result.DeadLettered++
+		default:
+			result.Retried++
+		}
+	}
+
+	log.Info("webhook inbox job completed",
+		log.Int("events_due", result.EventsDue),
+		log.Int("processed", result.Processed),
+		log.Int("retried", result.Retried),
+		log.Int("dead_lettered", result.DeadLettered),
+		log.Int("skipped", result.Skipped),
+	)
+
+	return result, nil
+}
+
+// process runs one attempt of a claimed event and records its outcome. The
+// returned error is only about recording it.
+func (u *WebhookInbox) process(ctx context.Context, event domain.WebhookInboxEvent, now time.Time) (domain.WebhookEventStatus, error) {
+	procErr := u.processor.ProcessWebhookEvent(ctx, event)
+	if procErr == nil {
+		if err := u.repo.MarkProcessed(ctx, event.ID, now); err != nil {
+			return "", err
+		}
+		return domain.WebhookEventStatusProcessed, nil
+	}
+
+	status := domain.WebhookEventStatusPending
+	if event.Attempts >= domain.WebhookMaxAttempts {
+		status = domain.WebhookEventStatusDead
+	}
+	log.WarnContext(ctx, "webhook event failed",
+		log.String("provider", string(event.Provider)),
+		log.String("event_id", event.EventID),
+		log.Int("attempts", event.Attempts),
+		log.String("status", string(status)),
+		log.Err(procErr),
+	)
+
+	nextAttemptAt := now.Add(domain.WebhookRetryDelay(event.Attempts))
+	if err := u.repo.MarkFailed(ctx, event.ID, procErr.Error(), status, nextAttemptAt); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+func (u *WebhookInbox) ListDeadLetters(ctx context.Context) ([]domain.WebhookInboxEvent, error) {
+	return u.repo.ListByStatus(ctx, domain.WebhookEventStatusDead, webhookDeadLetterList)
+}
+
+// Replay processes the event again right away with a fresh attempt count. If it
+// fails, it goes back to the regular retries.
+func (u *WebhookInbox) Replay(ctx context.Context, id uuid.UUID) (domain.WebhookInboxEvent, error) {
+	now := time.Now()
+
+	event, err := u.repo.Requeue(ctx, id, now)
+	if err != nil {
+		return domain.WebhookInboxEvent{}, err
+	}
+
+	claimed, err := u.repo.Claim(ctx, event, now.Add(domain.WebhookProcessingLease))
+	if err != nil {
+		return domain.WebhookInboxEvent{}, err
+	}
+	if claimed {
+		event.Attempts++
+		if _, err := u.process(ctx, event, now); err != nil {
+			return domain.WebhookInboxEvent{}, err
+		}
+	}
+
+	return u.repo.FindByID(ctx, id)
+}
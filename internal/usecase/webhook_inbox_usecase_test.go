@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"personal-finance/internal/domain"
+	"personal-finance/pkg/log"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookInbox_ProcessDue(t *testing.T) {
+	log.Initialize()
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	lease := now.Add(domain.WebhookProcessingLease)
+	event := domain.NewWebhookInboxEvent(domain.WebhookProviderStripe, "evt_1", "customer.subscription.updated", []byte(`{}`), now)
+
+	tests := map[string]struct {
+		attempts       int
+		claimed        bool
+		processErr     error
+		mockSetup      func(repo *MockWebhookInboxRepository)
+		expectedResult WebhookInboxJobResult
+	}{
+		"should mark the event processed": {
+			claimed: true,
+			mockSetup: func(repo *MockWebhookInboxRepository) {
+				repo.On("MarkProcessed", event.ID, now).Return(nil)
+			},
+			expectedResult: WebhookInboxJobResult{EventsDue: 1, Processed: 1},
+		},
+		"should schedule a retry with backoff": {
+			attempts:   2,
+			claimed:    true,
+			processErr: errors.New("firebase unavailable"),
+			mockSetup: func(repo *MockWebhookInboxRepository) {
+				repo.On("MarkFailed", event.ID, "firebase unavailable", domain.WebhookEventStatusPending, now.Add(4*time.Minute)).Return(nil)
+			},
+			expectedResult: WebhookInboxJobResult{EventsDue: 1, Retried: 1},
+		},
+		"should dead-letter the event on the last attempt": {
+			attempts:   domain.WebhookMaxAttempts - 1,
+			claimed:    true,
+			processErr: errors.New("no app_user_id"),
+			mockSetup: func(repo *MockWebhookInboxRepository) {
+				repo.On("MarkFailed", event.ID, "no app_user_id", domain.WebhookEventStatusDead, mock.Anything).Return(nil)
+			},
+			expectedResult: WebhookInboxJobResult{EventsDue: 1, DeadLettered: 1},
+		},
+		"should skip an event claimed by another run": {
+			claimed:        false,
+			mockSetup:      func(repo *MockWebhookInboxRepository) {},
+			expectedResult: WebhookInboxJobResult{EventsDue: 1, Skipped: 1},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockWebhookInboxRepository)
+			processor := new(MockWebhookEventProcessor)
+			due := event
+			due.Attempts = tt.attempts
+			claimed := due
+			claimed.Attempts++
+
+			repo.On("FindDue", now, webhookBatchSize).Return([]domain.WebhookInboxEvent{due}, nil)
+			repo.On("Claim", due, lease).Return(tt.claimed, nil)
+			processor.On("ProcessWebhookEvent", claimed).Return(tt.processErr)
+			tt.mockSetup(repo)
+
+			result, err := NewWebhookInbox(repo, processor).ProcessDue(context.Background(), now)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+			repo.AssertExpectations(t)
+			if !tt.claimed {
+				processor.AssertNotCalled(t, "ProcessWebhookEvent", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestWebhookInbox_Enqueue(t *testing.T) {
+	log.Initialize()
+	event := domain.NewWebhookInboxEvent(domain.WebhookProviderRevenueCat, "rc-1", "RENEWAL", []byte(`{}`), time.Now())
+
+	t.Run("should accept a duplicate delivery without processing it again", func(t *testing.T) {
+		repo := new(MockWebhookInboxRepository)
+		repo.On("Insert", event).Return(false, nil)
+
+		err := NewWebhookInbox(repo, nil).Enqueue(context.Background(), event)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail when the event cannot be stored", func(t *testing.T) {
+		repo := new(MockWebhookInboxRepository)
+		repo.On("Insert", event).Return(false, errors.New("connection refused"))
+
+		err := NewWebhookInbox(repo, nil).Enqueue(context.Background(), event)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookInbox_Replay(t *testing.T) {
+	log.Initialize()
+	id := uuid.New()
+	dead := domain.WebhookInboxEvent{ID: id, Provider: domain.WebhookProviderStripe, Status: domain.WebhookEventStatusPending}
+
+	repo := new(MockWebhookInboxRepository)
+	processor := new(MockWebhookEventProcessor)
+	repo.On("Requeue", id, mock.Anything).Return(dead, nil)
+	repo.On("Claim", dead, mock.Anything).Return(true, nil)
+	processor.On("ProcessWebhookEvent", mock.MatchedBy(func(e domain.WebhookInboxEvent) bool { return e.ID == id && e.Attempts == 1 })).Return(nil)
+	repo.On("MarkProcessed", id, mock.Anything).Return(nil)
+	repo.On("FindByID", id).Return(domain.WebhookInboxEvent{ID: id, Status: domain.WebhookEventStatusProcessed, Attempts: 1}, nil)
+
+	event, err := NewWebhookInbox(repo, processor).Replay(context.Background(), id)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookEventStatusProcessed, event.Status)
+	repo.AssertExpectations(t)
+}